@@ -195,7 +195,8 @@ func (op *BaseNodeOperation) NodeSign(priv Privatekey, networkID NetworkID, node
 		}
 	}
 
-	ns, err := NewBaseNodeSignFromFact(node, priv, networkID, op.fact)
+	ns, err := NewBaseNodeSignFromFact(
+		node, PrivatekeyWithSignKind(priv, OperationSignKind, op.fact), networkID, op.fact)
 	if err != nil {
 		return err
 	}
@@ -2,12 +2,22 @@ package base
 
 import "github.com/ProtoconNet/mitum2/util"
 
-var objcache util.GCache[string, any]
+var (
+	objcache          util.GCache[string, any]
+	verifiedSignCache util.GCache[string, struct{}]
+)
 
 func init() {
-	objcache = util.NewLRUGCache[string, any](1 << 13) //nolint:gomnd //...
+	objcache = util.NewLRUGCache[string, any](1 << 13)               //nolint:gomnd //...
+	verifiedSignCache = util.NewLRUGCache[string, struct{}](1 << 14) //nolint:gomnd //...
 }
 
 func SetObjCache(c util.GCache[string, any]) {
 	objcache = c
 }
+
+// SetVerifiedSignCache sets the cache for the verified signatures; if nil,
+// the signatures are always verified.
+func SetVerifiedSignCache(c util.GCache[string, struct{}]) {
+	verifiedSignCache = c
+}
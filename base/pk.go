@@ -32,6 +32,39 @@ type Publickey interface {
 	Verify([]byte, Signature) error
 }
 
+// BallotSigner is the Privatekey, which can sign with the ballot fact; the
+// returned Privatekey signs the ballot fact with the point and stage, so the
+// signer can prevent the double sign of different facts for same point.
+type BallotSigner interface {
+	WithBallotFact(BallotFact) Privatekey
+}
+
+// SignKind is the kind of sign bytes except ballot.
+type SignKind string
+
+var (
+	BlockMapSignKind  SignKind = "blockmap"
+	OperationSignKind SignKind = "operation"
+	ChallengeSignKind SignKind = "challenge"
+)
+
+// KindSigner is the Privatekey, which can sign with the kind and the source of
+// sign bytes; the returned Privatekey sends the source with the sign bytes, so
+// the signer can rebuild the sign bytes from source by kind.
+type KindSigner interface {
+	WithSignKind(SignKind, interface{}) Privatekey
+}
+
+// PrivatekeyWithSignKind returns the KindSigner Privatekey for kind; if priv is
+// not KindSigner, priv is returned.
+func PrivatekeyWithSignKind(priv Privatekey, kind SignKind, source interface{}) Privatekey {
+	if i, ok := priv.(KindSigner); ok {
+		return i.WithSignKind(kind, source)
+	}
+
+	return priv
+}
+
 type Signature []byte
 
 func (sg Signature) Bytes() []byte {
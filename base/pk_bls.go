@@ -0,0 +1,479 @@
+package base
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/hint"
+	"github.com/btcsuite/btcutil/base58"
+	bls12381 "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/cloudflare/circl/sign/bls"
+	"github.com/pkg/errors"
+)
+
+var (
+	BLSPrivatekeyHint = hint.MustNewHint("bpr-v0.0.1")
+	BLSPublickeyHint  = hint.MustNewHint("bpu-v0.0.1")
+)
+
+const (
+	blsPrivatekeySize = bls12381.ScalarSize
+	blsPublickeySize  = bls12381.G2SizeCompressed
+	blsSignatureSize  = bls12381.G1SizeCompressed
+	blsPOPSize        = bls12381.G1SizeCompressed
+)
+
+var (
+	// NOTE ciphersuites of proof of possession scheme of
+	// draft-irtf-cfrg-bls-signature; the publickey is in G2 and the signature
+	// is in G1.
+	blsSignDST = []byte("BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_")
+	blsPOPDST  = []byte("BLS_POP_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_")
+)
+
+// BLSPrivatekey is the BLS privatekey over BLS12-381; the signature is in G1
+// and the publickey is in G2. The signatures from the different BLS keys can be
+// aggregated into one signature by AggregateBLSSignatures.
+type BLSPrivatekey struct {
+	k   *bls12381.Scalar
+	pub *BLSPublickey
+	hint.BaseHinter
+}
+
+func NewBLSPrivatekey() *BLSPrivatekey {
+	ikm := make([]byte, 32) //nolint:gomnd //...
+	if _, err := rand.Read(ikm); err != nil {
+		panic(errors.Wrap(err, "new bls privatekey"))
+	}
+
+	k, err := blsKeyGen(ikm)
+	if err != nil {
+		panic(errors.Wrap(err, "new bls privatekey"))
+	}
+
+	return k
+}
+
+func NewBLSPrivatekeyFromSeed(s string) (*BLSPrivatekey, error) {
+	if l := len([]byte(s)); l < PrivatekeyMinSeedSize {
+		return nil, util.ErrInvalid.Errorf(
+			"wrong seed for privatekey; too short, %d < %d", l, PrivatekeyMinSeedSize)
+	}
+
+	h := sha256.Sum256([]byte(s))
+
+	k, err := blsKeyGen(h[:])
+	if err != nil {
+		return nil, util.ErrInvalid.WithMessage(err, "wrong seed for privatekey")
+	}
+
+	return k, nil
+}
+
+func ParseBLSPrivatekey(s string) (*BLSPrivatekey, error) {
+	t := BLSPrivatekeyHint.Type().String()
+
+	switch {
+	case !strings.HasSuffix(s, t):
+		return nil, util.ErrInvalid.Errorf("unknown privatekey string")
+	case len(s) <= len(t):
+		return nil, util.ErrInvalid.Errorf("invalid privatekey string; too short")
+	}
+
+	return LoadBLSPrivatekey(s[:len(s)-len(t)])
+}
+
+func LoadBLSPrivatekey(s string) (*BLSPrivatekey, error) {
+	b := base58.Decode(s)
+
+	if len(b) != blsPrivatekeySize {
+		return nil, util.ErrInvalid.Errorf("malformed private key")
+	}
+
+	k := new(bls12381.Scalar)
+	if err := k.UnmarshalBinary(b); err != nil {
+		return nil, util.ErrInvalid.Errorf("malformed private key; out of range")
+	}
+
+	if k.IsZero() == 1 {
+		return nil, util.ErrInvalid.Errorf("malformed private key; zero key")
+	}
+
+	return newBLSPrivatekey(k), nil
+}
+
+func newBLSPrivatekey(k *bls12381.Scalar) *BLSPrivatekey {
+	pk := new(bls12381.G2)
+	pk.ScalarMult(k, bls12381.G2Generator())
+
+	// NOTE proof of possession; the publickey bytes signed by the privatekey.
+	pop := blsHashToG1(pk.BytesCompressed(), blsPOPDST)
+	pop.ScalarMult(k, pop)
+
+	return &BLSPrivatekey{
+		BaseHinter: hint.NewBaseHinter(BLSPrivatekeyHint),
+		k:          k,
+		pub:        newBLSPublickey(pk, pop.BytesCompressed()),
+	}
+}
+
+func (k *BLSPrivatekey) String() string {
+	b, _ := k.k.MarshalBinary()
+
+	return fmt.Sprintf("%s%s", base58.Encode(b), k.Hint().Type().String())
+}
+
+func (k *BLSPrivatekey) Bytes() []byte {
+	return []byte(k.String())
+}
+
+func (k *BLSPrivatekey) IsValid([]byte) error {
+	if err := k.BaseHinter.IsValid(BLSPrivatekeyHint.Type().Bytes()); err != nil {
+		return util.ErrInvalid.WithMessage(err, "wrong hint in privatekey")
+	}
+
+	if k.k == nil || k.k.IsZero() == 1 {
+		return util.ErrInvalid.Errorf("empty bls privatekey")
+	}
+
+	return nil
+}
+
+func (k *BLSPrivatekey) Publickey() Publickey {
+	return k.pub
+}
+
+func (k *BLSPrivatekey) Equal(b PKKey) bool {
+	return IsEqualPKKey(k, b)
+}
+
+func (k *BLSPrivatekey) Sign(b []byte) (Signature, error) {
+	h := blsHashToG1(b, blsSignDST)
+	h.ScalarMult(k.k, h)
+
+	return Signature(h.BytesCompressed()), nil
+}
+
+func (k *BLSPrivatekey) MarshalText() ([]byte, error) {
+	return k.Bytes(), nil
+}
+
+func (k *BLSPrivatekey) UnmarshalText(b []byte) error {
+	u, err := LoadBLSPrivatekey(string(b))
+	if err != nil {
+		return err
+	}
+
+	*k = *u
+
+	return nil
+}
+
+// BLSPublickey is the BLS publickey over BLS12-381. The publickey string has
+// the proof of possession, which is verified when the publickey is loaded; the
+// aggregated signature of the publickeys without the proof of possession can be
+// forged by the rogue key.
+type BLSPublickey struct {
+	k   *bls12381.G2
+	pop []byte
+	hint.BaseHinter
+}
+
+func newBLSPublickey(k *bls12381.G2, pop []byte) *BLSPublickey {
+	return &BLSPublickey{
+		BaseHinter: hint.NewBaseHinter(BLSPublickeyHint),
+		k:          k,
+		pop:        pop,
+	}
+}
+
+func ParseBLSPublickey(s string) (*BLSPublickey, error) {
+	t := BLSPublickeyHint.Type().String()
+
+	switch {
+	case !strings.HasSuffix(s, t):
+		return nil, util.ErrInvalid.Errorf("unknown publickey string")
+	case len(s) <= len(t):
+		return nil, util.ErrInvalid.Errorf("invalid publickey string; too short")
+	}
+
+	return LoadBLSPublickey(s[:len(s)-len(t)])
+}
+
+func LoadBLSPublickey(s string) (*BLSPublickey, error) {
+	b := base58.Decode(s)
+
+	if len(b) != blsPublickeySize+blsPOPSize {
+		return nil, util.ErrInvalid.Errorf("load publickey; malformed public key")
+	}
+
+	k := new(bls12381.G2)
+
+	switch err := k.SetBytes(b[:blsPublickeySize]); {
+	case err != nil:
+		return nil, util.ErrInvalid.Errorf("load publickey; not in G2")
+	case k.IsIdentity():
+		return nil, util.ErrInvalid.Errorf("load publickey; identity")
+	}
+
+	pub := newBLSPublickey(k, b[blsPublickeySize:])
+
+	if err := pub.verifyPOP(); err != nil {
+		return nil, util.ErrInvalid.WithMessage(err, "load publickey")
+	}
+
+	return pub, nil
+}
+
+func (k *BLSPublickey) String() string {
+	return fmt.Sprintf("%s%s",
+		base58.Encode(util.ConcatBytesSlice(k.k.BytesCompressed(), k.pop)),
+		k.Hint().Type().String(),
+	)
+}
+
+func (k *BLSPublickey) Bytes() []byte {
+	return []byte(k.String())
+}
+
+func (k *BLSPublickey) IsValid([]byte) error {
+	if err := k.BaseHinter.IsValid(BLSPublickeyHint.Type().Bytes()); err != nil {
+		return util.ErrInvalid.WithMessage(err, "wrong hint in publickey")
+	}
+
+	switch {
+	case k.k == nil:
+		return util.ErrInvalid.Errorf("empty bls publickey in publickey")
+	case len(k.pop) != blsPOPSize:
+		return util.ErrInvalid.Errorf("empty proof of possession in publickey")
+	}
+
+	return nil
+}
+
+func (k *BLSPublickey) Equal(b PKKey) bool {
+	return IsEqualPKKey(k, b)
+}
+
+func (k *BLSPublickey) Verify(input []byte, sig Signature) error {
+	return VerifyBLSAggregatedSignature([]Publickey{k}, [][]byte{input}, sig)
+}
+
+func (k *BLSPublickey) MarshalText() ([]byte, error) {
+	return k.Bytes(), nil
+}
+
+func (k *BLSPublickey) UnmarshalText(b []byte) error {
+	u, err := LoadBLSPublickey(string(b))
+	if err != nil {
+		return errors.Wrap(err, "unmarshal publickey")
+	}
+
+	*k = *u
+
+	return nil
+}
+
+func (k *BLSPublickey) verifyPOP() error {
+	p, err := loadBLSSignature(k.pop)
+	if err != nil {
+		return errors.WithMessage(err, "proof of possession")
+	}
+
+	if !blsPairingCheck(
+		[]*bls12381.G1{blsHashToG1(k.k.BytesCompressed(), blsPOPDST)},
+		[]*bls12381.G2{k.k},
+		p,
+	) {
+		return errors.Errorf("wrong proof of possession")
+	}
+
+	return nil
+}
+
+// AggregateBLSSignatures aggregates the BLS signatures into one signature.
+func AggregateBLSSignatures(sigs []Signature) (Signature, error) {
+	if len(sigs) < 1 {
+		return nil, util.ErrInvalid.Errorf("empty signatures to aggregate")
+	}
+
+	agg := new(bls12381.G1)
+	agg.SetIdentity()
+
+	for i := range sigs {
+		p, err := loadBLSSignature(sigs[i])
+		if err != nil {
+			return nil, err
+		}
+
+		agg.Add(agg, p)
+	}
+
+	return Signature(agg.BytesCompressed()), nil
+}
+
+// VerifyBLSAggregatedSignature verifies the aggregated signature of the
+// messages; each message is signed by the publickey of same index. The
+// publickeys have the proof of possession, so the messages can be same.
+func VerifyBLSAggregatedSignature(pubs []Publickey, msgs [][]byte, sig Signature) error {
+	switch {
+	case len(pubs) < 1:
+		return ErrSignatureVerification.Errorf("empty publickeys")
+	case len(pubs) != len(msgs):
+		return ErrSignatureVerification.Errorf("publickeys and messages not matched, %d != %d", len(pubs), len(msgs))
+	}
+
+	p, err := loadBLSSignature(sig)
+	if err != nil {
+		return err
+	}
+
+	hs := make([]*bls12381.G1, len(pubs))
+	ks := make([]*bls12381.G2, len(pubs))
+
+	for i := range pubs {
+		pub, ok := pubs[i].(*BLSPublickey)
+		if !ok {
+			return ErrSignatureVerification.Errorf("not bls publickey, %T", pubs[i])
+		}
+
+		hs[i] = blsHashToG1(msgs[i], blsSignDST)
+		ks[i] = pub.k
+	}
+
+	if !blsPairingCheck(hs, ks, p) {
+		return ErrSignatureVerification.WithStack()
+	}
+
+	return nil
+}
+
+// VerifyBLSBatch verifies the multiple signatures of the different messages at
+// once; each signature is weighted by random scalar, so the invalid signature
+// can not be compensated by the other signatures. The pairings share one final
+// exponentiation.
+func VerifyBLSBatch(pubs []Publickey, msgs [][]byte, sigs []Signature) error {
+	switch {
+	case len(pubs) < 1:
+		return ErrSignatureVerification.Errorf("empty publickeys")
+	case len(pubs) != len(msgs), len(pubs) != len(sigs):
+		return ErrSignatureVerification.Errorf(
+			"publickeys, messages and signatures not matched, %d, %d, %d", len(pubs), len(msgs), len(sigs))
+	}
+
+	lhs := new(bls12381.G1)
+	lhs.SetIdentity()
+
+	hs := make([]*bls12381.G1, len(pubs))
+	ks := make([]*bls12381.G2, len(pubs))
+
+	for i := range pubs {
+		pub, ok := pubs[i].(*BLSPublickey)
+		if !ok {
+			return ErrSignatureVerification.Errorf("not bls publickey, %T", pubs[i])
+		}
+
+		p, err := loadBLSSignature(sigs[i])
+		if err != nil {
+			return err
+		}
+
+		r, err := blsBatchScalar()
+		if err != nil {
+			return ErrSignatureVerification.Wrap(err)
+		}
+
+		p.ScalarMult(r, p)
+		lhs.Add(lhs, p)
+
+		h := blsHashToG1(msgs[i], blsSignDST)
+		h.ScalarMult(r, h)
+
+		hs[i] = h
+		ks[i] = pub.k
+	}
+
+	if !blsPairingCheck(hs, ks, lhs) {
+		return ErrSignatureVerification.WithStack()
+	}
+
+	return nil
+}
+
+// blsPairingCheck checks e(sig, g2) == e(hs[0], ks[0]) * ... * e(hs[n], ks[n]).
+func blsPairingCheck(hs []*bls12381.G1, ks []*bls12381.G2, sig *bls12381.G1) bool {
+	g1s := make([]*bls12381.G1, len(hs)+1)
+	g2s := make([]*bls12381.G2, len(hs)+1)
+	signs := make([]int, len(hs)+1)
+
+	for i := range hs {
+		g1s[i], g2s[i], signs[i] = hs[i], ks[i], 1
+	}
+
+	g1s[len(hs)], g2s[len(hs)], signs[len(hs)] = sig, bls12381.G2Generator(), -1
+
+	return bls12381.ProdPairFrac(g1s, g2s, signs).IsIdentity()
+}
+
+func blsKeyGen(ikm []byte) (*BLSPrivatekey, error) {
+	i, err := bls.KeyGen[bls.KeyG2SigG1](ikm, nil, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	b, err := i.MarshalBinary()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	k := new(bls12381.Scalar)
+	if err := k.UnmarshalBinary(b); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return newBLSPrivatekey(k), nil
+}
+
+func blsBatchScalar() (*bls12381.Scalar, error) {
+	b := make([]byte, 8) //nolint:gomnd //...
+
+	for {
+		if _, err := rand.Read(b); err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		r := new(bls12381.Scalar)
+		r.SetBytes(b)
+
+		if r.IsZero() != 1 {
+			return r, nil
+		}
+	}
+}
+
+func loadBLSSignature(sig Signature) (*bls12381.G1, error) {
+	if len(sig) != blsSignatureSize {
+		return nil, ErrSignatureVerification.Errorf("wrong bls signature size, %d", len(sig))
+	}
+
+	p := new(bls12381.G1)
+
+	switch err := p.SetBytes(sig); {
+	case err != nil:
+		return nil, ErrSignatureVerification.Errorf("malformed bls signature")
+	case p.IsIdentity():
+		return nil, ErrSignatureVerification.Errorf("identity bls signature")
+	}
+
+	return p, nil
+}
+
+func blsHashToG1(b, dst []byte) *bls12381.G1 {
+	h := new(bls12381.G1)
+	h.Hash(b, dst)
+
+	return h
+}
@@ -0,0 +1,275 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	jsonenc "github.com/ProtoconNet/mitum2/util/encoder/json"
+	"github.com/btcsuite/btcutil/base58"
+	bls12381 "github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/stretchr/testify/suite"
+)
+
+type testBLSPrivatekey struct {
+	suite.Suite
+}
+
+func (t *testBLSPrivatekey) TestNew() {
+	priv := NewBLSPrivatekey()
+
+	t.NoError(priv.IsValid(nil))
+	t.NoError(priv.Publickey().IsValid(nil))
+
+	t.Implements((*Privatekey)(nil), priv)
+	t.Implements((*Publickey)(nil), priv.Publickey())
+}
+
+func (t *testBLSPrivatekey) TestFromSeed() {
+	seed := util.UUID().String() + util.UUID().String()
+
+	priva, err := NewBLSPrivatekeyFromSeed(seed)
+	t.NoError(err)
+
+	b, err := NewBLSPrivatekeyFromSeed(seed)
+	t.NoError(err)
+	t.True(priva.Equal(b))
+	t.True(priva.Publickey().Equal(b.Publickey()))
+
+	t.Run("too short", func() {
+		_, err := NewBLSPrivatekeyFromSeed(util.UUID().String()[:PrivatekeyMinSeedSize-1])
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "too short")
+	})
+}
+
+func (t *testBLSPrivatekey) TestParse() {
+	priv := NewBLSPrivatekey()
+
+	upriv, err := ParseBLSPrivatekey(priv.String())
+	t.NoError(err)
+	t.True(priv.Equal(upriv))
+
+	upub, err := ParseBLSPublickey(priv.Publickey().String())
+	t.NoError(err)
+	t.True(priv.Publickey().Equal(upub))
+
+	t.Run("mpr", func() {
+		_, err := ParseBLSPrivatekey(NewMPrivatekey().String())
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "unknown privatekey string")
+	})
+
+	t.Run("malformed", func() {
+		_, err := ParseBLSPublickey("showme" + BLSPublickeyHint.Type().String())
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "malformed")
+	})
+
+	pub := priv.Publickey().(*BLSPublickey)
+
+	t.Run("identity", func() {
+		k := new(bls12381.G2)
+		k.SetIdentity()
+
+		_, err := LoadBLSPublickey(base58.Encode(util.ConcatBytesSlice(k.BytesCompressed(), pub.pop)))
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "identity")
+	})
+
+	t.Run("wrong proof of possession", func() {
+		other := NewBLSPrivatekey().Publickey().(*BLSPublickey)
+
+		_, err := LoadBLSPublickey(base58.Encode(util.ConcatBytesSlice(pub.k.BytesCompressed(), other.pop)))
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "wrong proof of possession")
+	})
+
+	t.Run("rogue key", func() {
+		// NOTE rogue key, x*g2 - pub; the proof of possession can not be made
+		// without the privatekey of rogue key.
+		x := NewBLSPrivatekey()
+
+		neg := new(bls12381.G2)
+		*neg = *pub.k
+		neg.Neg()
+
+		rogue := new(bls12381.G2)
+		rogue.Add(x.Publickey().(*BLSPublickey).k, neg)
+
+		_, err := LoadBLSPublickey(base58.Encode(util.ConcatBytesSlice(
+			rogue.BytesCompressed(), x.Publickey().(*BLSPublickey).pop)))
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "wrong proof of possession")
+	})
+}
+
+func (t *testBLSPrivatekey) TestSign() {
+	priv := NewBLSPrivatekey()
+
+	input := []byte("makeme")
+
+	sig, err := priv.Sign(input)
+	t.NoError(err)
+
+	t.NoError(priv.Publickey().Verify(input, sig))
+
+	t.Run("different input", func() {
+		err := priv.Publickey().Verify([]byte("findme"), sig)
+		t.ErrorIs(err, ErrSignatureVerification)
+	})
+
+	t.Run("different publickey", func() {
+		err := NewBLSPrivatekey().Publickey().Verify(input, sig)
+		t.ErrorIs(err, ErrSignatureVerification)
+	})
+
+	t.Run("secp256k1 signature", func() {
+		msig, err := NewMPrivatekey().Sign(input)
+		t.NoError(err)
+
+		err = priv.Publickey().Verify(input, msig)
+		t.ErrorIs(err, ErrSignatureVerification)
+	})
+}
+
+func (t *testBLSPrivatekey) TestAggregate() {
+	privs := make([]*BLSPrivatekey, 4)
+	pubs := make([]Publickey, len(privs))
+	msgs := make([][]byte, len(privs))
+	sigs := make([]Signature, len(privs))
+
+	for i := range privs {
+		privs[i] = NewBLSPrivatekey()
+		pubs[i] = privs[i].Publickey()
+		msgs[i] = util.UUID().Bytes()
+
+		sig, err := privs[i].Sign(msgs[i])
+		t.NoError(err)
+
+		sigs[i] = sig
+	}
+
+	agg, err := AggregateBLSSignatures(sigs)
+	t.NoError(err)
+	t.Equal(len(sigs[0]), len(agg))
+
+	t.NoError(VerifyBLSAggregatedSignature(pubs, msgs, agg))
+
+	t.Run("missing signer", func() {
+		err := VerifyBLSAggregatedSignature(pubs[:3], msgs[:3], agg)
+		t.ErrorIs(err, ErrSignatureVerification)
+	})
+
+	t.Run("swapped messages", func() {
+		err := VerifyBLSAggregatedSignature(pubs, [][]byte{msgs[1], msgs[0], msgs[2], msgs[3]}, agg)
+		t.ErrorIs(err, ErrSignatureVerification)
+	})
+
+	t.Run("not bls publickey", func() {
+		err := VerifyBLSAggregatedSignature(
+			[]Publickey{pubs[0], pubs[1], pubs[2], NewMPrivatekey().Publickey()}, msgs, agg)
+		t.ErrorIs(err, ErrSignatureVerification)
+		t.ErrorContains(err, "not bls publickey")
+	})
+
+	t.Run("empty", func() {
+		_, err := AggregateBLSSignatures(nil)
+		t.ErrorIs(err, util.ErrInvalid)
+	})
+}
+
+func (t *testBLSPrivatekey) TestBatch() {
+	privs := make([]*BLSPrivatekey, 4)
+	pubs := make([]Publickey, len(privs))
+	msgs := make([][]byte, len(privs))
+	sigs := make([]Signature, len(privs))
+
+	for i := range privs {
+		privs[i] = NewBLSPrivatekey()
+		pubs[i] = privs[i].Publickey()
+		msgs[i] = util.UUID().Bytes()
+
+		sig, err := privs[i].Sign(msgs[i])
+		t.NoError(err)
+
+		sigs[i] = sig
+	}
+
+	t.NoError(VerifyBLSBatch(pubs, msgs, sigs))
+
+	t.Run("same message", func() {
+		sig, err := privs[1].Sign(msgs[0])
+		t.NoError(err)
+
+		t.NoError(VerifyBLSBatch(pubs[:2], [][]byte{msgs[0], msgs[0]}, []Signature{sigs[0], sig}))
+	})
+
+	t.Run("swapped signatures", func() {
+		err := VerifyBLSBatch(pubs, msgs, []Signature{sigs[1], sigs[0], sigs[2], sigs[3]})
+		t.ErrorIs(err, ErrSignatureVerification)
+	})
+
+	t.Run("compensated signatures", func() {
+		a, err := loadBLSSignature(sigs[0])
+		t.NoError(err)
+		b, err := loadBLSSignature(sigs[1])
+		t.NoError(err)
+
+		delta := blsHashToG1(util.UUID().Bytes(), blsSignDST)
+
+		a.Add(a, delta)
+		delta.Neg()
+		b.Add(b, delta)
+
+		csigs := []Signature{Signature(a.BytesCompressed()), Signature(b.BytesCompressed()), sigs[2], sigs[3]}
+
+		agg, err := AggregateBLSSignatures(csigs)
+		t.NoError(err)
+		t.NoError(VerifyBLSAggregatedSignature(pubs, msgs, agg))
+
+		err = VerifyBLSBatch(pubs, msgs, csigs)
+		t.ErrorIs(err, ErrSignatureVerification)
+	})
+
+	t.Run("not matched", func() {
+		err := VerifyBLSBatch(pubs, msgs, sigs[:3])
+		t.ErrorIs(err, ErrSignatureVerification)
+		t.ErrorContains(err, "not matched")
+	})
+
+	t.Run("not bls publickey", func() {
+		err := VerifyBLSBatch(
+			[]Publickey{pubs[0], pubs[1], pubs[2], NewMPrivatekey().Publickey()}, msgs, sigs)
+		t.ErrorIs(err, ErrSignatureVerification)
+		t.ErrorContains(err, "not bls publickey")
+	})
+}
+
+func TestBLSPrivatekey(t *testing.T) {
+	suite.Run(t, new(testBLSPrivatekey))
+}
+
+func TestBLSDecodeFromString(tt *testing.T) {
+	t := new(suite.Suite)
+	t.SetT(tt)
+
+	enc := jsonenc.NewEncoder()
+
+	t.NoError(enc.Add(encoder.DecodeDetail{Hint: BLSPublickeyHint, Instance: &BLSPublickey{}}))
+	t.NoError(enc.Add(encoder.DecodeDetail{Hint: BLSPrivatekeyHint, Instance: &BLSPrivatekey{}}))
+
+	priv := NewBLSPrivatekey()
+
+	upriv, err := DecodePrivatekeyFromString(priv.String(), enc)
+	t.NoError(err)
+	t.True(priv.Equal(upriv))
+
+	upub, err := DecodePublickeyFromString(priv.Publickey().String(), enc)
+	t.NoError(err)
+	t.True(priv.Publickey().Equal(upub))
+
+	sig, err := upriv.Sign([]byte("showme"))
+	t.NoError(err)
+	t.NoError(upub.Verify([]byte("showme"), sig))
+}
@@ -0,0 +1,204 @@
+package base
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"strings"
+
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/hint"
+	"github.com/ProtoconNet/mitum2/util/valuehash"
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/pkg/errors"
+)
+
+var (
+	Ed25519PrivatekeyHint = hint.MustNewHint("epr-v0.0.1")
+	Ed25519PublickeyHint  = hint.MustNewHint("epu-v0.0.1")
+)
+
+// Ed25519Privatekey is the ed25519 privatekey; the string is the seed of
+// privatekey.
+type Ed25519Privatekey struct {
+	priv ed25519.PrivateKey
+	hint.BaseHinter
+}
+
+func NewEd25519Privatekey() *Ed25519Privatekey {
+	_, priv, _ := ed25519.GenerateKey(nil)
+
+	return newEd25519Privatekey(priv)
+}
+
+func NewEd25519PrivatekeyFromSeed(s string) (*Ed25519Privatekey, error) {
+	if l := len([]byte(s)); l < PrivatekeyMinSeedSize {
+		return nil, util.ErrInvalid.Errorf(
+			"wrong seed for privatekey; too short, %d < %d", l, PrivatekeyMinSeedSize)
+	}
+
+	return newEd25519Privatekey(ed25519.NewKeyFromSeed(valuehash.NewSHA256([]byte(s)).Bytes())), nil
+}
+
+func ParseEd25519Privatekey(s string) (*Ed25519Privatekey, error) {
+	t := Ed25519PrivatekeyHint.Type().String()
+
+	switch {
+	case !strings.HasSuffix(s, t):
+		return nil, util.ErrInvalid.Errorf("unknown privatekey string")
+	case len(s) <= len(t):
+		return nil, util.ErrInvalid.Errorf("invalid privatekey string; too short")
+	}
+
+	return LoadEd25519Privatekey(s[:len(s)-len(t)])
+}
+
+func LoadEd25519Privatekey(s string) (*Ed25519Privatekey, error) {
+	b := base58.Decode(s)
+
+	if len(b) != ed25519.SeedSize {
+		return nil, util.ErrInvalid.Errorf("malformed private key")
+	}
+
+	return newEd25519Privatekey(ed25519.NewKeyFromSeed(b)), nil
+}
+
+func newEd25519Privatekey(priv ed25519.PrivateKey) *Ed25519Privatekey {
+	return &Ed25519Privatekey{
+		BaseHinter: hint.NewBaseHinter(Ed25519PrivatekeyHint),
+		priv:       priv,
+	}
+}
+
+func (k *Ed25519Privatekey) String() string {
+	return fmt.Sprintf("%s%s", base58.Encode(k.priv.Seed()), k.Hint().Type().String())
+}
+
+func (k *Ed25519Privatekey) Bytes() []byte {
+	return []byte(k.String())
+}
+
+func (k *Ed25519Privatekey) IsValid([]byte) error {
+	if err := k.BaseHinter.IsValid(Ed25519PrivatekeyHint.Type().Bytes()); err != nil {
+		return util.ErrInvalid.WithMessage(err, "wrong hint in privatekey")
+	}
+
+	if len(k.priv) != ed25519.PrivateKeySize {
+		return util.ErrInvalid.Errorf("empty ed25519 privatekey")
+	}
+
+	return nil
+}
+
+func (k *Ed25519Privatekey) Publickey() Publickey {
+	return NewEd25519Publickey(k.priv.Public().(ed25519.PublicKey)) //nolint:forcetypeassert //...
+}
+
+func (k *Ed25519Privatekey) Equal(b PKKey) bool {
+	return IsEqualPKKey(k, b)
+}
+
+func (k *Ed25519Privatekey) Sign(b []byte) (Signature, error) {
+	return Signature(ed25519.Sign(k.priv, b)), nil
+}
+
+func (k *Ed25519Privatekey) MarshalText() ([]byte, error) {
+	return k.Bytes(), nil
+}
+
+func (k *Ed25519Privatekey) UnmarshalText(b []byte) error {
+	u, err := LoadEd25519Privatekey(string(b))
+	if err != nil {
+		return err
+	}
+
+	*k = *u
+
+	return nil
+}
+
+// Ed25519Publickey is the ed25519 publickey.
+type Ed25519Publickey struct {
+	k ed25519.PublicKey
+	hint.BaseHinter
+}
+
+func NewEd25519Publickey(k ed25519.PublicKey) *Ed25519Publickey {
+	return &Ed25519Publickey{
+		BaseHinter: hint.NewBaseHinter(Ed25519PublickeyHint),
+		k:          k,
+	}
+}
+
+func ParseEd25519Publickey(s string) (*Ed25519Publickey, error) {
+	t := Ed25519PublickeyHint.Type().String()
+
+	switch {
+	case !strings.HasSuffix(s, t):
+		return nil, util.ErrInvalid.Errorf("unknown publickey string")
+	case len(s) <= len(t):
+		return nil, util.ErrInvalid.Errorf("invalid publickey string; too short")
+	}
+
+	return LoadEd25519Publickey(s[:len(s)-len(t)])
+}
+
+func LoadEd25519Publickey(s string) (*Ed25519Publickey, error) {
+	b := base58.Decode(s)
+
+	if len(b) != ed25519.PublicKeySize {
+		return nil, util.ErrInvalid.Errorf("load publickey; malformed public key")
+	}
+
+	return NewEd25519Publickey(ed25519.PublicKey(b)), nil
+}
+
+func (k *Ed25519Publickey) String() string {
+	return fmt.Sprintf("%s%s", base58.Encode(k.k), k.Hint().Type().String())
+}
+
+func (k *Ed25519Publickey) Bytes() []byte {
+	return []byte(k.String())
+}
+
+func (k *Ed25519Publickey) IsValid([]byte) error {
+	if err := k.BaseHinter.IsValid(Ed25519PublickeyHint.Type().Bytes()); err != nil {
+		return util.ErrInvalid.WithMessage(err, "wrong hint in publickey")
+	}
+
+	if len(k.k) != ed25519.PublicKeySize {
+		return util.ErrInvalid.Errorf("empty ed25519 publickey in publickey")
+	}
+
+	return nil
+}
+
+func (k *Ed25519Publickey) Equal(b PKKey) bool {
+	return IsEqualPKKey(k, b)
+}
+
+func (k *Ed25519Publickey) Verify(input []byte, sig Signature) error {
+	if len(sig) != ed25519.SignatureSize {
+		return ErrSignatureVerification.Errorf("wrong signature size, %d", len(sig))
+	}
+
+	if !ed25519.Verify(k.k, input, sig) {
+		return ErrSignatureVerification.WithStack()
+	}
+
+	return nil
+}
+
+func (k *Ed25519Publickey) MarshalText() ([]byte, error) {
+	return k.Bytes(), nil
+}
+
+func (k *Ed25519Publickey) UnmarshalText(b []byte) error {
+	u, err := LoadEd25519Publickey(string(b))
+	if err != nil {
+		return errors.Wrap(err, "unmarshal publickey")
+	}
+
+	*k = *u
+
+	return nil
+}
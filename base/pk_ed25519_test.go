@@ -0,0 +1,156 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	jsonenc "github.com/ProtoconNet/mitum2/util/encoder/json"
+	"github.com/stretchr/testify/suite"
+)
+
+type testEd25519Privatekey struct {
+	suite.Suite
+}
+
+func (t *testEd25519Privatekey) TestNew() {
+	priv := NewEd25519Privatekey()
+
+	t.NoError(priv.IsValid(nil))
+	t.NoError(priv.Publickey().IsValid(nil))
+
+	t.Implements((*Privatekey)(nil), priv)
+	t.Implements((*Publickey)(nil), priv.Publickey())
+}
+
+func (t *testEd25519Privatekey) TestFromSeed() {
+	seed := util.UUID().String() + util.UUID().String()
+
+	priva, err := NewEd25519PrivatekeyFromSeed(seed)
+	t.NoError(err)
+
+	b, err := NewEd25519PrivatekeyFromSeed(seed)
+	t.NoError(err)
+	t.True(priva.Equal(b))
+	t.True(priva.Publickey().Equal(b.Publickey()))
+
+	t.Run("too short", func() {
+		_, err := NewEd25519PrivatekeyFromSeed(util.UUID().String()[:PrivatekeyMinSeedSize-1])
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "too short")
+	})
+
+	t.Run("different from secp256k1", func() {
+		mpriv, err := NewMPrivatekeyFromSeed(seed)
+		t.NoError(err)
+
+		t.False(priva.Equal(mpriv))
+		t.False(priva.Publickey().Equal(mpriv.Publickey()))
+	})
+}
+
+func (t *testEd25519Privatekey) TestParse() {
+	priv := NewEd25519Privatekey()
+
+	upriv, err := ParseEd25519Privatekey(priv.String())
+	t.NoError(err)
+	t.True(priv.Equal(upriv))
+
+	upub, err := ParseEd25519Publickey(priv.Publickey().String())
+	t.NoError(err)
+	t.True(priv.Publickey().Equal(upub))
+
+	t.Run("mpr", func() {
+		_, err := ParseEd25519Privatekey(NewMPrivatekey().String())
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "unknown privatekey string")
+	})
+
+	t.Run("empty body", func() {
+		_, err := ParseEd25519Publickey(Ed25519PublickeyHint.Type().String())
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "invalid publickey string")
+	})
+
+	t.Run("malformed", func() {
+		_, err := ParseEd25519Publickey("showme" + Ed25519PublickeyHint.Type().String())
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "malformed")
+	})
+}
+
+func (t *testEd25519Privatekey) TestSign() {
+	priv := NewEd25519Privatekey()
+
+	input := []byte("makeme")
+
+	sig, err := priv.Sign(input)
+	t.NoError(err)
+
+	t.NoError(priv.Publickey().Verify(input, sig))
+
+	t.Run("different input", func() {
+		err := priv.Publickey().Verify([]byte("findme"), sig)
+		t.ErrorIs(err, ErrSignatureVerification)
+	})
+
+	t.Run("different publickey", func() {
+		err := NewEd25519Privatekey().Publickey().Verify(input, sig)
+		t.ErrorIs(err, ErrSignatureVerification)
+	})
+
+	t.Run("secp256k1 signature", func() {
+		msig, err := NewMPrivatekey().Sign(input)
+		t.NoError(err)
+
+		err = priv.Publickey().Verify(input, msig)
+		t.ErrorIs(err, ErrSignatureVerification)
+	})
+
+	t.Run("by secp256k1 publickey", func() {
+		err := NewMPrivatekey().Publickey().Verify(input, sig)
+		t.Error(err)
+	})
+}
+
+func TestEd25519Privatekey(t *testing.T) {
+	suite.Run(t, new(testEd25519Privatekey))
+}
+
+func TestEd25519DecodeFromString(tt *testing.T) {
+	t := new(suite.Suite)
+	t.SetT(tt)
+
+	enc := jsonenc.NewEncoder()
+
+	t.NoError(enc.Add(encoder.DecodeDetail{Hint: MPublickeyHint, Instance: &MPublickey{}}))
+	t.NoError(enc.Add(encoder.DecodeDetail{Hint: MPrivatekeyHint, Instance: &MPrivatekey{}}))
+	t.NoError(enc.Add(encoder.DecodeDetail{Hint: Ed25519PublickeyHint, Instance: &Ed25519Publickey{}}))
+	t.NoError(enc.Add(encoder.DecodeDetail{Hint: Ed25519PrivatekeyHint, Instance: &Ed25519Privatekey{}}))
+
+	for _, priv := range []Privatekey{NewMPrivatekey(), NewEd25519Privatekey()} {
+		upriv, err := DecodePrivatekeyFromString(priv.String(), enc)
+		t.NoError(err)
+		t.True(priv.Equal(upriv))
+
+		upub, err := DecodePublickeyFromString(priv.Publickey().String(), enc)
+		t.NoError(err)
+		t.True(priv.Publickey().Equal(upub))
+
+		_, err = DecodePublickeyFromString(priv.String(), enc)
+		t.ErrorContains(err, "expected base.Publickey")
+
+		b, err := enc.Marshal(priv.Publickey())
+		t.NoError(err)
+
+		var s string
+		t.NoError(enc.Unmarshal(b, &s))
+
+		upub, err = DecodePublickeyFromString(s, enc)
+		t.NoError(err)
+
+		sig, err := priv.Sign([]byte("showme"))
+		t.NoError(err)
+		t.NoError(upub.Verify([]byte("showme"), sig))
+	}
+}
@@ -100,17 +100,21 @@ func (si BaseSign) IsValid([]byte) error {
 }
 
 func (si BaseSign) Verify(networkID NetworkID, b []byte) error {
-	if err := si.signer.Verify(util.ConcatBytesSlice(
-		networkID,
-		b,
-		localtime.New(si.signedAt).Bytes(),
-	), si.signature); err != nil {
+	if err := verifySignature(si.signer, si.signedBytes(networkID, b), si.signature); err != nil {
 		return errors.Wrap(err, "verfiy sign")
 	}
 
 	return nil
 }
 
+func (si BaseSign) signedBytes(networkID NetworkID, b []byte) []byte {
+	return util.ConcatBytesSlice(
+		networkID,
+		b,
+		localtime.New(si.signedAt).Bytes(),
+	)
+}
+
 type BaseNodeSign struct {
 	node Address
 	BaseSign
@@ -169,6 +173,10 @@ func (si BaseNodeSign) Verify(networkID NetworkID, b []byte) error {
 	return si.BaseSign.Verify(networkID, util.ConcatByters(si.node, util.BytesToByter(b)))
 }
 
+func (si BaseNodeSign) signedBytes(networkID NetworkID, b []byte) []byte {
+	return si.BaseSign.signedBytes(networkID, util.ConcatByters(si.node, util.BytesToByter(b)))
+}
+
 func CheckFactSignsBySuffrage(suf Suffrage, threshold Threshold, signs []NodeSign) error {
 	var sign float64
 
@@ -0,0 +1,147 @@
+package base
+
+import (
+	"crypto/sha256"
+
+	"github.com/ProtoconNet/mitum2/util"
+)
+
+type signedByteser interface {
+	signedBytes(NetworkID, []byte) []byte
+}
+
+type signVerifyItem struct {
+	pub Publickey
+	sig Signature
+	key string
+	msg []byte
+}
+
+// verifySignature verifies the signature; the verified signature is kept in
+// the verified sign cache, so the same signature is not verified again.
+func verifySignature(pub Publickey, msg []byte, sig Signature) error {
+	c := verifiedSignCache
+	if c == nil {
+		return pub.Verify(msg, sig)
+	}
+
+	k := verifiedSignCacheKey(pub, msg, sig)
+
+	if c.Exists(k) {
+		return nil
+	}
+
+	if err := pub.Verify(msg, sig); err != nil {
+		return err
+	}
+
+	c.Set(k, struct{}{}, 0)
+
+	return nil
+}
+
+// BatchVerifySignFacts verifies the BLS signs of sign facts at once by
+// VerifyBLSBatch and keeps the verified signs in the verified sign cache; the
+// following IsValid of sign facts does not need to verify the BLS signs again.
+// The other signs are not verified here, they are verified by IsValid of sign
+// facts. If the verified sign cache is not set, it does nothing.
+func BatchVerifySignFacts(networkID NetworkID, sfs []SignFact) error {
+	c := verifiedSignCache
+	if c == nil {
+		return nil
+	}
+
+	var blsitems []signVerifyItem
+
+	for i := range sfs {
+		if sfs[i] == nil || sfs[i].Fact() == nil || sfs[i].Fact().Hash() == nil {
+			continue
+		}
+
+		signs := sfs[i].Signs()
+
+		for j := range signs {
+			item, ok := newSignVerifyItem(networkID, sfs[i].Fact().Hash().Bytes(), signs[j])
+
+			if !ok {
+				continue
+			}
+
+			if _, isbls := item.pub.(*BLSPublickey); !isbls || c.Exists(item.key) {
+				continue
+			}
+
+			blsitems = append(blsitems, item)
+		}
+	}
+
+	return batchVerifyBLSItems(blsitems)
+}
+
+func batchVerifyBLSItems(items []signVerifyItem) error {
+	c := verifiedSignCache
+
+	switch {
+	case c == nil, len(items) < 1:
+		return nil
+	case len(items) > 1:
+		pubs := make([]Publickey, len(items))
+		msgs := make([][]byte, len(items))
+		sigs := make([]Signature, len(items))
+
+		for i := range items {
+			pubs[i] = items[i].pub
+			msgs[i] = items[i].msg
+			sigs[i] = items[i].sig
+		}
+
+		if err := VerifyBLSBatch(pubs, msgs, sigs); err == nil {
+			for i := range items {
+				c.Set(items[i].key, struct{}{}, 0)
+			}
+
+			return nil
+		}
+	}
+
+	// NOTE if batch verification failed, find the wrong one.
+	for i := range items {
+		if err := items[i].pub.Verify(items[i].msg, items[i].sig); err != nil {
+			return ErrSignatureVerification.WithMessage(err, "batch verify sign facts")
+		}
+
+		c.Set(items[i].key, struct{}{}, 0)
+	}
+
+	return nil
+}
+
+func newSignVerifyItem(networkID NetworkID, b []byte, sign Sign) (item signVerifyItem, _ bool) {
+	if sign == nil || sign.Signer() == nil || len(sign.Signature()) < 1 {
+		return item, false
+	}
+
+	i, ok := sign.(signedByteser)
+	if !ok {
+		return item, false
+	}
+
+	msg := i.signedBytes(networkID, b)
+
+	return signVerifyItem{
+		pub: sign.Signer(),
+		msg: msg,
+		sig: sign.Signature(),
+		key: verifiedSignCacheKey(sign.Signer(), msg, sign.Signature()),
+	}, true
+}
+
+func verifiedSignCacheKey(pub Publickey, msg []byte, sig Signature) string {
+	h := sha256.Sum256(msg)
+
+	// NOTE fields are length prefixed, so the different fields can not make
+	// the same key.
+	b, _ := util.NewLengthedBytesSlice([][]byte{[]byte(pub.String()), h[:], sig})
+
+	return string(b)
+}
@@ -0,0 +1,155 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/hint"
+	"github.com/stretchr/testify/suite"
+)
+
+var dummySignVerifyOperationHint = hint.MustNewHint("dummy-sign-verify-operation-v0.0.1")
+
+type testSignVerify struct {
+	suite.Suite
+	networkID NetworkID
+	cache     util.GCache[string, struct{}]
+	previous  util.GCache[string, struct{}]
+}
+
+func (t *testSignVerify) SetupTest() {
+	t.networkID = util.UUID().Bytes()
+	t.previous = verifiedSignCache
+	t.cache = util.NewLRUGCache[string, struct{}](1 << 9)
+
+	SetVerifiedSignCache(t.cache)
+}
+
+func (t *testSignVerify) TearDownTest() {
+	SetVerifiedSignCache(t.previous)
+}
+
+func (t *testSignVerify) newSignFact(privs ...Privatekey) BaseOperation {
+	op := NewBaseOperation(dummySignVerifyOperationHint, NewDummyFact(util.UUID().Bytes(), util.UUID().String()))
+
+	for i := range privs {
+		t.NoError(op.Sign(privs[i], t.networkID))
+	}
+
+	return op
+}
+
+func (t *testSignVerify) cacheKey(sf SignFact, i int) string {
+	item, ok := newSignVerifyItem(t.networkID, sf.Fact().Hash().Bytes(), sf.Signs()[i])
+	t.True(ok)
+
+	return item.key
+}
+
+func (t *testSignVerify) TestCache() {
+	op := t.newSignFact(NewMPrivatekey())
+
+	k := t.cacheKey(op, 0)
+	t.False(t.cache.Exists(k))
+
+	t.NoError(IsValidSignFact(op, t.networkID))
+	t.True(t.cache.Exists(k))
+
+	t.Run("wrong network id", func() {
+		err := IsValidSignFact(op, util.UUID().Bytes())
+		t.Error(err)
+		t.ErrorIs(err, ErrSignatureVerification)
+	})
+
+	t.Run("invalid signature not cached", func() {
+		sign := op.Signs()[0]
+		op.signs[0] = NewBaseSign(NewMPrivatekey().Publickey(), sign.Signature(), sign.SignedAt())
+
+		err := IsValidSignFact(op, t.networkID)
+		t.Error(err)
+		t.ErrorIs(err, ErrSignatureVerification)
+		t.False(t.cache.Exists(t.cacheKey(op, 0)))
+	})
+}
+
+func (t *testSignVerify) TestBatchVerifySignFacts() {
+	sfs := make([]SignFact, 3)
+
+	for i := range sfs {
+		sfs[i] = t.newSignFact(NewBLSPrivatekey(), NewBLSPrivatekey(), NewMPrivatekey())
+	}
+
+	t.NoError(BatchVerifySignFacts(t.networkID, sfs))
+
+	for i := range sfs {
+		for j := range sfs[i].Signs() {
+			_, isbls := sfs[i].Signs()[j].Signer().(*BLSPublickey)
+
+			// NOTE non-BLS signs are not verified by BatchVerifySignFacts
+			t.Equal(isbls, t.cache.Exists(t.cacheKey(sfs[i], j)))
+		}
+	}
+
+	t.Run("invalid bls sign", func() {
+		t.cache.Purge()
+
+		op := t.newSignFact(NewBLSPrivatekey(), NewBLSPrivatekey())
+		sign := op.Signs()[1]
+		op.signs[1] = NewBaseSign(NewBLSPrivatekey().Publickey(), sign.Signature(), sign.SignedAt())
+
+		err := BatchVerifySignFacts(t.networkID, []SignFact{sfs[0], op})
+		t.Error(err)
+		t.ErrorIs(err, ErrSignatureVerification)
+
+		t.False(t.cache.Exists(t.cacheKey(op, 1)))
+	})
+
+	t.Run("invalid non-BLS sign", func() {
+		op := t.newSignFact(NewMPrivatekey())
+		sign := op.Signs()[0]
+		op.signs[0] = NewBaseSign(NewMPrivatekey().Publickey(), sign.Signature(), sign.SignedAt())
+
+		t.NoError(BatchVerifySignFacts(t.networkID, []SignFact{op}))
+		t.False(t.cache.Exists(t.cacheKey(op, 0)))
+
+		err := IsValidSignFact(op, t.networkID)
+		t.Error(err)
+		t.ErrorIs(err, ErrSignatureVerification)
+	})
+
+	t.Run("without cache", func() {
+		SetVerifiedSignCache(nil)
+		defer SetVerifiedSignCache(t.cache)
+
+		op := t.newSignFact(NewMPrivatekey())
+		op.signs[0] = NewBaseSign(NewMPrivatekey().Publickey(), op.Signs()[0].Signature(), op.Signs()[0].SignedAt())
+
+		t.NoError(BatchVerifySignFacts(t.networkID, []SignFact{op}))
+		t.ErrorIs(IsValidSignFact(op, t.networkID), ErrSignatureVerification)
+	})
+}
+
+func (t *testSignVerify) TestCacheKey() {
+	pub := NewMPrivatekey().Publickey()
+	msg := util.UUID().Bytes()
+	sig := Signature(util.UUID().Bytes())
+
+	k := verifiedSignCacheKey(pub, msg, sig)
+	t.Equal(k, verifiedSignCacheKey(pub, msg, sig))
+
+	t.Run("different signature", func() {
+		t.NotEqual(k, verifiedSignCacheKey(pub, msg, Signature(util.UUID().Bytes())))
+	})
+
+	t.Run("shifted signature", func() {
+		a := verifiedSignCacheKey(pub, msg, append(Signature{0x00}, sig...))
+		b := verifiedSignCacheKey(pub, msg, append(sig, 0x00))
+
+		t.NotEqual(a, b)
+		t.NotEqual(k, a)
+	})
+}
+
+func TestSignVerify(t *testing.T) {
+	suite.Run(t, new(testSignVerify))
+}
@@ -87,6 +87,16 @@ func isValidVoteproofSignFacts(vp Voteproof, networkID NetworkID) error {
 	}
 
 	vs := vp.SignFacts()
+
+	sfs := make([]SignFact, len(vs))
+	for i := range vs {
+		sfs[i] = vs[i]
+	}
+
+	if err := BatchVerifySignFacts(networkID, sfs); err != nil {
+		return util.ErrInvalid.WithMessage(err, "invalid sign facts")
+	}
+
 	bs := make([]util.IsValider, len(vs))
 
 	for i := range vs {
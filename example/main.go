@@ -31,11 +31,15 @@ var CLI struct { //nolint:govet //...
 		Client launchcmd.NetworkClientCommand `cmd:"" help:"network client"`
 	} `cmd:"" help:"network"`
 	Key struct {
-		New  launchcmd.KeyNewCommand  `cmd:"" help:"generate new key"`
-		Load launchcmd.KeyLoadCommand `cmd:"" help:"load key"`
-		Sign launchcmd.KeySignCommand `cmd:"" help:"sign"`
+		New    launchcmd.KeyNewCommand    `cmd:"" help:"generate new key"`
+		Load   launchcmd.KeyLoadCommand   `cmd:"" help:"load key"`
+		Sign   launchcmd.KeySignCommand   `cmd:"" help:"sign"`
+		Export launchcmd.KeyExportCommand `cmd:"" help:"export privatekey to encrypted keystore"`
+		Import launchcmd.KeyImportCommand `cmd:"" help:"import privatekey from encrypted keystore"`
 	} `cmd:"" help:"key"`
+	Signer   launchcmd.SignerCommand    `cmd:"" help:"run local signer"`
 	Handover launchcmd.HandoverCommands `cmd:""`
+	Debug    launchcmd.DebugCommands    `cmd:"" help:"debug"`
 	Version  struct{}                   `cmd:"" help:"version"`
 	//revive:enable:nested-structs
 }
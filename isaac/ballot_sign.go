@@ -50,6 +50,10 @@ func (baseBallotSignFact) IsValid([]byte) error {
 }
 
 func (sf *baseBallotSignFact) NodeSign(priv base.Privatekey, networkID base.NetworkID, node base.Address) error {
+	if i, ok := priv.(base.BallotSigner); ok {
+		priv = i.WithBallotFact(sf.fact)
+	}
+
 	sign, err := base.NewBaseNodeSignFromFact(node, priv, networkID, sf.fact)
 	if err != nil {
 		return errors.Wrap(err, "sign base ballot sign fact")
@@ -143,6 +143,24 @@ func (m Manifest) generateHash() util.Hash {
 	))
 }
 
+// IsValidManifestHash checks the hash of manifest is generated from it's
+// fields; Manifest.IsValid() does not check the hash.
+func IsValidManifestHash(m base.Manifest) error {
+	if !NewManifest(
+		m.Height(),
+		m.Previous(),
+		m.Proposal(),
+		m.OperationsTree(),
+		m.StatesTree(),
+		m.Suffrage(),
+		m.ProposedAt(),
+	).Hash().Equal(m.Hash()) {
+		return util.ErrInvalid.Errorf("manifest hash does not match")
+	}
+
+	return nil
+}
+
 type BlockItemFile struct {
 	uri            url.URL
 	compressFormat string
@@ -205,7 +223,7 @@ func (f BlockItemFile) CompressFormat() string {
 		return f.compressFormat
 	}
 
-	if s := f.uri.Scheme; s != LocalFSBlockItemScheme && s != "file" {
+	if !IsInLocalBlockItemFile(f.uri) {
 		return ""
 	}
 
@@ -32,7 +32,13 @@ type BlockImporter struct {
 	root                     string
 	networkID                base.NetworkID
 	statestree               fixedtree.Tree
+	opstree                  fixedtree.Tree
+	trustedops               []util.Hash
+	trustedsts               []util.Hash
 	batchlimit               uint64
+	trustedl                 sync.Mutex
+	trusted                  bool
+	verifySigns              bool
 }
 
 func NewBlockImporter(
@@ -71,6 +77,26 @@ func NewBlockImporter(
 	return im, nil
 }
 
+// SetTrusted makes BlockImporter skip the signature verification of
+// operations and voteproofs; the block should be trusted by the hash chain of
+// checkpoint. The signature of BlockMap is not verified, so the operations and
+// states are checked with the trees of manifest at Save.
+func (im *BlockImporter) SetTrusted(trusted bool) *BlockImporter {
+	im.trusted = trusted
+
+	return im
+}
+
+// SetVerifySigns makes the trusted BlockImporter still verify the signatures
+// of operations and voteproofs; the block at the checkpoint height and the last
+// imported block should be verified, because their voteproofs are used as the
+// last voteproofs.
+func (im *BlockImporter) SetVerifySigns(verify bool) *BlockImporter {
+	im.verifySigns = verify
+
+	return im
+}
+
 func (im *BlockImporter) WriteMap(m base.BlockMap) error {
 	e := util.StringError("write BlockMap")
 
@@ -113,6 +139,12 @@ func (im *BlockImporter) Save(context.Context) (func(context.Context) error, err
 		return nil, e.Errorf("not yet finished")
 	}
 
+	if im.trusted {
+		if err := im.isValidTrustedItems(); err != nil {
+			return nil, e.Wrap(err)
+		}
+	}
+
 	if im.sufst != nil {
 		proof, err := im.statestree.Proof(im.sufst.Hash().String())
 		if err != nil {
@@ -185,6 +217,8 @@ func (im *BlockImporter) importItem(t base.BlockItemType, ir isaac.BlockItemRead
 		switch t {
 		case base.BlockItemStatesTree:
 			return im.importStatesTree(ir)
+		case base.BlockItemOperationsTree:
+			return im.importOperationsTree(ir)
 		case base.BlockItemStates:
 			return im.importStates(ir)
 		case base.BlockItemOperations:
@@ -229,7 +263,13 @@ func (im *BlockImporter) importOperations(ir isaac.BlockItemReader) error {
 	validate := func(op base.Operation) error {
 		return op.IsValid(im.networkID)
 	}
-	if im.m.Manifest().Height() == base.GenesisHeight {
+
+	switch {
+	case im.trusted && !im.verifySigns:
+		validate = func(op base.Operation) error {
+			return isValidTrustedOperation(op, im.networkID)
+		}
+	case im.m.Manifest().Height() == base.GenesisHeight:
 		validate = func(op base.Operation) error {
 			return base.IsValidGenesisOperation(op, im.networkID, im.m.Signer())
 		}
@@ -266,6 +306,18 @@ func (im *BlockImporter) importOperations(ir isaac.BlockItemReader) error {
 	default:
 		ops = ops[:i]
 
+		if im.trusted {
+			facts := make([]util.Hash, len(ops))
+
+			for i := range ops {
+				facts[i] = ops[i].Fact().Hash()
+			}
+
+			im.trustedl.Lock()
+			im.trustedops = facts
+			im.trustedl.Unlock()
+		}
+
 		return im.bwdb.SetOperations(ophs[:len(ops)])
 	}
 }
@@ -303,6 +355,18 @@ func (im *BlockImporter) importStates(ir isaac.BlockItemReader) error {
 	default:
 		sts = sts[:i]
 
+		if im.trusted {
+			hs := make([]util.Hash, len(sts))
+
+			for i := range sts {
+				hs[i] = sts[i].Hash()
+			}
+
+			im.trustedl.Lock()
+			im.trustedsts = hs
+			im.trustedl.Unlock()
+		}
+
 		return im.bwdb.SetStates(sts)
 	}
 }
@@ -316,6 +380,15 @@ func (im *BlockImporter) importStatesTree(ir isaac.BlockItemReader) error {
 	}
 }
 
+func (im *BlockImporter) importOperationsTree(ir isaac.BlockItemReader) error {
+	switch v, err := ir.Decode(); {
+	case err != nil:
+		return errors.WithMessage(err, "operations tree")
+	default:
+		return util.SetInterfaceValue(v, &im.opstree)
+	}
+}
+
 func (im *BlockImporter) importVoteproofs(ir isaac.BlockItemReader) error {
 	switch v, err := ir.Decode(); {
 	case err != nil:
@@ -325,9 +398,11 @@ func (im *BlockImporter) importVoteproofs(ir isaac.BlockItemReader) error {
 		case err != nil:
 			return err
 		default:
-			for i := range vps {
-				if err := vps[i].IsValid(im.networkID); err != nil {
-					return err
+			if !im.trusted || im.verifySigns {
+				for i := range vps {
+					if err := vps[i].IsValid(im.networkID); err != nil {
+						return err
+					}
 				}
 			}
 
@@ -347,3 +422,100 @@ func (*BlockImporter) importOther(ir isaac.BlockItemReader) error {
 
 	return nil
 }
+
+// isValidTrustedItems checks the operations and states with the trees of
+// manifest; the trees are checked by it's root hash in manifest, so the items
+// can not be replaced without changing manifest.
+func (im *BlockImporter) isValidTrustedItems() error {
+	im.trustedl.Lock()
+	defer im.trustedl.Unlock()
+
+	manifest := im.m.Manifest()
+
+	switch keys, err := isValidTrustedTree(im.statestree, manifest.StatesTree()); {
+	case err != nil:
+		return errors.WithMessage(err, "states tree")
+	case len(keys) != len(im.trustedsts):
+		return errors.Errorf("states does not match with states tree, %d != %d", len(im.trustedsts), len(keys))
+	default:
+		for i := range im.trustedsts {
+			k := im.trustedsts[i].String()
+
+			if _, found := keys[k]; !found {
+				return errors.Errorf("state not in states tree, %q", k)
+			}
+
+			delete(keys, k) // NOTE duplicated state
+		}
+	}
+
+	switch keys, err := isValidTrustedTree(im.opstree, manifest.OperationsTree()); {
+	case err != nil:
+		return errors.WithMessage(err, "operations tree")
+	case len(im.trustedops) > len(keys):
+		return errors.Errorf("too many operations for operations tree, %d > %d", len(im.trustedops), len(keys))
+	default:
+		for i := range im.trustedops {
+			k := im.trustedops[i].String()
+
+			if _, found := keys[k]; found {
+				delete(keys, k)
+
+				continue
+			}
+
+			if _, found := keys[k+"-"]; found { // NOTE not in state operation
+				delete(keys, k+"-")
+
+				continue
+			}
+
+			return errors.Errorf("operation not in operations tree, %q", k)
+		}
+	}
+
+	return nil
+}
+
+func isValidTrustedTree(tr fixedtree.Tree, root util.Hash) (map[string]struct{}, error) {
+	switch {
+	case root == nil:
+		if tr.Len() > 0 {
+			return nil, errors.Errorf("not empty tree for empty root")
+		}
+
+		return map[string]struct{}{}, nil
+	case tr.Len() < 1:
+		return nil, errors.Errorf("empty tree")
+	}
+
+	if err := tr.IsValid(nil); err != nil {
+		return nil, err
+	}
+
+	if !tr.Root().Equal(root) {
+		return nil, errors.Errorf("root does not match with manifest")
+	}
+
+	keys := make(map[string]struct{}, tr.Len())
+
+	if err := tr.Traverse(func(_ uint64, n fixedtree.Node) (bool, error) {
+		keys[n.Key()] = struct{}{}
+
+		return true, nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// isValidTrustedOperation checks the fact of operation without verifying the
+// signatures; the fact hash is checked with operations tree.
+func isValidTrustedOperation(op base.Operation, networkID base.NetworkID) error {
+	if op.Fact() == nil {
+		return util.ErrInvalid.Errorf("empty fact")
+	}
+
+	return op.Fact().IsValid(networkID)
+}
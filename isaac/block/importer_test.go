@@ -308,6 +308,57 @@ func (t *testBlockImporter) TestWriteVoteproofs() {
 	})
 }
 
+func (t *testBlockImporter) TestWriteVoteproofsTrusted() {
+	point := base.RawPoint(33, 44)
+	m := t.prepare(point)
+
+	networkID := util.UUID().Bytes() // NOTE different network id
+
+	t.Run("not trusted", func() {
+		bwdb := t.NewLeveldbBlockWriteDatabase(point.Height())
+		defer bwdb.DeepClose()
+
+		im, err := NewBlockImporter(t.Root, t.Encs, m, bwdb, func(context.Context) error { return nil }, networkID)
+		t.NoError(err)
+
+		_, _, err = t.Readers.Item(point.Height(), base.BlockItemVoteproofs, func(ir isaac.BlockItemReader) error {
+			return im.WriteItem(base.BlockItemVoteproofs, ir)
+		})
+		t.Error(err)
+		t.ErrorIs(err, base.ErrSignatureVerification)
+	})
+
+	t.Run("trusted", func() {
+		bwdb := t.NewLeveldbBlockWriteDatabase(point.Height())
+		defer bwdb.DeepClose()
+
+		im, err := NewBlockImporter(t.Root, t.Encs, m, bwdb, func(context.Context) error { return nil }, networkID)
+		t.NoError(err)
+		im.SetTrusted(true)
+
+		_, found, err := t.Readers.Item(point.Height(), base.BlockItemVoteproofs, func(ir isaac.BlockItemReader) error {
+			return im.WriteItem(base.BlockItemVoteproofs, ir)
+		})
+		t.True(found)
+		t.NoError(err)
+	})
+
+	t.Run("trusted, but verify signs", func() {
+		bwdb := t.NewLeveldbBlockWriteDatabase(point.Height())
+		defer bwdb.DeepClose()
+
+		im, err := NewBlockImporter(t.Root, t.Encs, m, bwdb, func(context.Context) error { return nil }, networkID)
+		t.NoError(err)
+		im.SetTrusted(true).SetVerifySigns(true)
+
+		_, _, err = t.Readers.Item(point.Height(), base.BlockItemVoteproofs, func(ir isaac.BlockItemReader) error {
+			return im.WriteItem(base.BlockItemVoteproofs, ir)
+		})
+		t.Error(err)
+		t.ErrorIs(err, base.ErrSignatureVerification)
+	})
+}
+
 func (t *testBlockImporter) TestWriteStates() {
 	point := base.RawPoint(33, 44)
 	m := t.prepare(point)
@@ -516,6 +567,109 @@ func (t *testBlockImporter) TestSave() {
 	})
 }
 
+func (t *testBlockImporter) TestSaveTrusted() {
+	point := base.RawPoint(33, 44)
+	m := t.prepare(point)
+	readers := t.Readers
+
+	// NOTE other block of same height
+	root := t.Root
+	t.Root = filepath.Join(root, "other")
+	t.NoError(os.MkdirAll(t.Root, 0o700))
+	t.Readers = t.NewReaders(t.Root)
+	om := t.prepare(point)
+	oreaders := t.Readers
+	t.Root, t.Readers = root, readers
+
+	// NOTE the manifest is not changed, but the items are replaced with the
+	// other block; under checkpoint, the signature of BlockMap is not verified.
+	tampered := func(types ...base.BlockItemType) (base.BlockMap, map[base.BlockItemType]bool) {
+		others := map[base.BlockItemType]bool{}
+		for i := range types {
+			others[types[i]] = true
+		}
+
+		n := NewBlockMap()
+		n.SetManifest(m.Manifest())
+
+		m.Items(func(item base.BlockMapItem) bool {
+			if others[item.Type()] {
+				item, _ = om.Item(item.Type())
+			}
+
+			t.NoError(n.SetItem(item))
+
+			return true
+		})
+
+		return n, others
+	}
+
+	importf := func(types ...base.BlockItemType) error {
+		bm, others := tampered(types...)
+
+		bwdb := t.NewLeveldbBlockWriteDatabase(point.Height())
+		defer bwdb.DeepClose()
+
+		im, err := NewBlockImporter(
+			filepath.Join(t.Root, util.UUID().String()), t.Encs, bm, bwdb,
+			func(context.Context) error { return nil }, t.LocalParams.NetworkID(),
+		)
+		t.NoError(err)
+		im.SetTrusted(true)
+
+		bm.Items(func(item base.BlockMapItem) bool {
+			r := readers
+			if others[item.Type()] {
+				r = oreaders
+			}
+
+			_, found, err := r.Item(point.Height(), item.Type(), func(ir isaac.BlockItemReader) error {
+				return im.WriteItem(item.Type(), ir)
+			})
+			t.True(found, "not found: %q", item.Type())
+			t.NoError(err, "failed: %q", item.Type())
+
+			return true
+		})
+
+		_, err = im.Save(context.Background())
+		if err != nil {
+			t.NoError(im.CancelImport(context.Background()))
+		}
+
+		return err
+	}
+
+	t.Run("ok", func() {
+		t.NoError(importf())
+	})
+
+	t.Run("tampered states", func() {
+		err := importf(base.BlockItemStates)
+		t.Error(err)
+		t.ErrorContains(err, "state not in states tree")
+	})
+
+	t.Run("tampered states and states tree", func() {
+		err := importf(base.BlockItemStates, base.BlockItemStatesTree)
+		t.Error(err)
+		t.ErrorContains(err, "root does not match with manifest")
+	})
+
+	t.Run("tampered operations", func() {
+		err := importf(base.BlockItemOperations)
+		t.Error(err)
+		t.ErrorContains(err, "operation not in operations tree")
+	})
+
+	t.Run("tampered operations and operations tree", func() {
+		err := importf(base.BlockItemOperations, base.BlockItemOperationsTree)
+		t.Error(err)
+		t.ErrorContains(err, "root does not match with manifest")
+	})
+}
+
 func (t *testBlockImporter) TestCancelImport() {
 	point := base.RawPoint(33, 44)
 	m := t.prepare(point)
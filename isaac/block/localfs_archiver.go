@@ -0,0 +1,576 @@
+package isaacblock
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+var DefaultLocalFSArchiverInterval = time.Minute * 10
+
+var ErrStaleBlockArchive = util.NewIDError("stale block archive")
+
+type LocalFSArchiverArgs struct {
+	LastHeightFunc func() (base.Height, bool, error)
+	// Heights is the number of heights in one archive; if zero, archiving is
+	// disabled. The last Heights blocks are not archived.
+	Heights      func() uint64
+	Interval     func() time.Duration
+	WhenArchived func(*isaac.BlockArchive)
+}
+
+func NewLocalFSArchiverArgs() *LocalFSArchiverArgs {
+	return &LocalFSArchiverArgs{
+		LastHeightFunc: func() (base.Height, bool, error) { return base.NilHeight, false, nil },
+		Heights:        func() uint64 { return 0 },
+		Interval:       func() time.Duration { return DefaultLocalFSArchiverInterval },
+		WhenArchived:   func(*isaac.BlockArchive) {},
+	}
+}
+
+// LocalFSArchiver packs the old height directories of LocalFSWriter into the
+// block archives. The local item files of block item files are replaced with
+// the archive item files and the height directories are removed. Before
+// replacing, the archived items are checked with the current blockmap; if not
+// matched, the archive is truncated. The blocks in local fs should be removed
+// thru RemoveBlocks to prevent removing blocks during archiving.
+type LocalFSArchiver struct {
+	*logging.Logging
+	*util.ContextDaemon
+	readers *isaac.BlockItemReaders
+	args    *LocalFSArchiverArgs
+	resumed bool
+	sync.Mutex
+}
+
+func NewLocalFSArchiver(readers *isaac.BlockItemReaders, args *LocalFSArchiverArgs) *LocalFSArchiver {
+	if args == nil {
+		args = NewLocalFSArchiverArgs() //revive:disable-line:modifies-parameter
+	}
+
+	a := &LocalFSArchiver{
+		Logging: logging.NewLogging(func(zctx zerolog.Context) zerolog.Context {
+			return zctx.Str("module", "localfs-archiver")
+		}),
+		readers: readers,
+		args:    args,
+	}
+
+	a.ContextDaemon = util.NewContextDaemon(a.start)
+
+	return a
+}
+
+// Archive archives the heights ready to be archived.
+func (a *LocalFSArchiver) Archive() (archived []*isaac.BlockArchive, _ error) {
+	a.Lock()
+	defer a.Unlock()
+
+	e := util.StringError("archive")
+
+	n := a.args.Heights()
+	if n < 1 {
+		return nil, nil
+	}
+
+	if err := a.readers.ReloadArchives(); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	var next base.Height
+
+	switch archives := a.readers.Archives(); {
+	case len(archives) < 1:
+		next = base.GenesisHeight
+	default:
+		last := archives[len(archives)-1]
+		next = last.To() + 1
+
+		if !a.resumed {
+			// NOTE the items of last archive may not be replaced yet
+			switch i, err := a.replace(last); {
+			case err != nil:
+				return nil, e.Wrap(err)
+			case i == nil:
+				next = last.From()
+			default:
+				next = i.To() + 1
+			}
+
+			a.resumed = true
+		}
+	}
+
+	var top base.Height
+
+	switch i, found, err := a.args.LastHeightFunc(); {
+	case err != nil:
+		return nil, e.Wrap(err)
+	case !found:
+		return nil, nil
+	default:
+		top = i
+	}
+
+	for {
+		to := next + base.Height(n) - 1 //nolint:gosec //...
+
+		if to+base.Height(n) > top { //nolint:gosec //...
+			break
+		}
+
+		i, err := a.archive(next, to)
+		if err != nil {
+			return archived, e.Wrap(err)
+		}
+
+		archived = append(archived, i)
+
+		a.args.WhenArchived(i)
+
+		next = to + 1
+	}
+
+	return archived, nil
+}
+
+func (a *LocalFSArchiver) archive(from, to base.Height) (*isaac.BlockArchive, error) {
+	e := util.StringError("archive %d-%d", from, to)
+
+	w, err := isaac.NewBlockArchiveWriter(a.readers.Root(), from, to)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	for height := from; height <= to; height++ {
+		if err := a.add(w, height); err != nil {
+			_ = w.Cancel()
+
+			return nil, e.Wrap(err)
+		}
+	}
+
+	archive, err := w.Save()
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	if err := a.readers.ReloadArchives(); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	switch i, err := a.replace(archive); {
+	case err != nil:
+		return nil, e.Wrap(err)
+	case i == nil || i.To() != to:
+		return nil, e.Wrap(ErrStaleBlockArchive.Errorf("archived items changed during archiving"))
+	}
+
+	a.Log().Debug().Interface("from", from).Interface("to", to).Msg("archived")
+
+	return archive, nil
+}
+
+func (a *LocalFSArchiver) add(w *isaac.BlockArchiveWriter, height base.Height) error {
+	var bfiles base.BlockItemFiles
+
+	switch i, found, err := a.readers.ItemFiles(height); {
+	case err != nil:
+		return err
+	case !found:
+		return nil
+	default:
+		bfiles = i
+	}
+
+	items := bfiles.Items()
+
+	for t := range items {
+		item := items[t]
+
+		if item.URI().Scheme != isaac.LocalFSBlockItemScheme {
+			continue
+		}
+
+		switch f, found, err := a.readers.ReadFileFromItemFile(height, item); {
+		case err != nil:
+			return err
+		case !found:
+			return util.ErrNotFound.Errorf("item file, %q of height, %d", t, height)
+		default:
+			err := w.Add(height, strings.TrimPrefix(item.URI().Path, "/"), f)
+
+			_ = f.Close()
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// RemoveBlocks removes the blocks from height in local fs like
+// RemoveBlocksFromLocalFS; it waits the running archiving.
+func (a *LocalFSArchiver) RemoveBlocks(height base.Height) (bool, error) {
+	a.Lock()
+	defer a.Unlock()
+
+	removed, err := RemoveBlocksFromLocalFS(a.readers.Root(), height)
+
+	a.readers.PurgeItemFiles()
+
+	if err != nil {
+		return removed, err
+	}
+
+	// NOTE the last archive will be checked again
+	a.resumed = false
+
+	return removed, a.readers.ReloadArchives()
+}
+
+// replace replaces the local item files with the archive item files and removes
+// the height directories. If the archived items of height does not match with
+// the current blockmap, the archive is truncated to the previous height and
+// the truncated archive is returned; if whole archive is stale, nil is
+// returned.
+func (a *LocalFSArchiver) replace(archive *isaac.BlockArchive) (*isaac.BlockArchive, error) {
+	for height := archive.From(); height <= archive.To(); height++ {
+		switch err := a.replaceHeight(archive, height); {
+		case err == nil:
+		case errors.Is(err, ErrStaleBlockArchive):
+			a.Log().Warn().Err(err).
+				Interface("from", archive.From()).
+				Interface("to", archive.To()).
+				Interface("height", height).
+				Msg("stale archive found; truncate")
+
+			return a.truncate(archive, height)
+		default:
+			return nil, err
+		}
+	}
+
+	return archive, nil
+}
+
+func (a *LocalFSArchiver) truncate(archive *isaac.BlockArchive, height base.Height) (*isaac.BlockArchive, error) {
+	if err := truncateBlockArchive(a.readers.Root(), archive.Path(), height); err != nil {
+		return nil, err
+	}
+
+	if err := a.readers.ReloadArchives(); err != nil {
+		return nil, err
+	}
+
+	if height == archive.From() {
+		return nil, nil
+	}
+
+	switch i, found, err := a.readers.Archive(height - 1); {
+	case err != nil:
+		return nil, err
+	case !found:
+		return nil, util.ErrNotFound.Errorf("truncated archive, %d-%d", archive.From(), height-1)
+	default:
+		return i, nil
+	}
+}
+
+func (a *LocalFSArchiver) replaceHeight(archive *isaac.BlockArchive, height base.Height) error {
+	var bfiles base.BlockItemFiles
+
+	switch i, found, err := a.readers.ItemFiles(height); {
+	case err != nil:
+		return err
+	case !found:
+		// NOTE the block of archived height was removed
+		return ErrStaleBlockArchive.Errorf("block item files of height, %d not found", height)
+	default:
+		bfiles = i
+	}
+
+	var m base.BlockMap
+
+	switch i, found, err := isaac.BlockItemReadersDecode[base.BlockMap](
+		a.readers.Item, height, base.BlockItemMap, nil); {
+	case err != nil:
+		return err
+	case !found:
+		return util.ErrNotFound.Errorf("blockmap of height, %d", height)
+	default:
+		m = i
+	}
+
+	items := bfiles.Items()
+	newitems := make(map[base.BlockItemType]base.BlockItemFile, len(items))
+
+	var replaced, haslocal bool
+
+	for t := range items {
+		item := items[t]
+		newitems[t] = item
+
+		if item.URI().Scheme != isaac.LocalFSBlockItemScheme {
+			continue
+		}
+
+		name := strings.TrimPrefix(item.URI().Path, "/")
+
+		if _, found := archive.Item(height, name); !found {
+			haslocal = true
+
+			continue
+		}
+
+		if err := a.checkArchivedItem(archive, height, m, t, item); err != nil {
+			return err
+		}
+
+		newitems[t] = isaac.NewBlockArchiveBlockItemFile(name, item.CompressFormat())
+		replaced = true
+	}
+
+	if replaced {
+		b, err := util.MarshalJSON(isaac.NewBlockItemFiles(newitems))
+		if err != nil {
+			return err
+		}
+
+		if _, err := a.readers.WriteItemFiles(height, b); err != nil {
+			return err
+		}
+	}
+
+	if haslocal {
+		return nil
+	}
+
+	d := filepath.Join(a.readers.Root(), isaac.BlockHeightDirectory(height))
+
+	if err := os.RemoveAll(d); err != nil {
+		return errors.WithMessagef(err, "remove %q", d)
+	}
+
+	return nil
+}
+
+// checkArchivedItem compares the checksum of archived item with the blockmap
+// item; the blockmap itself is compared with the local file.
+func (a *LocalFSArchiver) checkArchivedItem(
+	archive *isaac.BlockArchive,
+	height base.Height,
+	m base.BlockMap,
+	t base.BlockItemType,
+	item base.BlockItemFile,
+) error {
+	name := strings.TrimPrefix(item.URI().Path, "/")
+
+	mitem, found := m.Item(t)
+	if t == base.BlockItemMap || !found {
+		return a.checkArchivedRawItem(archive, height, name, item)
+	}
+
+	var checksum string
+
+	switch r, found, err := archive.Open(height, name); {
+	case err != nil:
+		return err
+	case !found:
+		return util.ErrNotFound.Errorf("archived item, %q of height, %d", t, height)
+	default:
+		defer func() {
+			_ = r.Close()
+		}()
+
+		i, err := decompressedChecksum(r, item.CompressFormat())
+		if err != nil {
+			return errors.WithMessagef(err, "archived item, %q of height, %d", t, height)
+		}
+
+		checksum = i
+	}
+
+	if checksum != mitem.Checksum() {
+		return ErrStaleBlockArchive.Errorf(
+			"archived item, %q of height, %d does not match with blockmap; %q != %q",
+			t, height, checksum, mitem.Checksum())
+	}
+
+	return nil
+}
+
+func (a *LocalFSArchiver) checkArchivedRawItem(
+	archive *isaac.BlockArchive,
+	height base.Height,
+	name string,
+	item base.BlockItemFile,
+) error {
+	aitem, _ := archive.Item(height, name)
+
+	switch f, found, err := a.readers.ReadFileFromItemFile(height, item); {
+	case err != nil:
+		return err
+	case !found:
+		return util.ErrNotFound.Errorf("item file, %q of height, %d", name, height)
+	default:
+		defer func() {
+			_ = f.Close()
+		}()
+
+		h := sha256.New()
+
+		if _, err := io.Copy(h, f); err != nil {
+			return errors.WithStack(err)
+		}
+
+		if checksum := fmt.Sprintf("%x", h.Sum(nil)); checksum != aitem.Checksum {
+			return ErrStaleBlockArchive.Errorf(
+				"archived item, %q of height, %d does not match with local file; %q != %q",
+				name, height, aitem.Checksum, checksum)
+		}
+
+		return nil
+	}
+}
+
+func (a *LocalFSArchiver) start(ctx context.Context) error {
+	interval := a.interval()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+end:
+	for {
+		select {
+		case <-ctx.Done():
+			break end
+		case <-ticker.C:
+			switch archived, err := a.Archive(); {
+			case err != nil:
+				a.Log().Error().Err(err).Msg("failed to archive")
+			case len(archived) > 0:
+				a.Log().Debug().Int("archives", len(archived)).Msg("archived")
+			}
+
+			if i := a.interval(); i != interval {
+				ticker.Reset(i)
+				interval = i
+			}
+		}
+	}
+
+	return nil
+}
+
+func (a *LocalFSArchiver) interval() time.Duration {
+	switch d := a.args.Interval(); {
+	case d < 1:
+		return DefaultLocalFSArchiverInterval
+	default:
+		return d
+	}
+}
+
+// truncateBlockArchive rebuilds the archive only with the items below height
+// and removes the original archive; if height is the first height of archive,
+// the archive is just removed.
+func truncateBlockArchive(root, path string, height base.Height) error {
+	e := util.StringError("truncate block archive, %q", path)
+
+	archive, err := isaac.OpenBlockArchive(path)
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	if height > archive.From() {
+		w, err := isaac.NewBlockArchiveWriter(root, archive.From(), height-1)
+		if err != nil {
+			return e.Wrap(err)
+		}
+
+		if err := copyBlockArchiveItems(w, archive, height); err != nil {
+			_ = w.Cancel()
+
+			return e.Wrap(err)
+		}
+
+		if _, err := w.Save(); err != nil {
+			return e.Wrap(err)
+		}
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
+
+func copyBlockArchiveItems(w *isaac.BlockArchiveWriter, archive *isaac.BlockArchive, height base.Height) error {
+	items := archive.Index().Items
+
+	for i := range items {
+		item := items[i]
+
+		if item.Height >= height {
+			continue
+		}
+
+		switch r, found, err := archive.Open(item.Height, item.Name); {
+		case err != nil:
+			return err
+		case !found:
+			return util.ErrNotFound.Errorf("archived item, %q of height, %d", item.Name, item.Height)
+		default:
+			err := w.Add(item.Height, item.Name, r)
+
+			_ = r.Close()
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func decompressedChecksum(r io.Reader, compressFormat string) (string, error) {
+	cr, err := util.NewCompressedReader(r, compressFormat, nil)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_ = cr.Close()
+	}()
+
+	dr, err := cr.Decompress()
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+
+	if _, err := io.Copy(h, dr); err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
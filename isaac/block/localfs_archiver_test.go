@@ -0,0 +1,356 @@
+package isaacblock
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/stretchr/testify/suite"
+)
+
+type testLocalFSArchiver struct {
+	BaseTestLocalBlockFS
+}
+
+func (t *testLocalFSArchiver) prepare(from, to base.Height) {
+	for height := from; height <= to; height++ {
+		fs, _, _, _, _, _, _ := t.PrepareFS(base.NewPoint(height, 0), nil, nil)
+
+		_, err := fs.Save(context.Background())
+		t.NoError(err)
+	}
+}
+
+func (t *testLocalFSArchiver) newArchiver(heights uint64, last *base.Height) *LocalFSArchiver {
+	args := NewLocalFSArchiverArgs()
+	args.Heights = func() uint64 { return heights }
+	args.LastHeightFunc = func() (base.Height, bool, error) {
+		return *last, true, nil
+	}
+
+	a := NewLocalFSArchiver(t.Readers, args)
+	_ = a.SetLogging(logging.TestNilLogging)
+
+	return a
+}
+
+func (t *testLocalFSArchiver) readMap(height base.Height) base.BlockMap {
+	m, found, err := isaac.BlockItemReadersDecode[base.BlockMap](t.Readers.Item, height, base.BlockItemMap, nil)
+	t.NoError(err)
+	t.True(found)
+
+	return m
+}
+
+func (t *testLocalFSArchiver) rawItem(height base.Height, it base.BlockItemType) []byte {
+	fname, err := DefaultBlockItemFileName(it, t.Encs.JSON().Hint().Type())
+	t.NoError(err)
+
+	b, err := os.ReadFile(filepath.Join(t.Root, isaac.BlockHeightDirectory(height), fname))
+	t.NoError(err)
+
+	return b
+}
+
+func (t *testLocalFSArchiver) isHeightDirectoryExists(height base.Height) bool {
+	_, err := os.Stat(filepath.Join(t.Root, isaac.BlockHeightDirectory(height)))
+	if os.IsNotExist(err) {
+		return false
+	}
+
+	t.NoError(err)
+
+	return true
+}
+
+func (t *testLocalFSArchiver) TestArchive() {
+	t.prepare(0, 9)
+
+	maps := map[base.Height]base.BlockMap{}
+	for height := base.Height(0); height <= 9; height++ {
+		maps[height] = t.readMap(height)
+	}
+
+	rawmap := t.rawItem(4, base.BlockItemMap)
+
+	last := base.Height(9)
+	a := t.newArchiver(3, &last)
+
+	t.Run("archive", func() {
+		archived, err := a.Archive()
+		t.NoError(err)
+		t.Equal(2, len(archived))
+
+		t.Equal(base.Height(0), archived[0].From())
+		t.Equal(base.Height(2), archived[0].To())
+		t.Equal(base.Height(3), archived[1].From())
+		t.Equal(base.Height(5), archived[1].To())
+	})
+
+	t.Run("archived heights", func() {
+		for height := base.Height(0); height <= 5; height++ {
+			t.False(t.isHeightDirectoryExists(height), "height=%d", height)
+
+			bfiles, found, err := t.Readers.ItemFiles(height)
+			t.NoError(err)
+			t.True(found)
+
+			for _, item := range bfiles.Items() {
+				t.Equal(isaac.BlockArchiveBlockItemScheme, item.URI().Scheme)
+			}
+
+			m := t.readMap(height)
+			t.True(maps[height].Manifest().Hash().Equal(m.Manifest().Hash()))
+		}
+	})
+
+	t.Run("not archived heights", func() {
+		for height := base.Height(6); height <= 9; height++ {
+			t.True(t.isHeightDirectoryExists(height), "height=%d", height)
+		}
+	})
+
+	t.Run("nothing to archive", func() {
+		archived, err := a.Archive()
+		t.NoError(err)
+		t.Empty(archived)
+	})
+
+	t.Run("next archive", func() {
+		t.prepare(10, 11)
+		last = 11
+
+		archived, err := a.Archive()
+		t.NoError(err)
+		t.Equal(1, len(archived))
+
+		t.Equal(base.Height(6), archived[0].From())
+		t.Equal(base.Height(8), archived[0].To())
+
+		m := t.readMap(7)
+		t.True(maps[7].Manifest().Hash().Equal(m.Manifest().Hash()))
+	})
+
+	t.Run("http", func() {
+		ts := httptest.NewServer(isaac.BlockItemReadersHTTPHandler(t.Readers))
+		defer ts.Close()
+
+		get := func(path, r string) *http.Response {
+			req, err := http.NewRequest(http.MethodGet, ts.URL+path, nil)
+			t.NoError(err)
+
+			if len(r) > 0 {
+				req.Header.Set("Range", r)
+			}
+
+			res, err := http.DefaultClient.Do(req)
+			t.NoError(err)
+
+			return res
+		}
+
+		res := get("/4/map", "")
+		defer res.Body.Close()
+
+		t.Equal(http.StatusOK, res.StatusCode)
+
+		b, err := io.ReadAll(res.Body)
+		t.NoError(err)
+		t.Equal(rawmap, b)
+
+		rres := get("/4/map", "bytes=3-9")
+		defer rres.Body.Close()
+
+		t.Equal(http.StatusPartialContent, rres.StatusCode)
+
+		rb, err := io.ReadAll(rres.Body)
+		t.NoError(err)
+		t.Equal(rawmap[3:10], rb)
+
+		ures := get("/44/map", "")
+		defer ures.Body.Close()
+
+		t.Equal(http.StatusNotFound, ures.StatusCode)
+	})
+
+	t.Run("remove blocks", func() {
+		removed, err := RemoveBlocksFromLocalFS(t.Root, 3)
+		t.NoError(err)
+		t.True(removed)
+
+		archives, err := isaac.LoadBlockArchives(t.Root)
+		t.NoError(err)
+		t.Equal(1, len(archives))
+		t.Equal(base.Height(0), archives[0].From())
+	})
+}
+
+func (t *testLocalFSArchiver) TestResume() {
+	t.prepare(0, 5)
+
+	rawmap := t.rawItem(1, base.BlockItemMap)
+
+	// NOTE archive file created, but items not replaced
+	w, err := isaac.NewBlockArchiveWriter(t.Root, 0, 2)
+	t.NoError(err)
+
+	a := t.newArchiver(3, nil)
+
+	for height := base.Height(0); height <= 2; height++ {
+		t.NoError(a.add(w, height))
+	}
+
+	_, err = w.Save()
+	t.NoError(err)
+
+	for height := base.Height(0); height <= 2; height++ {
+		t.True(t.isHeightDirectoryExists(height))
+	}
+
+	last := base.Height(5)
+	a = t.newArchiver(3, &last)
+
+	archived, err := a.Archive()
+	t.NoError(err)
+	t.Empty(archived)
+
+	for height := base.Height(0); height <= 2; height++ {
+		t.False(t.isHeightDirectoryExists(height), "height=%d", height)
+	}
+
+	r, found, err := t.Readers.ReadFileFromItemFile(1, isaac.NewBlockArchiveBlockItemFile("map.json", ""))
+	t.NoError(err)
+	t.True(found)
+	defer r.Close()
+
+	b, err := io.ReadAll(r)
+	t.NoError(err)
+	t.Equal(rawmap, b)
+}
+
+func (t *testLocalFSArchiver) TestRemoveBlocks() {
+	t.prepare(0, 9)
+
+	last := base.Height(9)
+	a := t.newArchiver(3, &last)
+
+	archived, err := a.Archive()
+	t.NoError(err)
+	t.Equal(2, len(archived))
+
+	oldmap := t.readMap(4)
+
+	removed, err := a.RemoveBlocks(4)
+	t.NoError(err)
+	t.True(removed)
+
+	t.Run("truncated", func() {
+		archives, err := isaac.LoadBlockArchives(t.Root)
+		t.NoError(err)
+		t.Equal(2, len(archives))
+
+		t.Equal(base.Height(0), archives[0].From())
+		t.Equal(base.Height(2), archives[0].To())
+		t.Equal(base.Height(3), archives[1].From())
+		t.Equal(base.Height(3), archives[1].To())
+
+		_, found := archives[1].Item(4, "map.json")
+		t.False(found)
+
+		m := t.readMap(3)
+		t.Equal(base.Height(3), m.Manifest().Height())
+	})
+
+	t.Run("archive new blocks", func() {
+		t.prepare(4, 11)
+		last = 11
+
+		newmap := t.readMap(4)
+		t.False(oldmap.Manifest().Hash().Equal(newmap.Manifest().Hash()))
+
+		archived, err := a.Archive()
+		t.NoError(err)
+		t.Equal(1, len(archived))
+
+		t.Equal(base.Height(4), archived[0].From())
+		t.Equal(base.Height(6), archived[0].To())
+
+		m := t.readMap(4)
+		t.True(newmap.Manifest().Hash().Equal(m.Manifest().Hash()))
+	})
+}
+
+func (t *testLocalFSArchiver) TestStaleArchive() {
+	t.prepare(0, 5)
+
+	// NOTE archive file created, but items not replaced
+	w, err := isaac.NewBlockArchiveWriter(t.Root, 0, 2)
+	t.NoError(err)
+
+	a := t.newArchiver(3, nil)
+
+	for height := base.Height(0); height <= 2; height++ {
+		t.NoError(a.add(w, height))
+	}
+
+	_, err = w.Save()
+	t.NoError(err)
+
+	// NOTE blocks are removed without truncating archive
+	for height := base.Height(5); height >= 1; height-- {
+		_, err := RemoveBlockFromLocalFS(t.Root, height)
+		t.NoError(err)
+	}
+
+	t.prepare(1, 5)
+
+	newmap := t.readMap(1)
+
+	last := base.Height(5)
+	a = t.newArchiver(3, &last)
+
+	archived, err := a.Archive()
+	t.NoError(err)
+	t.Empty(archived)
+
+	archives, err := isaac.LoadBlockArchives(t.Root)
+	t.NoError(err)
+	t.Equal(1, len(archives))
+	t.Equal(base.Height(0), archives[0].From())
+	t.Equal(base.Height(0), archives[0].To())
+
+	t.False(t.isHeightDirectoryExists(0))
+
+	for height := base.Height(1); height <= 5; height++ {
+		t.True(t.isHeightDirectoryExists(height), "height=%d", height)
+	}
+
+	m := t.readMap(1)
+	t.True(newmap.Manifest().Hash().Equal(m.Manifest().Hash()))
+}
+
+func (t *testLocalFSArchiver) TestDisabled() {
+	t.prepare(0, 9)
+
+	last := base.Height(9)
+	a := t.newArchiver(0, &last)
+
+	archived, err := a.Archive()
+	t.NoError(err)
+	t.Empty(archived)
+
+	archives, err := isaac.LoadBlockArchives(t.Root)
+	t.NoError(err)
+	t.Empty(archives)
+}
+
+func TestLocalFSArchiver(t *testing.T) {
+	suite.Run(t, new(testLocalFSArchiver))
+}
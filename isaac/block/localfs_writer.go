@@ -23,6 +23,7 @@ import (
 	"github.com/ProtoconNet/mitum2/util/encoder"
 	"github.com/ProtoconNet/mitum2/util/fixedtree"
 	"github.com/ProtoconNet/mitum2/util/hint"
+	"github.com/ProtoconNet/mitum2/util/tracing"
 	"github.com/pkg/errors"
 )
 
@@ -292,7 +293,13 @@ func (w *LocalFSWriter) Save(ctx context.Context) (base.BlockMap, error) {
 		return nil, errors.Errorf("save fs writer; check height directory")
 	}
 
-	switch m, err := w.save(ctx, heightdirectory); {
+	sctx, span := tracing.Start(ctx, "localfs-writer.save", tracing.WithAttribute("height", w.height))
+
+	m, err := w.save(sctx, heightdirectory)
+
+	span.End(err)
+
+	switch {
 	case err != nil:
 		_ = os.RemoveAll(heightdirectory)
 
@@ -775,9 +782,57 @@ func RemoveBlocksFromLocalFS(root string, height base.Height) (bool, error) {
 		}
 	}
 
+	if err := removeBlockArchivesFromLocalFS(root, height); err != nil {
+		return true, err
+	}
+
 	return true, nil
 }
 
+// removeBlockArchivesFromLocalFS removes the block archives, which starts from
+// over height. The archive, which has the height in the middle of range, is
+// truncated to the previous height of height; the archived items of removed
+// heights should not be used for the new blocks.
+func removeBlockArchivesFromLocalFS(root string, height base.Height) error {
+	d := filepath.Join(root, isaac.BlockArchiveDirectoryName)
+
+	var files []os.DirEntry
+
+	switch i, err := os.ReadDir(d); {
+	case os.IsNotExist(err):
+		return nil
+	case err != nil:
+		return errors.WithStack(err)
+	default:
+		files = i
+	}
+
+	for i := range files {
+		if files[i].IsDir() {
+			continue
+		}
+
+		switch from, to, err := isaac.BlockArchiveRangeFromFileName(files[i].Name()); {
+		case err != nil:
+			continue
+		case to < height:
+			continue
+		case from < height:
+			if err := truncateBlockArchive(root, filepath.Join(d, files[i].Name()), height); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(d, files[i].Name())); err != nil {
+			return errors.WithMessagef(err, "remove archive, %q", files[i].Name())
+		}
+	}
+
+	return nil
+}
+
 func isCompressedBlockItemType(t base.BlockItemType) bool {
 	switch t {
 	case base.BlockItemProposal,
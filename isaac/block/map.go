@@ -5,6 +5,7 @@ import (
 	"sort"
 
 	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
 	"github.com/ProtoconNet/mitum2/util"
 	"github.com/ProtoconNet/mitum2/util/hint"
 	"github.com/pkg/errors"
@@ -28,18 +29,48 @@ func NewBlockMap() BlockMap {
 
 func (m BlockMap) IsValid(b []byte) error {
 	e := util.ErrInvalid.Errorf("invalid blockmap")
-	if err := m.BaseHinter.IsValid(BlockMapHint.Type().Bytes()); err != nil {
+
+	if err := m.isValid(); err != nil {
 		return e.Wrap(err)
 	}
 
-	if err := util.CheckIsValiders(nil, false, m.manifest, m.BaseNodeSign); err != nil {
+	if err := m.BaseNodeSign.Verify(b, m.SignedBytes()); err != nil {
 		return e.Wrap(err)
 	}
 
-	if err := m.checkItems(); err != nil {
+	return nil
+}
+
+// IsValidWithoutSign checks BlockMap without verifying the signature, but the
+// manifest hash is checked instead. It is only for the BlockMap, which is
+// trusted by the hash chain of checkpoint.
+func (m BlockMap) IsValidWithoutSign() error {
+	e := util.ErrInvalid.Errorf("invalid blockmap")
+
+	if err := m.isValid(); err != nil {
 		return e.Wrap(err)
 	}
 
+	if err := isaac.IsValidManifestHash(m.manifest); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
+
+func (m BlockMap) isValid() error {
+	if err := m.BaseHinter.IsValid(BlockMapHint.Type().Bytes()); err != nil {
+		return err
+	}
+
+	if err := util.CheckIsValiders(nil, false, m.manifest, m.BaseNodeSign); err != nil {
+		return err
+	}
+
+	if err := m.checkItems(); err != nil {
+		return err
+	}
+
 	var vs []util.IsValider
 
 	m.items.Traverse(func(_ base.BlockItemType, v base.BlockMapItem) bool {
@@ -51,11 +82,7 @@ func (m BlockMap) IsValid(b []byte) error {
 	})
 
 	if err := util.CheckIsValiderSlice(nil, true, vs); err != nil {
-		return e.WithMessage(err, "invalid item found")
-	}
-
-	if err := m.BaseNodeSign.Verify(b, m.signedBytes()); err != nil {
-		return e.Wrap(err)
+		return errors.WithMessage(err, "invalid item found")
 	}
 
 	return nil
@@ -101,7 +128,8 @@ func (m BlockMap) Items(f func(base.BlockMapItem) bool) {
 }
 
 func (m *BlockMap) Sign(node base.Address, priv base.Privatekey, networkID base.NetworkID) error {
-	sign, err := base.NewBaseNodeSignFromBytes(node, priv, networkID, m.signedBytes())
+	sign, err := base.NewBaseNodeSignFromBytes(
+		node, base.PrivatekeyWithSignKind(priv, base.BlockMapSignKind, *m), networkID, m.SignedBytes())
 	if err != nil {
 		return errors.Wrap(err, "sign blockmap")
 	}
@@ -148,7 +176,9 @@ func (BlockMap) Bytes() []byte {
 	return nil
 }
 
-func (m BlockMap) signedBytes() []byte {
+// SignedBytes returns the bytes of manifest hash and item checksums, which are
+// signed by node.
+func (m BlockMap) SignedBytes() []byte {
 	var ts [][]byte
 
 	m.items.Traverse(func(_ base.BlockItemType, v base.BlockMapItem) bool {
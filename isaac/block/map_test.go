@@ -4,10 +4,12 @@ import (
 	"testing"
 
 	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
 	"github.com/ProtoconNet/mitum2/util"
 	"github.com/ProtoconNet/mitum2/util/encoder"
 	jsonenc "github.com/ProtoconNet/mitum2/util/encoder/json"
 	"github.com/ProtoconNet/mitum2/util/hint"
+	"github.com/ProtoconNet/mitum2/util/localtime"
 	"github.com/ProtoconNet/mitum2/util/valuehash"
 	"github.com/stretchr/testify/suite"
 )
@@ -189,6 +191,80 @@ func (t *testBlockMap) TestVerify() {
 
 		t.NoError(m.IsValid(t.networkID))
 	})
+
+	t.Run("ed25519", func() {
+		m := t.newmap()
+
+		priv := base.NewEd25519Privatekey()
+		t.NoError(m.Sign(t.local, priv, t.networkID))
+		t.NoError(m.IsValid(t.networkID))
+		t.True(priv.Publickey().Equal(m.Signer()))
+
+		enc := jsonenc.NewEncoder()
+		t.NoError(enc.Add(encoder.DecodeDetail{Hint: base.MPublickeyHint, Instance: &base.MPublickey{}}))
+		t.NoError(enc.Add(encoder.DecodeDetail{Hint: base.Ed25519PublickeyHint, Instance: &base.Ed25519Publickey{}}))
+		t.NoError(enc.Add(encoder.DecodeDetail{Hint: base.StringAddressHint, Instance: base.StringAddress{}}))
+		t.NoError(enc.Add(encoder.DecodeDetail{Hint: base.DummyManifestHint, Instance: base.DummyManifest{}}))
+		t.NoError(enc.Add(encoder.DecodeDetail{Hint: BlockMapHint, Instance: BlockMap{}}))
+
+		b, err := enc.Marshal(m)
+		t.NoError(err)
+
+		i, err := enc.Decode(b)
+		t.NoError(err)
+
+		um, ok := i.(BlockMap)
+		t.True(ok)
+		t.NoError(um.IsValid(t.networkID))
+
+		base.EqualBlockMap(t.Assert(), m, um)
+	})
+}
+
+func (t *testBlockMap) TestIsValidWithoutSign() {
+	newmap := func() BlockMap {
+		m := t.newmap()
+		m.SetManifest(isaac.NewManifest(
+			base.Height(33),
+			valuehash.RandomSHA256(),
+			valuehash.RandomSHA256(),
+			valuehash.RandomSHA256(),
+			valuehash.RandomSHA256(),
+			valuehash.RandomSHA256(),
+			localtime.Now(),
+		))
+
+		t.NoError(m.Sign(t.local, t.priv, t.networkID))
+
+		return m
+	}
+
+	t.Run("ok", func() {
+		m := newmap()
+		t.NoError(m.IsValidWithoutSign())
+	})
+
+	t.Run("wrong signature", func() {
+		m := newmap()
+		t.NoError(m.Sign(t.local, t.priv, util.UUID().Bytes()))
+
+		t.Error(m.IsValid(t.networkID))
+		t.NoError(m.IsValidWithoutSign())
+	})
+
+	t.Run("wrong manifest hash", func() {
+		m := newmap()
+
+		manifest := base.NewDummyManifest(base.Height(33), valuehash.RandomSHA256())
+		m.SetManifest(manifest)
+		t.NoError(m.Sign(t.local, t.priv, t.networkID))
+		t.NoError(m.IsValid(t.networkID))
+
+		err := m.IsValidWithoutSign()
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "manifest hash does not match")
+	})
 }
 
 type testBlockMapEncode struct {
@@ -400,6 +400,15 @@ func IsValidOperationsOfBlock( //nolint:dupl //...
 	}
 
 	if len(ops) > 0 {
+		sfs := make([]base.SignFact, len(ops))
+		for i := range ops {
+			sfs[i] = ops[i]
+		}
+
+		if err := base.BatchVerifySignFacts(networkID, sfs); err != nil {
+			return e.Wrap(err)
+		}
+
 		if err := util.BatchWork(context.Background(), int64(len(ops)), 333, //nolint:gomnd //...
 			func(context.Context, uint64) error { return nil },
 			func(_ context.Context, i, _ uint64) error {
@@ -9,6 +9,7 @@ import (
 	"github.com/ProtoconNet/mitum2/util"
 	"github.com/ProtoconNet/mitum2/util/fixedtree"
 	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/ProtoconNet/mitum2/util/tracing"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 )
@@ -359,7 +360,13 @@ func (w *Writer) Save(ctx context.Context) (base.BlockMap, error) {
 		}
 	}
 
-	if err := w.mergeDatabase(w.db); err != nil {
+	_, span := tracing.Start(ctx, "merge-block-write-database", tracing.WithAttribute("height", m.Manifest().Height()))
+
+	err := w.mergeDatabase(w.db)
+
+	span.End(err)
+
+	if err != nil {
 		return nil, e.Wrap(err)
 	}
 
@@ -0,0 +1,390 @@
+package isaac
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/pkg/errors"
+)
+
+var (
+	BlockArchiveBlockItemScheme = "archive"
+	BlockArchiveDirectoryName   = "archive"
+	BlockArchiveFileExtension   = ".blockarchive"
+	blockArchiveMagic           = []byte("mitumbar")
+	rBlockArchiveFile           = regexp.MustCompile(`^(\d+)-(\d+)\.blockarchive$`)
+)
+
+const blockArchiveTrailerSize = 24 // NOTE index offset + index length + magic
+
+// BlockArchive is the single file, which packs the local block item files of
+// the range of heights. The item files are appended as they are(without
+// decompressing) and the index is written at the end of file. The last 24
+// bytes are the trailer; the offset and length of index and the magic bytes.
+//
+//	| item file | item file | ... | index(json) | offset(8) | length(8) | magic(8) |
+//
+// The item in archive is referenced by the "archive" scheme uri with the
+// original item file name, like `archive:///operations.ndjson.gz`; the archive
+// file is found by height.
+type BlockArchive struct {
+	items map[string]BlockArchiveIndexItem
+	path  string
+	index BlockArchiveIndex
+}
+
+type BlockArchiveIndex struct {
+	Items []BlockArchiveIndexItem `json:"items"`
+	From  base.Height             `json:"from"`
+	To    base.Height             `json:"to"`
+}
+
+type BlockArchiveIndexItem struct {
+	Name     string      `json:"name"`
+	Checksum string      `json:"checksum"`
+	Height   base.Height `json:"height"`
+	Offset   int64       `json:"offset"`
+	Length   int64       `json:"length"`
+}
+
+func OpenBlockArchive(f string) (*BlockArchive, error) {
+	e := util.StringError("open block archive")
+
+	i, err := os.Open(filepath.Clean(f))
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	defer func() {
+		_ = i.Close()
+	}()
+
+	index, err := readBlockArchiveIndex(i)
+	if err != nil {
+		return nil, e.WithMessage(err, "%q", f)
+	}
+
+	items := make(map[string]BlockArchiveIndexItem, len(index.Items))
+
+	for j := range index.Items {
+		item := index.Items[j]
+
+		items[blockArchiveItemKey(item.Height, item.Name)] = item
+	}
+
+	return &BlockArchive{path: f, index: index, items: items}, nil
+}
+
+func (a *BlockArchive) Path() string {
+	return a.path
+}
+
+func (a *BlockArchive) From() base.Height {
+	return a.index.From
+}
+
+func (a *BlockArchive) To() base.Height {
+	return a.index.To
+}
+
+func (a *BlockArchive) Index() BlockArchiveIndex {
+	return a.index
+}
+
+func (a *BlockArchive) Item(height base.Height, name string) (BlockArchiveIndexItem, bool) {
+	i, found := a.items[blockArchiveItemKey(height, name)]
+
+	return i, found
+}
+
+// Open returns the reader of item; the reader only reads the range of item
+// in archive file.
+func (a *BlockArchive) Open(height base.Height, name string) (*BlockArchiveItemReader, bool, error) {
+	item, found := a.Item(height, name)
+	if !found {
+		return nil, false, nil
+	}
+
+	switch f, err := os.Open(filepath.Clean(a.path)); {
+	case os.IsNotExist(err):
+		return nil, false, nil
+	case err != nil:
+		return nil, false, errors.WithStack(err)
+	default:
+		return &BlockArchiveItemReader{
+			SectionReader: io.NewSectionReader(f, item.Offset, item.Length),
+			f:             f,
+		}, true, nil
+	}
+}
+
+type BlockArchiveItemReader struct {
+	*io.SectionReader
+	f *os.File
+}
+
+func (r *BlockArchiveItemReader) Close() error {
+	return errors.WithStack(r.f.Close())
+}
+
+// BlockArchiveWriter writes new block archive. The archive is written in
+// temporary file and it is moved to the archive directory by Save.
+type BlockArchiveWriter struct {
+	f      *os.File
+	root   string
+	index  BlockArchiveIndex
+	offset int64
+	sync.Mutex
+}
+
+func NewBlockArchiveWriter(root string, from, to base.Height) (*BlockArchiveWriter, error) {
+	e := util.StringError("create BlockArchiveWriter")
+
+	switch {
+	case from < base.GenesisHeight:
+		return nil, e.Errorf("wrong from height, %d", from)
+	case to < from:
+		return nil, e.Errorf("wrong to height, %d < %d", to, from)
+	}
+
+	d := filepath.Join(root, BlockArchiveDirectoryName)
+
+	if err := os.MkdirAll(d, 0o700); err != nil {
+		return nil, e.WithMessage(err, "create archive directory")
+	}
+
+	f, err := os.CreateTemp(d, "temp-")
+	if err != nil {
+		return nil, e.WithMessage(err, "create temp archive file")
+	}
+
+	return &BlockArchiveWriter{
+		f:     f,
+		root:  root,
+		index: BlockArchiveIndex{From: from, To: to},
+	}, nil
+}
+
+func (w *BlockArchiveWriter) Add(height base.Height, name string, r io.Reader) error {
+	w.Lock()
+	defer w.Unlock()
+
+	e := util.StringError("add item to block archive")
+
+	switch {
+	case w.f == nil:
+		return e.Errorf("already closed")
+	case height < w.index.From || height > w.index.To:
+		return e.Errorf("height, %d out of range, %d-%d", height, w.index.From, w.index.To)
+	case len(name) < 1:
+		return e.Errorf("empty name")
+	}
+
+	h := sha256.New()
+
+	n, err := io.Copy(io.MultiWriter(w.f, h), r)
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	w.index.Items = append(w.index.Items, BlockArchiveIndexItem{
+		Height:   height,
+		Name:     name,
+		Offset:   w.offset,
+		Length:   n,
+		Checksum: fmt.Sprintf("%x", h.Sum(nil)),
+	})
+
+	w.offset += n
+
+	return nil
+}
+
+// Save writes index and moves the archive file to the archive directory.
+func (w *BlockArchiveWriter) Save() (*BlockArchive, error) {
+	w.Lock()
+	defer w.Unlock()
+
+	e := util.StringError("save block archive")
+
+	if w.f == nil {
+		return nil, e.Errorf("already closed")
+	}
+
+	defer func() {
+		_ = w.f.Close()
+		_ = os.Remove(w.f.Name())
+
+		w.f = nil
+	}()
+
+	b, err := util.MarshalJSON(w.index)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	trailer := make([]byte, blockArchiveTrailerSize)
+	binary.BigEndian.PutUint64(trailer[:8], uint64(w.offset))
+	binary.BigEndian.PutUint64(trailer[8:16], uint64(len(b)))
+	copy(trailer[16:], blockArchiveMagic)
+
+	if _, err := w.f.Write(util.ConcatBytesSlice(b, trailer)); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	if err := w.f.Sync(); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	p := BlockArchivePath(w.root, w.index.From, w.index.To)
+
+	if err := os.Rename(w.f.Name(), p); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	return OpenBlockArchive(p)
+}
+
+func (w *BlockArchiveWriter) Cancel() error {
+	w.Lock()
+	defer w.Unlock()
+
+	if w.f == nil {
+		return nil
+	}
+
+	_ = w.f.Close()
+
+	defer func() {
+		w.f = nil
+	}()
+
+	if err := os.Remove(w.f.Name()); err != nil && !os.IsNotExist(err) {
+		return errors.WithMessage(err, "cancel block archive")
+	}
+
+	return nil
+}
+
+func BlockArchivePath(root string, from, to base.Height) string {
+	return filepath.Join(
+		root,
+		BlockArchiveDirectoryName,
+		fmt.Sprintf("%021d-%021d%s", from, to, BlockArchiveFileExtension),
+	)
+}
+
+// LoadBlockArchives loads the archives in root; the archives are sorted by
+// height.
+func LoadBlockArchives(root string) ([]*BlockArchive, error) {
+	var files []os.DirEntry
+
+	switch i, err := os.ReadDir(filepath.Join(root, BlockArchiveDirectoryName)); {
+	case os.IsNotExist(err):
+		return nil, nil
+	case err != nil:
+		return nil, errors.WithStack(err)
+	default:
+		files = util.FilterSlice(i, func(i os.DirEntry) bool {
+			return !i.IsDir() && rBlockArchiveFile.MatchString(i.Name())
+		})
+	}
+
+	archives := make([]*BlockArchive, len(files))
+
+	for i := range files {
+		a, err := OpenBlockArchive(filepath.Join(root, BlockArchiveDirectoryName, files[i].Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		archives[i] = a
+	}
+
+	sort.Slice(archives, func(i, j int) bool {
+		return archives[i].From() < archives[j].From()
+	})
+
+	return archives, nil
+}
+
+// BlockArchiveRangeFromFileName parses the height range from archive file name.
+func BlockArchiveRangeFromFileName(s string) (from, to base.Height, _ error) {
+	m := rBlockArchiveFile.FindStringSubmatch(filepath.Base(s))
+	if len(m) < 3 { //nolint:gomnd //...
+		return from, to, errors.Errorf("wrong block archive file name, %q", s)
+	}
+
+	for i, h := range []*base.Height{&from, &to} {
+		j, err := strconv.ParseInt(m[i+1], 10, 64)
+		if err != nil {
+			return from, to, errors.WithStack(err)
+		}
+
+		*h = base.Height(j)
+	}
+
+	return from, to, nil
+}
+
+func NewBlockArchiveBlockItemFile(name, compressFormat string) BlockItemFile {
+	return NewBlockItemFile(url.URL{Scheme: BlockArchiveBlockItemScheme, Path: "/" + name}, compressFormat)
+}
+
+func readBlockArchiveIndex(f *os.File) (index BlockArchiveIndex, _ error) {
+	var size int64
+
+	switch fi, err := f.Stat(); {
+	case err != nil:
+		return index, errors.WithStack(err)
+	case fi.Size() < blockArchiveTrailerSize:
+		return index, errors.Errorf("too short")
+	default:
+		size = fi.Size()
+	}
+
+	trailer := make([]byte, blockArchiveTrailerSize)
+
+	if _, err := f.ReadAt(trailer, size-blockArchiveTrailerSize); err != nil {
+		return index, errors.WithStack(err)
+	}
+
+	if !bytes.Equal(trailer[16:], blockArchiveMagic) {
+		return index, errors.Errorf("unknown magic")
+	}
+
+	offset := int64(binary.BigEndian.Uint64(trailer[:8]))   //nolint:gosec //...
+	length := int64(binary.BigEndian.Uint64(trailer[8:16])) //nolint:gosec //...
+
+	if offset < 0 || length < 1 || offset+length != size-blockArchiveTrailerSize {
+		return index, errors.Errorf("wrong index position")
+	}
+
+	b := make([]byte, length)
+
+	if _, err := f.ReadAt(b, offset); err != nil {
+		return index, errors.WithStack(err)
+	}
+
+	if err := util.UnmarshalJSON(b, &index); err != nil {
+		return index, errors.WithMessage(err, "index")
+	}
+
+	return index, nil
+}
+
+func blockArchiveItemKey(height base.Height, name string) string {
+	return height.String() + "/" + name
+}
@@ -0,0 +1,202 @@
+package isaac
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/stretchr/testify/suite"
+)
+
+type testBlockArchive struct {
+	suite.Suite
+	root string
+}
+
+func (t *testBlockArchive) SetupTest() {
+	t.root = t.T().TempDir()
+}
+
+func (t *testBlockArchive) write(from, to base.Height, items map[base.Height]map[string][]byte) *BlockArchive {
+	w, err := NewBlockArchiveWriter(t.root, from, to)
+	t.NoError(err)
+
+	for height := from; height <= to; height++ {
+		for name, b := range items[height] {
+			t.NoError(w.Add(height, name, bytes.NewReader(b)))
+		}
+	}
+
+	a, err := w.Save()
+	t.NoError(err)
+
+	return a
+}
+
+func (t *testBlockArchive) TestNew() {
+	items := map[base.Height]map[string][]byte{}
+
+	for height := base.Height(3); height <= 5; height++ {
+		items[height] = map[string][]byte{
+			"map.json":             util.UUID().Bytes(),
+			"operations.ndjson.gz": bytes.Repeat(util.UUID().Bytes(), int(height)),
+		}
+	}
+
+	a := t.write(3, 5, items)
+
+	t.Equal(BlockArchivePath(t.root, 3, 5), a.Path())
+	t.Equal(base.Height(3), a.From())
+	t.Equal(base.Height(5), a.To())
+	t.Equal(6, len(a.Index().Items))
+
+	// NOTE no temp file left
+	files, err := os.ReadDir(filepath.Join(t.root, BlockArchiveDirectoryName))
+	t.NoError(err)
+	t.Equal(1, len(files))
+
+	t.Run("read items", func() {
+		ra, err := OpenBlockArchive(a.Path())
+		t.NoError(err)
+
+		for height := range items {
+			for name, b := range items[height] {
+				r, found, err := ra.Open(height, name)
+				t.NoError(err)
+				t.True(found)
+
+				rb, err := io.ReadAll(r)
+				t.NoError(err)
+				t.NoError(r.Close())
+
+				t.Equal(b, rb, "height=%d name=%q", height, name)
+			}
+		}
+	})
+
+	t.Run("unknown item", func() {
+		_, found, err := a.Open(4, "states.ndjson.gz")
+		t.NoError(err)
+		t.False(found)
+
+		_, found, err = a.Open(6, "map.json")
+		t.NoError(err)
+		t.False(found)
+	})
+
+	t.Run("seek", func() {
+		b := items[4]["operations.ndjson.gz"]
+
+		r, found, err := a.Open(4, "operations.ndjson.gz")
+		t.NoError(err)
+		t.True(found)
+		defer r.Close()
+
+		_, err = r.Seek(3, io.SeekStart)
+		t.NoError(err)
+
+		rb, err := io.ReadAll(r)
+		t.NoError(err)
+		t.Equal(b[3:], rb)
+	})
+}
+
+func (t *testBlockArchive) TestAddOutOfRange() {
+	w, err := NewBlockArchiveWriter(t.root, 3, 5)
+	t.NoError(err)
+	defer w.Cancel()
+
+	err = w.Add(6, "map.json", bytes.NewReader(util.UUID().Bytes()))
+	t.Error(err)
+	t.ErrorContains(err, "out of range")
+}
+
+func (t *testBlockArchive) TestCancel() {
+	w, err := NewBlockArchiveWriter(t.root, 3, 5)
+	t.NoError(err)
+
+	t.NoError(w.Add(3, "map.json", bytes.NewReader(util.UUID().Bytes())))
+	t.NoError(w.Cancel())
+
+	files, err := os.ReadDir(filepath.Join(t.root, BlockArchiveDirectoryName))
+	t.NoError(err)
+	t.Equal(0, len(files))
+
+	_, err = w.Save()
+	t.Error(err)
+	t.ErrorContains(err, "already closed")
+}
+
+func (t *testBlockArchive) TestBrokenArchive() {
+	a := t.write(3, 5, map[base.Height]map[string][]byte{
+		3: {"map.json": util.UUID().Bytes()},
+	})
+
+	b, err := os.ReadFile(a.Path())
+	t.NoError(err)
+
+	t.Run("wrong magic", func() {
+		f := filepath.Join(t.root, "wrong-magic")
+		wb := make([]byte, len(b))
+		copy(wb, b)
+		wb[len(wb)-1] = 'x'
+
+		t.NoError(os.WriteFile(f, wb, 0o600))
+
+		_, err := OpenBlockArchive(f)
+		t.Error(err)
+		t.ErrorContains(err, "unknown magic")
+	})
+
+	t.Run("too short", func() {
+		f := filepath.Join(t.root, "too-short")
+		t.NoError(os.WriteFile(f, b[:3], 0o600))
+
+		_, err := OpenBlockArchive(f)
+		t.Error(err)
+		t.ErrorContains(err, "too short")
+	})
+
+	t.Run("truncated", func() {
+		f := filepath.Join(t.root, "truncated")
+		t.NoError(os.WriteFile(f, b[1:], 0o600))
+
+		_, err := OpenBlockArchive(f)
+		t.Error(err)
+		t.ErrorContains(err, "wrong index position")
+	})
+}
+
+func (t *testBlockArchive) TestLoadBlockArchives() {
+	t.Run("empty", func() {
+		archives, err := LoadBlockArchives(t.root)
+		t.NoError(err)
+		t.Empty(archives)
+	})
+
+	_ = t.write(6, 8, nil)
+	_ = t.write(0, 2, nil)
+	_ = t.write(3, 5, nil)
+
+	archives, err := LoadBlockArchives(t.root)
+	t.NoError(err)
+	t.Equal(3, len(archives))
+
+	for i := range archives {
+		t.Equal(base.Height(i*3), archives[i].From())
+		t.Equal(base.Height(i*3+2), archives[i].To())
+
+		from, to, err := BlockArchiveRangeFromFileName(archives[i].Path())
+		t.NoError(err)
+		t.Equal(archives[i].From(), from)
+		t.Equal(archives[i].To(), to)
+	}
+}
+
+func TestBlockArchive(t *testing.T) {
+	suite.Run(t, new(testBlockArchive))
+}
@@ -0,0 +1,208 @@
+package isaac
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/pkg/errors"
+)
+
+var BlockMirrorLastHeightFileName = "last_height"
+
+// BlockMirror reads the blocks from the static block mirror. The mirror has the
+// same layout with the root of LocalFSWriter and the last height file, which
+// has the last height as decimal string. The mirror can be served by the plain
+// http(s) server or local directory with `file://`. The local item files of
+// mirror are read under the height directory of mirror.
+type BlockMirror struct {
+	readf   RemoteBlockItemReadFunc
+	httpf   RemoteBlockItemReadFunc
+	jsonenc encoder.Encoder
+	u       url.URL
+}
+
+func NewBlockMirror(u *url.URL, jsonenc encoder.Encoder) (*BlockMirror, error) {
+	if err := IsValidBlockMirrorURL(u); err != nil {
+		return nil, err
+	}
+
+	httpf := HTTPBlockItemReadFunc()
+
+	readf := httpf
+	if u.Scheme == "file" {
+		readf = fileBlockItemReadFunc
+	}
+
+	return &BlockMirror{u: *u, readf: readf, httpf: httpf, jsonenc: jsonenc}, nil
+}
+
+func (m *BlockMirror) URL() url.URL {
+	return m.u
+}
+
+func (m *BlockMirror) LastHeight(ctx context.Context) (height base.Height, found bool, _ error) {
+	e := util.StringError("last height of block mirror")
+
+	switch i, err := m.readf(ctx, *m.u.JoinPath(BlockMirrorLastHeightFileName), func(r io.Reader) error {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		h, err := base.ParseHeightString(string(bytes.TrimSpace(b)))
+		if err != nil {
+			return err
+		}
+
+		height = h
+
+		return nil
+	}); {
+	case err != nil:
+		return height, false, e.Wrap(err)
+	default:
+		return height, i, nil
+	}
+}
+
+func (m *BlockMirror) ItemFiles(ctx context.Context, height base.Height) (
+	bfiles base.BlockItemFiles, found bool, _ error,
+) {
+	e := util.StringError("item files of block mirror")
+
+	switch i, err := m.readf(ctx, *m.u.JoinPath(BlockItemFilesPath("", height)), func(r io.Reader) error {
+		return encoder.DecodeReader(m.jsonenc, r, &bfiles)
+	}); {
+	case err != nil:
+		return nil, false, e.Wrap(err)
+	case !i:
+		return nil, false, nil
+	case bfiles == nil:
+		return nil, false, e.Errorf("empty block item files")
+	default:
+		return bfiles, true, nil
+	}
+}
+
+// Item reads the raw item file of mirror; the reader is not decompressed. The
+// remote item file of http(s) is read from it's location.
+func (m *BlockMirror) Item(
+	ctx context.Context,
+	height base.Height,
+	t base.BlockItemType,
+	f func(_ io.Reader, compressFormat string) error,
+) (bool, error) {
+	e := util.StringError("item of block mirror")
+
+	var item base.BlockItemFile
+
+	switch bfiles, found, err := m.ItemFiles(ctx, height); {
+	case err != nil:
+		return false, e.Wrap(err)
+	case !found:
+		return false, nil
+	default:
+		i, found := bfiles.Item(t)
+		if !found {
+			return false, nil
+		}
+
+		item = i
+	}
+
+	var readf RemoteBlockItemReadFunc
+	var uri url.URL
+
+	switch i := item.URI(); i.Scheme {
+	case LocalFSBlockItemScheme:
+		readf = m.readf
+		uri = *m.u.JoinPath(BlockHeightDirectory(height), strings.TrimPrefix(i.Path, "/"))
+	case "http", "https":
+		readf = m.httpf
+		uri = i
+	default:
+		return false, e.Errorf("unsupported item file, %q", (&i).String())
+	}
+
+	found, err := readf(ctx, uri, func(r io.Reader) error {
+		return f(r, item.CompressFormat())
+	})
+	if err != nil {
+		return found, e.Wrap(err)
+	}
+
+	return found, nil
+}
+
+func IsValidBlockMirrorURL(u *url.URL) error {
+	e := util.ErrInvalid.Errorf("invalid block mirror url")
+
+	switch {
+	case u == nil:
+		return e.Errorf("empty url")
+	case u.Scheme == "file":
+		if len(u.Path) < 1 {
+			return e.Errorf("missing path")
+		}
+	case u.Scheme == "http", u.Scheme == "https":
+		if len(u.Host) < 1 {
+			return e.Errorf("missing host")
+		}
+	default:
+		return e.Errorf("unsupported scheme, %q", u.Scheme)
+	}
+
+	return nil
+}
+
+// WriteBlockMirrorLastHeight updates the last height file of block mirror.
+func WriteBlockMirrorLastHeight(root string, height base.Height) error {
+	e := util.StringError("write last height of block mirror")
+
+	f, err := os.CreateTemp(root, "temp-")
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+
+	if _, err := f.WriteString(height.String()); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := f.Sync(); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := os.Rename(f.Name(), filepath.Join(root, BlockMirrorLastHeightFileName)); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
+
+func fileBlockItemReadFunc(_ context.Context, uri url.URL, callback func(io.Reader) error) (bool, error) {
+	switch f, err := os.Open(filepath.Clean(filepath.FromSlash(uri.Path))); {
+	case os.IsNotExist(err):
+		return false, nil
+	case err != nil:
+		return false, errors.WithStack(err)
+	default:
+		defer func() {
+			_ = f.Close()
+		}()
+
+		return true, callback(f)
+	}
+}
@@ -0,0 +1,128 @@
+package isaac
+
+import (
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/hint"
+	"github.com/pkg/errors"
+)
+
+var CheckpointHint = hint.MustNewHint("checkpoint-v0.0.1")
+
+// Checkpoint is the trusted block; the blocks under checkpoint can be imported
+// after checking the hash chain only. Checkpoint is signed by the suffrage
+// nodes; the checkpoint without signs can be set by the node design.
+type Checkpoint struct {
+	manifest util.Hash
+	signs    []base.NodeSign
+	hint.BaseHinter
+	height base.Height
+}
+
+func NewCheckpoint(height base.Height, manifest util.Hash) Checkpoint {
+	return Checkpoint{
+		BaseHinter: hint.NewBaseHinter(CheckpointHint),
+		height:     height,
+		manifest:   manifest,
+	}
+}
+
+func (c Checkpoint) IsValid(networkID []byte) error {
+	e := util.ErrInvalid.Errorf("invalid Checkpoint")
+
+	if err := c.BaseHinter.IsValid(CheckpointHint.Type().Bytes()); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := util.CheckIsValiders(nil, false, c.height, c.manifest); err != nil {
+		return e.Wrap(err)
+	}
+
+	if c.height <= base.GenesisHeight {
+		return e.Errorf("genesis checkpoint")
+	}
+
+	if len(c.signs) < 1 {
+		return nil
+	}
+
+	if err := util.CheckIsValiderSlice(nil, false, c.signs); err != nil {
+		return e.Wrap(err)
+	}
+
+	if util.IsDuplicatedSlice(c.signs, func(i base.NodeSign) (bool, string) {
+		if i == nil {
+			return true, ""
+		}
+
+		return true, i.Node().String()
+	}) {
+		return e.Errorf("duplicated node found in signs")
+	}
+
+	for i := range c.signs {
+		if err := c.signs[i].Verify(networkID, c.signedBytes()); err != nil {
+			return e.WithMessage(err, "sign of %q", c.signs[i].Node())
+		}
+	}
+
+	return nil
+}
+
+func (c Checkpoint) Height() base.Height {
+	return c.height
+}
+
+func (c Checkpoint) Manifest() util.Hash {
+	return c.manifest
+}
+
+func (c Checkpoint) Signs() []base.NodeSign {
+	return c.signs
+}
+
+// Sign adds the sign of node; the previous sign of same node is replaced.
+func (c *Checkpoint) Sign(node base.Address, priv base.Privatekey, networkID base.NetworkID) error {
+	sign, err := base.NewBaseNodeSignFromBytes(node, priv, networkID, c.signedBytes())
+	if err != nil {
+		return errors.WithMessage(err, "sign checkpoint")
+	}
+
+	for i := range c.signs {
+		if c.signs[i].Node().Equal(node) {
+			c.signs[i] = sign
+
+			return nil
+		}
+	}
+
+	c.signs = append(c.signs, sign)
+
+	return nil
+}
+
+// IsSignedBySuffrage checks the signs of suffrage nodes are over threshold.
+func (c Checkpoint) IsSignedBySuffrage(suf base.Suffrage, threshold base.Threshold) error {
+	if len(c.signs) < 1 {
+		return errors.Errorf("empty signs")
+	}
+
+	return base.CheckFactSignsBySuffrage(suf, threshold, c.signs)
+}
+
+// IsValidBlockMap checks the BlockMap of checkpoint height matches with
+// checkpoint.
+func (c Checkpoint) IsValidBlockMap(m base.BlockMap) error {
+	switch {
+	case m.Manifest().Height() != c.height:
+		return errors.Errorf("different height, %d != %d", m.Manifest().Height(), c.height)
+	case !m.Manifest().Hash().Equal(c.manifest):
+		return errors.Errorf("different manifest hash, %q != %q", m.Manifest().Hash(), c.manifest)
+	default:
+		return nil
+	}
+}
+
+func (c Checkpoint) signedBytes() []byte {
+	return util.ConcatByters(c.height, c.manifest)
+}
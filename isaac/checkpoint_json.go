@@ -0,0 +1,60 @@
+package isaac
+
+import (
+	"encoding/json"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/ProtoconNet/mitum2/util/hint"
+	"github.com/ProtoconNet/mitum2/util/valuehash"
+)
+
+type checkpointJSONMarshaler struct {
+	Manifest util.Hash       `json:"manifest"`
+	Signs    []base.NodeSign `json:"signs,omitempty"`
+	hint.BaseHinter
+	Height base.Height `json:"height"`
+}
+
+func (c Checkpoint) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(checkpointJSONMarshaler{
+		BaseHinter: c.BaseHinter,
+		Height:     c.height,
+		Manifest:   c.manifest,
+		Signs:      c.signs,
+	})
+}
+
+type checkpointJSONUnmarshaler struct {
+	Manifest valuehash.HashDecoder `json:"manifest"`
+	Signs    []json.RawMessage     `json:"signs"`
+	Height   base.HeightDecoder    `json:"height"`
+}
+
+func (c *Checkpoint) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	e := util.StringError("decode Checkpoint")
+
+	var u checkpointJSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	c.height = u.Height.Height()
+	c.manifest = u.Manifest.Hash()
+
+	if len(u.Signs) > 0 {
+		c.signs = make([]base.NodeSign, len(u.Signs))
+
+		for i := range u.Signs {
+			var ub base.BaseNodeSign
+			if err := ub.DecodeJSON(u.Signs[i], enc); err != nil {
+				return e.WithMessage(err, "decode sign")
+			}
+
+			c.signs[i] = ub
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,215 @@
+package isaac
+
+import (
+	"testing"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	jsonenc "github.com/ProtoconNet/mitum2/util/encoder/json"
+	"github.com/ProtoconNet/mitum2/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testCheckpoint struct {
+	suite.Suite
+	networkID base.NetworkID
+}
+
+func (t *testCheckpoint) SetupSuite() {
+	t.networkID = base.RandomNetworkID()
+}
+
+func (t *testCheckpoint) newSigned(nodes []base.LocalNode) Checkpoint {
+	c := NewCheckpoint(base.Height(33), valuehash.RandomSHA256())
+
+	for i := range nodes {
+		t.NoError(c.Sign(nodes[i].Address(), nodes[i].Privatekey(), t.networkID))
+	}
+
+	return c
+}
+
+func (t *testCheckpoint) TestNew() {
+	t.Run("without signs", func() {
+		c := NewCheckpoint(base.Height(33), valuehash.RandomSHA256())
+		t.NoError(c.IsValid(t.networkID))
+		t.Empty(c.Signs())
+	})
+
+	t.Run("signed", func() {
+		c := t.newSigned([]base.LocalNode{base.RandomLocalNode(), base.RandomLocalNode()})
+		t.NoError(c.IsValid(t.networkID))
+		t.Equal(2, len(c.Signs()))
+	})
+
+	t.Run("sign again", func() {
+		local := base.RandomLocalNode()
+
+		c := t.newSigned([]base.LocalNode{local})
+		t.NoError(c.Sign(local.Address(), local.Privatekey(), t.networkID))
+		t.NoError(c.IsValid(t.networkID))
+		t.Equal(1, len(c.Signs()))
+	})
+}
+
+func (t *testCheckpoint) TestIsValid() {
+	t.Run("genesis", func() {
+		c := NewCheckpoint(base.GenesisHeight, valuehash.RandomSHA256())
+
+		err := c.IsValid(t.networkID)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "genesis checkpoint")
+	})
+
+	t.Run("empty manifest", func() {
+		c := NewCheckpoint(base.Height(33), nil)
+
+		err := c.IsValid(t.networkID)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+	})
+
+	t.Run("wrong network id", func() {
+		c := t.newSigned([]base.LocalNode{base.RandomLocalNode()})
+
+		err := c.IsValid(util.UUID().Bytes())
+		t.Error(err)
+		t.ErrorIs(err, base.ErrSignatureVerification)
+	})
+
+	t.Run("duplicated signs", func() {
+		c := t.newSigned([]base.LocalNode{base.RandomLocalNode()})
+		c.signs = append(c.signs, c.signs[0])
+
+		err := c.IsValid(t.networkID)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "duplicated node")
+	})
+}
+
+func (t *testCheckpoint) TestIsSignedBySuffrage() {
+	locals := []base.LocalNode{base.RandomLocalNode(), base.RandomLocalNode(), base.RandomLocalNode()}
+	nodes := make([]base.Node, len(locals))
+
+	for i := range locals {
+		nodes[i] = locals[i]
+	}
+
+	suf, err := NewSuffrage(nodes)
+	t.NoError(err)
+
+	t.Run("ok", func() {
+		c := t.newSigned(locals)
+		t.NoError(c.IsSignedBySuffrage(suf, base.DefaultThreshold))
+	})
+
+	t.Run("not enough signs", func() {
+		c := t.newSigned(locals[:1])
+
+		err := c.IsSignedBySuffrage(suf, base.DefaultThreshold)
+		t.Error(err)
+		t.ErrorContains(err, "not enough signs")
+	})
+
+	t.Run("unknown nodes", func() {
+		c := t.newSigned([]base.LocalNode{base.RandomLocalNode(), base.RandomLocalNode(), base.RandomLocalNode()})
+
+		err := c.IsSignedBySuffrage(suf, base.DefaultThreshold)
+		t.Error(err)
+		t.ErrorContains(err, "not enough signs")
+	})
+
+	t.Run("empty signs", func() {
+		c := NewCheckpoint(base.Height(33), valuehash.RandomSHA256())
+
+		err := c.IsSignedBySuffrage(suf, base.DefaultThreshold)
+		t.Error(err)
+		t.ErrorContains(err, "empty signs")
+	})
+}
+
+func (t *testCheckpoint) TestIsValidBlockMap() {
+	c := NewCheckpoint(base.Height(33), valuehash.RandomSHA256())
+
+	t.Run("ok", func() {
+		m := base.NewDummyBlockMap(base.NewDummyManifest(c.Height(), c.Manifest()))
+		t.NoError(c.IsValidBlockMap(m))
+	})
+
+	t.Run("different height", func() {
+		m := base.NewDummyBlockMap(base.NewDummyManifest(c.Height()+1, c.Manifest()))
+
+		err := c.IsValidBlockMap(m)
+		t.Error(err)
+		t.ErrorContains(err, "different height")
+	})
+
+	t.Run("different manifest", func() {
+		m := base.NewDummyBlockMap(base.NewDummyManifest(c.Height(), valuehash.RandomSHA256()))
+
+		err := c.IsValidBlockMap(m)
+		t.Error(err)
+		t.ErrorContains(err, "different manifest hash")
+	})
+}
+
+func TestCheckpoint(t *testing.T) {
+	suite.Run(t, new(testCheckpoint))
+}
+
+func TestCheckpointJSON(tt *testing.T) {
+	t := new(encoder.BaseTestEncode)
+
+	enc := jsonenc.NewEncoder()
+	networkID := base.RandomNetworkID()
+
+	t.Encode = func() (interface{}, []byte) {
+		t.NoError(enc.Add(encoder.DecodeDetail{Hint: CheckpointHint, Instance: Checkpoint{}}))
+		t.NoError(enc.Add(encoder.DecodeDetail{Hint: base.MPublickeyHint, Instance: &base.MPublickey{}}))
+		t.NoError(enc.Add(encoder.DecodeDetail{Hint: base.StringAddressHint, Instance: base.StringAddress{}}))
+
+		c := NewCheckpoint(base.Height(33), valuehash.RandomSHA256())
+
+		for range make([]struct{}, 2) {
+			local := base.RandomLocalNode()
+			t.NoError(c.Sign(local.Address(), local.Privatekey(), networkID))
+		}
+
+		b, err := util.MarshalJSON(c)
+		t.NoError(err)
+
+		t.T().Log("marshaled:", string(b))
+
+		return c, b
+	}
+
+	t.Decode = func(b []byte) interface{} {
+		i, err := enc.Decode(b)
+		t.NoError(err)
+
+		u, ok := i.(Checkpoint)
+		t.True(ok)
+
+		t.NoError(u.IsValid(networkID))
+
+		return u
+	}
+	t.Compare = func(a, b interface{}) {
+		av := a.(Checkpoint)
+		bv := b.(Checkpoint)
+
+		t.True(av.Hint().Equal(bv.Hint()))
+		t.Equal(av.Height(), bv.Height())
+		t.True(av.Manifest().Equal(bv.Manifest()))
+		t.Equal(len(av.Signs()), len(bv.Signs()))
+
+		for i := range av.Signs() {
+			base.EqualSign(t.Assert(), av.Signs()[i], bv.Signs()[i])
+		}
+	}
+
+	suite.Run(tt, t)
+}
@@ -598,6 +598,15 @@ func (db *Center) RemoveBlocks(height base.Height) (bool, error) {
 	return true, nil
 }
 
+// TempsLen returns the number of active temp databases, which are not yet
+// merged to permanent database.
+func (db *Center) TempsLen() int {
+	db.RLock()
+	defer db.RUnlock()
+
+	return len(db.temps)
+}
+
 func (db *Center) activeTemps() []isaac.TempDatabase {
 	db.RLock()
 	defer db.RUnlock()
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"math"
+	"sync/atomic"
 	"time"
 
 	"github.com/ProtoconNet/mitum2/base"
@@ -27,6 +28,7 @@ type TempPool struct {
 	cleanRemovedNewOperationsDeep     int
 	cleanRemovedProposalDeep          int
 	cleanRemovedBallotDeep            int
+	newOperationsLen                  atomic.Int64
 }
 
 func NewTempPool(
@@ -64,6 +66,13 @@ func newTempPool(
 
 	db.ContextDaemon = util.NewContextDaemon(db.startClean)
 
+	switch i, err := db.CountOperations(); {
+	case err != nil:
+		return nil, err
+	default:
+		db.newOperationsLen.Store(int64(i))
+	}
+
 	return db, nil
 }
 
@@ -401,6 +410,41 @@ func (db *TempPool) TraverseOperationsBytes(
 	)
 }
 
+// OperationsLen returns the number of new operations in pool; it is
+// maintained when new operations are added or removed, unlike
+// CountOperations.
+func (db *TempPool) OperationsLen() uint64 {
+	if i := db.newOperationsLen.Load(); i > 0 {
+		return uint64(i)
+	}
+
+	return 0
+}
+
+// CountOperations counts the new operations in pool by scanning storage.
+func (db *TempPool) CountOperations() (uint64, error) {
+	pst, err := db.st()
+	if err != nil {
+		return 0, err
+	}
+
+	var count uint64
+
+	if err := pst.Iter(
+		leveldbutil.BytesPrefix(leveldbKeyPrefixNewOperationOrdered[:]),
+		func([]byte, []byte) (bool, error) {
+			count++
+
+			return true, nil
+		},
+		true,
+	); err != nil {
+		return 0, errors.WithMessage(err, "count operations")
+	}
+
+	return count, nil
+}
+
 func (db *TempPool) SetOperation(_ context.Context, op base.Operation) (bool, error) {
 	e := util.StringError("put operation")
 
@@ -445,6 +489,8 @@ func (db *TempPool) SetOperation(_ context.Context, op base.Operation) (bool, er
 		return false, e.Wrap(err)
 	}
 
+	db.newOperationsLen.Add(1)
+
 	db.setOpCache(op)
 
 	return true, nil
@@ -627,7 +673,13 @@ func (db *TempPool) removeNewOperationOrdereds(keys [][]byte) error {
 		batch.Delete(keys[i])
 	}
 
-	return pst.Batch(batch, nil)
+	if err := pst.Batch(batch, nil); err != nil {
+		return err
+	}
+
+	db.newOperationsLen.Add(-int64(len(keys)))
+
+	return nil
 }
 
 func (db *TempPool) setRemoveNewOperations(ctx context.Context, height base.Height, operationhashes []util.Hash) error {
@@ -653,6 +705,8 @@ func (db *TempPool) setRemoveNewOperations(ctx context.Context, height base.Heig
 	batchch := make(chan func(bt *leveldbstorage.PrefixStorageBatch))
 	donech := make(chan struct{})
 
+	var removed int64
+
 	go func() {
 		for i := range batchch {
 			i(batch)
@@ -679,6 +733,8 @@ func (db *TempPool) setRemoveNewOperations(ctx context.Context, height base.Heig
 					bt.Delete(infokey)
 					bt.Delete(orderedkey)
 					bt.Put(leveldbRemovedNewOperationKey(height, h), h.Bytes())
+
+					removed++
 				}
 
 				return nil
@@ -701,7 +757,13 @@ func (db *TempPool) setRemoveNewOperations(ctx context.Context, height base.Heig
 		return nil
 	}
 
-	return pst.Batch(batch, nil)
+	if err := pst.Batch(batch, nil); err != nil {
+		return err
+	}
+
+	db.newOperationsLen.Add(-removed)
+
+	return nil
 }
 
 func (db *TempPool) LastVoteproofs() (base.INITVoteproof, base.ACCEPTVoteproof, bool, error) {
@@ -729,8 +729,18 @@ func (t *testNewOperationPool) TestRemoveNewOperations() {
 		t.True(added)
 	}
 
+	t.Equal(uint64(len(ops)), pst.OperationsLen())
+
 	t.NoError(pst.setRemoveNewOperations(context.Background(), base.Height(33), removes))
 
+	t.Run("operations len", func() {
+		t.Equal(uint64(len(ops)-len(removes)), pst.OperationsLen())
+
+		n, err := pst.CountOperations()
+		t.NoError(err)
+		t.Equal(n, pst.OperationsLen())
+	})
+
 	t.Run("all", func() {
 		rops, err := pst.OperationHashes(context.Background(), base.Height(33), 100, nil)
 		t.NoError(err)
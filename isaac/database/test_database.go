@@ -175,6 +175,8 @@ func (t *BaseTestDatabase) SetupSuite() {
 	t.noerror(t.Encs.AddHinter(base.DummyManifest{}))
 	t.noerror(t.Encs.AddHinter(base.DummyBlockMap{}))
 	t.noerror(t.Encs.AddDetail(encoder.DecodeDetail{Hint: base.MPublickeyHint, Instance: &base.MPublickey{}}))
+	t.noerror(t.Encs.AddDetail(encoder.DecodeDetail{Hint: base.Ed25519PublickeyHint, Instance: &base.Ed25519Publickey{}}))
+	t.noerror(t.Encs.AddDetail(encoder.DecodeDetail{Hint: base.BLSPublickeyHint, Instance: &base.BLSPublickey{}}))
 	t.noerror(t.Encs.AddDetail(encoder.DecodeDetail{Hint: base.StringAddressHint, Instance: base.StringAddress{}}))
 	t.noerror(t.Encs.AddDetail(encoder.DecodeDetail{Hint: base.DummyNodeHint, Instance: base.BaseNode{}}))
 	t.noerror(t.Encs.AddDetail(encoder.DecodeDetail{Hint: base.DummyStateValueHint, Instance: base.DummyStateValue{}}))
@@ -142,6 +142,11 @@ func (u *LastConsensusNodesWatcher) GetSuffrage(height base.Height) (base.Suffra
 
 	if proof != nil {
 		suf, err := proof.Suffrage()
+		if err != nil {
+			return nil, true, err
+		}
+
+		suf, err = KeyRotatedSuffrage(suf, height)
 
 		return suf, true, err
 	}
@@ -384,6 +389,10 @@ func IsNodeInLastConsensusNodes(
 		return suf, true, nil
 	}
 
+	if i, ok := suf.(Suffrage); ok && i.existsRotatePublickey(node.Address(), node.Publickey()) {
+		return suf, true, nil
+	}
+
 	if candidatesst == nil {
 		return suf, false, nil
 	}
@@ -0,0 +1,266 @@
+package isaaclightclient
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/pkg/errors"
+)
+
+// ErrSuffrageProofsNotEnough indicates the suffrage proofs of StateProof are
+// capped by remote; the client should move the checkpoint to the last suffrage
+// proof and request again.
+var ErrSuffrageProofsNotEnough = util.NewIDError("suffrage proofs not enough")
+
+type GetStateProofFromRemoteFunc func(_ context.Context, key string, suffrageheight base.Height) (
+	StateProof, bool, error)
+
+// Client gets the state from remote node and verifies it with StateProof. The
+// checkpoint suffrage proof, like genesis suffrage proof, should be trusted;
+// after verification, the checkpoint moves to the newer suffrage proof.
+type Client struct {
+	checkpoint    base.SuffrageProof
+	getStateProof GetStateProofFromRemoteFunc
+	networkID     base.NetworkID
+	threshold     base.Threshold
+	l             sync.RWMutex
+}
+
+func NewClient(
+	networkID base.NetworkID,
+	threshold base.Threshold,
+	checkpoint base.SuffrageProof,
+	getStateProof GetStateProofFromRemoteFunc,
+) (*Client, error) {
+	e := util.StringError("create new light client")
+
+	if checkpoint == nil {
+		return nil, e.Errorf("empty checkpoint")
+	}
+
+	if err := checkpoint.IsValid(networkID); err != nil {
+		return nil, e.WithMessage(err, "invalid checkpoint")
+	}
+
+	if err := threshold.IsValid(nil); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	return &Client{
+		networkID:     networkID,
+		threshold:     threshold,
+		checkpoint:    checkpoint,
+		getStateProof: getStateProof,
+	}, nil
+}
+
+func (c *Client) Checkpoint() base.SuffrageProof {
+	c.l.RLock()
+	defer c.l.RUnlock()
+
+	return c.checkpoint
+}
+
+// State returns the verified state. If the state is not found, the absence of
+// state can not be proved.
+func (c *Client) State(ctx context.Context, key string) (base.State, bool, error) {
+	e := util.StringError("get state with proof")
+
+	for {
+		checkpoint := c.Checkpoint()
+
+		sp, found, err := c.getStateProof(ctx, key, checkpoint.SuffrageHeight())
+
+		switch {
+		case err != nil:
+			return nil, false, e.Wrap(err)
+		case !found:
+			return nil, false, nil
+		}
+
+		newcheckpoint, err := VerifyStateProof(c.networkID, c.threshold, checkpoint, sp)
+
+		switch {
+		case err == nil:
+		case errors.Is(err, ErrSuffrageProofsNotEnough):
+			// NOTE the last suffrage proof is proved; request again with it.
+			c.setCheckpoint(newcheckpoint)
+
+			continue
+		default:
+			return nil, false, e.Wrap(err)
+		}
+
+		if sp.State().Key() != key {
+			return nil, false, e.Errorf("state key does not match, %q != %q", sp.State().Key(), key)
+		}
+
+		c.setCheckpoint(newcheckpoint)
+
+		return sp.State(), true, nil
+	}
+}
+
+func (c *Client) setCheckpoint(proof base.SuffrageProof) {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	if proof.SuffrageHeight() > c.checkpoint.SuffrageHeight() {
+		c.checkpoint = proof
+	}
+}
+
+// VerifyStateProof verifies StateProof from the trusted checkpoint suffrage
+// proof. The suffrage proofs of StateProof should be continuous with the
+// checkpoint; the suffrage proofs older than checkpoint are proved by the
+// previous state hash of the newer one. VerifyStateProof returns the suffrage
+// proof of the manifest, which can be the next checkpoint. If the suffrage
+// proofs do not reach to the suffrage of manifest, VerifyStateProof returns
+// the last suffrage proof with ErrSuffrageProofsNotEnough.
+func VerifyStateProof(
+	networkID base.NetworkID,
+	threshold base.Threshold,
+	checkpoint base.SuffrageProof,
+	sp StateProof,
+) (base.SuffrageProof, error) {
+	e := util.ErrInvalid.Errorf("verify StateProof")
+
+	if err := sp.IsValid(networkID); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	if th := sp.Voteproof().Threshold(); th < threshold {
+		return nil, e.Errorf("voteproof threshold too low, %v < %v", th, threshold)
+	}
+
+	proofs, err := sortSuffrageProofs(checkpoint, sp.SuffrageProofs())
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	for i := 1; i < len(proofs); i++ {
+		if err := proveSuffrageProof(proofs[i-1], proofs[i]); err != nil {
+			return nil, e.Wrap(err)
+		}
+	}
+
+	m := sp.Manifest()
+
+	index := -1
+
+	for i := range proofs {
+		if proofs[i].State().Hash().Equal(m.Suffrage()) {
+			index = i
+
+			break
+		}
+	}
+
+	if index < 0 {
+		if last := proofs[len(proofs)-1]; last.SuffrageHeight() > checkpoint.SuffrageHeight() &&
+			last.Map().Manifest().Height() < m.Height() {
+			return last, ErrSuffrageProofsNotEnough.Errorf("last suffrage proof, %d", last.SuffrageHeight())
+		}
+
+		return nil, e.Errorf("suffrage proof of manifest not found")
+	}
+
+	suf, err := voteproofSuffrage(proofs, index, m.Height())
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	if err := isaac.IsValidVoteproofWithSuffrage(sp.Voteproof(), suf); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	return proofs[index], nil
+}
+
+func sortSuffrageProofs(checkpoint base.SuffrageProof, l []base.SuffrageProof) ([]base.SuffrageProof, error) {
+	proofs := make([]base.SuffrageProof, len(l)+1)
+	proofs[0] = checkpoint
+	copy(proofs[1:], l)
+
+	sort.SliceStable(proofs, func(i, j int) bool {
+		return proofs[i].SuffrageHeight() < proofs[j].SuffrageHeight()
+	})
+
+	for i := 1; i < len(proofs); i++ {
+		if proofs[i].SuffrageHeight() != proofs[i-1].SuffrageHeight()+1 {
+			return nil, util.ErrInvalid.Errorf(
+				"suffrage proofs not continuous, %d after %d",
+				proofs[i].SuffrageHeight(), proofs[i-1].SuffrageHeight(),
+			)
+		}
+	}
+
+	return proofs, nil
+}
+
+func proveSuffrageProof(previous, proof base.SuffrageProof) error {
+	e := util.ErrInvalid.Errorf("prove suffrage proof, %d", proof.SuffrageHeight())
+
+	if err := proof.Prove(previous.State()); err != nil {
+		return e.Wrap(err)
+	}
+
+	m := proof.Map()
+
+	if err := isaac.IsValidManifestHash(m.Manifest()); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := isValidStatesTreeProof(proof.Proof(), m.Manifest(), proof.State()); err != nil {
+		return e.Wrap(err)
+	}
+
+	// NOTE block map should be signed by the node of previous suffrage.
+	suf, err := previous.Suffrage()
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	suf, err = isaac.KeyRotatedSuffrage(suf, m.Manifest().Height()-1)
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	if !suf.ExistsPublickey(m.Node(), m.Signer()) {
+		return e.Errorf("block map not signed by suffrage node")
+	}
+
+	return nil
+}
+
+// voteproofSuffrage returns the suffrage, which voted the ACCEPT voteproof of
+// height; it is the suffrage at the previous height.
+func voteproofSuffrage(proofs []base.SuffrageProof, index int, height base.Height) (base.Suffrage, error) {
+	m := proofs[index].Map()
+
+	switch mheight := m.Manifest().Height(); {
+	case mheight > height:
+		return nil, util.ErrInvalid.Errorf("suffrage proof of manifest is higher than manifest")
+	case height == base.GenesisHeight:
+		// NOTE genesis block is voted by the signer node of genesis block map.
+		return isaac.NewSuffrage([]base.Node{isaac.NewNode(m.Signer(), m.Node())})
+	case mheight == height:
+		// NOTE suffrage updated at height
+		if index < 1 {
+			return nil, util.ErrInvalid.Errorf("previous suffrage proof of manifest not found")
+		}
+
+		index--
+	}
+
+	suf, err := proofs[index].Suffrage()
+	if err != nil {
+		return nil, err
+	}
+
+	return isaac.KeyRotatedSuffrage(suf, height-1)
+}
@@ -0,0 +1,434 @@
+package isaaclightclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	isaacblock "github.com/ProtoconNet/mitum2/isaac/block"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/fixedtree"
+	"github.com/ProtoconNet/mitum2/util/localtime"
+	"github.com/ProtoconNet/mitum2/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testBlock struct {
+	manifest base.Manifest
+	tree     fixedtree.Tree
+}
+
+type baseTestStateProof struct {
+	isaac.BaseTestBallots
+	locals []base.LocalNode // NOTE genesis suffrage
+	joined base.LocalNode   // NOTE joined at height 3
+	proofs []base.SuffrageProof
+	blocks map[base.Height]testBlock
+	states map[base.Height]base.State
+}
+
+// SetupTest prepares blocks; the genesis suffrage is updated at height 3 and
+// the states, "k3" at height 3 and "k5" at height 5 are stored.
+func (t *baseTestStateProof) SetupTest() {
+	t.BaseTestBallots.SetupTest()
+
+	t.locals, _ = t.Locals(3)
+	t.joined = base.RandomLocalNode()
+
+	t.proofs = nil
+	t.blocks = map[base.Height]testBlock{}
+	t.states = map[base.Height]base.State{}
+
+	nodes := make([]base.Node, len(t.locals))
+	for i := range t.locals {
+		nodes[i] = t.locals[i]
+	}
+
+	sufst0 := t.suffrageState(base.GenesisHeight, base.GenesisHeight, nodes, nil)
+	t.newBlock(base.GenesisHeight, t.locals[0], sufst0, sufst0)
+
+	t.newBlock(base.Height(1), t.locals[0], sufst0.Hash(), t.newState(1, "k1"))
+	t.newBlock(base.Height(2), t.locals[0], sufst0.Hash(), t.newState(2, "k2"))
+
+	sufst1 := t.suffrageState(base.Height(3), base.Height(1), append(nodes, t.joined), sufst0.Hash())
+	t.states[3] = t.newState(3, "k3")
+	t.newBlock(base.Height(3), t.locals[1], sufst1, sufst1, t.states[3])
+
+	t.newBlock(base.Height(4), t.locals[0], sufst1.Hash(), t.newState(4, "k4"))
+
+	t.states[5] = t.newState(5, "k5")
+	t.newBlock(base.Height(5), t.joined, sufst1.Hash(), t.states[5])
+}
+
+func (t *baseTestStateProof) suffrageState(
+	height, sufheight base.Height, nodes []base.Node, previous util.Hash,
+) base.State {
+	sufnodes := make([]base.SuffrageNodeStateValue, len(nodes))
+	for i := range nodes {
+		sufnodes[i] = isaac.NewSuffrageNodeStateValue(nodes[i], height)
+	}
+
+	return base.NewBaseState(
+		height,
+		isaac.SuffrageStateKey,
+		isaac.NewSuffrageNodesStateValue(sufheight, sufnodes),
+		previous,
+		[]util.Hash{valuehash.RandomSHA256()},
+	)
+}
+
+func (t *baseTestStateProof) newState(height base.Height, key string) base.State {
+	return base.NewBaseState(
+		height,
+		key,
+		base.NewDummyStateValue(util.UUID().String()),
+		nil,
+		[]util.Hash{valuehash.RandomSHA256()},
+	)
+}
+
+// newBlock creates new block; if suffrage is base.State, it is the new
+// suffrage state of block.
+func (t *baseTestStateProof) newBlock(
+	height base.Height, signer base.LocalNode, suffrage interface{}, sts ...base.State,
+) {
+	w, err := fixedtree.NewWriter(base.StateFixedtreeHint, uint64(len(sts)))
+	t.NoError(err)
+
+	for i := range sts {
+		t.NoError(w.Add(uint64(i), fixedtree.NewBaseNode(sts[i].Hash().String())))
+	}
+
+	t.NoError(w.Write(func(uint64, fixedtree.Node) error { return nil }))
+
+	tr, err := w.Tree()
+	t.NoError(err)
+
+	var sufst base.State
+	var sufh util.Hash
+
+	switch i := suffrage.(type) {
+	case base.State:
+		sufst = i
+		sufh = i.Hash()
+	case util.Hash:
+		sufh = i
+	}
+
+	var previous util.Hash
+	if height > base.GenesisHeight {
+		previous = t.blocks[height-1].manifest.Hash()
+	}
+
+	manifest := isaac.NewManifest(
+		height,
+		previous,
+		valuehash.RandomSHA256(),
+		valuehash.RandomSHA256(),
+		tr.Root(),
+		sufh,
+		localtime.Now().UTC(),
+	)
+
+	t.blocks[height] = testBlock{manifest: manifest, tree: tr}
+
+	if sufst == nil {
+		return
+	}
+
+	m := isaacblock.NewBlockMap()
+
+	for _, i := range []base.BlockItemType{
+		base.BlockItemProposal,
+		base.BlockItemOperations,
+		base.BlockItemOperationsTree,
+		base.BlockItemStates,
+		base.BlockItemStatesTree,
+		base.BlockItemVoteproofs,
+	} {
+		t.NoError(m.SetItem(isaacblock.NewBlockMapItem(i, util.UUID().String())))
+	}
+
+	m.SetManifest(manifest)
+	t.NoError(m.Sign(signer.Address(), signer.Privatekey(), t.LocalParams.NetworkID()))
+
+	proof, err := tr.Proof(sufst.Hash().String())
+	t.NoError(err)
+
+	t.proofs = append(t.proofs, isaacblock.NewSuffrageProof(m, sufst, proof))
+}
+
+func (t *baseTestStateProof) voteproof(height base.Height, nodes []base.LocalNode) base.ACCEPTVoteproof {
+	fact := isaac.NewACCEPTBallotFact(
+		base.NewPoint(height, 0), valuehash.RandomSHA256(), t.blocks[height].manifest.Hash(), nil)
+
+	vp, err := t.NewACCEPTVoteproof(fact, nodes[0], nodes)
+	t.NoError(err)
+
+	return vp
+}
+
+func (t *baseTestStateProof) stateProof(
+	st base.State, vp base.ACCEPTVoteproof, proofs ...base.SuffrageProof,
+) StateProof {
+	b := t.blocks[st.Height()]
+
+	proof, err := b.tree.Proof(st.Hash().String())
+	t.NoError(err)
+
+	return NewStateProof(st, proof, b.manifest, vp, proofs)
+}
+
+type testVerifyStateProof struct {
+	baseTestStateProof
+}
+
+func (t *testVerifyStateProof) TestFromGenesis() {
+	st := t.states[5]
+	sp := t.stateProof(st, t.voteproof(st.Height(), append(t.locals, t.joined)), t.proofs[1])
+
+	checkpoint, err := VerifyStateProof(t.LocalParams.NetworkID(), base.DefaultThreshold, t.proofs[0], sp)
+	t.NoError(err)
+	t.Equal(base.Height(1), checkpoint.SuffrageHeight())
+}
+
+func (t *testVerifyStateProof) TestSuffrageUpdatedAtHeight() {
+	st := t.states[3]
+
+	t.Run("voted by previous suffrage", func() {
+		sp := t.stateProof(st, t.voteproof(st.Height(), t.locals), t.proofs[1])
+
+		checkpoint, err := VerifyStateProof(t.LocalParams.NetworkID(), base.DefaultThreshold, t.proofs[0], sp)
+		t.NoError(err)
+		t.Equal(base.Height(1), checkpoint.SuffrageHeight())
+	})
+
+	t.Run("voted by new suffrage", func() {
+		sp := t.stateProof(st, t.voteproof(st.Height(), append(t.locals, t.joined)), t.proofs[1])
+
+		_, err := VerifyStateProof(t.LocalParams.NetworkID(), base.DefaultThreshold, t.proofs[0], sp)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+	})
+
+	t.Run("older than checkpoint", func() {
+		sp := t.stateProof(st, t.voteproof(st.Height(), t.locals), t.proofs[0])
+
+		checkpoint, err := VerifyStateProof(t.LocalParams.NetworkID(), base.DefaultThreshold, t.proofs[1], sp)
+		t.NoError(err)
+		t.Equal(base.Height(1), checkpoint.SuffrageHeight())
+	})
+}
+
+func (t *testVerifyStateProof) TestGenesis() {
+	sufst := t.proofs[0].State()
+
+	sp := t.stateProof(sufst, t.voteproof(base.GenesisHeight, t.locals[:1]))
+
+	checkpoint, err := VerifyStateProof(t.LocalParams.NetworkID(), base.DefaultThreshold, t.proofs[0], sp)
+	t.NoError(err)
+	t.Equal(base.GenesisHeight, checkpoint.SuffrageHeight())
+}
+
+func (t *testVerifyStateProof) TestInvalid() {
+	st := t.states[5]
+	vp := t.voteproof(st.Height(), append(t.locals, t.joined))
+
+	t.Run("missing suffrage proof", func() {
+		sp := t.stateProof(st, vp)
+
+		_, err := VerifyStateProof(t.LocalParams.NetworkID(), base.DefaultThreshold, t.proofs[0], sp)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "suffrage proof of manifest not found")
+	})
+
+	t.Run("suffrage proofs not enough", func() {
+		nodes := make([]base.Node, len(t.locals))
+		for i := range t.locals {
+			nodes[i] = t.locals[i]
+		}
+
+		sufst2 := t.suffrageState(base.Height(6), base.Height(2), nodes, t.proofs[1].State().Hash())
+		t.newBlock(base.Height(6), t.locals[0], sufst2, sufst2)
+
+		st := t.newState(7, "k7")
+		t.newBlock(base.Height(7), t.locals[0], sufst2.Hash(), st)
+
+		sp := t.stateProof(st, t.voteproof(st.Height(), t.locals), t.proofs[1])
+
+		last, err := VerifyStateProof(t.LocalParams.NetworkID(), base.DefaultThreshold, t.proofs[0], sp)
+		t.Error(err)
+		t.ErrorIs(err, ErrSuffrageProofsNotEnough)
+		t.NotNil(last)
+		t.Equal(base.Height(1), last.SuffrageHeight())
+	})
+
+	t.Run("not continuous", func() {
+		sp := t.stateProof(st, vp, t.proofs[1], t.proofs[1])
+
+		_, err := VerifyStateProof(t.LocalParams.NetworkID(), base.DefaultThreshold, t.proofs[0], sp)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "not continuous")
+	})
+
+	t.Run("unknown suffrage", func() {
+		locals, _ := t.Locals(3)
+
+		sp := t.stateProof(st, t.voteproof(st.Height(), locals), t.proofs[1])
+
+		_, err := VerifyStateProof(t.LocalParams.NetworkID(), base.DefaultThreshold, t.proofs[0], sp)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "invalid voteproof with suffrage")
+	})
+
+	t.Run("block map not signed by suffrage", func() {
+		proof := t.proofs[1]
+
+		m := proof.Map().(isaacblock.BlockMap)
+		local := base.RandomLocalNode()
+		t.NoError(m.Sign(local.Address(), local.Privatekey(), t.LocalParams.NetworkID()))
+
+		sp := t.stateProof(st, vp, isaacblock.NewSuffrageProof(m, proof.State(), proof.Proof()))
+
+		_, err := VerifyStateProof(t.LocalParams.NetworkID(), base.DefaultThreshold, t.proofs[0], sp)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "block map not signed by suffrage node")
+	})
+
+	t.Run("low threshold", func() {
+		sp := t.stateProof(st, vp, t.proofs[1])
+
+		_, err := VerifyStateProof(t.LocalParams.NetworkID(), base.MaxThreshold+1, t.proofs[0], sp)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "threshold too low")
+	})
+}
+
+func TestVerifyStateProof(t *testing.T) {
+	suite.Run(t, new(testVerifyStateProof))
+}
+
+type testClient struct {
+	baseTestStateProof
+}
+
+func (t *testClient) TestState() {
+	st := t.states[5]
+	sp := t.stateProof(st, t.voteproof(st.Height(), append(t.locals, t.joined)), t.proofs[1])
+
+	var requested base.Height
+
+	c, err := NewClient(t.LocalParams.NetworkID(), base.DefaultThreshold, t.proofs[0],
+		func(_ context.Context, key string, suffrageheight base.Height) (StateProof, bool, error) {
+			requested = suffrageheight
+
+			if key != st.Key() {
+				return StateProof{}, false, nil
+			}
+
+			return sp, true, nil
+		},
+	)
+	t.NoError(err)
+
+	t.Run("found", func() {
+		rst, found, err := c.State(context.Background(), st.Key())
+		t.NoError(err)
+		t.True(found)
+		t.True(base.IsEqualState(st, rst))
+
+		t.Equal(base.GenesisHeight, requested)
+		t.Equal(base.Height(1), c.Checkpoint().SuffrageHeight())
+	})
+
+	t.Run("not found", func() {
+		rst, found, err := c.State(context.Background(), util.UUID().String())
+		t.NoError(err)
+		t.False(found)
+		t.Nil(rst)
+
+		t.Equal(base.Height(1), requested)
+	})
+}
+
+func (t *testClient) TestWrongKey() {
+	st := t.states[5]
+	sp := t.stateProof(st, t.voteproof(st.Height(), append(t.locals, t.joined)), t.proofs[1])
+
+	c, err := NewClient(t.LocalParams.NetworkID(), base.DefaultThreshold, t.proofs[0],
+		func(context.Context, string, base.Height) (StateProof, bool, error) {
+			return sp, true, nil
+		},
+	)
+	t.NoError(err)
+
+	_, _, err = c.State(context.Background(), util.UUID().String())
+	t.Error(err)
+	t.ErrorContains(err, "state key does not match")
+
+	t.Equal(base.GenesisHeight, c.Checkpoint().SuffrageHeight())
+}
+
+func (t *testClient) TestInvalidProof() {
+	st := t.states[5]
+	sp := t.stateProof(st, t.voteproof(st.Height(), append(t.locals, t.joined)))
+
+	c, err := NewClient(t.LocalParams.NetworkID(), base.DefaultThreshold, t.proofs[0],
+		func(context.Context, string, base.Height) (StateProof, bool, error) {
+			return sp, true, nil
+		},
+	)
+	t.NoError(err)
+
+	_, found, err := c.State(context.Background(), st.Key())
+	t.Error(err)
+	t.ErrorIs(err, util.ErrInvalid)
+	t.False(found)
+
+	t.Equal(base.GenesisHeight, c.Checkpoint().SuffrageHeight())
+}
+
+func (t *testClient) TestCappedSuffrageProofs() {
+	nodes := make([]base.Node, len(t.locals))
+	for i := range t.locals {
+		nodes[i] = t.locals[i]
+	}
+
+	sufst2 := t.suffrageState(base.Height(6), base.Height(2), nodes, t.proofs[1].State().Hash())
+	t.newBlock(base.Height(6), t.locals[0], sufst2, sufst2)
+
+	st := t.newState(7, "k7")
+	t.newBlock(base.Height(7), t.locals[0], sufst2.Hash(), st)
+
+	vp := t.voteproof(st.Height(), t.locals)
+
+	var requested []base.Height
+
+	c, err := NewClient(t.LocalParams.NetworkID(), base.DefaultThreshold, t.proofs[0],
+		func(_ context.Context, _ string, suffrageheight base.Height) (StateProof, bool, error) {
+			requested = append(requested, suffrageheight)
+
+			// NOTE remote returns only one suffrage proof
+			return t.stateProof(st, vp, t.proofs[suffrageheight+1]), true, nil
+		},
+	)
+	t.NoError(err)
+
+	rst, found, err := c.State(context.Background(), st.Key())
+	t.NoError(err)
+	t.True(found)
+	t.True(base.IsEqualState(st, rst))
+
+	t.Equal([]base.Height{0, 1}, requested)
+	t.Equal(base.Height(2), c.Checkpoint().SuffrageHeight())
+}
+
+func TestClient(t *testing.T) {
+	suite.Run(t, new(testClient))
+}
@@ -0,0 +1,5 @@
+/*
+Package isaaclightclient verifies the states from the remote node without
+trusting the node.
+*/
+package isaaclightclient
@@ -0,0 +1,209 @@
+package isaaclightclient
+
+import (
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/fixedtree"
+	"github.com/ProtoconNet/mitum2/util/hint"
+)
+
+var OperationProofHint = hint.MustNewHint("operation-proof-v0.0.1")
+
+// OperationProof proves the operation was processed in the block. The
+// operation node is proved by the operations tree of the manifest and the
+// manifest is proved by the ACCEPT voteproof.
+//
+// The reason of node is not the part of operations tree, so it can not be
+// proved; only the fact hash and InState() are proved and the node of
+// OperationProof does not have the reason.
+type OperationProof struct {
+	manifest  base.Manifest
+	voteproof base.ACCEPTVoteproof
+	proof     fixedtree.Proof
+	node      base.OperationFixedtreeNode
+	hint.BaseHinter
+}
+
+func NewOperationProof(
+	node base.OperationFixedtreeNode,
+	proof fixedtree.Proof,
+	manifest base.Manifest,
+	voteproof base.ACCEPTVoteproof,
+) OperationProof {
+	return OperationProof{
+		BaseHinter: hint.NewBaseHinter(OperationProofHint),
+		node:       operationFixedtreeNodeWithoutReason(node),
+		proof:      proof,
+		manifest:   manifest,
+		voteproof:  voteproof,
+	}
+}
+
+func (p OperationProof) IsValid(networkID []byte) error {
+	e := util.ErrInvalid.Errorf("invalid OperationProof")
+
+	if err := p.BaseHinter.IsValid(OperationProofHint.Type().Bytes()); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := util.CheckIsValiders(networkID, false, p.node, p.proof, p.manifest, p.voteproof); err != nil {
+		return e.Wrap(err)
+	}
+
+	switch {
+	case p.node.Operation() == nil:
+		return e.Errorf("empty operation")
+	case p.node.Reason() != nil:
+		return e.Errorf("reason can not be proved")
+	case p.voteproof.Point().Height() != p.manifest.Height():
+		return e.Errorf("voteproof height does not match with manifest")
+	case p.voteproof.Result() != base.VoteResultMajority:
+		return e.Errorf("not majority voteproof")
+	case !p.voteproof.BallotMajority().NewBlock().Equal(p.manifest.Hash()):
+		return e.Errorf("manifest hash does not match with voteproof")
+	}
+
+	if err := isaac.IsValidManifestHash(p.manifest); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := isValidOperationsTreeProof(p.proof, p.manifest, p.node); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
+
+func (p OperationProof) Node() base.OperationFixedtreeNode {
+	return p.node
+}
+
+func (p OperationProof) Proof() fixedtree.Proof {
+	return p.proof
+}
+
+func (p OperationProof) Manifest() base.Manifest {
+	return p.manifest
+}
+
+func (p OperationProof) Voteproof() base.ACCEPTVoteproof {
+	return p.voteproof
+}
+
+// VerifyOperationProof verifies OperationProof with the trusted suffrage
+// proofs. Like VerifyStateProof, the suffrage proofs should be continuous and
+// one of them should be the suffrage of manifest; if the suffrage is updated at
+// the height of block, the previous suffrage proof is also needed.
+func VerifyOperationProof(
+	networkID base.NetworkID,
+	threshold base.Threshold,
+	proofs []base.SuffrageProof,
+	p OperationProof,
+) error {
+	e := util.ErrInvalid.Errorf("verify OperationProof")
+
+	if err := p.IsValid(networkID); err != nil {
+		return e.Wrap(err)
+	}
+
+	if th := p.Voteproof().Threshold(); th < threshold {
+		return e.Errorf("voteproof threshold too low, %v < %v", th, threshold)
+	}
+
+	if len(proofs) < 1 {
+		return e.Errorf("empty suffrage proofs")
+	}
+
+	sorted, err := sortSuffrageProofs(proofs[0], proofs[1:])
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	for i := 1; i < len(sorted); i++ {
+		if err := proveSuffrageProof(sorted[i-1], sorted[i]); err != nil {
+			return e.Wrap(err)
+		}
+	}
+
+	m := p.Manifest()
+
+	index := -1
+
+	for i := range sorted {
+		if sorted[i].State().Hash().Equal(m.Suffrage()) {
+			index = i
+
+			break
+		}
+	}
+
+	if index < 0 {
+		return e.Errorf("suffrage proof of manifest not found")
+	}
+
+	suf, err := voteproofSuffrage(sorted, index, m.Height())
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := isaac.IsValidVoteproofWithSuffrage(p.Voteproof(), suf); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
+
+func operationFixedtreeNodeWithoutReason(node base.OperationFixedtreeNode) base.OperationFixedtreeNode {
+	if node.Reason() == nil || node.Operation() == nil {
+		return node
+	}
+
+	var n base.OperationFixedtreeNode
+
+	switch {
+	case node.InState():
+		n = base.NewInStateOperationFixedtreeNode(node.Operation(), "")
+	default:
+		n = base.NewNotInStateOperationFixedtreeNode(node.Operation(), "")
+	}
+
+	return n.SetHash(node.Hash()).(base.OperationFixedtreeNode) //nolint:forcetypeassert //...
+}
+
+// isValidOperationsTreeProof checks the operation node is in the operations
+// tree of manifest.
+func isValidOperationsTreeProof(proof fixedtree.Proof, m base.Manifest, node base.OperationFixedtreeNode) error {
+	e := util.ErrInvalid.Errorf("invalid operations tree proof")
+
+	nodes := proof.Nodes()
+
+	switch {
+	case len(nodes) < 1:
+		return e.Errorf("empty proof")
+	case m.OperationsTree() == nil:
+		return e.Errorf("empty operations tree")
+	case !nodes[len(nodes)-1].Hash().Equal(m.OperationsTree()):
+		return e.Errorf("root does not match with manifest")
+	}
+
+	var found bool
+
+	for i := range nodes {
+		if nodes[i] != nil && nodes[i].Key() == node.Key() {
+			found = node.BaseNode.Equal(nodes[i])
+
+			break
+		}
+	}
+
+	if !found {
+		return e.Errorf("node not found in proof")
+	}
+
+	if err := proof.Prove(node.Key()); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
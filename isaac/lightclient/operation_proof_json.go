@@ -0,0 +1,61 @@
+package isaaclightclient
+
+import (
+	"encoding/json"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/ProtoconNet/mitum2/util/fixedtree"
+	"github.com/ProtoconNet/mitum2/util/hint"
+)
+
+type operationProofJSONMarshaler struct {
+	Manifest  base.Manifest               `json:"manifest"`
+	Voteproof base.ACCEPTVoteproof        `json:"voteproof"`
+	Proof     fixedtree.Proof             `json:"proof"`
+	Node      base.OperationFixedtreeNode `json:"node"`
+	hint.BaseHinter
+}
+
+func (p OperationProof) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(operationProofJSONMarshaler{
+		BaseHinter: p.BaseHinter,
+		Node:       p.node,
+		Proof:      p.proof,
+		Manifest:   p.manifest,
+		Voteproof:  p.voteproof,
+	})
+}
+
+type operationProofJSONUnmarshaler struct {
+	Manifest  json.RawMessage `json:"manifest"`
+	Voteproof json.RawMessage `json:"voteproof"`
+	Node      json.RawMessage `json:"node"`
+	Proof     fixedtree.Proof `json:"proof"`
+}
+
+func (p *OperationProof) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	e := util.StringError("decode OperationProof")
+
+	var u operationProofJSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := p.node.DecodeJSON(u.Node, enc); err != nil {
+		return e.WithMessage(err, "node")
+	}
+
+	if err := encoder.Decode(enc, u.Manifest, &p.manifest); err != nil {
+		return e.WithMessage(err, "manifest")
+	}
+
+	if err := encoder.Decode(enc, u.Voteproof, &p.voteproof); err != nil {
+		return e.WithMessage(err, "voteproof")
+	}
+
+	p.proof = u.Proof
+
+	return nil
+}
@@ -0,0 +1,336 @@
+package isaaclightclient
+
+import (
+	"testing"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	jsonenc "github.com/ProtoconNet/mitum2/util/encoder/json"
+	"github.com/ProtoconNet/mitum2/util/fixedtree"
+	"github.com/ProtoconNet/mitum2/util/localtime"
+	"github.com/ProtoconNet/mitum2/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testOperationProof struct {
+	isaac.BaseTestBallots
+	locals   []base.LocalNode
+	nodes    []base.OperationFixedtreeNode
+	tree     fixedtree.Tree
+	manifest base.Manifest
+}
+
+func (t *testOperationProof) SetupTest() {
+	t.BaseTestBallots.SetupTest()
+
+	t.locals, _ = t.Locals(3)
+
+	t.nodes = []base.OperationFixedtreeNode{
+		base.NewInStateOperationFixedtreeNode(valuehash.RandomSHA256(), ""),
+		base.NewNotInStateOperationFixedtreeNode(valuehash.RandomSHA256(), "failed"),
+		base.NewInStateOperationFixedtreeNode(valuehash.RandomSHA256(), ""),
+	}
+
+	w, err := fixedtree.NewWriter(base.OperationFixedtreeHint, uint64(len(t.nodes)))
+	t.NoError(err)
+
+	for i := range t.nodes {
+		t.NoError(w.Add(uint64(i), t.nodes[i]))
+	}
+
+	t.NoError(w.Write(func(uint64, fixedtree.Node) error { return nil }))
+
+	t.tree, err = w.Tree()
+	t.NoError(err)
+
+	t.manifest = isaac.NewManifest(
+		base.Height(33),
+		valuehash.RandomSHA256(),
+		valuehash.RandomSHA256(),
+		t.tree.Root(),
+		valuehash.RandomSHA256(),
+		valuehash.RandomSHA256(),
+		localtime.Now().UTC(),
+	)
+}
+
+func (t *testOperationProof) voteproof(m base.Manifest, nodes []base.LocalNode) base.ACCEPTVoteproof {
+	fact := isaac.NewACCEPTBallotFact(base.NewPoint(m.Height(), 0), valuehash.RandomSHA256(), m.Hash(), nil)
+
+	vp, err := t.NewACCEPTVoteproof(fact, nodes[0], nodes)
+	t.NoError(err)
+
+	return vp
+}
+
+func (t *testOperationProof) operationProof(i int) OperationProof {
+	node := t.tree.Node(uint64(i))
+
+	proof, err := t.tree.Proof(node.Key())
+	t.NoError(err)
+
+	return NewOperationProof(
+		node.(base.OperationFixedtreeNode), proof, t.manifest, t.voteproof(t.manifest, t.locals)) //nolint:forcetypeassert //...
+}
+
+func (t *testOperationProof) TestNew() {
+	for i := range t.nodes {
+		p := t.operationProof(i)
+
+		_ = (interface{})(p).(util.IsValider)
+
+		t.NoError(p.IsValid(t.LocalParams.NetworkID()))
+		t.True(p.Node().Operation().Equal(t.nodes[i].Operation()))
+		t.Equal(t.nodes[i].InState(), p.Node().InState())
+		t.Nil(p.Node().Reason())
+	}
+}
+
+func (t *testOperationProof) TestIsValid() {
+	t.Run("different operation", func() {
+		p := t.operationProof(0)
+		p.node = base.NewInStateOperationFixedtreeNode(valuehash.RandomSHA256(), "").
+			SetHash(valuehash.RandomSHA256()).(base.OperationFixedtreeNode) //nolint:forcetypeassert //...
+
+		err := p.IsValid(t.LocalParams.NetworkID())
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "node not found in proof")
+	})
+
+	t.Run("different in state", func() {
+		p := t.operationProof(1)
+		p.node = base.NewInStateOperationFixedtreeNode(t.nodes[1].Operation(), "").
+			SetHash(p.node.Hash()).(base.OperationFixedtreeNode) //nolint:forcetypeassert //...
+
+		err := p.IsValid(t.LocalParams.NetworkID())
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "node not found in proof")
+	})
+
+	t.Run("with reason", func() {
+		p := t.operationProof(1)
+		p.node = base.NewNotInStateOperationFixedtreeNode(t.nodes[1].Operation(), "hehehe").
+			SetHash(p.node.Hash()).(base.OperationFixedtreeNode) //nolint:forcetypeassert //...
+
+		err := p.IsValid(t.LocalParams.NetworkID())
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "reason can not be proved")
+	})
+
+	t.Run("root does not match", func() {
+		p := t.operationProof(0)
+		p.manifest = isaac.NewManifest(
+			t.manifest.Height(),
+			t.manifest.Previous(),
+			t.manifest.Proposal(),
+			valuehash.RandomSHA256(),
+			t.manifest.StatesTree(),
+			t.manifest.Suffrage(),
+			t.manifest.ProposedAt(),
+		)
+		p.voteproof = t.voteproof(p.manifest, t.locals)
+
+		err := p.IsValid(t.LocalParams.NetworkID())
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "root does not match with manifest")
+	})
+
+	t.Run("manifest not voted", func() {
+		p := t.operationProof(0)
+		p.voteproof = t.voteproof(base.NewDummyManifest(t.manifest.Height(), valuehash.RandomSHA256()), t.locals)
+
+		err := p.IsValid(t.LocalParams.NetworkID())
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "manifest hash does not match with voteproof")
+	})
+
+	t.Run("wrong manifest hash", func() {
+		p := t.operationProof(0)
+		p.manifest = base.NewDummyManifest(t.manifest.Height(), t.manifest.Hash())
+
+		err := p.IsValid(t.LocalParams.NetworkID())
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "manifest hash does not match")
+	})
+}
+
+func (t *testOperationProof) TestEncode() {
+	tt := new(encoder.BaseTestEncode)
+	enc := jsonenc.NewEncoder()
+
+	hints := []encoder.DecodeDetail{
+		{Hint: base.MPublickeyHint, Instance: &base.MPublickey{}},
+		{Hint: base.StringAddressHint, Instance: base.StringAddress{}},
+		{Hint: base.BaseOperationProcessReasonErrorHint, Instance: base.BaseOperationProcessReasonError{}},
+		{Hint: isaac.ManifestHint, Instance: isaac.Manifest{}},
+		{Hint: isaac.ACCEPTBallotSignFactHint, Instance: isaac.ACCEPTBallotSignFact{}},
+		{Hint: isaac.ACCEPTBallotFactHint, Instance: isaac.ACCEPTBallotFact{}},
+		{Hint: isaac.ACCEPTVoteproofHint, Instance: isaac.ACCEPTVoteproof{}},
+		{Hint: OperationProofHint, Instance: OperationProof{}},
+	}
+	for i := range hints {
+		t.NoError(enc.Add(hints[i]))
+	}
+
+	tt.Encode = func() (interface{}, []byte) {
+		p := t.operationProof(1)
+
+		b, err := enc.Marshal(p)
+		t.NoError(err)
+
+		t.T().Log("marshaled:", string(b))
+
+		return p, b
+	}
+	tt.Decode = func(b []byte) interface{} {
+		i, err := enc.Decode(b)
+		t.NoError(err)
+
+		_, ok := i.(OperationProof)
+		t.True(ok)
+
+		return i
+	}
+	tt.Compare = func(a, b interface{}) {
+		ap, ok := a.(OperationProof)
+		t.True(ok)
+		bp, ok := b.(OperationProof)
+		t.True(ok)
+
+		t.NoError(bp.IsValid(t.LocalParams.NetworkID()))
+
+		t.True(ap.Node().Equal(bp.Node()))
+		t.Equal(ap.Node().InState(), bp.Node().InState())
+		t.Nil(bp.Node().Reason())
+		base.EqualManifest(t.Assert(), ap.Manifest(), bp.Manifest())
+		base.EqualVoteproof(t.Assert(), ap.Voteproof(), bp.Voteproof())
+	}
+
+	suite.Run(t.T(), tt)
+}
+
+func TestOperationProof(t *testing.T) {
+	suite.Run(t, new(testOperationProof))
+}
+
+type testVerifyOperationProof struct {
+	baseTestStateProof
+}
+
+// operationProof creates new block at height with the operation; the suffrage of
+// block is same with the block of sufheight.
+func (t *testVerifyOperationProof) operationProof(
+	height, sufheight base.Height, nodes []base.LocalNode,
+) OperationProof {
+	node := base.NewInStateOperationFixedtreeNode(valuehash.RandomSHA256(), "")
+
+	w, err := fixedtree.NewWriter(base.OperationFixedtreeHint, 1)
+	t.NoError(err)
+	t.NoError(w.Add(0, node))
+	t.NoError(w.Write(func(uint64, fixedtree.Node) error { return nil }))
+
+	tr, err := w.Tree()
+	t.NoError(err)
+
+	b := t.blocks[sufheight]
+
+	m := isaac.NewManifest(
+		height,
+		valuehash.RandomSHA256(),
+		valuehash.RandomSHA256(),
+		tr.Root(),
+		b.manifest.StatesTree(),
+		b.manifest.Suffrage(),
+		localtime.Now().UTC(),
+	)
+
+	fact := isaac.NewACCEPTBallotFact(base.NewPoint(height, 0), valuehash.RandomSHA256(), m.Hash(), nil)
+
+	vp, err := t.NewACCEPTVoteproof(fact, nodes[0], nodes)
+	t.NoError(err)
+
+	proof, err := tr.Proof(node.Key())
+	t.NoError(err)
+
+	return NewOperationProof(tr.Node(0).(base.OperationFixedtreeNode), proof, m, vp) //nolint:forcetypeassert //...
+}
+
+func (t *testVerifyOperationProof) TestVerify() {
+	all := append(t.locals, t.joined)
+
+	t.Run("ok", func() {
+		p := t.operationProof(6, 5, all)
+
+		t.NoError(VerifyOperationProof(t.LocalParams.NetworkID(), base.DefaultThreshold, t.proofs[1:], p))
+	})
+
+	t.Run("continuous suffrage proofs", func() {
+		p := t.operationProof(6, 5, all)
+
+		t.NoError(VerifyOperationProof(t.LocalParams.NetworkID(), base.DefaultThreshold, t.proofs, p))
+	})
+
+	t.Run("older suffrage proof", func() {
+		p := t.operationProof(6, 5, all)
+
+		err := VerifyOperationProof(t.LocalParams.NetworkID(), base.DefaultThreshold, t.proofs[:1], p)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "suffrage proof of manifest not found")
+	})
+
+	t.Run("empty suffrage proofs", func() {
+		p := t.operationProof(6, 5, all)
+
+		err := VerifyOperationProof(t.LocalParams.NetworkID(), base.DefaultThreshold, nil, p)
+		t.Error(err)
+		t.ErrorContains(err, "empty suffrage proofs")
+	})
+
+	t.Run("unknown suffrage", func() {
+		other, _ := t.Locals(3)
+
+		p := t.operationProof(6, 5, other)
+
+		err := VerifyOperationProof(t.LocalParams.NetworkID(), base.DefaultThreshold, t.proofs[1:], p)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+	})
+}
+
+func (t *testVerifyOperationProof) TestSuffrageUpdatedAtHeight() {
+	t.Run("without previous suffrage proof", func() {
+		p := t.operationProof(3, 3, t.locals)
+
+		err := VerifyOperationProof(t.LocalParams.NetworkID(), base.DefaultThreshold, t.proofs[1:], p)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "previous suffrage proof of manifest not found")
+	})
+
+	t.Run("voted by previous suffrage", func() {
+		p := t.operationProof(3, 3, t.locals)
+
+		t.NoError(VerifyOperationProof(t.LocalParams.NetworkID(), base.DefaultThreshold, t.proofs, p))
+	})
+
+	t.Run("voted by new suffrage", func() {
+		p := t.operationProof(3, 3, append(t.locals, t.joined))
+
+		err := VerifyOperationProof(t.LocalParams.NetworkID(), base.DefaultThreshold, t.proofs, p)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+	})
+}
+
+func TestVerifyOperationProof(t *testing.T) {
+	suite.Run(t, new(testVerifyOperationProof))
+}
@@ -0,0 +1,122 @@
+package isaaclightclient
+
+import (
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/fixedtree"
+	"github.com/ProtoconNet/mitum2/util/hint"
+)
+
+var StateProofHint = hint.MustNewHint("state-proof-v0.0.1")
+
+// StateProof proves the state. The state is proved by the states tree of the
+// manifest, the manifest is proved by the ACCEPT voteproof and the suffrage of
+// the voteproof is proved by the suffrage proofs.
+type StateProof struct {
+	st        base.State
+	manifest  base.Manifest
+	voteproof base.ACCEPTVoteproof
+	proof     fixedtree.Proof
+	suffrages []base.SuffrageProof
+	hint.BaseHinter
+}
+
+func NewStateProof(
+	st base.State,
+	proof fixedtree.Proof,
+	manifest base.Manifest,
+	voteproof base.ACCEPTVoteproof,
+	suffrages []base.SuffrageProof,
+) StateProof {
+	return StateProof{
+		BaseHinter: hint.NewBaseHinter(StateProofHint),
+		st:         st,
+		proof:      proof,
+		manifest:   manifest,
+		voteproof:  voteproof,
+		suffrages:  suffrages,
+	}
+}
+
+func (p StateProof) IsValid(networkID []byte) error {
+	e := util.ErrInvalid.Errorf("invalid StateProof")
+
+	if err := p.BaseHinter.IsValid(StateProofHint.Type().Bytes()); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := util.CheckIsValiders(networkID, false, p.st, p.proof, p.manifest, p.voteproof); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := util.CheckIsValiderSlice(networkID, false, p.suffrages); err != nil {
+		return e.Wrap(err)
+	}
+
+	switch {
+	case p.st.Height() != p.manifest.Height():
+		return e.Errorf("state height does not match with manifest")
+	case p.voteproof.Point().Height() != p.manifest.Height():
+		return e.Errorf("voteproof height does not match with manifest")
+	case p.voteproof.Result() != base.VoteResultMajority:
+		return e.Errorf("not majority voteproof")
+	case !p.voteproof.BallotMajority().NewBlock().Equal(p.manifest.Hash()):
+		return e.Errorf("manifest hash does not match with voteproof")
+	}
+
+	if err := isaac.IsValidManifestHash(p.manifest); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := isValidStatesTreeProof(p.proof, p.manifest, p.st); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
+
+func (p StateProof) State() base.State {
+	return p.st
+}
+
+func (p StateProof) Proof() fixedtree.Proof {
+	return p.proof
+}
+
+func (p StateProof) Manifest() base.Manifest {
+	return p.manifest
+}
+
+func (p StateProof) Voteproof() base.ACCEPTVoteproof {
+	return p.voteproof
+}
+
+// SuffrageProofs returns the suffrage proofs between the suffrage of the
+// client and the suffrage of the state height.
+func (p StateProof) SuffrageProofs() []base.SuffrageProof {
+	return p.suffrages
+}
+
+// isValidStatesTreeProof checks the state is in the states tree of manifest;
+// the last node of proof is the root of tree.
+func isValidStatesTreeProof(proof fixedtree.Proof, m base.Manifest, st base.State) error {
+	e := util.ErrInvalid.Errorf("invalid states tree proof")
+
+	nodes := proof.Nodes()
+
+	switch {
+	case len(nodes) < 1:
+		return e.Errorf("empty proof")
+	case m.StatesTree() == nil:
+		return e.Errorf("empty states tree")
+	case !nodes[len(nodes)-1].Hash().Equal(m.StatesTree()):
+		return e.Errorf("root does not match with manifest")
+	}
+
+	if err := proof.Prove(st.Hash().String()); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
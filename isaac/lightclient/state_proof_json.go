@@ -0,0 +1,72 @@
+package isaaclightclient
+
+import (
+	"encoding/json"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/ProtoconNet/mitum2/util/fixedtree"
+	"github.com/ProtoconNet/mitum2/util/hint"
+)
+
+type stateProofJSONMarshaler struct {
+	State     base.State           `json:"state"`
+	Manifest  base.Manifest        `json:"manifest"`
+	Voteproof base.ACCEPTVoteproof `json:"voteproof"`
+	Suffrages []base.SuffrageProof `json:"suffrage_proofs"`
+	Proof     fixedtree.Proof      `json:"proof"`
+	hint.BaseHinter
+}
+
+func (p StateProof) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(stateProofJSONMarshaler{
+		BaseHinter: p.BaseHinter,
+		State:      p.st,
+		Proof:      p.proof,
+		Manifest:   p.manifest,
+		Voteproof:  p.voteproof,
+		Suffrages:  p.suffrages,
+	})
+}
+
+type stateProofJSONUnmarshaler struct {
+	State     json.RawMessage   `json:"state"`
+	Manifest  json.RawMessage   `json:"manifest"`
+	Voteproof json.RawMessage   `json:"voteproof"`
+	Suffrages []json.RawMessage `json:"suffrage_proofs"`
+	Proof     fixedtree.Proof   `json:"proof"`
+}
+
+func (p *StateProof) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	e := util.StringError("decode StateProof")
+
+	var u stateProofJSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := encoder.Decode(enc, u.State, &p.st); err != nil {
+		return e.WithMessage(err, "state")
+	}
+
+	if err := encoder.Decode(enc, u.Manifest, &p.manifest); err != nil {
+		return e.WithMessage(err, "manifest")
+	}
+
+	if err := encoder.Decode(enc, u.Voteproof, &p.voteproof); err != nil {
+		return e.WithMessage(err, "voteproof")
+	}
+
+	p.suffrages = make([]base.SuffrageProof, len(u.Suffrages))
+
+	for i := range u.Suffrages {
+		if err := encoder.Decode(enc, u.Suffrages[i], &p.suffrages[i]); err != nil {
+			return e.WithMessage(err, "suffrage proof")
+		}
+	}
+
+	p.proof = u.Proof
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package isaaclightclient
+
+import (
+	"testing"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	isaacblock "github.com/ProtoconNet/mitum2/isaac/block"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	jsonenc "github.com/ProtoconNet/mitum2/util/encoder/json"
+	"github.com/ProtoconNet/mitum2/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testStateProof struct {
+	baseTestStateProof
+}
+
+func (t *testStateProof) TestNew() {
+	st := t.states[5]
+	sp := t.stateProof(st, t.voteproof(st.Height(), append(t.locals, t.joined)), t.proofs[1])
+
+	_ = (interface{})(sp).(util.IsValider)
+
+	t.NoError(sp.IsValid(t.LocalParams.NetworkID()))
+}
+
+func (t *testStateProof) TestIsValid() {
+	st := t.states[5]
+	vp := t.voteproof(st.Height(), append(t.locals, t.joined))
+
+	t.Run("state not in tree", func() {
+		sp := t.stateProof(st, vp)
+
+		other := t.newState(st.Height(), st.Key())
+		sp.st = other
+
+		err := sp.IsValid(t.LocalParams.NetworkID())
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "invalid states tree proof")
+	})
+
+	t.Run("state of different height", func() {
+		sp := t.stateProof(t.states[3], vp)
+		sp.manifest = t.blocks[5].manifest
+
+		err := sp.IsValid(t.LocalParams.NetworkID())
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "state height does not match")
+	})
+
+	t.Run("manifest not voted", func() {
+		sp := t.stateProof(st, t.voteproof(t.states[3].Height(), t.locals))
+
+		err := sp.IsValid(t.LocalParams.NetworkID())
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "voteproof height does not match")
+	})
+
+	t.Run("wrong manifest hash", func() {
+		m := t.blocks[5].manifest
+
+		fact := isaac.NewACCEPTBallotFact(base.NewPoint(m.Height(), 0), valuehash.RandomSHA256(), m.Hash(), nil)
+		wvp, err := t.NewACCEPTVoteproof(fact, t.locals[0], append(t.locals, t.joined))
+		t.NoError(err)
+
+		sp := t.stateProof(st, wvp)
+		sp.manifest = base.NewDummyManifest(m.Height(), m.Hash())
+
+		err = sp.IsValid(t.LocalParams.NetworkID())
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "manifest hash does not match")
+	})
+}
+
+func (t *testStateProof) TestEncode() {
+	tt := new(encoder.BaseTestEncode)
+	enc := jsonenc.NewEncoder()
+
+	hints := []encoder.DecodeDetail{
+		{Hint: base.MPublickeyHint, Instance: &base.MPublickey{}},
+		{Hint: base.StringAddressHint, Instance: base.StringAddress{}},
+		{Hint: base.BaseStateHint, Instance: base.BaseState{}},
+		{Hint: base.DummyStateValueHint, Instance: base.DummyStateValue{}},
+		{Hint: isaac.ManifestHint, Instance: isaac.Manifest{}},
+		{Hint: isaac.SuffrageNodeStateValueHint, Instance: isaac.SuffrageNodeStateValue{}},
+		{Hint: isaac.SuffrageNodesStateValueHint, Instance: isaac.SuffrageNodesStateValue{}},
+		{Hint: isaac.ACCEPTBallotSignFactHint, Instance: isaac.ACCEPTBallotSignFact{}},
+		{Hint: isaac.ACCEPTBallotFactHint, Instance: isaac.ACCEPTBallotFact{}},
+		{Hint: isaac.ACCEPTVoteproofHint, Instance: isaac.ACCEPTVoteproof{}},
+		{Hint: isaacblock.BlockMapHint, Instance: isaacblock.BlockMap{}},
+		{Hint: isaacblock.SuffrageProofHint, Instance: isaacblock.SuffrageProof{}},
+		{Hint: StateProofHint, Instance: StateProof{}},
+	}
+	for i := range hints {
+		t.NoError(enc.Add(hints[i]))
+	}
+
+	tt.Encode = func() (interface{}, []byte) {
+		st := t.states[5]
+		sp := t.stateProof(st, t.voteproof(st.Height(), append(t.locals, t.joined)), t.proofs[1])
+
+		b, err := enc.Marshal(sp)
+		t.NoError(err)
+
+		t.T().Log("marshaled:", string(b))
+
+		return sp, b
+	}
+	tt.Decode = func(b []byte) interface{} {
+		i, err := enc.Decode(b)
+		t.NoError(err)
+
+		_, ok := i.(StateProof)
+		t.True(ok)
+
+		return i
+	}
+	tt.Compare = func(a, b interface{}) {
+		ap, ok := a.(StateProof)
+		t.True(ok)
+		bp, ok := b.(StateProof)
+		t.True(ok)
+
+		t.NoError(bp.IsValid(t.LocalParams.NetworkID()))
+
+		t.True(base.IsEqualState(ap.State(), bp.State()))
+		base.EqualManifest(t.Assert(), ap.Manifest(), bp.Manifest())
+		base.EqualVoteproof(t.Assert(), ap.Voteproof(), bp.Voteproof())
+		t.Equal(len(ap.SuffrageProofs()), len(bp.SuffrageProofs()))
+
+		_, err := VerifyStateProof(t.LocalParams.NetworkID(), base.DefaultThreshold, t.proofs[0], bp)
+		t.NoError(err)
+	}
+
+	suite.Run(t.T(), tt)
+}
+
+func TestStateProof(t *testing.T) {
+	suite.Run(t, new(testStateProof))
+}
@@ -0,0 +1,80 @@
+package isaaclightclient
+
+import (
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+)
+
+// ProveSuffrageProofs proves the suffrage proofs from the trusted first suffrage
+// proof; the proofs should be continuous with the first one. The sorted proofs
+// are returned.
+func ProveSuffrageProofs(
+	networkID base.NetworkID,
+	first base.SuffrageProof,
+	l []base.SuffrageProof,
+) ([]base.SuffrageProof, error) {
+	e := util.ErrInvalid.Errorf("prove suffrage proofs")
+
+	if first == nil {
+		return nil, e.Errorf("empty first suffrage proof")
+	}
+
+	proofs, err := sortSuffrageProofs(first, l)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	for i := range proofs {
+		if err := proofs[i].IsValid(networkID); err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		if i < 1 {
+			continue
+		}
+
+		if err := proveSuffrageProof(proofs[i-1], proofs[i]); err != nil {
+			return nil, e.Wrap(err)
+		}
+	}
+
+	return proofs, nil
+}
+
+// VerifyBlockMapWithSuffrageProofs checks the block map is signed by the node
+// of suffrage, which voted the block. The proofs should be proved by
+// ProveSuffrageProofs.
+func VerifyBlockMapWithSuffrageProofs(proofs []base.SuffrageProof, m base.BlockMap) error {
+	manifest := m.Manifest()
+
+	e := util.ErrInvalid.Errorf("verify block map, %d with suffrage proofs", manifest.Height())
+
+	if manifest.Suffrage() == nil {
+		return e.Errorf("empty suffrage of manifest")
+	}
+
+	index := -1
+
+	for i := range proofs {
+		if proofs[i].State().Hash().Equal(manifest.Suffrage()) {
+			index = i
+
+			break
+		}
+	}
+
+	if index < 0 {
+		return e.Errorf("suffrage proof of manifest not found")
+	}
+
+	suf, err := voteproofSuffrage(proofs, index, manifest.Height())
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	if !suf.ExistsPublickey(m.Node(), m.Signer()) {
+		return e.Errorf("block map not signed by suffrage node")
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package isaaclightclient
+
+import (
+	"testing"
+
+	"github.com/ProtoconNet/mitum2/base"
+	isaacblock "github.com/ProtoconNet/mitum2/isaac/block"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/stretchr/testify/suite"
+)
+
+type testSuffrageProofs struct {
+	baseTestStateProof
+}
+
+func (t *testSuffrageProofs) blockMap(height base.Height, signer base.LocalNode) base.BlockMap {
+	m := isaacblock.NewBlockMap()
+	m.SetManifest(t.blocks[height].manifest)
+	t.NoError(m.Sign(signer.Address(), signer.Privatekey(), t.LocalParams.NetworkID()))
+
+	return m
+}
+
+func (t *testSuffrageProofs) TestProve() {
+	t.Run("ok", func() {
+		proofs, err := ProveSuffrageProofs(t.LocalParams.NetworkID(), t.proofs[0], t.proofs[1:])
+		t.NoError(err)
+		t.Equal(2, len(proofs))
+		t.Equal(base.GenesisHeight, proofs[0].SuffrageHeight())
+		t.Equal(base.Height(1), proofs[1].SuffrageHeight())
+	})
+
+	t.Run("empty first", func() {
+		_, err := ProveSuffrageProofs(t.LocalParams.NetworkID(), nil, t.proofs)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "empty first suffrage proof")
+	})
+
+	t.Run("wrong network id", func() {
+		_, err := ProveSuffrageProofs(util.UUID().Bytes(), t.proofs[0], t.proofs[1:])
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+	})
+
+	t.Run("not continuous", func() {
+		_, err := ProveSuffrageProofs(t.LocalParams.NetworkID(), t.proofs[0], []base.SuffrageProof{t.proofs[1], t.proofs[1]})
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "not continuous")
+	})
+}
+
+func (t *testSuffrageProofs) TestVerifyBlockMap() {
+	proofs, err := ProveSuffrageProofs(t.LocalParams.NetworkID(), t.proofs[0], t.proofs[1:])
+	t.NoError(err)
+
+	t.Run("genesis", func() {
+		t.NoError(VerifyBlockMapWithSuffrageProofs(proofs, t.proofs[0].Map()))
+	})
+
+	t.Run("signed by suffrage", func() {
+		t.NoError(VerifyBlockMapWithSuffrageProofs(proofs, t.blockMap(2, t.locals[1])))
+		t.NoError(VerifyBlockMapWithSuffrageProofs(proofs, t.blockMap(5, t.joined)))
+	})
+
+	t.Run("suffrage updated at height", func() {
+		t.NoError(VerifyBlockMapWithSuffrageProofs(proofs, t.blockMap(3, t.locals[2])))
+
+		err := VerifyBlockMapWithSuffrageProofs(proofs, t.blockMap(3, t.joined))
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "block map not signed by suffrage node")
+	})
+
+	t.Run("unknown signer", func() {
+		err := VerifyBlockMapWithSuffrageProofs(proofs, t.blockMap(4, base.RandomLocalNode()))
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "block map not signed by suffrage node")
+	})
+
+	t.Run("suffrage proof not found", func() {
+		err := VerifyBlockMapWithSuffrageProofs(proofs[:1], t.blockMap(4, t.locals[0]))
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "suffrage proof of manifest not found")
+	})
+}
+
+func TestSuffrageProofs(t *testing.T) {
+	suite.Run(t, new(testSuffrageProofs))
+}
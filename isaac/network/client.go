@@ -8,6 +8,7 @@ import (
 
 	"github.com/ProtoconNet/mitum2/base"
 	"github.com/ProtoconNet/mitum2/isaac"
+	isaaclightclient "github.com/ProtoconNet/mitum2/isaac/lightclient"
 	isaacstates "github.com/ProtoconNet/mitum2/isaac/states"
 	"github.com/ProtoconNet/mitum2/network/quicstream"
 	quicstreamheader "github.com/ProtoconNet/mitum2/network/quicstream/header"
@@ -19,6 +20,7 @@ import (
 type BaseClient struct {
 	Encoders *encoder.Encoders
 	Encoder  encoder.Encoder
+	connf    quicstream.ConnInfoDialFunc
 	dialf    quicstreamheader.DialFunc
 	closef   func() error
 	clientid string
@@ -33,11 +35,20 @@ func NewBaseClient(
 	return &BaseClient{
 		Encoders: encs,
 		Encoder:  enc,
-		dialf:    quicstreamheader.NewDialFunc(dialf, encs, enc),
+		connf:    dialf,
+		dialf:    quicstreamheader.NewDialFunc(dialf, encs, enc, nil),
 		closef:   closef,
 	}
 }
 
+// SetHeadExtensionFunc sets the function, which decides whether the request
+// uses the extended head; see quicstreamheader.NewDialFunc.
+func (c *BaseClient) SetHeadExtensionFunc(f func() bool) *BaseClient {
+	c.dialf = quicstreamheader.NewDialFunc(c.connf, c.Encoders, c.Encoder, f)
+
+	return c
+}
+
 func (c *BaseClient) ClientID() string {
 	return c.clientid
 }
@@ -539,11 +550,12 @@ func (c *BaseClient) NodeChallenge(
 	return c.nodeChallenge(
 		ctx, ci, networkID, node, nodePublickey, input, me,
 		func(input []byte) (base.Signature, error) {
-			return me.Privatekey().Sign(util.ConcatBytesSlice(
-				me.Address().Bytes(),
-				networkID,
-				input,
-			))
+			return base.PrivatekeyWithSignKind(me.Privatekey(), base.ChallengeSignKind, input).
+				Sign(util.ConcatBytesSlice(
+					me.Address().Bytes(),
+					networkID,
+					input,
+				))
 		},
 	)
 }
@@ -609,6 +621,92 @@ func (c *BaseClient) State(
 	return st, found, err
 }
 
+// StateProof requests the state with the proof; suffrageheight is the suffrage
+// height of the trusted suffrage proof of client. The request is allowed by
+// ACL of remote node; priv is used to verify client node.
+func (c *BaseClient) StateProof(
+	ctx context.Context, ci quicstream.ConnInfo, key string, suffrageheight base.Height,
+	priv base.Privatekey,
+	networkID base.NetworkID,
+) (sp isaaclightclient.StateProof, found bool, _ error) {
+	header := NewStateProofRequestHeader(key, suffrageheight, priv.Publickey())
+	header.SetClientID(c.ClientID())
+
+	if err := header.IsValid(nil); err != nil {
+		return sp, false, err
+	}
+
+	streamer, err := c.dial(ctx, ci)
+	if err != nil {
+		return sp, false, err
+	}
+
+	err = streamer(ctx, func(ctx context.Context, broker *quicstreamheader.ClientBroker) error {
+		if err := broker.WriteRequestHead(ctx, header); err != nil {
+			return err
+		}
+
+		if err := VerifyNode(ctx, broker, priv, networkID); err != nil {
+			return err
+		}
+
+		switch h, renc, body, err := hcResBody(ctx, broker); {
+		case err != nil:
+			return err
+		case h.Err() != nil:
+			return h.Err()
+		case !h.OK(), body == nil:
+			found = false
+
+			return nil
+		default:
+			found = true
+
+			return encoder.DecodeReader(renc, body, &sp)
+		}
+	})
+
+	return sp, found, err
+}
+
+// OperationProof requests the operation with the inclusion proof; height is the
+// height of block, which the operation was processed in.
+func (c *BaseClient) OperationProof(
+	ctx context.Context, ci quicstream.ConnInfo, facthash util.Hash, height base.Height,
+) (p isaaclightclient.OperationProof, found bool, _ error) {
+	header := NewOperationProofRequestHeader(facthash, height)
+	header.SetClientID(c.ClientID())
+
+	if err := header.IsValid(nil); err != nil {
+		return p, false, err
+	}
+
+	streamer, err := c.dial(ctx, ci)
+	if err != nil {
+		return p, false, err
+	}
+
+	err = streamer(ctx, func(ctx context.Context, broker *quicstreamheader.ClientBroker) error {
+		rfound, rerr := HCReqResBodyDecOK(
+			ctx,
+			broker,
+			header,
+			func(enc encoder.Encoder, r io.Reader) error {
+				return encoder.DecodeReader(enc, r, &p)
+			},
+		)
+		if rerr != nil {
+			return rerr
+		}
+
+		found = rfound
+
+		return nil
+	})
+
+	return p, found, err
+}
+
 func (c *BaseClient) ExistsInStateOperation(
 	ctx context.Context, ci quicstream.ConnInfo, facthash util.Hash,
 ) (found bool, _ error) {
@@ -1088,11 +1186,12 @@ func (*BaseClient) nodeChallengeVerifyMe(
 	default:
 		if signf == nil {
 			signf = func(input []byte) (base.Signature, error) { //revive:disable-line:modifies-parameter
-				return me.Privatekey().Sign(util.ConcatBytesSlice(
-					me.Address().Bytes(),
-					networkID,
-					input,
-				))
+				return base.PrivatekeyWithSignKind(me.Privatekey(), base.ChallengeSignKind, input).
+					Sign(util.ConcatBytesSlice(
+						me.Address().Bytes(),
+						networkID,
+						input,
+					))
 			}
 		}
 
@@ -1309,7 +1408,8 @@ func VerifyNode(
 		return errors.WithMessage(err, "read signature input")
 	}
 
-	switch sig, err := priv.Sign(util.ConcatBytesSlice(networkID, input)); {
+	switch sig, err := base.PrivatekeyWithSignKind(priv, base.ChallengeSignKind, input).
+		Sign(util.ConcatBytesSlice(networkID, input)); {
 	case err != nil:
 		return errors.WithMessage(err, "sign input")
 	default:
@@ -26,6 +26,8 @@ var (
 	SuffrageNodeConnInfoRequestHeaderHint   = hint.MustNewHint("suffrage-node-conninfo-header-v0.0.1")
 	SyncSourceConnInfoRequestHeaderHint     = hint.MustNewHint("sync-source-conninfo-header-v0.0.1")
 	StateRequestHeaderHint                  = hint.MustNewHint("state-header-v0.0.1")
+	StateProofRequestHeaderHint             = hint.MustNewHint("state-proof-header-v0.0.1")
+	OperationProofRequestHeaderHint         = hint.MustNewHint("operation-proof-header-v0.0.1")
 	ExistsInStateOperationRequestHeaderHint = hint.MustNewHint("exists-instate-operation-header-v0.0.1")
 	NodeInfoRequestHeaderHint               = hint.MustNewHint("node-info-header-v0.0.1")
 	SendBallotsHeaderHint                   = hint.MustNewHint("send-ballots-header-v0.0.1")
@@ -56,6 +58,8 @@ var (
 	HandlerNameOperation              quicstream.HandlerName = "operation"
 	HandlerNameSendOperation          quicstream.HandlerName = "send_operation"
 	HandlerNameState                  quicstream.HandlerName = "state"
+	HandlerNameStateProof             quicstream.HandlerName = "state_proof"
+	HandlerNameOperationProof         quicstream.HandlerName = "operation_proof"
 	HandlerNameExistsInStateOperation quicstream.HandlerName = "exists_instate_operation"
 	HandlerNameNodeInfo               quicstream.HandlerName = "node_info"
 	HandlerNameSendBallots            quicstream.HandlerName = "send_ballots"
@@ -82,6 +86,8 @@ var (
 	handlerPrefixOperation              = quicstream.HashPrefix(HandlerNameOperation)
 	handlerPrefixSendOperation          = quicstream.HashPrefix(HandlerNameSendOperation)
 	handlerPrefixState                  = quicstream.HashPrefix(HandlerNameState)
+	handlerPrefixStateProof             = quicstream.HashPrefix(HandlerNameStateProof)
+	handlerPrefixOperationProof         = quicstream.HashPrefix(HandlerNameOperationProof)
 	handlerPrefixExistsInStateOperation = quicstream.HashPrefix(HandlerNameExistsInStateOperation)
 	handlerPrefixNodeInfo               = quicstream.HashPrefix(HandlerNameNodeInfo)
 	handlerPrefixSendBallots            = quicstream.HashPrefix(HandlerNameSendBallots)
@@ -560,6 +566,93 @@ func (h StateRequestHeader) Hash() util.Hash {
 	return h.h
 }
 
+type StateProofRequestHeader struct {
+	aclUserHeader
+	key            string
+	suffrageheight base.Height
+	BaseHeader
+}
+
+func NewStateProofRequestHeader(
+	key string, suffrageheight base.Height, acluser base.Publickey,
+) StateProofRequestHeader {
+	return StateProofRequestHeader{
+		BaseHeader:     NewBaseHeader(StateProofRequestHeaderHint),
+		aclUserHeader:  newACLUserHeader(acluser),
+		key:            key,
+		suffrageheight: suffrageheight,
+	}
+}
+
+func (h StateProofRequestHeader) IsValid([]byte) error {
+	e := util.ErrInvalid.Errorf("invalid StateProofHeader")
+
+	if err := h.BaseHinter.IsValid(StateProofRequestHeaderHint.Type().Bytes()); err != nil {
+		return e.Wrap(err)
+	}
+
+	if len(h.key) < 1 {
+		return e.Errorf("empty state key")
+	}
+
+	if err := h.suffrageheight.IsValid(nil); err != nil {
+		return e.WithMessage(err, "invalid suffrage height")
+	}
+
+	if err := h.aclUserHeader.IsValid(nil); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
+
+func (h StateProofRequestHeader) Key() string {
+	return h.key
+}
+
+// SuffrageHeight is the suffrage height of the trusted suffrage proof of
+// client.
+func (h StateProofRequestHeader) SuffrageHeight() base.Height {
+	return h.suffrageheight
+}
+
+type OperationProofRequestHeader struct {
+	facthash util.Hash
+	height   base.Height
+	BaseHeader
+}
+
+func NewOperationProofRequestHeader(facthash util.Hash, height base.Height) OperationProofRequestHeader {
+	return OperationProofRequestHeader{
+		BaseHeader: NewBaseHeader(OperationProofRequestHeaderHint),
+		facthash:   facthash,
+		height:     height,
+	}
+}
+
+func (h OperationProofRequestHeader) IsValid([]byte) error {
+	e := util.ErrInvalid.Errorf("invalid OperationProofHeader")
+
+	if err := h.BaseHinter.IsValid(OperationProofRequestHeaderHint.Type().Bytes()); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := util.CheckIsValiders(nil, false, h.facthash, h.height); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
+
+func (h OperationProofRequestHeader) FactHash() util.Hash {
+	return h.facthash
+}
+
+// Height is the height of block, which the operation was processed in.
+func (h OperationProofRequestHeader) Height() base.Height {
+	return h.height
+}
+
 type ExistsInStateOperationRequestHeader struct {
 	facthash util.Hash
 	BaseHeader
@@ -964,6 +1057,10 @@ func headerPrefixByHint(ht hint.Hint) quicstream.HandlerPrefix {
 		return handlerPrefixSendOperation
 	case StateRequestHeaderHint.Type():
 		return handlerPrefixState
+	case StateProofRequestHeaderHint.Type():
+		return handlerPrefixStateProof
+	case OperationProofRequestHeaderHint.Type():
+		return handlerPrefixOperationProof
 	case ExistsInStateOperationRequestHeaderHint.Type():
 		return handlerPrefixExistsInStateOperation
 	case NodeInfoRequestHeaderHint.Type():
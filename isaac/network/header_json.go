@@ -528,6 +528,87 @@ func (h *StateRequestHeader) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+type stateProofRequestHeaderJSONMarshaler struct {
+	Key            string      `json:"key"`
+	SuffrageHeight base.Height `json:"suffrage_height"`
+}
+
+func (h StateProofRequestHeader) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(struct {
+		aclUserHeaderJSONMarshaler
+		stateProofRequestHeaderJSONMarshaler
+		BaseHeaderJSONMarshaler
+	}{
+		BaseHeaderJSONMarshaler:    h.BaseHeader.JSONMarshaler(),
+		aclUserHeaderJSONMarshaler: h.aclUserHeader.jsonMarshaler(),
+		stateProofRequestHeaderJSONMarshaler: stateProofRequestHeaderJSONMarshaler{
+			Key:            h.key,
+			SuffrageHeight: h.suffrageheight,
+		},
+	})
+}
+
+func (h *StateProofRequestHeader) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	e := util.StringError("unmarshal StateProofRequestHeader")
+
+	var u stateProofRequestHeaderJSONMarshaler
+
+	if err := util.UnmarshalJSON(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := util.UnmarshalJSON(b, &h.BaseHeader); err != nil {
+		return e.Wrap(err)
+	}
+
+	h.key = u.Key
+	h.suffrageheight = u.SuffrageHeight
+
+	return h.aclUserHeader.DecodeJSON(b, enc)
+}
+
+type operationProofRequestHeaderJSONMarshaler struct {
+	Fact   util.Hash   `json:"fact"`
+	Height base.Height `json:"height"`
+}
+
+func (h OperationProofRequestHeader) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(struct {
+		operationProofRequestHeaderJSONMarshaler
+		BaseHeaderJSONMarshaler
+	}{
+		BaseHeaderJSONMarshaler: h.BaseHeader.JSONMarshaler(),
+		operationProofRequestHeaderJSONMarshaler: operationProofRequestHeaderJSONMarshaler{
+			Fact:   h.facthash,
+			Height: h.height,
+		},
+	})
+}
+
+type operationProofRequestHeaderJSONUnmarshaler struct {
+	Fact   valuehash.HashDecoder `json:"fact"`
+	Height base.Height           `json:"height"`
+}
+
+func (h *OperationProofRequestHeader) UnmarshalJSON(b []byte) error {
+	e := util.StringError("unmarshal OperationProofRequestHeader")
+
+	var u operationProofRequestHeaderJSONUnmarshaler
+
+	if err := util.UnmarshalJSON(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := util.UnmarshalJSON(b, &h.BaseHeader); err != nil {
+		return e.Wrap(err)
+	}
+
+	h.facthash = u.Fact.Hash()
+	h.height = u.Height
+
+	return nil
+}
+
 type existsInStateOperationRequestHeaderJSONMarshaler struct {
 	Fact util.Hash `json:"fact"`
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/ProtoconNet/mitum2/base"
 	"github.com/ProtoconNet/mitum2/isaac"
 	isaacdatabase "github.com/ProtoconNet/mitum2/isaac/database"
+	isaaclightclient "github.com/ProtoconNet/mitum2/isaac/lightclient"
 	quicstreamheader "github.com/ProtoconNet/mitum2/network/quicstream/header"
 	"github.com/ProtoconNet/mitum2/util"
 	"github.com/ProtoconNet/mitum2/util/encoder"
@@ -63,9 +64,13 @@ func QuicstreamHandlerSendOperation(
 	broadcast func(context.Context, string, base.Operation, []byte) error,
 	maxMessageSize func() uint64,
 ) quicstreamheader.Handler[SendOperationRequestHeader] {
-	filterNewOperation := quicstreamHandlerFilterOperation(
+	newOperationf := NewOperationFunc(
+		networkID,
+		oppool,
 		existsInStateOperationf,
 		filterSendOperationf,
+		svvote,
+		broadcast,
 	)
 
 	return func(ctx context.Context, _ net.Addr,
@@ -103,30 +108,12 @@ func QuicstreamHandlerSendOperation(
 				return ctx, e.Errorf("empty operation found")
 			}
 
-			if err = op.IsValid(networkID); err != nil {
-				return ctx, e.Wrap(err)
-			}
-
 			body = i
 		}
 
-		if err := filterNewOperation(op); err != nil {
-			return ctx, e.Wrap(err)
-		}
-
-		var added bool
-
-		switch i, err := quicstreamHandlerSetOperation(ctx, oppool, svvote, op); {
-		case err != nil:
+		added, err := newOperationf(ctx, op, body)
+		if err != nil {
 			return ctx, e.Wrap(err)
-		case i && broadcast != nil:
-			go func() {
-				_ = broadcast(ctx, op.Hash().String(), op, body)
-			}()
-
-			added = i
-		default:
-			added = i
 		}
 
 		if err := broker.WriteResponseHeadOK(ctx, added, nil); err != nil {
@@ -378,6 +365,42 @@ func QuicstreamHandlerState(
 	)
 }
 
+func QuicstreamHandlerStateProof(
+	stateProoff func(key string, suffrageheight base.Height) (isaaclightclient.StateProof, bool, error),
+) quicstreamheader.Handler[StateProofRequestHeader] {
+	return boolEncodeQUICstreamHandler(
+		func(header StateProofRequestHeader) string {
+			return HandlerNameStateProof.String() + header.Key() + header.SuffrageHeight().String()
+		},
+		func(_ context.Context, header StateProofRequestHeader, _ encoder.Encoder) (interface{}, bool, error) {
+			switch sp, found, err := stateProoff(header.Key(), header.SuffrageHeight()); {
+			case err != nil, !found:
+				return nil, false, err
+			default:
+				return sp, true, nil
+			}
+		},
+	)
+}
+
+func QuicstreamHandlerOperationProof(
+	operationProoff func(facthash util.Hash, height base.Height) (isaaclightclient.OperationProof, bool, error),
+) quicstreamheader.Handler[OperationProofRequestHeader] {
+	return boolEncodeQUICstreamHandler(
+		func(header OperationProofRequestHeader) string {
+			return HandlerNameOperationProof.String() + header.FactHash().String() + header.Height().String()
+		},
+		func(_ context.Context, header OperationProofRequestHeader, _ encoder.Encoder) (interface{}, bool, error) {
+			switch p, found, err := operationProoff(header.FactHash(), header.Height()); {
+			case err != nil, !found:
+				return nil, false, err
+			default:
+				return p, true, nil
+			}
+		},
+	)
+}
+
 func QuicstreamHandlerExistsInStateOperation(
 	existsInStateOperationf func(util.Hash) (bool, error),
 ) quicstreamheader.Handler[ExistsInStateOperationRequestHeader] {
@@ -448,11 +471,12 @@ func QuicstreamHandlerNodeChallenge(
 	return quicstreamHandlerNodeChallenge(
 		networkID, local,
 		func(input []byte) (base.Signature, error) {
-			return local.Privatekey().Sign(util.ConcatBytesSlice(
-				local.Address().Bytes(),
-				networkID,
-				input,
-			))
+			return base.PrivatekeyWithSignKind(local.Privatekey(), base.ChallengeSignKind, input).
+				Sign(util.ConcatBytesSlice(
+					local.Address().Bytes(),
+					networkID,
+					input,
+				))
 		},
 	)
 }
@@ -470,11 +494,12 @@ func quicstreamHandlerNodeChallenge(
 		// verify node
 		if signf == nil {
 			signf = func(input []byte) (base.Signature, error) { //revive:disable-line:modifies-parameter
-				return local.Privatekey().Sign(util.ConcatBytesSlice(
-					local.Address().Bytes(),
-					networkID,
-					input,
-				))
+				return base.PrivatekeyWithSignKind(local.Privatekey(), base.ChallengeSignKind, input).
+					Sign(util.ConcatBytesSlice(
+						local.Address().Bytes(),
+						networkID,
+						input,
+					))
 			}
 		}
 
@@ -689,6 +714,47 @@ func QuicstreamHandlerStreamOperations(
 	}
 }
 
+// NewOperationFunc validates and filters the new operation, and sets it into
+// pool; if the operation is newly added, it is broadcasted. The operations from
+// the other paths, like gossip, also should be handled by NewOperationFunc
+// with the same filters of QuicstreamHandlerSendOperation.
+func NewOperationFunc(
+	networkID base.NetworkID,
+	oppool isaac.NewOperationPool,
+	existsInStateOperationf func(util.Hash) (bool, error),
+	filterSendOperationf func(base.Operation) (bool, error),
+	svvote isaac.SuffrageVoteFunc,
+	broadcast func(context.Context, string, base.Operation, []byte) error,
+) func(context.Context, base.Operation, []byte) (bool, error) {
+	filterNewOperation := quicstreamHandlerFilterOperation(
+		existsInStateOperationf,
+		filterSendOperationf,
+	)
+
+	return func(ctx context.Context, op base.Operation, body []byte) (bool, error) {
+		if err := op.IsValid(networkID); err != nil {
+			return false, err
+		}
+
+		if err := filterNewOperation(op); err != nil {
+			return false, err
+		}
+
+		switch added, err := quicstreamHandlerSetOperation(ctx, oppool, svvote, op); {
+		case err != nil:
+			return false, err
+		case added && broadcast != nil:
+			go func() {
+				_ = broadcast(ctx, op.Hash().String(), op, body)
+			}()
+
+			return true, nil
+		default:
+			return added, nil
+		}
+	}
+}
+
 func quicstreamHandlerFilterOperation(
 	existsInStateOperationf func(util.Hash) (bool, error),
 	filterSendOperationf func(base.Operation) (bool, error),
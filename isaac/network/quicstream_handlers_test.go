@@ -13,11 +13,14 @@ import (
 	"github.com/ProtoconNet/mitum2/base"
 	"github.com/ProtoconNet/mitum2/isaac"
 	isaacdatabase "github.com/ProtoconNet/mitum2/isaac/database"
+	isaaclightclient "github.com/ProtoconNet/mitum2/isaac/lightclient"
 	isaacstates "github.com/ProtoconNet/mitum2/isaac/states"
 	"github.com/ProtoconNet/mitum2/network/quicstream"
 	quicstreamheader "github.com/ProtoconNet/mitum2/network/quicstream/header"
 	"github.com/ProtoconNet/mitum2/util"
 	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/ProtoconNet/mitum2/util/fixedtree"
+	"github.com/ProtoconNet/mitum2/util/localtime"
 	"github.com/ProtoconNet/mitum2/util/valuehash"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/suite"
@@ -53,6 +56,8 @@ func (t *testQuicstreamHandlers) SetupSuite() {
 	t.NoError(t.Enc.Add(encoder.DecodeDetail{Hint: SuffrageProofRequestHeaderHint, Instance: SuffrageProofRequestHeader{}}))
 	t.NoError(t.Enc.Add(encoder.DecodeDetail{Hint: SyncSourceConnInfoRequestHeaderHint, Instance: SyncSourceConnInfoRequestHeader{}}))
 	t.NoError(t.Enc.Add(encoder.DecodeDetail{Hint: StateRequestHeaderHint, Instance: StateRequestHeader{}}))
+	t.NoError(t.Enc.Add(encoder.DecodeDetail{Hint: StateProofRequestHeaderHint, Instance: StateProofRequestHeader{}}))
+	t.NoError(t.Enc.Add(encoder.DecodeDetail{Hint: OperationProofRequestHeaderHint, Instance: OperationProofRequestHeader{}}))
 	t.NoError(t.Enc.Add(encoder.DecodeDetail{Hint: ExistsInStateOperationRequestHeaderHint, Instance: ExistsInStateOperationRequestHeader{}}))
 	t.NoError(t.Enc.Add(encoder.DecodeDetail{Hint: SendBallotsHeaderHint, Instance: SendBallotsHeader{}}))
 	t.NoError(t.Enc.Add(encoder.DecodeDetail{Hint: SetAllowConsensusHeaderHint, Instance: SetAllowConsensusHeader{}}))
@@ -74,6 +79,13 @@ func (t *testQuicstreamHandlers) SetupSuite() {
 	t.NoError(t.Enc.Add(encoder.DecodeDetail{Hint: isaac.SuffrageExpelFactHint, Instance: isaac.SuffrageExpelFact{}}))
 	t.NoError(t.Enc.Add(encoder.DecodeDetail{Hint: isaac.INITBallotSignFactHint, Instance: isaac.INITBallotSignFact{}}))
 	t.NoError(t.Enc.Add(encoder.DecodeDetail{Hint: isaac.INITBallotFactHint, Instance: isaac.INITBallotFact{}}))
+	t.NoError(t.Enc.Add(encoder.DecodeDetail{Hint: isaac.ACCEPTBallotSignFactHint, Instance: isaac.ACCEPTBallotSignFact{}}))
+	t.NoError(t.Enc.Add(encoder.DecodeDetail{Hint: isaac.ACCEPTBallotFactHint, Instance: isaac.ACCEPTBallotFact{}}))
+	t.NoError(t.Enc.Add(encoder.DecodeDetail{Hint: isaac.ACCEPTVoteproofHint, Instance: isaac.ACCEPTVoteproof{}}))
+	t.NoError(t.Enc.Add(encoder.DecodeDetail{Hint: isaac.ManifestHint, Instance: isaac.Manifest{}}))
+	t.NoError(t.Enc.Add(encoder.DecodeDetail{Hint: isaaclightclient.StateProofHint, Instance: isaaclightclient.StateProof{}}))
+	t.NoError(t.Enc.Add(encoder.DecodeDetail{Hint: isaaclightclient.OperationProofHint, Instance: isaaclightclient.OperationProof{}}))
+	t.NoError(t.Enc.Add(encoder.DecodeDetail{Hint: base.BaseOperationProcessReasonErrorHint, Instance: base.BaseOperationProcessReasonError{}}))
 }
 
 func (t *testQuicstreamHandlers) TestClient() {
@@ -324,6 +336,123 @@ func (t *testQuicstreamHandlers) TestSendOperation() {
 		t.False(updated)
 		t.ErrorContains(err, "filtered")
 	})
+
+	t.Run("already in state; not broadcasted", func() {
+		_ = pool.Clean()
+
+		ch := make(chan []byte, 1)
+		handler := QuicstreamHandlerSendOperation(t.LocalParams.NetworkID(), pool,
+			func(util.Hash) (bool, error) { return true, nil },
+			func(base.Operation) (bool, error) { return true, nil },
+			nil,
+			func(_ context.Context, _ string, _ base.Operation, b []byte) error {
+				ch <- b
+
+				return nil
+			},
+			func() uint64 { return 1 << 18 },
+		)
+
+		_, dialf := TestingDialFunc(t.Encs, HandlerNameSendOperation, handler)
+
+		c := NewBaseClient(t.Encs, t.Enc, dialf, func() error { return nil })
+
+		updated, err := c.SendOperation(context.Background(), ci, op)
+		t.Error(err)
+		t.False(updated)
+		t.ErrorContains(err, "already in state")
+
+		select {
+		case <-time.After(time.Millisecond * 300):
+		case <-ch:
+			t.Fail("operation in state should not be broadcasted")
+		}
+
+		_, found, err := pool.Operation(context.Background(), op.Hash())
+		t.NoError(err)
+		t.False(found)
+	})
+}
+
+func (t *testQuicstreamHandlers) TestNewOperationFunc() {
+	pool := t.NewPool()
+	defer pool.DeepClose()
+
+	newop := func() base.Operation {
+		fact := isaac.NewDummyOperationFact(util.UUID().Bytes(), valuehash.RandomSHA256())
+		op, err := isaac.NewDummyOperation(fact, t.Local.Privatekey(), t.LocalParams.NetworkID())
+		t.NoError(err)
+
+		return op
+	}
+
+	var inState, passed bool
+
+	ch := make(chan base.Operation, 1)
+
+	f := NewOperationFunc(t.LocalParams.NetworkID(), pool,
+		func(util.Hash) (bool, error) { return inState, nil },
+		func(base.Operation) (bool, error) { return passed, nil },
+		nil,
+		func(_ context.Context, _ string, op base.Operation, _ []byte) error {
+			ch <- op
+
+			return nil
+		},
+	)
+
+	waitBroadcasted := func(op base.Operation) bool {
+		select {
+		case <-time.After(time.Millisecond * 300):
+			return false
+		case rop := <-ch:
+			t.True(op.Hash().Equal(rop.Hash()))
+
+			return true
+		}
+	}
+
+	t.Run("ok", func() {
+		inState, passed = false, true
+
+		op := newop()
+
+		added, err := f(context.Background(), op, nil)
+		t.NoError(err)
+		t.True(added)
+		t.True(waitBroadcasted(op))
+
+		t.Run("again", func() {
+			added, err := f(context.Background(), op, nil)
+			t.NoError(err)
+			t.False(added)
+			t.False(waitBroadcasted(op))
+		})
+	})
+
+	t.Run("in state", func() {
+		inState, passed = true, true
+
+		op := newop()
+
+		added, err := f(context.Background(), op, nil)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrFound)
+		t.False(added)
+		t.False(waitBroadcasted(op))
+	})
+
+	t.Run("filtered", func() {
+		inState, passed = false, false
+
+		op := newop()
+
+		added, err := f(context.Background(), op, nil)
+		t.Error(err)
+		t.ErrorContains(err, "filtered")
+		t.False(added)
+		t.False(waitBroadcasted(op))
+	})
 }
 
 func (t *testQuicstreamHandlers) TestStreamOperations() {
@@ -1439,6 +1568,205 @@ func (t *testQuicstreamHandlers) TestState() {
 	})
 }
 
+func (t *testQuicstreamHandlers) TestStateProof() {
+	st := base.NewBaseState(
+		base.Height(33),
+		util.UUID().String(),
+		base.NewDummyStateValue(util.UUID().String()),
+		valuehash.RandomSHA256(),
+		[]util.Hash{valuehash.RandomSHA256(), valuehash.RandomSHA256()},
+	)
+
+	w, err := fixedtree.NewWriter(base.StateFixedtreeHint, 1)
+	t.NoError(err)
+	t.NoError(w.Add(0, fixedtree.NewBaseNode(st.Hash().String())))
+	t.NoError(w.Write(func(uint64, fixedtree.Node) error { return nil }))
+
+	tr, err := w.Tree()
+	t.NoError(err)
+
+	proof, err := tr.Proof(st.Hash().String())
+	t.NoError(err)
+
+	manifest := isaac.NewManifest(st.Height(), valuehash.RandomSHA256(), valuehash.RandomSHA256(), nil, tr.Root(), valuehash.RandomSHA256(), localtime.Now().UTC())
+
+	afact := t.NewACCEPTBallotFact(base.NewPoint(st.Height(), 0), nil, manifest.Hash())
+	avp, err := t.NewACCEPTVoteproof(afact, t.Local, []base.LocalNode{t.Local})
+	t.NoError(err)
+
+	sp := isaaclightclient.NewStateProof(st, proof, manifest, avp, nil)
+	t.NoError(sp.IsValid(t.LocalParams.NetworkID()))
+
+	ci := quicstream.UnsafeConnInfo(nil, true)
+
+	var aclhandler quicstreamheader.Handler[StateProofRequestHeader] = func(ctx context.Context, addr net.Addr, broker *quicstreamheader.HandlerBroker, header StateProofRequestHeader) (context.Context, error) {
+		err := QuicstreamHandlerVerifyNode(
+			ctx, addr, broker,
+			t.Local.Publickey(), t.LocalParams.NetworkID(),
+		)
+		return ctx, err
+	}
+
+	t.Run("ok", func() {
+		var rsufheight base.Height
+
+		handler := QuicstreamHandlerStateProof(
+			func(key string, suffrageheight base.Height) (isaaclightclient.StateProof, bool, error) {
+				rsufheight = suffrageheight
+
+				return sp, true, nil
+			},
+		)
+		_, dialf := TestingDialFunc(t.Encs, HandlerNameStateProof, aclhandler.Handler(handler))
+
+		c := NewBaseClient(t.Encs, t.Enc, dialf, func() error { return nil })
+
+		usp, found, err := c.StateProof(context.Background(), ci, st.Key(), base.Height(3), t.Local.Privatekey(), t.LocalParams.NetworkID())
+		t.NoError(err)
+		t.True(found)
+		t.Equal(base.Height(3), rsufheight)
+
+		t.NoError(usp.IsValid(t.LocalParams.NetworkID()))
+		t.True(base.IsEqualState(st, usp.State()))
+		base.EqualManifest(t.Assert(), manifest, usp.Manifest())
+	})
+
+	t.Run("not found", func() {
+		handler := QuicstreamHandlerStateProof(
+			func(string, base.Height) (isaaclightclient.StateProof, bool, error) {
+				return isaaclightclient.StateProof{}, false, nil
+			},
+		)
+		_, dialf := TestingDialFunc(t.Encs, HandlerNameStateProof, aclhandler.Handler(handler))
+
+		c := NewBaseClient(t.Encs, t.Enc, dialf, func() error { return nil })
+
+		_, found, err := c.StateProof(context.Background(), ci, st.Key(), base.GenesisHeight, t.Local.Privatekey(), t.LocalParams.NetworkID())
+		t.NoError(err)
+		t.False(found)
+	})
+
+	t.Run("error", func() {
+		handler := QuicstreamHandlerStateProof(
+			func(string, base.Height) (isaaclightclient.StateProof, bool, error) {
+				return isaaclightclient.StateProof{}, false, errors.Errorf("hehehe")
+			},
+		)
+		_, dialf := TestingDialFunc(t.Encs, HandlerNameStateProof, aclhandler.Handler(handler))
+
+		c := NewBaseClient(t.Encs, t.Enc, dialf, func() error { return nil })
+
+		_, found, err := c.StateProof(context.Background(), ci, st.Key(), base.GenesisHeight, t.Local.Privatekey(), t.LocalParams.NetworkID())
+		t.Error(err)
+		t.False(found)
+		t.ErrorContains(err, "hehehe")
+	})
+
+	t.Run("verify node failed", func() {
+		handler := QuicstreamHandlerStateProof(
+			func(string, base.Height) (isaaclightclient.StateProof, bool, error) {
+				return sp, true, nil
+			},
+		)
+		_, dialf := TestingDialFunc(t.Encs, HandlerNameStateProof, aclhandler.Handler(handler))
+
+		c := NewBaseClient(t.Encs, t.Enc, dialf, func() error { return nil })
+
+		_, found, err := c.StateProof(context.Background(), ci, st.Key(), base.GenesisHeight, base.NewMPrivatekey(), t.LocalParams.NetworkID())
+		t.Error(err)
+		t.False(found)
+	})
+}
+
+func (t *testQuicstreamHandlers) TestOperationProof() {
+	facthash := valuehash.RandomSHA256()
+	height := base.Height(33)
+
+	w, err := fixedtree.NewWriter(base.OperationFixedtreeHint, 1)
+	t.NoError(err)
+	t.NoError(w.Add(0, base.NewNotInStateOperationFixedtreeNode(facthash, "hihihi")))
+	t.NoError(w.Write(func(uint64, fixedtree.Node) error { return nil }))
+
+	tr, err := w.Tree()
+	t.NoError(err)
+
+	node := tr.Node(0).(base.OperationFixedtreeNode) //nolint:forcetypeassert //...
+
+	proof, err := tr.Proof(node.Key())
+	t.NoError(err)
+
+	manifest := isaac.NewManifest(height, valuehash.RandomSHA256(), valuehash.RandomSHA256(), tr.Root(), nil, valuehash.RandomSHA256(), localtime.Now().UTC())
+
+	afact := t.NewACCEPTBallotFact(base.NewPoint(height, 0), nil, manifest.Hash())
+	avp, err := t.NewACCEPTVoteproof(afact, t.Local, []base.LocalNode{t.Local})
+	t.NoError(err)
+
+	op := isaaclightclient.NewOperationProof(node, proof, manifest, avp)
+	t.NoError(op.IsValid(t.LocalParams.NetworkID()))
+
+	ci := quicstream.UnsafeConnInfo(nil, true)
+
+	t.Run("ok", func() {
+		var rfacthash util.Hash
+		var rheight base.Height
+
+		handler := QuicstreamHandlerOperationProof(
+			func(facthash util.Hash, height base.Height) (isaaclightclient.OperationProof, bool, error) {
+				rfacthash = facthash
+				rheight = height
+
+				return op, true, nil
+			},
+		)
+		_, dialf := TestingDialFunc(t.Encs, HandlerNameOperationProof, handler)
+
+		c := NewBaseClient(t.Encs, t.Enc, dialf, func() error { return nil })
+
+		uop, found, err := c.OperationProof(context.Background(), ci, facthash, height)
+		t.NoError(err)
+		t.True(found)
+		t.True(facthash.Equal(rfacthash))
+		t.Equal(height, rheight)
+
+		t.NoError(uop.IsValid(t.LocalParams.NetworkID()))
+		t.True(facthash.Equal(uop.Node().Operation()))
+		t.False(uop.Node().InState())
+		t.Nil(uop.Node().Reason())
+		base.EqualManifest(t.Assert(), manifest, uop.Manifest())
+	})
+
+	t.Run("not found", func() {
+		handler := QuicstreamHandlerOperationProof(
+			func(util.Hash, base.Height) (isaaclightclient.OperationProof, bool, error) {
+				return isaaclightclient.OperationProof{}, false, nil
+			},
+		)
+		_, dialf := TestingDialFunc(t.Encs, HandlerNameOperationProof, handler)
+
+		c := NewBaseClient(t.Encs, t.Enc, dialf, func() error { return nil })
+
+		_, found, err := c.OperationProof(context.Background(), ci, facthash, height)
+		t.NoError(err)
+		t.False(found)
+	})
+
+	t.Run("error", func() {
+		handler := QuicstreamHandlerOperationProof(
+			func(util.Hash, base.Height) (isaaclightclient.OperationProof, bool, error) {
+				return isaaclightclient.OperationProof{}, false, errors.Errorf("hehehe")
+			},
+		)
+		_, dialf := TestingDialFunc(t.Encs, HandlerNameOperationProof, handler)
+
+		c := NewBaseClient(t.Encs, t.Enc, dialf, func() error { return nil })
+
+		_, found, err := c.OperationProof(context.Background(), ci, facthash, height)
+		t.Error(err)
+		t.False(found)
+		t.ErrorContains(err, "hehehe")
+	})
+}
+
 func (t *testQuicstreamHandlers) TestExistsInStateOperation() {
 	ci := quicstream.UnsafeConnInfo(nil, true)
 
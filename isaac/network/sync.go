@@ -29,6 +29,9 @@ var (
 	SyncSourceTypeSuffrageNodes SyncSourceType = "sync-source-suffrage-nodes"
 	SyncSourceTypeSyncSources   SyncSourceType = "sync-source-sync-sources"
 	SyncSourceTypeURL           SyncSourceType = "sync-source-url"
+	// SyncSourceTypeMirror is the static block mirror, not node; the blocks
+	// are read from the mirror directly by syncer.
+	SyncSourceTypeMirror SyncSourceType = "sync-source-mirror"
 )
 
 type SyncSource struct {
@@ -43,7 +46,8 @@ func (s SyncSource) IsValid([]byte) error {
 	case SyncSourceTypeNode,
 		SyncSourceTypeSuffrageNodes,
 		SyncSourceTypeSyncSources,
-		SyncSourceTypeURL:
+		SyncSourceTypeURL,
+		SyncSourceTypeMirror:
 	default:
 		return e.Errorf("unknown sync source type, %q", s.Type)
 	}
@@ -76,13 +80,18 @@ func (s SyncSource) IsValid([]byte) error {
 			return e.Errorf("invalid type for NamedConnInfo, %v", s.Type)
 		}
 	case *url.URL:
-		if s.Type != SyncSourceTypeURL {
+		switch s.Type {
+		case SyncSourceTypeURL:
+			if err := util.IsValidURL(t); err != nil {
+				return e.Wrap(err)
+			}
+		case SyncSourceTypeMirror:
+			if err := isaac.IsValidBlockMirrorURL(t); err != nil {
+				return e.Wrap(err)
+			}
+		default:
 			return e.Errorf("invalid type for url, %v", s.Type)
 		}
-
-		if err := util.IsValidURL(t); err != nil {
-			return e.Wrap(err)
-		}
 	}
 
 	return nil
@@ -271,6 +280,8 @@ func (c *SyncSourceChecker) fetch(ctx context.Context, source SyncSource) (ncis
 		ncis, err = c.fetchFromSyncSources(ctx, source.Source)
 	case SyncSourceTypeURL:
 		ncis, err = c.fetchFromURL(ctx, source.Source.(*url.URL))
+	case SyncSourceTypeMirror:
+		return nil, nil
 	default:
 		return nil, e.Errorf("unsupported source type, %q", source.Type)
 	}
@@ -100,6 +100,16 @@ func (d *SyncSource) decodeYAMLMap(t string, b []byte, jsonencoder encoder.Encod
 		d.Type = ty
 		d.Source = i
 
+		return nil
+	case SyncSourceTypeMirror:
+		i, err := d.decodeYAMLMirror(b)
+		if err != nil {
+			return err
+		}
+
+		d.Type = ty
+		d.Source = i
+
 		return nil
 	default:
 		return errors.Errorf("unsupported type, %q", ty)
@@ -161,3 +171,28 @@ func (SyncSource) decodeYAMLConnInfo(
 
 	return quicmemberlist.NewNamedConnInfo(u.Publish, u.TLSInsecure)
 }
+
+type syncSourceMirrorUnmarshaler struct {
+	URL string `yaml:"url"`
+}
+
+func (SyncSource) decodeYAMLMirror(b []byte) (*url.URL, error) {
+	e := util.StringError("decode mirror of SyncSource")
+
+	var u syncSourceMirrorUnmarshaler
+
+	if err := yaml.Unmarshal(b, &u); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	i, err := url.Parse(u.URL)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	if err := isaac.IsValidBlockMirrorURL(i); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	return i, nil
+}
@@ -102,7 +102,7 @@ func (p *SuffrageDisjoinProcessor) PreProcess(ctx context.Context, op base.Opera
 		return ctx, base.NewBaseOperationProcessReasonError("not in suffrage, %q", n), nil
 	case fact.Start() != stv.Start():
 		return ctx, base.NewBaseOperationProcessReasonError("start does not match"), nil
-	case !signer.Equal(stv.Publickey()):
+	case !signer.Equal(isaac.SuffrageNodePublickey(stv, p.Height())):
 		return ctx, base.NewBaseOperationProcessReasonError("not signed by node key"), nil
 	}
 
@@ -210,6 +210,7 @@ type SuffrageJoinStateValueMerger struct {
 	existing  base.SuffrageNodesStateValue
 	joined    []base.Node
 	disjoined []base.Address
+	rotated   []suffrageKeyRotateNodeStateValue
 	sync.Mutex
 }
 
@@ -232,6 +233,8 @@ func (s *SuffrageJoinStateValueMerger) Merge(value base.StateValue, op util.Hash
 		s.joined = append(s.joined, t.nodes...)
 	case suffrageDisjoinNodeStateValue:
 		s.disjoined = append(s.disjoined, t.node)
+	case suffrageKeyRotateNodeStateValue:
+		s.rotated = append(s.rotated, t)
 	default:
 		return errors.Errorf("unsupported suffrage state value, %T", value)
 	}
@@ -256,7 +259,7 @@ func (s *SuffrageJoinStateValueMerger) CloseValue() (base.State, error) {
 }
 
 func (s *SuffrageJoinStateValueMerger) closeValue() (base.StateValue, error) {
-	if len(s.disjoined) < 1 && len(s.joined) < 1 {
+	if len(s.disjoined) < 1 && len(s.joined) < 1 && len(s.rotated) < 1 {
 		return nil, base.ErrIgnoreStateValue.Errorf("no nodes changes")
 	}
 
@@ -272,6 +275,8 @@ func (s *SuffrageJoinStateValueMerger) closeValue() (base.StateValue, error) {
 		)
 	}
 
+	existingnodes = s.rotateKeys(existingnodes)
+
 	if len(s.joined) > 0 {
 		sort.Slice(s.joined, func(i, j int) bool { // NOTE sort by address
 			return strings.Compare(s.joined[i].Address().String(), s.joined[j].Address().String()) < 0
@@ -291,6 +296,38 @@ func (s *SuffrageJoinStateValueMerger) closeValue() (base.StateValue, error) {
 	), nil
 }
 
+// rotateKeys sets the new key rotations and replaces the publickey of the
+// nodes, whose rotation is already effective from the next height.
+func (s *SuffrageJoinStateValueMerger) rotateKeys(
+	nodes []base.SuffrageNodeStateValue,
+) []base.SuffrageNodeStateValue {
+	rotated := map[string]suffrageKeyRotateNodeStateValue{}
+
+	for i := range s.rotated {
+		rotated[s.rotated[i].node.String()] = s.rotated[i]
+	}
+
+	newnodes := make([]base.SuffrageNodeStateValue, len(nodes))
+
+	for i := range nodes {
+		n := nodes[i]
+		newnodes[i] = n
+
+		if pub := isaac.SuffrageNodePublickey(n, s.Height()+1); !pub.Equal(n.Publickey()) {
+			n = isaac.NewSuffrageNodeStateValue(isaac.NewNode(pub, n.Address()), n.Start())
+			newnodes[i] = n
+		}
+
+		if r, found := rotated[n.Address().String()]; found {
+			newnodes[i] = isaac.NewSuffrageNodeStateValue(
+				isaac.NewNode(n.Publickey(), n.Address()), n.Start(),
+			).SetKeyRotation(r.publickey, r.height)
+		}
+	}
+
+	return newnodes
+}
+
 type suffrageJoinNodeStateValue struct {
 	nodes []base.Node
 }
@@ -0,0 +1,164 @@
+package isaacoperation
+
+import (
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/hint"
+	"github.com/ProtoconNet/mitum2/util/valuehash"
+)
+
+var (
+	SuffrageKeyRotateFactHint = hint.MustNewHint("suffrage-key-rotate-fact-v0.0.1")
+	SuffrageKeyRotateHint     = hint.MustNewHint("suffrage-key-rotate-operation-v0.0.1")
+)
+
+// SuffrageKeyRotateFact replaces the publickey of suffrage node with the new
+// publickey from the given height.
+type SuffrageKeyRotateFact struct {
+	node      base.Address
+	publickey base.Publickey
+	base.BaseFact
+	height base.Height
+}
+
+func NewSuffrageKeyRotateFact(
+	token base.Token,
+	node base.Address,
+	publickey base.Publickey,
+	height base.Height,
+) SuffrageKeyRotateFact {
+	fact := SuffrageKeyRotateFact{
+		BaseFact:  base.NewBaseFact(SuffrageKeyRotateFactHint, token),
+		node:      node,
+		publickey: publickey,
+		height:    height,
+	}
+
+	fact.SetHash(fact.hash())
+
+	return fact
+}
+
+func (fact SuffrageKeyRotateFact) IsValid([]byte) error {
+	e := util.ErrInvalid.Errorf("invalid SuffrageKeyRotateFact")
+
+	if err := util.CheckIsValiders(nil, false, fact.BaseFact, fact.node, fact.publickey, fact.height); err != nil {
+		return e.Wrap(err)
+	}
+
+	if fact.height <= base.GenesisHeight {
+		return e.Errorf("height should be over genesis height")
+	}
+
+	if !fact.Hash().Equal(fact.hash()) {
+		return e.Errorf("hash does not match")
+	}
+
+	return nil
+}
+
+func (fact SuffrageKeyRotateFact) Node() base.Address {
+	return fact.node
+}
+
+func (fact SuffrageKeyRotateFact) Publickey() base.Publickey {
+	return fact.publickey
+}
+
+// Height is the first height, which the new publickey is used.
+func (fact SuffrageKeyRotateFact) Height() base.Height {
+	return fact.height
+}
+
+func (fact SuffrageKeyRotateFact) hash() util.Hash {
+	return valuehash.NewSHA256(util.ConcatByters(
+		util.BytesToByter(fact.Token()),
+		fact.node,
+		fact.publickey,
+		fact.height,
+	))
+}
+
+// SuffrageKeyRotate should be signed by both of the current publickey and the
+// new publickey.
+type SuffrageKeyRotate struct {
+	base.BaseOperation
+}
+
+func NewSuffrageKeyRotate(fact SuffrageKeyRotateFact) SuffrageKeyRotate {
+	return SuffrageKeyRotate{
+		BaseOperation: base.NewBaseOperation(SuffrageKeyRotateHint, fact),
+	}
+}
+
+func (op *SuffrageKeyRotate) SetToken(t base.Token) error {
+	fact := op.Fact().(SuffrageKeyRotateFact) //nolint:forcetypeassert //...
+
+	if err := fact.SetToken(t); err != nil {
+		return err
+	}
+
+	fact.SetHash(fact.hash())
+
+	op.BaseOperation.SetFact(fact)
+
+	return nil
+}
+
+func (op SuffrageKeyRotate) IsValid(networkID []byte) error {
+	e := util.ErrInvalid.Errorf("invalid SuffrageKeyRotate")
+
+	if err := op.BaseOperation.IsValid(networkID); err != nil {
+		return e.Wrap(err)
+	}
+
+	var fact SuffrageKeyRotateFact
+	if err := util.SetInterfaceValue(op.Fact(), &fact); err != nil {
+		return e.Wrap(err)
+	}
+
+	sfs := op.Signs()
+
+	switch {
+	case len(sfs) != 2:
+		return e.Errorf("2 signs required, but %d", len(sfs))
+	case sfs[0].Signer().Equal(sfs[1].Signer()):
+		return e.Errorf("duplicated signs found")
+	}
+
+	if _, found := op.previousSign(fact.Publickey()); !found {
+		return e.Errorf("not signed by new publickey")
+	}
+
+	return nil
+}
+
+// PreviousSigner returns the signer of current publickey.
+func (op SuffrageKeyRotate) PreviousSigner() base.Publickey {
+	fact, ok := op.Fact().(SuffrageKeyRotateFact)
+	if !ok {
+		return nil
+	}
+
+	sign, _ := op.previousSign(fact.Publickey())
+	if sign == nil {
+		return nil
+	}
+
+	return sign.Signer()
+}
+
+func (op SuffrageKeyRotate) previousSign(pub base.Publickey) (sign base.Sign, foundnew bool) {
+	sfs := op.Signs()
+
+	for i := range sfs {
+		switch {
+		case sfs[i].Signer().Equal(pub):
+			foundnew = true
+		default:
+			sign = sfs[i]
+		}
+	}
+
+	return sign, foundnew
+}
@@ -0,0 +1,59 @@
+package isaacoperation
+
+import (
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+)
+
+type suffrageKeyRotateFactJSONMarshaler struct {
+	Node      base.Address   `json:"node"`
+	Publickey base.Publickey `json:"publickey"`
+	base.BaseFactJSONMarshaler
+	Height base.Height `json:"height"`
+}
+
+func (fact SuffrageKeyRotateFact) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(suffrageKeyRotateFactJSONMarshaler{
+		BaseFactJSONMarshaler: fact.BaseFact.JSONMarshaler(),
+		Node:                  fact.node,
+		Publickey:             fact.publickey,
+		Height:                fact.height,
+	})
+}
+
+type suffrageKeyRotateFactJSONUnmarshaler struct {
+	Node      string `json:"node"`
+	Publickey string `json:"publickey"`
+	base.BaseFactJSONUnmarshaler
+	Height base.Height `json:"height"`
+}
+
+func (fact *SuffrageKeyRotateFact) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	e := util.StringError("decode SuffrageKeyRotateFact")
+
+	var u suffrageKeyRotateFactJSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	fact.BaseFact.SetJSONUnmarshaler(u.BaseFactJSONUnmarshaler)
+
+	switch i, err := base.DecodeAddress(u.Node, enc); {
+	case err != nil:
+		return e.Wrap(err)
+	default:
+		fact.node = i
+	}
+
+	switch i, err := base.DecodePublickeyFromString(u.Publickey, enc); {
+	case err != nil:
+		return e.Wrap(err)
+	default:
+		fact.publickey = i
+	}
+
+	fact.height = u.Height
+
+	return nil
+}
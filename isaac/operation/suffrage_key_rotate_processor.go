@@ -0,0 +1,179 @@
+package isaacoperation
+
+import (
+	"context"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	"github.com/ProtoconNet/mitum2/util"
+)
+
+type SuffrageKeyRotateProcessor struct {
+	*base.BaseOperationProcessor
+	suffrage     map[string]base.SuffrageNodeStateValue
+	preprocessed map[string]struct{} //revive:disable-line:nested-structs
+}
+
+func NewSuffrageKeyRotateProcessor(
+	height base.Height,
+	getStateFunc base.GetStateFunc,
+	newPreProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+	newProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+) (*SuffrageKeyRotateProcessor, error) {
+	e := util.StringError("create new SuffrageKeyRotateProcessor")
+
+	b, err := base.NewBaseOperationProcessor(
+		height, getStateFunc, newPreProcessConstraintFunc, newProcessConstraintFunc)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	p := &SuffrageKeyRotateProcessor{
+		BaseOperationProcessor: b,
+		suffrage:               map[string]base.SuffrageNodeStateValue{},
+		preprocessed:           map[string]struct{}{},
+	}
+
+	switch i, found, err := getStateFunc(isaac.SuffrageStateKey); {
+	case err != nil:
+		return nil, e.Wrap(err)
+	case !found, i == nil:
+		return nil, e.Errorf("empty state")
+	default:
+		sufstv := i.Value().(base.SuffrageNodesStateValue) //nolint:forcetypeassert //...
+
+		snodes := sufstv.Nodes()
+
+		for i := range snodes {
+			node := snodes[i]
+
+			p.suffrage[node.Address().String()] = node
+		}
+	}
+
+	return p, nil
+}
+
+func (p *SuffrageKeyRotateProcessor) Close() error {
+	if err := p.BaseOperationProcessor.Close(); err != nil {
+		return err
+	}
+
+	clear(p.suffrage)
+	clear(p.preprocessed)
+
+	return nil
+}
+
+func (p *SuffrageKeyRotateProcessor) PreProcess(
+	ctx context.Context, op base.Operation, getStateFunc base.GetStateFunc,
+) (context.Context, base.OperationProcessReasonError, error) {
+	e := util.StringError("preprocess for SuffrageKeyRotate")
+
+	var rop SuffrageKeyRotate
+	if err := util.SetInterfaceValue(op, &rop); err != nil {
+		return ctx, nil, e.Wrap(err)
+	}
+
+	fact := op.Fact().(SuffrageKeyRotateFact) //nolint:forcetypeassert //...
+	n := fact.Node()
+
+	if _, found := p.preprocessed[n.String()]; found {
+		return ctx, base.NewBaseOperationProcessReasonError("already preprocessed, %q", n), nil
+	}
+
+	if fact.Height() <= p.Height()+1 {
+		return ctx, base.NewBaseOperationProcessReasonError(
+			"rotate height should be over next height, %d", p.Height()+1), nil
+	}
+
+	stv, found := p.suffrage[n.String()]
+	if !found {
+		return ctx, base.NewBaseOperationProcessReasonError("not in suffrage, %q", n), nil
+	}
+
+	if i, ok := stv.(isaac.SuffrageNodeKeyRotator); ok {
+		if pub, height := i.KeyRotation(); pub != nil && height > p.Height() {
+			return ctx, base.NewBaseOperationProcessReasonError("key rotation already pending, %q", n), nil
+		}
+	}
+
+	pub := isaac.SuffrageNodePublickey(stv, p.Height())
+
+	switch signer := rop.PreviousSigner(); {
+	case signer == nil, !signer.Equal(pub):
+		return ctx, base.NewBaseOperationProcessReasonError("not signed by node key"), nil
+	case fact.Publickey().Equal(pub):
+		return ctx, base.NewBaseOperationProcessReasonError("same publickey"), nil
+	}
+
+	for k := range p.suffrage {
+		if isaac.SuffrageNodePublickey(p.suffrage[k], p.Height()).Equal(fact.Publickey()) {
+			return ctx, base.NewBaseOperationProcessReasonError("publickey already used by %q", k), nil
+		}
+	}
+
+	switch reasonerr, err := p.PreProcessConstraintFunc(ctx, op, getStateFunc); {
+	case err != nil:
+		return ctx, nil, e.Wrap(err)
+	case reasonerr != nil:
+		return ctx, reasonerr, nil
+	}
+
+	p.preprocessed[n.String()] = struct{}{}
+
+	return ctx, nil, nil
+}
+
+func (p *SuffrageKeyRotateProcessor) Process(ctx context.Context, op base.Operation, getStateFunc base.GetStateFunc) (
+	[]base.StateMergeValue, base.OperationProcessReasonError, error,
+) {
+	e := util.StringError("process for SuffrageKeyRotate")
+
+	switch reasonerr, err := p.ProcessConstraintFunc(ctx, op, getStateFunc); {
+	case err != nil:
+		return nil, nil, e.Wrap(err)
+	case reasonerr != nil:
+		return nil, reasonerr, nil
+	}
+
+	fact := op.Fact().(SuffrageKeyRotateFact) //nolint:forcetypeassert //...
+
+	return []base.StateMergeValue{
+		base.NewBaseStateMergeValue(
+			isaac.SuffrageStateKey,
+			newSuffrageKeyRotateNodeStateValue(fact.Node(), fact.Publickey(), fact.Height()),
+			func(height base.Height, st base.State) base.StateValueMerger {
+				return NewSuffrageJoinStateValueMerger(height, st)
+			},
+		),
+	}, nil, nil
+}
+
+type suffrageKeyRotateNodeStateValue struct {
+	node      base.Address
+	publickey base.Publickey
+	height    base.Height
+}
+
+func newSuffrageKeyRotateNodeStateValue(
+	node base.Address, publickey base.Publickey, height base.Height,
+) suffrageKeyRotateNodeStateValue {
+	return suffrageKeyRotateNodeStateValue{
+		node:      node,
+		publickey: publickey,
+		height:    height,
+	}
+}
+
+func (s suffrageKeyRotateNodeStateValue) IsValid([]byte) error {
+	if err := util.CheckIsValiders(nil, false, s.node, s.publickey, s.height); err != nil {
+		return util.ErrInvalid.Errorf("invalid suffrageKeyRotateNodeStateValue")
+	}
+
+	return nil
+}
+
+func (s suffrageKeyRotateNodeStateValue) HashBytes() []byte {
+	return util.ConcatByters(s.node, s.publickey, s.height)
+}
@@ -0,0 +1,301 @@
+package isaacoperation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testSuffrageKeyRotateProcessor struct {
+	suite.Suite
+	networkID base.NetworkID
+}
+
+func (t *testSuffrageKeyRotateProcessor) SetupTest() {
+	t.networkID = util.UUID().Bytes()
+}
+
+func (t *testSuffrageKeyRotateProcessor) prepare(height base.Height, nodesstv []base.SuffrageNodeStateValue) (
+	base.BaseState, base.GetStateFunc,
+) {
+	suffragest := base.NewBaseState(
+		height-1,
+		isaac.SuffrageStateKey,
+		isaac.NewSuffrageNodesStateValue(base.Height(22), nodesstv),
+		valuehash.RandomSHA256(),
+		[]util.Hash{valuehash.RandomSHA256()},
+	)
+
+	return suffragest, func(key string) (base.State, bool, error) {
+		switch key {
+		case isaac.SuffrageStateKey:
+			return suffragest, true, nil
+		default:
+			return nil, false, nil
+		}
+	}
+}
+
+func (t *testSuffrageKeyRotateProcessor) nodes(height base.Height, n int) (
+	[]isaac.LocalNode, []base.SuffrageNodeStateValue,
+) {
+	nodes := make([]isaac.LocalNode, n)
+	nodesstv := make([]base.SuffrageNodeStateValue, n)
+
+	for i := range nodes {
+		node := isaac.NewLocalNode(base.NewMPrivatekey(), base.RandomAddress(""))
+
+		nodes[i] = node
+		nodesstv[i] = isaac.NewSuffrageNodeStateValue(node, height)
+	}
+
+	return nodes, nodesstv
+}
+
+func (t *testSuffrageKeyRotateProcessor) newOperation(
+	node base.Address, priv, newpriv base.Privatekey, height base.Height,
+) SuffrageKeyRotate {
+	op := NewSuffrageKeyRotate(NewSuffrageKeyRotateFact(util.UUID().Bytes(), node, newpriv.Publickey(), height))
+	t.NoError(op.Sign(priv, t.networkID))
+	t.NoError(op.Sign(newpriv, t.networkID))
+
+	return op
+}
+
+func (t *testSuffrageKeyRotateProcessor) process(
+	height base.Height, st base.State, op base.Operation, getStateFunc base.GetStateFunc,
+) base.State {
+	pp, err := NewSuffrageKeyRotateProcessor(height, getStateFunc, nil, nil)
+	t.NoError(err)
+
+	_, reason, err := pp.PreProcess(context.Background(), op, getStateFunc)
+	t.NoError(err)
+	t.Nil(reason)
+
+	mergevalues, reason, err := pp.Process(context.Background(), op, getStateFunc)
+	t.NoError(err)
+	t.Nil(reason)
+	t.Equal(1, len(mergevalues))
+
+	merger := mergevalues[0].Merger(height, st)
+	t.NoError(merger.Merge(mergevalues[0].Value(), op.Hash()))
+
+	nst, err := merger.CloseValue()
+	t.NoError(err)
+
+	return nst
+}
+
+func (t *testSuffrageKeyRotateProcessor) TestNew() {
+	height := base.Height(33)
+	rotateHeight := height + 5
+
+	nodes, nodesstv := t.nodes(height, 3)
+	suffragest, getStateFunc := t.prepare(height, nodesstv)
+
+	local := nodes[1]
+	newpriv := base.NewMPrivatekey()
+
+	op := t.newOperation(local.Address(), local.Privatekey(), newpriv, rotateHeight)
+
+	nst := t.process(height, suffragest, op, getStateFunc)
+	t.Equal(height, nst.Height())
+	t.True(suffragest.Hash().Equal(nst.Previous()))
+
+	uv := nst.Value().(base.SuffrageNodesStateValue)
+	t.Equal(suffragest.Value().(base.SuffrageNodesStateValue).Height()+1, uv.Height())
+	t.Equal(3, len(uv.Nodes()))
+
+	t.Run("key rotation", func() {
+		unode := uv.Nodes()[1]
+		t.True(unode.Publickey().Equal(local.Publickey()))
+
+		pub, rheight := unode.(isaac.SuffrageNodeKeyRotator).KeyRotation()
+		t.True(pub.Equal(newpriv.Publickey()))
+		t.Equal(rotateHeight, rheight)
+
+		t.True(isaac.SuffrageNodePublickey(unode, rotateHeight-1).Equal(local.Publickey()))
+		t.True(isaac.SuffrageNodePublickey(unode, rotateHeight).Equal(newpriv.Publickey()))
+	})
+
+	t.Run("switch at rotate height", func() {
+		suf, err := uv.Suffrage()
+		t.NoError(err)
+
+		// NOTE ballots of rotate height are checked with the suffrage of
+		// previous height.
+		before, err := isaac.KeyRotatedSuffrage(suf, rotateHeight-2)
+		t.NoError(err)
+		t.True(before.ExistsPublickey(local.Address(), local.Publickey()))
+		t.False(before.ExistsPublickey(local.Address(), newpriv.Publickey()))
+
+		after, err := isaac.KeyRotatedSuffrage(suf, rotateHeight-1)
+		t.NoError(err)
+		t.False(after.ExistsPublickey(local.Address(), local.Publickey()))
+		t.True(after.ExistsPublickey(local.Address(), newpriv.Publickey()))
+
+		t.True(after.ExistsPublickey(nodes[0].Address(), nodes[0].Publickey()))
+		t.True(after.ExistsPublickey(nodes[2].Address(), nodes[2].Publickey()))
+	})
+
+	t.Run("effective rotation merged", func() {
+		another := nodes[2]
+		anotherpriv := base.NewMPrivatekey()
+
+		nheight := rotateHeight + 1
+
+		nextst := base.NewBaseState(nheight-1, isaac.SuffrageStateKey, uv, valuehash.RandomSHA256(), nil)
+		nextf := func(key string) (base.State, bool, error) {
+			switch key {
+			case isaac.SuffrageStateKey:
+				return nextst, true, nil
+			default:
+				return nil, false, nil
+			}
+		}
+
+		op := t.newOperation(another.Address(), another.Privatekey(), anotherpriv, nheight+3)
+
+		nst := t.process(nheight, nextst, op, nextf)
+
+		unodes := nst.Value().(base.SuffrageNodesStateValue).Nodes()
+
+		t.True(unodes[1].Publickey().Equal(newpriv.Publickey()))
+
+		pub, _ := unodes[1].(isaac.SuffrageNodeKeyRotator).KeyRotation()
+		t.Nil(pub)
+
+		pub, _ = unodes[2].(isaac.SuffrageNodeKeyRotator).KeyRotation()
+		t.True(pub.Equal(anotherpriv.Publickey()))
+	})
+}
+
+func (t *testSuffrageKeyRotateProcessor) TestFromEmptyState() {
+	_, err := NewSuffrageKeyRotateProcessor(
+		base.Height(33),
+		func(key string) (base.State, bool, error) {
+			return nil, false, nil
+		},
+		nil,
+		nil,
+	)
+	t.Error(err)
+	t.ErrorContains(err, "empty state")
+}
+
+func (t *testSuffrageKeyRotateProcessor) TestPreProcess() {
+	height := base.Height(33)
+
+	nodes, nodesstv := t.nodes(height, 3)
+	_, getStateFunc := t.prepare(height, nodesstv)
+
+	local := nodes[1]
+
+	preprocess := func(op base.Operation, f base.GetStateFunc) base.OperationProcessReasonError {
+		pp, err := NewSuffrageKeyRotateProcessor(height, f, nil, nil)
+		t.NoError(err)
+
+		_, reason, err := pp.PreProcess(context.Background(), op, f)
+		t.NoError(err)
+
+		return reason
+	}
+
+	t.Run("not in suffrage", func() {
+		op := t.newOperation(base.RandomAddress(""), local.Privatekey(), base.NewMPrivatekey(), height+3)
+
+		reason := preprocess(op, getStateFunc)
+		t.NotNil(reason)
+		t.ErrorContains(reason, "not in suffrage")
+	})
+
+	t.Run("not signed by node key", func() {
+		op := t.newOperation(local.Address(), base.NewMPrivatekey(), base.NewMPrivatekey(), height+3)
+
+		reason := preprocess(op, getStateFunc)
+		t.NotNil(reason)
+		t.ErrorContains(reason, "not signed by node key")
+	})
+
+	t.Run("rotate height too low", func() {
+		op := t.newOperation(local.Address(), local.Privatekey(), base.NewMPrivatekey(), height+1)
+
+		reason := preprocess(op, getStateFunc)
+		t.NotNil(reason)
+		t.ErrorContains(reason, "rotate height should be over next height")
+	})
+
+	t.Run("publickey of other node", func() {
+		op := NewSuffrageKeyRotate(NewSuffrageKeyRotateFact(
+			util.UUID().Bytes(), local.Address(), nodes[0].Publickey(), height+3))
+		t.NoError(op.Sign(local.Privatekey(), t.networkID))
+		t.NoError(op.Sign(nodes[0].Privatekey(), t.networkID))
+
+		reason := preprocess(op, getStateFunc)
+		t.NotNil(reason)
+		t.ErrorContains(reason, "publickey already used")
+	})
+
+	t.Run("already preprocessed", func() {
+		pp, err := NewSuffrageKeyRotateProcessor(height, getStateFunc, nil, nil)
+		t.NoError(err)
+
+		op := t.newOperation(local.Address(), local.Privatekey(), base.NewMPrivatekey(), height+3)
+
+		_, reason, err := pp.PreProcess(context.Background(), op, getStateFunc)
+		t.NoError(err)
+		t.Nil(reason)
+
+		op = t.newOperation(local.Address(), local.Privatekey(), base.NewMPrivatekey(), height+3)
+
+		_, reason, err = pp.PreProcess(context.Background(), op, getStateFunc)
+		t.NoError(err)
+		t.NotNil(reason)
+		t.ErrorContains(reason, "already preprocessed")
+	})
+
+	t.Run("pending rotation", func() {
+		rotated := make([]base.SuffrageNodeStateValue, len(nodesstv))
+		copy(rotated, nodesstv)
+
+		rotated[1] = nodesstv[1].(isaac.SuffrageNodeStateValue).SetKeyRotation(
+			base.NewMPrivatekey().Publickey(), height+2)
+
+		_, f := t.prepare(height, rotated)
+
+		op := t.newOperation(local.Address(), local.Privatekey(), base.NewMPrivatekey(), height+3)
+
+		reason := preprocess(op, f)
+		t.NotNil(reason)
+		t.ErrorContains(reason, "key rotation already pending")
+	})
+
+	t.Run("signed by rotated key", func() {
+		rotatedpriv := base.NewMPrivatekey()
+
+		rotated := make([]base.SuffrageNodeStateValue, len(nodesstv))
+		copy(rotated, nodesstv)
+
+		rotated[1] = nodesstv[1].(isaac.SuffrageNodeStateValue).SetKeyRotation(rotatedpriv.Publickey(), height)
+
+		_, f := t.prepare(height, rotated)
+
+		op := t.newOperation(local.Address(), local.Privatekey(), base.NewMPrivatekey(), height+3)
+
+		reason := preprocess(op, f)
+		t.NotNil(reason)
+		t.ErrorContains(reason, "not signed by node key")
+
+		op = t.newOperation(local.Address(), rotatedpriv, base.NewMPrivatekey(), height+3)
+		t.Nil(preprocess(op, f))
+	})
+}
+
+func TestSuffrageKeyRotateProcessor(t *testing.T) {
+	suite.Run(t, new(testSuffrageKeyRotateProcessor))
+}
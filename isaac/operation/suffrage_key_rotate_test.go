@@ -0,0 +1,256 @@
+package isaacoperation
+
+import (
+	"testing"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	jsonenc "github.com/ProtoconNet/mitum2/util/encoder/json"
+	"github.com/ProtoconNet/mitum2/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testSuffrageKeyRotateFact struct {
+	suite.Suite
+}
+
+func (t *testSuffrageKeyRotateFact) TestNew() {
+	fact := NewSuffrageKeyRotateFact(
+		util.UUID().Bytes(), base.RandomAddress(""), base.NewMPrivatekey().Publickey(), base.Height(33))
+	t.NoError(fact.IsValid(nil))
+}
+
+func (t *testSuffrageKeyRotateFact) TestIsValid() {
+	t.Run("invalid BaseFact", func() {
+		fact := NewSuffrageKeyRotateFact(
+			util.UUID().Bytes(), base.RandomAddress(""), base.NewMPrivatekey().Publickey(), base.Height(33))
+		fact.SetToken(nil)
+
+		err := fact.IsValid(nil)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "invalid BaseFact")
+	})
+
+	t.Run("empty node", func() {
+		fact := NewSuffrageKeyRotateFact(
+			util.UUID().Bytes(), nil, base.NewMPrivatekey().Publickey(), base.Height(33))
+
+		err := fact.IsValid(nil)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "invalid SuffrageKeyRotateFact")
+	})
+
+	t.Run("empty publickey", func() {
+		fact := NewSuffrageKeyRotateFact(util.UUID().Bytes(), base.RandomAddress(""), nil, base.Height(33))
+
+		err := fact.IsValid(nil)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "invalid SuffrageKeyRotateFact")
+	})
+
+	t.Run("genesis height", func() {
+		fact := NewSuffrageKeyRotateFact(
+			util.UUID().Bytes(), base.RandomAddress(""), base.NewMPrivatekey().Publickey(), base.GenesisHeight)
+
+		err := fact.IsValid(nil)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "over genesis height")
+	})
+
+	t.Run("wrong hash", func() {
+		fact := NewSuffrageKeyRotateFact(
+			util.UUID().Bytes(), base.RandomAddress(""), base.NewMPrivatekey().Publickey(), base.Height(33))
+		fact.SetHash(valuehash.NewBytes(util.UUID().Bytes()))
+
+		err := fact.IsValid(nil)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "hash does not match")
+	})
+}
+
+func TestSuffrageKeyRotateFact(t *testing.T) {
+	suite.Run(t, new(testSuffrageKeyRotateFact))
+}
+
+func TestSuffrageKeyRotateFactEncode(tt *testing.T) {
+	t := new(encoder.BaseTestEncode)
+
+	enc := jsonenc.NewEncoder()
+
+	t.Encode = func() (interface{}, []byte) {
+		fact := NewSuffrageKeyRotateFact(
+			util.UUID().Bytes(), base.RandomAddress(""), base.NewMPrivatekey().Publickey(), base.Height(33))
+
+		b, err := enc.Marshal(fact)
+		t.NoError(err)
+
+		t.T().Log("marshaled:", string(b))
+
+		return fact, b
+	}
+	t.Decode = func(b []byte) interface{} {
+		t.NoError(enc.Add(encoder.DecodeDetail{Hint: base.StringAddressHint, Instance: base.StringAddress{}}))
+		t.NoError(enc.Add(encoder.DecodeDetail{Hint: base.MPublickeyHint, Instance: &base.MPublickey{}}))
+		t.NoError(enc.Add(encoder.DecodeDetail{Hint: SuffrageKeyRotateFactHint, Instance: SuffrageKeyRotateFact{}}))
+
+		i, err := enc.Decode(b)
+		t.NoError(err)
+
+		_, ok := i.(SuffrageKeyRotateFact)
+		t.True(ok)
+
+		return i
+	}
+	t.Compare = func(a, b interface{}) {
+		af, ok := a.(SuffrageKeyRotateFact)
+		t.True(ok)
+		bf, ok := b.(SuffrageKeyRotateFact)
+		t.True(ok)
+
+		t.NoError(bf.IsValid(nil))
+
+		base.EqualFact(t.Assert(), af, bf)
+		t.True(af.Publickey().Equal(bf.Publickey()))
+	}
+
+	suite.Run(tt, t)
+}
+
+type testSuffrageKeyRotate struct {
+	suite.Suite
+}
+
+func (t *testSuffrageKeyRotate) TestIsValid() {
+	networkID := util.UUID().Bytes()
+
+	t.Run("ok", func() {
+		priv := base.NewMPrivatekey()
+		newpriv := base.NewMPrivatekey()
+
+		fact := NewSuffrageKeyRotateFact(util.UUID().Bytes(), base.RandomAddress(""), newpriv.Publickey(), base.Height(33))
+		op := NewSuffrageKeyRotate(fact)
+		t.NoError(op.Sign(priv, networkID))
+		t.NoError(op.Sign(newpriv, networkID))
+
+		t.NoError(op.IsValid(networkID))
+		t.True(priv.Publickey().Equal(op.PreviousSigner()))
+	})
+
+	t.Run("not signed by new key", func() {
+		newpriv := base.NewMPrivatekey()
+
+		fact := NewSuffrageKeyRotateFact(util.UUID().Bytes(), base.RandomAddress(""), newpriv.Publickey(), base.Height(33))
+		op := NewSuffrageKeyRotate(fact)
+		t.NoError(op.Sign(base.NewMPrivatekey(), networkID))
+		t.NoError(op.Sign(base.NewMPrivatekey(), networkID))
+
+		err := op.IsValid(networkID)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "not signed by new publickey")
+	})
+
+	t.Run("only new key", func() {
+		newpriv := base.NewMPrivatekey()
+
+		fact := NewSuffrageKeyRotateFact(util.UUID().Bytes(), base.RandomAddress(""), newpriv.Publickey(), base.Height(33))
+		op := NewSuffrageKeyRotate(fact)
+		t.NoError(op.Sign(newpriv, networkID))
+
+		err := op.IsValid(networkID)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "2 signs required")
+	})
+
+	t.Run("too many signs", func() {
+		newpriv := base.NewMPrivatekey()
+
+		fact := NewSuffrageKeyRotateFact(util.UUID().Bytes(), base.RandomAddress(""), newpriv.Publickey(), base.Height(33))
+		op := NewSuffrageKeyRotate(fact)
+		t.NoError(op.Sign(base.NewMPrivatekey(), networkID))
+		t.NoError(op.Sign(newpriv, networkID))
+		t.NoError(op.Sign(base.NewMPrivatekey(), networkID))
+
+		err := op.IsValid(networkID)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "2 signs required")
+	})
+
+	t.Run("different network id", func() {
+		newpriv := base.NewMPrivatekey()
+
+		fact := NewSuffrageKeyRotateFact(util.UUID().Bytes(), base.RandomAddress(""), newpriv.Publickey(), base.Height(33))
+		op := NewSuffrageKeyRotate(fact)
+		t.NoError(op.Sign(base.NewMPrivatekey(), networkID))
+		t.NoError(op.Sign(newpriv, networkID))
+
+		err := op.IsValid(util.UUID().Bytes())
+		t.Error(err)
+		t.ErrorIs(err, base.ErrSignatureVerification)
+	})
+}
+
+func TestSuffrageKeyRotate(t *testing.T) {
+	suite.Run(t, new(testSuffrageKeyRotate))
+}
+
+func TestSuffrageKeyRotateEncode(tt *testing.T) {
+	t := new(encoder.BaseTestEncode)
+
+	enc := jsonenc.NewEncoder()
+	networkID := util.UUID().Bytes()
+
+	t.Encode = func() (interface{}, []byte) {
+		newpriv := base.NewMPrivatekey()
+
+		fact := NewSuffrageKeyRotateFact(util.UUID().Bytes(), base.RandomAddress(""), newpriv.Publickey(), base.Height(33))
+		op := NewSuffrageKeyRotate(fact)
+		t.NoError(op.Sign(base.NewMPrivatekey(), networkID))
+		t.NoError(op.Sign(newpriv, networkID))
+
+		t.NoError(op.IsValid(networkID))
+
+		b, err := enc.Marshal(op)
+		t.NoError(err)
+
+		t.T().Log("marshaled:", string(b))
+
+		return op, b
+	}
+	t.Decode = func(b []byte) interface{} {
+		t.NoError(enc.Add(encoder.DecodeDetail{Hint: base.StringAddressHint, Instance: base.StringAddress{}}))
+		t.NoError(enc.Add(encoder.DecodeDetail{Hint: base.MPublickeyHint, Instance: &base.MPublickey{}}))
+		t.NoError(enc.Add(encoder.DecodeDetail{Hint: SuffrageKeyRotateFactHint, Instance: SuffrageKeyRotateFact{}}))
+		t.NoError(enc.Add(encoder.DecodeDetail{Hint: SuffrageKeyRotateHint, Instance: SuffrageKeyRotate{}}))
+
+		i, err := enc.Decode(b)
+		t.NoError(err)
+
+		op, ok := i.(SuffrageKeyRotate)
+		t.True(ok)
+
+		t.NoError(op.IsValid(networkID))
+
+		return i
+	}
+	t.Compare = func(a, b interface{}) {
+		af, ok := a.(SuffrageKeyRotate)
+		t.True(ok)
+		bf, ok := b.(SuffrageKeyRotate)
+		t.True(ok)
+
+		t.NoError(bf.IsValid(networkID))
+
+		base.EqualOperation(t.Assert(), af, bf)
+	}
+
+	suite.Run(tt, t)
+}
@@ -95,6 +95,18 @@ func (p *ProposalMaker) preferEmpty(
 
 func (p *ProposalMaker) Make(
 	ctx context.Context, point base.Point, previousBlock util.Hash,
+) (base.ProposalSignFact, error) {
+	sctx, span := StartPointSpan(ctx, "proposal-maker.make", point)
+
+	pr, err := p.make(sctx, point, previousBlock)
+
+	span.End(err)
+
+	return pr, err
+}
+
+func (p *ProposalMaker) make(
+	ctx context.Context, point base.Point, previousBlock util.Hash,
 ) (base.ProposalSignFact, error) {
 	p.Lock()
 	defer p.Unlock()
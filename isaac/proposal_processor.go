@@ -10,6 +10,7 @@ import (
 	"github.com/ProtoconNet/mitum2/util"
 	"github.com/ProtoconNet/mitum2/util/hint"
 	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/ProtoconNet/mitum2/util/tracing"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 )
@@ -57,7 +58,10 @@ type DefaultProposalProcessorArgs struct {
 	GetOperationFunc          OperationProcessorGetOperationFunction
 	NewOperationProcessorFunc NewOperationProcessorFunc
 	EmptyProposalNoBlockFunc  func() bool
-	MaxWorkerSize             int64
+	// WhenOperationProcessedFunc is called for the processed operations after
+	// the block is saved; if reason is not nil, the operation is rejected.
+	WhenOperationProcessedFunc func(_ base.Operation, reason base.OperationProcessReasonError)
+	MaxWorkerSize              int64
 }
 
 func NewDefaultProposalProcessorArgs() *DefaultProposalProcessorArgs {
@@ -65,8 +69,9 @@ func NewDefaultProposalProcessorArgs() *DefaultProposalProcessorArgs {
 		NewOperationProcessorFunc: func(base.Height, hint.Hint, base.GetStateFunc) (base.OperationProcessor, error) {
 			return nil, nil
 		},
-		MaxWorkerSize:            1 << 13, //nolint:gomnd // big enough
-		EmptyProposalNoBlockFunc: func() bool { return false },
+		MaxWorkerSize:              1 << 13, //nolint:gomnd // big enough
+		EmptyProposalNoBlockFunc:   func() bool { return false },
+		WhenOperationProcessedFunc: func(base.Operation, base.OperationProcessReasonError) {},
 	}
 }
 
@@ -82,7 +87,9 @@ type DefaultProposalProcessor struct {
 	ivp         base.INITVoteproof
 	oprs        *util.ShardedMap[string, base.OperationProcessor]
 	stcache     *util.ShardedMap[string, [2]interface{}]
+	processed   [][2]interface{} // NOTE [base.Operation, base.OperationProcessReasonError]
 	processlock sync.Mutex
+	processedl  sync.Mutex
 	isprocessed bool
 	issaved     bool
 }
@@ -152,7 +159,13 @@ func (p *DefaultProposalProcessor) Process(ctx context.Context, ivp base.INITVot
 
 	p.ivp = ivp
 
-	switch manifest, err := p.process(pctx); {
+	_, span := StartPointSpan(ctx, "proposal-processor.process", p.proposal.Point())
+
+	manifest, err := p.process(tracing.ContextWithSpan(pctx, span))
+
+	span.End(err)
+
+	switch {
 	case err != nil:
 		return nil, e.Wrap(err)
 	default:
@@ -187,7 +200,13 @@ func (p *DefaultProposalProcessor) Save(ctx context.Context, avp base.ACCEPTVote
 
 	p.issaved = true
 
-	switch bm, err := p.save(sctx, avp); {
+	_, span := StartPointSpan(ctx, "proposal-processor.save", p.proposal.Point())
+
+	bm, err := p.save(tracing.ContextWithSpan(sctx, span), avp)
+
+	span.End(err)
+
+	switch {
 	case err != nil:
 		p.Log().Error().Err(err).Msg("save")
 
@@ -214,6 +233,8 @@ func (p *DefaultProposalProcessor) isCanceled() bool {
 func (p *DefaultProposalProcessor) process(ctx context.Context) (base.Manifest, error) {
 	defer logging.TimeElapsed()(p.Log().Debug(), "processed")
 
+	p.processed = nil
+
 	var cops, reserved []base.Operation
 
 	switch i, j, err := p.collectOperations(ctx); {
@@ -436,9 +457,15 @@ func (p *DefaultProposalProcessor) processOperation(
 				return err
 			}
 
-			return writer.SetProcessResult(
+			if err := writer.SetProcessResult(
 				ctx, uint64(opsindex), op.Hash(), op.Fact().Hash(), false, reasonerr,
-			)
+			); err != nil {
+				return err
+			}
+
+			p.operationProcessed(op, reasonerr)
+
+			return nil
 		}); err != nil {
 			return pctx, false, err
 		}
@@ -531,6 +558,8 @@ func (p *DefaultProposalProcessor) doProcessOperation(
 		return e.Wrap(err)
 	}
 
+	p.operationProcessed(op, errorreason)
+
 	return nil
 }
 
@@ -627,9 +656,23 @@ func (p *DefaultProposalProcessor) save(ctx context.Context, avp base.ACCEPTVote
 
 	p.Log().Info().Interface("blockmap", m).Msg("new block saved in proposal processor")
 
+	for i := range p.processed {
+		op, _ := p.processed[i][0].(base.Operation)
+		reason, _ := p.processed[i][1].(base.OperationProcessReasonError)
+
+		p.args.WhenOperationProcessedFunc(op, reason)
+	}
+
 	return m, nil
 }
 
+func (p *DefaultProposalProcessor) operationProcessed(op base.Operation, reason base.OperationProcessReasonError) {
+	p.processedl.Lock()
+	defer p.processedl.Unlock()
+
+	p.processed = append(p.processed, [2]interface{}{op, reason})
+}
+
 func (*DefaultProposalProcessor) deferctx(ctx context.Context, cancel func()) (func(), func()) {
 	donech := make(chan struct{}, 1)
 
@@ -1561,10 +1561,14 @@ func (t *testDefaultProposalProcessor) TestSave() {
 			return writer.setStates(ctx, index, states, op)
 		}
 
+		var processed int
 		args := t.newargs(newwriterf)
 		args.GetOperationFunc = func(_ context.Context, oph, fact util.Hash) (base.Operation, error) {
 			return ops[oph.String()], nil
 		}
+		args.WhenOperationProcessedFunc = func(base.Operation, base.OperationProcessReasonError) {
+			processed++
+		}
 
 		opp, _ := NewDefaultProposalProcessor(pr, previous, args)
 
@@ -1573,6 +1577,7 @@ func (t *testDefaultProposalProcessor) TestSave() {
 		t.NotNil(m)
 
 		t.Equal(len(ops), writer.sts.Len())
+		t.Equal(0, processed, "processed before save")
 
 		afact := t.NewACCEPTBallotFact(point.NextHeight(), pr.Fact().Hash(), m.Hash())
 		avp, err := t.NewACCEPTVoteproof(afact, t.Local, []base.LocalNode{t.Local})
@@ -1586,6 +1591,8 @@ func (t *testDefaultProposalProcessor) TestSave() {
 			t.Fail("failed to wait to save")
 		case <-savech:
 		}
+
+		t.Equal(len(ops), processed)
 	})
 
 	t.Run("different manifest hash with majority", func() {
@@ -1633,10 +1640,14 @@ func (t *testDefaultProposalProcessor) TestSaveFailed() {
 		return nil, errors.Errorf("killme")
 	}
 
+	var processed int
 	args := t.newargs(newwriterf)
 	args.GetOperationFunc = func(_ context.Context, oph, fact util.Hash) (base.Operation, error) {
 		return ops[oph.String()], nil
 	}
+	args.WhenOperationProcessedFunc = func(base.Operation, base.OperationProcessReasonError) {
+		processed++
+	}
 
 	opp, _ := NewDefaultProposalProcessor(pr, previous, args)
 
@@ -1653,6 +1664,7 @@ func (t *testDefaultProposalProcessor) TestSaveFailed() {
 	_, err = opp.Save(context.Background(), avp)
 	t.Error(err)
 	t.ErrorContains(err, "killme")
+	t.Equal(0, processed)
 }
 
 func (t *testDefaultProposalProcessor) TestSaveAgain() {
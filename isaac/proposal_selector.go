@@ -371,6 +371,26 @@ func ConcurrentRequestProposal(
 	client NetworkClient,
 	cis []quicstream.ConnInfo,
 	networkID base.NetworkID,
+) (base.ProposalSignFact, bool, error) {
+	sctx, span := StartPointSpan(ctx, "proposal.concurrent-request", point)
+	span.SetAttribute("nodes", len(cis))
+
+	pr, found, err := concurrentRequestProposal(sctx, point, proposer, previousBlock, client, cis, networkID)
+
+	span.SetAttribute("found", found)
+	span.End(err)
+
+	return pr, found, err
+}
+
+func concurrentRequestProposal(
+	ctx context.Context,
+	point base.Point,
+	proposer base.Node,
+	previousBlock util.Hash,
+	client NetworkClient,
+	cis []quicstream.ConnInfo,
+	networkID base.NetworkID,
 ) (base.ProposalSignFact, bool, error) {
 	worker, err := util.NewBaseJobWorker(ctx, int64(len(cis)))
 	if err != nil {
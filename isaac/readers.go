@@ -272,6 +272,8 @@ type BlockItemReaders struct {
 	emptyHeightsLock util.LockedMap[base.Height, time.Time]
 	bfilessg         singleflight.Group
 	root             string
+	archives         []*BlockArchive
+	archivesLock     sync.RWMutex
 }
 
 func NewBlockItemReaders(
@@ -311,6 +313,12 @@ func (rs *BlockItemReaders) Close() {
 	rs.emptyHeightsLock.Close()
 }
 
+// PurgeItemFiles purges the cached block item files; it should be called after
+// blocks are removed.
+func (rs *BlockItemReaders) PurgeItemFiles() {
+	rs.bfilescache.Purge()
+}
+
 func (rs *BlockItemReaders) Root() string {
 	return rs.root
 }
@@ -361,13 +369,13 @@ func (rs *BlockItemReaders) Item(
 	switch i, found, err := rs.ItemFile(height, t); {
 	case err != nil, !found:
 		return nil, found, errors.WithMessage(err, t.String())
-	case i.URI().Scheme != LocalFSBlockItemScheme:
+	case i.URI().Scheme != LocalFSBlockItemScheme && i.URI().Scheme != BlockArchiveBlockItemScheme:
 		return i, false, nil
 	default:
 		bfile = i
 	}
 
-	var f *os.File
+	var f io.ReadSeekCloser
 
 	switch i, found, err := rs.ReadFileFromItemFile(height, bfile); {
 	case err != nil:
@@ -562,7 +570,11 @@ func (rs *BlockItemReaders) ItemFile(height base.Height, t base.BlockItemType) (
 	}
 }
 
-func (rs *BlockItemReaders) ReadFileFromItemFile(height base.Height, bfile base.BlockItemFile) (*os.File, bool, error) {
+// ReadFileFromItemFile opens the local item file; if the item file is in
+// block archive, the returned reader reads only the range of item.
+func (rs *BlockItemReaders) ReadFileFromItemFile(
+	height base.Height, bfile base.BlockItemFile,
+) (io.ReadSeekCloser, bool, error) {
 	var p string
 
 	switch u := bfile.URI(); u.Scheme {
@@ -570,6 +582,8 @@ func (rs *BlockItemReaders) ReadFileFromItemFile(height base.Height, bfile base.
 		p = filepath.Join(rs.root, BlockHeightDirectory(height), u.Path)
 	case "file":
 		p = u.Path
+	case BlockArchiveBlockItemScheme:
+		return rs.readFileFromArchive(height, strings.TrimPrefix(u.Path, "/"))
 	default:
 		return nil, false, nil
 	}
@@ -584,6 +598,71 @@ func (rs *BlockItemReaders) ReadFileFromItemFile(height base.Height, bfile base.
 	}
 }
 
+// Archive returns the block archive, which has the items of height.
+func (rs *BlockItemReaders) Archive(height base.Height) (*BlockArchive, bool, error) {
+	if a := rs.findArchive(height); a != nil {
+		return a, true, nil
+	}
+
+	// NOTE new archive may be added
+	if err := rs.ReloadArchives(); err != nil {
+		return nil, false, err
+	}
+
+	a := rs.findArchive(height)
+
+	return a, a != nil, nil
+}
+
+// Archives returns the loaded block archives.
+func (rs *BlockItemReaders) Archives() []*BlockArchive {
+	rs.archivesLock.RLock()
+	defer rs.archivesLock.RUnlock()
+
+	return rs.archives
+}
+
+func (rs *BlockItemReaders) ReloadArchives() error {
+	rs.archivesLock.Lock()
+	defer rs.archivesLock.Unlock()
+
+	switch i, err := LoadBlockArchives(rs.root); {
+	case err != nil:
+		return err
+	default:
+		rs.archives = i
+
+		return nil
+	}
+}
+
+func (rs *BlockItemReaders) findArchive(height base.Height) *BlockArchive {
+	rs.archivesLock.RLock()
+	defer rs.archivesLock.RUnlock()
+
+	for i := len(rs.archives) - 1; i >= 0; i-- {
+		if a := rs.archives[i]; height >= a.From() && height <= a.To() {
+			return a
+		}
+	}
+
+	return nil
+}
+
+func (rs *BlockItemReaders) readFileFromArchive(height base.Height, name string) (io.ReadSeekCloser, bool, error) {
+	switch a, found, err := rs.Archive(height); {
+	case err != nil, !found:
+		return nil, found, err
+	default:
+		switch i, found, err := a.Open(height, name); {
+		case err != nil, !found:
+			return nil, found, err
+		default:
+			return i, true, nil
+		}
+	}
+}
+
 func (rs *BlockItemReaders) findBlockReader(f io.Reader, compressFormat string) (
 	NewBlockItemReaderFunc,
 	encoder.Encoder,
@@ -837,7 +916,8 @@ func (*BlockItemReaders) isInLocalFS(f base.BlockItemFiles) bool {
 	m := f.Items()
 
 	for i := range m {
-		if m[i].URI().Scheme == LocalFSBlockItemScheme {
+		switch m[i].URI().Scheme {
+		case LocalFSBlockItemScheme, BlockArchiveBlockItemScheme:
 			return true
 		}
 	}
@@ -1092,7 +1172,7 @@ func NewDefaultRemotesBlockItemReadFunc() RemotesBlockItemReadFunc {
 		callback func(io.Reader, string) error,
 	) (bool, bool, error) {
 		switch uri.Scheme {
-		case LocalFSBlockItemScheme:
+		case LocalFSBlockItemScheme, BlockArchiveBlockItemScheme:
 			return true, false, nil
 		case "http", "https":
 			found, err := httpf(ctx, uri, func(r io.Reader) error {
@@ -1156,9 +1236,85 @@ func httpBlockItemReadFunc(insecure bool) RemoteBlockItemReadFunc {
 	}
 }
 
+// BlockItemReadersHTTPHandler serves the block item files thru http. The path
+// is `/<height>/<block item type>`. The local item file is served with range
+// request support, the item of block archive also; the remote item file is
+// redirected.
+func BlockItemReadersHTTPHandler(readers *BlockItemReaders) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		var height base.Height
+		var t base.BlockItemType
+
+		switch l := strings.Split(strings.Trim(r.URL.Path, "/"), "/"); {
+		case len(l) != 2: //nolint:gomnd //...
+			http.NotFound(w, r)
+
+			return
+		default:
+			i, err := base.ParseHeightString(l[0])
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+
+				return
+			}
+
+			height = i
+			t = base.BlockItemType(l[1])
+
+			if err := t.IsValid(nil); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+
+				return
+			}
+		}
+
+		var bfile base.BlockItemFile
+
+		switch i, found, err := readers.ItemFile(height, t); {
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		case !found:
+			http.NotFound(w, r)
+
+			return
+		case !IsInLocalBlockItemFile(i.URI()):
+			u := i.URI()
+
+			http.Redirect(w, r, u.String(), http.StatusTemporaryRedirect)
+
+			return
+		default:
+			bfile = i
+		}
+
+		switch f, found, err := readers.ReadFileFromItemFile(height, bfile); {
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		case !found:
+			http.NotFound(w, r)
+		default:
+			defer func() {
+				_ = f.Close()
+			}()
+
+			w.Header().Set("Content-Type", "application/octet-stream")
+
+			http.ServeContent(w, r, filepath.Base(bfile.URI().Path), time.Time{}, f)
+		}
+	})
+}
+
 func IsInLocalBlockItemFile(uri url.URL) bool {
 	switch uri.Scheme {
-	case LocalFSBlockItemScheme, "file":
+	case LocalFSBlockItemScheme, "file", BlockArchiveBlockItemScheme:
 		return true
 	default:
 		return false
@@ -24,6 +24,7 @@ type Ballotbox struct {
 	isValidVoteprooff func(base.Voteproof, base.Suffrage) error
 	suffrageVotef     func(base.SuffrageExpelOperation) error
 	newBallotf        func(base.Ballot)
+	whenVotedf        func(_ base.BallotSignFact, inVoteproof bool)
 	getThreshold      func() base.Threshold
 	vpch              chan base.Voteproof
 	vrs               *util.ShardedMap[string, *voterecords]
@@ -55,6 +56,7 @@ func NewBallotbox(
 		isValidVoteprooff: func(base.Voteproof, base.Suffrage) error { return nil },
 		suffrageVotef:     func(base.SuffrageExpelOperation) error { return nil },
 		newBallotf:        func(base.Ballot) {},
+		whenVotedf:        func(base.BallotSignFact, bool) {},
 		countAfter:        time.Second * 5, //nolint:gomnd //...
 		interval:          time.Second,
 		removed:           util.EmptyLocked[[]*voterecords](),
@@ -84,6 +86,13 @@ func (box *Ballotbox) SetNewBallotFunc(f func(base.Ballot)) *Ballotbox {
 	return box
 }
 
+// SetWhenVotedFunc sets the function, which is called after ballot is voted.
+func (box *Ballotbox) SetWhenVotedFunc(f func(_ base.BallotSignFact, inVoteproof bool)) *Ballotbox {
+	box.whenVotedf = f
+
+	return box
+}
+
 func (box *Ballotbox) SetInterval(d time.Duration) *Ballotbox {
 	box.interval = d
 
@@ -115,6 +124,8 @@ func (box *Ballotbox) Vote(bl base.Ballot) (bool, error) {
 		return false, errors.WithMessage(err, "vote")
 	}
 
+	box.whenVotedf(bl.SignFact(), inVoteproof)
+
 	switch e := box.Log().Debug().
 		Interface("ballot", bl).
 		Interface("sign_fact", bl.SignFact()).
@@ -146,6 +157,8 @@ func (box *Ballotbox) VoteSignFact(sf base.BallotSignFact) (bool, error) {
 		return false, errors.WithMessage(err, "vote sign fact")
 	}
 
+	box.whenVotedf(sf, inVoteproof)
+
 	switch e := box.Log().Debug().Interface("sign_fact", sf).Bool("in_voteproof", inVoteproof); {
 	case inVoteproof:
 		e.Msg("ballot voted")
@@ -848,7 +861,7 @@ func (vr *voterecords) countFromVoted(
 	case majority == nil:
 		expelsnotyet = found
 	default:
-		vr.vp = vr.newVoteproof(wsfs, majority, threshold, expels)
+		vr.vp = vr.newVoteproof(wsfs, majority, threshold, expels, suf)
 
 		return vr.vp
 	}
@@ -872,7 +885,7 @@ func (vr *voterecords) countFromVoted(
 		return vp
 	}
 
-	vr.vp = vr.newVoteproof(sfs, majority, threshold, nil)
+	vr.vp = vr.newVoteproof(sfs, majority, threshold, nil, suf)
 
 	return vr.vp
 }
@@ -924,7 +937,17 @@ func (vr *voterecords) newVoteproof(
 	majority base.BallotFact,
 	threshold base.Threshold,
 	expels []base.SuffrageExpelOperation,
+	suf base.Suffrage,
 ) base.Voteproof {
+	if len(expels) < 1 && isaac.HasBLSSignFacts(sfs) {
+		switch vp, err := vr.newAggregatedVoteproof(sfs, majority, threshold, suf); {
+		case err != nil:
+			vr.log.Error().Err(err).Msg("failed to aggregate voteproof; fallback to voteproof")
+		default:
+			return vp
+		}
+	}
+
 	switch s := vr.sp.Stage(); {
 	case s == base.StageINIT && len(expels) > 0:
 		ivp := isaac.NewINITExpelVoteproof(vr.sp.Point)
@@ -969,6 +992,44 @@ func (vr *voterecords) newVoteproof(
 	}
 }
 
+func (vr *voterecords) newAggregatedVoteproof(
+	sfs []base.BallotSignFact,
+	majority base.BallotFact,
+	threshold base.Threshold,
+	suf base.Suffrage,
+) (base.Voteproof, error) {
+	switch s := vr.sp.Stage(); s {
+	case base.StageINIT:
+		ivp := isaac.NewINITAggregatedVoteproof(vr.sp.Point)
+		_ = ivp.
+			SetSignFacts(sfs).
+			SetMajority(majority).
+			SetThreshold(threshold).
+			Finish()
+
+		if err := ivp.Aggregate(suf); err != nil {
+			return nil, err
+		}
+
+		return ivp, nil
+	case base.StageACCEPT:
+		avp := isaac.NewACCEPTAggregatedVoteproof(vr.sp.Point)
+		_ = avp.
+			SetSignFacts(sfs).
+			SetMajority(majority).
+			SetThreshold(threshold).
+			Finish()
+
+		if err := avp.Aggregate(suf); err != nil {
+			return nil, err
+		}
+
+		return avp, nil
+	default:
+		return nil, errors.Errorf("unknown stage, %q", s)
+	}
+}
+
 func (vr *voterecords) getSuffrage() (base.Suffrage, bool, error) {
 	return vr.getSuffrageFunc(vr.sp.Height().SafePrev())
 }
@@ -291,6 +291,49 @@ func (t *testBallotbox) TestVoteINITBallotSignFact() {
 	}
 }
 
+func (t *testBallotbox) TestVoteBLSAggregated() {
+	suf, nodes := isaac.NewTestSuffrage(1,
+		isaac.NewLocalNode(base.NewBLSPrivatekey(), base.NewStringAddress("bls00")),
+		isaac.NewLocalNode(base.NewBLSPrivatekey(), base.NewStringAddress("bls01")),
+	)
+	th := base.Threshold(100)
+
+	box := NewBallotbox(
+		base.RandomAddress(""),
+		func() base.Threshold { return th },
+		func(base.Height) (base.Suffrage, bool, error) {
+			return suf, true, nil
+		},
+	)
+
+	point := base.RawPoint(33, 0)
+	prev := valuehash.RandomSHA256()
+	pr := valuehash.RandomSHA256()
+
+	for i := range nodes {
+		bl := t.initBallot(nodes[i], nodes, point, prev, pr, nil, nil)
+		box.SetLastPoint(mustNewLastPoint(bl.Voteproof().Point(), true, false))
+
+		voted, err := box.VoteSignFact(bl.SignFact())
+		t.NoError(err)
+		t.True(voted)
+	}
+
+	select {
+	case <-time.After(time.Second * 2):
+		t.Fail("failed to wait voteproof")
+	case vp := <-box.Voteproof():
+		avp, ok := vp.(isaac.INITAggregatedVoteproof)
+		t.True(ok, "%T", vp)
+
+		t.NoError(avp.IsValid(t.networkID))
+		t.Equal(base.VoteResultMajority, avp.Result())
+		t.Equal(3, len(avp.SignFacts()))
+
+		t.NoError(isaac.IsValidVoteproofWithSuffrage(avp, suf))
+	}
+}
+
 func (t *testBallotbox) TestVotePreviousRoundAlreadyMajority() {
 	suf, nodes := isaac.NewTestSuffrage(3)
 	th := base.Threshold(100)
@@ -24,6 +24,7 @@ type (
 		from, to base.Height,
 		batchlimit int64,
 		blockMapFunc func(context.Context, base.Height) (base.BlockMap, bool, error),
+		blockItemFunc isaacblock.ImportBlocksBlockItemFunc, // NOTE nil if not downloaded by syncer
 	) error
 )
 
@@ -34,7 +35,12 @@ type SyncerArgs struct {
 	WhenStoppedFunc      func() error
 	RemovePrevBlockFunc  func(base.Height) (bool, error)
 	NewImportBlocksFunc  NewImportBlocksFunc
+	SyncSourcePool       *isaac.SyncSourcePool
+	FetchBlockItemFunc   SyncerFetchBlockItemFunc
+	Checkpoint           *isaac.Checkpoint
 	BatchLimit           int64
+	BlockItemsWindow     int64
+	BlockItemSources     int
 	LastBlockMapInterval time.Duration
 	LastBlockMapTimeout  time.Duration
 }
@@ -47,10 +53,13 @@ func NewSyncerArgs() SyncerArgs {
 		RemovePrevBlockFunc: func(base.Height) (bool, error) { return false, nil },
 		NewImportBlocksFunc: func(context.Context, base.Height, base.Height, int64,
 			func(context.Context, base.Height) (base.BlockMap, bool, error),
+			isaacblock.ImportBlocksBlockItemFunc,
 		) error {
 			return errors.Errorf("nothing happened")
 		},
 		BatchLimit:           33, //nolint:gomnd // big enough size
+		BlockItemsWindow:     33, //nolint:gomnd //...
+		BlockItemSources:     3,  //nolint:gomnd //...
 		LastBlockMapInterval: time.Second * 2,
 		LastBlockMapTimeout:  isaac.DefaultTimeoutRequest,
 	}
@@ -338,13 +347,37 @@ func (s *Syncer) doSync(ctx context.Context, prev base.BlockMap, to base.Height)
 func (s *Syncer) prepareMaps(ctx context.Context, prev base.BlockMap, to base.Height) (base.BlockMap, error) {
 	var last base.BlockMap
 
+	// NOTE if checkpoint is over prev, the hash chain is checked up to the
+	// checkpoint.
+	checkpoint := s.args.Checkpoint
+	if checkpoint != nil && prev != nil && prev.Manifest().Height() >= checkpoint.Height() {
+		checkpoint = nil
+	}
+
+	top := to
+	if checkpoint != nil && checkpoint.Height() > top {
+		top = checkpoint.Height()
+	}
+
 	if err := base.BatchIsValidMaps(
 		ctx,
 		prev,
-		to,
+		top,
 		s.args.BatchLimit,
 		s.fetchMap,
 		func(m base.BlockMap) error {
+			if checkpoint != nil && m.Manifest().Height() == checkpoint.Height() {
+				if err := checkpoint.IsValidBlockMap(m); err != nil {
+					return errors.WithMessage(err, "checkpoint")
+				}
+
+				s.Log().Debug().Interface("checkpoint", checkpoint).Msg("checkpoint checked")
+			}
+
+			if m.Manifest().Height() > to {
+				return nil
+			}
+
 			if h := m.Manifest().Height(); h%100 == 0 || h == to {
 				s.Log().Debug().Interface("height", h).Msg("blockmap prepared")
 			}
@@ -416,14 +449,7 @@ func (s *Syncer) syncBlocks(ctx context.Context, prev base.BlockMap, to base.Hei
 	}
 
 	if err := util.Retry(ctx, func() (bool, error) {
-		if err := s.args.NewImportBlocksFunc(
-			ctx,
-			from, to,
-			s.args.BatchLimit,
-			func(_ context.Context, height base.Height) (base.BlockMap, bool, error) {
-				return s.args.TempSyncPool.BlockMap(height)
-			},
-		); err != nil {
+		if err := s.importBlocks(ctx, from, to); err != nil {
 			s.Log().Error().Err(err).
 				Interface("from", from).
 				Interface("to", to).
@@ -443,6 +469,54 @@ func (s *Syncer) syncBlocks(ctx context.Context, prev base.BlockMap, to base.Hei
 	return nil
 }
 
+// importBlocks imports blocks; if SyncSourcePool and FetchBlockItemFunc are
+// set, the block items are downloaded from the multiple sync sources. Without
+// sync sources in pool, like syncing only from the block mirrors, the block
+// items are read by NewImportBlocksFunc.
+func (s *Syncer) importBlocks(ctx context.Context, from, to base.Height) error {
+	blockMapf := func(_ context.Context, height base.Height) (base.BlockMap, bool, error) {
+		return s.args.TempSyncPool.BlockMap(height)
+	}
+
+	if s.args.SyncSourcePool == nil || s.args.FetchBlockItemFunc == nil || s.args.SyncSourcePool.Len() < 1 {
+		return s.args.NewImportBlocksFunc(ctx, from, to, s.args.BatchLimit, blockMapf, nil)
+	}
+
+	dctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	window := s.args.BlockItemsWindow
+	if window < 1 {
+		window = s.args.BatchLimit
+	}
+
+	d := newSyncerBlockItemsDownloader(
+		s.args.SyncSourcePool,
+		s.args.FetchBlockItemFunc,
+		blockMapf,
+		from, to,
+		s.args.BlockItemSources,
+		window,
+	)
+	_ = d.SetLogging(s.Logging)
+
+	donech := make(chan error, 1)
+
+	go func() {
+		donech <- d.run(dctx)
+	}()
+
+	// NOTE the block items are downloaded concurrently, but the blocks are
+	// imported in order.
+	err := s.args.NewImportBlocksFunc(dctx, from, to, s.args.BatchLimit, blockMapf, d.blockItem)
+
+	cancel()
+
+	<-donech
+
+	return err
+}
+
 func (s *Syncer) updateLastBlockMap(ctx context.Context) {
 	var last util.Hash
 
@@ -0,0 +1,477 @@
+package isaacstates
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"io"
+	"sync"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// SyncerFetchBlockItemFunc fetches the block item from the sync source. The
+// returned bytes are the compressed item body.
+type SyncerFetchBlockItemFunc func(
+	_ context.Context, _ isaac.NodeConnInfo, _ base.Height, _ base.BlockItemType,
+) (_ []byte, compressFormat string, found bool, _ error)
+
+type syncerBlockItemKey struct {
+	t      base.BlockItemType
+	height base.Height
+}
+
+type syncerBlockItem struct {
+	compressFormat string
+	b              []byte
+}
+
+type syncerBlockItemJob struct {
+	item   base.BlockMapItem
+	height base.Height
+}
+
+// syncerBlockItemsDownloader downloads the block items from the multiple sync
+// sources concurrently. The heights are divided by window; in each window, the
+// block items are assigned to the sync sources by continuous height range and
+// the idle source steals the jobs of the other sources. The downloaded items
+// are kept until they are consumed by blockItem.
+type syncerBlockItemsDownloader struct {
+	*logging.Logging
+	pool      *isaac.SyncSourcePool
+	fetchf    SyncerFetchBlockItemFunc
+	blockMapf func(context.Context, base.Height) (base.BlockMap, bool, error)
+	items     map[syncerBlockItemKey]syncerBlockItem
+	updatedch chan struct{}
+	err       error
+	from      base.Height
+	to        base.Height
+	consumed  base.Height
+	window    int64
+	sources   int
+	sync.Mutex
+}
+
+func newSyncerBlockItemsDownloader(
+	pool *isaac.SyncSourcePool,
+	fetchf SyncerFetchBlockItemFunc,
+	blockMapf func(context.Context, base.Height) (base.BlockMap, bool, error),
+	from, to base.Height,
+	sources int,
+	window int64,
+) *syncerBlockItemsDownloader {
+	return &syncerBlockItemsDownloader{
+		Logging: logging.NewLogging(func(lctx zerolog.Context) zerolog.Context {
+			return lctx.Str("module", "syncer-block-items-downloader")
+		}),
+		pool:      pool,
+		fetchf:    fetchf,
+		blockMapf: blockMapf,
+		from:      from,
+		to:        to,
+		sources:   sources,
+		window:    window,
+		consumed:  from - 1,
+		items:     map[syncerBlockItemKey]syncerBlockItem{},
+		updatedch: make(chan struct{}),
+	}
+}
+
+// run downloads all the block items; the result is delivered by blockItem.
+func (d *syncerBlockItemsDownloader) run(ctx context.Context) error {
+	err := d.download(ctx)
+
+	_ = d.update(func() bool {
+		switch {
+		case err != nil:
+			d.err = err
+		default:
+			d.err = errors.Errorf("block item not downloaded")
+		}
+
+		return true
+	})
+
+	return err
+}
+
+func (d *syncerBlockItemsDownloader) download(ctx context.Context) error {
+	for start := d.from; start <= d.to; start += base.Height(d.window) {
+		end := start + base.Height(d.window) - 1
+		if end > d.to {
+			end = d.to
+		}
+
+		// NOTE wait until the previous window is consumed; the downloaded
+		// items are kept up to 2 windows.
+		if err := d.waitConsumed(ctx, start-base.Height(d.window)); err != nil {
+			return err
+		}
+
+		jobs, err := d.jobs(ctx, start, end)
+		if err != nil {
+			return err
+		}
+
+		if err := d.downloadJobs(ctx, jobs); err != nil {
+			return errors.WithMessagef(err, "download block items, %d - %d", start, end)
+		}
+
+		d.Log().Debug().Interface("from", start).Interface("to", end).Msg("block items downloaded")
+	}
+
+	return nil
+}
+
+func (d *syncerBlockItemsDownloader) jobs(ctx context.Context, from, to base.Height) ([]syncerBlockItemJob, error) {
+	var jobs []syncerBlockItemJob
+
+	for height := from; height <= to; height++ {
+		switch m, found, err := d.blockMapf(ctx, height); {
+		case err != nil:
+			return nil, err
+		case !found:
+			return nil, util.ErrNotFound.Errorf("BlockMap not found, %d", height)
+		default:
+			m.Items(func(item base.BlockMapItem) bool {
+				jobs = append(jobs, syncerBlockItemJob{height: height, item: item})
+
+				return true
+			})
+		}
+	}
+
+	return jobs, nil
+}
+
+func (d *syncerBlockItemsDownloader) downloadJobs(ctx context.Context, jobs []syncerBlockItemJob) error {
+	if len(jobs) < 1 {
+		return nil
+	}
+
+	ncis, reports, err := d.pool.PickMultiple(d.sources)
+	if err != nil {
+		return err
+	}
+
+	queues := newSyncerBlockItemQueues(len(ncis), jobs)
+
+	var wg sync.WaitGroup
+	wg.Add(len(ncis))
+
+	for i := range ncis {
+		go func(i int) {
+			defer wg.Done()
+
+			for {
+				job, found := queues.pop(i)
+				if !found {
+					return
+				}
+
+				err := d.downloadJob(ctx, ncis[i], job)
+
+				queues.done(i, job, err)
+
+				if err != nil {
+					if ctx.Err() == nil {
+						d.Log().Error().Err(err).
+							Interface("source", ncis[i]).
+							Interface("height", job.height).
+							Interface("item", job.item.Type()).
+							Msg("failed to download block item; source stopped")
+
+						// NOTE the source is excluded from the sync sources for
+						// a while.
+						reports[i](isaac.ErrRetrySyncSources.Wrap(err))
+					}
+
+					return
+				}
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	switch {
+	case queues.len() < 1:
+		return nil
+	case ctx.Err() != nil:
+		return ctx.Err()
+	default:
+		return isaac.ErrRetrySyncSources.Errorf("failed to download block items from all sync sources")
+	}
+}
+
+func (d *syncerBlockItemsDownloader) downloadJob(
+	ctx context.Context, nci isaac.NodeConnInfo, job syncerBlockItemJob,
+) error {
+	switch b, compressFormat, found, err := d.fetchf(ctx, nci, job.height, job.item.Type()); {
+	case err != nil:
+		return err
+	case !found:
+		return util.ErrNotFound.Errorf("block item not found, %d, %q", job.height, job.item.Type())
+	default:
+		if err := isValidSyncerBlockItemChecksum(job.item, b, compressFormat); err != nil {
+			return err
+		}
+
+		_ = d.update(func() bool {
+			d.items[syncerBlockItemKey{height: job.height, t: job.item.Type()}] = syncerBlockItem{
+				b:              b,
+				compressFormat: compressFormat,
+			}
+
+			return true
+		})
+
+		return nil
+	}
+}
+
+// blockItem waits the block item downloaded; it follows
+// isaacblock.ImportBlocksBlockItemFunc.
+func (d *syncerBlockItemsDownloader) blockItem(
+	ctx context.Context, height base.Height, t base.BlockItemType, f func(io.Reader, bool, string) error,
+) error {
+	key := syncerBlockItemKey{height: height, t: t}
+
+	for {
+		var item syncerBlockItem
+		var found bool
+		var err error
+
+		updatedch := d.update(func() bool {
+			item, found = d.items[key]
+			if found {
+				delete(d.items, key)
+			}
+
+			err = d.err
+
+			if height > d.consumed {
+				d.consumed = height
+
+				return true
+			}
+
+			return false
+		})
+
+		switch {
+		case found:
+			return f(bytes.NewReader(item.b), true, item.compressFormat)
+		case err != nil:
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-updatedch:
+		}
+	}
+}
+
+func (d *syncerBlockItemsDownloader) waitConsumed(ctx context.Context, height base.Height) error {
+	for {
+		var consumed base.Height
+
+		updatedch := d.update(func() bool {
+			consumed = d.consumed
+
+			return false
+		})
+
+		if consumed >= height {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-updatedch:
+		}
+	}
+}
+
+// update runs f; if f returns true, it wakes up the waiters. update returns
+// the channel, which will be closed by the next update.
+func (d *syncerBlockItemsDownloader) update(f func() bool) chan struct{} {
+	d.Lock()
+	defer d.Unlock()
+
+	if f() {
+		close(d.updatedch)
+		d.updatedch = make(chan struct{})
+	}
+
+	return d.updatedch
+}
+
+func isValidSyncerBlockItemChecksum(item base.BlockMapItem, b []byte, compressFormat string) error {
+	e := util.ErrInvalid.Errorf("invalid block item, %q", item.Type())
+
+	cr, err := util.NewCompressedReader(bytes.NewReader(b), compressFormat, nil)
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	defer func() {
+		_ = cr.Close()
+	}()
+
+	r, err := cr.Decompress()
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	cw := util.NewHashChecksumWriter(sha256.New())
+	defer func() {
+		_ = cw.Close()
+	}()
+
+	if _, err := io.Copy(cw, r); err != nil {
+		return e.Wrap(err)
+	}
+
+	if cw.Checksum() != item.Checksum() {
+		return e.Errorf("checksum does not match, expected=%q != %q", item.Checksum(), cw.Checksum())
+	}
+
+	return nil
+}
+
+// syncerBlockItemQueues keeps the jobs of each source. The jobs are divided by
+// continuous range; when the queue of source is empty, it steals the job from
+// the end of the longest queue of the other source. When the job of source
+// fails, the job is returned to the queue and the source is stopped; the other
+// sources will steal it.
+type syncerBlockItemQueues struct {
+	cond     *sync.Cond
+	queues   [][]syncerBlockItemJob
+	stopped  []bool
+	inflight int
+	sync.Mutex
+}
+
+func newSyncerBlockItemQueues(n int, jobs []syncerBlockItemJob) *syncerBlockItemQueues {
+	queues := make([][]syncerBlockItemJob, n)
+
+	size := len(jobs) / n
+	if len(jobs)%n != 0 {
+		size++
+	}
+
+	for i := range queues {
+		start := i * size
+		if start >= len(jobs) {
+			break
+		}
+
+		end := start + size
+		if end > len(jobs) {
+			end = len(jobs)
+		}
+
+		queues[i] = jobs[start:end:end]
+	}
+
+	q := &syncerBlockItemQueues{
+		queues:  queues,
+		stopped: make([]bool, n),
+	}
+
+	q.cond = sync.NewCond(&q.Mutex)
+
+	return q
+}
+
+// pop returns the next job of source; if no job left, it waits until the
+// running jobs of the other sources are done.
+func (q *syncerBlockItemQueues) pop(i int) (syncerBlockItemJob, bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	for {
+		if q.stopped[i] {
+			return syncerBlockItemJob{}, false
+		}
+
+		if job, found := q.next(i); found {
+			q.inflight++
+
+			return job, true
+		}
+
+		if q.inflight < 1 {
+			return syncerBlockItemJob{}, false
+		}
+
+		q.cond.Wait()
+	}
+}
+
+func (q *syncerBlockItemQueues) next(i int) (syncerBlockItemJob, bool) {
+	if len(q.queues[i]) > 0 {
+		job := q.queues[i][0]
+		q.queues[i] = q.queues[i][1:]
+
+		return job, true
+	}
+
+	longest := -1
+
+	for j := range q.queues {
+		if len(q.queues[j]) < 1 {
+			continue
+		}
+
+		if longest < 0 || len(q.queues[j]) > len(q.queues[longest]) {
+			longest = j
+		}
+	}
+
+	if longest < 0 {
+		return syncerBlockItemJob{}, false
+	}
+
+	last := len(q.queues[longest]) - 1
+	job := q.queues[longest][last]
+	q.queues[longest] = q.queues[longest][:last]
+
+	return job, true
+}
+
+func (q *syncerBlockItemQueues) done(i int, job syncerBlockItemJob, err error) {
+	q.Lock()
+	defer q.Unlock()
+
+	q.inflight--
+
+	if err != nil {
+		q.queues[i] = append([]syncerBlockItemJob{job}, q.queues[i]...)
+		q.stopped[i] = true
+	}
+
+	q.cond.Broadcast()
+}
+
+func (q *syncerBlockItemQueues) len() int {
+	q.Lock()
+	defer q.Unlock()
+
+	var n int
+
+	for i := range q.queues {
+		n += len(q.queues[i])
+	}
+
+	return n
+}
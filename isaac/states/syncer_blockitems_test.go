@@ -0,0 +1,365 @@
+package isaacstates
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	isaacblock "github.com/ProtoconNet/mitum2/isaac/block"
+	isaacdatabase "github.com/ProtoconNet/mitum2/isaac/database"
+	"github.com/ProtoconNet/mitum2/network/quicmemberlist"
+	"github.com/ProtoconNet/mitum2/network/quicstream"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/valuehash"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/suite"
+)
+
+type dummySyncSource struct {
+	quicmemberlist.NamedConnInfo
+	base.BaseNode
+}
+
+func (n dummySyncSource) IsValid([]byte) error {
+	return nil
+}
+
+type testSyncerBlockItemsDownloader struct {
+	isaac.BaseTestBallots
+	maps map[base.Height]base.BlockMap
+}
+
+func (t *testSyncerBlockItemsDownloader) SetupTest() {
+	t.BaseTestBallots.SetupTest()
+
+	t.maps = map[base.Height]base.BlockMap{}
+}
+
+func (t *testSyncerBlockItemsDownloader) itemBody(height base.Height, item base.BlockItemType) []byte {
+	return []byte(fmt.Sprintf("%d-%s", height, item))
+}
+
+func (t *testSyncerBlockItemsDownloader) prepareMaps(from, to base.Height) {
+	var previous util.Hash
+	suffrage := valuehash.RandomSHA256()
+
+	for height := from; height <= to; height++ {
+		m := isaacblock.NewBlockMap()
+
+		for _, i := range []base.BlockItemType{
+			base.BlockItemProposal,
+			base.BlockItemOperations,
+			base.BlockItemStates,
+			base.BlockItemVoteproofs,
+		} {
+			t.NoError(m.SetItem(isaacblock.NewBlockMapItem(i, util.SHA256Checksum(t.itemBody(height, i)))))
+		}
+
+		manifest := base.NewDummyManifest(height, valuehash.RandomSHA256())
+		manifest.SetPrevious(previous)
+		manifest.SetSuffrage(suffrage)
+
+		m.SetManifest(manifest)
+		t.NoError(m.Sign(t.Local.Address(), t.Local.Privatekey(), t.LocalParams.NetworkID()))
+
+		t.maps[height] = m
+		previous = manifest.Hash()
+	}
+}
+
+func (t *testSyncerBlockItemsDownloader) blockMapf(_ context.Context, height base.Height) (base.BlockMap, bool, error) {
+	m, found := t.maps[height]
+
+	return m, found, nil
+}
+
+func (t *testSyncerBlockItemsDownloader) sources(n int) []isaac.NodeConnInfo {
+	cis := quicstream.RandomConnInfos(n)
+
+	ncis := make([]isaac.NodeConnInfo, n)
+
+	for i := range cis {
+		ci, err := quicmemberlist.NewNamedConnInfo(cis[i].Addr().String(), true)
+		t.NoError(err)
+
+		ncis[i] = dummySyncSource{
+			BaseNode:      isaac.NewNode(base.NewMPrivatekey().Publickey(), base.RandomAddress("")),
+			NamedConnInfo: ci,
+		}
+	}
+
+	return ncis
+}
+
+// consume reads all the block items in order, like ImportBlocks.
+func (t *testSyncerBlockItemsDownloader) consume(
+	ctx context.Context, d *syncerBlockItemsDownloader, from, to base.Height,
+) error {
+	for height := from; height <= to; height++ {
+		var err error
+
+		t.maps[height].Items(func(item base.BlockMapItem) bool {
+			err = d.blockItem(ctx, height, item.Type(), func(r io.Reader, found bool, compressFormat string) error {
+				if !found {
+					return errors.Errorf("not found")
+				}
+
+				b, err := io.ReadAll(r)
+				if err != nil {
+					return err
+				}
+
+				t.Equal(t.itemBody(height, item.Type()), b)
+
+				return nil
+			})
+
+			return err == nil
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *testSyncerBlockItemsDownloader) run(
+	d *syncerBlockItemsDownloader, from, to base.Height,
+) (runerr error, consumeerr error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	donech := make(chan error, 1)
+
+	go func() {
+		donech <- d.run(ctx)
+	}()
+
+	consumeerr = t.consume(ctx, d, from, to)
+
+	cancel()
+
+	return <-donech, consumeerr
+}
+
+func (t *testSyncerBlockItemsDownloader) TestDownload() {
+	from, to := base.Height(3), base.Height(33)
+	t.prepareMaps(from, to)
+
+	sources := t.sources(3)
+	pool := isaac.NewSyncSourcePool(sources)
+
+	var l sync.Mutex
+	served := map[string]int{}
+
+	fetchf := func(_ context.Context, nci isaac.NodeConnInfo, height base.Height, item base.BlockItemType) (
+		[]byte, string, bool, error,
+	) {
+		l.Lock()
+		served[nci.Address().String()]++
+		l.Unlock()
+
+		<-time.After(time.Millisecond * 3)
+
+		return t.itemBody(height, item), "", true, nil
+	}
+
+	d := newSyncerBlockItemsDownloader(pool, fetchf, t.blockMapf, from, to, 3, 5)
+
+	runerr, consumeerr := t.run(d, from, to)
+	t.NoError(runerr)
+	t.NoError(consumeerr)
+
+	t.Equal(3, len(served), "all sources should be used")
+
+	var total int
+	for i := range served {
+		total += served[i]
+	}
+
+	t.Equal(int(to-from+1)*4, total)
+	t.Empty(d.items)
+}
+
+func (t *testSyncerBlockItemsDownloader) TestFailedSource() {
+	from, to := base.Height(3), base.Height(13)
+	t.prepareMaps(from, to)
+
+	sources := t.sources(3)
+	pool := isaac.NewSyncSourcePool(sources)
+
+	failed := sources[1].Address()
+
+	fetchf := func(_ context.Context, nci isaac.NodeConnInfo, height base.Height, item base.BlockItemType) (
+		[]byte, string, bool, error,
+	) {
+		if nci.Address().Equal(failed) {
+			return nil, "", false, errors.Errorf("hehehe")
+		}
+
+		<-time.After(time.Millisecond * 3)
+
+		return t.itemBody(height, item), "", true, nil
+	}
+
+	d := newSyncerBlockItemsDownloader(pool, fetchf, t.blockMapf, from, to, 3, 100)
+
+	runerr, consumeerr := t.run(d, from, to)
+	t.NoError(runerr)
+	t.NoError(consumeerr)
+
+	t.True(pool.IsInFixed(failed))
+
+	pool.Actives(func(nci isaac.NodeConnInfo) bool {
+		t.False(nci.Address().Equal(failed), "failed source should be reported")
+
+		return true
+	})
+}
+
+func (t *testSyncerBlockItemsDownloader) TestWrongChecksum() {
+	from, to := base.Height(3), base.Height(13)
+	t.prepareMaps(from, to)
+
+	sources := t.sources(2)
+	pool := isaac.NewSyncSourcePool(sources)
+
+	wrong := sources[0].Address()
+
+	fetchf := func(_ context.Context, nci isaac.NodeConnInfo, height base.Height, item base.BlockItemType) (
+		[]byte, string, bool, error,
+	) {
+		if nci.Address().Equal(wrong) {
+			return []byte("showme"), "", true, nil
+		}
+
+		return t.itemBody(height, item), "", true, nil
+	}
+
+	d := newSyncerBlockItemsDownloader(pool, fetchf, t.blockMapf, from, to, 2, 100)
+
+	runerr, consumeerr := t.run(d, from, to)
+	t.NoError(runerr)
+	t.NoError(consumeerr)
+}
+
+func (t *testSyncerBlockItemsDownloader) TestAllSourcesFailed() {
+	from, to := base.Height(3), base.Height(13)
+	t.prepareMaps(from, to)
+
+	pool := isaac.NewSyncSourcePool(t.sources(2))
+
+	fetchf := func(context.Context, isaac.NodeConnInfo, base.Height, base.BlockItemType) (
+		[]byte, string, bool, error,
+	) {
+		return []byte("showme"), "", true, nil
+	}
+
+	d := newSyncerBlockItemsDownloader(pool, fetchf, t.blockMapf, from, to, 2, 100)
+
+	runerr, consumeerr := t.run(d, from, to)
+	t.Error(runerr)
+	t.ErrorContains(runerr, "failed to download block items from all sync sources")
+
+	t.Error(consumeerr)
+	t.ErrorContains(consumeerr, "failed to download block items from all sync sources")
+}
+
+func (t *testSyncerBlockItemsDownloader) TestSyncer() {
+	lastheight := base.Height(3)
+	to := lastheight + 20
+	t.prepareMaps(lastheight, to)
+
+	pool := isaac.NewSyncSourcePool(t.sources(3))
+
+	args := NewSyncerArgs()
+	args.TempSyncPool = isaacdatabase.NewMemTempSyncPool()
+	args.BlockMapFunc = t.blockMapf
+	args.SyncSourcePool = pool
+	args.FetchBlockItemFunc = func(_ context.Context, _ isaac.NodeConnInfo, height base.Height, item base.BlockItemType) (
+		[]byte, string, bool, error,
+	) {
+		return t.itemBody(height, item), "", true, nil
+	}
+	args.BatchLimit = 3
+	args.BlockItemsWindow = 4
+
+	var imported []base.Height
+
+	args.NewImportBlocksFunc = func(
+		ctx context.Context,
+		from, to base.Height,
+		_ int64,
+		blockMapf func(context.Context, base.Height) (base.BlockMap, bool, error),
+		blockItemf isaacblock.ImportBlocksBlockItemFunc,
+	) error {
+		if blockItemf == nil {
+			return errors.Errorf("empty blockItemf")
+		}
+
+		for height := from; height <= to; height++ {
+			m, found, err := blockMapf(ctx, height)
+			switch {
+			case err != nil:
+				return err
+			case !found:
+				return errors.Errorf("blockmap not found")
+			}
+
+			m.Items(func(item base.BlockMapItem) bool {
+				err = blockItemf(ctx, height, item.Type(), func(r io.Reader, found bool, _ string) error {
+					b, err := io.ReadAll(r)
+					if err != nil {
+						return err
+					}
+
+					t.Equal(t.itemBody(height, item.Type()), b)
+
+					return nil
+				})
+
+				return err == nil
+			})
+
+			if err != nil {
+				return err
+			}
+
+			imported = append(imported, height)
+		}
+
+		return nil
+	}
+
+	s := NewSyncer(t.maps[lastheight], args)
+	t.NoError(s.Start(context.Background()))
+	defer s.Cancel()
+
+	t.True(s.Add(to))
+
+	select {
+	case <-time.After(time.Second * 10):
+		t.Fail("failed to wait")
+	case height := <-s.Finished():
+		t.Equal(to, height)
+	case <-s.Done():
+		t.NoError(s.Err())
+	}
+
+	t.Equal(int(to-lastheight), len(imported))
+
+	for i := range imported {
+		t.Equal(lastheight+base.Height(i)+1, imported[i])
+	}
+}
+
+func TestSyncerBlockItemsDownloader(t *testing.T) {
+	suite.Run(t, new(testSyncerBlockItemsDownloader))
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/ProtoconNet/mitum2/util"
 	"github.com/ProtoconNet/mitum2/util/encoder"
 	"github.com/ProtoconNet/mitum2/util/fixedtree"
+	"github.com/ProtoconNet/mitum2/util/valuehash"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/goleak"
@@ -322,6 +323,7 @@ func (t *testSyncer) TestFetchMaps() {
 			from, to base.Height,
 			batchlimit int64,
 			blockMapf func(context.Context, base.Height) (base.BlockMap, bool, error),
+			_ isaacblock.ImportBlocksBlockItemFunc,
 		) error {
 			return isaacblock.ImportBlocks(
 				ctx,
@@ -375,6 +377,7 @@ func (t *testSyncer) TestFetchMaps() {
 			from, to base.Height,
 			batchlimit int64,
 			blockMapf func(context.Context, base.Height) (base.BlockMap, bool, error),
+			_ isaacblock.ImportBlocksBlockItemFunc,
 		) error {
 			return isaacblock.ImportBlocks(
 				ctx,
@@ -423,6 +426,7 @@ func (t *testSyncer) TestFetchMaps() {
 			from, to base.Height,
 			batchlimit int64,
 			blockMapf func(context.Context, base.Height) (base.BlockMap, bool, error),
+			_ isaacblock.ImportBlocksBlockItemFunc,
 		) error {
 			return isaacblock.ImportBlocks(
 				ctx,
@@ -471,6 +475,7 @@ func (t *testSyncer) TestFetchMaps() {
 			from, to base.Height,
 			batchlimit int64,
 			blockMapf func(context.Context, base.Height) (base.BlockMap, bool, error),
+			_ isaacblock.ImportBlocksBlockItemFunc,
 		) error {
 			return isaacblock.ImportBlocks(
 				ctx,
@@ -541,6 +546,7 @@ func (t *testSyncer) TestFetchMaps() {
 			from, to base.Height,
 			batchlimit int64,
 			blockMapf func(context.Context, base.Height) (base.BlockMap, bool, error),
+			_ isaacblock.ImportBlocksBlockItemFunc,
 		) error {
 			return isaacblock.ImportBlocks(
 				ctx,
@@ -572,6 +578,123 @@ func (t *testSyncer) TestFetchMaps() {
 	})
 }
 
+func (t *testSyncer) TestCheckpoint() {
+	newargs := func(maps []base.BlockMap, fetched *sync.Map) SyncerArgs {
+		args := t.newargs()
+		args.BlockMapFunc = func(_ context.Context, height base.Height) (base.BlockMap, bool, error) {
+			index := (height - base.GenesisHeight).Int64()
+			if index < 0 || index >= int64(len(maps)) {
+				return nil, false, nil
+			}
+
+			fetched.Store(height, true)
+
+			return maps[index], true, nil
+		}
+		args.NewImportBlocksFunc = func(
+			context.Context, base.Height, base.Height, int64,
+			func(context.Context, base.Height) (base.BlockMap, bool, error),
+			isaacblock.ImportBlocksBlockItemFunc,
+		) error {
+			return nil
+		}
+
+		return args
+	}
+
+	t.Run("checked over to", func() {
+		to := base.Height(5)
+		maps := t.maps(base.GenesisHeight, to+5)
+
+		checkpoint := isaac.NewCheckpoint(to+3, maps[(to+3).Int64()].Manifest().Hash())
+
+		var fetched sync.Map
+
+		args := newargs(maps, &fetched)
+		args.Checkpoint = &checkpoint
+
+		s := NewSyncer(nil, args)
+		t.NoError(s.Start(context.Background()))
+		defer s.Cancel()
+
+		t.True(s.Add(to))
+
+		select {
+		case <-time.After(time.Second * 3):
+			t.Fail("failed to wait")
+		case height := <-s.Finished():
+			t.Equal(to, height)
+		case <-s.Done():
+			t.NoError(s.Err())
+		}
+
+		_, found := fetched.Load(checkpoint.Height())
+		t.True(found, "checkpoint blockmap should be fetched")
+
+		_, found = fetched.Load(checkpoint.Height() + 1)
+		t.False(found)
+
+		_, found, err := args.TempSyncPool.BlockMap(to + 1)
+		t.NoError(err)
+		t.False(found, "blockmap over to should not be stored")
+	})
+
+	t.Run("different manifest", func() {
+		to := base.Height(5)
+		maps := t.maps(base.GenesisHeight, to)
+
+		checkpoint := isaac.NewCheckpoint(to-1, maps[(to-2).Int64()].Manifest().Hash())
+
+		var fetched sync.Map
+
+		args := newargs(maps, &fetched)
+		args.Checkpoint = &checkpoint
+
+		s := NewSyncer(nil, args)
+		t.NoError(s.Start(context.Background()))
+		defer s.Cancel()
+
+		t.True(s.Add(to))
+
+		select {
+		case <-time.After(time.Second * 3):
+			t.Fail("failed to wait")
+		case <-s.Finished():
+			t.Fail("should be failed")
+		case <-s.Done():
+			t.Error(s.Err())
+			t.ErrorContains(s.Err(), "different manifest hash")
+		}
+	})
+
+	t.Run("prev over checkpoint", func() {
+		to := base.Height(5)
+		maps := t.maps(base.GenesisHeight, to)
+
+		checkpoint := isaac.NewCheckpoint(base.Height(2), valuehash.RandomSHA256())
+
+		var fetched sync.Map
+
+		args := newargs(maps, &fetched)
+		args.Checkpoint = &checkpoint
+
+		s := NewSyncer(maps[3], args)
+		t.NoError(s.Start(context.Background()))
+		defer s.Cancel()
+
+		t.True(s.Add(to))
+
+		select {
+		case <-time.After(time.Second * 3):
+			t.Fail("failed to wait")
+		case height := <-s.Finished():
+			t.Equal(to, height)
+		case <-s.Done():
+			t.NoError(s.Err())
+		}
+	})
+}
+
 func (t *testSyncer) TestFetchBlockItem() {
 	lastheight := base.Height(3)
 	to := lastheight + 10
@@ -591,6 +714,7 @@ func (t *testSyncer) TestFetchBlockItem() {
 		from, to base.Height,
 		batchlimit int64,
 		blockMapf func(context.Context, base.Height) (base.BlockMap, bool, error),
+		_ isaacblock.ImportBlocksBlockItemFunc,
 	) error {
 		return isaacblock.ImportBlocks(
 			ctx,
@@ -670,6 +794,7 @@ func (t *testSyncer) TestFetchDifferentMap() {
 		from, to base.Height,
 		batchlimit int64,
 		blockMapf func(context.Context, base.Height) (base.BlockMap, bool, error),
+		_ isaacblock.ImportBlocksBlockItemFunc,
 	) error {
 		return isaacblock.ImportBlocks(
 			ctx,
@@ -7,8 +7,14 @@ import (
 )
 
 type Suffrage struct {
-	m  map[string]base.Node
-	ns []base.Node
+	m         map[string]base.Node
+	rotations map[string]suffrageKeyRotation
+	ns        []base.Node
+}
+
+type suffrageKeyRotation struct {
+	pub    base.Publickey
+	height base.Height
 }
 
 func NewSuffrage(nodes []base.Node) (Suffrage, error) {
@@ -67,6 +73,66 @@ func (suf Suffrage) Len() int {
 	return len(suf.ns)
 }
 
+// KeyRotated returns the suffrage for the ballots of the next height of the
+// given height; the publickey of node is switched to the rotated one from the
+// rotate height.
+func (suf Suffrage) KeyRotated(height base.Height) (Suffrage, error) {
+	if len(suf.rotations) < 1 {
+		return suf, nil
+	}
+
+	var rotated bool
+
+	nodes := make([]base.Node, len(suf.ns))
+	rotations := map[string]suffrageKeyRotation{}
+
+	for i := range suf.ns {
+		n := suf.ns[i]
+		nodes[i] = n
+
+		switch r, found := suf.rotations[n.Address().String()]; {
+		case !found:
+		case height+1 >= r.height:
+			nodes[i] = NewNode(r.pub, n.Address())
+			rotated = true
+		default:
+			rotations[n.Address().String()] = r
+		}
+	}
+
+	if !rotated {
+		return suf, nil
+	}
+
+	nsuf, err := NewSuffrage(nodes)
+	if err != nil {
+		return suf, err
+	}
+
+	if len(rotations) > 0 {
+		nsuf.rotations = rotations
+	}
+
+	return nsuf, nil
+}
+
+func (suf Suffrage) existsRotatePublickey(node base.Address, pub base.Publickey) bool {
+	r, found := suf.rotations[node.String()]
+
+	return found && r.pub.Equal(pub)
+}
+
+// KeyRotatedSuffrage applies the key rotations of suffrage for the given
+// height; see Suffrage.KeyRotated.
+func KeyRotatedSuffrage(suf base.Suffrage, height base.Height) (base.Suffrage, error) {
+	i, ok := suf.(Suffrage)
+	if !ok {
+		return suf, nil
+	}
+
+	return i.KeyRotated(height)
+}
+
 func NewSuffrageWithExpels(
 	suf base.Suffrage,
 	threshold base.Threshold,
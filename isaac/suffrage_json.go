@@ -14,7 +14,15 @@ func (suf Suffrage) MarshalJSON() ([]byte, error) {
 }
 
 type suffrageNodeStateValueJSONMarshaler struct {
-	Start base.Height `json:"start"`
+	RotatePublickey base.Publickey `json:"rotate_publickey,omitempty"`
+	Start           base.Height    `json:"start"`
+	RotateHeight    base.Height    `json:"rotate_height,omitempty"`
+}
+
+type suffrageNodeStateValueJSONUnmarshaler struct {
+	RotatePublickey string      `json:"rotate_publickey,omitempty"`
+	Start           base.Height `json:"start"`
+	RotateHeight    base.Height `json:"rotate_height,omitempty"`
 }
 
 func (s SuffrageNodeStateValue) MarshalJSON() ([]byte, error) {
@@ -29,7 +37,9 @@ func (s SuffrageNodeStateValue) MarshalJSON() ([]byte, error) {
 			Publickey: s.Publickey(),
 		},
 		suffrageNodeStateValueJSONMarshaler: suffrageNodeStateValueJSONMarshaler{
-			Start: s.start,
+			Start:           s.start,
+			RotatePublickey: s.rotatePublickey,
+			RotateHeight:    s.rotateHeight,
 		},
 	})
 }
@@ -37,13 +47,23 @@ func (s SuffrageNodeStateValue) MarshalJSON() ([]byte, error) {
 func (s *SuffrageNodeStateValue) DecodeJSON(b []byte, enc encoder.Encoder) error {
 	e := util.StringError("decode SuffrageNodeStateValue")
 
-	var u suffrageNodeStateValueJSONMarshaler
+	var u suffrageNodeStateValueJSONUnmarshaler
 	if err := enc.Unmarshal(b, &u); err != nil {
 		return e.Wrap(err)
 	}
 
 	s.start = u.Start
 
+	if len(u.RotatePublickey) > 0 {
+		pub, err := base.DecodePublickeyFromString(u.RotatePublickey, enc)
+		if err != nil {
+			return e.Wrap(err)
+		}
+
+		s.rotatePublickey = pub
+		s.rotateHeight = u.RotateHeight
+	}
+
 	var ub base.BaseNode
 
 	if err := ub.DecodeJSON(b, enc); err != nil {
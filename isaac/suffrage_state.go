@@ -31,10 +31,18 @@ func NewSuffrageFromState(st base.State) (suf base.Suffrage, _ error) {
 	}
 }
 
+// SuffrageNodeKeyRotator has the pending key rotation of suffrage node; the
+// rotated publickey is used from the rotate height.
+type SuffrageNodeKeyRotator interface {
+	KeyRotation() (base.Publickey, base.Height)
+}
+
 type SuffrageNodeStateValue struct {
 	base.Node
 	hint.BaseHinter
-	start base.Height
+	rotatePublickey base.Publickey
+	start           base.Height
+	rotateHeight    base.Height
 }
 
 func NewSuffrageNodeStateValue(node base.Node, start base.Height) SuffrageNodeStateValue {
@@ -49,6 +57,31 @@ func (s SuffrageNodeStateValue) Start() base.Height {
 	return s.start
 }
 
+// KeyRotation returns the new publickey and it's effective height; if not
+// rotated, publickey is nil.
+func (s SuffrageNodeStateValue) KeyRotation() (base.Publickey, base.Height) {
+	return s.rotatePublickey, s.rotateHeight
+}
+
+// SuffrageNodePublickey returns the publickey of suffrage node, which is
+// effective at the given height.
+func SuffrageNodePublickey(node base.SuffrageNodeStateValue, height base.Height) base.Publickey {
+	if i, ok := node.(SuffrageNodeKeyRotator); ok {
+		if pub, rotateHeight := i.KeyRotation(); pub != nil && height >= rotateHeight {
+			return pub
+		}
+	}
+
+	return node.Publickey()
+}
+
+func (s SuffrageNodeStateValue) SetKeyRotation(pub base.Publickey, height base.Height) SuffrageNodeStateValue {
+	s.rotatePublickey = pub
+	s.rotateHeight = height
+
+	return s
+}
+
 func (s SuffrageNodeStateValue) Hint() hint.Hint {
 	return s.BaseHinter.Hint()
 }
@@ -64,11 +97,29 @@ func (s SuffrageNodeStateValue) IsValid([]byte) error {
 		return e.Wrap(err)
 	}
 
+	if s.rotatePublickey != nil {
+		if err := util.CheckIsValiders(nil, false, s.rotatePublickey, s.rotateHeight); err != nil {
+			return e.WithMessage(err, "key rotation")
+		}
+
+		switch {
+		case s.rotatePublickey.Equal(s.Publickey()):
+			return e.Errorf("same rotate publickey")
+		case s.rotateHeight <= s.start:
+			return e.Errorf("rotate height should be over start")
+		}
+	}
+
 	return nil
 }
 
 func (s SuffrageNodeStateValue) HashBytes() []byte {
-	return util.ConcatBytesSlice(s.Node.HashBytes(), s.start.Bytes())
+	if s.rotatePublickey == nil {
+		return util.ConcatBytesSlice(s.Node.HashBytes(), s.start.Bytes())
+	}
+
+	return util.ConcatBytesSlice(
+		s.Node.HashBytes(), s.start.Bytes(), s.rotatePublickey.Bytes(), s.rotateHeight.Bytes())
 }
 
 type SuffrageNodesStateValue struct {
@@ -137,7 +188,27 @@ func (s SuffrageNodesStateValue) Suffrage() (base.Suffrage, error) {
 		nodes[i] = s.nodes[i]
 	}
 
-	return NewSuffrage(nodes)
+	suf, err := NewSuffrage(nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range s.nodes {
+		j, ok := s.nodes[i].(SuffrageNodeKeyRotator)
+		if !ok {
+			continue
+		}
+
+		if pub, height := j.KeyRotation(); pub != nil {
+			if suf.rotations == nil {
+				suf.rotations = map[string]suffrageKeyRotation{}
+			}
+
+			suf.rotations[s.nodes[i].Address().String()] = suffrageKeyRotation{pub: pub, height: height}
+		}
+	}
+
+	return suf, nil
 }
 
 type SuffrageCandidateStateValue struct {
@@ -259,6 +330,11 @@ func GetSuffrageFromDatabase(
 			return nil, false, nil
 		default:
 			suf, err := proof.Suffrage()
+			if err != nil {
+				return nil, true, err
+			}
+
+			suf, err = KeyRotatedSuffrage(suf, height)
 
 			return suf, true, err
 		}
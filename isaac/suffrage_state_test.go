@@ -51,6 +51,54 @@ func TestSuffrageNodeStateValueJSON(tt *testing.T) {
 	suite.Run(tt, t)
 }
 
+func TestSuffrageNodeStateValueKeyRotationJSON(tt *testing.T) {
+	t := new(encoder.BaseTestEncode)
+
+	enc := jsonenc.NewEncoder()
+
+	t.Encode = func() (interface{}, []byte) {
+		t.NoError(enc.Add(encoder.DecodeDetail{Hint: base.StringAddressHint, Instance: base.StringAddress{}}))
+		t.NoError(enc.Add(encoder.DecodeDetail{Hint: base.MPublickeyHint, Instance: &base.MPublickey{}}))
+		t.NoError(enc.Add(encoder.DecodeDetail{Hint: base.DummyNodeHint, Instance: base.BaseNode{}}))
+		t.NoError(enc.Add(encoder.DecodeDetail{Hint: SuffrageNodeStateValueHint, Instance: SuffrageNodeStateValue{}}))
+
+		stv := NewSuffrageNodeStateValue(base.RandomLocalNode(), base.Height(33)).
+			SetKeyRotation(base.NewMPrivatekey().Publickey(), base.Height(44))
+		t.NoError(stv.IsValid(nil))
+
+		b, err := util.MarshalJSON(stv)
+		t.NoError(err)
+
+		t.T().Log("marshaled:", string(b))
+
+		return stv, b
+	}
+
+	t.Decode = func(b []byte) interface{} {
+		i, err := enc.Decode(b)
+		t.NoError(err)
+
+		u, ok := i.(SuffrageNodeStateValue)
+		t.True(ok)
+
+		return u
+	}
+	t.Compare = func(a, b interface{}) {
+		av := a.(SuffrageNodeStateValue)
+		bv := b.(SuffrageNodeStateValue)
+
+		t.True(av.Hint().Equal(bv.Hint()))
+		t.True(base.IsEqualStateValue(av, bv))
+
+		apub, aheight := av.KeyRotation()
+		bpub, bheight := bv.KeyRotation()
+		t.True(apub.Equal(bpub))
+		t.Equal(aheight, bheight)
+	}
+
+	suite.Run(tt, t)
+}
+
 func TestSuffrageNodesStateValueJSON(tt *testing.T) {
 	t := new(encoder.BaseTestEncode)
 
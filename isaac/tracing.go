@@ -0,0 +1,25 @@
+package isaac
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util/tracing"
+)
+
+// PointTraceID returns the tracing.TraceID of point; the nodes share the same
+// TraceID for the same height and round.
+func PointTraceID(point base.Point) tracing.TraceID {
+	return tracing.NewTraceIDFromBytes([]byte(fmt.Sprintf("%d/%d", point.Height(), point.Round())))
+}
+
+// StartPointSpan starts new span; without parent span, the TraceID of point
+// is used.
+func StartPointSpan(ctx context.Context, name string, point base.Point) (context.Context, *tracing.Span) {
+	return tracing.Start(ctx, name,
+		tracing.WithTraceID(PointTraceID(point)),
+		tracing.WithAttribute("height", point.Height()),
+		tracing.WithAttribute("round", point.Round()),
+	)
+}
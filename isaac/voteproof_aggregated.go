@@ -0,0 +1,449 @@
+package isaac
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/hint"
+	"github.com/ProtoconNet/mitum2/util/localtime"
+)
+
+var (
+	INITAggregatedVoteproofHint   = hint.MustNewHint("init-aggregated-voteproof-v0.0.1")
+	ACCEPTAggregatedVoteproofHint = hint.MustNewHint("accept-aggregated-voteproof-v0.0.1")
+)
+
+// aggregatedBallotSigns is the sign facts of same ballot fact, which are
+// signed by BLS keys; the signatures are aggregated into one signature. The
+// signs keep the node and publickey of each signer, so the aggregated signature
+// can be verified without suffrage.
+type aggregatedBallotSigns struct {
+	fact      base.BallotFact
+	signature base.Signature
+	signs     []aggregatedBallotSign
+}
+
+type aggregatedBallotSign struct {
+	signedAt time.Time
+	node     base.Address
+	signer   base.Publickey
+}
+
+func (a aggregatedBallotSigns) isValid(networkID []byte, point base.StagePoint) error {
+	switch {
+	case a.fact == nil:
+		return util.ErrInvalid.Errorf("empty fact")
+	case len(a.signs) < 1:
+		return util.ErrInvalid.Errorf("empty signs")
+	}
+
+	if err := util.CheckIsValiders(networkID, false, a.fact, a.signature); err != nil {
+		return err
+	}
+
+	if !a.fact.Point().Equal(point) {
+		return util.ErrInvalid.Errorf("point does not match, voteproof(%q) != fact(%q)", point, a.fact.Point())
+	}
+
+	pubs := make([]base.Publickey, len(a.signs))
+	msgs := make([][]byte, len(a.signs))
+
+	for i := range a.signs {
+		s := a.signs[i]
+
+		switch {
+		case s.signedAt.IsZero():
+			return util.ErrInvalid.Errorf("empty signed at")
+		case s.signer == nil:
+			return util.ErrInvalid.Errorf("empty signer")
+		}
+
+		if err := util.CheckIsValiders(nil, false, s.node, s.signer); err != nil {
+			return err
+		}
+
+		if _, ok := s.signer.(*base.BLSPublickey); !ok {
+			return util.ErrInvalid.Errorf("not bls publickey, %T", s.signer)
+		}
+
+		pubs[i] = s.signer
+		msgs[i] = util.ConcatBytesSlice(
+			networkID,
+			util.ConcatByters(s.node, a.fact.Hash()),
+			localtime.New(s.signedAt).Bytes(),
+		)
+	}
+
+	if err := base.VerifyBLSAggregatedSignature(pubs, msgs, a.signature); err != nil {
+		return util.ErrInvalid.WithMessage(err, "aggregated signature")
+	}
+
+	return nil
+}
+
+func (a aggregatedBallotSigns) hashBytes() []byte {
+	bs := make([]util.Byter, len(a.signs)*3+2)
+	bs[0] = a.fact.Hash()
+	bs[1] = a.signature
+
+	for i := range a.signs {
+		bs[2+i*3] = a.signs[i].node
+		bs[2+i*3+1] = a.signs[i].signer
+		bs[2+i*3+2] = localtime.New(a.signs[i].signedAt)
+	}
+
+	return util.ConcatByters(bs...)
+}
+
+// signFacts restores the sign facts from the aggregated signs; the signature
+// of sign is the aggregated signature, so it can not be verified by itself.
+func (a aggregatedBallotSigns) signFacts() []base.BallotSignFact {
+	sfs := make([]base.BallotSignFact, len(a.signs))
+
+	for i := range a.signs {
+		s := a.signs[i]
+
+		sfs[i] = newAggregatedBallotSignFact(
+			a.fact, base.NewBaseNodeSign(s.node, s.signer, a.signature, s.signedAt),
+		)
+	}
+
+	return sfs
+}
+
+type baseAggregatedVoteproof struct {
+	aggregated []aggregatedBallotSigns
+}
+
+func (vp baseAggregatedVoteproof) isValid(networkID []byte, bvp baseVoteproof) error {
+	switch {
+	case len(bvp.id) < 1:
+		return util.ErrInvalid.Errorf("empty id")
+	case !bvp.point.Stage().CanVote():
+		return util.ErrInvalid.Errorf("wrong stage, %q for Voteproof", bvp.point.Stage())
+	case bvp.Result() == base.VoteResultNotYet:
+		return util.ErrInvalid.Errorf("not yet finished")
+	case len(vp.aggregated) < 1:
+		return util.ErrInvalid.Errorf("empty aggregated signs")
+	}
+
+	if err := util.CheckIsValiders(networkID, false, bvp.point, bvp.Result(), bvp.threshold); err != nil {
+		return err
+	}
+
+	if util.IsDuplicatedSlice(vp.signFacts(bvp.sfs), func(sf base.BallotSignFact) (bool, string) {
+		if sf == nil || sf.Node() == nil {
+			return true, ""
+		}
+
+		return true, sf.Node().String()
+	}) {
+		return util.ErrInvalid.Errorf("duplicated node found in SignFacts of voteproof")
+	}
+
+	facts := make([]base.BallotFact, len(bvp.sfs)+len(vp.aggregated))
+
+	for i := range bvp.sfs {
+		sf := bvp.sfs[i]
+
+		if err := sf.IsValid(networkID); err != nil {
+			return util.ErrInvalid.WithMessage(err, "invalid sign facts")
+		}
+
+		fact, err := util.AssertInterfaceValue[base.BallotFact](sf.Fact())
+		if err != nil {
+			return util.ErrInvalid.Wrap(err)
+		}
+
+		if !fact.Point().Equal(bvp.point) {
+			return util.ErrInvalid.Errorf("point does not match, voteproof(%q) != fact(%q)", bvp.point, fact.Point())
+		}
+
+		facts[i] = fact
+	}
+
+	for i := range vp.aggregated {
+		if err := vp.aggregated[i].isValid(networkID, bvp.point); err != nil {
+			return util.ErrInvalid.WithMessage(err, "invalid aggregated signs")
+		}
+
+		facts[len(bvp.sfs)+i] = vp.aggregated[i].fact
+	}
+
+	if util.IsDuplicatedSlice(vp.aggregated, func(a aggregatedBallotSigns) (bool, string) {
+		return true, a.fact.Hash().String()
+	}) {
+		return util.ErrInvalid.Errorf("duplicated fact found in aggregated signs")
+	}
+
+	return isValidAggregatedVoteproofMajority(networkID, bvp, facts)
+}
+
+func isValidAggregatedVoteproofMajority(networkID []byte, bvp baseVoteproof, facts []base.BallotFact) error {
+	switch {
+	case bvp.Result() == base.VoteResultDraw:
+		if bvp.majority != nil {
+			return util.ErrInvalid.Errorf("not empty majority for draw")
+		}
+
+		return nil
+	case bvp.majority == nil:
+		return util.ErrInvalid.Errorf("empty majority for majority")
+	}
+
+	if err := bvp.majority.IsValid(networkID); err != nil {
+		return util.ErrInvalid.WithMessage(err, "invalid majority")
+	}
+
+	if !bvp.majority.Point().Equal(bvp.point) {
+		return util.ErrInvalid.Errorf("invalid majority; point does not match")
+	}
+
+	for i := range facts {
+		if facts[i].Hash().Equal(bvp.majority.Hash()) {
+			return nil
+		}
+	}
+
+	return util.ErrInvalid.Errorf("majoirty not found in sign facts")
+}
+
+func (vp baseAggregatedVoteproof) hashBytes() []byte {
+	bs := make([]util.Byter, len(vp.aggregated))
+
+	for i := range vp.aggregated {
+		a := vp.aggregated[i]
+		bs[i] = util.DummyByter(a.hashBytes)
+	}
+
+	return util.ConcatByters(bs...)
+}
+
+// signFacts returns the plain sign facts and the sign facts restored from the
+// aggregated signs.
+func (vp baseAggregatedVoteproof) signFacts(sfs []base.BallotSignFact) []base.BallotSignFact {
+	if len(vp.aggregated) < 1 {
+		return sfs
+	}
+
+	n := make([]base.BallotSignFact, len(sfs), len(sfs)+len(vp.aggregated))
+	copy(n, sfs)
+
+	for i := range vp.aggregated {
+		n = append(n, vp.aggregated[i].signFacts()...)
+	}
+
+	return n
+}
+
+// aggregate moves the sign facts signed by BLS keys of suffrage nodes into
+// aggregated signs.
+func (vp *baseAggregatedVoteproof) aggregate(bvp *baseVoteproof, suf base.Suffrage) error {
+	var plain []base.BallotSignFact
+
+	groups := map[string]*aggregatedBallotSigns{}
+	sigs := map[string][]base.Signature{}
+
+	var keys []string
+
+	for i := range bvp.sfs {
+		sf := bvp.sfs[i]
+
+		_, isbls := sf.Signer().(*base.BLSPublickey)
+		if !isbls || !suf.ExistsPublickey(sf.Node(), sf.Signer()) {
+			plain = append(plain, sf)
+
+			continue
+		}
+
+		key := sf.Fact().Hash().String()
+
+		g, found := groups[key]
+		if !found {
+			g = &aggregatedBallotSigns{
+				fact: sf.Fact().(base.BallotFact), //nolint:forcetypeassert //...
+			}
+			groups[key] = g
+			keys = append(keys, key)
+		}
+
+		g.signs = append(g.signs, aggregatedBallotSign{
+			node:     sf.Node(),
+			signer:   sf.Signer(),
+			signedAt: sf.NodeSigns()[0].SignedAt(),
+		})
+		sigs[key] = append(sigs[key], sf.NodeSigns()[0].Signature())
+	}
+
+	if len(keys) < 1 {
+		return util.ErrInvalid.Errorf("no BLS sign facts to aggregate")
+	}
+
+	sort.Strings(keys)
+
+	vp.aggregated = make([]aggregatedBallotSigns, len(keys))
+
+	for i := range keys {
+		g := groups[keys[i]]
+
+		sig, err := base.AggregateBLSSignatures(sigs[keys[i]])
+		if err != nil {
+			return err
+		}
+
+		g.signature = sig
+		vp.aggregated[i] = *g
+	}
+
+	bvp.sfs = plain
+
+	return nil
+}
+
+// newAggregatedBallotSignFact restores the sign fact from the aggregated
+// signs.
+func newAggregatedBallotSignFact(fact base.BallotFact, sign base.BaseNodeSign) base.BallotSignFact {
+	switch t := fact.(type) {
+	case base.INITBallotFact:
+		sf := NewINITBallotSignFact(t)
+		sf.sign = sign
+
+		return sf
+	default:
+		sf := NewACCEPTBallotSignFact(fact.(base.ACCEPTBallotFact)) //nolint:forcetypeassert //...
+		sf.sign = sign
+
+		return sf
+	}
+}
+
+// INITAggregatedVoteproof is the INITVoteproof, which has the aggregated BLS
+// signatures instead of each sign facts.
+type INITAggregatedVoteproof struct {
+	baseAggregatedVoteproof
+	INITVoteproof
+}
+
+func NewINITAggregatedVoteproof(point base.Point) INITAggregatedVoteproof {
+	vp := INITAggregatedVoteproof{
+		INITVoteproof: NewINITVoteproof(point),
+	}
+
+	vp.BaseHinter = vp.SetHint(INITAggregatedVoteproofHint).(hint.BaseHinter) //nolint:forcetypeassert //...
+
+	return vp
+}
+
+func (vp INITAggregatedVoteproof) IsValid(networkID []byte) error {
+	e := util.ErrInvalid.Errorf("invalid INITAggregatedVoteproof")
+
+	if err := vp.BaseHinter.IsValid(INITAggregatedVoteproofHint.Type().Bytes()); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := vp.baseAggregatedVoteproof.isValid(networkID, vp.baseVoteproof); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := base.IsValidINITVoteproof(vp, networkID); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
+
+func (vp INITAggregatedVoteproof) HashBytes() []byte {
+	return util.ConcatBytesSlice(vp.baseVoteproof.HashBytes(), vp.baseAggregatedVoteproof.hashBytes())
+}
+
+func (vp INITAggregatedVoteproof) SignFacts() []base.BallotSignFact {
+	return vp.baseAggregatedVoteproof.signFacts(vp.sfs)
+}
+
+func (vp INITAggregatedVoteproof) BallotSignFacts() []base.INITBallotSignFact {
+	sfs := vp.SignFacts()
+
+	vs := make([]base.INITBallotSignFact, len(sfs))
+
+	for i := range sfs {
+		vs[i] = sfs[i].(base.INITBallotSignFact) //nolint:forcetypeassert //...
+	}
+
+	return vs
+}
+
+func (vp *INITAggregatedVoteproof) Aggregate(suf base.Suffrage) error {
+	return vp.baseAggregatedVoteproof.aggregate(&vp.baseVoteproof, suf)
+}
+
+// ACCEPTAggregatedVoteproof is the ACCEPTVoteproof, which has the aggregated
+// BLS signatures instead of each sign facts.
+type ACCEPTAggregatedVoteproof struct {
+	baseAggregatedVoteproof
+	ACCEPTVoteproof
+}
+
+func NewACCEPTAggregatedVoteproof(point base.Point) ACCEPTAggregatedVoteproof {
+	vp := ACCEPTAggregatedVoteproof{
+		ACCEPTVoteproof: NewACCEPTVoteproof(point),
+	}
+
+	vp.BaseHinter = vp.SetHint(ACCEPTAggregatedVoteproofHint).(hint.BaseHinter) //nolint:forcetypeassert //...
+
+	return vp
+}
+
+func (vp ACCEPTAggregatedVoteproof) IsValid(networkID []byte) error {
+	e := util.ErrInvalid.Errorf("invalid ACCEPTAggregatedVoteproof")
+
+	if err := vp.BaseHinter.IsValid(ACCEPTAggregatedVoteproofHint.Type().Bytes()); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := vp.baseAggregatedVoteproof.isValid(networkID, vp.baseVoteproof); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := base.IsValidACCEPTVoteproof(vp, networkID); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
+
+func (vp ACCEPTAggregatedVoteproof) HashBytes() []byte {
+	return util.ConcatBytesSlice(vp.baseVoteproof.HashBytes(), vp.baseAggregatedVoteproof.hashBytes())
+}
+
+func (vp ACCEPTAggregatedVoteproof) SignFacts() []base.BallotSignFact {
+	return vp.baseAggregatedVoteproof.signFacts(vp.sfs)
+}
+
+func (vp ACCEPTAggregatedVoteproof) BallotSignFacts() []base.ACCEPTBallotSignFact {
+	sfs := vp.SignFacts()
+
+	vs := make([]base.ACCEPTBallotSignFact, len(sfs))
+
+	for i := range sfs {
+		vs[i] = sfs[i].(base.ACCEPTBallotSignFact) //nolint:forcetypeassert //...
+	}
+
+	return vs
+}
+
+func (vp *ACCEPTAggregatedVoteproof) Aggregate(suf base.Suffrage) error {
+	return vp.baseAggregatedVoteproof.aggregate(&vp.baseVoteproof, suf)
+}
+
+// HasBLSSignFacts checks whether the sign facts can be aggregated.
+func HasBLSSignFacts(sfs []base.BallotSignFact) bool {
+	for i := range sfs {
+		if _, ok := sfs[i].Signer().(*base.BLSPublickey); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,170 @@
+package isaac
+
+import (
+	"encoding/json"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/ProtoconNet/mitum2/util/localtime"
+)
+
+type aggregatedBallotSignJSONMarshaler struct {
+	SignedAt localtime.Time `json:"signed_at"`
+	Node     base.Address   `json:"node"`
+	Signer   base.Publickey `json:"signer"`
+}
+
+type aggregatedBallotSignJSONUnmarshaler struct {
+	SignedAt localtime.Time `json:"signed_at"`
+	Node     string         `json:"node"`
+	Signer   string         `json:"signer"`
+}
+
+type aggregatedBallotSignsJSONMarshaler struct {
+	Fact      base.BallotFact                     `json:"fact"`
+	Signature base.Signature                      `json:"signature"`
+	Signs     []aggregatedBallotSignJSONMarshaler `json:"signs"`
+}
+
+type aggregatedBallotSignsJSONUnmarshaler struct {
+	Fact      json.RawMessage                       `json:"fact"`
+	Signature base.Signature                        `json:"signature"`
+	Signs     []aggregatedBallotSignJSONUnmarshaler `json:"signs"`
+}
+
+func (a aggregatedBallotSigns) MarshalJSON() ([]byte, error) {
+	signs := make([]aggregatedBallotSignJSONMarshaler, len(a.signs))
+
+	for i := range a.signs {
+		signs[i] = aggregatedBallotSignJSONMarshaler{
+			SignedAt: localtime.New(a.signs[i].signedAt),
+			Node:     a.signs[i].node,
+			Signer:   a.signs[i].signer,
+		}
+	}
+
+	return util.MarshalJSON(aggregatedBallotSignsJSONMarshaler{
+		Fact:      a.fact,
+		Signature: a.signature,
+		Signs:     signs,
+	})
+}
+
+func (a *aggregatedBallotSigns) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	e := util.StringError("decode aggregated signs")
+
+	var u aggregatedBallotSignsJSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := encoder.Decode(enc, u.Fact, &a.fact); err != nil {
+		return e.WithMessage(err, "decode fact")
+	}
+
+	a.signature = u.Signature
+	a.signs = make([]aggregatedBallotSign, len(u.Signs))
+
+	for i := range u.Signs {
+		node, err := base.DecodeAddress(u.Signs[i].Node, enc)
+		if err != nil {
+			return e.WithMessage(err, "decode node")
+		}
+
+		signer, err := base.DecodePublickeyFromString(u.Signs[i].Signer, enc)
+		if err != nil {
+			return e.WithMessage(err, "decode signer")
+		}
+
+		a.signs[i] = aggregatedBallotSign{
+			signedAt: u.Signs[i].SignedAt.Time,
+			node:     node,
+			signer:   signer,
+		}
+	}
+
+	return nil
+}
+
+type aggregatedVoteproofJSONMarshaler struct {
+	Aggregated []aggregatedBallotSigns `json:"aggregated"`
+	baseVoteproofJSONMarshaler
+}
+
+type aggregatedVoteproofJSONUnmarshaler struct {
+	Aggregated []json.RawMessage `json:"aggregated"`
+}
+
+func (vp INITAggregatedVoteproof) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(aggregatedVoteproofJSONMarshaler{
+		baseVoteproofJSONMarshaler: vp.jsonMarshaller(),
+		Aggregated:                 vp.aggregated,
+	})
+}
+
+func (vp ACCEPTAggregatedVoteproof) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(aggregatedVoteproofJSONMarshaler{
+		baseVoteproofJSONMarshaler: vp.jsonMarshaller(),
+		Aggregated:                 vp.aggregated,
+	})
+}
+
+func (vp *baseAggregatedVoteproof) decodeJSON(
+	b []byte, enc encoder.Encoder, bu baseVoteproofJSONUnmarshaler, bvp *baseVoteproof,
+) error {
+	var u aggregatedVoteproofJSONUnmarshaler
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return err
+	}
+
+	majority := bu.Majority.Hash()
+
+	vp.aggregated = make([]aggregatedBallotSigns, len(u.Aggregated))
+
+	for i := range u.Aggregated {
+		if err := vp.aggregated[i].DecodeJSON(u.Aggregated[i], enc); err != nil {
+			return err
+		}
+
+		if majority == nil || bvp.majority != nil || vp.aggregated[i].fact == nil {
+			continue
+		}
+
+		if vp.aggregated[i].fact.Hash().Equal(majority) { // NOTE find in aggregated signs
+			bvp.majority = vp.aggregated[i].fact
+		}
+	}
+
+	return nil
+}
+
+func (vp *INITAggregatedVoteproof) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	e := util.StringError("decode INITAggregatedVoteproof")
+
+	u, err := vp.baseVoteproof.decodeJSON(b, enc)
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := vp.baseAggregatedVoteproof.decodeJSON(b, enc, u, &vp.baseVoteproof); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
+
+func (vp *ACCEPTAggregatedVoteproof) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	e := util.StringError("decode ACCEPTAggregatedVoteproof")
+
+	u, err := vp.baseVoteproof.decodeJSON(b, enc)
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := vp.baseAggregatedVoteproof.decodeJSON(b, enc, u, &vp.baseVoteproof); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,242 @@
+package isaac
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	jsonenc "github.com/ProtoconNet/mitum2/util/encoder/json"
+	"github.com/ProtoconNet/mitum2/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testAggregatedVoteproof struct {
+	suite.Suite
+	networkID base.NetworkID
+	enc       *jsonenc.Encoder
+}
+
+func (t *testAggregatedVoteproof) SetupTest() {
+	t.networkID = base.NetworkID(util.UUID().Bytes())
+
+	t.enc = jsonenc.NewEncoder()
+
+	t.NoError(t.enc.Add(encoder.DecodeDetail{Hint: base.StringAddressHint, Instance: base.StringAddress{}}))
+	t.NoError(t.enc.Add(encoder.DecodeDetail{Hint: base.MPublickeyHint, Instance: &base.MPublickey{}}))
+	t.NoError(t.enc.Add(encoder.DecodeDetail{Hint: base.BLSPublickeyHint, Instance: &base.BLSPublickey{}}))
+	t.NoError(t.enc.Add(encoder.DecodeDetail{Hint: INITBallotFactHint, Instance: INITBallotFact{}}))
+	t.NoError(t.enc.Add(encoder.DecodeDetail{Hint: ACCEPTBallotFactHint, Instance: ACCEPTBallotFact{}}))
+	t.NoError(t.enc.Add(encoder.DecodeDetail{Hint: INITBallotSignFactHint, Instance: INITBallotSignFact{}}))
+	t.NoError(t.enc.Add(encoder.DecodeDetail{Hint: ACCEPTBallotSignFactHint, Instance: ACCEPTBallotSignFact{}}))
+	t.NoError(t.enc.Add(encoder.DecodeDetail{Hint: INITAggregatedVoteproofHint, Instance: INITAggregatedVoteproof{}}))
+	t.NoError(t.enc.Add(encoder.DecodeDetail{Hint: ACCEPTAggregatedVoteproofHint, Instance: ACCEPTAggregatedVoteproof{}}))
+}
+
+func (t *testAggregatedVoteproof) suffrage(n, bls int) (base.Suffrage, []base.LocalNode) {
+	extras := make([]base.LocalNode, bls)
+
+	for i := range extras {
+		extras[i] = NewLocalNode(base.NewBLSPrivatekey(), base.NewStringAddress(fmt.Sprintf("bls%02d", i)))
+	}
+
+	return NewTestSuffrage(n, extras...)
+}
+
+func (t *testAggregatedVoteproof) signFacts(fact base.BallotFact, nodes []base.LocalNode) []base.BallotSignFact {
+	sfs := make([]base.BallotSignFact, len(nodes))
+
+	for i := range nodes {
+		switch ft := fact.(type) {
+		case INITBallotFact:
+			sf := NewINITBallotSignFact(ft)
+			t.NoError(sf.NodeSign(nodes[i].Privatekey(), t.networkID, nodes[i].Address()))
+
+			sfs[i] = sf
+		case ACCEPTBallotFact:
+			sf := NewACCEPTBallotSignFact(ft)
+			t.NoError(sf.NodeSign(nodes[i].Privatekey(), t.networkID, nodes[i].Address()))
+
+			sfs[i] = sf
+		}
+	}
+
+	return sfs
+}
+
+func (t *testAggregatedVoteproof) initVoteproof(suf base.Suffrage, nodes []base.LocalNode) INITAggregatedVoteproof {
+	fact := NewINITBallotFact(base.RawPoint(33, 55), valuehash.RandomSHA256(), valuehash.RandomSHA256(), nil)
+
+	ivp := NewINITAggregatedVoteproof(fact.Point().Point)
+	ivp.
+		SetMajority(fact).
+		SetSignFacts(t.signFacts(fact, nodes)).
+		SetThreshold(base.Threshold(100)).
+		Finish()
+
+	t.NoError(ivp.Aggregate(suf))
+
+	return ivp
+}
+
+func (t *testAggregatedVoteproof) decode(vp base.Voteproof) base.Voteproof {
+	b, err := t.enc.Marshal(vp)
+	t.NoError(err)
+
+	hinter, err := t.enc.Decode(b)
+	t.NoError(err)
+
+	uvp, ok := hinter.(base.Voteproof)
+	t.True(ok)
+
+	return uvp
+}
+
+func (t *testAggregatedVoteproof) TestAggregate() {
+	suf, nodes := t.suffrage(1, 3)
+
+	ivp := t.initVoteproof(suf, nodes)
+
+	t.Equal(1, len(ivp.sfs))
+	t.Equal(1, len(ivp.aggregated))
+	t.Equal(3, len(ivp.aggregated[0].signs))
+	t.Equal(4, len(ivp.SignFacts()))
+
+	t.NoError(ivp.IsValid(t.networkID))
+	t.NoError(IsValidVoteproofWithSuffrage(ivp, suf))
+
+	t.Run("without bls", func() {
+		suf, nodes := t.suffrage(3, 0)
+
+		fact := NewINITBallotFact(base.RawPoint(33, 55), valuehash.RandomSHA256(), valuehash.RandomSHA256(), nil)
+
+		ivp := NewINITAggregatedVoteproof(fact.Point().Point)
+		ivp.
+			SetMajority(fact).
+			SetSignFacts(t.signFacts(fact, nodes)).
+			SetThreshold(base.Threshold(100)).
+			Finish()
+
+		err := ivp.Aggregate(suf)
+		t.Error(err)
+		t.ErrorContains(err, "no BLS sign facts")
+	})
+}
+
+func (t *testAggregatedVoteproof) TestDecode() {
+	suf, nodes := t.suffrage(1, 3)
+
+	ivp := t.initVoteproof(suf, nodes)
+
+	uvp := t.decode(ivp)
+
+	uivp, ok := uvp.(INITAggregatedVoteproof)
+	t.True(ok)
+
+	t.Equal(ivp.HashBytes(), uivp.HashBytes())
+	base.EqualBallotFact(t.Assert(), ivp.Majority(), uivp.Majority())
+	t.Equal(4, len(uivp.SignFacts()))
+
+	t.NoError(uivp.IsValid(t.networkID))
+	t.NoError(IsValidVoteproofWithSuffrage(uivp, suf))
+
+	bs := uivp.BallotSignFacts()
+	t.Equal(4, len(bs))
+
+	for i := range bs {
+		t.True(suf.ExistsPublickey(bs[i].Node(), bs[i].Signer()))
+	}
+}
+
+func (t *testAggregatedVoteproof) TestMajorityInAggregated() {
+	suf, nodes := t.suffrage(0, 3)
+
+	fact := NewACCEPTBallotFact(base.RawPoint(33, 55), valuehash.RandomSHA256(), valuehash.RandomSHA256(), nil)
+
+	avp := NewACCEPTAggregatedVoteproof(fact.Point().Point)
+	avp.
+		SetMajority(fact).
+		SetSignFacts(t.signFacts(fact, nodes)).
+		SetThreshold(base.Threshold(100)).
+		Finish()
+
+	t.NoError(avp.Aggregate(suf))
+	t.Empty(avp.sfs)
+
+	uvp := t.decode(avp)
+	t.NotNil(uvp.Majority())
+	t.True(uvp.Majority().Hash().Equal(fact.Hash()))
+
+	t.NoError(uvp.IsValid(t.networkID))
+	t.NoError(IsValidVoteproofWithSuffrage(uvp, suf))
+}
+
+func (t *testAggregatedVoteproof) TestWrongSignature() {
+	suf, nodes := t.suffrage(1, 3)
+
+	t.Run("different network id", func() {
+		suf, nodes := t.suffrage(0, 3)
+
+		uvp := t.decode(t.initVoteproof(suf, nodes))
+
+		err := uvp.IsValid(base.NetworkID(util.UUID().Bytes()))
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "aggregated signature")
+	})
+
+	t.Run("wrong signed at", func() {
+		uvp := t.decode(t.initVoteproof(suf, nodes)).(INITAggregatedVoteproof) //nolint:forcetypeassert //...
+		uvp.aggregated[0].signs[0].signedAt = uvp.aggregated[0].signs[0].signedAt.Add(time.Second)
+
+		err := uvp.IsValid(t.networkID)
+		t.Error(err)
+		t.ErrorContains(err, "aggregated signature")
+	})
+
+	t.Run("forged signature without plain sign facts", func() {
+		suf, nodes := t.suffrage(0, 3)
+
+		uvp := t.decode(t.initVoteproof(suf, nodes)).(INITAggregatedVoteproof) //nolint:forcetypeassert //...
+		t.Empty(uvp.sfs)
+
+		sig, err := nodes[0].Privatekey().Sign(util.UUID().Bytes())
+		t.NoError(err)
+
+		uvp.aggregated[0].signature = sig
+
+		err = uvp.IsValid(t.networkID)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "aggregated signature")
+	})
+
+	t.Run("missing signer", func() {
+		uvp := t.decode(t.initVoteproof(suf, nodes)).(INITAggregatedVoteproof) //nolint:forcetypeassert //...
+		uvp.aggregated[0].signs = uvp.aggregated[0].signs[1:]
+
+		err := uvp.IsValid(t.networkID)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "aggregated signature")
+	})
+
+	t.Run("different suffrage", func() {
+		nsuf, _ := t.suffrage(1, 3)
+
+		uvp := t.decode(t.initVoteproof(suf, nodes))
+
+		t.NoError(uvp.IsValid(t.networkID))
+		t.NoError(IsValidVoteproofWithSuffrage(uvp, suf))
+
+		err := IsValidVoteproofWithSuffrage(uvp, nsuf)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+	})
+}
+
+func TestAggregatedVoteproof(t *testing.T) {
+	suite.Run(t, new(testAggregatedVoteproof))
+}
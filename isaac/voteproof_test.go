@@ -252,6 +252,61 @@ func (t *testVoteproof) TestWrongMajorityWithSuffrage() {
 	t.ErrorContains(err, "wrong majority")
 }
 
+func (t *testVoteproof) TestMixedKeyTypes() {
+	suf, nodes := NewTestSuffrage(2,
+		NewLocalNode(base.NewEd25519Privatekey(), base.NewStringAddress("ed00")),
+		NewLocalNode(base.NewEd25519Privatekey(), base.NewStringAddress("ed01")),
+	)
+
+	fact := NewINITBallotFact(base.RawPoint(33, 55), valuehash.RandomSHA256(), valuehash.RandomSHA256(), nil)
+
+	sfs := make([]base.BallotSignFact, len(nodes))
+
+	for i := range nodes {
+		signfact := NewINITBallotSignFact(fact)
+		t.NoError(signfact.NodeSign(nodes[i].Privatekey(), t.networkID, nodes[i].Address()))
+
+		sfs[i] = signfact
+	}
+
+	ivp := NewINITVoteproof(fact.Point().Point)
+	ivp.
+		SetMajority(fact).
+		SetSignFacts(sfs).
+		SetThreshold(base.Threshold(100)).
+		Finish()
+
+	t.NoError(ivp.IsValid(t.networkID))
+	t.NoError(base.IsValidVoteproofWithSuffrage(ivp, suf, ivp.Threshold()))
+
+	t.Run("wrong ed25519 signature", func() {
+		signfact := NewINITBallotSignFact(fact)
+		t.NoError(signfact.NodeSign(base.NewEd25519Privatekey(), t.networkID, nodes[3].Address()))
+
+		nsfs := make([]base.BallotSignFact, len(sfs))
+		copy(nsfs, sfs)
+
+		for i := range nsfs {
+			if nsfs[i].Node().Equal(nodes[3].Address()) {
+				nsfs[i] = signfact
+			}
+		}
+
+		nivp := NewINITVoteproof(fact.Point().Point)
+		nivp.
+			SetMajority(fact).
+			SetSignFacts(nsfs).
+			SetThreshold(base.Threshold(100)).
+			Finish()
+
+		t.NoError(nivp.IsValid(t.networkID))
+
+		err := base.IsValidVoteproofWithSuffrage(nivp, suf, nivp.Threshold())
+		t.Error(err)
+		t.ErrorContains(err, "publickey")
+	})
+}
+
 func (t *testVoteproof) TestUnknownNode() {
 	ivp := t.validINITVoteproof(base.RawPoint(33, 55))
 
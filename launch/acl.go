@@ -52,6 +52,9 @@ var AllACLScopes = []ACLScope{
 	ACLACLScope,
 	HandoverACLScope,
 	EventLoggingACLScope,
+	NetworkStatsACLScope,
+	ProfileACLScope,
+	StateProofACLScope,
 }
 
 var ErrACLAccessDenied = util.NewIDError("access denied")
@@ -0,0 +1,559 @@
+package launch
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	isaacstates "github.com/ProtoconNet/mitum2/isaac/states"
+	"github.com/ProtoconNet/mitum2/network/quicmemberlist"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/localtime"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/ProtoconNet/mitum2/util/ps"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+var (
+	PNameAlerts      = ps.Name("alerts")
+	PNameStartAlerts = ps.Name("start-alerts")
+	AlertsContextKey = util.ContextKey("alerts")
+)
+
+var AlertEventLogger EventLoggerName = "alert"
+
+var DefaultAlertsInterval = time.Second * 10
+
+type AlertRule string
+
+const (
+	AlertRuleConsensusLeft         AlertRule = "consensus_left"
+	AlertRuleSuffrageExpel         AlertRule = "suffrage_expel"
+	AlertRuleBallotStuck           AlertRule = "ballot_stuck"
+	AlertRuleTimeDrift             AlertRule = "time_drift"
+	AlertRuleDiskUsage             AlertRule = "disk_usage"
+	AlertRuleMembersBelowThreshold AlertRule = "members_below_threshold"
+)
+
+// AlertsDesign sets the alert rules; the zero value disables the rule.
+type AlertsDesign struct {
+	// Webhook receives the alerts in json.
+	Webhook string `json:"webhook,omitempty" yaml:"webhook,omitempty"`
+	// Interval is the interval to evaluate the rules.
+	Interval util.ReadableDuration `json:"interval,omitempty" yaml:"interval,omitempty"`
+	// ConsensusLeft fires when local suffrage node leaves consensus state
+	// over the duration.
+	ConsensusLeft util.ReadableDuration `json:"consensus_left,omitempty" yaml:"consensus_left,omitempty"`
+	// TimeDrift fires when the offset of time server is over the duration;
+	// without time server, it fires.
+	TimeDrift util.ReadableDuration `json:"time_drift,omitempty" yaml:"time_drift,omitempty"`
+	// DiskUsage fires when the disk usage percent under storage base is over.
+	DiskUsage float64 `json:"disk_usage,omitempty" yaml:"disk_usage,omitempty"`
+	// SuffrageExpel fires when the suffrage expel operation targets local.
+	SuffrageExpel bool `json:"suffrage_expel,omitempty" yaml:"suffrage_expel,omitempty"`
+	// BallotStuck fires when the ballot stuck resolver requests the missing
+	// ballots.
+	BallotStuck bool `json:"ballot_stuck,omitempty" yaml:"ballot_stuck,omitempty"`
+	// MembersBelowThreshold fires when the alive suffrage members in
+	// memberlist are under threshold.
+	MembersBelowThreshold bool `json:"members_below_threshold,omitempty" yaml:"members_below_threshold,omitempty"`
+}
+
+func (d *AlertsDesign) IsValid([]byte) error {
+	e := util.ErrInvalid.Errorf("invalid AlertsDesign")
+
+	if len(d.Webhook) > 0 {
+		switch u, err := url.Parse(d.Webhook); {
+		case err != nil:
+			return e.WithMessage(err, "webhook")
+		case u.Scheme != "http" && u.Scheme != "https", len(u.Host) < 1:
+			return e.Errorf("invalid webhook, %q", d.Webhook)
+		}
+	}
+
+	switch {
+	case d.Interval < 0:
+		return e.Errorf("wrong interval")
+	case d.ConsensusLeft < 0:
+		return e.Errorf("wrong consensus_left")
+	case d.TimeDrift < 0:
+		return e.Errorf("wrong time_drift")
+	case d.DiskUsage < 0 || d.DiskUsage > 100:
+		return e.Errorf("wrong disk_usage, %v", d.DiskUsage)
+	}
+
+	if d.Interval < 1 {
+		d.Interval = util.ReadableDuration(DefaultAlertsInterval)
+	}
+
+	return nil
+}
+
+// Rules returns the enabled rules.
+func (d AlertsDesign) Rules() []AlertRule {
+	var rules []AlertRule
+
+	for rule, enabled := range map[AlertRule]bool{
+		AlertRuleConsensusLeft:         d.ConsensusLeft > 0,
+		AlertRuleSuffrageExpel:         d.SuffrageExpel,
+		AlertRuleBallotStuck:           d.BallotStuck,
+		AlertRuleTimeDrift:             d.TimeDrift > 0,
+		AlertRuleDiskUsage:             d.DiskUsage > 0,
+		AlertRuleMembersBelowThreshold: d.MembersBelowThreshold,
+	} {
+		if enabled {
+			rules = append(rules, rule)
+		}
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i] < rules[j]
+	})
+
+	return rules
+}
+
+type Alert struct {
+	At       time.Time `json:"at"`
+	Rule     AlertRule `json:"rule"`
+	Node     string    `json:"node"`
+	Message  string    `json:"message,omitempty"`
+	Resolved bool      `json:"resolved,omitempty"`
+}
+
+// AlertCondition checks the condition of rule; if firing, it returns true with
+// the message.
+type AlertCondition func() (firing bool, message string, _ error)
+
+// Alerts evaluates the conditions periodically and publishes the alerts thru
+// the alert event logger and the webhook. The condition alert is published
+// when it starts firing and when it is resolved; the signal alerts, which come
+// from events like suffrage expel, are collected and published at once in the
+// next evaluation.
+type Alerts struct {
+	*logging.Logging
+	*util.ContextDaemon
+	el         *zerolog.Logger
+	webhook    EventSink
+	rules      map[AlertRule]struct{}
+	conditions map[AlertRule]AlertCondition
+	firing     map[AlertRule]bool
+	signals    map[AlertRule][]string
+	expels     *util.BaseGCache[string, struct{}]
+	local      base.Address
+	state      isaacstates.StateType
+	stateAt    time.Time
+	interval   time.Duration
+	sync.Mutex
+}
+
+func NewAlerts(
+	local base.Address,
+	rules []AlertRule,
+	interval time.Duration,
+	el *zerolog.Logger,
+	webhook EventSink,
+) *Alerts {
+	if interval < 1 {
+		interval = DefaultAlertsInterval
+	}
+
+	a := &Alerts{
+		Logging: logging.NewLogging(func(zctx zerolog.Context) zerolog.Context {
+			return zctx.Str("module", "alerts")
+		}),
+		local:      local,
+		rules:      map[AlertRule]struct{}{},
+		conditions: map[AlertRule]AlertCondition{},
+		firing:     map[AlertRule]bool{},
+		signals:    map[AlertRule][]string{},
+		expels:     util.NewLRUGCache[string, struct{}](1 << 9), //nolint:gomnd //...
+		interval:   interval,
+		el:         el,
+		webhook:    webhook,
+		state:      isaacstates.StateEmpty,
+		stateAt:    localtime.Now().UTC(),
+	}
+
+	for i := range rules {
+		a.rules[rules[i]] = struct{}{}
+	}
+
+	a.ContextDaemon = util.NewContextDaemon(a.start)
+
+	return a
+}
+
+func (a *Alerts) IsEnabled(rule AlertRule) bool {
+	_, found := a.rules[rule]
+
+	return found
+}
+
+// SetCondition sets the condition of rule; if rule is not enabled, it is
+// ignored.
+func (a *Alerts) SetCondition(rule AlertRule, f AlertCondition) {
+	if !a.IsEnabled(rule) {
+		return
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	a.conditions[rule] = f
+}
+
+// Signal collects the event of rule; if rule is not enabled, it is ignored.
+func (a *Alerts) Signal(rule AlertRule, message string) {
+	if !a.IsEnabled(rule) {
+		return
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	a.signals[rule] = append(a.signals[rule], message)
+}
+
+func (a *Alerts) StateSwitched(next isaacstates.StateType) {
+	a.Lock()
+	defer a.Unlock()
+
+	a.state = next
+	a.stateAt = localtime.Now().UTC()
+}
+
+// SuffrageExpel signals when the expel operation targets local node; the same
+// expel fact is signaled only once.
+func (a *Alerts) SuffrageExpel(op base.SuffrageExpelOperation) {
+	fact := op.ExpelFact()
+
+	if !a.IsEnabled(AlertRuleSuffrageExpel) || !fact.Node().Equal(a.local) {
+		return
+	}
+
+	if a.expels.Exists(fact.Hash().String()) {
+		return
+	}
+
+	a.expels.Set(fact.Hash().String(), struct{}{}, 0)
+
+	a.Signal(AlertRuleSuffrageExpel, fmt.Sprintf("expel operation, %q; start=%d end=%d reason=%q",
+		fact.Hash(), fact.ExpelStart(), fact.ExpelEnd(), fact.Reason()))
+}
+
+// ConsensusLeftCondition fires when the state is not CONSENSUS over d. Before
+// states started or when local is not in the last suffrage, it does not fire.
+func (a *Alerts) ConsensusLeftCondition(
+	d time.Duration,
+	lastSuffragef func() (base.Suffrage, bool, error),
+) AlertCondition {
+	return func() (bool, string, error) {
+		a.Lock()
+		state, at := a.state, a.stateAt
+		a.Unlock()
+
+		switch state {
+		case isaacstates.StateEmpty, isaacstates.StateConsensus:
+			return false, "", nil
+		}
+
+		switch suf, found, err := lastSuffragef(); {
+		case err != nil:
+			return false, "", err
+		case !found, !suf.Exists(a.local):
+			return false, "not in suffrage", nil
+		}
+
+		elapsed := localtime.Now().UTC().Sub(at)
+
+		return elapsed > d, fmt.Sprintf("%s over %s", state, elapsed.Truncate(time.Second)), nil
+	}
+}
+
+// Evaluate checks the conditions and publishes the alerts.
+func (a *Alerts) Evaluate() {
+	a.Lock()
+	conditions := make(map[AlertRule]AlertCondition, len(a.conditions))
+
+	for rule := range a.conditions {
+		conditions[rule] = a.conditions[rule]
+	}
+
+	signals := a.signals
+	a.signals = map[AlertRule][]string{}
+	a.Unlock()
+
+	now := localtime.Now().UTC()
+
+	for _, rule := range sortedAlertRules(conditions) {
+		firing, message, err := conditions[rule]()
+		if err != nil {
+			a.Log().Error().Err(err).Interface("rule", rule).Msg("failed to check alert condition")
+
+			continue
+		}
+
+		a.Lock()
+		prev := a.firing[rule]
+		a.firing[rule] = firing
+		a.Unlock()
+
+		switch {
+		case firing && !prev:
+			a.publish(Alert{At: now, Rule: rule, Message: message})
+		case !firing && prev:
+			a.publish(Alert{At: now, Rule: rule, Message: message, Resolved: true})
+		}
+	}
+
+	for _, rule := range sortedAlertRules(signals) {
+		a.publish(Alert{At: now, Rule: rule, Message: strings.Join(signals[rule], "; ")})
+	}
+}
+
+func (a *Alerts) publish(alert Alert) {
+	alert.Node = a.local.String()
+
+	if a.el != nil {
+		l := a.el.Warn()
+		if alert.Resolved {
+			l = a.el.Info()
+		}
+
+		l.Interface("alert", alert).Msg("alert")
+	}
+
+	if a.webhook == nil {
+		return
+	}
+
+	b, err := util.MarshalJSON(alert)
+	if err != nil {
+		a.Log().Error().Err(err).Msg("failed to marshal alert")
+
+		return
+	}
+
+	if err := a.webhook.Write(AlertEventLogger, b); err != nil {
+		a.Log().Error().Err(err).Interface("alert", alert).Msg("failed to send alert to webhook")
+	}
+}
+
+func (a *Alerts) start(ctx context.Context) error {
+	defer func() {
+		if a.webhook != nil {
+			_ = a.webhook.Close()
+		}
+	}()
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			a.Evaluate()
+		}
+	}
+}
+
+func sortedAlertRules[T any](m map[AlertRule]T) []AlertRule {
+	rules := make([]AlertRule, 0, len(m))
+
+	for rule := range m {
+		rules = append(rules, rule)
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i] < rules[j]
+	})
+
+	return rules
+}
+
+// TimeDriftAlertCondition fires when the time offset is over d; without
+// TimeSyncer, the time drift can not be checked, so it fires.
+func TimeDriftAlertCondition(ts *localtime.TimeSyncer, d time.Duration) AlertCondition {
+	return func() (bool, string, error) {
+		if ts == nil {
+			return true, "time server not configured; time drift can not be checked", nil
+		}
+
+		offset := ts.Offset()
+		if offset < 0 {
+			offset *= -1
+		}
+
+		return offset > d, fmt.Sprintf("time offset, %s", ts.Offset()), nil
+	}
+}
+
+func DiskUsageAlertCondition(path string, percent float64) AlertCondition {
+	return func() (bool, string, error) {
+		used, total, err := diskUsage(path)
+
+		switch {
+		case err != nil:
+			return false, "", err
+		case total < 1:
+			return false, "", nil
+		}
+
+		p := float64(used) / float64(total) * 100 //nolint:gomnd //...
+
+		return p > percent, fmt.Sprintf("disk usage of %q, %.2f%%", path, p), nil
+	}
+}
+
+// MembersBelowThresholdAlertCondition fires when the number of suffrage nodes
+// in memberlist is under the threshold of last suffrage.
+func MembersBelowThresholdAlertCondition(
+	m *quicmemberlist.Memberlist,
+	lastSuffragef func() (base.Suffrage, bool, error),
+	threshold base.Threshold,
+) AlertCondition {
+	return func() (bool, string, error) {
+		var suf base.Suffrage
+
+		switch i, found, err := lastSuffragef(); {
+		case err != nil:
+			return false, "", err
+		case !found:
+			return false, "", nil
+		default:
+			suf = i
+		}
+
+		alive := map[string]struct{}{}
+
+		m.Members(func(member quicmemberlist.Member) bool {
+			if suf.Exists(member.Address()) {
+				alive[member.Address().String()] = struct{}{}
+			}
+
+			return true
+		})
+
+		t := threshold.Threshold(uint(suf.Len()))
+
+		return uint(len(alive)) < t,
+			fmt.Sprintf("alive suffrage members, %d; threshold=%d suffrage=%d", len(alive), t, suf.Len()), nil
+	}
+}
+
+func PAlerts(pctx context.Context) (context.Context, error) {
+	e := util.StringError("alerts")
+
+	var log *logging.Logging
+	var design NodeDesign
+	var local base.LocalNode
+	var eventLogging *EventLogging
+
+	if err := util.LoadFromContextOK(pctx,
+		LoggingContextKey, &log,
+		DesignContextKey, &design,
+		LocalContextKey, &local,
+		EventLoggingContextKey, &eventLogging,
+	); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	rules := design.Alerts.Rules()
+	if len(rules) < 1 {
+		return pctx, nil
+	}
+
+	var el *zerolog.Logger
+
+	if i, found := eventLogging.Logger(AlertEventLogger); found {
+		el = &i
+	}
+
+	var webhook EventSink
+
+	if len(design.Alerts.Webhook) > 0 {
+		u, err := url.Parse(design.Alerts.Webhook)
+		if err != nil {
+			return pctx, e.Wrap(err)
+		}
+
+		webhook = NewWebhookEventSink(u, nil, DefaultWebhookEventSinkRetry)
+	}
+
+	a := NewAlerts(local.Address(), rules, time.Duration(design.Alerts.Interval), el, webhook)
+	_ = a.SetLogging(log)
+
+	return context.WithValue(pctx, AlertsContextKey, a), nil
+}
+
+func PStartAlerts(pctx context.Context) (context.Context, error) {
+	e := util.StringError("start alerts")
+
+	var a *Alerts
+
+	switch err := util.LoadFromContext(pctx, AlertsContextKey, &a); {
+	case err != nil:
+		return pctx, e.Wrap(err)
+	case a == nil:
+		return pctx, nil
+	}
+
+	var design NodeDesign
+	var isaacparams *isaac.Params
+	var m *quicmemberlist.Memberlist
+	var sp *SuffragePool
+	var ts *localtime.TimeSyncer
+
+	if err := util.LoadFromContextOK(pctx,
+		DesignContextKey, &design,
+		ISAACParamsContextKey, &isaacparams,
+		MemberlistContextKey, &m,
+		SuffragePoolContextKey, &sp,
+	); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	if err := util.LoadFromContext(pctx, TimeSyncerContextKey, &ts); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	a.SetCondition(AlertRuleConsensusLeft,
+		a.ConsensusLeftCondition(time.Duration(design.Alerts.ConsensusLeft), sp.Last))
+	a.SetCondition(AlertRuleDiskUsage, DiskUsageAlertCondition(design.Storage.Base, design.Alerts.DiskUsage))
+	a.SetCondition(AlertRuleMembersBelowThreshold,
+		MembersBelowThresholdAlertCondition(m, sp.Last, isaacparams.Threshold()))
+	a.SetCondition(AlertRuleTimeDrift, TimeDriftAlertCondition(ts, time.Duration(design.Alerts.TimeDrift)))
+
+	if err := a.Start(context.Background()); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	return pctx, nil
+}
+
+func PCloseAlerts(pctx context.Context) (context.Context, error) {
+	var a *Alerts
+
+	switch err := util.LoadFromContext(pctx, AlertsContextKey, &a); {
+	case err != nil:
+		return pctx, err
+	case a == nil:
+		return pctx, nil
+	}
+
+	if err := a.Stop(); err != nil && !errors.Is(err, util.ErrDaemonAlreadyStopped) {
+		return pctx, err
+	}
+
+	return pctx, nil
+}
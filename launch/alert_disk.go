@@ -0,0 +1,22 @@
+//go:build !windows
+// +build !windows
+
+package launch
+
+import (
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+func diskUsage(path string) (used, total uint64, _ error) {
+	var st syscall.Statfs_t
+
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, 0, errors.WithStack(err)
+	}
+
+	total = st.Blocks * uint64(st.Bsize) //nolint:gosec //...
+
+	return total - st.Bfree*uint64(st.Bsize), total, nil //nolint:gosec //...
+}
@@ -0,0 +1,10 @@
+//go:build windows
+// +build windows
+
+package launch
+
+import "github.com/pkg/errors"
+
+func diskUsage(string) (used, total uint64, _ error) {
+	return 0, 0, errors.Errorf("disk usage not supported")
+}
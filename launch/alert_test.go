@@ -0,0 +1,218 @@
+package launch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	isaacstates "github.com/ProtoconNet/mitum2/isaac/states"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/stretchr/testify/suite"
+)
+
+type testAlerts struct {
+	suite.Suite
+	local base.Address
+}
+
+func (t *testAlerts) SetupSuite() {
+	t.local = base.RandomAddress("")
+}
+
+func (t *testAlerts) alerts(sink *dummyEventSink, rules ...AlertRule) *Alerts {
+	return NewAlerts(t.local, rules, time.Second, nil, sink)
+}
+
+func (t *testAlerts) decode(sink *dummyEventSink) []Alert {
+	sink.Lock()
+	defer sink.Unlock()
+
+	bs := sink.events[AlertEventLogger]
+	alerts := make([]Alert, len(bs))
+
+	for i := range bs {
+		t.NoError(util.UnmarshalJSON([]byte(bs[i]), &alerts[i]))
+	}
+
+	return alerts
+}
+
+func (t *testAlerts) TestDesign() {
+	t.Run("empty", func() {
+		d := AlertsDesign{}
+
+		t.NoError(d.IsValid(nil))
+		t.Equal(DefaultAlertsInterval, time.Duration(d.Interval))
+		t.Empty(d.Rules())
+	})
+
+	t.Run("rules", func() {
+		d := AlertsDesign{
+			ConsensusLeft: util.ReadableDuration(time.Minute),
+			DiskUsage:     90,
+			BallotStuck:   true,
+		}
+
+		t.NoError(d.IsValid(nil))
+		t.Equal([]AlertRule{AlertRuleBallotStuck, AlertRuleConsensusLeft, AlertRuleDiskUsage}, d.Rules())
+	})
+
+	t.Run("wrong disk usage", func() {
+		d := AlertsDesign{DiskUsage: 101}
+
+		err := d.IsValid(nil)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "disk_usage")
+	})
+
+	t.Run("wrong webhook", func() {
+		d := AlertsDesign{Webhook: "file:///tmp/a"}
+
+		err := d.IsValid(nil)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "webhook")
+	})
+}
+
+func (t *testAlerts) TestCondition() {
+	sink := &dummyEventSink{}
+	a := t.alerts(sink, AlertRuleDiskUsage)
+
+	var firing bool
+
+	a.SetCondition(AlertRuleDiskUsage, func() (bool, string, error) {
+		return firing, "showme", nil
+	})
+	a.SetCondition(AlertRuleTimeDrift, func() (bool, string, error) {
+		return true, "not enabled", nil
+	})
+
+	a.Evaluate()
+	t.Empty(t.decode(sink))
+
+	firing = true
+	a.Evaluate()
+	a.Evaluate()
+
+	alerts := t.decode(sink)
+	t.Equal(1, len(alerts))
+	t.Equal(AlertRuleDiskUsage, alerts[0].Rule)
+	t.Equal(t.local.String(), alerts[0].Node)
+	t.Equal("showme", alerts[0].Message)
+	t.False(alerts[0].Resolved)
+
+	firing = false
+	a.Evaluate()
+
+	alerts = t.decode(sink)
+	t.Equal(2, len(alerts))
+	t.Equal(AlertRuleDiskUsage, alerts[1].Rule)
+	t.True(alerts[1].Resolved)
+}
+
+func (t *testAlerts) TestSuffrageExpel() {
+	sink := &dummyEventSink{}
+	a := t.alerts(sink, AlertRuleSuffrageExpel)
+
+	op := isaac.NewSuffrageExpelOperation(isaac.NewSuffrageExpelFact(t.local, base.Height(33), base.Height(34), "showme"))
+	other := isaac.NewSuffrageExpelOperation(
+		isaac.NewSuffrageExpelFact(base.RandomAddress(""), base.Height(33), base.Height(34), "showme"))
+
+	a.SuffrageExpel(op)
+	a.SuffrageExpel(op)
+	a.SuffrageExpel(other)
+
+	a.Evaluate()
+
+	alerts := t.decode(sink)
+	t.Equal(1, len(alerts))
+	t.Equal(AlertRuleSuffrageExpel, alerts[0].Rule)
+	t.Contains(alerts[0].Message, op.Fact().Hash().String())
+
+	t.Run("signals are flushed", func() {
+		a.Evaluate()
+
+		t.Equal(1, len(t.decode(sink)))
+	})
+}
+
+func (t *testAlerts) TestConsensusLeft() {
+	sink := &dummyEventSink{}
+	a := t.alerts(sink, AlertRuleConsensusLeft)
+
+	suf, err := isaac.NewSuffrage([]base.Node{isaac.NewNode(base.NewMPrivatekey().Publickey(), t.local)})
+	t.NoError(err)
+
+	insuf := true
+
+	a.SetCondition(AlertRuleConsensusLeft, a.ConsensusLeftCondition(time.Millisecond*100,
+		func() (base.Suffrage, bool, error) {
+			return suf, insuf, nil
+		},
+	))
+
+	t.Run("not yet started", func() {
+		time.Sleep(time.Millisecond * 200)
+		a.Evaluate()
+
+		t.Empty(t.decode(sink))
+	})
+
+	t.Run("syncing", func() {
+		a.StateSwitched(isaacstates.StateSyncing)
+
+		a.Evaluate()
+		t.Empty(t.decode(sink))
+
+		time.Sleep(time.Millisecond * 200)
+		a.Evaluate()
+
+		alerts := t.decode(sink)
+		t.Equal(1, len(alerts))
+		t.Equal(AlertRuleConsensusLeft, alerts[0].Rule)
+		t.Contains(alerts[0].Message, isaacstates.StateSyncing.String())
+	})
+
+	t.Run("back to consensus", func() {
+		a.StateSwitched(isaacstates.StateConsensus)
+		a.Evaluate()
+
+		alerts := t.decode(sink)
+		t.Equal(2, len(alerts))
+		t.True(alerts[1].Resolved)
+	})
+
+	t.Run("not in suffrage", func() {
+		insuf = false
+
+		a.StateSwitched(isaacstates.StateSyncing)
+
+		time.Sleep(time.Millisecond * 200)
+		a.Evaluate()
+
+		t.Equal(2, len(t.decode(sink)))
+	})
+}
+
+func (t *testAlerts) TestTimeDriftWithoutTimeSyncer() {
+	firing, message, err := TimeDriftAlertCondition(nil, time.Second)()
+	t.NoError(err)
+	t.True(firing)
+	t.Contains(message, "time server not configured")
+}
+
+func (t *testAlerts) TestDiskUsage() {
+	f := DiskUsageAlertCondition(t.T().TempDir(), 100)
+
+	firing, message, err := f()
+	t.NoError(err)
+	t.False(firing)
+	t.Contains(message, "disk usage")
+}
+
+func TestAlerts(t *testing.T) {
+	suite.Run(t, new(testAlerts))
+}
@@ -0,0 +1,649 @@
+package launch
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	isaaclightclient "github.com/ProtoconNet/mitum2/isaac/lightclient"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/pkg/errors"
+)
+
+var (
+	BlockBundleManifestFileName            = "manifest.json"
+	BlockBundleDataDirectoryName           = "data"
+	BlockBundleSuffrageProofsDirectoryName = "suffrage_proofs"
+)
+
+var maxBlockBundleManifestSize int64 = 1 << 30 //nolint:gomnd //...
+
+// BlockBundleManifest lists the files of block bundle with the sha256
+// checksums. The block bundle is the tar file, which has the block items of
+// the range of heights in LocalFSWriter layout under the data directory and
+// the suffrage proofs for the range under the suffrage proofs directory. The
+// manifest is written at the end of bundle.
+type BlockBundleManifest struct {
+	NetworkID base.NetworkID    `json:"network_id"`
+	Files     []BlockBundleFile `json:"files"`
+	From      base.Height       `json:"from"`
+	To        base.Height       `json:"to"`
+}
+
+type BlockBundleFile struct {
+	Path     string `json:"path"`
+	Checksum string `json:"checksum"`
+	Size     int64  `json:"size"`
+}
+
+func (m BlockBundleManifest) IsValid(networkID base.NetworkID) error {
+	e := util.ErrInvalid.Errorf("invalid BlockBundleManifest")
+
+	switch {
+	case !m.NetworkID.Equal(networkID):
+		return e.Errorf("network id does not match")
+	case m.From < base.GenesisHeight:
+		return e.Errorf("wrong from height, %d", m.From)
+	case m.To < m.From:
+		return e.Errorf("wrong to height, %d < %d", m.To, m.From)
+	case len(m.Files) < 1:
+		return e.Errorf("empty files")
+	}
+
+	return nil
+}
+
+// BlockBundleWriter writes the files into the block bundle; the manifest is
+// written by Close.
+type BlockBundleWriter struct {
+	w        *tar.Writer
+	paths    map[string]struct{}
+	manifest BlockBundleManifest
+	sync.Mutex
+}
+
+func NewBlockBundleWriter(w io.Writer, networkID base.NetworkID, from, to base.Height) *BlockBundleWriter {
+	return &BlockBundleWriter{
+		w:     tar.NewWriter(w),
+		paths: map[string]struct{}{},
+		manifest: BlockBundleManifest{
+			NetworkID: networkID,
+			From:      from,
+			To:        to,
+		},
+	}
+}
+
+func (w *BlockBundleWriter) Add(p string, r io.Reader, size int64) error {
+	w.Lock()
+	defer w.Unlock()
+
+	e := util.StringError("add file to block bundle")
+
+	switch {
+	case w.w == nil:
+		return e.Errorf("already closed")
+	case size < 0:
+		return e.Errorf("wrong size, %d", size)
+	}
+
+	if err := isValidBlockBundlePath(p); err != nil {
+		return e.Wrap(err)
+	}
+
+	if _, found := w.paths[p]; found {
+		return e.Errorf("already added, %q", p)
+	}
+
+	if err := w.w.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     p,
+		Size:     size,
+		Mode:     0o600, //nolint:gomnd //...
+	}); err != nil {
+		return e.Wrap(err)
+	}
+
+	h := sha256.New()
+
+	if _, err := io.CopyN(io.MultiWriter(w.w, h), r, size); err != nil {
+		return e.WithMessage(err, "%q", p)
+	}
+
+	w.paths[p] = struct{}{}
+	w.manifest.Files = append(w.manifest.Files, BlockBundleFile{
+		Path:     p,
+		Checksum: fmt.Sprintf("%x", h.Sum(nil)),
+		Size:     size,
+	})
+
+	return nil
+}
+
+func (w *BlockBundleWriter) Close() (BlockBundleManifest, error) {
+	w.Lock()
+	defer w.Unlock()
+
+	e := util.StringError("close block bundle")
+
+	if w.w == nil {
+		return BlockBundleManifest{}, e.Errorf("already closed")
+	}
+
+	defer func() {
+		w.w = nil
+	}()
+
+	b, err := util.MarshalJSON(w.manifest)
+	if err != nil {
+		return BlockBundleManifest{}, e.Wrap(err)
+	}
+
+	if err := w.w.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     BlockBundleManifestFileName,
+		Size:     int64(len(b)),
+		Mode:     0o600, //nolint:gomnd //...
+	}); err != nil {
+		return BlockBundleManifest{}, e.Wrap(err)
+	}
+
+	if _, err := w.w.Write(b); err != nil {
+		return BlockBundleManifest{}, e.Wrap(err)
+	}
+
+	if err := w.w.Close(); err != nil {
+		return BlockBundleManifest{}, e.Wrap(err)
+	}
+
+	return w.manifest, nil
+}
+
+// WriteBlockBundle writes the block bundle of heights from local storage. The
+// item files are rewritten with the local item files, so the data directory of
+// bundle can be imported like the local block directory. The suffrage proofs
+// start from the suffrage of previous height of from height.
+func WriteBlockBundle(
+	ctx context.Context,
+	w io.Writer,
+	networkID base.NetworkID,
+	from, to base.Height,
+	db isaac.Database,
+	readers *isaac.BlockItemReaders,
+	fromRemotes isaac.RemotesBlockItemReadFunc,
+) (BlockBundleManifest, error) {
+	e := util.StringError("write block bundle")
+
+	switch {
+	case from < base.GenesisHeight:
+		return BlockBundleManifest{}, e.Errorf("wrong from height, %d", from)
+	case to < from:
+		return BlockBundleManifest{}, e.Errorf("wrong to height, %d < %d", to, from)
+	}
+
+	switch m, found, err := db.LastBlockMap(); {
+	case err != nil:
+		return BlockBundleManifest{}, e.Wrap(err)
+	case !found:
+		return BlockBundleManifest{}, e.Errorf("last block map not found")
+	case m.Manifest().Height() < to:
+		return BlockBundleManifest{}, e.Errorf("to height higher than last, %d > %d", to, m.Manifest().Height())
+	}
+
+	bw := NewBlockBundleWriter(w, networkID, from, to)
+
+	for height := from; height <= to; height++ {
+		if err := ctx.Err(); err != nil {
+			return BlockBundleManifest{}, e.Wrap(err)
+		}
+
+		if err := writeLocalFSBlockItems(
+			ctx, bw.Add, BlockBundleDataDirectoryName, height, readers, fromRemotes,
+		); err != nil {
+			return BlockBundleManifest{}, e.WithMessage(err, "height, %d", height)
+		}
+	}
+
+	if err := writeBlockBundleSuffrageProofs(bw, from, to, db); err != nil {
+		return BlockBundleManifest{}, e.Wrap(err)
+	}
+
+	manifest, err := bw.Close()
+	if err != nil {
+		return BlockBundleManifest{}, e.Wrap(err)
+	}
+
+	return manifest, nil
+}
+
+// ExtractBlockBundle extracts the block bundle into the directory and checks
+// the files with the manifest.
+func ExtractBlockBundle(r io.Reader, dir string) (BlockBundleManifest, error) {
+	e := util.StringError("extract block bundle")
+
+	var manifest BlockBundleManifest
+	var manifestb []byte
+
+	files := map[string]BlockBundleFile{}
+
+	tr := tar.NewReader(r)
+
+end:
+	for {
+		hdr, err := tr.Next()
+
+		switch {
+		case errors.Is(err, io.EOF):
+			break end
+		case err != nil:
+			return manifest, e.Wrap(err)
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			return manifest, e.Errorf("not regular file, %q", hdr.Name)
+		}
+
+		if hdr.Name == BlockBundleManifestFileName {
+			if manifestb != nil {
+				return manifest, e.Errorf("duplicated manifest")
+			}
+
+			i, err := io.ReadAll(io.LimitReader(tr, maxBlockBundleManifestSize))
+			if err != nil {
+				return manifest, e.WithMessage(err, "manifest")
+			}
+
+			manifestb = i
+
+			continue
+		}
+
+		if _, found := files[hdr.Name]; found {
+			return manifest, e.Errorf("duplicated file, %q", hdr.Name)
+		}
+
+		f, err := extractBlockBundleFile(tr, dir, hdr.Name)
+		if err != nil {
+			return manifest, e.Wrap(err)
+		}
+
+		files[hdr.Name] = f
+	}
+
+	if manifestb == nil {
+		return manifest, e.Errorf("manifest not found")
+	}
+
+	if err := util.UnmarshalJSON(manifestb, &manifest); err != nil {
+		return manifest, e.WithMessage(err, "manifest")
+	}
+
+	if len(manifest.Files) != len(files) {
+		return manifest, e.Errorf("files does not match with manifest, %d != %d", len(files), len(manifest.Files))
+	}
+
+	for i := range manifest.Files {
+		mf := manifest.Files[i]
+
+		switch f, found := files[mf.Path]; {
+		case !found:
+			return manifest, e.Errorf("file in manifest not found, %q", mf.Path)
+		case f.Size != mf.Size:
+			return manifest, e.Errorf("size does not match, %q", mf.Path)
+		case f.Checksum != mf.Checksum:
+			return manifest, e.Errorf("checksum does not match, %q", mf.Path)
+		}
+	}
+
+	return manifest, nil
+}
+
+// BlockBundleDataDirectory is the block data directory of extracted bundle.
+func BlockBundleDataDirectory(dir string) string {
+	return filepath.Join(dir, BlockBundleDataDirectoryName)
+}
+
+// LoadBlockBundleSuffrageProofs loads the suffrage proofs from the extracted
+// bundle directory.
+func LoadBlockBundleSuffrageProofs(
+	dir string,
+	manifest BlockBundleManifest,
+	jsonenc encoder.Encoder,
+) ([]base.SuffrageProof, error) {
+	e := util.StringError("load suffrage proofs of block bundle")
+
+	var proofs []base.SuffrageProof
+
+	for i := range manifest.Files {
+		p := manifest.Files[i].Path
+
+		if !strings.HasPrefix(p, BlockBundleSuffrageProofsDirectoryName+"/") {
+			continue
+		}
+
+		b, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(p)))
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		var proof base.SuffrageProof
+
+		if err := encoder.Decode(jsonenc, b, &proof); err != nil {
+			return nil, e.WithMessage(err, "%q", p)
+		}
+
+		proofs = append(proofs, proof)
+	}
+
+	sort.Slice(proofs, func(i, j int) bool {
+		return proofs[i].SuffrageHeight() < proofs[j].SuffrageHeight()
+	})
+
+	return proofs, nil
+}
+
+// VerifyBlockBundle verifies the block maps of heights in the extracted bundle
+// with the suffrage proofs of bundle. The suffrage proofs are proved from the
+// trusted suffrage proof, which is the suffrage proof of previous height of
+// from height in local storage. Without trusted one, from height should be
+// genesis and the first suffrage proof of bundle is trusted.
+func VerifyBlockBundle(
+	networkID base.NetworkID,
+	trusted base.SuffrageProof,
+	proofs []base.SuffrageProof,
+	itemf isaac.BlockItemReadersItemFunc,
+	from, to base.Height,
+) error {
+	e := util.ErrInvalid.Errorf("verify block bundle")
+
+	if len(proofs) < 1 {
+		return e.Errorf("empty suffrage proofs")
+	}
+
+	first := trusted
+	others := proofs
+
+	switch {
+	case trusted == nil && from != base.GenesisHeight:
+		return e.Errorf("empty trusted suffrage proof for from height, %d", from)
+	case trusted == nil:
+		first = proofs[0]
+		others = proofs[1:]
+	default:
+		others = util.FilterSlice(proofs, func(i base.SuffrageProof) bool {
+			return i.SuffrageHeight() > trusted.SuffrageHeight()
+		})
+
+		for i := range proofs {
+			if proofs[i].SuffrageHeight() != trusted.SuffrageHeight() {
+				continue
+			}
+
+			if !proofs[i].State().Hash().Equal(trusted.State().Hash()) {
+				return e.Errorf("suffrage proof, %d does not match with local", trusted.SuffrageHeight())
+			}
+		}
+	}
+
+	sorted, err := isaaclightclient.ProveSuffrageProofs(networkID, first, others)
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	for height := from; height <= to; height++ {
+		switch m, found, err := isaac.BlockItemReadersDecode[base.BlockMap](itemf, height, base.BlockItemMap, nil); {
+		case err != nil:
+			return e.Wrap(err)
+		case !found:
+			return e.Errorf("block map, %d not found", height)
+		default:
+			if err := m.IsValid(networkID); err != nil {
+				return e.Wrap(err)
+			}
+
+			if err := isaaclightclient.VerifyBlockMapWithSuffrageProofs(sorted, m); err != nil {
+				return e.Wrap(err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeLocalFSBlockItems writes the block items of height in LocalFSWriter
+// layout under dir; the item files are rewritten with the local item files.
+func writeLocalFSBlockItems(
+	ctx context.Context,
+	add func(string, io.Reader, int64) error,
+	dir string,
+	height base.Height,
+	readers *isaac.BlockItemReaders,
+	fromRemotes isaac.RemotesBlockItemReadFunc,
+) error {
+	var items map[base.BlockItemType]base.BlockItemFile
+
+	switch bfiles, found, err := readers.ItemFiles(height); {
+	case err != nil:
+		return err
+	case !found:
+		return util.ErrNotFound.Errorf("block item files")
+	default:
+		items = bfiles.Items()
+	}
+
+	types := make([]base.BlockItemType, 0, len(items))
+
+	for t := range items {
+		types = append(types, t)
+	}
+
+	sort.Slice(types, func(i, j int) bool {
+		return types[i] < types[j]
+	})
+
+	hdir := path.Join(dir, filepath.ToSlash(isaac.BlockHeightDirectory(height)))
+	newitems := make(map[base.BlockItemType]base.BlockItemFile, len(items))
+	names := map[string]struct{}{}
+
+	for i := range types {
+		t := types[i]
+		item := items[t]
+
+		name := blockBundleItemName(t, item.URI())
+		if _, found := names[name]; found {
+			name = t.String() + "-" + name
+		}
+
+		names[name] = struct{}{}
+
+		if err := writeLocalFSBlockItem(ctx, add, path.Join(hdir, name), height, item, readers, fromRemotes); err != nil {
+			return errors.WithMessagef(err, "item, %q", t)
+		}
+
+		newitems[t] = isaac.NewLocalFSBlockItemFile(name, item.CompressFormat())
+	}
+
+	b, err := util.MarshalJSON(isaac.NewBlockItemFiles(newitems))
+	if err != nil {
+		return err
+	}
+
+	return add(
+		path.Join(dir, filepath.ToSlash(isaac.BlockItemFilesPath("", height))),
+		bytes.NewReader(b),
+		int64(len(b)),
+	)
+}
+
+func writeLocalFSBlockItem(
+	ctx context.Context,
+	add func(string, io.Reader, int64) error,
+	p string,
+	height base.Height,
+	item base.BlockItemFile,
+	readers *isaac.BlockItemReaders,
+	fromRemotes isaac.RemotesBlockItemReadFunc,
+) error {
+	if isaac.IsInLocalBlockItemFile(item.URI()) {
+		switch f, found, err := readers.ReadFileFromItemFile(height, item); {
+		case err != nil:
+			return err
+		case !found:
+			return util.ErrNotFound.Errorf("item file")
+		default:
+			defer func() {
+				_ = f.Close()
+			}()
+
+			size, err := f.Seek(0, io.SeekEnd)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return errors.WithStack(err)
+			}
+
+			return add(p, f, size)
+		}
+	}
+
+	uri := item.URI()
+
+	// NOTE remote item is downloaded into memory to know the size.
+	switch known, found, err := fromRemotes(ctx, uri, item.CompressFormat(),
+		func(r io.Reader, _ string) error {
+			b, err := io.ReadAll(r)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			return add(p, bytes.NewReader(b), int64(len(b)))
+		},
+	); {
+	case err != nil:
+		return err
+	case !known:
+		return errors.Errorf("unknown remote item file, %q", (&uri).String())
+	case !found:
+		return util.ErrNotFound.Errorf("remote item file, %q", (&uri).String())
+	default:
+		return nil
+	}
+}
+
+func writeBlockBundleSuffrageProofs(bw *BlockBundleWriter, from, to base.Height, db isaac.Database) error {
+	start := from - 1
+	if start < base.GenesisHeight {
+		start = base.GenesisHeight
+	}
+
+	var proof base.SuffrageProof
+
+	switch i, found, err := db.SuffrageProofByBlockHeight(start); {
+	case err != nil:
+		return err
+	case !found:
+		return util.ErrNotFound.Errorf("suffrage proof by block height, %d", start)
+	default:
+		proof = i
+	}
+
+	for {
+		b, err := util.MarshalJSON(proof)
+		if err != nil {
+			return err
+		}
+
+		if err := bw.Add(
+			path.Join(BlockBundleSuffrageProofsDirectoryName, proof.SuffrageHeight().String()+".json"),
+			bytes.NewReader(b),
+			int64(len(b)),
+		); err != nil {
+			return err
+		}
+
+		switch i, found, err := db.SuffrageProof(proof.SuffrageHeight() + 1); {
+		case err != nil:
+			return err
+		case !found, i.Map().Manifest().Height() > to:
+			return nil
+		default:
+			proof = i
+		}
+	}
+}
+
+func extractBlockBundleFile(r io.Reader, dir, p string) (BlockBundleFile, error) {
+	if err := isValidBlockBundlePath(p); err != nil {
+		return BlockBundleFile{}, err
+	}
+
+	fpath := filepath.Join(dir, filepath.FromSlash(p))
+
+	if err := os.MkdirAll(filepath.Dir(fpath), 0o700); err != nil {
+		return BlockBundleFile{}, errors.WithStack(err)
+	}
+
+	f, err := os.OpenFile(filepath.Clean(fpath), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return BlockBundleFile{}, errors.WithStack(err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	h := sha256.New()
+
+	size, err := io.Copy(io.MultiWriter(f, h), r)
+	if err != nil {
+		return BlockBundleFile{}, errors.WithMessagef(err, "%q", p)
+	}
+
+	return BlockBundleFile{
+		Path:     p,
+		Checksum: fmt.Sprintf("%x", h.Sum(nil)),
+		Size:     size,
+	}, nil
+}
+
+func isValidBlockBundlePath(p string) error {
+	switch {
+	case len(p) < 1:
+		return errors.Errorf("empty path")
+	case p == BlockBundleManifestFileName:
+		return errors.Errorf("manifest path")
+	case path.IsAbs(p), path.Clean(p) != p, p == "..", strings.HasPrefix(p, "../"):
+		return errors.Errorf("wrong path, %q", p)
+	case !strings.HasPrefix(p, BlockBundleDataDirectoryName+"/") &&
+		!strings.HasPrefix(p, BlockBundleSuffrageProofsDirectoryName+"/"):
+		return errors.Errorf("unknown path, %q", p)
+	}
+
+	return nil
+}
+
+func blockBundleItemName(t base.BlockItemType, uri url.URL) string {
+	switch name := path.Base(uri.Path); {
+	case isaac.IsInLocalBlockItemFile(uri):
+		return strings.TrimPrefix(uri.Path, "/")
+	case len(name) < 1, name == ".", name == "/":
+		return t.String()
+	default:
+		return name
+	}
+}
@@ -0,0 +1,165 @@
+package launch
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/stretchr/testify/suite"
+)
+
+type testBlockBundle struct {
+	suite.Suite
+	networkID base.NetworkID
+}
+
+func (t *testBlockBundle) SetupTest() {
+	t.networkID = base.RandomNetworkID()
+}
+
+func (t *testBlockBundle) write(files map[string][]byte) (*bytes.Buffer, BlockBundleManifest) {
+	buf := bytes.NewBuffer(nil)
+
+	w := NewBlockBundleWriter(buf, t.networkID, 3, 5)
+
+	for p, b := range files {
+		t.NoError(w.Add(p, bytes.NewReader(b), int64(len(b))))
+	}
+
+	manifest, err := w.Close()
+	t.NoError(err)
+
+	return buf, manifest
+}
+
+func (t *testBlockBundle) rawTar(files map[string][]byte) *bytes.Buffer {
+	buf := bytes.NewBuffer(nil)
+
+	w := tar.NewWriter(buf)
+
+	for p, b := range files {
+		t.NoError(w.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: p, Size: int64(len(b)), Mode: 0o600}))
+
+		_, err := w.Write(b)
+		t.NoError(err)
+	}
+
+	t.NoError(w.Close())
+
+	return buf
+}
+
+func (t *testBlockBundle) TestExtract() {
+	files := map[string][]byte{
+		"data/000/000/000/000/000/000/003/map.json": util.UUID().Bytes(),
+		"data/000/000/000/000/000/000/3.json":       util.UUID().Bytes(),
+		"suffrage_proofs/1.json":                    util.UUID().Bytes(),
+	}
+
+	buf, manifest := t.write(files)
+
+	t.NoError(manifest.IsValid(t.networkID))
+	t.Equal(base.Height(3), manifest.From)
+	t.Equal(base.Height(5), manifest.To)
+	t.Equal(3, len(manifest.Files))
+
+	dir := t.T().TempDir()
+
+	rmanifest, err := ExtractBlockBundle(buf, dir)
+	t.NoError(err)
+	t.Equal(manifest, rmanifest)
+
+	for p, b := range files {
+		rb, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(p)))
+		t.NoError(err)
+		t.Equal(b, rb, "path=%q", p)
+	}
+
+	t.Run("wrong network id", func() {
+		err := rmanifest.IsValid(base.RandomNetworkID())
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+	})
+}
+
+func (t *testBlockBundle) TestWrongPath() {
+	w := NewBlockBundleWriter(bytes.NewBuffer(nil), t.networkID, 3, 5)
+
+	for _, p := range []string{
+		"",
+		BlockBundleManifestFileName,
+		"/data/a",
+		"data/../../a",
+		"unknown/a",
+	} {
+		err := w.Add(p, bytes.NewReader(nil), 0)
+		t.Error(err, "path=%q", p)
+	}
+
+	t.Run("traversal in bundle", func() {
+		buf := t.rawTar(map[string][]byte{"data/../../a": util.UUID().Bytes()})
+
+		_, err := ExtractBlockBundle(buf, t.T().TempDir())
+		t.Error(err)
+		t.ErrorContains(err, "wrong path")
+	})
+}
+
+func (t *testBlockBundle) TestBrokenBundle() {
+	files := map[string][]byte{
+		"data/000/000/000/000/000/000/003/map.json": util.UUID().Bytes(),
+	}
+
+	_, manifest := t.write(files)
+
+	mb, err := util.MarshalJSON(manifest)
+	t.NoError(err)
+
+	t.Run("manifest not found", func() {
+		_, err := ExtractBlockBundle(t.rawTar(files), t.T().TempDir())
+		t.Error(err)
+		t.ErrorContains(err, "manifest not found")
+	})
+
+	t.Run("checksum does not match", func() {
+		buf := t.rawTar(map[string][]byte{
+			"data/000/000/000/000/000/000/003/map.json": util.UUID().Bytes(),
+			BlockBundleManifestFileName:                 mb,
+		})
+
+		_, err := ExtractBlockBundle(buf, t.T().TempDir())
+		t.Error(err)
+		t.ErrorContains(err, "checksum does not match")
+	})
+
+	t.Run("unknown file", func() {
+		buf := t.rawTar(map[string][]byte{
+			"data/000/000/000/000/000/000/003/map.json":    files["data/000/000/000/000/000/000/003/map.json"],
+			"data/000/000/000/000/000/000/003/states.json": util.UUID().Bytes(),
+			BlockBundleManifestFileName:                    mb,
+		})
+
+		_, err := ExtractBlockBundle(buf, t.T().TempDir())
+		t.Error(err)
+		t.ErrorContains(err, "files does not match with manifest")
+	})
+
+	t.Run("missing file", func() {
+		buf := t.rawTar(map[string][]byte{
+			"data/000/000/000/000/000/000/003/states.json": files["data/000/000/000/000/000/000/003/map.json"],
+			BlockBundleManifestFileName:                    mb,
+		})
+
+		_, err := ExtractBlockBundle(buf, t.T().TempDir())
+		t.Error(err)
+		t.ErrorContains(err, "file in manifest not found")
+	})
+}
+
+func TestBlockBundle(t *testing.T) {
+	suite.Run(t, new(testBlockBundle))
+}
@@ -0,0 +1,181 @@
+package launch
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	isaacnetwork "github.com/ProtoconNet/mitum2/isaac/network"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/pkg/errors"
+)
+
+// PublishBlockMirror writes the blocks of local storage into the static block
+// mirror directory. The blocks are written in LocalFSWriter layout with the
+// local item files and the last height file is updated after each height, so
+// the mirror can be served while publishing. To prevent the gap in mirror, from
+// height should not be higher than the next of the last height of mirror; the
+// last height of mirror is not lowered by republishing the old blocks.
+func PublishBlockMirror(
+	ctx context.Context,
+	root string,
+	from, to base.Height,
+	readers *isaac.BlockItemReaders,
+	fromRemotes isaac.RemotesBlockItemReadFunc,
+) error {
+	e := util.StringError("publish block mirror")
+
+	switch {
+	case from < base.GenesisHeight:
+		return e.Errorf("wrong from height, %d", from)
+	case to < from:
+		return e.Errorf("wrong to height, %d < %d", to, from)
+	}
+
+	if err := os.MkdirAll(root, 0o700); err != nil {
+		return e.Wrap(err)
+	}
+
+	last := base.NilHeight
+
+	switch mirror, err := isaac.NewBlockMirror(&url.URL{Scheme: "file", Path: filepath.ToSlash(root)}, nil); {
+	case err != nil:
+		return e.Wrap(err)
+	default:
+		switch i, found, err := mirror.LastHeight(ctx); {
+		case err != nil:
+			return e.Wrap(err)
+		case found:
+			last = i
+		}
+	}
+
+	if from > last+1 {
+		return e.Errorf("from height over the next of last height of mirror, %d > %d", from, last+1)
+	}
+
+	add := func(p string, r io.Reader, _ int64) error {
+		return writeBlockMirrorFile(root, p, r)
+	}
+
+	for height := from; height <= to; height++ {
+		if err := ctx.Err(); err != nil {
+			return e.Wrap(err)
+		}
+
+		if err := writeLocalFSBlockItems(ctx, add, "", height, readers, fromRemotes); err != nil {
+			return e.WithMessage(err, "height, %d", height)
+		}
+
+		if height <= last {
+			continue
+		}
+
+		if err := isaac.WriteBlockMirrorLastHeight(root, height); err != nil {
+			return e.Wrap(err)
+		}
+
+		last = height
+	}
+
+	return nil
+}
+
+// BlockMirrorsFromSyncSources returns the block mirrors of the sync sources.
+func BlockMirrorsFromSyncSources(
+	sources []isaacnetwork.SyncSource,
+	jsonenc encoder.Encoder,
+) ([]*isaac.BlockMirror, error) {
+	var mirrors []*isaac.BlockMirror
+
+	for i := range sources {
+		s := sources[i]
+
+		if s.Type != isaacnetwork.SyncSourceTypeMirror {
+			continue
+		}
+
+		u, ok := s.Source.(*url.URL)
+		if !ok {
+			return nil, errors.Errorf("expected *url.URL for mirror, but %T", s.Source)
+		}
+
+		m, err := isaac.NewBlockMirror(u, jsonenc)
+		if err != nil {
+			return nil, err
+		}
+
+		mirrors = append(mirrors, m)
+	}
+
+	return mirrors, nil
+}
+
+// BlockMirrorBlockMap reads and decodes the block map from block mirror.
+func BlockMirrorBlockMap(
+	ctx context.Context,
+	mirror *isaac.BlockMirror,
+	height base.Height,
+	readers *isaac.BlockItemReaders,
+) (m base.BlockMap, found bool, _ error) {
+	found, err := mirror.Item(ctx, height, base.BlockItemMap, func(r io.Reader, compressFormat string) error {
+		i, err := isaac.BlockItemReadersDecodeFromReader[base.BlockMap](
+			readers.ItemFromReader, base.BlockItemMap, r, compressFormat, nil)
+		if err != nil {
+			return err
+		}
+
+		m = i
+
+		return nil
+	})
+
+	return m, found, err
+}
+
+// BlockMirrorLastBlockMap reads the block map of last height of block mirror.
+func BlockMirrorLastBlockMap(
+	ctx context.Context,
+	mirror *isaac.BlockMirror,
+	readers *isaac.BlockItemReaders,
+) (base.BlockMap, bool, error) {
+	switch height, found, err := mirror.LastHeight(ctx); {
+	case err != nil, !found:
+		return nil, false, err
+	default:
+		return BlockMirrorBlockMap(ctx, mirror, height, readers)
+	}
+}
+
+func writeBlockMirrorFile(root, p string, r io.Reader) error {
+	fpath := filepath.Join(root, filepath.FromSlash(p))
+
+	if err := os.MkdirAll(filepath.Dir(fpath), 0o700); err != nil {
+		return errors.WithStack(err)
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(fpath), "temp-")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+
+	if _, err := io.Copy(f, r); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := f.Sync(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return errors.WithStack(os.Rename(f.Name(), fpath))
+}
@@ -0,0 +1,166 @@
+package launch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	isaacblock "github.com/ProtoconNet/mitum2/isaac/block"
+	"github.com/stretchr/testify/suite"
+)
+
+type testBlockMirror struct {
+	isaacblock.BaseTestLocalBlockFS
+}
+
+func (t *testBlockMirror) prepare(from, to base.Height) map[base.Height]base.BlockMap {
+	maps := map[base.Height]base.BlockMap{}
+
+	for height := from; height <= to; height++ {
+		fs, _, _, _, _, _, _ := t.PrepareFS(base.NewPoint(height, 0), nil, nil)
+
+		m, err := fs.Save(context.Background())
+		t.NoError(err)
+
+		maps[height] = m
+	}
+
+	return maps
+}
+
+func (t *testBlockMirror) newMirror(u *url.URL) *isaac.BlockMirror {
+	mirror, err := isaac.NewBlockMirror(u, t.Enc)
+	t.NoError(err)
+
+	return mirror
+}
+
+func (t *testBlockMirror) TestPublish() {
+	maps := t.prepare(0, 4)
+
+	root := filepath.Join(t.T().TempDir(), "mirror")
+
+	t.NoError(PublishBlockMirror(context.Background(), root, 0, 2, t.Readers, isaac.NewDefaultRemotesBlockItemReadFunc()))
+
+	mirror := t.newMirror(&url.URL{Scheme: "file", Path: filepath.ToSlash(root)})
+
+	t.Run("last height", func() {
+		height, found, err := mirror.LastHeight(context.Background())
+		t.NoError(err)
+		t.True(found)
+		t.Equal(base.Height(2), height)
+	})
+
+	t.Run("blockmap", func() {
+		m, found, err := BlockMirrorBlockMap(context.Background(), mirror, 1, t.Readers)
+		t.NoError(err)
+		t.True(found)
+		t.True(maps[1].Manifest().Hash().Equal(m.Manifest().Hash()))
+	})
+
+	t.Run("last blockmap", func() {
+		m, found, err := BlockMirrorLastBlockMap(context.Background(), mirror, t.Readers)
+		t.NoError(err)
+		t.True(found)
+		t.Equal(base.Height(2), m.Manifest().Height())
+	})
+
+	t.Run("unknown height", func() {
+		_, found, err := BlockMirrorBlockMap(context.Background(), mirror, 3, t.Readers)
+		t.NoError(err)
+		t.False(found)
+	})
+
+	t.Run("http", func() {
+		ts := httptest.NewServer(http.FileServer(http.Dir(root)))
+		defer ts.Close()
+
+		u, err := url.Parse(ts.URL)
+		t.NoError(err)
+
+		hmirror := t.newMirror(u)
+
+		height, found, err := hmirror.LastHeight(context.Background())
+		t.NoError(err)
+		t.True(found)
+		t.Equal(base.Height(2), height)
+
+		m, found, err := BlockMirrorBlockMap(context.Background(), hmirror, 2, t.Readers)
+		t.NoError(err)
+		t.True(found)
+		t.True(maps[2].Manifest().Hash().Equal(m.Manifest().Hash()))
+
+		_, found, err = BlockMirrorBlockMap(context.Background(), hmirror, 3, t.Readers)
+		t.NoError(err)
+		t.False(found)
+	})
+
+	t.Run("continue", func() {
+		t.NoError(PublishBlockMirror(context.Background(), root, 3, 4, t.Readers, isaac.NewDefaultRemotesBlockItemReadFunc()))
+
+		height, found, err := mirror.LastHeight(context.Background())
+		t.NoError(err)
+		t.True(found)
+		t.Equal(base.Height(4), height)
+
+		m, found, err := BlockMirrorBlockMap(context.Background(), mirror, 4, t.Readers)
+		t.NoError(err)
+		t.True(found)
+		t.True(maps[4].Manifest().Hash().Equal(m.Manifest().Hash()))
+	})
+
+	t.Run("republish old blocks", func() {
+		t.NoError(PublishBlockMirror(context.Background(), root, 1, 2, t.Readers, isaac.NewDefaultRemotesBlockItemReadFunc()))
+
+		height, found, err := mirror.LastHeight(context.Background())
+		t.NoError(err)
+		t.True(found)
+		t.Equal(base.Height(4), height)
+	})
+
+	t.Run("gap", func() {
+		t.prepare(5, 6)
+
+		err := PublishBlockMirror(context.Background(), root, 6, 6, t.Readers, isaac.NewDefaultRemotesBlockItemReadFunc())
+		t.Error(err)
+		t.ErrorContains(err, "from height over the next of last height of mirror")
+
+		height, found, err := mirror.LastHeight(context.Background())
+		t.NoError(err)
+		t.True(found)
+		t.Equal(base.Height(4), height)
+	})
+
+	t.Run("no temp files left", func() {
+		files, err := os.ReadDir(root)
+		t.NoError(err)
+
+		for i := range files {
+			t.NotContains(files[i].Name(), "temp-")
+		}
+	})
+}
+
+func (t *testBlockMirror) TestWrongHeights() {
+	root := t.T().TempDir()
+
+	err := PublishBlockMirror(context.Background(), root, 3, 2, t.Readers, isaac.NewDefaultRemotesBlockItemReadFunc())
+	t.Error(err)
+	t.ErrorContains(err, "wrong to height")
+
+	t.Run("empty mirror", func() {
+		err := PublishBlockMirror(context.Background(), root, 1, 2, t.Readers, isaac.NewDefaultRemotesBlockItemReadFunc())
+		t.Error(err)
+		t.ErrorContains(err, "from height over the next of last height of mirror")
+	})
+}
+
+func TestBlockMirror(t *testing.T) {
+	suite.Run(t, new(testBlockMirror))
+}
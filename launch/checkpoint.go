@@ -0,0 +1,193 @@
+package launch
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/ProtoconNet/mitum2/util/valuehash"
+	"github.com/pkg/errors"
+)
+
+// CheckpointDesign sets the trusted checkpoint for syncing; the blocks under
+// the checkpoint are imported after checking the hash chain only. The
+// checkpoint is loaded from File, which is signed by the suffrage nodes, or is
+// set by Height and Manifest. SuffrageProofFile is the file of trusted suffrage
+// proof to verify the signs of checkpoint file.
+type CheckpointDesign struct {
+	checkpoint        *isaac.Checkpoint
+	suffrageProof     base.SuffrageProof
+	File              string      `json:"file,omitempty" yaml:"file,omitempty"`
+	SuffrageProofFile string      `json:"suffrage_proof,omitempty" yaml:"suffrage_proof,omitempty"`
+	Manifest          string      `json:"manifest,omitempty" yaml:"manifest,omitempty"`
+	Height            base.Height `json:"height,omitempty" yaml:"height,omitempty"`
+}
+
+// Decode loads the checkpoint.
+func (d *CheckpointDesign) Decode(jsonencoder encoder.Encoder) error {
+	e := util.StringError("decode CheckpointDesign")
+
+	switch {
+	case len(d.File) > 0:
+		if d.Height > 0 || len(d.Manifest) > 0 {
+			return e.Errorf("file and height/manifest both given")
+		}
+
+		c, err := LoadCheckpointFile(d.File, jsonencoder)
+		if err != nil {
+			return e.Wrap(err)
+		}
+
+		d.checkpoint = &c
+
+		if len(d.SuffrageProofFile) > 0 {
+			proof, err := loadSuffrageProofFile(d.SuffrageProofFile, jsonencoder)
+			if err != nil {
+				return e.Wrap(err)
+			}
+
+			d.suffrageProof = proof
+		}
+	default:
+		if len(d.SuffrageProofFile) > 0 {
+			return e.Errorf("suffrage proof without file")
+		}
+
+		c := isaac.NewCheckpoint(d.Height, valuehash.NewBytesFromString(d.Manifest))
+
+		d.checkpoint = &c
+	}
+
+	return nil
+}
+
+func (d *CheckpointDesign) IsValid(networkID []byte) error {
+	e := util.ErrInvalid.Errorf("invalid CheckpointDesign")
+
+	if d.checkpoint == nil {
+		return e.Errorf("not loaded")
+	}
+
+	if err := d.checkpoint.IsValid(networkID); err != nil {
+		return e.Wrap(err)
+	}
+
+	if d.suffrageProof != nil {
+		if err := d.suffrageProof.IsValid(networkID); err != nil {
+			return e.WithMessage(err, "suffrage proof")
+		}
+	}
+
+	return nil
+}
+
+// Checkpoint returns the loaded checkpoint; if the checkpoint is not signed,
+// it is trusted without suffrage.
+func (d *CheckpointDesign) Checkpoint() *isaac.Checkpoint {
+	return d.checkpoint
+}
+
+// SuffrageProof returns the trusted suffrage proof for the signed checkpoint.
+func (d *CheckpointDesign) SuffrageProof() base.SuffrageProof {
+	return d.suffrageProof
+}
+
+func LoadCheckpointFile(f string, jsonencoder encoder.Encoder) (c isaac.Checkpoint, _ error) {
+	b, err := os.ReadFile(filepath.Clean(f))
+	if err != nil {
+		return c, errors.WithStack(err)
+	}
+
+	if err := encoder.Decode(jsonencoder, b, &c); err != nil {
+		return c, errors.WithMessage(err, "load checkpoint file")
+	}
+
+	return c, nil
+}
+
+func loadSuffrageProofFile(f string, jsonencoder encoder.Encoder) (proof base.SuffrageProof, _ error) {
+	b, err := os.ReadFile(filepath.Clean(f))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if err := encoder.Decode(jsonencoder, b, &proof); err != nil {
+		return nil, errors.WithMessage(err, "load suffrage proof file")
+	}
+
+	return proof, nil
+}
+
+// IsCheckpointSignedByLastSuffrage checks the checkpoint is signed by the
+// suffrage of the last suffrage proof.
+func IsCheckpointSignedByLastSuffrage(
+	c isaac.Checkpoint,
+	proof base.SuffrageProof,
+	threshold base.Threshold,
+) error {
+	e := util.StringError("check checkpoint by last suffrage")
+
+	if proof == nil {
+		return e.Errorf("empty last suffrage proof")
+	}
+
+	suf, err := proof.Suffrage()
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	suf, err = isaac.KeyRotatedSuffrage(suf, proof.Map().Manifest().Height())
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := c.IsSignedBySuffrage(suf, threshold); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
+
+// syncerCheckpointFunc returns the checkpoint for syncer. The checkpoint
+// without signs is trusted as it is; the signed checkpoint is checked by the
+// trusted suffrage proof, which is the suffrage proof of design or the last
+// suffrage proof of local database.
+func syncerCheckpointFunc(
+	design *CheckpointDesign,
+	lastSuffrageProoff func() (base.SuffrageProof, bool, error),
+	threshold base.Threshold,
+) func() (*isaac.Checkpoint, error) {
+	return func() (*isaac.Checkpoint, error) {
+		if design == nil || design.Checkpoint() == nil {
+			return nil, nil
+		}
+
+		c := design.Checkpoint()
+
+		if len(c.Signs()) < 1 {
+			return c, nil
+		}
+
+		proof := design.SuffrageProof()
+
+		if proof == nil {
+			switch i, found, err := lastSuffrageProoff(); {
+			case err != nil:
+				return nil, errors.WithMessage(err, "last suffrage proof for checkpoint")
+			case !found:
+				return nil, errors.Errorf("signed checkpoint; trusted suffrage proof not found")
+			default:
+				proof = i
+			}
+		}
+
+		if err := IsCheckpointSignedByLastSuffrage(*c, proof, threshold); err != nil {
+			return nil, errors.WithMessage(err, "signed checkpoint")
+		}
+
+		return c, nil
+	}
+}
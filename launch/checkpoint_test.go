@@ -0,0 +1,257 @@
+package launch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	isaacblock "github.com/ProtoconNet/mitum2/isaac/block"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	jsonenc "github.com/ProtoconNet/mitum2/util/encoder/json"
+	"github.com/ProtoconNet/mitum2/util/localtime"
+	"github.com/ProtoconNet/mitum2/util/valuehash"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+)
+
+type testCheckpointDesign struct {
+	suite.Suite
+	enc       *jsonenc.Encoder
+	networkID base.NetworkID
+}
+
+func (t *testCheckpointDesign) SetupSuite() {
+	t.enc = jsonenc.NewEncoder()
+	t.networkID = base.RandomNetworkID()
+
+	t.NoError(t.enc.Add(encoder.DecodeDetail{Hint: base.StringAddressHint, Instance: base.StringAddress{}}))
+	t.NoError(t.enc.Add(encoder.DecodeDetail{Hint: base.MPublickeyHint, Instance: &base.MPublickey{}}))
+	t.NoError(t.enc.Add(encoder.DecodeDetail{Hint: isaac.CheckpointHint, Instance: isaac.Checkpoint{}}))
+}
+
+func (t *testCheckpointDesign) decode(b []byte) (*CheckpointDesign, error) {
+	var d CheckpointDesign
+	t.NoError(yaml.Unmarshal(b, &d))
+
+	if err := d.Decode(t.enc); err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+func (t *testCheckpointDesign) TestHeightAndManifest() {
+	manifest := valuehash.RandomSHA256()
+
+	d, err := t.decode([]byte(`
+height: 33
+manifest: ` + manifest.String()))
+	t.NoError(err)
+	t.NoError(d.IsValid(t.networkID))
+
+	c := d.Checkpoint()
+	t.NotNil(c)
+	t.Equal(base.Height(33), c.Height())
+	t.True(manifest.Equal(c.Manifest()))
+	t.Empty(c.Signs())
+}
+
+func (t *testCheckpointDesign) TestEmptyManifest() {
+	d, err := t.decode([]byte(`height: 33`))
+	t.NoError(err)
+
+	err = d.IsValid(t.networkID)
+	t.Error(err)
+	t.ErrorIs(err, util.ErrInvalid)
+}
+
+func (t *testCheckpointDesign) TestFile() {
+	local := base.RandomLocalNode()
+
+	c := isaac.NewCheckpoint(base.Height(33), valuehash.RandomSHA256())
+	t.NoError(c.Sign(local.Address(), local.Privatekey(), t.networkID))
+
+	b, err := util.MarshalJSON(c)
+	t.NoError(err)
+
+	f := filepath.Join(t.T().TempDir(), "checkpoint.json")
+	t.NoError(os.WriteFile(f, b, 0o600))
+
+	t.Run("ok", func() {
+		d, err := t.decode([]byte(`file: ` + f))
+		t.NoError(err)
+		t.NoError(d.IsValid(t.networkID))
+
+		rc := d.Checkpoint()
+		t.Equal(c.Height(), rc.Height())
+		t.True(c.Manifest().Equal(rc.Manifest()))
+		t.Equal(1, len(rc.Signs()))
+	})
+
+	t.Run("wrong network id", func() {
+		d, err := t.decode([]byte(`file: ` + f))
+		t.NoError(err)
+
+		err = d.IsValid(util.UUID().Bytes())
+		t.Error(err)
+		t.ErrorIs(err, base.ErrSignatureVerification)
+	})
+
+	t.Run("file and height", func() {
+		_, err := t.decode([]byte(`
+file: ` + f + `
+height: 33`))
+		t.Error(err)
+		t.ErrorContains(err, "both given")
+	})
+
+	t.Run("suffrage proof without file", func() {
+		_, err := t.decode([]byte(`
+height: 33
+manifest: ` + c.Manifest().String() + `
+suffrage_proof: ` + f + `.proof`))
+		t.Error(err)
+		t.ErrorContains(err, "suffrage proof without file")
+	})
+
+	t.Run("unknown suffrage proof file", func() {
+		_, err := t.decode([]byte(`
+file: ` + f + `
+suffrage_proof: ` + f + `.proof`))
+		t.Error(err)
+		t.ErrorIs(err, os.ErrNotExist)
+	})
+
+	t.Run("unknown file", func() {
+		_, err := t.decode([]byte(`file: ` + f + `.unknown`))
+		t.Error(err)
+		t.ErrorIs(err, os.ErrNotExist)
+	})
+}
+
+type dummyCheckpointSuffrageProof struct {
+	base.SuffrageProof
+	m   base.BlockMap
+	suf base.Suffrage
+}
+
+func (p dummyCheckpointSuffrageProof) Map() base.BlockMap {
+	return p.m
+}
+
+func (p dummyCheckpointSuffrageProof) Suffrage() (base.Suffrage, error) {
+	return p.suf, nil
+}
+
+func (t *testCheckpointDesign) suffrageProof(nodes ...base.Node) base.SuffrageProof {
+	suf, err := isaac.NewSuffrage(nodes)
+	t.NoError(err)
+
+	m := isaacblock.NewBlockMap()
+	m.SetManifest(isaac.NewManifest(
+		base.Height(3), valuehash.RandomSHA256(), nil, nil, nil, valuehash.RandomSHA256(), localtime.Now().UTC()))
+
+	return dummyCheckpointSuffrageProof{m: m, suf: suf}
+}
+
+func (t *testCheckpointDesign) TestSignedByLastSuffrage() {
+	local := base.RandomLocalNode()
+
+	c := isaac.NewCheckpoint(base.Height(33), valuehash.RandomSHA256())
+
+	sc := c
+	t.NoError(sc.Sign(local.Address(), local.Privatekey(), t.networkID))
+
+	notfoundf := func() (base.SuffrageProof, bool, error) {
+		return nil, false, nil
+	}
+
+	t.Run("empty proof", func() {
+		err := IsCheckpointSignedByLastSuffrage(c, nil, base.DefaultThreshold)
+		t.Error(err)
+		t.ErrorContains(err, "empty last suffrage proof")
+	})
+
+	t.Run("syncer checkpoint without signs", func() {
+		d := &CheckpointDesign{checkpoint: &c}
+
+		f := syncerCheckpointFunc(d, notfoundf, base.DefaultThreshold)
+
+		rc, err := f()
+		t.NoError(err)
+		t.NotNil(rc)
+	})
+
+	t.Run("syncer checkpoint without proof", func() {
+		d := &CheckpointDesign{checkpoint: &sc}
+
+		f := syncerCheckpointFunc(d, notfoundf, base.DefaultThreshold)
+
+		rc, err := f()
+		t.Error(err)
+		t.ErrorContains(err, "trusted suffrage proof not found")
+		t.Nil(rc)
+	})
+
+	t.Run("syncer checkpoint with proof of design", func() {
+		d := &CheckpointDesign{checkpoint: &sc, suffrageProof: t.suffrageProof(local)}
+
+		f := syncerCheckpointFunc(d, notfoundf, base.DefaultThreshold)
+
+		rc, err := f()
+		t.NoError(err)
+		t.NotNil(rc)
+	})
+
+	t.Run("syncer checkpoint with last proof of database", func() {
+		d := &CheckpointDesign{checkpoint: &sc}
+
+		f := syncerCheckpointFunc(d, func() (base.SuffrageProof, bool, error) {
+			return t.suffrageProof(local), true, nil
+		}, base.DefaultThreshold)
+
+		rc, err := f()
+		t.NoError(err)
+		t.NotNil(rc)
+	})
+
+	t.Run("syncer checkpoint not signed by suffrage", func() {
+		d := &CheckpointDesign{checkpoint: &sc, suffrageProof: t.suffrageProof(base.RandomLocalNode())}
+
+		f := syncerCheckpointFunc(d, notfoundf, base.DefaultThreshold)
+
+		rc, err := f()
+		t.Error(err)
+		t.ErrorContains(err, "signed checkpoint")
+		t.Nil(rc)
+	})
+
+	t.Run("syncer checkpoint with database error", func() {
+		d := &CheckpointDesign{checkpoint: &sc}
+
+		f := syncerCheckpointFunc(d, func() (base.SuffrageProof, bool, error) {
+			return nil, false, errors.Errorf("hehehe")
+		}, base.DefaultThreshold)
+
+		rc, err := f()
+		t.Error(err)
+		t.ErrorContains(err, "hehehe")
+		t.Nil(rc)
+	})
+
+	t.Run("empty design", func() {
+		f := syncerCheckpointFunc(nil, notfoundf, base.DefaultThreshold)
+
+		rc, err := f()
+		t.NoError(err)
+		t.Nil(rc)
+	})
+}
+
+func TestCheckpointDesign(t *testing.T) {
+	suite.Run(t, new(testCheckpointDesign))
+}
@@ -0,0 +1,66 @@
+package launchcmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/ProtoconNet/mitum2/launch"
+	"github.com/pkg/errors"
+)
+
+type DebugCommands struct {
+	//revive:disable:line-length-limit
+	Timeline DebugTimelineCommand `cmd:"" name:"timeline" help:"render consensus timeline of height from timeline event dumps"`
+	//revive:enable:line-length-limit
+}
+
+type DebugTimelineCommand struct { //nolint:govet //...
+	BaseCommand
+	Height launch.HeightFlag `arg:"" help:"height"`
+	Dumps  []string          `arg:"" name:"dump" help:"timeline event dump files of nodes; '-' for stdin"`
+}
+
+func (cmd *DebugTimelineCommand) Run(pctx context.Context) error {
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	if !cmd.Height.IsSet() {
+		return errors.Errorf("empty height")
+	}
+
+	cmd.Log.Debug().
+		Interface("height", cmd.Height.Height()).
+		Strs("dumps", cmd.Dumps).
+		Msg("flags")
+
+	var records []launch.TimelineRecord
+
+	for i := range cmd.Dumps {
+		rs, err := cmd.load(cmd.Dumps[i])
+		if err != nil {
+			return errors.WithMessagef(err, "dump, %q", cmd.Dumps[i])
+		}
+
+		records = append(records, rs...)
+	}
+
+	return launch.RenderTimeline(os.Stdout, cmd.Height.Height(), records)
+}
+
+func (*DebugTimelineCommand) load(p string) ([]launch.TimelineRecord, error) {
+	if p == "-" {
+		return launch.LoadTimelineRecords(os.Stdin)
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	return launch.LoadTimelineRecords(f)
+}
@@ -29,7 +29,8 @@ var (
 type ImportCommand struct { //nolint:govet //...
 	// revive:disable:line-length-limit
 	launch.DesignFlag
-	Source      string           `arg:"" name:"source directory" help:"block data directory to import" type:"existingdir"`
+	Source      string           `arg:"" name:"source directory" help:"block data directory to import" type:"existingdir" optional:""`
+	Bundle      string           `name:"bundle" help:"block bundle file to import" type:"existingfile"`
 	HeightRange launch.RangeFlag `name:"range" help:"<from>-<to>" default:""`
 	launch.PrivatekeyFlags
 	Do              bool   `name:"do" help:"really do import"`
@@ -43,6 +44,8 @@ type ImportCommand struct { //nolint:govet //...
 	sourceReaders   *isaac.BlockItemReaders
 	toReaders       *isaac.BlockItemReaders
 	importedReaders *isaac.BlockItemReaders
+	bundleDirectory string
+	bundle          launch.BlockBundleManifest
 	// revive:enable:line-length-limit
 }
 
@@ -67,6 +70,7 @@ func (cmd *ImportCommand) Run(pctx context.Context) error {
 		Interface("privatekey", cmd.PrivatekeyFlags).
 		Interface("dev", cmd.DevFlags).
 		Str("source", cmd.Source).
+		Str("bundle", cmd.Bundle).
 		Interface("from_height", cmd.fromHeight).
 		Interface("to_height", cmd.toHeight).
 		Bool("do", cmd.Do).
@@ -155,7 +159,117 @@ func (cmd *ImportCommand) prepare() error {
 		}
 	}
 
-	return checkCacheDirectory()
+	switch {
+	case len(cmd.Bundle) > 0 && len(cmd.Source) > 0:
+		return errors.Errorf("source directory and bundle both given")
+	case len(cmd.Bundle) < 1 && len(cmd.Source) < 1:
+		return errors.Errorf("empty source directory")
+	}
+
+	if err := checkCacheDirectory(); err != nil {
+		return err
+	}
+
+	if len(cmd.Bundle) > 0 {
+		if err := cmd.extractBundle(); err != nil {
+			_ = os.RemoveAll(cmd.CacheDirectory)
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cmd *ImportCommand) extractBundle() error {
+	e := util.StringError("extract bundle")
+
+	f, err := os.Open(filepath.Clean(cmd.Bundle))
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	cmd.bundleDirectory = filepath.Join(cmd.CacheDirectory, "bundle")
+
+	switch i, err := launch.ExtractBlockBundle(f, cmd.bundleDirectory); {
+	case err != nil:
+		return e.Wrap(err)
+	default:
+		cmd.bundle = i
+		cmd.Source = launch.BlockBundleDataDirectory(cmd.bundleDirectory)
+	}
+
+	cmd.log.Debug().
+		Interface("from", cmd.bundle.From).
+		Interface("to", cmd.bundle.To).
+		Int("files", len(cmd.bundle.Files)).
+		Msg("bundle extracted")
+
+	return nil
+}
+
+// verifyBundle verifies the block maps of bundle with the suffrage proofs of
+// bundle; the suffrage proofs are proved from the suffrage proof of local
+// storage.
+func (cmd *ImportCommand) verifyBundle(pctx context.Context) error {
+	e := util.StringError("verify bundle")
+
+	var encs *encoder.Encoders
+	var isaacparams *isaac.Params
+	var db isaac.Database
+
+	if err := util.LoadFromContextOK(pctx,
+		launch.EncodersContextKey, &encs,
+		launch.ISAACParamsContextKey, &isaacparams,
+		launch.CenterDatabaseContextKey, &db,
+	); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := cmd.bundle.IsValid(isaacparams.NetworkID()); err != nil {
+		return e.Wrap(err)
+	}
+
+	if cmd.fromHeight < cmd.bundle.From {
+		return e.Errorf("bundle starts from %d, but from height is %d", cmd.bundle.From, cmd.fromHeight)
+	}
+
+	var trusted base.SuffrageProof
+
+	if cmd.fromHeight > base.GenesisHeight {
+		switch i, found, err := db.SuffrageProofByBlockHeight(cmd.fromHeight - 1); {
+		case err != nil:
+			return e.Wrap(err)
+		case !found:
+			return e.Errorf("suffrage proof not found for height, %d", cmd.fromHeight-1)
+		default:
+			trusted = i
+		}
+	}
+
+	proofs, err := launch.LoadBlockBundleSuffrageProofs(cmd.bundleDirectory, cmd.bundle, encs.JSON())
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := launch.VerifyBlockBundle(
+		isaacparams.NetworkID(),
+		trusted,
+		proofs,
+		cmd.sourceReaders.Item,
+		cmd.fromHeight,
+		cmd.lastHeight,
+	); err != nil {
+		return e.Wrap(err)
+	}
+
+	cmd.log.Debug().Msg("bundle verified")
+
+	return nil
 }
 
 func (cmd *ImportCommand) preImportBlocks(pctx context.Context) (context.Context, error) {
@@ -214,6 +328,12 @@ func (cmd *ImportCommand) preImportBlocks(pctx context.Context) (context.Context
 		cmd.lastHeight = i
 	}
 
+	if len(cmd.Bundle) > 0 {
+		if err := cmd.verifyBundle(pctx); err != nil {
+			return pctx, err
+		}
+	}
+
 	if err := cmd.validateSourceBlocks(
 		cmd.loadItemFile(cmd.sourceReaders, fromRemotes, cmd.Do),
 		cmd.lastHeight,
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 
@@ -18,7 +19,8 @@ import (
 
 type KeyNewCommand struct {
 	BaseCommand
-	Seed string `arg:"" name:"seed" optional:"" help:"seed for generating key"`
+	Seed    string `arg:"" name:"seed" optional:"" help:"seed for generating key"`
+	KeyType string `name:"type" enum:"secp256k1,ed25519,bls" default:"secp256k1" help:"key type; secp256k1, ed25519 or bls"`
 }
 
 func (cmd *KeyNewCommand) Run(pctx context.Context) error {
@@ -28,6 +30,7 @@ func (cmd *KeyNewCommand) Run(pctx context.Context) error {
 
 	cmd.Log.Debug().
 		Str("seed", cmd.Seed).
+		Str("type", cmd.KeyType).
 		Msg("flags")
 
 	if _, err := cmd.prepare(pctx); err != nil {
@@ -42,12 +45,16 @@ func (cmd *KeyNewCommand) Run(pctx context.Context) error {
 			cmd.Log.Warn().Msg("seed consists with empty spaces")
 		}
 
-		i, err := base.NewMPrivatekeyFromSeed(cmd.Seed)
+		i, err := cmd.newPrivatekeyFromSeed()
 		if err != nil {
 			return err
 		}
 
 		key = i
+	case cmd.KeyType == "ed25519":
+		key = base.NewEd25519Privatekey()
+	case cmd.KeyType == "bls":
+		key = base.NewBLSPrivatekey()
 	default:
 		key = base.NewMPrivatekey()
 	}
@@ -79,6 +86,17 @@ func (cmd *KeyNewCommand) Run(pctx context.Context) error {
 	return nil
 }
 
+func (cmd *KeyNewCommand) newPrivatekeyFromSeed() (base.Privatekey, error) {
+	switch cmd.KeyType {
+	case "ed25519":
+		return base.NewEd25519PrivatekeyFromSeed(cmd.Seed)
+	case "bls":
+		return base.NewBLSPrivatekeyFromSeed(cmd.Seed)
+	default:
+		return base.NewMPrivatekeyFromSeed(cmd.Seed)
+	}
+}
+
 type KeyLoadCommand struct {
 	BaseCommand
 	KeyString string `arg:"" name:"key string" help:"key string"`
@@ -372,3 +390,123 @@ func (cmd *KeySignCommand) sign(ptr interface{}) error {
 
 	return nil
 }
+
+type KeyExportCommand struct { //nolint:govet //...
+	BaseCommand
+	launch.PrivatekeyArgument
+	Keystore       string `name:"keystore" help:"keystore file to write; if empty, print to stdout"`
+	PassphraseFile string `name:"passphrase-file" help:"passphrase file"`
+}
+
+func (cmd *KeyExportCommand) Run(pctx context.Context) error {
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	cmd.Log.Debug().
+		Str("keystore", cmd.Keystore).
+		Str("passphrase_file", cmd.PassphraseFile).
+		Msg("flags")
+
+	priv, err := launch.DecodePrivatekey(strings.TrimSpace(string(cmd.PrivatekeyArgument.Flag.Body())), cmd.JSONEncoder)
+	if err != nil {
+		return err
+	}
+
+	passphrase, err := launch.ReadKeystorePassphrase(cmd.PassphraseFile, true)
+	if err != nil {
+		return err
+	}
+
+	defer clear(passphrase)
+
+	b, err := launch.EncryptKeystore(priv, passphrase)
+	if err != nil {
+		return err
+	}
+
+	if len(cmd.Keystore) < 1 {
+		_, _ = fmt.Fprintln(os.Stdout, string(b))
+
+		return nil
+	}
+
+	if err := os.WriteFile(filepath.Clean(cmd.Keystore), b, 0o600); err != nil {
+		return errors.WithStack(err)
+	}
+
+	cmd.Log.Debug().Str("keystore", cmd.Keystore).Msg("keystore exported")
+
+	return nil
+}
+
+type KeyImportCommand struct {
+	BaseCommand
+	Keystore       string `arg:"" name:"keystore" help:"keystore file"`
+	PassphraseFile string `name:"passphrase-file" help:"passphrase file"`
+	Out            string `name:"out" help:"file to write decrypted privatekey; privatekey is not printed"`
+}
+
+func (cmd *KeyImportCommand) Run(pctx context.Context) error {
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	cmd.Log.Debug().
+		Str("keystore", cmd.Keystore).
+		Str("passphrase_file", cmd.PassphraseFile).
+		Str("out", cmd.Out).
+		Msg("flags")
+
+	body, err := os.ReadFile(filepath.Clean(cmd.Keystore))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	passphrase, err := launch.ReadKeystorePassphrase(cmd.PassphraseFile, false)
+	if err != nil {
+		return err
+	}
+
+	defer clear(passphrase)
+
+	key, err := launch.DecryptKeystore(body, passphrase, cmd.JSONEncoder)
+	if err != nil {
+		return err
+	}
+
+	if len(cmd.Out) > 0 {
+		if err := os.WriteFile(filepath.Clean(cmd.Out), []byte(key.String()), 0o600); err != nil {
+			return errors.WithStack(err)
+		}
+
+		cmd.Log.Debug().Str("out", cmd.Out).Msg("privatekey imported")
+	}
+
+	// NOTE the decrypted privatekey is not printed
+	o := struct {
+		Publickey base.PKKey  `json:"publickey"`
+		Hint      interface{} `json:"hint,omitempty"`
+		Keystore  string      `json:"keystore"`
+		Out       string      `json:"out,omitempty"`
+		Type      string      `json:"type"`
+	}{
+		Keystore:  cmd.Keystore,
+		Publickey: key.Publickey(),
+		Out:       cmd.Out,
+		Type:      "privatekey",
+	}
+
+	if hinter, ok := key.(hint.Hinter); ok {
+		o.Hint = hinter.Hint()
+	}
+
+	b, err := util.MarshalJSONIndent(o)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintln(os.Stdout, string(b))
+
+	return nil
+}
@@ -25,6 +25,7 @@ type NetworkClientCommand struct { //nolint:govet //...
 	NodeInfo       NetworkClientNodeInfoCommand       `cmd:"" name:"node-info" help:"remote node info"`
 	SendOperation  NetworkClientSendOperationCommand  `cmd:"" name:"send-operation" help:"send operation"`
 	State          NetworkClientStateCommand          `cmd:"" name:"state" help:"get state"`
+	ProveOperation NetworkClientProveOperationCommand `cmd:"" name:"prove-operation" help:"get and verify operation inclusion proof"`
 	LastBlockMap   NetworkClientLastBlockMapCommand   `cmd:"" name:"last-blockmap" help:"get last blockmap"`
 	BlockItemFiles NetworkClientBlockItemFilesCommand `cmd:"" name:"block-item-files" help:"download block item files"`
 	BlockItemFile  NetworkClientBlockItemFileCommand  `cmd:"" name:"block-item-file" help:"download block item file"`
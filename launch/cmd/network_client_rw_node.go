@@ -224,6 +224,7 @@ func (*NetworkClientWriteNodeCommand) helpACL() string {
 		},
 		launch.HandoverACLScope:     {launch.WriteAllowACLPerm.String()},
 		launch.EventLoggingACLScope: {launch.ReadAllowACLPerm.String()},
+		launch.NetworkStatsACLScope: {launch.ReadAllowACLPerm.String()},
 	}
 
 	for i := range launch.AllACLScopes {
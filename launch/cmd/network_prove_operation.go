@@ -0,0 +1,121 @@
+package launchcmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ProtoconNet/mitum2/base"
+	isaaclightclient "github.com/ProtoconNet/mitum2/isaac/lightclient"
+	"github.com/ProtoconNet/mitum2/launch"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/ProtoconNet/mitum2/util/valuehash"
+	"github.com/pkg/errors"
+)
+
+// NetworkClientProveOperationCommand gets the inclusion proof of operation from
+// remote and verifies it without trusting remote. The ACCEPT voteproof is
+// verified with the trusted suffrage proofs; one of them should be the suffrage
+// of block manifest. If the suffrage was updated at the height of block, the
+// previous suffrage proof is also needed. The result of operation, which is
+// not stored in the operations tree, can not be proved.
+type NetworkClientProveOperationCommand struct { //nolint:govet //...
+	BaseNetworkClientCommand
+	Fact           string            `arg:"" name:"fact hash" help:"operation fact hash"`
+	Height         launch.HeightFlag `arg:"" help:"block height, which the operation was processed in"`
+	SuffrageProofs []string          `name:"suffrage-proof" help:"trusted suffrage proof files" type:"existingfile" required:""` //nolint:lll //...
+	Threshold      float64           `name:"threshold" help:"threshold" default:"67"`
+}
+
+func (cmd *NetworkClientProveOperationCommand) Run(pctx context.Context) error {
+	if err := cmd.Prepare(pctx); err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = cmd.Client.Close()
+	}()
+
+	if len(strings.TrimSpace(cmd.Fact)) < 1 {
+		return errors.Errorf("empty fact hash")
+	}
+
+	facthash := valuehash.NewBytesFromString(cmd.Fact)
+
+	height := cmd.Height.Height()
+	if err := height.IsValid(nil); err != nil {
+		return err
+	}
+
+	threshold := base.Threshold(cmd.Threshold)
+	if err := threshold.IsValid(nil); err != nil {
+		return err
+	}
+
+	proofs, err := cmd.loadSuffrageProofs()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(pctx, cmd.Timeout)
+	defer cancel()
+
+	var op isaaclightclient.OperationProof
+
+	switch i, found, err := cmd.Client.OperationProof(ctx, cmd.Remote.ConnInfo(), facthash, height); {
+	case err != nil:
+		cmd.Log.Error().Err(err).Msg("failed to get operation proof")
+
+		return err
+	case !found:
+		cmd.Log.Error().Msg("not found")
+
+		return nil
+	default:
+		op = i
+	}
+
+	if !op.Node().Operation().Equal(facthash) {
+		return errors.Errorf("operation fact hash does not match")
+	}
+
+	if err := isaaclightclient.VerifyOperationProof(
+		base.NetworkID(cmd.NetworkID), threshold, proofs, op); err != nil {
+		cmd.Log.Error().Err(err).Msg("failed to verify operation proof")
+
+		return err
+	}
+
+	cmd.Log.Info().
+		Stringer("fact", facthash).
+		Interface("height", height).
+		Bool("in_state", op.Node().InState()).
+		Msg("operation proved")
+
+	return cmd.Print(op, os.Stdout)
+}
+
+func (cmd *NetworkClientProveOperationCommand) loadSuffrageProofs() ([]base.SuffrageProof, error) {
+	e := util.StringError("load suffrage proofs")
+
+	proofs := make([]base.SuffrageProof, len(cmd.SuffrageProofs))
+
+	for i := range cmd.SuffrageProofs {
+		b, err := os.ReadFile(filepath.Clean(cmd.SuffrageProofs[i]))
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		if err := encoder.Decode(cmd.JSONEncoder, b, &proofs[i]); err != nil {
+			return nil, e.WithMessage(err, "file, %q", cmd.SuffrageProofs[i])
+		}
+
+		if err := proofs[i].IsValid(base.NetworkID(cmd.NetworkID)); err != nil {
+			return nil, e.WithMessage(err, "file, %q", cmd.SuffrageProofs[i])
+		}
+	}
+
+	return proofs, nil
+}
@@ -26,13 +26,18 @@ type RunCommand struct { //nolint:govet //...
 	launch.DesignFlag
 	launch.DevFlags `embed:"" prefix:"dev."`
 	launch.PrivatekeyFlags
-	Discovery []launch.ConnInfoFlag `help:"member discovery" placeholder:"connection info"`
-	Hold      launch.HeightFlag     `help:"hold consensus states" placeholder:"height"`
-	HTTPState string                `name:"http-state" help:"runtime statistics thru https" placeholder:"bind address"`
+	Discovery      []launch.ConnInfoFlag `help:"member discovery" placeholder:"connection info"`
+	Hold           launch.HeightFlag     `help:"hold consensus states" placeholder:"height"`
+	HTTPState      string                `name:"http-state" help:"runtime statistics thru https" placeholder:"bind address"`
+	HTTPBlockItems string                `name:"http-block-items" help:"serve block item files thru http" placeholder:"bind address"`
+	Tracing        string                `name:"tracing" help:"export tracing spans to file or collector url" placeholder:"exporter"`
 	launch.ACLFlags
-	exitf  func(error)
-	log    *zerolog.Logger
-	holded bool
+	exitf      func(error)
+	log        *zerolog.Logger
+	instrument *quicstream.InstrumentStats
+	metrics    *launch.Metrics
+	health     *launch.Health
+	holded     bool
 	//revive:enable:line-length-limit
 }
 
@@ -48,11 +53,16 @@ func (cmd *RunCommand) Run(pctx context.Context) error {
 		Interface("discovery", cmd.Discovery).
 		Interface("hold", cmd.Hold).
 		Interface("http_state", cmd.HTTPState).
+		Interface("http_block_items", cmd.HTTPBlockItems).
+		Interface("tracing", cmd.Tracing).
 		Interface("dev", cmd.DevFlags).
 		Interface("acl", cmd.ACLFlags).
 		Msg("flags")
 
 	cmd.log = log.Log()
+	cmd.instrument = launch.NewQuicstreamInstrument()
+	cmd.metrics = launch.NewMetrics()
+	cmd.health = launch.NewHealth()
 
 	if len(cmd.HTTPState) > 0 {
 		if err := cmd.runHTTPState(cmd.HTTPState); err != nil {
@@ -61,11 +71,15 @@ func (cmd *RunCommand) Run(pctx context.Context) error {
 	}
 
 	nctx := util.ContextWithValues(pctx, map[util.ContextKey]interface{}{
-		launch.DesignFlagContextKey:    cmd.DesignFlag,
-		launch.DevFlagsContextKey:      cmd.DevFlags,
-		launch.DiscoveryFlagContextKey: cmd.Discovery,
-		launch.PrivatekeyContextKey:    string(cmd.PrivatekeyFlags.Flag.Body()),
-		launch.ACLFlagsContextKey:      cmd.ACLFlags,
+		launch.DesignFlagContextKey:           cmd.DesignFlag,
+		launch.DevFlagsContextKey:             cmd.DevFlags,
+		launch.DiscoveryFlagContextKey:        cmd.Discovery,
+		launch.PrivatekeyContextKey:           string(cmd.PrivatekeyFlags.Flag.Body()),
+		launch.ACLFlagsContextKey:             cmd.ACLFlags,
+		launch.QuicstreamInstrumentContextKey: cmd.instrument,
+		launch.MetricsContextKey:              cmd.metrics,
+		launch.HealthContextKey:               cmd.health,
+		launch.TracingFlagContextKey:          cmd.Tracing,
 	})
 
 	pps := launch.DefaultRunPS()
@@ -92,6 +106,12 @@ func (cmd *RunCommand) Run(pctx context.Context) error {
 		return err
 	}
 
+	if len(cmd.HTTPBlockItems) > 0 {
+		if err := cmd.runHTTPBlockItems(nctx, cmd.HTTPBlockItems); err != nil {
+			return errors.Wrap(err, "run http block items")
+		}
+	}
+
 	log.Log().Debug().
 		Interface("discovery", cmd.Discovery).
 		Interface("hold", cmd.Hold.Height()).
@@ -246,6 +266,11 @@ func (cmd *RunCommand) runHTTPState(bind string) error {
 		return errors.Wrap(err, "register statsviz for http-state")
 	}
 
+	mux.HandleFunc("/quicstream", cmd.handleHTTPStateQuicstream)
+	mux.Handle("/metrics", cmd.metrics)
+	mux.HandleFunc("/healthz", cmd.health.ServeLiveness)
+	mux.HandleFunc("/readyz", cmd.health.ServeReadiness)
+
 	cmd.log.Debug().Stringer("bind", addr).Msg("statsviz started")
 
 	go func() {
@@ -254,3 +279,36 @@ func (cmd *RunCommand) runHTTPState(bind string) error {
 
 	return nil
 }
+
+func (cmd *RunCommand) runHTTPBlockItems(pctx context.Context, bind string) error {
+	addr, err := net.ResolveTCPAddr("tcp", bind)
+	if err != nil {
+		return errors.Wrap(err, "parse --http-block-items")
+	}
+
+	var readers *isaac.BlockItemReaders
+	if err := util.LoadFromContextOK(pctx, launch.BlockItemReadersContextKey, &readers); err != nil {
+		return err
+	}
+
+	cmd.log.Debug().Stringer("bind", addr).Msg("http block items started")
+
+	go func() {
+		_ = http.ListenAndServe(addr.String(), isaac.BlockItemReadersHTTPHandler(readers))
+	}()
+
+	return nil
+}
+
+func (cmd *RunCommand) handleHTTPStateQuicstream(w http.ResponseWriter, _ *http.Request) {
+	b, err := util.MarshalJSON(cmd.instrument.Snapshot())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	_, _ = w.Write(b)
+}
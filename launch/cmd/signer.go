@@ -0,0 +1,95 @@
+package launchcmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/launch"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/pkg/errors"
+)
+
+type SignerCommand struct { //nolint:govet //...
+	BaseCommand
+	launch.PrivatekeyArgument
+	Node      launch.AddressFlag `arg:"" name:"node" help:"node address"`
+	NetworkID string             `arg:"" name:"network-id" help:"network-id"`
+	Address   string             `name:"address" default:"unix:///tmp/mitum-signer.sock" help:"signer address; 'unix:///tmp/signer.sock' or 'tcp://127.0.0.1:4322'"` //nolint:lll //...
+	State     string             `name:"state" help:"state file to keep signed ballots"`
+	TokenFile string             `name:"token-file" help:"token file to authenticate requests; required for tcp"`
+	AllowRaw  bool               `name:"allow-raw" help:"allow to sign bytes without sign kind; ballot sign bytes are still refused"`
+}
+
+func (cmd *SignerCommand) Run(pctx context.Context) error {
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	cmd.Log.Debug().
+		Interface("node", cmd.Node.Address()).
+		Str("network_id", cmd.NetworkID).
+		Str("address", cmd.Address).
+		Str("state", cmd.State).
+		Str("token_file", cmd.TokenFile).
+		Bool("allow_raw", cmd.AllowRaw).
+		Msg("flags")
+
+	priv, err := launch.DecodePrivatekey(strings.TrimSpace(string(cmd.PrivatekeyArgument.Flag.Body())), cmd.JSONEncoder)
+	if err != nil {
+		return err
+	}
+
+	var token string
+
+	if len(cmd.TokenFile) > 0 {
+		i, err := launch.LoadSignerToken(cmd.TokenFile)
+		if err != nil {
+			return err
+		}
+
+		token = i
+	}
+
+	signer, err := launch.NewLocalSigner(launch.LocalSignerArgs{
+		Privatekey: priv,
+		Node:       cmd.Node.Address(),
+		NetworkID:  base.NetworkID(cmd.NetworkID),
+		Encoder:    cmd.JSONEncoder,
+		Address:    cmd.Address,
+		StateFile:  cmd.State,
+		Token:      token,
+		AllowRaw:   cmd.AllowRaw,
+	})
+	if err != nil {
+		return err
+	}
+
+	var log *logging.Logging
+	if err := util.LoadFromContextOK(pctx, launch.LoggingContextKey, &log); err != nil {
+		return err
+	}
+
+	_ = signer.SetLogging(log)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := signer.Start(ctx); err != nil {
+		return err
+	}
+
+	cmd.Log.Info().Str("address", cmd.Address).Interface("publickey", priv.Publickey()).Msg("signer started")
+
+	<-ctx.Done()
+
+	if err := signer.Stop(); err != nil {
+		return err
+	}
+
+	return errors.WithStack(ctx.Err())
+}
@@ -1,11 +1,14 @@
 package launchcmd
 
 type StorageCommand struct { //nolint:govet //...
-	Import         ImportCommand         `cmd:"" help:"import block data files"`
-	Clean          CleanCommand          `cmd:"" help:"clean storage"`
-	ValidateBlocks ValidateBlocksCommand `cmd:"" help:"validate blocks in storage"`
-	Status         StorageStatusCommand  `cmd:"" help:"storage status"`
-	Database       DatabaseCommand       `cmd:"" help:""`
+	Import         ImportCommand               `cmd:"" help:"import block data files"`
+	Clean          CleanCommand                `cmd:"" help:"clean storage"`
+	ValidateBlocks ValidateBlocksCommand       `cmd:"" help:"validate blocks in storage"`
+	Status         StorageStatusCommand        `cmd:"" help:"storage status"`
+	Database       DatabaseCommand             `cmd:"" help:""`
+	Checkpoint     StorageCheckpointCommand    `cmd:"" help:"trusted checkpoint for syncing"`
+	Export         StorageExportCommand        `cmd:"" help:"export blocks into bundle file"`
+	PublishMirror  StoragePublishMirrorCommand `cmd:"" name:"publish-mirror" help:"publish blocks into static block mirror"`
 }
 
 type DatabaseCommand struct {
@@ -0,0 +1,293 @@
+package launchcmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	"github.com/ProtoconNet/mitum2/launch"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/ProtoconNet/mitum2/util/ps"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+var PNameStorageCheckpointSign = ps.Name("storage-checkpoint-sign")
+
+type StorageCheckpointCommand struct {
+	Sign   StorageCheckpointSignCommand   `cmd:"" help:"make checkpoint from local storage and sign it"`
+	Verify StorageCheckpointVerifyCommand `cmd:"" help:"verify checkpoint file"`
+}
+
+// StorageCheckpointSignCommand makes the checkpoint of the block in local
+// storage and signs it by local node. If the checkpoint file is given, the
+// sign of local node is added to it; the checkpoint should match with the
+// block of local storage.
+type StorageCheckpointSignCommand struct { //nolint:govet //...
+	launch.DesignFlag
+	launch.PrivatekeyFlags
+	Height          launch.HeightFlag `arg:"" optional:"" help:"block height; if empty, last block is used"`
+	Checkpoint      string            `name:"checkpoint" help:"checkpoint file to add sign" type:"existingfile"`
+	Out             string            `name:"out" help:"checkpoint file to write; if empty, print to stdout"`
+	log             *zerolog.Logger
+	launch.DevFlags `embed:"" prefix:"dev."`
+}
+
+func (cmd *StorageCheckpointSignCommand) Run(pctx context.Context) error {
+	var log *logging.Logging
+	if err := util.LoadFromContextOK(pctx, launch.LoggingContextKey, &log); err != nil {
+		return err
+	}
+
+	log.Log().Debug().
+		Interface("design", cmd.DesignFlag).
+		Interface("privatekey", cmd.PrivatekeyFlags).
+		Interface("dev", cmd.DevFlags).
+		Interface("height", cmd.Height).
+		Str("checkpoint", cmd.Checkpoint).
+		Str("out", cmd.Out).
+		Msg("flags")
+
+	cmd.log = log.Log()
+
+	pps := ps.NewPS("cmd-storage-checkpoint-sign")
+	_ = pps.SetLogging(log)
+
+	_ = pps.
+		AddOK(launch.PNameEncoder, launch.PEncoder, nil).
+		AddOK(launch.PNameDesign, launch.PLoadDesign, nil, launch.PNameEncoder).
+		AddOK(launch.PNameLocal, launch.PLocal, nil, launch.PNameDesign).
+		AddOK(launch.PNameBlockItemReaders, launch.PBlockItemReaders, nil, launch.PNameDesign).
+		AddOK(launch.PNameStorage, launch.PStorage, launch.PCloseStorage, launch.PNameLocal)
+
+	_ = pps.POK(launch.PNameEncoder).
+		PostAddOK(launch.PNameAddHinters, launch.PAddHinters)
+
+	_ = pps.POK(launch.PNameDesign).
+		PostAddOK(launch.PNameCheckDesign, launch.PCheckDesign)
+
+	_ = pps.POK(launch.PNameBlockItemReaders).
+		PreAddOK(launch.PNameBlockItemReadersDecompressFunc, launch.PBlockItemReadersDecompressFunc).
+		PostAddOK(launch.PNameRemotesBlockItemReaderFunc, launch.PRemotesBlockItemReaderFunc)
+
+	_ = pps.POK(launch.PNameStorage).
+		PreAddOK(launch.PNameCheckLocalFS, launch.PCheckLocalFS).
+		PreAddOK(launch.PNameLoadDatabase, launch.PLoadDatabase).
+		PostAddOK(launch.PNameCheckLeveldbStorage, launch.PCheckLeveldbStorage).
+		PostAddOK(PNameStorageCheckpointSign, cmd.pSign)
+
+	nctx := util.ContextWithValues(pctx, map[util.ContextKey]interface{}{
+		launch.DesignFlagContextKey: cmd.DesignFlag,
+		launch.DevFlagsContextKey:   cmd.DevFlags,
+		launch.PrivatekeyContextKey: string(cmd.PrivatekeyFlags.Flag.Body()),
+	})
+
+	cmd.log.Debug().Interface("process", pps.Verbose()).Msg("process ready")
+
+	nctx, err := pps.Run(nctx)
+	defer func() {
+		cmd.log.Debug().Interface("process", pps.Verbose()).Msg("process will be closed")
+
+		if _, err = pps.Close(nctx); err != nil {
+			cmd.log.Error().Err(err).Msg("failed to close")
+		}
+	}()
+
+	return err
+}
+
+func (cmd *StorageCheckpointSignCommand) pSign(pctx context.Context) (context.Context, error) {
+	e := util.StringError("sign checkpoint")
+
+	var encs *encoder.Encoders
+	var local base.LocalNode
+	var isaacparams *isaac.Params
+	var db isaac.Database
+
+	if err := util.LoadFromContextOK(pctx,
+		launch.EncodersContextKey, &encs,
+		launch.LocalContextKey, &local,
+		launch.ISAACParamsContextKey, &isaacparams,
+		launch.CenterDatabaseContextKey, &db,
+	); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	m, err := cmd.blockMap(db)
+	if err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	c := isaac.NewCheckpoint(m.Manifest().Height(), m.Manifest().Hash())
+
+	if len(cmd.Checkpoint) > 0 {
+		switch i, err := launch.LoadCheckpointFile(cmd.Checkpoint, encs.JSON()); {
+		case err != nil:
+			return pctx, e.Wrap(err)
+		default:
+			if err := i.IsValid(isaacparams.NetworkID()); err != nil {
+				return pctx, e.Wrap(err)
+			}
+
+			if err := i.IsValidBlockMap(m); err != nil {
+				return pctx, e.WithMessage(err, "checkpoint does not match with local block")
+			}
+
+			c = i
+		}
+	}
+
+	if err := c.Sign(local.Address(), local.Privatekey(), isaacparams.NetworkID()); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	if err := c.IsValid(isaacparams.NetworkID()); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	cmd.log.Info().
+		Interface("height", c.Height()).
+		Interface("manifest", c.Manifest()).
+		Int("signs", len(c.Signs())).
+		Msg("checkpoint signed")
+
+	return pctx, writeCheckpoint(c, cmd.Out)
+}
+
+func (cmd *StorageCheckpointSignCommand) blockMap(db isaac.Database) (base.BlockMap, error) {
+	if cmd.Height.IsSet() && cmd.Height.Height() > base.NilHeight {
+		switch m, found, err := db.BlockMap(cmd.Height.Height()); {
+		case err != nil:
+			return nil, err
+		case !found:
+			return nil, util.ErrNotFound.Errorf("blockmap, %d", cmd.Height.Height())
+		default:
+			return m, nil
+		}
+	}
+
+	switch m, found, err := db.LastBlockMap(); {
+	case err != nil:
+		return nil, err
+	case !found:
+		return nil, util.ErrNotFound.Errorf("last blockmap")
+	default:
+		return m, nil
+	}
+}
+
+// StorageCheckpointVerifyCommand verifies the signs of checkpoint file. If the
+// suffrage proof file is given, the checkpoint should be signed by the
+// suffrage of it over threshold.
+type StorageCheckpointVerifyCommand struct { //nolint:govet //...
+	BaseCommand
+	Checkpoint    string  `arg:"" name:"checkpoint" help:"checkpoint file" type:"existingfile"`
+	NetworkID     string  `name:"network-id" help:"network-id" required:""`
+	SuffrageProof string  `name:"suffrage-proof" help:"last suffrage proof file" type:"existingfile"`
+	Threshold     float64 `name:"threshold" help:"threshold" default:"67"`
+}
+
+func (cmd *StorageCheckpointVerifyCommand) Run(pctx context.Context) error {
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	cmd.Log.Debug().
+		Str("checkpoint", cmd.Checkpoint).
+		Str("network_id", cmd.NetworkID).
+		Str("suffrage_proof", cmd.SuffrageProof).
+		Float64("threshold", cmd.Threshold).
+		Msg("flags")
+
+	networkID := base.NetworkID(cmd.NetworkID)
+
+	threshold := base.Threshold(cmd.Threshold)
+	if err := threshold.IsValid(nil); err != nil {
+		return err
+	}
+
+	c, err := launch.LoadCheckpointFile(cmd.Checkpoint, cmd.JSONEncoder)
+	if err != nil {
+		return err
+	}
+
+	if err := c.IsValid(networkID); err != nil {
+		return err
+	}
+
+	switch {
+	case len(cmd.SuffrageProof) > 0:
+		proof, err := cmd.loadSuffrageProof(networkID)
+		if err != nil {
+			return err
+		}
+
+		if err := launch.IsCheckpointSignedByLastSuffrage(c, proof, threshold); err != nil {
+			return err
+		}
+	case len(c.Signs()) < 1:
+		cmd.Log.Warn().Msg("checkpoint not signed")
+	default:
+		cmd.Log.Warn().Msg("signs not verified with suffrage; suffrage proof not given")
+	}
+
+	cmd.Log.Info().
+		Interface("height", c.Height()).
+		Interface("manifest", c.Manifest()).
+		Int("signs", len(c.Signs())).
+		Msg("checkpoint verified")
+
+	return nil
+}
+
+func (cmd *StorageCheckpointVerifyCommand) loadSuffrageProof(networkID base.NetworkID) (base.SuffrageProof, error) {
+	e := util.StringError("load suffrage proof")
+
+	b, err := os.ReadFile(filepath.Clean(cmd.SuffrageProof))
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	var proof base.SuffrageProof
+	if err := encoder.Decode(cmd.JSONEncoder, b, &proof); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	if err := proof.IsValid(networkID); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	return proof, nil
+}
+
+func writeCheckpoint(c isaac.Checkpoint, f string) error {
+	b, err := util.MarshalJSONIndent(c)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+
+	if len(f) > 0 {
+		i, err := os.OpenFile(filepath.Clean(f), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		defer func() {
+			_ = i.Close()
+		}()
+
+		out = i
+	}
+
+	_, err = fmt.Fprintln(out, string(b))
+
+	return errors.WithStack(err)
+}
@@ -0,0 +1,180 @@
+package launchcmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	"github.com/ProtoconNet/mitum2/launch"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/ProtoconNet/mitum2/util/ps"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+var PNameStorageExport = ps.Name("storage-export")
+
+// StorageExportCommand writes the block bundle of heights from local storage.
+// The bundle can be imported by `storage import --bundle` without network.
+type StorageExportCommand struct { //nolint:govet //...
+	launch.DesignFlag
+	launch.PrivatekeyFlags
+	From            launch.HeightFlag `name:"from" help:"from height" required:""`
+	To              launch.HeightFlag `name:"to" help:"to height; if empty, last block is used"`
+	Out             string            `name:"out" help:"bundle file to write" required:""`
+	log             *zerolog.Logger
+	launch.DevFlags `embed:"" prefix:"dev."`
+}
+
+func (cmd *StorageExportCommand) Run(pctx context.Context) error {
+	var log *logging.Logging
+	if err := util.LoadFromContextOK(pctx, launch.LoggingContextKey, &log); err != nil {
+		return err
+	}
+
+	log.Log().Debug().
+		Interface("design", cmd.DesignFlag).
+		Interface("privatekey", cmd.PrivatekeyFlags).
+		Interface("dev", cmd.DevFlags).
+		Interface("from", cmd.From).
+		Interface("to", cmd.To).
+		Str("out", cmd.Out).
+		Msg("flags")
+
+	cmd.log = log.Log()
+
+	switch _, err := os.Stat(cmd.Out); {
+	case err == nil:
+		return errors.Errorf("bundle file already exists, %q", cmd.Out)
+	case !os.IsNotExist(err):
+		return errors.WithStack(err)
+	}
+
+	pps := ps.NewPS("cmd-storage-export")
+	_ = pps.SetLogging(log)
+
+	_ = pps.
+		AddOK(launch.PNameEncoder, launch.PEncoder, nil).
+		AddOK(launch.PNameDesign, launch.PLoadDesign, nil, launch.PNameEncoder).
+		AddOK(launch.PNameLocal, launch.PLocal, nil, launch.PNameDesign).
+		AddOK(launch.PNameBlockItemReaders, launch.PBlockItemReaders, nil, launch.PNameDesign).
+		AddOK(launch.PNameStorage, launch.PStorage, launch.PCloseStorage, launch.PNameLocal)
+
+	_ = pps.POK(launch.PNameEncoder).
+		PostAddOK(launch.PNameAddHinters, launch.PAddHinters)
+
+	_ = pps.POK(launch.PNameDesign).
+		PostAddOK(launch.PNameCheckDesign, launch.PCheckDesign)
+
+	_ = pps.POK(launch.PNameBlockItemReaders).
+		PreAddOK(launch.PNameBlockItemReadersDecompressFunc, launch.PBlockItemReadersDecompressFunc).
+		PostAddOK(launch.PNameRemotesBlockItemReaderFunc, launch.PRemotesBlockItemReaderFunc)
+
+	_ = pps.POK(launch.PNameStorage).
+		PreAddOK(launch.PNameCheckLocalFS, launch.PCheckLocalFS).
+		PreAddOK(launch.PNameLoadDatabase, launch.PLoadDatabase).
+		PostAddOK(launch.PNameCheckLeveldbStorage, launch.PCheckLeveldbStorage).
+		PostAddOK(PNameStorageExport, cmd.pExport)
+
+	nctx := util.ContextWithValues(pctx, map[util.ContextKey]interface{}{
+		launch.DesignFlagContextKey: cmd.DesignFlag,
+		launch.DevFlagsContextKey:   cmd.DevFlags,
+		launch.PrivatekeyContextKey: string(cmd.PrivatekeyFlags.Flag.Body()),
+	})
+
+	cmd.log.Debug().Interface("process", pps.Verbose()).Msg("process ready")
+
+	nctx, err := pps.Run(nctx)
+	defer func() {
+		cmd.log.Debug().Interface("process", pps.Verbose()).Msg("process will be closed")
+
+		if _, err = pps.Close(nctx); err != nil {
+			cmd.log.Error().Err(err).Msg("failed to close")
+		}
+	}()
+
+	return err
+}
+
+func (cmd *StorageExportCommand) pExport(pctx context.Context) (context.Context, error) {
+	e := util.StringError("export blocks")
+
+	var isaacparams *isaac.Params
+	var db isaac.Database
+	var readers *isaac.BlockItemReaders
+	var fromRemotes isaac.RemotesBlockItemReadFunc
+
+	if err := util.LoadFromContextOK(pctx,
+		launch.ISAACParamsContextKey, &isaacparams,
+		launch.CenterDatabaseContextKey, &db,
+		launch.BlockItemReadersContextKey, &readers,
+		launch.RemotesBlockItemReaderFuncContextKey, &fromRemotes,
+	); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	from, to, err := cmd.heights(db)
+	if err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	f, err := os.OpenFile(filepath.Clean(cmd.Out), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	manifest, err := launch.WriteBlockBundle(pctx, f, isaacparams.NetworkID(), from, to, db, readers, fromRemotes)
+	if err != nil {
+		_ = os.Remove(f.Name())
+
+		return pctx, e.Wrap(err)
+	}
+
+	if err := f.Sync(); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	cmd.log.Info().
+		Str("out", cmd.Out).
+		Interface("from", manifest.From).
+		Interface("to", manifest.To).
+		Int("files", len(manifest.Files)).
+		Msg("blocks exported")
+
+	return pctx, nil
+}
+
+func (cmd *StorageExportCommand) heights(db isaac.Database) (from, to base.Height, _ error) {
+	from = cmd.From.Height()
+
+	if err := from.IsValid(nil); err != nil {
+		return from, to, errors.WithMessagef(err, "invalid from height")
+	}
+
+	switch {
+	case cmd.To.IsSet() && cmd.To.Height() > base.NilHeight:
+		to = cmd.To.Height()
+	default:
+		switch m, found, err := db.LastBlockMap(); {
+		case err != nil:
+			return from, to, err
+		case !found:
+			return from, to, util.ErrNotFound.Errorf("last blockmap")
+		default:
+			to = m.Manifest().Height()
+		}
+	}
+
+	if from > to {
+		return from, to, errors.Errorf("from height is higher than to; from=%d to=%d", from, to)
+	}
+
+	return from, to, nil
+}
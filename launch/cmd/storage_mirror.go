@@ -0,0 +1,184 @@
+package launchcmd
+
+import (
+	"context"
+	"net/url"
+	"path/filepath"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	"github.com/ProtoconNet/mitum2/launch"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/ProtoconNet/mitum2/util/ps"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+var PNameStoragePublishMirror = ps.Name("storage-publish-mirror")
+
+// StoragePublishMirrorCommand publishes the blocks of local storage into the
+// static block mirror directory, which can be served by plain http server and
+// used as `sync-source-mirror`. Without from height, it continues from the
+// last height of mirror; from height over the next of last height of mirror is
+// refused.
+type StoragePublishMirrorCommand struct { //nolint:govet //...
+	launch.DesignFlag
+	launch.PrivatekeyFlags
+	Directory       string            `arg:"" name:"directory" help:"mirror directory"`
+	From            launch.HeightFlag `name:"from" help:"from height; if empty, continue from the last of mirror"`
+	To              launch.HeightFlag `name:"to" help:"to height; if empty, last block is used"`
+	log             *zerolog.Logger
+	launch.DevFlags `embed:"" prefix:"dev."`
+}
+
+func (cmd *StoragePublishMirrorCommand) Run(pctx context.Context) error {
+	var log *logging.Logging
+	if err := util.LoadFromContextOK(pctx, launch.LoggingContextKey, &log); err != nil {
+		return err
+	}
+
+	log.Log().Debug().
+		Interface("design", cmd.DesignFlag).
+		Interface("privatekey", cmd.PrivatekeyFlags).
+		Interface("dev", cmd.DevFlags).
+		Str("directory", cmd.Directory).
+		Interface("from", cmd.From).
+		Interface("to", cmd.To).
+		Msg("flags")
+
+	cmd.log = log.Log()
+
+	pps := ps.NewPS("cmd-storage-publish-mirror")
+	_ = pps.SetLogging(log)
+
+	_ = pps.
+		AddOK(launch.PNameEncoder, launch.PEncoder, nil).
+		AddOK(launch.PNameDesign, launch.PLoadDesign, nil, launch.PNameEncoder).
+		AddOK(launch.PNameLocal, launch.PLocal, nil, launch.PNameDesign).
+		AddOK(launch.PNameBlockItemReaders, launch.PBlockItemReaders, nil, launch.PNameDesign).
+		AddOK(launch.PNameStorage, launch.PStorage, launch.PCloseStorage, launch.PNameLocal)
+
+	_ = pps.POK(launch.PNameEncoder).
+		PostAddOK(launch.PNameAddHinters, launch.PAddHinters)
+
+	_ = pps.POK(launch.PNameDesign).
+		PostAddOK(launch.PNameCheckDesign, launch.PCheckDesign)
+
+	_ = pps.POK(launch.PNameBlockItemReaders).
+		PreAddOK(launch.PNameBlockItemReadersDecompressFunc, launch.PBlockItemReadersDecompressFunc).
+		PostAddOK(launch.PNameRemotesBlockItemReaderFunc, launch.PRemotesBlockItemReaderFunc)
+
+	_ = pps.POK(launch.PNameStorage).
+		PreAddOK(launch.PNameCheckLocalFS, launch.PCheckLocalFS).
+		PreAddOK(launch.PNameLoadDatabase, launch.PLoadDatabase).
+		PostAddOK(launch.PNameCheckLeveldbStorage, launch.PCheckLeveldbStorage).
+		PostAddOK(PNameStoragePublishMirror, cmd.pPublish)
+
+	nctx := util.ContextWithValues(pctx, map[util.ContextKey]interface{}{
+		launch.DesignFlagContextKey: cmd.DesignFlag,
+		launch.DevFlagsContextKey:   cmd.DevFlags,
+		launch.PrivatekeyContextKey: string(cmd.PrivatekeyFlags.Flag.Body()),
+	})
+
+	cmd.log.Debug().Interface("process", pps.Verbose()).Msg("process ready")
+
+	nctx, err := pps.Run(nctx)
+	defer func() {
+		cmd.log.Debug().Interface("process", pps.Verbose()).Msg("process will be closed")
+
+		if _, err = pps.Close(nctx); err != nil {
+			cmd.log.Error().Err(err).Msg("failed to close")
+		}
+	}()
+
+	return err
+}
+
+func (cmd *StoragePublishMirrorCommand) pPublish(pctx context.Context) (context.Context, error) {
+	e := util.StringError("publish mirror")
+
+	var encs *encoder.Encoders
+	var db isaac.Database
+	var readers *isaac.BlockItemReaders
+	var fromRemotes isaac.RemotesBlockItemReadFunc
+
+	if err := util.LoadFromContextOK(pctx,
+		launch.EncodersContextKey, &encs,
+		launch.CenterDatabaseContextKey, &db,
+		launch.BlockItemReadersContextKey, &readers,
+		launch.RemotesBlockItemReaderFuncContextKey, &fromRemotes,
+	); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	root, err := filepath.Abs(cmd.Directory)
+	if err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	from, to, err := cmd.heights(pctx, root, db, encs)
+	if err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	if from > to {
+		cmd.log.Info().Interface("last", to).Msg("nothing to publish; mirror is up to date")
+
+		return pctx, nil
+	}
+
+	if err := launch.PublishBlockMirror(pctx, root, from, to, readers, fromRemotes); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	cmd.log.Info().
+		Str("directory", root).
+		Interface("from", from).
+		Interface("to", to).
+		Msg("mirror published")
+
+	return pctx, nil
+}
+
+func (cmd *StoragePublishMirrorCommand) heights(
+	ctx context.Context,
+	root string,
+	db isaac.Database,
+	encs *encoder.Encoders,
+) (from, to base.Height, _ error) {
+	switch {
+	case cmd.To.IsSet() && cmd.To.Height() > base.NilHeight:
+		to = cmd.To.Height()
+	default:
+		switch m, found, err := db.LastBlockMap(); {
+		case err != nil:
+			return from, to, err
+		case !found:
+			return from, to, util.ErrNotFound.Errorf("last blockmap")
+		default:
+			to = m.Manifest().Height()
+		}
+	}
+
+	if cmd.From.IsSet() && cmd.From.Height() > base.NilHeight {
+		return cmd.From.Height(), to, nil
+	}
+
+	mirror, err := isaac.NewBlockMirror(&url.URL{Scheme: "file", Path: filepath.ToSlash(root)}, encs.JSON())
+	if err != nil {
+		return from, to, err
+	}
+
+	switch last, found, err := mirror.LastHeight(ctx); {
+	case err != nil:
+		return from, to, errors.WithMessage(err, "last height of mirror")
+	case !found:
+		from = base.GenesisHeight
+	default:
+		from = last + 1
+	}
+
+	return from, to, nil
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/ProtoconNet/mitum2/base"
 	"github.com/ProtoconNet/mitum2/isaac"
 	isaacnetwork "github.com/ProtoconNet/mitum2/isaac/network"
+	"github.com/ProtoconNet/mitum2/network"
 	"github.com/ProtoconNet/mitum2/network/quicmemberlist"
 	"github.com/ProtoconNet/mitum2/network/quicstream"
 	"github.com/ProtoconNet/mitum2/util"
@@ -51,15 +52,35 @@ func init() {
 }
 
 type NodeDesign struct { //nolint:govet //...
-	Address        base.Address
-	Privatekey     base.Privatekey
-	Storage        NodeStorageDesign
-	Network        NodeNetworkDesign
-	NetworkID      base.NetworkID
-	LocalParams    *LocalParams
-	SyncSources    *SyncSourcesDesign
-	TimeServerPort int
-	TimeServer     string
+	Address     base.Address
+	Privatekey  base.Privatekey
+	Storage     NodeStorageDesign
+	Network     NodeNetworkDesign
+	NetworkID   base.NetworkID
+	LocalParams *LocalParams
+	SyncSources *SyncSourcesDesign
+	// DiscoveryProviders is the list of discovery provider strings; see
+	// NewDiscoveryProviderFromString.
+	DiscoveryProviders []string
+	// Events sets the event sinks and the retention of event database.
+	Events NodeEventsDesign
+	// Alerts sets the alert rules of local node.
+	Alerts AlertsDesign
+	// Profiler sets the pprof snapshot captures.
+	Profiler ProfilerDesign
+	// Signer sets the remote signer; if set, Privatekey is the
+	// RemoteSignerPrivatekey.
+	Signer *SignerDesign
+	// Checkpoint sets the trusted checkpoint for syncing.
+	Checkpoint *CheckpointDesign
+	// RotatePrivatekey is the new privatekey of the key rotation of local
+	// node; local node switches to it from the rotate height.
+	RotatePrivatekey base.Privatekey
+	TimeServerPort   int
+	TimeServer       string
+	// keystore is the keystore url of privatekey; it is decrypted by
+	// PLoadDesign.
+	keystore string
 }
 
 func NodeDesignFromFile(f string, jsonencoder encoder.Encoder) (d NodeDesign, _ []byte, _ error) {
@@ -148,6 +169,16 @@ func (d *NodeDesign) IsValid([]byte) error {
 		return e.Wrap(err)
 	}
 
+	if d.RotatePrivatekey != nil {
+		if err := d.RotatePrivatekey.IsValid(nil); err != nil {
+			return e.WithMessage(err, "rotate privatekey")
+		}
+
+		if d.RotatePrivatekey.Publickey().Equal(d.Privatekey.Publickey()) {
+			return e.Errorf("same rotate privatekey")
+		}
+	}
+
 	if err := d.Network.IsValid(nil); err != nil {
 		return e.Wrap(err)
 	}
@@ -164,6 +195,28 @@ func (d *NodeDesign) IsValid([]byte) error {
 		return e.Wrap(err)
 	}
 
+	if _, err := d.Discoveries(); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := d.Events.IsValid(nil); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := d.Alerts.IsValid(nil); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := d.Profiler.IsValid(nil); err != nil {
+		return e.Wrap(err)
+	}
+
+	if d.Checkpoint != nil {
+		if err := d.Checkpoint.IsValid(d.NetworkID); err != nil {
+			return e.Wrap(err)
+		}
+	}
+
 	switch {
 	case d.LocalParams == nil:
 		d.LocalParams = defaultLocalParams(d.NetworkID)
@@ -180,6 +233,26 @@ func (d *NodeDesign) IsValid([]byte) error {
 	return nil
 }
 
+// Discoveries returns the DiscoveryProviders from DiscoveryProviders strings.
+func (d *NodeDesign) Discoveries() ([]DiscoveryProvider, error) {
+	if len(d.DiscoveryProviders) < 1 {
+		return nil, nil
+	}
+
+	ps := make([]DiscoveryProvider, len(d.DiscoveryProviders))
+
+	for i := range d.DiscoveryProviders {
+		p, err := NewDiscoveryProviderFromString(d.DiscoveryProviders[i])
+		if err != nil {
+			return nil, err
+		}
+
+		ps[i] = p
+	}
+
+	return ps, nil
+}
+
 func (d *NodeDesign) Check(devflags DevFlags) error {
 	if !devflags.AllowRiskyThreshold {
 		if t := d.LocalParams.ISAAC.Threshold(); t < base.SafeThreshold {
@@ -191,38 +264,117 @@ func (d *NodeDesign) Check(devflags DevFlags) error {
 }
 
 type NodeDesignMarshaler struct {
-	LocalParams *LocalParams       `json:"parameters" yaml:"parameters"` //nolint:tagliatelle //...
-	SyncSources *SyncSourcesDesign `json:"sync_sources" yaml:"sync_sources"`
-	Address     base.Address       `json:"address" yaml:"address"`
-	Privatekey  base.Privatekey    `json:"privatekey" yaml:"privatekey"`
-	Storage     NodeStorageDesign  `json:"storage" yaml:"storage"`
-	NetworkID   string             `json:"network_id" yaml:"network_id"`
-	TimeServer  string             `json:"time_server,omitempty" yaml:"time_server,omitempty"`
-	Network     NodeNetworkDesign  `json:"network" yaml:"network"`
+	LocalParams        *LocalParams       `json:"parameters" yaml:"parameters"` //nolint:tagliatelle //...
+	SyncSources        *SyncSourcesDesign `json:"sync_sources" yaml:"sync_sources"`
+	Address            base.Address       `json:"address" yaml:"address"`
+	Privatekey         interface{}        `json:"privatekey,omitempty" yaml:"privatekey,omitempty"`
+	Storage            NodeStorageDesign  `json:"storage" yaml:"storage"`
+	NetworkID          string             `json:"network_id" yaml:"network_id"`
+	TimeServer         string             `json:"time_server,omitempty" yaml:"time_server,omitempty"`
+	Network            NodeNetworkDesign  `json:"network" yaml:"network"`
+	DiscoveryProviders []string           `json:"discovery_providers,omitempty" yaml:"discovery_providers,omitempty"`
+	Events             NodeEventsDesign   `json:"events,omitempty" yaml:"events,omitempty"`
+	Alerts             AlertsDesign       `json:"alerts,omitempty" yaml:"alerts,omitempty"`
+	Profiler           ProfilerDesign     `json:"profiler,omitempty" yaml:"profiler,omitempty"`
+	Signer             *SignerDesign      `json:"signer,omitempty" yaml:"signer,omitempty"`
+	Checkpoint         *CheckpointDesign  `json:"checkpoint,omitempty" yaml:"checkpoint,omitempty"`
+	RotatePrivatekey   base.Privatekey    `json:"rotate_privatekey,omitempty" yaml:"rotate_privatekey,omitempty"`
 }
 
 type NodeDesignYAMLUnmarshaler struct {
-	SyncSources interface{}                 `json:"sync_sources" yaml:"sync_sources"`
-	Storage     NodeStorageDesignLMarshaler `json:"storage" yaml:"storage"`
-	Address     string                      `json:"address" yaml:"address"`
-	Privatekey  string                      `json:"privatekey" yaml:"privatekey"`
-	NetworkID   string                      `json:"network_id" yaml:"network_id"`
-	TimeServer  string                      `json:"time_server,omitempty" yaml:"time_server,omitempty"`
-	LocalParams interface{}                 `json:"parameters" yaml:"parameters"` //nolint:tagliatelle //...
-	Network     NodeNetworkDesignMarshaler  `json:"network" yaml:"network"`
+	SyncSources        interface{}                  `json:"sync_sources" yaml:"sync_sources"`
+	Storage            NodeStorageDesignLMarshaler  `json:"storage" yaml:"storage"`
+	Address            string                       `json:"address" yaml:"address"`
+	Privatekey         string                       `json:"privatekey" yaml:"privatekey"`
+	NetworkID          string                       `json:"network_id" yaml:"network_id"`
+	TimeServer         string                       `json:"time_server,omitempty" yaml:"time_server,omitempty"`
+	LocalParams        interface{}                  `json:"parameters" yaml:"parameters"` //nolint:tagliatelle //...
+	Network            NodeNetworkDesignMarshaler   `json:"network" yaml:"network"`
+	DiscoveryProviders []string                     `json:"discovery_providers,omitempty" yaml:"discovery_providers,omitempty"`
+	Events             NodeEventsDesign             `json:"events,omitempty" yaml:"events,omitempty"`
+	Alerts             AlertsDesign                 `json:"alerts,omitempty" yaml:"alerts,omitempty"`
+	Profiler           ProfilerDesign               `json:"profiler,omitempty" yaml:"profiler,omitempty"`
+	Signer             *SignerDesignYAMLUnmarshaler `json:"signer,omitempty" yaml:"signer,omitempty"`
+	Checkpoint         *CheckpointDesign            `json:"checkpoint,omitempty" yaml:"checkpoint,omitempty"`
+	RotatePrivatekey   string                       `json:"rotate_privatekey,omitempty" yaml:"rotate_privatekey,omitempty"`
 }
 
 func (d NodeDesign) marshaler() NodeDesignMarshaler {
+	var priv interface{}
+
+	switch {
+	case d.Signer != nil:
+	case len(d.keystore) > 0:
+		priv = d.keystore // NOTE the decrypted privatekey is not exposed
+	case d.Privatekey != nil:
+		priv = d.Privatekey
+	}
+
 	return NodeDesignMarshaler{
-		Address:     d.Address,
-		Privatekey:  d.Privatekey,
-		NetworkID:   string(d.NetworkID),
-		Network:     d.Network,
-		Storage:     d.Storage,
-		LocalParams: d.LocalParams,
-		TimeServer:  d.TimeServer,
-		SyncSources: d.SyncSources,
+		Address:            d.Address,
+		Privatekey:         priv,
+		NetworkID:          string(d.NetworkID),
+		Network:            d.Network,
+		Storage:            d.Storage,
+		LocalParams:        d.LocalParams,
+		TimeServer:         d.TimeServer,
+		SyncSources:        d.SyncSources,
+		DiscoveryProviders: d.DiscoveryProviders,
+		Events:             d.Events,
+		Alerts:             d.Alerts,
+		Profiler:           d.Profiler,
+		Signer:             d.Signer,
+		Checkpoint:         d.Checkpoint,
+		RotatePrivatekey:   d.RotatePrivatekey,
+	}
+}
+
+func (d *NodeDesign) decodePrivatekey(u NodeDesignYAMLUnmarshaler, jsonencoder encoder.Encoder) error {
+	if len(u.RotatePrivatekey) > 0 {
+		priv, err := base.DecodePrivatekeyFromString(u.RotatePrivatekey, jsonencoder)
+		if err != nil {
+			return errors.WithMessage(err, "invalid rotate privatekey")
+		}
+
+		d.RotatePrivatekey = priv
 	}
+
+	if u.Signer == nil {
+		if IsKeystoreURL(u.Privatekey) {
+			// NOTE the keystore is decrypted by PLoadDesign
+			d.keystore = u.Privatekey
+
+			return nil
+		}
+
+		priv, err := base.DecodePrivatekeyFromString(u.Privatekey, jsonencoder)
+		if err != nil {
+			return errors.WithMessage(err, "invalid privatekey")
+		}
+
+		d.Privatekey = priv
+
+		return nil
+	}
+
+	if len(u.Privatekey) > 0 {
+		return errors.Errorf("privatekey and signer both given")
+	}
+
+	signer, err := u.Signer.Decode(jsonencoder)
+	if err != nil {
+		return err
+	}
+
+	priv, err := NewRemoteSignerPrivatekey(*signer)
+	if err != nil {
+		return errors.WithMessage(err, "invalid signer")
+	}
+
+	d.Signer = signer
+	d.Privatekey = priv
+
+	return nil
 }
 
 func (d NodeDesign) MarshalJSON() ([]byte, error) {
@@ -253,11 +405,8 @@ func (d *NodeDesign) DecodeYAML(b []byte, jsonencoder encoder.Encoder) error {
 		d.Address = address
 	}
 
-	switch priv, err := base.DecodePrivatekeyFromString(u.Privatekey, jsonencoder); {
-	case err != nil:
-		return e.WithMessage(err, "invalid privatekey")
-	default:
-		d.Privatekey = priv
+	if err := d.decodePrivatekey(u, jsonencoder); err != nil {
+		return e.Wrap(err)
 	}
 
 	d.NetworkID = base.NetworkID([]byte(u.NetworkID))
@@ -298,16 +447,36 @@ func (d *NodeDesign) DecodeYAML(b []byte, jsonencoder encoder.Encoder) error {
 	}
 
 	d.TimeServer = strings.TrimSpace(u.TimeServer)
+	d.DiscoveryProviders = u.DiscoveryProviders
+	d.Events = u.Events
+	d.Alerts = u.Alerts
+	d.Profiler = u.Profiler
+
+	if u.Checkpoint != nil {
+		if err := u.Checkpoint.Decode(jsonencoder); err != nil {
+			return e.Wrap(err)
+		}
+
+		d.Checkpoint = u.Checkpoint
+	}
 
 	return nil
 }
 
 type NodeNetworkDesign struct {
 	publishConnInfo quicstream.ConnInfo
+	relayConnInfo   quicstream.ConnInfo
 	Bind            *net.UDPAddr `yaml:"bind"`
 	publish         *net.UDPAddr
 	PublishString   string `yaml:"publish"` //nolint:tagliatelle //...
-	TLSInsecure     bool   `yaml:"tls_insecure"`
+	// RelayString is the ConnInfo of relay node. If local node can not be
+	// reached directly, like behind NAT, the relay node forwards the streams
+	// to local node.
+	RelayString string `yaml:"relay"`
+	TLSInsecure bool   `yaml:"tls_insecure"`
+	// RelayServer enables the relay; the nodes behind NAT can register to
+	// local node.
+	RelayServer bool `yaml:"relay_server"`
 }
 
 func (d *NodeNetworkDesign) IsValid([]byte) error {
@@ -344,6 +513,17 @@ func (d *NodeNetworkDesign) IsValid([]byte) error {
 		d.publishConnInfo = i
 	}
 
+	if s := strings.TrimSpace(d.RelayString); len(s) > 0 {
+		switch i, err := quicstream.NewConnInfoFromFullString(s); {
+		case err != nil:
+			return e.WithMessage(err, "invalid relay")
+		case network.EqualConnInfo(i, d.publishConnInfo):
+			return e.Errorf("relay same with publish")
+		default:
+			d.relayConnInfo = i
+		}
+	}
+
 	return nil
 }
 
@@ -355,10 +535,16 @@ func (d NodeNetworkDesign) PublishConnInfo() quicstream.ConnInfo {
 	return d.publishConnInfo
 }
 
+func (d NodeNetworkDesign) Relay() (quicstream.ConnInfo, bool) {
+	return d.relayConnInfo, d.relayConnInfo.UDPAddr() != nil
+}
+
 type NodeNetworkDesignMarshaler struct {
 	Bind        string `json:"bind,omitempty" yaml:"bind,omitempty"`
 	Publish     string `json:"publish" yaml:"publish"`
+	Relay       string `json:"relay,omitempty" yaml:"relay,omitempty"`
 	TLSInsecure bool   `json:"tls_insecure" yaml:"tls_insecure"`
+	RelayServer bool   `json:"relay_server,omitempty" yaml:"relay_server,omitempty"`
 }
 
 func (d NodeNetworkDesign) marshaler() NodeNetworkDesignMarshaler {
@@ -371,7 +557,9 @@ func (d NodeNetworkDesign) marshaler() NodeNetworkDesignMarshaler {
 	return NodeNetworkDesignMarshaler{
 		Bind:        bind,
 		Publish:     d.PublishString,
+		Relay:       d.RelayString,
 		TLSInsecure: d.TLSInsecure,
+		RelayServer: d.RelayServer,
 	}
 }
 
@@ -396,7 +584,9 @@ func (y *NodeNetworkDesignMarshaler) Decode(encoder.Encoder) (d NodeNetworkDesig
 	}
 
 	d.PublishString = y.Publish
+	d.RelayString = y.Relay
 	d.TLSInsecure = y.TLSInsecure
+	d.RelayServer = y.RelayServer
 
 	return d, nil
 }
@@ -309,6 +309,40 @@ func (t *testSyncSourcesDesign) TestDecode() {
 		t.ErrorContains(err, "missing host")
 	})
 
+	t.Run("ok: mirror", func() {
+		b := []byte(`
+- type: sync-source-mirror
+  url: https://a.b.c.d/blocks#https_insecure
+- type: sync-source-mirror
+  url: file:///var/mirror
+`)
+
+		var s SyncSourcesDesign
+		t.NoError(s.DecodeYAML(b, t.enc))
+
+		t.NoError(s.IsValid(nil))
+
+		sources := s.Sources()
+		t.Equal(2, len(sources))
+
+		t.Equal(isaacnetwork.SyncSourceTypeMirror, sources[0].Type)
+		t.Equal("https://a.b.c.d/blocks#https_insecure", sources[0].Source.(*url.URL).String())
+		t.Equal(isaacnetwork.SyncSourceTypeMirror, sources[1].Type)
+		t.Equal("file:///var/mirror", sources[1].Source.(*url.URL).String())
+	})
+
+	t.Run("invalid: mirror", func() {
+		b := []byte(`
+- type: sync-source-mirror
+  url: quic://a.b.c.d:1234
+`)
+
+		var s SyncSourcesDesign
+		err := s.DecodeYAML(b, t.enc)
+		t.Error(err)
+		t.ErrorContains(err, "unsupported scheme")
+	})
+
 	t.Run("invalid type", func() {
 		b := []byte(`
 - type: sync-source-node
@@ -654,8 +688,13 @@ parameters:
   misc:
     valid_proposal_operation_expire: 11s
     object_cache_size: 33
+    sign_cache_size: 44
     block_item_readers_remove_empty_after: 4h
     block_item_readers_remove_empty_interval: 5h
+    health_max_sync_lag: 9
+    health_max_last_block_elapsed: 44s
+    block_archive_heights: 1000
+    block_archive_interval: 55s
   memberlist:
     tcp_timeout: 6s
     udp_buffer_size: 333
@@ -705,8 +744,13 @@ parameters:
 		misc := defaultMISCParams()
 		misc.SetValidProposalOperationExpire(time.Second * 11)
 		misc.SetObjectCacheSize(33)
+		misc.SetSignCacheSize(44)
 		misc.SetBlockItemReadersRemoveEmptyAfter(time.Hour * 4)
 		misc.SetBlockItemReadersRemoveEmptyInterval(time.Hour * 5)
+		misc.SetHealthMaxSyncLag(9)
+		misc.SetHealthMaxLastBlockElapsed(time.Second * 44)
+		misc.SetBlockArchiveHeights(1000)
+		misc.SetBlockArchiveInterval(time.Second * 55)
 
 		equalMISCParams(t.Assert(), misc, a.LocalParams.MISC)
 
@@ -1553,12 +1597,18 @@ func equalMemberlistParams(t *assert.Assertions, a, b *MemberlistParams) {
 
 func equalMISCParams(t *assert.Assertions, a, b *MISCParams) {
 	t.Equal(a.SyncSourceCheckerInterval(), b.SyncSourceCheckerInterval())
+	t.Equal(a.DiscoveryInterval(), b.DiscoveryInterval())
 	t.Equal(a.ValidProposalOperationExpire(), b.ValidProposalOperationExpire())
 	t.Equal(a.ValidProposalSuffrageOperationsExpire(), b.ValidProposalSuffrageOperationsExpire())
 	t.Equal(a.BlockItemReadersRemoveEmptyAfter(), b.BlockItemReadersRemoveEmptyAfter())
 	t.Equal(a.BlockItemReadersRemoveEmptyInterval(), b.BlockItemReadersRemoveEmptyInterval())
 	t.Equal(a.MaxMessageSize(), b.MaxMessageSize())
 	t.Equal(a.ObjectCacheSize(), b.ObjectCacheSize())
+	t.Equal(a.SignCacheSize(), b.SignCacheSize())
+	t.Equal(a.HealthMaxSyncLag(), b.HealthMaxSyncLag())
+	t.Equal(a.HealthMaxLastBlockElapsed(), b.HealthMaxLastBlockElapsed())
+	t.Equal(a.BlockArchiveHeights(), b.BlockArchiveHeights())
+	t.Equal(a.BlockArchiveInterval(), b.BlockArchiveInterval())
 }
 
 func equalNetworkParams(t *assert.Assertions, a, b *NetworkParams) {
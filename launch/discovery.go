@@ -0,0 +1,484 @@
+package launch
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/network"
+	"github.com/ProtoconNet/mitum2/network/quicstream"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	DiscoveryProviderSchemeDNSSRV = "dns+srv"
+	DiscoveryProviderSchemeDNSTXT = "dns+txt"
+	DiscoveryProviderSchemeFile   = "file"
+	DiscoveryProviderSchemeHTTP   = "http"
+	DiscoveryProviderSchemeHTTPS  = "https"
+)
+
+// DiscoveryProvider provides the discoveries from the external source.
+type DiscoveryProvider interface {
+	Discover(context.Context) ([]quicstream.ConnInfo, error)
+	String() string
+}
+
+// DiscoveryWatcher is the DiscoveryProvider, which can notify the changes of
+// source without waiting next interval.
+type DiscoveryWatcher interface {
+	Watch(_ context.Context, notify func()) error
+}
+
+// NewDiscoveryProviderFromString parses the provider string. Supported formats
+// are,
+//   - "dns+srv://_mitum._udp.example.com": SRV records of name
+//   - "dns+txt://discovery.example.com": TXT records of name; each record has
+//     the conninfo strings
+//   - "file:///path/to/discovery.yml": local file; the file is watched for
+//     changes
+//   - "https://example.com/discovery": http endpoint
+//
+// The "#tls_insecure" suffix sets tls insecure for the conninfos of DNS SRV
+// records, and for the http client of http endpoint.
+func NewDiscoveryProviderFromString(s string) (DiscoveryProvider, error) {
+	e := util.ErrInvalid.Errorf("invalid discovery provider, %q", s)
+
+	as, tlsinsecure := network.ParseTLSInsecure(strings.TrimSpace(s))
+
+	u, err := url.Parse(as)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	switch u.Scheme {
+	case DiscoveryProviderSchemeDNSSRV, DiscoveryProviderSchemeDNSTXT:
+		if len(u.Host) < 1 {
+			return nil, e.Errorf("empty name")
+		}
+
+		if u.Scheme == DiscoveryProviderSchemeDNSSRV {
+			return NewDNSSRVDiscoveryProvider(u.Host, tlsinsecure, nil), nil
+		}
+
+		return NewDNSTXTDiscoveryProvider(u.Host, nil), nil
+	case DiscoveryProviderSchemeFile:
+		p := u.Path
+		if len(u.Host) > 0 {
+			p = filepath.Join(u.Host, u.Path)
+		}
+
+		if len(p) < 1 {
+			return nil, e.Errorf("empty file path")
+		}
+
+		return NewFileDiscoveryProvider(filepath.Clean(p)), nil
+	case DiscoveryProviderSchemeHTTP, DiscoveryProviderSchemeHTTPS:
+		if len(u.Host) < 1 {
+			return nil, e.Errorf("empty host")
+		}
+
+		return NewHTTPDiscoveryProvider(u, tlsinsecure, nil), nil
+	default:
+		return nil, e.Errorf("unknown scheme, %q", u.Scheme)
+	}
+}
+
+type DNSSRVDiscoveryProvider struct {
+	resolver    *net.Resolver
+	name        string
+	tlsinsecure bool
+}
+
+func NewDNSSRVDiscoveryProvider(name string, tlsinsecure bool, resolver *net.Resolver) *DNSSRVDiscoveryProvider {
+	if resolver == nil {
+		resolver = net.DefaultResolver //revive:disable-line:modifies-parameter
+	}
+
+	return &DNSSRVDiscoveryProvider{resolver: resolver, name: name, tlsinsecure: tlsinsecure}
+}
+
+func (p *DNSSRVDiscoveryProvider) Discover(ctx context.Context) ([]quicstream.ConnInfo, error) {
+	_, srvs, err := p.resolver.LookupSRV(ctx, "", "", p.name)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "lookup srv, %q", p.name)
+	}
+
+	cis := make([]quicstream.ConnInfo, 0, len(srvs))
+
+	for i := range srvs {
+		addr := net.JoinHostPort(
+			strings.TrimSuffix(srvs[i].Target, "."),
+			strconv.FormatUint(uint64(srvs[i].Port), 10),
+		)
+
+		ci, err := quicstream.NewConnInfoFromStringAddr(addr, p.tlsinsecure)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "srv record, %q", addr)
+		}
+
+		cis = append(cis, ci)
+	}
+
+	return cis, nil
+}
+
+func (p *DNSSRVDiscoveryProvider) String() string {
+	return DiscoveryProviderSchemeDNSSRV + "://" + p.name
+}
+
+type DNSTXTDiscoveryProvider struct {
+	resolver *net.Resolver
+	name     string
+}
+
+func NewDNSTXTDiscoveryProvider(name string, resolver *net.Resolver) *DNSTXTDiscoveryProvider {
+	if resolver == nil {
+		resolver = net.DefaultResolver //revive:disable-line:modifies-parameter
+	}
+
+	return &DNSTXTDiscoveryProvider{resolver: resolver, name: name}
+}
+
+func (p *DNSTXTDiscoveryProvider) Discover(ctx context.Context) ([]quicstream.ConnInfo, error) {
+	txts, err := p.resolver.LookupTXT(ctx, p.name)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "lookup txt, %q", p.name)
+	}
+
+	return parseDiscoveryConnInfos([]byte(strings.Join(txts, "\n")))
+}
+
+func (p *DNSTXTDiscoveryProvider) String() string {
+	return DiscoveryProviderSchemeDNSTXT + "://" + p.name
+}
+
+// FileDiscoveryProvider reads the conninfos from local file. The file can be
+// yaml list or the conninfo strings separated by new line.
+type FileDiscoveryProvider struct {
+	f string
+}
+
+func NewFileDiscoveryProvider(f string) *FileDiscoveryProvider {
+	return &FileDiscoveryProvider{f: f}
+}
+
+func (p *FileDiscoveryProvider) Discover(context.Context) ([]quicstream.ConnInfo, error) {
+	b, err := os.ReadFile(p.f)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return parseDiscoveryConnInfos(b)
+}
+
+// Watch watches the directory of file instead of file; the editors and
+// configuration tools usually replace the file.
+func (p *FileDiscoveryProvider) Watch(ctx context.Context, notify func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	defer func() {
+		_ = watcher.Close()
+	}()
+
+	if err := watcher.Add(filepath.Dir(p.f)); err != nil {
+		return errors.WithStack(err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			if filepath.Clean(ev.Name) == p.f && !ev.Has(fsnotify.Chmod) {
+				notify()
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+
+			return errors.WithStack(err)
+		}
+	}
+}
+
+func (p *FileDiscoveryProvider) String() string {
+	return DiscoveryProviderSchemeFile + "://" + p.f
+}
+
+// HTTPDiscoveryProvider requests the conninfos to http endpoint. The response
+// body can be yaml(or json) list or the conninfo strings separated by new line.
+type HTTPDiscoveryProvider struct {
+	client *http.Client
+	u      *url.URL
+}
+
+func NewHTTPDiscoveryProvider(u *url.URL, tlsinsecure bool, client *http.Client) *HTTPDiscoveryProvider {
+	if client == nil {
+		client = &http.Client{ //revive:disable-line:modifies-parameter
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: tlsinsecure, //nolint:gosec //...
+				},
+			},
+		}
+	}
+
+	return &HTTPDiscoveryProvider{client: client, u: u}
+}
+
+func (p *HTTPDiscoveryProvider) Discover(ctx context.Context) ([]quicstream.ConnInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.u.String(), nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status code, %d", res.StatusCode)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(res.Body, 1<<20)) //nolint:gomnd // big enough
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return parseDiscoveryConnInfos(b)
+}
+
+func (p *HTTPDiscoveryProvider) String() string {
+	return p.u.String()
+}
+
+// DiscoveryUpdater periodically collects the conninfos from the
+// DiscoveryProviders and merges them into the discoveries. The discoveries,
+// which are not from DiscoveryProviders, like the discovery flag, are kept.
+type DiscoveryUpdater struct {
+	*logging.Logging
+	*util.ContextDaemon
+	discoveries *util.Locked[[]quicstream.ConnInfo]
+	whenUpdated func(context.Context, []quicstream.ConnInfo)
+	intervalf   func() time.Duration
+	last        map[string][]quicstream.ConnInfo
+	discovered  []quicstream.ConnInfo
+	providers   []DiscoveryProvider
+	sync.Mutex
+}
+
+func NewDiscoveryUpdater(
+	providers []DiscoveryProvider,
+	discoveries *util.Locked[[]quicstream.ConnInfo],
+	intervalf func() time.Duration,
+	whenUpdated func(context.Context, []quicstream.ConnInfo),
+) *DiscoveryUpdater {
+	if whenUpdated == nil {
+		whenUpdated = func(context.Context, []quicstream.ConnInfo) {} //revive:disable-line:modifies-parameter
+	}
+
+	u := &DiscoveryUpdater{
+		Logging: logging.NewLogging(func(zctx zerolog.Context) zerolog.Context {
+			return zctx.Str("module", "discovery-updater")
+		}),
+		providers:   providers,
+		discoveries: discoveries,
+		intervalf:   intervalf,
+		whenUpdated: whenUpdated,
+		last:        map[string][]quicstream.ConnInfo{},
+	}
+
+	u.ContextDaemon = util.NewContextDaemon(u.start)
+
+	return u
+}
+
+// Discovered returns the last conninfos from DiscoveryProviders.
+func (u *DiscoveryUpdater) Discovered() []quicstream.ConnInfo {
+	u.Lock()
+	defer u.Unlock()
+
+	return u.discovered
+}
+
+func (u *DiscoveryUpdater) start(ctx context.Context) error {
+	notifych := make(chan struct{}, 1)
+
+	notify := func() {
+		select {
+		case notifych <- struct{}{}:
+		default:
+		}
+	}
+
+	for i := range u.providers {
+		w, ok := u.providers[i].(DiscoveryWatcher)
+		if !ok {
+			continue
+		}
+
+		go func(p DiscoveryProvider) {
+			if err := w.Watch(ctx, notify); err != nil {
+				u.Log().Error().Err(err).Stringer("provider", p).Msg("failed to watch discovery provider")
+			}
+		}(u.providers[i])
+	}
+
+	ticker := time.NewTicker(u.interval())
+	defer ticker.Stop()
+
+	for {
+		u.Update(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-notifych:
+		case <-ticker.C:
+		}
+
+		ticker.Reset(u.interval())
+	}
+}
+
+func (u *DiscoveryUpdater) interval() time.Duration {
+	if i := u.intervalf(); i > 0 {
+		return i
+	}
+
+	return time.Second * 30 //nolint:gomnd //...
+}
+
+// Update collects the conninfos from DiscoveryProviders. If provider fails,
+// the previous conninfos of the provider are used.
+func (u *DiscoveryUpdater) Update(ctx context.Context) {
+	u.Lock()
+	defer u.Unlock()
+
+	var cis []quicstream.ConnInfo
+
+	for i := range u.providers {
+		p := u.providers[i]
+
+		switch j, err := u.discover(ctx, p); {
+		case err != nil:
+			u.Log().Error().Err(err).Stringer("provider", p).Msg("failed to discover; previous one will be used")
+		default:
+			u.last[p.String()] = j
+		}
+
+		cis = append(cis, u.last[p.String()]...)
+	}
+
+	cis, _ = util.RemoveDuplicatedSlice(cis, func(i quicstream.ConnInfo) (string, error) {
+		return i.String(), nil
+	})
+
+	prev := u.discovered
+	u.discovered = cis
+
+	_, _ = u.discoveries.Set(func(i []quicstream.ConnInfo, _ bool) ([]quicstream.ConnInfo, error) {
+		// NOTE remove the previously discovered ones and add new ones
+		others := util.Filter2Slices(i, prev, func(a, b quicstream.ConnInfo) bool {
+			return a.String() == b.String()
+		})
+
+		n, _ := util.RemoveDuplicatedSlice(append(others, cis...), func(i quicstream.ConnInfo) (string, error) {
+			return i.String(), nil
+		})
+
+		if len(n) == len(i) && len(util.Filter2Slices(n, i, func(a, b quicstream.ConnInfo) bool {
+			return a.String() == b.String()
+		})) < 1 {
+			return nil, util.ErrLockedSetIgnore
+		}
+
+		u.Log().Debug().Interface("discoveries", n).Msg("discoveries updated")
+
+		return n, nil
+	})
+
+	u.whenUpdated(ctx, cis)
+}
+
+func (u *DiscoveryUpdater) discover(ctx context.Context, p DiscoveryProvider) ([]quicstream.ConnInfo, error) {
+	cctx, cancel := context.WithTimeout(ctx, time.Second*9) //nolint:gomnd //...
+	defer cancel()
+
+	return p.Discover(cctx)
+}
+
+// parseDiscoveryConnInfos parses yaml(or json) list of conninfo strings; if
+// not list, the conninfo strings are separated by spaces, commas or new lines.
+// The line starting with "#" is ignored.
+func parseDiscoveryConnInfos(b []byte) ([]quicstream.ConnInfo, error) {
+	var sl []string
+
+	if err := yaml.Unmarshal(b, &sl); err != nil {
+		sl = nil
+
+		lines := bytes.Split(b, []byte{'\n'})
+
+		for i := range lines {
+			line := strings.TrimSpace(string(lines[i]))
+			if len(line) < 1 || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			sl = append(sl, strings.FieldsFunc(line, func(r rune) bool {
+				return r == ',' || r == ' ' || r == '\t'
+			})...)
+		}
+	}
+
+	cis := make([]quicstream.ConnInfo, 0, len(sl))
+
+	for i := range sl {
+		s := strings.TrimSpace(sl[i])
+		if len(s) < 1 {
+			continue
+		}
+
+		if err := network.IsValidAddr(s); err != nil {
+			return nil, errors.WithMessagef(err, "conninfo, %q", s)
+		}
+
+		ci, err := quicstream.NewConnInfoFromFullString(s)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "conninfo, %q", s)
+		}
+
+		cis = append(cis, ci)
+	}
+
+	return cis, nil
+}
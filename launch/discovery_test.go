@@ -0,0 +1,293 @@
+package launch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/network/quicstream"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestNewDiscoveryProviderFromString(tt *testing.T) {
+	t := new(suite.Suite)
+	t.SetT(tt)
+
+	cases := []struct {
+		name     string
+		s        string
+		expected string
+		err      string
+	}{
+		{name: "dns srv", s: "dns+srv://_mitum._udp.example.com", expected: "dns+srv://_mitum._udp.example.com"},
+		{name: "dns srv; tls insecure", s: "dns+srv://_mitum._udp.example.com#tls_insecure", expected: "dns+srv://_mitum._udp.example.com"},
+		{name: "dns txt", s: "dns+txt://example.com", expected: "dns+txt://example.com"},
+		{name: "dns; empty name", s: "dns+txt://", err: "empty name"},
+		{name: "file", s: "file:///a/b/c.yml", expected: "file:///a/b/c.yml"},
+		{name: "http", s: "https://example.com/discovery", expected: "https://example.com/discovery"},
+		{name: "unknown scheme", s: "ftp://example.com", err: "unknown scheme"},
+	}
+
+	for i, c := range cases {
+		i := i
+		c := c
+
+		t.Run(c.name, func() {
+			p, err := NewDiscoveryProviderFromString(c.s)
+			if len(c.err) > 0 {
+				t.Error(err, "%d: %v", i, c.name)
+				t.ErrorContains(err, c.err, "%d: %v", i, c.name)
+
+				return
+			}
+
+			t.NoError(err, "%d: %v", i, c.name)
+			t.Equal(c.expected, p.String(), "%d: %v", i, c.name)
+		})
+	}
+}
+
+func TestParseDiscoveryConnInfos(tt *testing.T) {
+	t := new(suite.Suite)
+	t.SetT(tt)
+
+	cases := []struct {
+		name     string
+		s        string
+		expected []string
+		err      string
+	}{
+		{name: "empty", s: "", expected: []string{}},
+		{name: "yaml list", s: "- 127.0.0.1:4321\n- 127.0.0.1:4322#tls_insecure", expected: []string{"127.0.0.1:4321", "127.0.0.1:4322#tls_insecure"}},
+		{name: "json list", s: `["127.0.0.1:4321", "127.0.0.1:4322"]`, expected: []string{"127.0.0.1:4321", "127.0.0.1:4322"}},
+		{name: "lines", s: "# comment\n127.0.0.1:4321\n\n127.0.0.1:4322#tls_insecure\n", expected: []string{"127.0.0.1:4321", "127.0.0.1:4322#tls_insecure"}},
+		{name: "separated", s: "127.0.0.1:4321,127.0.0.1:4322 127.0.0.1:4323", expected: []string{"127.0.0.1:4321", "127.0.0.1:4322", "127.0.0.1:4323"}},
+		{name: "invalid", s: "127.0.0.1", err: "conninfo"},
+	}
+
+	for i, c := range cases {
+		i := i
+		c := c
+
+		t.Run(c.name, func() {
+			cis, err := parseDiscoveryConnInfos([]byte(c.s))
+			if len(c.err) > 0 {
+				t.Error(err, "%d: %v", i, c.name)
+				t.ErrorContains(err, c.err, "%d: %v", i, c.name)
+
+				return
+			}
+
+			t.NoError(err, "%d: %v", i, c.name)
+
+			s := make([]string, len(cis))
+			for j := range cis {
+				s[j] = cis[j].String()
+			}
+
+			t.Equal(c.expected, s, "%d: %v", i, c.name)
+		})
+	}
+}
+
+type dummyDiscoveryProvider struct {
+	f    func(context.Context) ([]quicstream.ConnInfo, error)
+	name string
+}
+
+func (p dummyDiscoveryProvider) Discover(ctx context.Context) ([]quicstream.ConnInfo, error) {
+	return p.f(ctx)
+}
+
+func (p dummyDiscoveryProvider) String() string {
+	return p.name
+}
+
+type testDiscoveryUpdater struct {
+	suite.Suite
+}
+
+func (t *testDiscoveryUpdater) cis(n int) []quicstream.ConnInfo {
+	cis := make([]quicstream.ConnInfo, n)
+
+	for i := range cis {
+		cis[i] = quicstream.MustNewConnInfoFromFullString(fmt.Sprintf("127.0.0.1:%d", 4321+i))
+	}
+
+	return cis
+}
+
+func (t *testDiscoveryUpdater) strings(cis []quicstream.ConnInfo) []string {
+	s := make([]string, len(cis))
+
+	for i := range cis {
+		s[i] = cis[i].String()
+	}
+
+	return s
+}
+
+func (t *testDiscoveryUpdater) TestUpdate() {
+	cis := t.cis(4)
+
+	discoveries := util.NewLocked([]quicstream.ConnInfo{cis[0]})
+
+	var discovered []quicstream.ConnInfo
+	var failed bool
+
+	p := dummyDiscoveryProvider{
+		name: "dummy",
+		f: func(context.Context) ([]quicstream.ConnInfo, error) {
+			if failed {
+				return nil, errors.Errorf("failed")
+			}
+
+			return discovered, nil
+		},
+	}
+
+	updatedch := make(chan []quicstream.ConnInfo, 1)
+
+	u := NewDiscoveryUpdater([]DiscoveryProvider{p}, discoveries, func() time.Duration { return time.Minute },
+		func(_ context.Context, cis []quicstream.ConnInfo) {
+			updatedch <- cis
+		},
+	)
+	_ = u.SetLogging(logging.TestNilLogging)
+
+	t.Run("add", func() {
+		discovered = []quicstream.ConnInfo{cis[1], cis[2]}
+
+		u.Update(context.Background())
+
+		t.Equal(t.strings(cis[:3]), t.strings(GetDiscoveriesFromLocked(discoveries)))
+		t.Equal(t.strings(cis[1:3]), t.strings(<-updatedch))
+	})
+
+	t.Run("replace", func() {
+		discovered = []quicstream.ConnInfo{cis[3]}
+
+		u.Update(context.Background())
+
+		t.Equal(t.strings([]quicstream.ConnInfo{cis[0], cis[3]}), t.strings(GetDiscoveriesFromLocked(discoveries)))
+		t.Equal(t.strings(cis[3:]), t.strings(<-updatedch))
+	})
+
+	t.Run("failed; keep previous", func() {
+		failed = true
+
+		u.Update(context.Background())
+
+		t.Equal(t.strings([]quicstream.ConnInfo{cis[0], cis[3]}), t.strings(GetDiscoveriesFromLocked(discoveries)))
+		t.Equal(t.strings(cis[3:]), t.strings(<-updatedch))
+	})
+
+	t.Run("same with static", func() {
+		failed = false
+		discovered = []quicstream.ConnInfo{cis[0]}
+
+		u.Update(context.Background())
+
+		t.Equal(t.strings(cis[:1]), t.strings(GetDiscoveriesFromLocked(discoveries)))
+		t.Equal(t.strings(cis[:1]), t.strings(u.Discovered()))
+		<-updatedch
+	})
+}
+
+func (t *testDiscoveryUpdater) TestFileProvider() {
+	cis := t.cis(3)
+
+	f := filepath.Join(t.T().TempDir(), "discovery.yml")
+	t.NoError(os.WriteFile(f, []byte(cis[0].String()), 0o600))
+
+	p, err := NewDiscoveryProviderFromString("file://" + f)
+	t.NoError(err)
+
+	discoveries := util.EmptyLocked[[]quicstream.ConnInfo]()
+
+	updatedch := make(chan []quicstream.ConnInfo, 3)
+
+	u := NewDiscoveryUpdater([]DiscoveryProvider{p}, discoveries, func() time.Duration { return time.Minute },
+		func(_ context.Context, cis []quicstream.ConnInfo) {
+			updatedch <- cis
+		},
+	)
+	_ = u.SetLogging(logging.TestNilLogging)
+
+	t.NoError(u.Start(context.Background()))
+	defer u.Stop()
+
+	select {
+	case <-time.After(time.Second * 2):
+		t.Fail("failed to wait update")
+	case i := <-updatedch:
+		t.Equal(t.strings(cis[:1]), t.strings(i))
+	}
+
+	<-time.After(time.Millisecond * 333) // NOTE wait to start watching
+
+	t.T().Log("update file")
+	t.NoError(os.WriteFile(f, []byte("- "+cis[1].String()+"\n- "+cis[2].String()), 0o600))
+
+	timeout := time.After(time.Second * 3)
+
+	for {
+		select {
+		case <-timeout:
+			t.Fail("failed to wait update by watch")
+
+			return
+		case i := <-updatedch:
+			if len(i) != 2 {
+				continue
+			}
+
+			t.Equal(t.strings(cis[1:]), t.strings(i))
+			t.Equal(t.strings(cis[1:]), t.strings(GetDiscoveriesFromLocked(discoveries)))
+
+			return
+		}
+	}
+}
+
+func (t *testDiscoveryUpdater) TestHTTPProvider() {
+	cis := t.cis(2)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprintf(w, `["%s", "%s"]`, cis[0].String(), cis[1].String())
+	}))
+	defer ts.Close()
+
+	p, err := NewDiscoveryProviderFromString(ts.URL)
+	t.NoError(err)
+
+	rcis, err := p.Discover(context.Background())
+	t.NoError(err)
+	t.Equal(t.strings(cis), t.strings(rcis))
+
+	t.Run("not ok", func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer ts.Close()
+
+		p, err := NewDiscoveryProviderFromString(ts.URL)
+		t.NoError(err)
+
+		_, err = p.Discover(context.Background())
+		t.Error(err)
+		t.ErrorContains(err, "unexpected status code")
+	})
+}
+
+func TestDiscoveryUpdater(t *testing.T) {
+	suite.Run(t, new(testDiscoveryUpdater))
+}
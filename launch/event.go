@@ -51,6 +51,8 @@ var AllEventLoggerNames = []EventLoggerName{
 	ACLEventLogger,
 	EventLoggingEventLogger,
 	BlockItemFilesEventLogger,
+	TimelineEventLogger,
+	AlertEventLogger,
 }
 
 var EventLoggingEventLogger EventLoggerName = "event_logging"
@@ -60,12 +62,18 @@ var PNameEventLoggingNetworkHandlers = ps.Name("event-log-network-handlers")
 var EventLoggingACLScope = ACLScope("event-log")
 
 type EventLogging struct {
-	db *eventDatabase
-	w  io.Writer
-	m  map[EventLoggerName]zerolog.Logger
+	db    *eventDatabase
+	w     io.Writer
+	m     map[EventLoggerName]zerolog.Logger
+	sinks *util.Locked[[]eventSink]
 	sync.RWMutex
 }
 
+type eventSink struct {
+	sink  EventSink
+	names map[EventLoggerName]struct{}
+}
+
 func LoadDefaultEventStorage( //revive:disable-line:flag-parameter
 	dir string, readonly bool,
 ) (*leveldbstorage.Storage, error) {
@@ -111,9 +119,10 @@ func NewEventLogging(root string, defaultwriter io.Writer) (*EventLogging, error
 
 func newEventLoggingWithStorage(st *leveldbstorage.Storage, defaultwriter io.Writer) *EventLogging {
 	el := &EventLogging{
-		db: newEventDatabase(st),
-		w:  defaultwriter,
-		m:  map[EventLoggerName]zerolog.Logger{},
+		db:    newEventDatabase(st),
+		w:     defaultwriter,
+		m:     map[EventLoggerName]zerolog.Logger{},
+		sinks: util.EmptyLocked[[]eventSink](),
 	}
 
 	_, _ = el.Register(UnknownEventLogger)
@@ -122,9 +131,83 @@ func newEventLoggingWithStorage(st *leveldbstorage.Storage, defaultwriter io.Wri
 }
 
 func (el *EventLogging) Close() error {
+	if err := el.CloseSinks(); err != nil {
+		return err
+	}
+
 	return el.db.close()
 }
 
+// AddSink adds EventSink, which receives the events of names; if names is
+// empty, it receives all events.
+func (el *EventLogging) AddSink(sink EventSink, names ...EventLoggerName) {
+	s := eventSink{sink: sink}
+
+	for i := range names {
+		if names[i] == AllEventLogger {
+			s.names = nil
+
+			break
+		}
+
+		if s.names == nil {
+			s.names = map[EventLoggerName]struct{}{}
+		}
+
+		s.names[names[i]] = struct{}{}
+	}
+
+	_, _ = el.sinks.Set(func(i []eventSink, _ bool) ([]eventSink, error) {
+		return append(i, s), nil
+	})
+}
+
+// CloseSinks closes and removes all the EventSinks.
+func (el *EventLogging) CloseSinks() error {
+	var sinks []eventSink
+
+	_, _ = el.sinks.Set(func(i []eventSink, _ bool) ([]eventSink, error) {
+		sinks = i
+
+		return nil, nil
+	})
+
+	var err error
+
+	for i := range sinks {
+		if cerr := sinks[i].sink.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+
+	return err
+}
+
+// Clean removes the events added before the given time.
+func (el *EventLogging) Clean(before time.Time) (int, error) {
+	return el.db.clean(before.UnixNano())
+}
+
+func (el *EventLogging) writeSinks(name EventLoggerName, b []byte) {
+	sinks, _ := el.sinks.Value()
+	if len(sinks) < 1 {
+		return
+	}
+
+	c := make([]byte, len(b))
+	copy(c, b)
+
+	for i := range sinks {
+		if sinks[i].names != nil {
+			if _, found := sinks[i].names[name]; !found {
+				continue
+			}
+		}
+
+		_ = sinks[i].sink.Write(name, c)
+	}
+}
+
 func (el *EventLogging) Register(name EventLoggerName) (l zerolog.Logger, _ error) {
 	if name == AllEventLogger {
 		return l, errors.Errorf("%q, already registered", name)
@@ -172,11 +255,13 @@ func (el *EventLogging) IterName(
 func (el *EventLogging) newLogger(name EventLoggerName) zerolog.Logger {
 	prefix := EventNameKeyPrefix(name)
 
-	var w io.Writer = newEventWriter(prefix, el.db)
+	ws := []io.Writer{newEventWriter(prefix, el.db), eventSinkWriter{el: el, name: name}}
 	if el.w != nil {
-		w = zerolog.MultiLevelWriter(w, el.w)
+		ws = append(ws, el.w)
 	}
 
+	w := zerolog.MultiLevelWriter(ws...)
+
 	return zerolog.New(w).With().
 		Timestamp().
 		Caller().
@@ -258,6 +343,60 @@ func (db *eventDatabase) put(prefix [32]byte, b []byte) error {
 	return st.Batch(batch, nil)
 }
 
+func (db *eventDatabase) clean(before int64) (int, error) {
+	var st *leveldbstorage.PrefixStorage
+
+	switch i, err := db.st(); {
+	case err != nil:
+		return 0, err
+	default:
+		st = i
+	}
+
+	r := &leveldbutil.Range{
+		Start: db.keyPrefixAll[:],
+		Limit: util.ConcatBytesSlice(db.keyPrefixAll[:], util.Int64ToBytes(before)),
+	}
+
+	batch := st.NewBatch()
+	defer batch.Reset()
+
+	var removed int
+
+	flush := func() error {
+		if batch.Len() < 1 {
+			return nil
+		}
+
+		if err := st.Batch(batch, nil); err != nil {
+			return err
+		}
+
+		batch.Reset()
+
+		return nil
+	}
+
+	if err := st.Iter(r, func(key, nk []byte) (bool, error) {
+		batch.Delete(key)
+		batch.Delete(nk)
+
+		removed++
+
+		if removed%333 == 0 { //nolint:gomnd //...
+			if err := flush(); err != nil {
+				return false, err
+			}
+		}
+
+		return true, nil
+	}, true); err != nil {
+		return removed, err
+	}
+
+	return removed, flush()
+}
+
 func (db *eventDatabase) iterAll(
 	offsets [2]int64, // [2]int64{start unix nano, end unix nano}
 	callback func(addedAt time.Time, offset int64, raw []byte) (bool, error),
@@ -386,6 +525,17 @@ func (w *eventWriter) Write(b []byte) (int, error) {
 	return len(b), nil
 }
 
+type eventSinkWriter struct {
+	el   *EventLogging
+	name EventLoggerName
+}
+
+func (w eventSinkWriter) Write(b []byte) (int, error) {
+	w.el.writeSinks(w.name, b)
+
+	return len(b), nil
+}
+
 func EventNameKeyPrefix(name EventLoggerName) [32]byte {
 	return [32]byte(valuehash.NewSHA256([]byte(name)).Bytes())
 }
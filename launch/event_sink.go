@@ -0,0 +1,753 @@
+package launch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/localtime"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/ProtoconNet/mitum2/util/ps"
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	DefaultFileEventSinkMaxSize       int64 = 1 << 27 //nolint:gomnd // 128MiB
+	DefaultFileEventSinkMaxFiles            = 5
+	DefaultWebhookEventSinkRetry            = 3
+	defaultWebhookEventSinkQueue            = 1 << 10
+	defaultWebhookEventSinkTimeout          = time.Second * 3
+	defaultWebhookEventSinkBackoff          = time.Millisecond * 500
+	defaultWebhookEventSinkMaxBackoff       = time.Second * 30
+	defaultUnixSocketEventSinkTimeout       = time.Second
+	defaultUnixSocketEventSinkQueue         = 1 << 8
+	defaultSyslogEventSinkTag               = "mitum"
+	defaultSyslogEventSinkQueue             = 1 << 10
+	defaultSyslogEventSinkTimeout           = time.Second * 3
+)
+
+// EventSink receives the events of EventLogging in real time. Write should not
+// be blocked long.
+type EventSink interface {
+	Write(name EventLoggerName, b []byte) error
+	Close() error
+}
+
+// NewEventSinkFromString returns the EventSink from the sink string;
+//   - "file:///a/b/events.json?max_size=1048576&max_files=3": FileEventSink
+//   - "syslog://localhost:514?tag=mitum", "syslog+tcp://localhost:514" or
+//     "syslog:///dev/log": SyslogEventSink
+//   - "https://localhost/events?retry=5": WebhookEventSink
+//   - "unix:///tmp/events.sock": UnixSocketEventSink
+func NewEventSinkFromString(s string) (EventSink, error) {
+	e := util.StringError("event sink")
+
+	u, err := parseEventSinkString(s)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	var sink EventSink
+
+	switch u.Scheme {
+	case "file":
+		maxsize, maxfiles, _ := fileEventSinkQuery(u)
+
+		sink, err = NewFileEventSink(u.Path, maxsize, maxfiles)
+	case "syslog", "syslog+tcp":
+		sink, err = NewSyslogEventSink(u)
+	case "http", "https":
+		retry, _ := webhookEventSinkQuery(u)
+
+		sink = NewWebhookEventSink(u, nil, retry)
+	case "unix":
+		sink, err = NewUnixSocketEventSink(u.Path)
+	}
+
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	return sink, nil
+}
+
+func parseEventSinkString(s string) (*url.URL, error) {
+	if len(strings.TrimSpace(s)) < 1 {
+		return nil, errors.Errorf("empty")
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	switch u.Scheme {
+	case "file", "unix":
+		if len(u.Path) < 1 {
+			return nil, errors.Errorf("empty path")
+		}
+
+		if u.Scheme == "file" {
+			if _, _, err := fileEventSinkQuery(u); err != nil {
+				return nil, err
+			}
+		}
+	case "syslog", "syslog+tcp":
+		if len(u.Host) < 1 && len(u.Path) < 1 {
+			return nil, errors.Errorf("empty host or path")
+		}
+	case "http", "https":
+		if len(u.Host) < 1 {
+			return nil, errors.Errorf("empty host")
+		}
+
+		if _, err := webhookEventSinkQuery(u); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Errorf("unsupported scheme, %q", u.Scheme)
+	}
+
+	return u, nil
+}
+
+func fileEventSinkQuery(u *url.URL) (maxsize int64, maxfiles int, _ error) {
+	maxsize, maxfiles = DefaultFileEventSinkMaxSize, DefaultFileEventSinkMaxFiles
+
+	if s := u.Query().Get("max_size"); len(s) > 0 {
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || i < 1 {
+			return 0, 0, errors.Errorf("invalid max_size, %q", s)
+		}
+
+		maxsize = i
+	}
+
+	if s := u.Query().Get("max_files"); len(s) > 0 {
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || i < 1 {
+			return 0, 0, errors.Errorf("invalid max_files, %q", s)
+		}
+
+		maxfiles = int(i)
+	}
+
+	return maxsize, maxfiles, nil
+}
+
+func webhookEventSinkQuery(u *url.URL) (int, error) {
+	s := u.Query().Get("retry")
+	if len(s) < 1 {
+		return DefaultWebhookEventSinkRetry, nil
+	}
+
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || i < 0 {
+		return 0, errors.Errorf("invalid retry, %q", s)
+	}
+
+	return int(i), nil
+}
+
+// FileEventSink appends the events to file in json lines. If file size is
+// over maxsize, file is rotated; the old files are kept up to maxfiles with the
+// ".<n>" suffix.
+type FileEventSink struct {
+	f        *os.File
+	p        string
+	size     int64
+	maxsize  int64
+	maxfiles int
+	sync.Mutex
+}
+
+func NewFileEventSink(p string, maxsize int64, maxfiles int) (*FileEventSink, error) {
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	sink := &FileEventSink{p: filepath.Clean(p), maxsize: maxsize, maxfiles: maxfiles}
+
+	if err := sink.open(); err != nil {
+		return nil, err
+	}
+
+	return sink, nil
+}
+
+func (sink *FileEventSink) Write(_ EventLoggerName, b []byte) error {
+	sink.Lock()
+	defer sink.Unlock()
+
+	if sink.f == nil {
+		return errors.Errorf("closed")
+	}
+
+	if sink.size > 0 && sink.size+int64(len(b)) > sink.maxsize {
+		if err := sink.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := sink.f.Write(b)
+	sink.size += int64(n)
+
+	return errors.WithStack(err)
+}
+
+func (sink *FileEventSink) Close() error {
+	sink.Lock()
+	defer sink.Unlock()
+
+	if sink.f == nil {
+		return nil
+	}
+
+	err := sink.f.Close()
+	sink.f = nil
+
+	return errors.WithStack(err)
+}
+
+func (sink *FileEventSink) open() error {
+	f, err := os.OpenFile(sink.p, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+
+		return errors.WithStack(err)
+	}
+
+	sink.f = f
+	sink.size = fi.Size()
+
+	return nil
+}
+
+func (sink *FileEventSink) rotate() error {
+	if err := sink.f.Close(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	sink.f = nil
+
+	name := func(i int) string {
+		return fmt.Sprintf("%s.%d", sink.p, i)
+	}
+
+	if err := os.Remove(name(sink.maxfiles)); err != nil && !os.IsNotExist(err) {
+		return errors.WithStack(err)
+	}
+
+	for i := sink.maxfiles - 1; i > 0; i-- {
+		if err := os.Rename(name(i), name(i+1)); err != nil && !os.IsNotExist(err) {
+			return errors.WithStack(err)
+		}
+	}
+
+	if err := os.Rename(sink.p, name(1)); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return sink.open()
+}
+
+// SyslogEventSink sends the events to syslog in RFC 3164 format with the
+// local0 facility. The events are queued and sent in background; if the queue
+// is full, the new event is dropped.
+type SyslogEventSink struct {
+	conn     net.Conn
+	queue    *eventSinkQueue
+	network  string
+	addr     string
+	tag      string
+	hostname string
+}
+
+func NewSyslogEventSink(u *url.URL) (*SyslogEventSink, error) {
+	sink := &SyslogEventSink{tag: defaultSyslogEventSinkTag}
+
+	if s := u.Query().Get("tag"); len(s) > 0 {
+		sink.tag = s
+	}
+
+	switch {
+	case len(u.Host) < 1:
+		sink.network, sink.addr = "unixgram", u.Path
+	case u.Scheme == "syslog+tcp":
+		sink.network, sink.addr = "tcp", u.Host
+	default:
+		sink.network, sink.addr = "udp", u.Host
+	}
+
+	sink.hostname, _ = os.Hostname()
+
+	if err := sink.connect(); err != nil {
+		return nil, err
+	}
+
+	sink.queue = newEventSinkQueue(defaultSyslogEventSinkQueue, sink.send)
+
+	return sink, nil
+}
+
+func (sink *SyslogEventSink) Write(_ EventLoggerName, b []byte) error {
+	msg := fmt.Sprintf("<%d>%s %s %s[%d]: %s",
+		16*8+6, //nolint:gomnd // local0.info
+		time.Now().Format(time.Stamp),
+		sink.hostname,
+		sink.tag,
+		os.Getpid(),
+		bytes.TrimRight(b, "\n"),
+	)
+
+	if sink.network == "tcp" {
+		msg += "\n"
+	}
+
+	return sink.queue.push([]byte(msg))
+}
+
+func (sink *SyslogEventSink) Close() error {
+	sink.queue.close()
+
+	if sink.conn == nil {
+		return nil
+	}
+
+	err := sink.conn.Close()
+	sink.conn = nil
+
+	return errors.WithStack(err)
+}
+
+// send is only called by the queue, so conn is not shared.
+func (sink *SyslogEventSink) send(_ context.Context, b []byte) {
+	if sink.conn == nil {
+		if err := sink.connect(); err != nil {
+			return
+		}
+	}
+
+	_ = sink.conn.SetWriteDeadline(time.Now().Add(defaultSyslogEventSinkTimeout))
+
+	if _, err := sink.conn.Write(b); err != nil {
+		_ = sink.conn.Close()
+		sink.conn = nil
+	}
+}
+
+func (sink *SyslogEventSink) connect() error {
+	conn, err := net.DialTimeout(sink.network, sink.addr, defaultSyslogEventSinkTimeout)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	sink.conn = conn
+
+	return nil
+}
+
+// WebhookEventSink posts each event to the url. The events are queued; if
+// post fails, it retries with exponential backoff. If the queue is full, the
+// new event is dropped.
+type WebhookEventSink struct {
+	client *http.Client
+	u      *url.URL
+	queue  *eventSinkQueue
+	retry  int
+}
+
+func NewWebhookEventSink(u *url.URL, client *http.Client, retry int) *WebhookEventSink {
+	if client == nil {
+		client = http.DefaultClient //revive:disable-line:modifies-parameter
+	}
+
+	sink := &WebhookEventSink{
+		client: client,
+		u:      u,
+		retry:  retry,
+	}
+
+	sink.queue = newEventSinkQueue(defaultWebhookEventSinkQueue, func(ctx context.Context, b []byte) {
+		_ = sink.send(ctx, b)
+	})
+
+	return sink
+}
+
+func (sink *WebhookEventSink) Write(_ EventLoggerName, b []byte) error {
+	return sink.queue.push(b)
+}
+
+func (sink *WebhookEventSink) Close() error {
+	sink.queue.close()
+
+	return nil
+}
+
+func (sink *WebhookEventSink) send(ctx context.Context, b []byte) error {
+	backoff := defaultWebhookEventSinkBackoff
+
+	var err error
+
+	for i := 0; i <= sink.retry; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return errors.WithStack(ctx.Err())
+			case <-time.After(backoff):
+			}
+
+			backoff *= 2
+
+			if backoff > defaultWebhookEventSinkMaxBackoff {
+				backoff = defaultWebhookEventSinkMaxBackoff
+			}
+		}
+
+		if err = sink.post(ctx, b); err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+func (sink *WebhookEventSink) post(ctx context.Context, b []byte) error {
+	pctx, cancel := context.WithTimeout(ctx, defaultWebhookEventSinkTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(pctx, http.MethodPost, sink.u.String(), bytes.NewReader(b))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := sink.client.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return errors.Errorf("unexpected status code, %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+// UnixSocketEventSink listens the unix socket and sends the events to the
+// connected clients in json lines. Each client has it's own queue; the slow
+// client, which can not empty the queue, is disconnected.
+type UnixSocketEventSink struct {
+	l     net.Listener
+	conns map[net.Conn]chan []byte
+	p     string
+	sync.Mutex
+}
+
+func NewUnixSocketEventSink(p string) (*UnixSocketEventSink, error) {
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return nil, errors.WithStack(err)
+	}
+
+	l, err := net.Listen("unix", p)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	sink := &UnixSocketEventSink{l: l, p: p, conns: map[net.Conn]chan []byte{}}
+
+	go sink.accept()
+
+	return sink, nil
+}
+
+func (sink *UnixSocketEventSink) Write(_ EventLoggerName, b []byte) error {
+	sink.Lock()
+	defer sink.Unlock()
+
+	for conn := range sink.conns {
+		select {
+		case sink.conns[conn] <- b:
+		default:
+			sink.removeConn(conn)
+		}
+	}
+
+	return nil
+}
+
+func (sink *UnixSocketEventSink) Close() error {
+	err := sink.l.Close()
+
+	sink.Lock()
+	defer sink.Unlock()
+
+	for conn := range sink.conns {
+		sink.removeConn(conn)
+	}
+
+	_ = os.Remove(sink.p)
+
+	return errors.WithStack(err)
+}
+
+func (sink *UnixSocketEventSink) accept() {
+	for {
+		conn, err := sink.l.Accept()
+		if err != nil {
+			return
+		}
+
+		queue := make(chan []byte, defaultUnixSocketEventSinkQueue)
+
+		sink.Lock()
+		sink.conns[conn] = queue
+		sink.Unlock()
+
+		go sink.send(conn, queue)
+	}
+}
+
+func (sink *UnixSocketEventSink) send(conn net.Conn, queue chan []byte) {
+	for b := range queue {
+		_ = conn.SetWriteDeadline(time.Now().Add(defaultUnixSocketEventSinkTimeout))
+
+		if _, err := conn.Write(b); err != nil {
+			sink.Lock()
+			sink.removeConn(conn)
+			sink.Unlock()
+
+			return
+		}
+	}
+}
+
+// removeConn should be called under lock.
+func (sink *UnixSocketEventSink) removeConn(conn net.Conn) {
+	queue, found := sink.conns[conn]
+	if !found {
+		return
+	}
+
+	_ = conn.Close()
+
+	close(queue)
+
+	delete(sink.conns, conn)
+}
+
+// eventSinkQueue calls f with the queued events in background. If the queue
+// is full, the new event is dropped.
+type eventSinkQueue struct {
+	queue  chan []byte
+	cancel func()
+	donech chan struct{}
+}
+
+func newEventSinkQueue(size int, f func(context.Context, []byte)) *eventSinkQueue {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	q := &eventSinkQueue{
+		queue:  make(chan []byte, size),
+		cancel: cancel,
+		donech: make(chan struct{}),
+	}
+
+	go func() {
+		defer close(q.donech)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case b := <-q.queue:
+				f(ctx, b)
+			}
+		}
+	}()
+
+	return q
+}
+
+func (q *eventSinkQueue) push(b []byte) error {
+	select {
+	case q.queue <- b:
+		return nil
+	default:
+		return errors.Errorf("queue full")
+	}
+}
+
+func (q *eventSinkQueue) close() {
+	q.cancel()
+
+	<-q.donech
+}
+
+var (
+	PNameStartEventLogging          = ps.Name("start-event-logging")
+	EventLoggingRetentionContextKey = util.ContextKey("event-logging-retention")
+)
+
+var DefaultEventRetentionInterval = time.Minute * 10
+
+type NodeEventsDesign struct {
+	Sinks     []EventSinkDesign    `json:"sinks,omitempty" yaml:"sinks,omitempty"`
+	Retention EventRetentionDesign `json:"retention,omitempty" yaml:"retention,omitempty"`
+}
+
+// EventSinkDesign is the sink string of NewEventSinkFromString with the event
+// logger names; if Loggers is empty, sink receives all events.
+type EventSinkDesign struct {
+	URI     string            `json:"uri" yaml:"uri"`
+	Loggers []EventLoggerName `json:"loggers,omitempty" yaml:"loggers,omitempty"`
+}
+
+// EventRetentionDesign removes the events older than MaxAge in every Interval;
+// zero MaxAge keeps events forever.
+type EventRetentionDesign struct {
+	MaxAge   util.ReadableDuration `json:"max_age,omitempty" yaml:"max_age,omitempty"`
+	Interval util.ReadableDuration `json:"interval,omitempty" yaml:"interval,omitempty"`
+}
+
+func (d *NodeEventsDesign) IsValid([]byte) error {
+	e := util.ErrInvalid.Errorf("invalid NodeEventsDesign")
+
+	for i := range d.Sinks {
+		if _, err := parseEventSinkString(d.Sinks[i].URI); err != nil {
+			return e.WithMessage(err, "sink, %q", d.Sinks[i].URI)
+		}
+
+		for j := range d.Sinks[i].Loggers {
+			if !slices.Contains(AllEventLoggerNames, d.Sinks[i].Loggers[j]) {
+				return e.Errorf("unknown event logger, %q", d.Sinks[i].Loggers[j])
+			}
+		}
+	}
+
+	switch {
+	case d.Retention.MaxAge < 0:
+		return e.Errorf("wrong retention max_age")
+	case d.Retention.Interval < 0:
+		return e.Errorf("wrong retention interval")
+	case d.Retention.MaxAge > 0 && d.Retention.Interval < 1:
+		d.Retention.Interval = util.ReadableDuration(DefaultEventRetentionInterval)
+	}
+
+	return nil
+}
+
+func PStartEventLogging(pctx context.Context) (context.Context, error) {
+	e := util.StringError("start event logging")
+
+	var log *logging.Logging
+	var design NodeDesign
+	var eventLogging *EventLogging
+
+	if err := util.LoadFromContextOK(pctx,
+		LoggingContextKey, &log,
+		DesignContextKey, &design,
+		EventLoggingContextKey, &eventLogging,
+	); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	for i := range design.Events.Sinks {
+		d := design.Events.Sinks[i]
+
+		sink, err := NewEventSinkFromString(d.URI)
+		if err != nil {
+			_ = eventLogging.CloseSinks()
+
+			return pctx, e.Wrap(err)
+		}
+
+		eventLogging.AddSink(sink, d.Loggers...)
+
+		log.Log().Debug().Str("sink", d.URI).Interface("loggers", d.Loggers).Msg("event sink added")
+	}
+
+	maxAge := time.Duration(design.Events.Retention.MaxAge)
+	if maxAge < 1 {
+		return pctx, nil
+	}
+
+	interval := time.Duration(design.Events.Retention.Interval)
+
+	retention := util.NewContextDaemon(func(ctx context.Context) error {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+				switch removed, err := eventLogging.Clean(localtime.Now().Add(-maxAge)); {
+				case err != nil:
+					log.Log().Error().Err(err).Msg("failed to clean old events")
+				case removed > 0:
+					log.Log().Debug().Int("removed", removed).Msg("old events cleaned")
+				}
+			}
+		}
+	})
+
+	if err := retention.Start(context.Background()); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	return context.WithValue(pctx, EventLoggingRetentionContextKey, retention), nil
+}
+
+func PCloseEventLogging(pctx context.Context) (context.Context, error) {
+	var retention *util.ContextDaemon
+	var eventLogging *EventLogging
+
+	if err := util.LoadFromContext(pctx,
+		EventLoggingRetentionContextKey, &retention,
+		EventLoggingContextKey, &eventLogging,
+	); err != nil {
+		return pctx, err
+	}
+
+	if retention != nil {
+		if err := retention.Stop(); err != nil && !errors.Is(err, util.ErrDaemonAlreadyStopped) {
+			return pctx, err
+		}
+	}
+
+	if eventLogging != nil {
+		if err := eventLogging.CloseSinks(); err != nil {
+			return pctx, err
+		}
+	}
+
+	return pctx, nil
+}
@@ -0,0 +1,268 @@
+package launch
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+)
+
+type testEventSink struct {
+	suite.Suite
+}
+
+func (t *testEventSink) TestFromString() {
+	cases := []struct {
+		name string
+		s    string
+		err  string
+	}{
+		{name: "file", s: "file:///a/b/events.json?max_size=33&max_files=3"},
+		{name: "file wrong max_size", s: "file:///a/b/events.json?max_size=a", err: "invalid max_size"},
+		{name: "syslog", s: "syslog://localhost:514?tag=showme"},
+		{name: "syslog unix", s: "syslog:///dev/log"},
+		{name: "webhook", s: "https://localhost/events?retry=1"},
+		{name: "webhook wrong retry", s: "https://localhost/events?retry=-1", err: "invalid retry"},
+		{name: "unix", s: "unix:///tmp/events.sock"},
+		{name: "empty unix path", s: "unix://", err: "empty path"},
+		{name: "unknown scheme", s: "ftp://localhost/a", err: "unsupported scheme"},
+		{name: "empty", s: "", err: "empty"},
+	}
+
+	for i, c := range cases {
+		i := i
+		c := c
+
+		t.Run(c.name, func() {
+			_, err := parseEventSinkString(c.s)
+
+			if len(c.err) > 0 {
+				t.Error(err, "%d: %v", i, c.name)
+				t.ErrorContains(err, c.err, "%d: %v", i, c.name)
+
+				return
+			}
+
+			t.NoError(err, "%d: %v", i, c.name)
+		})
+	}
+}
+
+func (t *testEventSink) TestFileRotate() {
+	p := filepath.Join(t.T().TempDir(), "events.json")
+
+	sink, err := NewFileEventSink(p, 10, 2)
+	t.NoError(err)
+
+	for _, s := range []string{"000000\n", "111111\n", "222222\n", "333333\n"} {
+		t.NoError(sink.Write("a", []byte(s)))
+	}
+
+	t.NoError(sink.Close())
+
+	read := func(p string) string {
+		b, err := os.ReadFile(p)
+		t.NoError(err)
+
+		return string(b)
+	}
+
+	t.Equal("333333\n", read(p))
+	t.Equal("222222\n", read(p+".1"))
+	t.Equal("111111\n", read(p+".2"))
+
+	_, err = os.Stat(p + ".3")
+	t.True(os.IsNotExist(err))
+}
+
+func (t *testEventSink) TestWebhookRetry() {
+	var count int64
+
+	received := make(chan string, 1)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt64(&count, 1) < 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		b, _ := io.ReadAll(r.Body)
+
+		received <- string(b)
+	}))
+	defer ts.Close()
+
+	sink, err := NewEventSinkFromString(ts.URL + "/events?retry=2")
+	t.NoError(err)
+
+	defer sink.Close()
+
+	t.NoError(sink.Write("a", []byte(`{"a":1}`)))
+
+	select {
+	case <-time.After(time.Second * 3):
+		t.Fail("failed to wait event")
+	case s := <-received:
+		t.Equal(`{"a":1}`, s)
+		t.Equal(int64(2), atomic.LoadInt64(&count))
+	}
+}
+
+func (t *testEventSink) TestUnixSocket() {
+	p := filepath.Join(t.T().TempDir(), "events.sock")
+
+	sink, err := NewUnixSocketEventSink(p)
+	t.NoError(err)
+
+	conn, err := net.Dial("unix", p)
+	t.NoError(err)
+
+	defer conn.Close()
+
+	t.Eventually(func() bool {
+		sink.Lock()
+		defer sink.Unlock()
+
+		return len(sink.conns) == 1
+	}, time.Second, time.Millisecond*10)
+
+	t.NoError(sink.Write("a", []byte("{\"a\":1}\n")))
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	t.NoError(err)
+	t.Equal("{\"a\":1}\n", line)
+
+	t.NoError(sink.Close())
+
+	_, err = os.Stat(p)
+	t.True(os.IsNotExist(err))
+}
+
+func (t *testEventSink) TestUnixSocketSlowClient() {
+	p := filepath.Join(t.T().TempDir(), "events.sock")
+
+	sink, err := NewUnixSocketEventSink(p)
+	t.NoError(err)
+
+	defer sink.Close()
+
+	conn, err := net.Dial("unix", p)
+	t.NoError(err)
+
+	defer conn.Close()
+
+	t.Eventually(func() bool {
+		sink.Lock()
+		defer sink.Unlock()
+
+		return len(sink.conns) == 1
+	}, time.Second, time.Millisecond*10)
+
+	b := bytes.Repeat([]byte("a"), 1<<16)
+
+	started := time.Now()
+
+	for i := 0; i < defaultUnixSocketEventSinkQueue*2; i++ {
+		t.NoError(sink.Write("a", b))
+	}
+
+	t.True(time.Since(started) < defaultUnixSocketEventSinkTimeout, "write should not be blocked")
+
+	t.Eventually(func() bool {
+		sink.Lock()
+		defer sink.Unlock()
+
+		return len(sink.conns) < 1
+	}, time.Second*3, time.Millisecond*10)
+}
+
+func (t *testEventSink) TestSyslog() {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	t.NoError(err)
+
+	defer pc.Close()
+
+	u, err := url.Parse("syslog://" + pc.LocalAddr().String() + "?tag=showme")
+	t.NoError(err)
+
+	sink, err := NewSyslogEventSink(u)
+	t.NoError(err)
+
+	defer sink.Close()
+
+	t.NoError(sink.Write("a", []byte("{\"a\":1}\n")))
+
+	_ = pc.SetReadDeadline(time.Now().Add(time.Second * 2))
+
+	buf := make([]byte, 1<<10)
+
+	n, _, err := pc.ReadFrom(buf)
+	t.NoError(err)
+
+	msg := string(buf[:n])
+	t.True(strings.HasPrefix(msg, "<134>"), msg)
+	t.Contains(msg, "showme[")
+	t.True(strings.HasSuffix(msg, ": {\"a\":1}"), msg)
+
+	t.Run("queue full", func() {
+		sink.queue.cancel()
+		<-sink.queue.donech
+
+		var err error
+
+		for i := 0; i < defaultSyslogEventSinkQueue+1; i++ {
+			if err = sink.Write("a", []byte("{}")); err != nil {
+				break
+			}
+		}
+
+		t.Error(err)
+		t.ErrorContains(err, "queue full")
+	})
+}
+
+func (t *testEventSink) TestDesign() {
+	t.Run("ok", func() {
+		var d NodeEventsDesign
+		t.NoError(yaml.Unmarshal([]byte(`
+sinks:
+  - uri: file:///tmp/events.json
+    loggers: [node, acl]
+  - uri: unix:///tmp/events.sock
+retention:
+  max_age: 72h
+`), &d))
+
+		t.NoError(d.IsValid(nil))
+
+		t.Equal(2, len(d.Sinks))
+		t.Equal([]EventLoggerName{NodeEventLogger, ACLEventLogger}, d.Sinks[0].Loggers)
+		t.Equal(time.Hour*72, time.Duration(d.Retention.MaxAge))
+		t.Equal(DefaultEventRetentionInterval, time.Duration(d.Retention.Interval))
+	})
+
+	t.Run("unknown logger", func() {
+		d := NodeEventsDesign{Sinks: []EventSinkDesign{{URI: "unix:///tmp/a.sock", Loggers: []EventLoggerName{"showme"}}}}
+
+		err := d.IsValid(nil)
+		t.Error(err)
+		t.ErrorContains(err, "unknown event logger")
+	})
+}
+
+func TestEventSink(t *testing.T) {
+	suite.Run(t, new(testEventSink))
+}
@@ -3,6 +3,7 @@ package launch
 import (
 	"os"
 	"sort"
+	"sync"
 	"testing"
 	"time"
 
@@ -270,6 +271,107 @@ func (t *testEventLogging) TestIterNameLimit() {
 	})
 }
 
+func (t *testEventLogging) TestClean() {
+	el := newEventLoggingWithStorage(t.st, nil)
+
+	name := EventLoggerName(util.UUID().String())
+	_, err := el.Register(name)
+	t.NoError(err)
+
+	l, _ := el.Logger(name)
+
+	for i := range make([]int, 3) {
+		l.Info().Int("i", i).Msg("old")
+	}
+
+	before := time.Now()
+
+	for i := range make([]int, 2) {
+		l.Info().Int("i", i+3).Msg("new")
+	}
+
+	removed, err := el.Clean(before)
+	t.NoError(err)
+	t.Equal(3, removed)
+
+	rs := t.iterValues(el, name)
+	t.Equal(2, len(rs))
+	t.Equal(float64(3), rs[0]["i"])
+
+	var all int
+
+	t.NoError(el.Iter([2]int64{}, func(time.Time, int64, []byte) (bool, error) {
+		all++
+
+		return true, nil
+	}, true, 333))
+	t.Equal(2, all)
+}
+
+type dummyEventSink struct {
+	events map[EventLoggerName][]string
+	sync.Mutex
+	closed bool
+}
+
+func (s *dummyEventSink) Write(name EventLoggerName, b []byte) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.events == nil {
+		s.events = map[EventLoggerName][]string{}
+	}
+
+	s.events[name] = append(s.events[name], string(b))
+
+	return nil
+}
+
+func (s *dummyEventSink) Close() error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.closed = true
+
+	return nil
+}
+
+func (t *testEventLogging) TestSinks() {
+	el := newEventLoggingWithStorage(t.st, nil)
+
+	a, b := EventLoggerName("a"), EventLoggerName("b")
+
+	for _, name := range []EventLoggerName{a, b} {
+		_, err := el.Register(name)
+		t.NoError(err)
+	}
+
+	allsink := &dummyEventSink{}
+	asink := &dummyEventSink{}
+
+	el.AddSink(allsink)
+	el.AddSink(asink, a)
+
+	la, _ := el.Logger(a)
+	lb, _ := el.Logger(b)
+
+	la.Info().Str("showme", "a").Msg("a")
+	lb.Info().Str("showme", "b").Msg("b")
+
+	t.Equal(1, len(allsink.events[a]))
+	t.Equal(1, len(allsink.events[b]))
+	t.Equal(1, len(asink.events[a]))
+	t.Empty(asink.events[b])
+	t.Contains(asink.events[a][0], `"showme":"a"`)
+
+	t.NoError(el.CloseSinks())
+	t.True(allsink.closed)
+	t.True(asink.closed)
+
+	la.Info().Msg("after closed")
+	t.Equal(1, len(asink.events[a]))
+}
+
 func TestEventLogging(t *testing.T) {
 	suite.Run(t, new(testEventLogging))
 }
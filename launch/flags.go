@@ -294,6 +294,10 @@ func (f *SecretFlag) UnmarshalText(b []byte) error {
 	}
 
 	switch u.Scheme {
+	case "keystore": // NOTE keystore is decrypted when it is loaded
+		f.body = []byte(f.s)
+
+		return nil
 	case "file", "":
 		switch fi, err := os.Stat(u.Path); {
 		case err != nil:
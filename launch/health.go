@@ -0,0 +1,353 @@
+package launch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	isaacdatabase "github.com/ProtoconNet/mitum2/isaac/database"
+	isaacnetwork "github.com/ProtoconNet/mitum2/isaac/network"
+	isaacstates "github.com/ProtoconNet/mitum2/isaac/states"
+	"github.com/ProtoconNet/mitum2/network/quicmemberlist"
+	"github.com/ProtoconNet/mitum2/network/quicstream"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/localtime"
+	"github.com/ProtoconNet/mitum2/util/ps"
+)
+
+var (
+	PNamePatchHealth = ps.Name("patch-health")
+	HealthContextKey = util.ContextKey("health")
+)
+
+var defaultHealthSyncSourcesCacheExpire = time.Second * 3
+
+type healthDaemon interface {
+	IsStarted() bool
+}
+
+// HealthCheck is the result of one check.
+type HealthCheck struct {
+	Message string `json:"message,omitempty"`
+	OK      bool   `json:"ok"`
+}
+
+// HealthReport is the response of liveness and readiness.
+type HealthReport struct {
+	Checks map[string]HealthCheck `json:"checks"`
+	OK     bool                   `json:"ok"`
+}
+
+func (r *HealthReport) add(name string, ok bool, format string, args ...interface{}) {
+	r.Checks[name] = HealthCheck{OK: ok, Message: fmt.Sprintf(format, args...)}
+
+	if !ok {
+		r.OK = false
+	}
+}
+
+// Health reports the liveness and readiness of node. The liveness checks the
+// daemons and BROKEN state; the readiness checks the liveness, the sync lag
+// and the elapsed time since the last block. The sync lag is the difference
+// between the last height of sync sources and the local last block height.
+// The node, which is not in suffrage, is ready in SYNCING state, if the sync
+// lag is within bounds.
+type Health struct {
+	daemons               map[string]healthDaemon
+	lastBlockMap          func() (base.BlockMap, bool, error)
+	syncSourcesLastHeight func() (base.Height, bool, error)
+	isInSuffrage          func() (bool, error)
+	params                *MISCParams
+	synced                *util.Locked[[2]interface{}]
+	state                 isaacstates.StateType
+	sync.RWMutex
+}
+
+func NewHealth() *Health {
+	return &Health{
+		state:  isaacstates.StateEmpty,
+		synced: util.EmptyLocked[[2]interface{}](),
+	}
+}
+
+// SetComponents sets the components to be checked; before SetComponents,
+// node is not alive.
+func (h *Health) SetComponents(
+	lastBlockMap func() (base.BlockMap, bool, error),
+	params *MISCParams,
+	daemons map[string]healthDaemon,
+) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.lastBlockMap = lastBlockMap
+	h.params = params
+	h.daemons = daemons
+}
+
+// SetSyncSources sets the functions for the sync lag; syncSourcesLastHeight
+// returns the highest last height of sync sources and isInSuffrage checks
+// whether local is in the last suffrage.
+func (h *Health) SetSyncSources(
+	syncSourcesLastHeight func() (base.Height, bool, error),
+	isInSuffrage func() (bool, error),
+) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.syncSourcesLastHeight = syncSourcesLastHeight
+	h.isInSuffrage = isInSuffrage
+
+	h.synced.EmptyValue()
+}
+
+func (h *Health) StateSwitched(next isaacstates.StateType) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.state = next
+}
+
+func (h *Health) Liveness() HealthReport {
+	h.RLock()
+	defer h.RUnlock()
+
+	return h.liveness()
+}
+
+func (h *Health) Readiness() HealthReport {
+	// NOTE the slow functions, like the last height of sync sources, are
+	// called without lock.
+	h.RLock()
+	r := h.liveness()
+	state, params := h.state, h.params
+	lastBlockMap, syncSourcesLastHeight, isInSuffrage := h.lastBlockMap, h.syncSourcesLastHeight, h.isInSuffrage
+	h.RUnlock()
+
+	if lastBlockMap == nil {
+		return r
+	}
+
+	switch state {
+	case isaacstates.StateConsensus, isaacstates.StateHandover:
+		r.add("state", true, "%s", state)
+	case isaacstates.StateSyncing:
+		// NOTE the node, which is not in suffrage, keeps syncing.
+		var insuf bool
+
+		if isInSuffrage != nil {
+			i, err := isInSuffrage()
+			if err != nil {
+				r.add("state", false, "%s; %v", state, err)
+
+				break
+			}
+
+			insuf = i
+		}
+
+		r.add("state", !insuf, "%s; in_suffrage=%v", state, insuf)
+	default:
+		r.add("state", false, "%s", state)
+	}
+
+	var last base.Manifest
+
+	switch m, found, err := lastBlockMap(); {
+	case err != nil:
+		r.add("last_block", false, "%v", err)
+
+		return r
+	case !found:
+		r.add("last_block", false, "empty block")
+
+		return r
+	default:
+		last = m.Manifest()
+	}
+
+	switch height, found, err := h.lastHeightOfSyncSources(syncSourcesLastHeight); {
+	case err != nil:
+		r.add("sync_lag", false, "%v", err)
+	case !found:
+		r.add("sync_lag", true, "no sync sources")
+	case height <= last.Height():
+		r.add("sync_lag", true, "0")
+	default:
+		lag := uint64(height - last.Height())
+
+		r.add("sync_lag", lag <= params.HealthMaxSyncLag(), "%d", lag)
+	}
+
+	elapsed := localtime.Now().UTC().Sub(last.ProposedAt())
+
+	r.add("last_block_elapsed", elapsed <= params.HealthMaxLastBlockElapsed(),
+		"%s; height=%d", elapsed.Truncate(time.Millisecond), last.Height())
+
+	return r
+}
+
+// lastHeightOfSyncSources caches the last height of sync sources for a while;
+// readiness can be requested frequently.
+func (h *Health) lastHeightOfSyncSources(
+	f func() (base.Height, bool, error),
+) (height base.Height, found bool, _ error) {
+	if f == nil {
+		return height, false, nil
+	}
+
+	i, err := h.synced.Set(func(i [2]interface{}, isempty bool) ([2]interface{}, error) {
+		if !isempty && localtime.Now().UTC().Sub(i[1].(time.Time)) < defaultHealthSyncSourcesCacheExpire { //nolint:forcetypeassert,lll //...
+			return i, util.ErrLockedSetIgnore
+		}
+
+		switch height, found, err := f(); {
+		case err != nil:
+			return i, err
+		case !found:
+			return [2]interface{}{base.NilHeight, localtime.Now().UTC()}, nil
+		default:
+			return [2]interface{}{height, localtime.Now().UTC()}, nil
+		}
+	})
+	if err != nil {
+		return height, false, err
+	}
+
+	height = i[0].(base.Height) //nolint:forcetypeassert //...
+
+	return height, height > base.NilHeight, nil
+}
+
+func (h *Health) liveness() HealthReport {
+	r := HealthReport{OK: true, Checks: map[string]HealthCheck{}}
+
+	if h.daemons == nil {
+		r.add("components", false, "not yet prepared")
+
+		return r
+	}
+
+	names := make([]string, len(h.daemons))
+
+	var i int
+
+	for name := range h.daemons {
+		names[i] = name
+		i++
+	}
+
+	sort.Strings(names)
+
+	for i := range names {
+		started := h.daemons[names[i]].IsStarted()
+
+		r.add(names[i], started, "started=%v", started)
+	}
+
+	r.add("broken", h.state != isaacstates.StateBroken, "%s", h.state)
+
+	return r
+}
+
+func (h *Health) ServeLiveness(w http.ResponseWriter, _ *http.Request) {
+	writeHealthReport(w, h.Liveness())
+}
+
+func (h *Health) ServeReadiness(w http.ResponseWriter, _ *http.Request) {
+	writeHealthReport(w, h.Readiness())
+}
+
+func writeHealthReport(w http.ResponseWriter, r HealthReport) {
+	b, err := util.MarshalJSON(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if !r.OK {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	_, _ = w.Write(b)
+}
+
+func PPatchHealth(pctx context.Context) (context.Context, error) {
+	e := util.StringError("patch health")
+
+	var h *Health
+
+	switch err := util.LoadFromContext(pctx, HealthContextKey, &h); {
+	case err != nil:
+		return pctx, e.Wrap(err)
+	case h == nil:
+		return pctx, nil
+	}
+
+	var local base.LocalNode
+	var params *LocalParams
+	var isaacparams *isaac.Params
+	var db *isaacdatabase.Center
+	var pool *isaacdatabase.TempPool
+	var memberlist *quicmemberlist.Memberlist
+	var server *quicstream.Server
+	var client *isaacnetwork.BaseClient
+	var syncSourcePool *isaac.SyncSourcePool
+	var sp *SuffragePool
+
+	if err := util.LoadFromContextOK(pctx,
+		LocalContextKey, &local,
+		LocalParamsContextKey, &params,
+		ISAACParamsContextKey, &isaacparams,
+		CenterDatabaseContextKey, &db,
+		PoolDatabaseContextKey, &pool,
+		MemberlistContextKey, &memberlist,
+		QuicstreamServerContextKey, &server,
+		QuicstreamClientContextKey, &client,
+		SyncSourcePoolContextKey, &syncSourcePool,
+		SuffragePoolContextKey, &sp,
+	); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	h.SetComponents(db.LastBlockMap, params.MISC, map[string]healthDaemon{
+		"database":   db,
+		"pool":       pool,
+		"memberlist": memberlist,
+		"quicstream": server,
+	})
+
+	lastBlockMapf := syncerLastBlockMapFunc(client, isaacparams, syncSourcePool, nil, nil)
+
+	h.SetSyncSources(
+		func() (base.Height, bool, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), params.Network.TimeoutRequest())
+			defer cancel()
+
+			switch m, found, err := lastBlockMapf(ctx, nil); {
+			case err != nil, !found:
+				return base.NilHeight, false, err
+			default:
+				return m.Manifest().Height(), true, nil
+			}
+		},
+		func() (bool, error) {
+			switch suf, found, err := sp.Last(); {
+			case err != nil, !found:
+				return false, err
+			default:
+				return suf.Exists(local.Address()), nil
+			}
+		},
+	)
+
+	return pctx, nil
+}
@@ -0,0 +1,192 @@
+package launch
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	isaacstates "github.com/ProtoconNet/mitum2/isaac/states"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type dummyHealthDaemon bool
+
+func (d dummyHealthDaemon) IsStarted() bool {
+	return bool(d)
+}
+
+type dummyHealthBlockMap struct {
+	base.BlockMap
+	m base.Manifest
+}
+
+func (m dummyHealthBlockMap) Manifest() base.Manifest {
+	return m.m
+}
+
+type testHealth struct {
+	suite.Suite
+}
+
+func (t *testHealth) lastBlockMap(height base.Height, proposedAt time.Time) func() (base.BlockMap, bool, error) {
+	m := isaac.NewManifest(
+		height,
+		valuehash.RandomSHA256(),
+		valuehash.RandomSHA256(),
+		nil,
+		nil,
+		valuehash.RandomSHA256(),
+		proposedAt,
+	)
+
+	return func() (base.BlockMap, bool, error) {
+		return dummyHealthBlockMap{m: m}, true, nil
+	}
+}
+
+func (t *testHealth) TestNotPrepared() {
+	h := NewHealth()
+
+	r := h.Liveness()
+	t.False(r.OK)
+	t.False(r.Checks["components"].OK)
+
+	rec := httptest.NewRecorder()
+	h.ServeReadiness(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	t.Equal(http.StatusServiceUnavailable, rec.Code)
+}
+
+func (t *testHealth) TestLiveness() {
+	h := NewHealth()
+
+	daemons := map[string]healthDaemon{
+		"database":   dummyHealthDaemon(true),
+		"memberlist": dummyHealthDaemon(true),
+	}
+
+	h.SetComponents(t.lastBlockMap(33, time.Now()), defaultMISCParams(), daemons)
+
+	t.True(h.Liveness().OK)
+
+	t.Run("daemon stopped", func() {
+		daemons["memberlist"] = dummyHealthDaemon(false)
+		defer func() {
+			daemons["memberlist"] = dummyHealthDaemon(true)
+		}()
+
+		r := h.Liveness()
+		t.False(r.OK)
+		t.False(r.Checks["memberlist"].OK)
+		t.True(r.Checks["database"].OK)
+	})
+
+	t.Run("broken", func() {
+		h.StateSwitched(isaacstates.StateBroken)
+
+		r := h.Liveness()
+		t.False(r.OK)
+		t.False(r.Checks["broken"].OK)
+
+		rec := httptest.NewRecorder()
+		h.ServeLiveness(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		t.Equal(http.StatusServiceUnavailable, rec.Code)
+
+		var u HealthReport
+		t.NoError(util.UnmarshalJSON(rec.Body.Bytes(), &u))
+		t.False(u.OK)
+	})
+}
+
+func (t *testHealth) TestReadiness() {
+	h := NewHealth()
+
+	params := defaultMISCParams()
+	t.NoError(params.SetHealthMaxSyncLag(2))
+	t.NoError(params.SetHealthMaxLastBlockElapsed(time.Second * 10))
+
+	h.SetComponents(t.lastBlockMap(33, time.Now()), params, map[string]healthDaemon{
+		"database": dummyHealthDaemon(true),
+	})
+
+	insuf := true
+	h.SetSyncSources(nil, func() (bool, error) { return insuf, nil })
+
+	t.Run("syncing in suffrage", func() {
+		h.StateSwitched(isaacstates.StateSyncing)
+
+		r := h.Readiness()
+		t.False(r.OK)
+		t.False(r.Checks["state"].OK)
+		t.True(r.Checks["broken"].OK)
+	})
+
+	t.Run("syncing not in suffrage", func() {
+		insuf = false
+		defer func() {
+			insuf = true
+		}()
+
+		h.StateSwitched(isaacstates.StateSyncing)
+
+		r := h.Readiness()
+		t.True(r.OK)
+		t.True(r.Checks["state"].OK)
+	})
+
+	h.StateSwitched(isaacstates.StateConsensus)
+
+	rec := httptest.NewRecorder()
+	h.ServeReadiness(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	t.Equal(http.StatusOK, rec.Code, rec.Body.String())
+
+	t.Run("sync lag", func() {
+		syncSourcesLastHeight := func(height base.Height) func() (base.Height, bool, error) {
+			return func() (base.Height, bool, error) {
+				return height, true, nil
+			}
+		}
+
+		h.SetSyncSources(syncSourcesLastHeight(35), nil)
+		t.True(h.Readiness().OK)
+
+		h.SetSyncSources(syncSourcesLastHeight(36), nil)
+
+		r := h.Readiness()
+		t.False(r.OK)
+		t.False(r.Checks["sync_lag"].OK)
+		t.Equal("3", r.Checks["sync_lag"].Message)
+	})
+
+	t.Run("no sync sources", func() {
+		h.SetSyncSources(func() (base.Height, bool, error) {
+			return base.NilHeight, false, nil
+		}, nil)
+
+		r := h.Readiness()
+		t.True(r.OK)
+		t.True(r.Checks["sync_lag"].OK)
+	})
+
+	t.Run("last block elapsed", func() {
+		h.SetSyncSources(func() (base.Height, bool, error) {
+			return 36, true, nil
+		}, nil)
+		h.SetComponents(t.lastBlockMap(36, time.Now().Add(-time.Second*11)), params, map[string]healthDaemon{
+			"database": dummyHealthDaemon(true),
+		})
+
+		r := h.Readiness()
+		t.False(r.OK)
+		t.True(r.Checks["sync_lag"].OK)
+		t.False(r.Checks["last_block_elapsed"].OK)
+	})
+}
+
+func TestHealth(t *testing.T) {
+	suite.Run(t, new(testHealth))
+}
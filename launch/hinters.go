@@ -4,6 +4,7 @@ import (
 	"github.com/ProtoconNet/mitum2/base"
 	"github.com/ProtoconNet/mitum2/isaac"
 	isaacblock "github.com/ProtoconNet/mitum2/isaac/block"
+	isaaclightclient "github.com/ProtoconNet/mitum2/isaac/lightclient"
 	isaacnetwork "github.com/ProtoconNet/mitum2/isaac/network"
 	isaacoperation "github.com/ProtoconNet/mitum2/isaac/operation"
 	isaacstates "github.com/ProtoconNet/mitum2/isaac/states"
@@ -21,6 +22,10 @@ var Hinters = []encoder.DecodeDetail{
 	{Hint: EventLoggingHeaderHint, Instance: EventLoggingHeader{}},
 	{Hint: base.BaseOperationProcessReasonErrorHint, Instance: base.BaseOperationProcessReasonError{}},
 	{Hint: base.BaseStateHint, Instance: base.BaseState{}},
+	{Hint: base.Ed25519PrivatekeyHint, Instance: &base.Ed25519Privatekey{}},
+	{Hint: base.Ed25519PublickeyHint, Instance: &base.Ed25519Publickey{}},
+	{Hint: base.BLSPrivatekeyHint, Instance: &base.BLSPrivatekey{}},
+	{Hint: base.BLSPublickeyHint, Instance: &base.BLSPublickey{}},
 	{Hint: base.MPrivatekeyHint, Instance: &base.MPrivatekey{}},
 	{Hint: base.MPublickeyHint, Instance: &base.MPublickey{}},
 	{Hint: base.OperationFixedtreeHint, Instance: base.OperationFixedtreeNode{}},
@@ -32,6 +37,7 @@ var Hinters = []encoder.DecodeDetail{
 	{Hint: isaac.ACCEPTVoteproofHint, Instance: isaac.ACCEPTVoteproof{}},
 	{Hint: isaac.ACCEPTExpelVoteproofHint, Instance: isaac.ACCEPTExpelVoteproof{}},
 	{Hint: isaac.ACCEPTStuckVoteproofHint, Instance: isaac.ACCEPTStuckVoteproof{}},
+	{Hint: isaac.ACCEPTAggregatedVoteproofHint, Instance: isaac.ACCEPTAggregatedVoteproof{}},
 	{Hint: isaac.FixedSuffrageCandidateLimiterRuleHint, Instance: isaac.FixedSuffrageCandidateLimiterRule{}},
 	{Hint: isaac.INITBallotFactHint, Instance: isaac.INITBallotFact{}},
 	{Hint: isaac.EmptyProposalINITBallotFactHint, Instance: isaac.EmptyProposalINITBallotFact{}},
@@ -42,6 +48,7 @@ var Hinters = []encoder.DecodeDetail{
 	{Hint: isaac.INITVoteproofHint, Instance: isaac.INITVoteproof{}},
 	{Hint: isaac.INITExpelVoteproofHint, Instance: isaac.INITExpelVoteproof{}},
 	{Hint: isaac.INITStuckVoteproofHint, Instance: isaac.INITStuckVoteproof{}},
+	{Hint: isaac.INITAggregatedVoteproofHint, Instance: isaac.INITAggregatedVoteproof{}},
 	{Hint: isaac.ParamsHint, Instance: &isaac.Params{}},
 	{Hint: isaac.ManifestHint, Instance: isaac.Manifest{}},
 	{Hint: isaac.NetworkPolicyHint, Instance: isaac.NetworkPolicy{}},
@@ -58,7 +65,10 @@ var Hinters = []encoder.DecodeDetail{
 	{Hint: isaacblock.BlockMapHint, Instance: isaacblock.BlockMap{}},
 	{Hint: isaac.BlockItemFileHint, Instance: isaac.BlockItemFile{}},
 	{Hint: isaac.BlockItemFilesHint, Instance: isaac.BlockItemFiles{}},
+	{Hint: isaac.CheckpointHint, Instance: isaac.Checkpoint{}},
 	{Hint: isaacblock.SuffrageProofHint, Instance: isaacblock.SuffrageProof{}},
+	{Hint: isaaclightclient.StateProofHint, Instance: isaaclightclient.StateProof{}},
+	{Hint: isaaclightclient.OperationProofHint, Instance: isaaclightclient.OperationProof{}},
 	{
 		Hint:     isaacnetwork.ExistsInStateOperationRequestHeaderHint,
 		Instance: isaacnetwork.ExistsInStateOperationRequestHeader{},
@@ -78,6 +88,8 @@ var Hinters = []encoder.DecodeDetail{
 	{Hint: isaacnetwork.SendBallotsHeaderHint, Instance: isaacnetwork.SendBallotsHeader{}},
 	{Hint: isaacnetwork.SendOperationRequestHeaderHint, Instance: isaacnetwork.SendOperationRequestHeader{}},
 	{Hint: isaacnetwork.StateRequestHeaderHint, Instance: isaacnetwork.StateRequestHeader{}},
+	{Hint: isaacnetwork.StateProofRequestHeaderHint, Instance: isaacnetwork.StateProofRequestHeader{}},
+	{Hint: isaacnetwork.OperationProofRequestHeaderHint, Instance: isaacnetwork.OperationProofRequestHeader{}},
 	{Hint: isaacnetwork.StreamOperationsHeaderHint, Instance: isaacnetwork.StreamOperationsHeader{}},
 	{
 		Hint:     isaacnetwork.SuffrageNodeConnInfoRequestHeaderHint,
@@ -102,6 +114,7 @@ var Hinters = []encoder.DecodeDetail{
 	{Hint: isaacoperation.SuffrageGenesisJoinHint, Instance: isaacoperation.SuffrageGenesisJoin{}},
 	{Hint: isaacoperation.SuffrageDisjoinHint, Instance: isaacoperation.SuffrageDisjoin{}},
 	{Hint: isaacoperation.SuffrageJoinHint, Instance: isaacoperation.SuffrageJoin{}},
+	{Hint: isaacoperation.SuffrageKeyRotateHint, Instance: isaacoperation.SuffrageKeyRotate{}},
 	{
 		Hint:     isaacoperation.SuffrageGenesisJoinFactHint,
 		Instance: isaacoperation.SuffrageGenesisJoinFact{},
@@ -140,6 +153,7 @@ var SupportedProposalOperationFactHinters = []encoder.DecodeDetail{
 	{Hint: isaacoperation.SuffrageCandidateFactHint, Instance: isaacoperation.SuffrageCandidateFact{}},
 	{Hint: isaacoperation.SuffrageDisjoinFactHint, Instance: isaacoperation.SuffrageDisjoinFact{}},
 	{Hint: isaacoperation.SuffrageJoinFactHint, Instance: isaacoperation.SuffrageJoinFact{}},
+	{Hint: isaacoperation.SuffrageKeyRotateFactHint, Instance: isaacoperation.SuffrageKeyRotateFact{}},
 	{Hint: isaacoperation.NetworkPolicyFactHint, Instance: isaacoperation.NetworkPolicyFact{}},
 }
 
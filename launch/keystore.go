@@ -0,0 +1,318 @@
+package launch
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/term"
+)
+
+var (
+	KeystoreVersion             = "keystore-v0.0.1"
+	KeystorePassphraseEnv       = "MITUM_KEYSTORE_PASSPHRASE"
+	KeystorePassphraseFileEnv   = "MITUM_KEYSTORE_PASSPHRASE_FILE"
+	DefaultKeystoreScryptN      = 1 << 15
+	DefaultKeystoreScryptR      = 8
+	DefaultKeystoreScryptP      = 1
+	MaxKeystoreScryptN          = 1 << 20
+	MaxKeystoreScryptR          = 16
+	MaxKeystoreScryptP          = 16
+	maxKeystoreScryptCost       = 1 << 22 // NOTE N * R * P
+	keystoreScryptKeyLen        = 32
+	keystoreScryptSaltSize      = 32
+	keystoreSchemePrefix        = "keystore://"
+	keystorePassphraseFileQuery = "passphrase_file"
+)
+
+var ErrKeystorePassphrase = util.NewIDError("wrong keystore passphrase")
+
+// Keystore is the encrypted privatekey file. The key is derived from the
+// passphrase by scrypt and the privatekey is encrypted by AES-256-GCM; the
+// publickey is authenticated as additional data.
+type Keystore struct {
+	Version    string         `json:"version"`
+	Publickey  string         `json:"publickey"`
+	KDF        string         `json:"kdf"`
+	KDFParams  KeystoreScrypt `json:"kdf_params"`
+	Cipher     string         `json:"cipher"`
+	Nonce      []byte         `json:"nonce"`
+	Ciphertext []byte         `json:"ciphertext"`
+}
+
+type KeystoreScrypt struct {
+	Salt []byte `json:"salt"`
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+}
+
+func (k Keystore) IsValid([]byte) error {
+	e := util.ErrInvalid.Errorf("invalid Keystore")
+
+	switch {
+	case k.Version != KeystoreVersion:
+		return e.Errorf("unknown version, %q", k.Version)
+	case k.KDF != "scrypt":
+		return e.Errorf("unknown kdf, %q", k.KDF)
+	case k.Cipher != "aes-256-gcm":
+		return e.Errorf("unknown cipher, %q", k.Cipher)
+	case len(k.Publickey) < 1:
+		return e.Errorf("empty publickey")
+	case len(k.KDFParams.Salt) < 1:
+		return e.Errorf("empty salt")
+	case k.KDFParams.N < 2, k.KDFParams.R < 1, k.KDFParams.P < 1:
+		return e.Errorf("wrong scrypt params")
+	case k.KDFParams.N&(k.KDFParams.N-1) != 0:
+		return e.Errorf("wrong scrypt params; n should be power of 2")
+	case k.KDFParams.N > MaxKeystoreScryptN,
+		k.KDFParams.R > MaxKeystoreScryptR,
+		k.KDFParams.P > MaxKeystoreScryptP,
+		k.KDFParams.N*k.KDFParams.R*k.KDFParams.P > maxKeystoreScryptCost:
+		return e.Errorf("too big scrypt params")
+	case len(k.Nonce) < 1, len(k.Ciphertext) < 1:
+		return e.Errorf("empty ciphertext")
+	default:
+		return nil
+	}
+}
+
+// EncryptKeystore encrypts the privatekey with passphrase and returns the
+// keystore json.
+func EncryptKeystore(priv base.Privatekey, passphrase []byte) ([]byte, error) {
+	e := util.StringError("encrypt keystore")
+
+	if len(passphrase) < 1 {
+		return nil, e.Errorf("empty passphrase")
+	}
+
+	salt := make([]byte, keystoreScryptSaltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	k := Keystore{
+		Version:   KeystoreVersion,
+		Publickey: priv.Publickey().String(),
+		KDF:       "scrypt",
+		KDFParams: KeystoreScrypt{
+			Salt: salt,
+			N:    DefaultKeystoreScryptN,
+			R:    DefaultKeystoreScryptR,
+			P:    DefaultKeystoreScryptP,
+		},
+		Cipher: "aes-256-gcm",
+	}
+
+	aead, err := k.aead(passphrase)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	k.Nonce = make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, k.Nonce); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	k.Ciphertext = aead.Seal(nil, k.Nonce, []byte(priv.String()), k.additionalData())
+
+	return util.MarshalJSONIndent(k)
+}
+
+// DecryptKeystore decrypts the privatekey from keystore json.
+func DecryptKeystore(b, passphrase []byte, jsonencoder encoder.Encoder) (base.Privatekey, error) {
+	e := util.StringError("decrypt keystore")
+
+	var k Keystore
+	if err := util.UnmarshalJSON(b, &k); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	if err := k.IsValid(nil); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	aead, err := k.aead(passphrase)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	if len(k.Nonce) != aead.NonceSize() {
+		return nil, e.Errorf("wrong nonce size")
+	}
+
+	body, err := aead.Open(nil, k.Nonce, k.Ciphertext, k.additionalData())
+	if err != nil {
+		return nil, e.Wrap(ErrKeystorePassphrase.Wrap(err))
+	}
+
+	defer clear(body)
+
+	priv, err := DecodePrivatekey(string(body), jsonencoder)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	if priv.Publickey().String() != k.Publickey {
+		return nil, e.Errorf("publickey not matched")
+	}
+
+	return priv, nil
+}
+
+func (k Keystore) aead(passphrase []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, k.KDFParams.Salt, k.KDFParams.N, k.KDFParams.R, k.KDFParams.P, keystoreScryptKeyLen)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	defer clear(key)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+
+	return aead, errors.WithStack(err)
+}
+
+func (k Keystore) additionalData() []byte {
+	return util.ConcatBytesSlice([]byte(k.Version), []byte(k.Publickey))
+}
+
+// IsKeystoreURL checks the privatekey string is keystore url,
+// 'keystore:///path/key.json'; the passphrase file can be given by query,
+// 'keystore:///path/key.json?passphrase_file=/path/passphrase'.
+func IsKeystoreURL(s string) bool {
+	return strings.HasPrefix(strings.TrimSpace(s), keystoreSchemePrefix)
+}
+
+// LoadKeystorePrivatekey loads the privatekey from the keystore url.
+func LoadKeystorePrivatekey(s string, jsonencoder encoder.Encoder) (base.Privatekey, error) {
+	e := util.StringError("load keystore")
+
+	u, err := url.Parse(strings.TrimSpace(s))
+
+	switch {
+	case err != nil:
+		return nil, e.Wrap(err)
+	case u.Scheme != "keystore":
+		return nil, e.Errorf("not keystore url, %q", s)
+	case len(u.Path) < 1:
+		return nil, e.Errorf("empty keystore path")
+	}
+
+	b, err := os.ReadFile(filepath.Clean(u.Path))
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	passphrase, err := ReadKeystorePassphrase(u.Query().Get(keystorePassphraseFileQuery), false)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	defer clear(passphrase)
+
+	priv, err := DecryptKeystore(b, passphrase, jsonencoder)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	return priv, nil
+}
+
+func decodePrivatekeyOrKeystore(s string, jsonencoder encoder.Encoder) (base.Privatekey, error) {
+	if IsKeystoreURL(s) {
+		return LoadKeystorePrivatekey(s, jsonencoder)
+	}
+
+	return base.DecodePrivatekeyFromString(s, jsonencoder)
+}
+
+// ReadKeystorePassphrase reads the passphrase from the given file, the
+// environment variables or the terminal prompt in order.
+func ReadKeystorePassphrase(f string, confirm bool) ([]byte, error) { //revive:disable-line:flag-parameter
+	if len(f) < 1 {
+		f = os.Getenv(KeystorePassphraseFileEnv)
+	}
+
+	if len(f) > 0 {
+		b, err := os.ReadFile(filepath.Clean(f))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		return trimPassphrase(b)
+	}
+
+	if i := os.Getenv(KeystorePassphraseEnv); len(i) > 0 {
+		return []byte(i), nil
+	}
+
+	return promptKeystorePassphrase(confirm)
+}
+
+func promptKeystorePassphrase(confirm bool) ([]byte, error) { //revive:disable-line:flag-parameter
+	fd := int(os.Stdin.Fd()) //nolint:gosec //...
+
+	if !term.IsTerminal(fd) {
+		return nil, errors.Errorf("keystore passphrase not given; set %q or %q", KeystorePassphraseEnv, KeystorePassphraseFileEnv)
+	}
+
+	read := func(prompt string) ([]byte, error) {
+		_, _ = fmt.Fprint(os.Stderr, prompt)
+
+		defer func() {
+			_, _ = fmt.Fprintln(os.Stderr)
+		}()
+
+		b, err := term.ReadPassword(fd)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		return trimPassphrase(b)
+	}
+
+	b, err := read("keystore passphrase: ")
+	if err != nil || !confirm {
+		return b, err
+	}
+
+	c, err := read("confirm passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+
+	defer clear(c)
+
+	if !bytes.Equal(b, c) {
+		return nil, errors.Errorf("passphrase not matched")
+	}
+
+	return b, nil
+}
+
+func trimPassphrase(b []byte) ([]byte, error) {
+	i := bytes.TrimRight(b, "\r\n")
+	if len(i) < 1 {
+		return nil, errors.Errorf("empty passphrase")
+	}
+
+	return i, nil
+}
@@ -0,0 +1,268 @@
+package launch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	jsonenc "github.com/ProtoconNet/mitum2/util/encoder/json"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+)
+
+type testKeystore struct {
+	suite.Suite
+	enc  *jsonenc.Encoder
+	root string
+}
+
+func (t *testKeystore) SetupSuite() {
+	t.enc = jsonenc.NewEncoder()
+
+	t.NoError(t.enc.Add(encoder.DecodeDetail{Hint: base.StringAddressHint, Instance: base.StringAddress{}}))
+	t.NoError(t.enc.Add(encoder.DecodeDetail{Hint: base.MPrivatekeyHint, Instance: &base.MPrivatekey{}}))
+	t.NoError(t.enc.Add(encoder.DecodeDetail{Hint: base.MPublickeyHint, Instance: &base.MPublickey{}}))
+	t.NoError(t.enc.Add(encoder.DecodeDetail{Hint: base.Ed25519PrivatekeyHint, Instance: &base.Ed25519Privatekey{}}))
+	t.NoError(t.enc.Add(encoder.DecodeDetail{Hint: base.Ed25519PublickeyHint, Instance: &base.Ed25519Publickey{}}))
+}
+
+func (t *testKeystore) SetupTest() {
+	t.root = t.T().TempDir()
+}
+
+func (t *testKeystore) writeFile(name string, b []byte) string {
+	f := filepath.Join(t.root, name)
+	t.NoError(os.WriteFile(f, b, 0o600))
+
+	return f
+}
+
+func (t *testKeystore) TestEncryptDecrypt() {
+	for _, priv := range []base.Privatekey{base.NewMPrivatekey(), base.NewEd25519Privatekey()} {
+		passphrase := util.UUID().Bytes()
+
+		b, err := EncryptKeystore(priv, passphrase)
+		t.NoError(err)
+		t.NotContains(string(b), priv.String())
+
+		upriv, err := DecryptKeystore(b, passphrase, t.enc)
+		t.NoError(err)
+		t.True(priv.Equal(upriv))
+	}
+}
+
+func (t *testKeystore) TestWrongPassphrase() {
+	priv := base.NewMPrivatekey()
+
+	b, err := EncryptKeystore(priv, []byte("showme"))
+	t.NoError(err)
+
+	_, err = DecryptKeystore(b, []byte("findme"), t.enc)
+	t.Error(err)
+	t.ErrorIs(err, ErrKeystorePassphrase)
+}
+
+func (t *testKeystore) TestEmptyPassphrase() {
+	_, err := EncryptKeystore(base.NewMPrivatekey(), nil)
+	t.Error(err)
+	t.ErrorContains(err, "empty passphrase")
+}
+
+func (t *testKeystore) TestTampered() {
+	priv := base.NewMPrivatekey()
+	passphrase := []byte("showme")
+
+	b, err := EncryptKeystore(priv, passphrase)
+	t.NoError(err)
+
+	t.Run("publickey", func() {
+		var k Keystore
+		t.NoError(util.UnmarshalJSON(b, &k))
+
+		k.Publickey = base.NewMPrivatekey().Publickey().String()
+
+		i, err := util.MarshalJSON(k)
+		t.NoError(err)
+
+		_, err = DecryptKeystore(i, passphrase, t.enc)
+		t.Error(err)
+		t.ErrorIs(err, ErrKeystorePassphrase)
+	})
+
+	t.Run("ciphertext", func() {
+		var k Keystore
+		t.NoError(util.UnmarshalJSON(b, &k))
+
+		k.Ciphertext[0] ^= 0xff
+
+		i, err := util.MarshalJSON(k)
+		t.NoError(err)
+
+		_, err = DecryptKeystore(i, passphrase, t.enc)
+		t.Error(err)
+		t.ErrorIs(err, ErrKeystorePassphrase)
+	})
+
+	t.Run("too big scrypt params", func() {
+		for _, i := range [][3]int{
+			{MaxKeystoreScryptN << 1, 1, 1},
+			{DefaultKeystoreScryptN, MaxKeystoreScryptR + 1, 1},
+			{DefaultKeystoreScryptN, 1, MaxKeystoreScryptP + 1},
+			{MaxKeystoreScryptN, MaxKeystoreScryptR, MaxKeystoreScryptP},
+		} {
+			var k Keystore
+			t.NoError(util.UnmarshalJSON(b, &k))
+
+			k.KDFParams.N, k.KDFParams.R, k.KDFParams.P = i[0], i[1], i[2]
+
+			i, err := util.MarshalJSON(k)
+			t.NoError(err)
+
+			_, err = DecryptKeystore(i, passphrase, t.enc)
+			t.Error(err)
+			t.ErrorIs(err, util.ErrInvalid)
+			t.ErrorContains(err, "too big scrypt params")
+		}
+	})
+
+	t.Run("n not power of 2", func() {
+		var k Keystore
+		t.NoError(util.UnmarshalJSON(b, &k))
+
+		k.KDFParams.N = DefaultKeystoreScryptN + 1
+
+		i, err := util.MarshalJSON(k)
+		t.NoError(err)
+
+		_, err = DecryptKeystore(i, passphrase, t.enc)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "power of 2")
+	})
+
+	t.Run("unknown version", func() {
+		var k Keystore
+		t.NoError(util.UnmarshalJSON(b, &k))
+
+		k.Version = "keystore-v9.9.9"
+
+		i, err := util.MarshalJSON(k)
+		t.NoError(err)
+
+		_, err = DecryptKeystore(i, passphrase, t.enc)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "unknown version")
+	})
+}
+
+func (t *testKeystore) TestLoadFromURL() {
+	priv := base.NewMPrivatekey()
+	passphrase := []byte("showme")
+
+	b, err := EncryptKeystore(priv, passphrase)
+	t.NoError(err)
+
+	f := t.writeFile("key.json", b)
+
+	t.Run("passphrase file", func() {
+		pf := t.writeFile("passphrase", append(passphrase, '\n'))
+
+		s := "keystore://" + f + "?passphrase_file=" + pf
+		t.True(IsKeystoreURL(s))
+
+		upriv, err := LoadKeystorePrivatekey(s, t.enc)
+		t.NoError(err)
+		t.True(priv.Equal(upriv))
+	})
+
+	t.Run("env", func() {
+		t.T().Setenv(KeystorePassphraseEnv, string(passphrase))
+
+		upriv, err := LoadKeystorePrivatekey("keystore://"+f, t.enc)
+		t.NoError(err)
+		t.True(priv.Equal(upriv))
+	})
+
+	t.Run("env passphrase file", func() {
+		pf := t.writeFile("passphrase-env", passphrase)
+
+		t.T().Setenv(KeystorePassphraseFileEnv, pf)
+
+		upriv, err := LoadKeystorePrivatekey("keystore://"+f, t.enc)
+		t.NoError(err)
+		t.True(priv.Equal(upriv))
+	})
+
+	t.Run("wrong passphrase", func() {
+		t.T().Setenv(KeystorePassphraseEnv, "findme")
+
+		_, err := LoadKeystorePrivatekey("keystore://"+f, t.enc)
+		t.Error(err)
+		t.ErrorIs(err, ErrKeystorePassphrase)
+	})
+
+	t.Run("unknown file", func() {
+		t.T().Setenv(KeystorePassphraseEnv, string(passphrase))
+
+		_, err := LoadKeystorePrivatekey("keystore://"+f+"-unknown", t.enc)
+		t.Error(err)
+		t.ErrorIs(err, os.ErrNotExist)
+	})
+}
+
+func (t *testKeystore) TestDesign() {
+	priv := base.NewMPrivatekey()
+	passphrase := []byte("showme")
+
+	b, err := EncryptKeystore(priv, passphrase)
+	t.NoError(err)
+
+	f := t.writeFile("key.json", b)
+
+	y := []byte(`
+address: no0sas
+privatekey: keystore://` + f + `
+network_id: hehe 1 2 3 4
+network:
+  bind: 0.0.0.0:1234
+  publish: 1.2.3.4:4321
+storage:
+  base: /tmp/a/b/c
+`)
+
+	var a NodeDesign
+	t.NoError(a.DecodeYAML(y, t.enc))
+
+	t.Nil(a.Privatekey)
+	t.Equal("keystore://"+f, a.keystore)
+
+	t.T().Setenv(KeystorePassphraseEnv, string(passphrase))
+
+	upriv, err := decodePrivatekeyOrKeystore(a.keystore, t.enc)
+	t.NoError(err)
+	t.True(priv.Equal(upriv))
+
+	t.Run("marshal keystore url", func() {
+		a.Privatekey = upriv
+
+		b, err := util.MarshalJSON(a)
+		t.NoError(err)
+
+		t.NotContains(string(b), priv.String())
+		t.Contains(string(b), "keystore://"+f)
+
+		yb, err := yaml.Marshal(a)
+		t.NoError(err)
+
+		t.NotContains(string(yb), priv.String())
+		t.Contains(string(yb), "keystore://"+f)
+	})
+}
+
+func TestKeystore(t *testing.T) {
+	suite.Run(t, new(testKeystore))
+}
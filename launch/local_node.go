@@ -0,0 +1,160 @@
+package launch
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/logging"
+)
+
+// KeyRotatableLocalNode is the LocalNode, which switches it's privatekey to
+// the rotate privatekey, when the key rotation of local node becomes effective.
+type KeyRotatableLocalNode struct {
+	local  base.LocalNode
+	rotate base.Privatekey
+	sync.RWMutex
+}
+
+func NewKeyRotatableLocalNode(local base.LocalNode, rotate base.Privatekey) *KeyRotatableLocalNode {
+	return &KeyRotatableLocalNode{local: local, rotate: rotate}
+}
+
+func (n *KeyRotatableLocalNode) Address() base.Address {
+	return n.current().Address()
+}
+
+func (n *KeyRotatableLocalNode) Publickey() base.Publickey {
+	return n.current().Publickey()
+}
+
+func (n *KeyRotatableLocalNode) Privatekey() base.Privatekey {
+	return n.current().Privatekey()
+}
+
+func (n *KeyRotatableLocalNode) HashBytes() []byte {
+	return n.current().HashBytes()
+}
+
+func (n *KeyRotatableLocalNode) IsValid(b []byte) error {
+	return n.current().IsValid(b)
+}
+
+func (n *KeyRotatableLocalNode) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(n.current())
+}
+
+// Rotated returns true if the privatekey was switched to the rotate privatekey.
+func (n *KeyRotatableLocalNode) Rotated() bool {
+	n.RLock()
+	defer n.RUnlock()
+
+	return n.rotate == nil
+}
+
+// Rotate switches the privatekey to the rotate privatekey, if the key rotation
+// of local node in the suffrage state is effective for the next height of the
+// given height.
+func (n *KeyRotatableLocalNode) Rotate(height base.Height, st base.State) (bool, error) {
+	n.Lock()
+	defer n.Unlock()
+
+	if n.rotate == nil || st == nil {
+		return false, nil
+	}
+
+	v, err := base.LoadSuffrageNodesStateValue(st)
+	if err != nil {
+		return false, err
+	}
+
+	nodes := v.Nodes()
+
+	for i := range nodes {
+		if !nodes[i].Address().Equal(n.local.Address()) {
+			continue
+		}
+
+		switch pub := isaac.SuffrageNodePublickey(nodes[i], height+1); {
+		case pub.Equal(n.local.Publickey()):
+			return false, nil
+		case !pub.Equal(n.rotate.Publickey()):
+			return false, util.ErrInvalid.Errorf("rotated publickey does not match with rotate privatekey")
+		}
+
+		n.local = isaac.NewLocalNode(n.rotate, n.local.Address())
+		n.rotate = nil
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (n *KeyRotatableLocalNode) current() base.LocalNode {
+	n.RLock()
+	defer n.RUnlock()
+
+	return n.local
+}
+
+// keyRotateLocalFunc rotates the privatekey of local node with the last
+// suffrage state; it is called when new block saved.
+func keyRotateLocalFunc(
+	log *logging.Logging,
+	local base.LocalNode,
+	db isaac.Database,
+) func(base.Height) {
+	n, ok := local.(*KeyRotatableLocalNode)
+	if !ok {
+		return func(base.Height) {}
+	}
+
+	return func(height base.Height) {
+		if n.Rotated() {
+			return
+		}
+
+		st, _, err := db.State(isaac.SuffrageStateKey)
+		if err != nil {
+			log.Log().Error().Err(err).Msg("failed to get suffrage state for key rotation")
+
+			return
+		}
+
+		switch rotated, err := n.Rotate(height, st); {
+		case err != nil:
+			log.Log().Error().Err(err).Interface("height", height).Msg("failed to rotate local key")
+		case rotated:
+			log.Log().Info().
+				Interface("height", height).
+				Stringer("publickey", n.Publickey()).
+				Msg("local key rotated")
+		}
+	}
+}
+
+func rotateLocalKeyWithLastBlock(pctx context.Context) error {
+	var log *logging.Logging
+	var local base.LocalNode
+	var db isaac.Database
+
+	if err := util.LoadFromContextOK(pctx,
+		LoggingContextKey, &log,
+		LocalContextKey, &local,
+		CenterDatabaseContextKey, &db,
+	); err != nil {
+		return err
+	}
+
+	switch m, found, err := db.LastBlockMap(); {
+	case err != nil:
+		return err
+	case found:
+		keyRotateLocalFunc(log, local, db)(m.Manifest().Height())
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package launch
+
+import (
+	"testing"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/stretchr/testify/suite"
+)
+
+type testKeyRotatableLocalNode struct {
+	suite.Suite
+}
+
+func (t *testKeyRotatableLocalNode) suffrageState(nodes ...base.SuffrageNodeStateValue) base.State {
+	return base.NewBaseState(
+		base.Height(3),
+		isaac.SuffrageStateKey,
+		isaac.NewSuffrageNodesStateValue(base.Height(1), nodes),
+		nil,
+		nil,
+	)
+}
+
+func (t *testKeyRotatableLocalNode) TestRotate() {
+	local := isaac.NewLocalNode(base.NewMPrivatekey(), base.RandomAddress(""))
+	rotate := base.NewMPrivatekey()
+
+	other := isaac.NewSuffrageNodeStateValue(isaac.NewNode(base.NewMPrivatekey().Publickey(), base.RandomAddress("")), 1)
+	st := t.suffrageState(
+		isaac.NewSuffrageNodeStateValue(isaac.NewNode(local.Publickey(), local.Address()), 1).
+			SetKeyRotation(rotate.Publickey(), base.Height(33)),
+		other,
+	)
+
+	n := NewKeyRotatableLocalNode(local, rotate)
+
+	t.Run("before rotate height", func() {
+		rotated, err := n.Rotate(base.Height(31), st)
+		t.NoError(err)
+		t.False(rotated)
+		t.False(n.Rotated())
+		t.True(n.Publickey().Equal(local.Publickey()))
+		t.True(n.Privatekey().Equal(local.Privatekey()))
+	})
+
+	t.Run("next height is rotate height", func() {
+		rotated, err := n.Rotate(base.Height(32), st)
+		t.NoError(err)
+		t.True(rotated)
+		t.True(n.Rotated())
+		t.True(n.Address().Equal(local.Address()))
+		t.True(n.Publickey().Equal(rotate.Publickey()))
+		t.True(n.Privatekey().Equal(rotate))
+		t.NoError(n.IsValid(nil))
+	})
+
+	t.Run("already rotated", func() {
+		rotated, err := n.Rotate(base.Height(33), st)
+		t.NoError(err)
+		t.False(rotated)
+	})
+}
+
+func (t *testKeyRotatableLocalNode) TestRotateUnknownKey() {
+	local := isaac.NewLocalNode(base.NewMPrivatekey(), base.RandomAddress(""))
+
+	st := t.suffrageState(
+		isaac.NewSuffrageNodeStateValue(isaac.NewNode(local.Publickey(), local.Address()), 1).
+			SetKeyRotation(base.NewMPrivatekey().Publickey(), base.Height(33)),
+	)
+
+	n := NewKeyRotatableLocalNode(local, base.NewMPrivatekey())
+
+	rotated, err := n.Rotate(base.Height(33), st)
+	t.Error(err)
+	t.ErrorIs(err, util.ErrInvalid)
+	t.False(rotated)
+	t.True(n.Publickey().Equal(local.Publickey()))
+}
+
+func (t *testKeyRotatableLocalNode) TestNotInSuffrage() {
+	local := isaac.NewLocalNode(base.NewMPrivatekey(), base.RandomAddress(""))
+
+	st := t.suffrageState(
+		isaac.NewSuffrageNodeStateValue(isaac.NewNode(base.NewMPrivatekey().Publickey(), base.RandomAddress("")), 1),
+	)
+
+	n := NewKeyRotatableLocalNode(local, base.NewMPrivatekey())
+
+	rotated, err := n.Rotate(base.Height(33), st)
+	t.NoError(err)
+	t.False(rotated)
+	t.False(n.Rotated())
+}
+
+func TestKeyRotatableLocalNode(t *testing.T) {
+	suite.Run(t, new(testKeyRotatableLocalNode))
+}
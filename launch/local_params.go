@@ -5,6 +5,7 @@ import (
 
 	"github.com/ProtoconNet/mitum2/base"
 	"github.com/ProtoconNet/mitum2/isaac"
+	isaacblock "github.com/ProtoconNet/mitum2/isaac/block"
 	isaacnetwork "github.com/ProtoconNet/mitum2/isaac/network"
 	"github.com/ProtoconNet/mitum2/network/quicmemberlist"
 	"github.com/ProtoconNet/mitum2/network/quicstream"
@@ -24,6 +25,7 @@ func init() {
 		isaacnetwork.HandlerNameCheckHandover:  0,
 		isaacnetwork.HandlerNameCheckHandoverX: 0,
 		isaacnetwork.HandlerNameStartHandover:  0,
+		quicstream.HandlerNameRelayForward:     0,
 	}
 
 	for i := range networkHandlerNames {
@@ -241,24 +243,35 @@ func (p *MemberlistParams) SetExtraSameMemberLimit(d uint64) error {
 type MISCParams struct {
 	*util.BaseParams
 	syncSourceCheckerInterval             time.Duration
+	discoveryInterval                     time.Duration
 	validProposalOperationExpire          time.Duration
 	validProposalSuffrageOperationsExpire time.Duration
 	blockItemReadersRemoveEmptyAfter      time.Duration
 	blockItemReadersRemoveEmptyInterval   time.Duration
+	healthMaxLastBlockElapsed             time.Duration
+	blockArchiveInterval                  time.Duration
 	maxMessageSize                        uint64
 	objectCacheSize                       uint64
+	signCacheSize                         uint64
+	healthMaxSyncLag                      uint64
+	blockArchiveHeights                   uint64
 }
 
 func defaultMISCParams() *MISCParams {
 	return &MISCParams{
 		BaseParams:                            util.NewBaseParams(),
 		syncSourceCheckerInterval:             time.Second * 30, //nolint:gomnd //...
+		discoveryInterval:                     time.Second * 30, //nolint:gomnd //...
 		validProposalOperationExpire:          time.Hour * 24,   //nolint:gomnd //...
 		validProposalSuffrageOperationsExpire: time.Hour * 2,
 		blockItemReadersRemoveEmptyAfter:      isaac.DefaultBlockItemReadersRemoveEmptyAfter,
 		blockItemReadersRemoveEmptyInterval:   isaac.DefaultBlockItemReadersRemoveEmptyInterval,
-		maxMessageSize:                        1 << 18, //nolint:gomnd //...
-		objectCacheSize:                       1 << 13, //nolint:gomnd // big enough
+		maxMessageSize:                        1 << 18,         //nolint:gomnd //...
+		objectCacheSize:                       1 << 13,         //nolint:gomnd // big enough
+		signCacheSize:                         1 << 14,         //nolint:gomnd //...
+		healthMaxSyncLag:                      3,               //nolint:gomnd //...
+		healthMaxLastBlockElapsed:             time.Minute * 3, //nolint:gomnd //...
+		blockArchiveInterval:                  isaacblock.DefaultLocalFSArchiverInterval,
 	}
 }
 
@@ -273,6 +286,10 @@ func (p *MISCParams) IsValid([]byte) error {
 		return e.Errorf("wrong duration; invalid syncSourceCheckerInterval")
 	}
 
+	if p.discoveryInterval < 0 {
+		return e.Errorf("wrong duration; invalid discoveryInterval")
+	}
+
 	if p.validProposalOperationExpire < 0 {
 		return e.Errorf("wrong duration; invalid validProposalOperationExpire")
 	}
@@ -289,6 +306,10 @@ func (p *MISCParams) IsValid([]byte) error {
 		return e.Errorf("wrong duration; invalid blockItemReadersRemoveEmptyInterval")
 	}
 
+	if p.blockArchiveInterval < 0 {
+		return e.Errorf("wrong duration; invalid blockArchiveInterval")
+	}
+
 	if p.maxMessageSize < 1 {
 		return e.Errorf("wrong maxMessageSize")
 	}
@@ -297,6 +318,18 @@ func (p *MISCParams) IsValid([]byte) error {
 		return e.Errorf("wrong objectCacheSize")
 	}
 
+	if p.signCacheSize < 1 {
+		return e.Errorf("wrong signCacheSize")
+	}
+
+	if p.healthMaxSyncLag < 1 {
+		return e.Errorf("wrong healthMaxSyncLag")
+	}
+
+	if p.healthMaxLastBlockElapsed < 1 {
+		return e.Errorf("wrong duration; invalid healthMaxLastBlockElapsed")
+	}
+
 	return nil
 }
 
@@ -321,6 +354,27 @@ func (p *MISCParams) SetSyncSourceCheckerInterval(d time.Duration) error {
 	})
 }
 
+// DiscoveryInterval is the interval to collect discoveries from discovery
+// providers.
+func (p *MISCParams) DiscoveryInterval() time.Duration {
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.discoveryInterval
+}
+
+func (p *MISCParams) SetDiscoveryInterval(d time.Duration) error {
+	return p.SetDuration(d, func(d time.Duration) (bool, error) {
+		if p.discoveryInterval == d {
+			return false, nil
+		}
+
+		p.discoveryInterval = d
+
+		return true, nil
+	})
+}
+
 // ValidProposalOperationExpire is the maximum creation time for valid
 // operation. If the creation time of operation is older than
 // ValidProposalOperationExpire, it will be ignored.
@@ -409,6 +463,48 @@ func (p *MISCParams) SetBlockItemReadersRemoveEmptyInterval(d time.Duration) err
 	})
 }
 
+// BlockArchiveHeights is the number of heights in one block archive. The old
+// height directories are packed into block archive by this number of heights.
+// Zero means archiving is disabled.
+func (p *MISCParams) BlockArchiveHeights() uint64 {
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.blockArchiveHeights
+}
+
+func (p *MISCParams) SetBlockArchiveHeights(d uint64) error {
+	return p.Set(func() (bool, error) {
+		if p.blockArchiveHeights == d {
+			return false, nil
+		}
+
+		p.blockArchiveHeights = d
+
+		return true, nil
+	})
+}
+
+// BlockArchiveInterval is the interval to check the heights to be archived.
+func (p *MISCParams) BlockArchiveInterval() time.Duration {
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.blockArchiveInterval
+}
+
+func (p *MISCParams) SetBlockArchiveInterval(d time.Duration) error {
+	return p.SetDuration(d, func(d time.Duration) (bool, error) {
+		if p.blockArchiveInterval == d {
+			return false, nil
+		}
+
+		p.blockArchiveInterval = d
+
+		return true, nil
+	})
+}
+
 // MaxMessageSize is the maximum size of incoming messages like ballot or
 // operation. If message size is over, it will be ignored.
 func (p *MISCParams) MaxMessageSize() uint64 {
@@ -451,6 +547,70 @@ func (p *MISCParams) SetObjectCacheSize(d uint64) error {
 	})
 }
 
+// SignCacheSize is the cache size for the verified signatures; the same
+// signature of operation or ballot is not verified again.
+func (p *MISCParams) SignCacheSize() uint64 {
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.signCacheSize
+}
+
+func (p *MISCParams) SetSignCacheSize(d uint64) error {
+	return p.SetUint64(d, func(d uint64) (bool, error) {
+		if p.signCacheSize == d {
+			return false, nil
+		}
+
+		p.signCacheSize = d
+
+		return true, nil
+	})
+}
+
+// HealthMaxSyncLag is the maximum difference between the last height of sync
+// sources and the local last block height; if the difference is over, node is
+// not ready.
+func (p *MISCParams) HealthMaxSyncLag() uint64 {
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.healthMaxSyncLag
+}
+
+func (p *MISCParams) SetHealthMaxSyncLag(d uint64) error {
+	return p.SetUint64(d, func(d uint64) (bool, error) {
+		if p.healthMaxSyncLag == d {
+			return false, nil
+		}
+
+		p.healthMaxSyncLag = d
+
+		return true, nil
+	})
+}
+
+// HealthMaxLastBlockElapsed is the maximum elapsed time since the last block
+// was proposed; if it is over, node is not ready.
+func (p *MISCParams) HealthMaxLastBlockElapsed() time.Duration {
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.healthMaxLastBlockElapsed
+}
+
+func (p *MISCParams) SetHealthMaxLastBlockElapsed(d time.Duration) error {
+	return p.SetDuration(d, func(d time.Duration) (bool, error) {
+		if p.healthMaxLastBlockElapsed == d {
+			return false, nil
+		}
+
+		p.healthMaxLastBlockElapsed = d
+
+		return true, nil
+	})
+}
+
 type NetworkParams struct {
 	*util.BaseParams
 	rateLimit             *NetworkRateLimitParams
@@ -463,6 +623,7 @@ type NetworkParams struct {
 	connectionPoolSize    uint64
 	maxIncomingStreams    uint64
 	maxStreamTimeout      time.Duration
+	headExtension         bool
 }
 
 func defaultNetworkParams() *NetworkParams {
@@ -759,6 +920,28 @@ func (p *NetworkParams) SetMaxStreamTimeout(d time.Duration) error {
 	})
 }
 
+// HeadExtension enables the extended head of quicstream request; the body
+// compression is negotiated thru the extended head. The old nodes can not read
+// the extended head, so it should be enabled after all the nodes are upgraded.
+func (p *NetworkParams) HeadExtension() bool {
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.headExtension
+}
+
+func (p *NetworkParams) SetHeadExtension(b bool) error {
+	return p.Set(func() (bool, error) {
+		if p.headExtension == b {
+			return false, nil
+		}
+
+		p.headExtension = b
+
+		return true, nil
+	})
+}
+
 func (p *NetworkParams) RateLimit() *NetworkRateLimitParams {
 	p.RLock()
 	defer p.RUnlock()
@@ -847,6 +1030,7 @@ var networkHandlerNames = []quicstream.HandlerName{
 	isaacnetwork.HandlerNameNodeChallenge,
 	isaacnetwork.HandlerNameNodeInfo,
 	isaacnetwork.HandlerNameOperation,
+	isaacnetwork.HandlerNameOperationProof,
 	isaacnetwork.HandlerNameProposal,
 	isaacnetwork.HandlerNameRequestProposal,
 	isaacnetwork.HandlerNameSendBallots,
@@ -854,6 +1038,7 @@ var networkHandlerNames = []quicstream.HandlerName{
 	isaacnetwork.HandlerNameSetAllowConsensus,
 	isaacnetwork.HandlerNameStartHandover,
 	isaacnetwork.HandlerNameState,
+	isaacnetwork.HandlerNameStateProof,
 	isaacnetwork.HandlerNameStreamOperations,
 	isaacnetwork.HandlerNameSuffrageNodeConnInfo,
 	isaacnetwork.HandlerNameSuffrageProof,
@@ -864,4 +1049,6 @@ var networkHandlerNames = []quicstream.HandlerName{
 	HandlerNameNodeRead,
 	HandlerNameNodeWrite,
 	HandlerNameEventLogging,
+	quicstream.HandlerNameRelayRegister,
+	quicstream.HandlerNameRelayForward,
 }
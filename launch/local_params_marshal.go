@@ -226,24 +226,36 @@ func (p *MemberlistParams) unmarshal(u memberlistParamsUnmarshaler) error {
 type miscParamsYAMLMarshaler struct {
 	//revive:disable:line-length-limit
 	SyncSourceCheckerInterval             util.ReadableDuration `json:"sync_source_checker_interval,omitempty" yaml:"sync_source_checker_interval,omitempty"`
+	DiscoveryInterval                     util.ReadableDuration `json:"discovery_interval,omitempty" yaml:"discovery_interval,omitempty"`
 	ValidProposalOperationExpire          util.ReadableDuration `json:"valid_proposal_operation_expire,omitempty" yaml:"valid_proposal_operation_expire,omitempty"`
 	ValidProposalSuffrageOperationsExpire util.ReadableDuration `json:"valid_proposal_suffrage_operations_expire,omitempty" yaml:"valid_proposal_suffrage_operations_expire,omitempty"`
 	BlockItemReadersRemoveEmptyAfter      util.ReadableDuration `json:"block_item_readers_remove_empty_after,omitempty" yaml:"block_item_readers_remove_empty_after,omitempty"`
 	BlockItemReadersRemoveEmptyInterval   util.ReadableDuration `json:"block_item_readers_remove_empty_interval,omitempty" yaml:"block_item_readers_remove_empty_interval,omitempty"`
 	MaxMessageSize                        uint64                `json:"max_message_size,omitempty" yaml:"max_message_size,omitempty"`
 	ObjectCacheSize                       uint64                `json:"object_cache_size,omitempty" yaml:"object_cache_size,omitempty"`
+	SignCacheSize                         uint64                `json:"sign_cache_size,omitempty" yaml:"sign_cache_size,omitempty"`
+	HealthMaxSyncLag                      uint64                `json:"health_max_sync_lag,omitempty" yaml:"health_max_sync_lag,omitempty"`
+	HealthMaxLastBlockElapsed             util.ReadableDuration `json:"health_max_last_block_elapsed,omitempty" yaml:"health_max_last_block_elapsed,omitempty"`
+	BlockArchiveHeights                   uint64                `json:"block_archive_heights,omitempty" yaml:"block_archive_heights,omitempty"`
+	BlockArchiveInterval                  util.ReadableDuration `json:"block_archive_interval,omitempty" yaml:"block_archive_interval,omitempty"`
 	//revive:enable:line-length-limit
 }
 
 func (p *MISCParams) marshaler() miscParamsYAMLMarshaler {
 	return miscParamsYAMLMarshaler{
 		SyncSourceCheckerInterval:             util.ReadableDuration(p.syncSourceCheckerInterval),
+		DiscoveryInterval:                     util.ReadableDuration(p.discoveryInterval),
 		ValidProposalOperationExpire:          util.ReadableDuration(p.validProposalOperationExpire),
 		ValidProposalSuffrageOperationsExpire: util.ReadableDuration(p.validProposalSuffrageOperationsExpire),
 		BlockItemReadersRemoveEmptyAfter:      util.ReadableDuration(p.blockItemReadersRemoveEmptyAfter),
 		BlockItemReadersRemoveEmptyInterval:   util.ReadableDuration(p.blockItemReadersRemoveEmptyInterval),
 		MaxMessageSize:                        p.maxMessageSize,
 		ObjectCacheSize:                       p.objectCacheSize,
+		SignCacheSize:                         p.signCacheSize,
+		HealthMaxSyncLag:                      p.healthMaxSyncLag,
+		HealthMaxLastBlockElapsed:             util.ReadableDuration(p.healthMaxLastBlockElapsed),
+		BlockArchiveHeights:                   p.blockArchiveHeights,
+		BlockArchiveInterval:                  util.ReadableDuration(p.blockArchiveInterval),
 	}
 }
 
@@ -258,12 +270,18 @@ func (p *MISCParams) MarshalYAML() (interface{}, error) {
 type miscParamsYAMLUnmarshaler struct {
 	//revive:disable:line-length-limit
 	SyncSourceCheckerInterval             *util.ReadableDuration `json:"sync_source_checker_interval,omitempty" yaml:"sync_source_checker_interval,omitempty"`
+	DiscoveryInterval                     *util.ReadableDuration `json:"discovery_interval,omitempty" yaml:"discovery_interval,omitempty"`
 	ValidProposalOperationExpire          *util.ReadableDuration `json:"valid_proposal_operation_expire,omitempty" yaml:"valid_proposal_operation_expire,omitempty"`
 	ValidProposalSuffrageOperationsExpire *util.ReadableDuration `json:"valid_proposal_suffrage_operations_expire,omitempty" yaml:"valid_proposal_suffrage_operations_expire,omitempty"`
 	BlockItemReadersRemoveEmptyAfter      *util.ReadableDuration `json:"block_item_readers_remove_empty_after,omitempty" yaml:"block_item_readers_remove_empty_after,omitempty"`
 	BlockItemReadersRemoveEmptyInterval   *util.ReadableDuration `json:"block_item_readers_remove_empty_interval,omitempty" yaml:"block_item_readers_remove_empty_interval,omitempty"`
 	MaxMessageSize                        *uint64                `json:"max_message_size,omitempty" yaml:"max_message_size,omitempty"`
 	ObjectCacheSize                       *uint64                `json:"object_cache_size,omitempty" yaml:"object_cache_size,omitempty"`
+	SignCacheSize                         *uint64                `json:"sign_cache_size,omitempty" yaml:"sign_cache_size,omitempty"`
+	HealthMaxSyncLag                      *uint64                `json:"health_max_sync_lag,omitempty" yaml:"health_max_sync_lag,omitempty"`
+	HealthMaxLastBlockElapsed             *util.ReadableDuration `json:"health_max_last_block_elapsed,omitempty" yaml:"health_max_last_block_elapsed,omitempty"`
+	BlockArchiveHeights                   *uint64                `json:"block_archive_heights,omitempty" yaml:"block_archive_heights,omitempty"`
+	BlockArchiveInterval                  *util.ReadableDuration `json:"block_archive_interval,omitempty" yaml:"block_archive_interval,omitempty"`
 	//revive:enable:line-length-limit
 }
 
@@ -306,12 +324,27 @@ func (p *MISCParams) unmarshal(u miscParamsYAMLUnmarshaler) error {
 		p.objectCacheSize = *u.ObjectCacheSize
 	}
 
+	if u.SignCacheSize != nil {
+		p.signCacheSize = *u.SignCacheSize
+	}
+
+	if u.HealthMaxSyncLag != nil {
+		p.healthMaxSyncLag = *u.HealthMaxSyncLag
+	}
+
+	if u.BlockArchiveHeights != nil {
+		p.blockArchiveHeights = *u.BlockArchiveHeights
+	}
+
 	durargs := [][2]interface{}{
 		{u.SyncSourceCheckerInterval, &p.syncSourceCheckerInterval},
+		{u.DiscoveryInterval, &p.discoveryInterval},
 		{u.ValidProposalOperationExpire, &p.validProposalOperationExpire},
 		{u.ValidProposalSuffrageOperationsExpire, &p.validProposalSuffrageOperationsExpire},
 		{u.BlockItemReadersRemoveEmptyAfter, &p.blockItemReadersRemoveEmptyAfter},
 		{u.BlockItemReadersRemoveEmptyInterval, &p.blockItemReadersRemoveEmptyInterval},
+		{u.HealthMaxLastBlockElapsed, &p.healthMaxLastBlockElapsed},
+		{u.BlockArchiveInterval, &p.blockArchiveInterval},
 	}
 
 	for i := range durargs {
@@ -342,6 +375,7 @@ type networkParamsYAMLMarshaler struct {
 	ConnectionPoolSize    uint64                                           `json:"connection_pool_size,omitempty" yaml:"connection_pool_size,omitempty"`
 	MaxIncomingStreams    uint64                                           `json:"max_incoming_streams,omitempty" yaml:"max_incoming_streams,omitempty"`
 	MaxStreamTimeout      util.ReadableDuration                            `json:"max_stream_timeout,omitempty" yaml:"max_stream_timeout,omitempty"`
+	HeadExtension         bool                                             `json:"head_extension,omitempty" yaml:"head_extension,omitempty"`
 	//revive:enable:line-length-limit
 }
 
@@ -373,6 +407,7 @@ func (p *NetworkParams) marshaler() networkParamsYAMLMarshaler {
 		ConnectionPoolSize:    p.connectionPoolSize,
 		MaxIncomingStreams:    p.maxIncomingStreams,
 		MaxStreamTimeout:      util.ReadableDuration(p.maxStreamTimeout),
+		HeadExtension:         p.headExtension,
 		RateLimit:             p.rateLimit,
 	}
 }
@@ -396,6 +431,7 @@ type networkParamsYAMLUnmarshaler struct {
 	ConnectionPoolSize    *uint64                                          `json:"connection_pool_size,omitempty" yaml:"connection_pool_size,omitempty"`
 	MaxIncomingStreams    *uint64                                          `json:"max_incoming_streams,omitempty" yaml:"max_incoming_streams,omitempty"`
 	MaxStreamTimeout      *util.ReadableDuration                           `json:"max_stream_timeout,omitempty" yaml:"max_stream_timeout,omitempty"`
+	HeadExtension         *bool                                            `json:"head_extension,omitempty" yaml:"head_extension,omitempty"`
 	RateLimit             *NetworkRateLimitParams                          `json:"ratelimit,omitempty" yaml:"ratelimit,omitempty"` //nolint:tagliatelle //...
 	//revive:enable:line-length-limit
 }
@@ -467,6 +503,10 @@ func (p *NetworkParams) unmarshal(u networkParamsYAMLUnmarshaler) error {
 		p.maxIncomingStreams = *u.MaxIncomingStreams
 	}
 
+	if u.HeadExtension != nil {
+		p.headExtension = *u.HeadExtension
+	}
+
 	if u.RateLimit != nil {
 		p.rateLimit = u.RateLimit
 	}
@@ -0,0 +1,293 @@
+package launch
+
+import (
+	"context"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	isaacdatabase "github.com/ProtoconNet/mitum2/isaac/database"
+	isaacstates "github.com/ProtoconNet/mitum2/isaac/states"
+	"github.com/ProtoconNet/mitum2/network/quicstream"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/metrics"
+	"github.com/ProtoconNet/mitum2/util/ps"
+)
+
+var (
+	PNameMetrics      = ps.Name("metrics")
+	PNamePatchMetrics = ps.Name("patch-metrics")
+	MetricsContextKey = util.ContextKey("metrics")
+)
+
+var allMetricsStates = []isaacstates.StateType{
+	isaacstates.StateStopped,
+	isaacstates.StateBooting,
+	isaacstates.StateJoining,
+	isaacstates.StateConsensus,
+	isaacstates.StateSyncing,
+	isaacstates.StateHandover,
+	isaacstates.StateBroken,
+}
+
+// Metrics collects the runtime metrics of node.
+type Metrics struct {
+	*metrics.Registry
+	blockHeight     *metrics.Gauge
+	blockInterval   *metrics.Histogram
+	round           *metrics.Gauge
+	roundsPerHeight *metrics.Histogram
+	state           *metrics.Gauge
+	stateSwitches   *metrics.Counter
+	ballots         *metrics.Counter
+	operations      *metrics.Counter
+	handlerLatency  *metrics.Histogram
+	lastManifest    *util.Locked[base.Manifest]
+	lastVoteproof   *util.Locked[base.Height]
+	lastState       *util.Locked[isaacstates.StateType]
+}
+
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		Registry: metrics.NewRegistry(),
+		blockHeight: metrics.NewGauge(
+			"mitum_block_height", "height of last saved block"),
+		blockInterval: metrics.NewHistogram(
+			"mitum_block_interval_seconds", "time between the proposals of blocks",
+			[]float64{0.5, 1, 2, 3, 5, 8, 13, 21, 34, 60}, //nolint:gomnd //...
+		),
+		round: metrics.NewGauge(
+			"mitum_consensus_round", "round of last voteproof"),
+		roundsPerHeight: metrics.NewHistogram(
+			"mitum_consensus_rounds_per_height", "number of rounds to decide block",
+			[]float64{1, 2, 3, 5, 8, 13}, //nolint:gomnd //...
+		),
+		state: metrics.NewGauge(
+			"mitum_state", "current state; 1 is current", "state"),
+		stateSwitches: metrics.NewCounter(
+			"mitum_state_switches_total", "number of state switches", "from", "to"),
+		ballots: metrics.NewCounter(
+			"mitum_ballotbox_votes_total", "number of ballots voted in ballotbox", "stage", "in_voteproof"),
+		operations: metrics.NewCounter(
+			"mitum_operations_processed_total", "number of processed operations by hint", "hint", "result"),
+		handlerLatency: metrics.NewHistogram(
+			"mitum_quicstream_handler_seconds", "latency of quicstream handlers", nil, "handler"),
+		lastManifest:  util.EmptyLocked[base.Manifest](),
+		lastVoteproof: util.EmptyLocked[base.Height](),
+		lastState:     util.NewLocked(isaacstates.StateEmpty),
+	}
+
+	_ = m.Register(
+		m.blockHeight,
+		m.blockInterval,
+		m.round,
+		m.roundsPerHeight,
+		m.state,
+		m.stateSwitches,
+		m.ballots,
+		m.operations,
+		m.handlerLatency,
+	)
+
+	return m
+}
+
+// BlockSaved observes the new block; the interval between blocks is measured
+// by the proposed time of manifests.
+func (m *Metrics) BlockSaved(manifest base.Manifest) {
+	_, _ = m.lastManifest.Set(func(prev base.Manifest, isempty bool) (base.Manifest, error) {
+		switch {
+		case isempty:
+		case manifest.Height() <= prev.Height():
+			return nil, util.ErrLockedSetIgnore
+		case manifest.Height() == prev.Height()+1:
+			m.blockInterval.Observe(manifest.ProposedAt().Sub(prev.ProposedAt()).Seconds())
+		}
+
+		m.blockHeight.Set(float64(manifest.Height()))
+
+		return manifest, nil
+	})
+}
+
+func (m *Metrics) NewVoteproof(vp base.Voteproof) {
+	point := vp.Point()
+
+	m.round.Set(float64(point.Round()))
+
+	_, _ = m.lastVoteproof.Set(func(prev base.Height, isempty bool) (base.Height, error) {
+		if !isempty && point.Height() <= prev {
+			return prev, util.ErrLockedSetIgnore
+		}
+
+		return point.Height(), nil
+	})
+
+	if point.Stage() == base.StageACCEPT && vp.Result() == base.VoteResultMajority {
+		m.roundsPerHeight.Observe(float64(point.Round() + 1))
+	}
+}
+
+func (m *Metrics) StateSwitched(next isaacstates.StateType) {
+	prev, _ := m.lastState.Value()
+	_ = m.lastState.SetValue(next)
+
+	if prev != isaacstates.StateEmpty {
+		m.stateSwitches.Inc(prev.String(), next.String())
+	}
+
+	for i := range allMetricsStates {
+		var v float64
+		if allMetricsStates[i] == next {
+			v = 1
+		}
+
+		m.state.Set(v, allMetricsStates[i].String())
+	}
+}
+
+func (m *Metrics) BallotVoted(sf base.BallotSignFact, inVoteproof bool) {
+	stage := base.StageUnknown
+
+	if i, ok := sf.Fact().(base.BallotFact); ok {
+		stage = i.Point().Stage()
+	}
+
+	result := "false"
+	if inVoteproof {
+		result = "true"
+	}
+
+	m.ballots.Inc(stage.String(), result)
+}
+
+// OperationProcessed counts the processed operation of saved block.
+func (m *Metrics) OperationProcessed(op base.Operation, reason base.OperationProcessReasonError) {
+	result := "processed"
+	if reason != nil {
+		result = "rejected"
+	}
+
+	m.operations.Inc(op.Hint().String(), result)
+}
+
+// QuicstreamInstrument returns quicstream.Instrument, which observes the
+// latencies of handlers.
+func (m *Metrics) QuicstreamInstrument() quicstream.Instrument {
+	return metricsQuicstreamInstrument{m: m}
+}
+
+// SetStorage registers the metrics of pool and database.
+func (m *Metrics) SetStorage(pool *isaacdatabase.TempPool, db *isaacdatabase.Center) error {
+	return m.Register(
+		metrics.NewGaugeFunc("mitum_pool_operations", "number of operations in pool", func() (float64, bool) {
+			return float64(pool.OperationsLen()), true
+		}),
+		metrics.NewGaugeFunc("mitum_database_temps", "number of temp databases", func() (float64, bool) {
+			return float64(db.TempsLen()), true
+		}),
+		metrics.NewGaugeFunc("mitum_sync_lag_blocks",
+			"difference between the height of last voteproof and last block",
+			func() (float64, bool) {
+				vh, isempty := m.lastVoteproof.Value()
+				if isempty {
+					return 0, false
+				}
+
+				var height base.Height
+
+				switch i, found, err := db.LastBlockMap(); {
+				case err != nil:
+					return 0, false
+				case found:
+					height = i.Manifest().Height()
+				}
+
+				if vh <= height {
+					return 0, true
+				}
+
+				return float64(vh - height), true
+			},
+		),
+	)
+}
+
+type metricsQuicstreamInstrument struct {
+	m *Metrics
+}
+
+func (metricsQuicstreamInstrument) ConnectionOpened(string, bool)        {}
+func (metricsQuicstreamInstrument) ConnectionClosed(string, bool)        {}
+func (metricsQuicstreamInstrument) ConnectionFailed(string, bool, error) {}
+func (metricsQuicstreamInstrument) StreamStarted(string, bool)           {}
+
+func (i metricsQuicstreamInstrument) StreamEnded(_ string, inbound bool, e quicstream.StreamEnd) {
+	if !inbound || len(e.Handler) < 1 {
+		return
+	}
+
+	i.m.handlerLatency.Observe(e.Elapsed.Seconds(), e.Handler.String())
+}
+
+func PMetrics(pctx context.Context) (context.Context, error) {
+	var m *Metrics
+
+	if err := util.LoadFromContext(pctx, MetricsContextKey, &m); err != nil {
+		return pctx, err
+	}
+
+	if m == nil {
+		m = NewMetrics()
+	}
+
+	return context.WithValue(pctx, MetricsContextKey, m), nil
+}
+
+func PPatchMetrics(pctx context.Context) (context.Context, error) {
+	e := util.StringError("patch metrics")
+
+	var m *Metrics
+	var pool *isaacdatabase.TempPool
+	var db *isaacdatabase.Center
+
+	if err := util.LoadFromContextOK(pctx,
+		MetricsContextKey, &m,
+		PoolDatabaseContextKey, &pool,
+		CenterDatabaseContextKey, &db,
+	); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	if err := m.SetStorage(pool, db); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	if i, found, err := db.LastBlockMap(); err == nil && found {
+		m.BlockSaved(i.Manifest())
+	}
+
+	return pctx, nil
+}
+
+// metricsWhenNewBlockSavedFunc observes the last block from database.
+func metricsWhenNewBlockSavedFunc(pctx context.Context) (func(base.Height), error) {
+	var m *Metrics
+	var db isaac.Database
+
+	if err := util.LoadFromContext(pctx,
+		MetricsContextKey, &m,
+		CenterDatabaseContextKey, &db,
+	); err != nil {
+		return nil, err
+	}
+
+	if m == nil || db == nil {
+		return func(base.Height) {}, nil
+	}
+
+	return func(base.Height) {
+		if i, found, err := db.LastBlockMap(); err == nil && found {
+			m.BlockSaved(i.Manifest())
+		}
+	}, nil
+}
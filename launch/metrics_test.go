@@ -0,0 +1,110 @@
+package launch
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	isaacstates "github.com/ProtoconNet/mitum2/isaac/states"
+	"github.com/ProtoconNet/mitum2/network/quicstream"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testMetrics struct {
+	suite.Suite
+}
+
+func (t *testMetrics) text(m *Metrics) string {
+	buf := bytes.NewBuffer(nil)
+	t.NoError(m.WriteText(buf))
+
+	return buf.String()
+}
+
+func (t *testMetrics) newManifest(height base.Height, proposedAt time.Time) base.Manifest {
+	return isaac.NewManifest(
+		height,
+		valuehash.RandomSHA256(),
+		valuehash.RandomSHA256(),
+		nil,
+		nil,
+		valuehash.RandomSHA256(),
+		proposedAt,
+	)
+}
+
+func (t *testMetrics) TestBlockSaved() {
+	m := NewMetrics()
+
+	now := time.Now()
+
+	m.BlockSaved(t.newManifest(33, now))
+	m.BlockSaved(t.newManifest(34, now.Add(time.Second*2)))
+	m.BlockSaved(t.newManifest(33, now.Add(time.Second*3))) // NOTE lower height; ignored
+
+	s := t.text(m)
+
+	t.Contains(s, "\nmitum_block_height 34\n")
+	t.Contains(s, `mitum_block_interval_seconds_bucket{le="1"} 0`)
+	t.Contains(s, `mitum_block_interval_seconds_bucket{le="2"} 1`)
+	t.Contains(s, "\nmitum_block_interval_seconds_count 1\n")
+}
+
+func (t *testMetrics) TestStateSwitched() {
+	m := NewMetrics()
+
+	m.StateSwitched(isaacstates.StateBooting)
+	m.StateSwitched(isaacstates.StateSyncing)
+
+	s := t.text(m)
+
+	t.Contains(s, `mitum_state{state="SYNCING"} 1`)
+	t.Contains(s, `mitum_state{state="BOOTING"} 0`)
+	t.NotContains(s, `to="BOOTING"`)
+	t.Contains(s, `mitum_state_switches_total{from="BOOTING",to="SYNCING"} 1`)
+}
+
+func (t *testMetrics) TestOperationProcessed() {
+	m := NewMetrics()
+
+	priv := base.NewMPrivatekey()
+	networkID := base.NetworkID(util.UUID().Bytes())
+
+	fact := isaac.NewDummyOperationFact(util.UUID().Bytes(), valuehash.RandomSHA256())
+	op, err := isaac.NewDummyOperation(fact, priv, networkID)
+	t.NoError(err)
+
+	m.OperationProcessed(op, nil)
+	m.OperationProcessed(op, nil)
+	m.OperationProcessed(op, base.NewBaseOperationProcessReasonError("showme"))
+
+	s := t.text(m)
+
+	ht := op.Hint().String()
+	t.Contains(s, `mitum_operations_processed_total{hint="`+ht+`",result="processed"} 2`)
+	t.Contains(s, `mitum_operations_processed_total{hint="`+ht+`",result="rejected"} 1`)
+}
+
+func (t *testMetrics) TestQuicstreamInstrument() {
+	m := NewMetrics()
+
+	i := m.QuicstreamInstrument()
+
+	i.StreamEnded("a", true, quicstream.StreamEnd{Handler: "echo", Elapsed: time.Millisecond * 30})
+	i.StreamEnded("a", false, quicstream.StreamEnd{Handler: "echo", Elapsed: time.Millisecond * 30}) // NOTE outbound; ignored
+	i.StreamEnded("a", true, quicstream.StreamEnd{Elapsed: time.Millisecond * 30})                   // NOTE no handler; ignored
+
+	s := t.text(m)
+
+	t.Contains(s, `mitum_quicstream_handler_seconds_count{handler="echo"} 1`)
+	t.Equal(1, strings.Count(s, "mitum_quicstream_handler_seconds_count"))
+}
+
+func TestMetrics(t *testing.T) {
+	suite.Run(t, new(testMetrics))
+}
@@ -0,0 +1,74 @@
+package launch
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+)
+
+var defaultOperationGossipExpire = time.Minute * 3
+
+// OperationGossip relays the new operations to the other nodes. Operation is
+// broadcasted by memberlist callback broadcast, which delivers the message to
+// the random subset of alive members. The node, which newly accepts the
+// operation into pool, relays it again, so operation reaches to all the
+// members. The already relayed operation is ignored until expired.
+type OperationGossip struct {
+	seen       util.GCache[string, bool]
+	broadcastf func(b []byte, id string) error
+	expire     time.Duration
+	sync.Mutex
+}
+
+func NewOperationGossip(
+	broadcastf func(b []byte, id string) error,
+	expire time.Duration,
+) *OperationGossip {
+	if expire < 1 {
+		expire = defaultOperationGossipExpire //revive:disable-line:modifies-parameter
+	}
+
+	return &OperationGossip{
+		seen:       util.NewLRUGCache[string, bool](1 << 13), //nolint:gomnd //...
+		broadcastf: broadcastf,
+		expire:     expire,
+	}
+}
+
+// Relay broadcasts the encoded operation, b. If the operation was already
+// relayed, it returns false.
+func (g *OperationGossip) Relay(op base.Operation, b []byte) (bool, error) {
+	id := op.Hash().String()
+
+	if !g.setSeen(id) {
+		return false, nil
+	}
+
+	if err := g.broadcastf(b, id); err != nil {
+		_ = g.seen.Remove(id)
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Seen checks whether the operation was already relayed.
+func (g *OperationGossip) Seen(op util.Hash) bool {
+	return g.seen.Exists(op.String())
+}
+
+func (g *OperationGossip) setSeen(id string) bool {
+	g.Lock()
+	defer g.Unlock()
+
+	if g.seen.Exists(id) {
+		return false
+	}
+
+	g.seen.Set(id, true, g.expire)
+
+	return true
+}
@@ -0,0 +1,100 @@
+package launch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/valuehash"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/suite"
+)
+
+type testOperationGossip struct {
+	suite.Suite
+	priv      base.Privatekey
+	networkID base.NetworkID
+}
+
+func (t *testOperationGossip) SetupSuite() {
+	t.priv = base.NewMPrivatekey()
+	t.networkID = util.UUID().Bytes()
+}
+
+func (t *testOperationGossip) newOperation() base.Operation {
+	fact := isaac.NewDummyOperationFact(util.UUID().Bytes(), valuehash.RandomSHA256())
+	op, err := isaac.NewDummyOperation(fact, t.priv, t.networkID)
+	t.NoError(err)
+
+	return op
+}
+
+func (t *testOperationGossip) TestRelay() {
+	var broadcasted []string
+
+	g := NewOperationGossip(func(_ []byte, id string) error {
+		broadcasted = append(broadcasted, id)
+
+		return nil
+	}, time.Minute)
+
+	op := t.newOperation()
+
+	t.False(g.Seen(op.Hash()))
+
+	relayed, err := g.Relay(op, util.UUID().Bytes())
+	t.NoError(err)
+	t.True(relayed)
+	t.True(g.Seen(op.Hash()))
+
+	t.Run("relay again", func() {
+		relayed, err := g.Relay(op, util.UUID().Bytes())
+		t.NoError(err)
+		t.False(relayed)
+	})
+
+	t.Run("another operation", func() {
+		relayed, err := g.Relay(t.newOperation(), util.UUID().Bytes())
+		t.NoError(err)
+		t.True(relayed)
+	})
+
+	t.Equal(2, len(broadcasted))
+	t.Equal(op.Hash().String(), broadcasted[0])
+}
+
+func (t *testOperationGossip) TestBroadcastError() {
+	var failed bool
+
+	g := NewOperationGossip(func([]byte, string) error {
+		if failed {
+			return errors.Errorf("hehehe")
+		}
+
+		return nil
+	}, time.Minute)
+
+	op := t.newOperation()
+
+	failed = true
+
+	relayed, err := g.Relay(op, util.UUID().Bytes())
+	t.Error(err)
+	t.ErrorContains(err, "hehehe")
+	t.False(relayed)
+	t.False(g.Seen(op.Hash()))
+
+	t.Run("retry", func() {
+		failed = false
+
+		relayed, err := g.Relay(op, util.UUID().Bytes())
+		t.NoError(err)
+		t.True(relayed)
+	})
+}
+
+func TestOperationGossip(t *testing.T) {
+	suite.Run(t, new(testOperationGossip))
+}
@@ -2,6 +2,8 @@ package launch
 
 import (
 	"context"
+	"strings"
+
 	"github.com/ProtoconNet/mitum2/base"
 	"github.com/ProtoconNet/mitum2/util"
 	"github.com/ProtoconNet/mitum2/util/encoder"
@@ -79,14 +81,29 @@ func PLoadDesign(pctx context.Context) (context.Context, error) {
 
 	log.Log().Debug().Interface("design", design).Msg("design loaded")
 
-	if len(privstring) > 0 {
-		priv, err := base.DecodePrivatekeyFromString(privstring, jsonencoder)
+	switch {
+	case len(privstring) > 0:
+		priv, err := decodePrivatekeyOrKeystore(privstring, jsonencoder)
 		if err != nil {
 			return pctx, e.Wrap(err)
 		}
 
 		log.Log().Debug().Interface("publickey", priv.Publickey()).Msg("privatekey loaded from somewhere")
 
+		design.Privatekey = priv
+		design.keystore = ""
+
+		if IsKeystoreURL(privstring) {
+			design.keystore = strings.TrimSpace(privstring)
+		}
+	case len(design.keystore) > 0:
+		priv, err := LoadKeystorePrivatekey(design.keystore, jsonencoder)
+		if err != nil {
+			return pctx, e.Wrap(err)
+		}
+
+		log.Log().Debug().Interface("publickey", priv.Publickey()).Msg("privatekey loaded from keystore")
+
 		design.Privatekey = priv
 	}
 
@@ -175,5 +192,11 @@ func PINITObjectCache(pctx context.Context) (context.Context, error) {
 
 	log.Log().Debug().Uint64("cache_size", cachesize).Msg("set object cache size")
 
+	signcachesize := design.LocalParams.MISC.SignCacheSize()
+
+	base.SetVerifiedSignCache(util.NewLRUGCache[string, struct{}](int(signcachesize)))
+
+	log.Log().Debug().Uint64("cache_size", signcachesize).Msg("set verified sign cache size")
+
 	return pctx, nil
 }
@@ -3,15 +3,23 @@ package launch
 import (
 	"context"
 
+	isaacnetwork "github.com/ProtoconNet/mitum2/isaac/network"
+	"github.com/ProtoconNet/mitum2/network"
+	"github.com/ProtoconNet/mitum2/network/quicmemberlist"
 	"github.com/ProtoconNet/mitum2/network/quicstream"
 	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/logging"
 	"github.com/ProtoconNet/mitum2/util/ps"
+	"github.com/pkg/errors"
 )
 
 var (
-	PNameDiscoveryFlag      = ps.Name("discovery-flag")
-	DiscoveryFlagContextKey = util.ContextKey("discovery-flag")
-	DiscoveryContextKey     = util.ContextKey("discovery")
+	PNameDiscoveryFlag         = ps.Name("discovery-flag")
+	PNameDiscoveryUpdater      = ps.Name("discovery-updater")
+	PNameStartDiscoveryUpdater = ps.Name("start-discovery-updater")
+	DiscoveryFlagContextKey    = util.ContextKey("discovery-flag")
+	DiscoveryContextKey        = util.ContextKey("discovery")
+	DiscoveryUpdaterContextKey = util.ContextKey("discovery-updater")
 )
 
 func PDiscoveryFlag(pctx context.Context) (context.Context, error) {
@@ -45,3 +53,149 @@ func GetDiscoveriesFromLocked(l *util.Locked[[]quicstream.ConnInfo]) []quicstrea
 		return i
 	}
 }
+
+// PDiscoveryUpdater prepares DiscoveryUpdater from the discovery providers of
+// design. The discovered ones are added to the discoveries and the dynamic
+// sync sources; if memberlist is not yet joined, it tries to join with the new
+// discoveries.
+func PDiscoveryUpdater(pctx context.Context) (context.Context, error) {
+	e := util.StringError("prepare discovery updater")
+
+	var log *logging.Logging
+	var design NodeDesign
+	var params *LocalParams
+	var discoveries *util.Locked[[]quicstream.ConnInfo]
+	var m *quicmemberlist.Memberlist
+	var long *LongRunningMemberlistJoin
+	var syncSourceChecker *isaacnetwork.SyncSourceChecker
+
+	if err := util.LoadFromContextOK(pctx,
+		LoggingContextKey, &log,
+		DesignContextKey, &design,
+		LocalParamsContextKey, &params,
+		DiscoveryContextKey, &discoveries,
+		MemberlistContextKey, &m,
+		LongRunningMemberlistJoinContextKey, &long,
+		SyncSourceCheckerContextKey, &syncSourceChecker,
+	); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	var providers []DiscoveryProvider
+
+	switch i, err := design.Discoveries(); {
+	case err != nil:
+		return pctx, e.Wrap(err)
+	case len(i) < 1:
+		return pctx, nil
+	default:
+		providers = i
+	}
+
+	updateSyncSources := discoverySyncSourcesFunc(design.Network.PublishConnInfo(), syncSourceChecker)
+
+	updater := NewDiscoveryUpdater(
+		providers,
+		discoveries,
+		params.MISC.DiscoveryInterval,
+		func(ctx context.Context, cis []quicstream.ConnInfo) {
+			if len(GetDiscoveriesFromLocked(discoveries)) > 0 && !m.IsJoined() {
+				_ = long.Join()
+			}
+
+			if err := updateSyncSources(ctx, cis); err != nil {
+				log.Log().Error().Err(err).Msg("failed to update sync sources from discoveries")
+			}
+		},
+	)
+	_ = updater.SetLogging(log)
+
+	return context.WithValue(pctx, DiscoveryUpdaterContextKey, updater), nil
+}
+
+func PStartDiscoveryUpdater(pctx context.Context) (context.Context, error) {
+	var updater *DiscoveryUpdater
+	if err := util.LoadFromContext(pctx, DiscoveryUpdaterContextKey, &updater); err != nil {
+		return pctx, err
+	}
+
+	if updater == nil {
+		return pctx, nil
+	}
+
+	return pctx, updater.Start(context.Background())
+}
+
+func PCloseDiscoveryUpdater(pctx context.Context) (context.Context, error) {
+	var updater *DiscoveryUpdater
+	if err := util.LoadFromContext(pctx, DiscoveryUpdaterContextKey, &updater); err != nil {
+		return pctx, err
+	}
+
+	if updater != nil {
+		if err := updater.Stop(); err != nil && !errors.Is(err, util.ErrDaemonAlreadyStopped) {
+			return pctx, err
+		}
+	}
+
+	return pctx, nil
+}
+
+// discoverySyncSourcesFunc keeps the discovered ones in the sync sources of
+// SyncSourceChecker. The discovered ones are added as
+// SyncSourceTypeSuffrageNodes and the previously discovered ones are removed.
+// The sync sources from design are kept.
+func discoverySyncSourcesFunc(
+	publish quicstream.ConnInfo,
+	syncSourceChecker *isaacnetwork.SyncSourceChecker,
+) func(context.Context, []quicstream.ConnInfo) error {
+	var prev []isaacnetwork.SyncSource
+
+	return func(ctx context.Context, cis []quicstream.ConnInfo) error {
+		sources := syncSourceChecker.Sources()
+
+		others := util.Filter2Slices(sources, prev, isEqualDiscoverySyncSource)
+
+		news := make([]isaacnetwork.SyncSource, 0, len(cis))
+
+		for i := range cis {
+			if network.EqualConnInfo(cis[i], publish) {
+				continue
+			}
+
+			news = append(news, isaacnetwork.SyncSource{
+				Type:   isaacnetwork.SyncSourceTypeSuffrageNodes,
+				Source: cis[i],
+			})
+		}
+
+		news = util.Filter2Slices(news, others, isEqualDiscoverySyncSource)
+		prev = news
+
+		n := append(others, news...) //nolint:gocritic //...
+
+		if len(n) == len(sources) && len(util.Filter2Slices(n, sources, isEqualDiscoverySyncSource)) < 1 {
+			return nil
+		}
+
+		return syncSourceChecker.UpdateSources(ctx, n)
+	}
+}
+
+func isEqualDiscoverySyncSource(a, b isaacnetwork.SyncSource) bool {
+	if a.Type != b.Type {
+		return false
+	}
+
+	aci, ok := a.Source.(quicstream.ConnInfo)
+	if !ok {
+		return false
+	}
+
+	bci, ok := b.Source.(quicstream.ConnInfo)
+	if !ok {
+		return false
+	}
+
+	return aci.String() == bci.String()
+}
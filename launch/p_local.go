@@ -35,6 +35,12 @@ func PLocal(pctx context.Context) (context.Context, error) {
 		return pctx, e.Wrap(err)
 	}
 
+	if i, ok := design.Privatekey.(*RemoteSignerPrivatekey); ok {
+		if err := i.Check(); err != nil {
+			return pctx, e.Wrap(err)
+		}
+	}
+
 	log.Log().Debug().Interface("local", local).Msg("local loaded")
 
 	return context.WithValue(pctx, LocalContextKey, local), nil
@@ -47,5 +53,9 @@ func LocalFromDesign(design NodeDesign) (base.LocalNode, error) {
 		return nil, err
 	}
 
+	if design.RotatePrivatekey != nil {
+		return NewKeyRotatableLocalNode(local, design.RotatePrivatekey), nil
+	}
+
 	return local, nil
 }
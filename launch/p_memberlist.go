@@ -33,6 +33,7 @@ var (
 	SuffrageVotingContextKey                = util.ContextKey("suffrage-voting")
 	SuffrageVotingVoteFuncContextKey        = util.ContextKey("suffrage-voting-vote-func")
 	FilterMemberlistNotifyMsgFuncContextKey = util.ContextKey("filter-memberlist-notify-msg-func")
+	OperationGossipContextKey               = util.ContextKey("operation-gossip")
 )
 
 var HandlerNameMemberlist quicstream.HandlerName = "memberlist"
@@ -62,6 +63,7 @@ func PMemberlist(pctx context.Context) (context.Context, error) {
 		connectionPool.Dial,
 		encs,
 		encs.Default(),
+		params.Network.HeadExtension,
 	)
 
 	localnode, err := memberlistLocalNode(pctx)
@@ -76,6 +78,11 @@ func PMemberlist(pctx context.Context) (context.Context, error) {
 
 	args := quicmemberlist.NewMemberlistArgs(encs.JSON(), config)
 	args.ExtraSameMemberLimit = params.Memberlist.ExtraSameMemberLimit
+	args.WhenRelayedFunc = func(ci, relay quicstream.ConnInfo) {
+		if connectionPool.SetRelay(ci, relay) {
+			log.Log().Debug().Interface("conninfo", ci).Interface("relay", relay).Msg("relay updated")
+		}
+	}
 	args.FetchCallbackBroadcastMessageFunc = quicmemberlist.FetchCallbackBroadcastMessageFunc(
 		handlerPrefixMemberlistCallbackBroadcastMessage,
 		headerdial,
@@ -104,9 +111,17 @@ func PMemberlist(pctx context.Context) (context.Context, error) {
 		}
 	})
 
+	gossip := NewOperationGossip(
+		func(b []byte, id string) error {
+			return m.CallbackBroadcast(b, id, nil)
+		},
+		0,
+	)
+
 	return util.ContextWithValues(pctx, map[util.ContextKey]interface{}{
 		MemberlistContextKey:          m,
 		EventWhenMemberLeftContextKey: pps,
+		OperationGossipContextKey:     gossip,
 		FilterMemberlistNotifyMsgFuncContextKey: quicmemberlist.FilterNotifyMsgFunc(
 			func(interface{}) (bool, error) { return true, nil },
 		),
@@ -179,6 +194,9 @@ func patchMemberlistNotifyMsg(pctx context.Context) (context.Context, error) {
 	var svvotef isaac.SuffrageVoteFunc
 	var filternotifymsg quicmemberlist.FilterNotifyMsgFunc
 	var oppool *isaacdatabase.TempPool
+	var gossip *OperationGossip
+	var db isaac.Database
+	var states *isaacstates.States
 
 	if err := util.LoadFromContextOK(pctx,
 		LoggingContextKey, &log,
@@ -189,10 +207,29 @@ func patchMemberlistNotifyMsg(pctx context.Context) (context.Context, error) {
 		SuffrageVotingVoteFuncContextKey, &svvotef,
 		FilterMemberlistNotifyMsgFuncContextKey, &filternotifymsg,
 		PoolDatabaseContextKey, &oppool,
+		OperationGossipContextKey, &gossip,
+		CenterDatabaseContextKey, &db,
+		StatesContextKey, &states,
 	); err != nil {
 		return pctx, err
 	}
 
+	sendOperationFilterf, err := SendOperationFilterFunc(pctx)
+	if err != nil {
+		return pctx, err
+	}
+
+	// NOTE the gossiped operation is handled like the operation from
+	// HandlerNameSendOperation handler.
+	newOperationf := isaacnetwork.NewOperationFunc(
+		isaacparams.NetworkID(),
+		oppool,
+		db.ExistsInStateOperation,
+		sendOperationFilterf,
+		svvotef,
+		relayNewOperationFunc(log, states, gossip),
+	)
+
 	l := log.Log().With().Str("module", "filter-notify-msg-memberlist").Logger()
 
 	m.SetNotifyMsg(func(b []byte, enc encoder.Encoder) {
@@ -264,12 +301,18 @@ func patchMemberlistNotifyMsg(pctx context.Context) (context.Context, error) {
 				}
 			}
 		case base.Operation:
-			switch isset, err := oppool.SetOperation(context.Background(), t); {
-			case err != nil:
-				l.Error().Err(err).Msg("failed to set operation")
-			default:
-				l.Debug().Bool("set", isset).Msg("set operation")
+			if gossip.Seen(t.Hash()) {
+				return
+			}
+
+			isset, err := newOperationf(context.Background(), t, b)
+			if err != nil {
+				l.Trace().Err(err).Interface("operation", t.Hash()).Msg("new operation; filtered")
+
+				return
 			}
+
+			l.Debug().Interface("operation", t.Hash()).Bool("set", isset).Msg("set operation")
 		default:
 			l.Debug().Interface("notify_message", m).Msgf("new incoming message; ignored; but unknown, %T", t)
 		}
@@ -343,7 +386,7 @@ func memberlistLocalNode(pctx context.Context) (quicmemberlist.Member, error) {
 		return nil, err
 	}
 
-	return quicmemberlist.NewMember(
+	member, err := quicmemberlist.NewMember(
 		fsnodeinfo.ID(),
 		design.Network.Publish(),
 		local.Address(),
@@ -351,6 +394,15 @@ func memberlistLocalNode(pctx context.Context) (quicmemberlist.Member, error) {
 		design.Network.PublishString,
 		design.Network.TLSInsecure,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	if relay, found := design.Network.Relay(); found {
+		return member.WithRelay(relay)
+	}
+
+	return member, nil
 }
 
 func memberlistConfig(
@@ -445,6 +497,10 @@ func memberlistConfig(
 				l.Debug().Msg("member removed from client pool")
 			}
 
+			if connectionPool.RemoveRelay(member.ConnInfo()) {
+				l.Debug().Msg("relay of member removed from client pool")
+			}
+
 			nci := isaacnetwork.NewNodeConnInfoFromMemberlistNode(member)
 			if syncSourcePool.RemoveNonFixed(nci) {
 				l.Debug().Msg("member removed from sync source pool")
@@ -543,11 +599,13 @@ func nodeChallengeFunc(pctx context.Context) (
 	var local base.LocalNode
 	var params *LocalParams
 	var client *isaacnetwork.BaseClient
+	var connectionPool *quicstream.ConnectionPool
 
 	if err := util.LoadFromContextOK(pctx,
 		LocalContextKey, &local,
 		LocalParamsContextKey, &params,
 		QuicstreamClientContextKey, &client,
+		ConnectionPoolContextKey, &connectionPool,
 	); err != nil {
 		return nil, err
 	}
@@ -561,13 +619,33 @@ func nodeChallengeFunc(pctx context.Context) (
 			return errors.WithMessage(err, "invalid memberlist node publickey")
 		}
 
+		// NOTE member behind NAT can be reached thru relay; the relay is kept
+		// after challenge passed.
+		relay, hasrelay := node.Relay()
+
+		challengeClient := client
+
+		if hasrelay {
+			challengeClient = isaacnetwork.NewBaseClient(
+				client.Encoders, client.Encoder,
+				func(ctx context.Context, i quicstream.ConnInfo) (quicstream.Streamer, error) {
+					if network.EqualConnInfo(i, ci) {
+						return connectionPool.DialRelay(ctx, i, relay)
+					}
+
+					return connectionPool.Dial(ctx, i)
+				},
+				func() error { return nil },
+			).SetHeadExtensionFunc(params.Network.HeadExtension)
+		}
+
 		input := util.UUID().Bytes()
 
 		sig, err := func() (base.Signature, error) {
 			ctx, cancel := context.WithTimeout(context.Background(), params.Network.TimeoutRequest())
 			defer cancel()
 
-			return client.NodeChallenge(
+			return challengeClient.NodeChallenge(
 				ctx, node.ConnInfo(), params.ISAAC.NetworkID(),
 				node.Address(), node.Publickey(), input, local,
 			)
@@ -581,7 +659,7 @@ func nodeChallengeFunc(pctx context.Context) (
 			ctx, cancel := context.WithTimeout(context.Background(), params.Network.TimeoutRequest())
 			defer cancel()
 
-			psig, err := client.NodeChallenge(
+			psig, err := challengeClient.NodeChallenge(
 				ctx, ci, params.ISAAC.NetworkID(),
 				node.Address(), node.Publickey(), input, local,
 			)
@@ -594,6 +672,10 @@ func nodeChallengeFunc(pctx context.Context) (
 			}
 		}
 
+		if hasrelay {
+			_ = connectionPool.SetRelay(ci, relay)
+		}
+
 		return nil
 	}, nil
 }
@@ -22,13 +22,16 @@ import (
 )
 
 var (
-	PNameNetwork                 = ps.Name("network")
-	PNameStartNetwork            = ps.Name("start-network")
-	PNameQuicstreamClient        = ps.Name("network-client")
-	QuicstreamClientContextKey   = util.ContextKey("network-client")
-	QuicstreamServerContextKey   = util.ContextKey("quicstream-server")
-	QuicstreamHandlersContextKey = util.ContextKey("quicstream-handlers")
-	ConnectionPoolContextKey     = util.ContextKey("network-connection-pool")
+	PNameNetwork                   = ps.Name("network")
+	PNameStartNetwork              = ps.Name("start-network")
+	PNameQuicstreamClient          = ps.Name("network-client")
+	QuicstreamClientContextKey     = util.ContextKey("network-client")
+	QuicstreamServerContextKey     = util.ContextKey("quicstream-server")
+	QuicstreamHandlersContextKey   = util.ContextKey("quicstream-handlers")
+	ConnectionPoolContextKey       = util.ContextKey("network-connection-pool")
+	RelayContextKey                = util.ContextKey("network-relay")
+	RelayClientContextKey          = util.ContextKey("network-relay-client")
+	QuicstreamInstrumentContextKey = util.ContextKey("quicstream-instrument")
 )
 
 func PQuicstreamClient(pctx context.Context) (context.Context, error) {
@@ -42,6 +45,16 @@ func PQuicstreamClient(pctx context.Context) (context.Context, error) {
 		return pctx, errors.WithMessage(err, "network client")
 	}
 
+	var instrument *quicstream.InstrumentStats
+
+	if err := util.LoadFromContext(pctx, QuicstreamInstrumentContextKey, &instrument); err != nil {
+		return pctx, errors.WithMessage(err, "network client")
+	}
+
+	if instrument == nil {
+		instrument = NewQuicstreamInstrument()
+	}
+
 	connectionPool, err := NewConnectionPool(
 		params.Network.ConnectionPoolSize(),
 		params.ISAAC.NetworkID(),
@@ -51,11 +64,14 @@ func PQuicstreamClient(pctx context.Context) (context.Context, error) {
 		return pctx, err
 	}
 
-	client := NewNetworkClient(encs, encs.Default(), connectionPool) //nolint:gomnd //...
+	connectionPool.SetInstrument(instrument)
+
+	client := NewNetworkClient(encs, encs.Default(), connectionPool, params.Network) //nolint:gomnd //...
 
 	return util.ContextWithValues(pctx, map[util.ContextKey]interface{}{
-		QuicstreamClientContextKey: client,
-		ConnectionPoolContextKey:   connectionPool,
+		QuicstreamClientContextKey:     client,
+		ConnectionPoolContextKey:       connectionPool,
+		QuicstreamInstrumentContextKey: instrument,
 	}), nil
 }
 
@@ -65,11 +81,15 @@ func PNetwork(pctx context.Context) (context.Context, error) {
 	var log *logging.Logging
 	var design NodeDesign
 	var params *LocalParams
+	var connectionPool *quicstream.ConnectionPool
+	var instrument *quicstream.InstrumentStats
 
 	if err := util.LoadFromContextOK(pctx,
 		LoggingContextKey, &log,
 		DesignContextKey, &design,
 		LocalParamsContextKey, &params,
+		ConnectionPoolContextKey, &connectionPool,
+		QuicstreamInstrumentContextKey, &instrument,
 	); err != nil {
 		return pctx, e.Wrap(err)
 	}
@@ -94,27 +114,115 @@ func PNetwork(pctx context.Context) (context.Context, error) {
 
 	_ = server.SetLogging(log)
 
-	return util.ContextWithValues(pctx, map[util.ContextKey]interface{}{
+	var m *Metrics
+
+	switch err := util.LoadFromContext(pctx, MetricsContextKey, &m); {
+	case err != nil:
+		return pctx, e.Wrap(err)
+	case m == nil:
+		server.SetInstrument(instrument)
+	default:
+		server.SetInstrument(quicstream.Instruments{instrument, m.QuicstreamInstrument()})
+	}
+
+	values := map[util.ContextKey]interface{}{
 		QuicstreamServerContextKey:   server,
 		QuicstreamHandlersContextKey: handlers,
-	}), nil
+	}
+
+	if design.Network.RelayServer {
+		var acl *YAMLACL
+		var encs *encoder.Encoders
+
+		if err := util.LoadFromContextOK(pctx,
+			ACLContextKey, &acl,
+			EncodersContextKey, &encs,
+		); err != nil {
+			return pctx, e.Wrap(err)
+		}
+
+		relay, err := quicstream.NewRelay(
+			design.Network.Publish(),
+			params.Network.ConnectionPoolSize(),
+			relayAllowFunc(acl.ACL, params.ISAAC.NetworkID(), encs.JSON(), log),
+		)
+		if err != nil {
+			return pctx, e.Wrap(err)
+		}
+
+		_ = relay.SetLogging(log)
+
+		connectionPool.SetLocalRelay(relay)
+
+		values[RelayContextKey] = relay
+	}
+
+	if relayci, found := design.Network.Relay(); found {
+		var local base.LocalNode
+
+		if err := util.LoadFromContextOK(pctx, LocalContextKey, &local); err != nil {
+			return pctx, e.Wrap(err)
+		}
+
+		relayClient := quicstream.NewRelayClient(
+			relayci,
+			design.Network.Publish().String(),
+			NewConnInfoDialFunc(params.ISAAC.NetworkID(), params.Network),
+			handlers.Handler,
+			relayProofFunc(local.Privatekey(), params.ISAAC.NetworkID()),
+			params.Network.MaxStreamTimeout,
+		)
+
+		_ = relayClient.SetLogging(log)
+
+		values[RelayClientContextKey] = relayClient
+	}
+
+	return util.ContextWithValues(pctx, values), nil
 }
 
 func PStartNetwork(pctx context.Context) (context.Context, error) {
 	var server *quicstream.Server
+	var relayClient *quicstream.RelayClient
+
 	if err := util.LoadFromContextOK(pctx, QuicstreamServerContextKey, &server); err != nil {
 		return pctx, err
 	}
 
-	return pctx, server.Start(context.Background())
+	if err := util.LoadFromContext(pctx, RelayClientContextKey, &relayClient); err != nil {
+		return pctx, err
+	}
+
+	if err := server.Start(context.Background()); err != nil {
+		return pctx, err
+	}
+
+	if relayClient != nil {
+		if err := relayClient.Start(context.Background()); err != nil {
+			return pctx, err
+		}
+	}
+
+	return pctx, nil
 }
 
 func PCloseNetwork(pctx context.Context) (context.Context, error) {
 	var server *quicstream.Server
-	if err := util.LoadFromContext(pctx, QuicstreamServerContextKey, &server); err != nil {
+	var relayClient *quicstream.RelayClient
+
+	if err := util.LoadFromContext(pctx,
+		QuicstreamServerContextKey, &server,
+		RelayClientContextKey, &relayClient,
+	); err != nil {
 		return pctx, err
 	}
 
+	if relayClient != nil {
+		if err := relayClient.Stop(); err != nil && !errors.Is(err, util.ErrDaemonAlreadyStopped) {
+			return pctx, err
+		}
+	}
+
 	if server != nil {
 		if err := server.Stop(); err != nil && !errors.Is(err, util.ErrDaemonAlreadyStopped) {
 			return pctx, err
@@ -124,16 +232,145 @@ func PCloseNetwork(pctx context.Context) (context.Context, error) {
 	return pctx, nil
 }
 
+// AttachRelayNetworkHandlers attaches the relay handlers; if relay server is
+// not enabled, it will be ignored.
+func AttachRelayNetworkHandlers(pctx context.Context) error {
+	var relay *quicstream.Relay
+
+	if err := util.LoadFromContext(pctx, RelayContextKey, &relay); err != nil {
+		return err
+	}
+
+	if relay == nil {
+		return nil
+	}
+
+	var params *LocalParams
+	var handlers *quicstream.PrefixHandler
+	var rateLimitHandler *RateLimitHandler
+
+	if err := util.LoadFromContextOK(pctx,
+		LocalParamsContextKey, &params,
+		QuicstreamHandlersContextKey, &handlers,
+		RateLimiterContextKey, &rateLimitHandler,
+	); err != nil {
+		return err
+	}
+
+	ratelimitf := rateLimitHandlerFunc(
+		rateLimitHandler,
+		func(prefix quicstream.HandlerPrefix) (string, bool) {
+			s, found := NetworkHandlerPrefixMapRev[prefix]
+
+			return s.String(), found
+		},
+	)
+
+	for name, handler := range map[quicstream.HandlerName]quicstream.Handler{
+		quicstream.HandlerNameRelayRegister: relay.RegisterHandler,
+		quicstream.HandlerNameRelayForward:  relay.ForwardHandler,
+	} {
+		timeoutf, err := params.Network.HandlerTimeoutFunc(name)
+		if err != nil {
+			return err
+		}
+
+		_ = handlers.Add(name, ratelimitf(quicstream.TimeoutHandler(handler, timeoutf)))
+	}
+
+	return nil
+}
+
+// RelayACLScope allows the node behind NAT to register to the local relay.
+var RelayACLScope = ACLScope("relay")
+
+type relayProofJSONMarshaler struct {
+	Publickey base.Publickey `json:"publickey"`
+	Signature base.Signature `json:"signature"`
+}
+
+type relayProofJSONUnmarshaler struct {
+	Publickey string         `json:"publickey"`
+	Signature base.Signature `json:"signature"`
+}
+
+func relayProofBytes(networkID base.NetworkID, target string, nonce []byte) []byte {
+	return util.ConcatBytesSlice(networkID, nonce, []byte(target))
+}
+
+// relayProofFunc signs the nonce from relay with the local privatekey.
+func relayProofFunc(priv base.Privatekey, networkID base.NetworkID) quicstream.RelayProofFunc {
+	return func(target string, nonce []byte) ([]byte, error) {
+		sig, err := priv.Sign(relayProofBytes(networkID, target, nonce))
+		if err != nil {
+			return nil, err
+		}
+
+		return util.MarshalJSON(relayProofJSONMarshaler{
+			Publickey: priv.Publickey(),
+			Signature: sig,
+		})
+	}
+}
+
+// relayAllowFunc verifies the proof of registration and allows the publickey
+// of proof, which has the write permission of RelayACLScope.
+func relayAllowFunc(
+	acl *ACL, networkID base.NetworkID, enc encoder.Encoder, log *logging.Logging,
+) quicstream.RelayAllowFunc {
+	return func(target string, nonce, proof []byte) bool {
+		l := log.Log().With().Str("target", target).Logger()
+
+		var u relayProofJSONUnmarshaler
+
+		if err := util.UnmarshalJSON(proof, &u); err != nil {
+			l.Debug().Err(err).Msg("wrong relay proof")
+
+			return false
+		}
+
+		pub, err := base.DecodePublickeyFromString(u.Publickey, enc)
+		if err != nil {
+			l.Debug().Err(err).Msg("wrong publickey of relay proof")
+
+			return false
+		}
+
+		if err := pub.Verify(relayProofBytes(networkID, target, nonce), u.Signature); err != nil {
+			l.Debug().Err(err).Msg("wrong signature of relay proof")
+
+			return false
+		}
+
+		assigned, allow := acl.Allow(pub.String(), RelayACLScope, WriteAllowACLPerm)
+
+		l.Debug().
+			Interface("publickey", pub).
+			Stringer("assigned", assigned).
+			Bool("allowed", allow).
+			Msg("relay acl")
+
+		return allow
+	}
+}
+
 func NewNetworkClient(
 	encs *encoder.Encoders,
 	enc encoder.Encoder,
 	connectionPool *quicstream.ConnectionPool,
+	params *NetworkParams,
 ) *isaacnetwork.BaseClient {
-	return isaacnetwork.NewBaseClient(
+	client := isaacnetwork.NewBaseClient(
 		encs, enc,
 		connectionPool.Dial,
 		connectionPool.CloseAll,
 	)
+
+	if params != nil {
+		_ = client.SetHeadExtensionFunc(params.HeadExtension)
+	}
+
+	return client
 }
 
 func NewConnectionPool(
@@ -145,6 +382,12 @@ func NewConnectionPool(
 	)
 }
 
+// NewQuicstreamInstrument returns the quicstream.InstrumentStats for
+// quicstream server and connection pool.
+func NewQuicstreamInstrument() *quicstream.InstrumentStats {
+	return quicstream.NewInstrumentStats(1 << 9) //nolint:gomnd //...
+}
+
 func NewConnInfoDialFunc(networkID base.NetworkID, params *NetworkParams) quicstream.ConnInfoDialFunc {
 	return quicstream.NewConnInfoDialFunc(
 		func() *quic.Config {
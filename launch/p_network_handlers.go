@@ -9,6 +9,7 @@ import (
 	"github.com/ProtoconNet/mitum2/base"
 	"github.com/ProtoconNet/mitum2/isaac"
 	isaacdatabase "github.com/ProtoconNet/mitum2/isaac/database"
+	isaaclightclient "github.com/ProtoconNet/mitum2/isaac/lightclient"
 	isaacnetwork "github.com/ProtoconNet/mitum2/isaac/network"
 	isaacoperation "github.com/ProtoconNet/mitum2/isaac/operation"
 	isaacstates "github.com/ProtoconNet/mitum2/isaac/states"
@@ -18,6 +19,7 @@ import (
 	"github.com/ProtoconNet/mitum2/storage"
 	"github.com/ProtoconNet/mitum2/util"
 	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/ProtoconNet/mitum2/util/fixedtree"
 	"github.com/ProtoconNet/mitum2/util/hint"
 	"github.com/ProtoconNet/mitum2/util/logging"
 	"github.com/ProtoconNet/mitum2/util/ps"
@@ -32,6 +34,13 @@ var (
 	OperationProcessorsMapContextKey = util.ContextKey("operation-processors-map")
 )
 
+var (
+	StateProofACLScope = ACLScope("state_proof")
+	// MaxStateProofSuffrageProofs limits the number of suffrage proofs of
+	// StateProof; the client continues with the last suffrage proof.
+	MaxStateProofSuffrageProofs = 1 << 6 //nolint:gomnd //...
+)
+
 var (
 	//revive:disable:line-length-limit
 	HandlerNameMemberlistCallbackBroadcastMessage quicstream.HandlerName = "memberlist_callback_broadcast_message"
@@ -62,6 +71,7 @@ func PNetworkHandlers(pctx context.Context) (context.Context, error) {
 	var ballotbox *isaacstates.Ballotbox
 	var filternotifymsg quicmemberlist.FilterNotifyMsgFunc
 	var lvps *isaac.LastVoteproofsHandler
+	var readers *isaac.BlockItemReaders
 
 	if err := util.LoadFromContextOK(pctx,
 		LoggingContextKey, &log,
@@ -79,12 +89,22 @@ func PNetworkHandlers(pctx context.Context) (context.Context, error) {
 		BallotboxContextKey, &ballotbox,
 		FilterMemberlistNotifyMsgFuncContextKey, &filternotifymsg,
 		LastVoteproofsHandlerContextKey, &lvps,
+		BlockItemReadersContextKey, &readers,
 	); err != nil {
 		return pctx, e.Wrap(err)
 	}
 
 	isaacparams := params.ISAAC
 
+	var aclallow ACLAllowFunc
+
+	switch i, err := pACLAllowFunc(pctx); {
+	case err != nil:
+		return pctx, e.Wrap(err)
+	default:
+		aclallow = i
+	}
+
 	lastBlockMapf := QuicstreamHandlerLastBlockMapFunc(db)
 	suffrageNodeConnInfof := QuicstreamHandlerSuffrageNodeConnInfoFunc(db, m)
 
@@ -156,6 +176,22 @@ func PNetworkHandlers(pctx context.Context) (context.Context, error) {
 		isaacnetwork.HandlerNameState,
 		isaacnetwork.QuicstreamHandlerState(db.StateBytes), nil)
 
+	EnsureHandlerAdd(pctx, &gerror,
+		isaacnetwork.HandlerNameStateProof,
+		ACLNetworkHandler[isaacnetwork.StateProofRequestHeader](
+			aclallow,
+			StateProofACLScope,
+			ReadAllowACLPerm,
+			isaacparams.NetworkID(),
+		).Handler(
+			isaacnetwork.QuicstreamHandlerStateProof(QuicstreamHandlerStateProofFunc(db, readers)),
+		),
+		nil)
+
+	EnsureHandlerAdd(pctx, &gerror,
+		isaacnetwork.HandlerNameOperationProof,
+		isaacnetwork.QuicstreamHandlerOperationProof(QuicstreamHandlerOperationProofFunc(db, readers)), nil)
+
 	EnsureHandlerAdd(pctx, &gerror,
 		isaacnetwork.HandlerNameExistsInStateOperation,
 		isaacnetwork.QuicstreamHandlerExistsInStateOperation(db.ExistsInStateOperation), nil)
@@ -209,6 +245,10 @@ func PNetworkHandlers(pctx context.Context) (context.Context, error) {
 		return pctx, err
 	}
 
+	if err := AttachRelayNetworkHandlers(pctx); err != nil {
+		return pctx, err
+	}
+
 	return pctx, nil
 }
 
@@ -289,6 +329,16 @@ func POperationProcessorsMap(pctx context.Context) (context.Context, error) {
 			)
 		})
 
+	_ = set.Add(isaacoperation.SuffrageKeyRotateHint,
+		func(height base.Height, getStatef base.GetStateFunc) (base.OperationProcessor, error) {
+			return isaacoperation.NewSuffrageKeyRotateProcessor(
+				height,
+				getStatef,
+				nil,
+				nil,
+			)
+		})
+
 	_ = set.Add(isaacoperation.NetworkPolicyHint,
 		func(height base.Height, getStatef base.GetStateFunc) (base.OperationProcessor, error) {
 			return isaacoperation.NewNetworkPolicyProcessor(
@@ -378,6 +428,221 @@ func QuicstreamHandlerLastBlockMapFunc(
 	}
 }
 
+func QuicstreamHandlerStateProofFunc(
+	db isaac.Database,
+	readers *isaac.BlockItemReaders,
+) func(string, base.Height) (isaaclightclient.StateProof, bool, error) {
+	// NOTE the states tree of recent blocks is requested frequently.
+	trees := util.NewLRUGCache[base.Height, fixedtree.Tree](1 << 6) //nolint:gomnd //...
+
+	statesTree := func(height base.Height) (fixedtree.Tree, bool, error) {
+		if tr, found := trees.Get(height); found {
+			return tr, true, nil
+		}
+
+		switch tr, found, err := isaac.BlockItemReadersDecode[fixedtree.Tree](
+			readers.Item, height, base.BlockItemStatesTree, nil); {
+		case err != nil, !found:
+			return tr, found, err
+		default:
+			trees.Set(height, tr, 0)
+
+			return tr, true, nil
+		}
+	}
+
+	return func(key string, suffrageheight base.Height) (sp isaaclightclient.StateProof, _ bool, _ error) {
+		var st base.State
+
+		switch i, found, err := db.State(key); {
+		case err != nil, !found:
+			return sp, found, err
+		default:
+			st = i
+		}
+
+		height := st.Height()
+
+		var m base.BlockMap
+
+		switch i, found, err := db.BlockMap(height); {
+		case err != nil:
+			return sp, false, err
+		case !found:
+			return sp, false, storage.ErrNotFound.Errorf("BlockMap not found, %d", height)
+		default:
+			m = i
+		}
+
+		var proof fixedtree.Proof
+
+		switch tr, found, err := statesTree(height); {
+		case err != nil:
+			return sp, false, err
+		case !found:
+			return sp, false, storage.ErrNotFound.Errorf("states tree not found, %d", height)
+		default:
+			i, err := tr.Proof(st.Hash().String())
+			if err != nil {
+				return sp, false, err
+			}
+
+			proof = i
+		}
+
+		var avp base.ACCEPTVoteproof
+
+		switch vps, found, err := isaac.BlockItemReadersDecode[[2]base.Voteproof](
+			readers.Item, height, base.BlockItemVoteproofs, nil); {
+		case err != nil:
+			return sp, false, err
+		case !found:
+			return sp, false, storage.ErrNotFound.Errorf("voteproofs not found, %d", height)
+		default:
+			if err := util.SetInterfaceValue(vps[1], &avp); err != nil {
+				return sp, false, err
+			}
+		}
+
+		proofs, err := stateProofSuffrageProofs(db, suffrageheight, height)
+		if err != nil {
+			return sp, false, err
+		}
+
+		return isaaclightclient.NewStateProof(st, proof, m.Manifest(), avp, proofs), true, nil
+	}
+}
+
+func QuicstreamHandlerOperationProofFunc(
+	db isaac.Database,
+	readers *isaac.BlockItemReaders,
+) func(util.Hash, base.Height) (isaaclightclient.OperationProof, bool, error) {
+	return func(facthash util.Hash, height base.Height) (op isaaclightclient.OperationProof, _ bool, _ error) {
+		var m base.BlockMap
+
+		switch i, found, err := db.BlockMap(height); {
+		case err != nil, !found:
+			return op, found, err
+		default:
+			m = i
+		}
+
+		var node base.OperationFixedtreeNode
+		var proof fixedtree.Proof
+
+		switch tr, found, err := isaac.BlockItemReadersDecode[fixedtree.Tree](
+			readers.Item, height, base.BlockItemOperationsTree, nil); {
+		case err != nil:
+			return op, false, err
+		case !found:
+			// NOTE empty block does not have operations tree.
+			return op, false, nil
+		default:
+			if err := tr.Traverse(func(_ uint64, n fixedtree.Node) (bool, error) {
+				if h, _ := base.ParseTreeNodeOperationKey(n.Key()); !h.Equal(facthash) {
+					return true, nil
+				}
+
+				return false, util.SetInterfaceValue(n, &node)
+			}); err != nil {
+				return op, false, err
+			}
+
+			if node.Operation() == nil {
+				return op, false, nil
+			}
+
+			i, err := tr.Proof(node.Key())
+			if err != nil {
+				return op, false, err
+			}
+
+			proof = i
+		}
+
+		var avp base.ACCEPTVoteproof
+
+		switch vps, found, err := isaac.BlockItemReadersDecode[[2]base.Voteproof](
+			readers.Item, height, base.BlockItemVoteproofs, nil); {
+		case err != nil:
+			return op, false, err
+		case !found:
+			return op, false, storage.ErrNotFound.Errorf("voteproofs not found, %d", height)
+		default:
+			if err := util.SetInterfaceValue(vps[1], &avp); err != nil {
+				return op, false, err
+			}
+		}
+
+		return isaaclightclient.NewOperationProof(node, proof, m.Manifest(), avp), true, nil
+	}
+}
+
+// stateProofSuffrageProofs returns the suffrage proofs between the suffrage
+// height of client and the suffrage of block height, including the suffrage,
+// which voted the block. The number of suffrage proofs is limited by
+// MaxStateProofSuffrageProofs; if the suffrage of client is older, the
+// suffrage proofs from the next of client are returned.
+func stateProofSuffrageProofs(
+	db isaac.Database, suffrageheight, height base.Height,
+) ([]base.SuffrageProof, error) {
+	findSuffrageHeight := func(height base.Height) (base.Height, error) {
+		switch proof, found, err := db.SuffrageProofByBlockHeight(height); {
+		case err != nil:
+			return base.NilHeight, err
+		case !found:
+			return base.NilHeight, storage.ErrNotFound.Errorf("SuffrageProof not found by block height, %d", height)
+		default:
+			return proof.SuffrageHeight(), nil
+		}
+	}
+
+	top, err := findSuffrageHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	bottom := top
+
+	if height > base.GenesisHeight {
+		i, err := findSuffrageHeight(height - 1)
+		if err != nil {
+			return nil, err
+		}
+
+		bottom = i
+	}
+
+	from, to := min(bottom, suffrageheight), max(top, suffrageheight)
+
+	switch {
+	case suffrageheight < bottom:
+		to = min(to, suffrageheight+base.Height(MaxStateProofSuffrageProofs))
+	case suffrageheight > top && suffrageheight-bottom > base.Height(MaxStateProofSuffrageProofs):
+		return nil, errors.Errorf("too many suffrage proofs for old state, %d > %d",
+			suffrageheight-bottom, MaxStateProofSuffrageProofs)
+	}
+
+	var proofs []base.SuffrageProof
+
+	for i := from; i <= to; i++ {
+		if i == suffrageheight {
+			continue
+		}
+
+		switch proof, found, err := db.SuffrageProof(i); {
+		case err != nil:
+			return nil, err
+		case !found:
+			return nil, storage.ErrNotFound.Errorf("SuffrageProof not found, %d", i)
+		default:
+			proofs = append(proofs, proof)
+		}
+	}
+
+	return proofs, nil
+}
+
 func QuicstreamHandlerSuffrageNodeConnInfoFunc(
 	db isaac.Database,
 	memberlist *quicmemberlist.Memberlist,
@@ -397,6 +662,15 @@ func QuicstreamHandlerSuffrageNodeConnInfoFunc(
 			}
 
 			suf = i
+
+			switch m, found, err := db.LastBlockMap(); {
+			case err != nil:
+				return nil, err
+			case found:
+				if suf, err = isaac.KeyRotatedSuffrage(suf, m.Manifest().Height()); err != nil {
+					return nil, err
+				}
+			}
 		}
 
 		addMembers, doneMembers := util.CompactAppendSlice[isaac.NodeConnInfo](memberlist.MembersLen() * 2)
@@ -647,6 +921,18 @@ func EnsureHandlerAdd[T quicstreamheader.RequestHeader](
 	newhandler := quicstreamheader.NewHandler(encs, rhandler, errhandler)
 	newhandler = quicstream.TimeoutHandler(newhandler, timeoutf)
 
+	var profiler *Profiler
+
+	if err := util.LoadFromContext(pctx, ProfilerContextKey, &profiler); err != nil {
+		*gerr = err
+
+		return
+	}
+
+	if profiler != nil {
+		newhandler = profileTimeoutHandler(newhandler, name, profiler)
+	}
+
 	_ = handlers.Add(
 		name,
 		newhandler,
@@ -0,0 +1,83 @@
+package launch
+
+import (
+	"testing"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	jsonenc "github.com/ProtoconNet/mitum2/util/encoder/json"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/stretchr/testify/suite"
+)
+
+type testRelayAllowFunc struct {
+	suite.Suite
+	enc       encoder.Encoder
+	networkID base.NetworkID
+}
+
+func (t *testRelayAllowFunc) SetupSuite() {
+	t.enc = jsonenc.NewEncoder()
+	t.networkID = base.RandomNetworkID()
+
+	t.NoError(t.enc.Add(encoder.DecodeDetail{Hint: base.MPublickeyHint, Instance: &base.MPublickey{}}))
+}
+
+func (t *testRelayAllowFunc) TestAllow() {
+	priv := base.NewMPrivatekey()
+
+	acl, err := NewACL(9, "")
+	t.NoError(err)
+
+	_, _, err = acl.setUser(priv.Publickey().String(), map[ACLScope]ACLPerm{RelayACLScope: WriteAllowACLPerm})
+	t.NoError(err)
+
+	allowf := relayAllowFunc(acl, t.networkID, t.enc, logging.TestNilLogging)
+
+	target := "1.2.3.4:4321"
+	nonce := util.UUID().Bytes()
+
+	t.Run("ok", func() {
+		proof, err := relayProofFunc(priv, t.networkID)(target, nonce)
+		t.NoError(err)
+
+		t.True(allowf(target, nonce, proof))
+	})
+
+	t.Run("not in acl", func() {
+		proof, err := relayProofFunc(base.NewMPrivatekey(), t.networkID)(target, nonce)
+		t.NoError(err)
+
+		t.False(allowf(target, nonce, proof))
+	})
+
+	t.Run("different nonce", func() {
+		proof, err := relayProofFunc(priv, t.networkID)(target, util.UUID().Bytes())
+		t.NoError(err)
+
+		t.False(allowf(target, nonce, proof))
+	})
+
+	t.Run("different target", func() {
+		proof, err := relayProofFunc(priv, t.networkID)("4.3.2.1:1234", nonce)
+		t.NoError(err)
+
+		t.False(allowf(target, nonce, proof))
+	})
+
+	t.Run("different network id", func() {
+		proof, err := relayProofFunc(priv, base.RandomNetworkID())(target, nonce)
+		t.NoError(err)
+
+		t.False(allowf(target, nonce, proof))
+	})
+
+	t.Run("empty proof", func() {
+		t.False(allowf(target, nonce, nil))
+	})
+}
+
+func TestRelayAllowFunc(t *testing.T) {
+	suite.Run(t, new(testRelayAllowFunc))
+}
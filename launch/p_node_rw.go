@@ -53,6 +53,11 @@ var AllNodeReadKeys = []string{
 	"discovery",
 	"acl",
 	"block_item_files",
+	"network_stats",
+	"network_stats.inbound",
+	"network_stats.outbound",
+	"network_stats.handlers",
+	"network_stats.peers",
 }
 
 var AllNodeWriteKeys = []string{
@@ -62,15 +67,19 @@ var AllNodeWriteKeys = []string{
 	"design.parameters.isaac.wait_preparing_init_ballot",
 	"design.parameters.isaac.max_try_handover_y_broker_sync_data",
 	"design.parameters.misc.sync_source_checker_interval",
+	"design.parameters.misc.discovery_interval",
 	"design.parameters.misc.valid_proposal_operation_expire",
 	"design.parameters.misc.valid_proposal_suffrage_operations_expire",
 	"design.parameters.misc.max_message_size",
+	"design.parameters.misc.health_max_sync_lag",
+	"design.parameters.misc.health_max_last_block_elapsed",
 	"design.parameters.memberlist.extra_same_member_limit",
 	"design.parameters.network.timeout_request",
 	"design.parameters.network.ratelimit",
 	"design.sync_sources",
 	"discovery",
 	"acl",
+	"profile",
 }
 
 type (
@@ -90,6 +99,7 @@ var (
 	DiscoveryACLScope            = ACLScope("discovery")
 	ACLACLScope                  = ACLScope("acl")
 	BlockItemFilesACLScope       = ACLScope("block_item_files")
+	NetworkStatsACLScope         = ACLScope("network_stats")
 )
 
 func PNetworkHandlersReadWriteNode(pctx context.Context) (context.Context, error) {
@@ -181,6 +191,11 @@ func writeNode(pctx context.Context, lock *sync.RWMutex) (writeNodeValueFunc, er
 		return nil, err
 	}
 
+	fProfile, err := writeProfile(pctx)
+	if err != nil {
+		return nil, err
+	}
+
 	return writeNodeKey(func(
 		ctx context.Context, key, nextkey, value, acluser string,
 	) (interface{}, interface{}, bool, error) {
@@ -198,6 +213,8 @@ func writeNode(pctx context.Context, lock *sync.RWMutex) (writeNodeValueFunc, er
 			return fACL(ctx, nextkey, value, acluser)
 		case "block_item_files":
 			return fBlockItemFiles(ctx, nextkey, value, acluser)
+		case "profile":
+			return fProfile(ctx, nextkey, value, acluser)
 		default:
 			return nil, nil, false, util.ErrNotFound.Errorf("unknown key, %q for params", key)
 		}
@@ -326,11 +343,16 @@ func writeDesignMap(pctx context.Context) (map[string]writeNodeValueFunc, error)
 		"parameters.isaac.max_try_handover_y_broker_sync_data": writeLocalParamISAACMaxTryHandoverYBrokerSyncData(params.ISAAC),
 
 		"parameters.misc.sync_source_checker_interval":              writeLocalParamMISCSyncSourceCheckerInterval(params.MISC),
+		"parameters.misc.discovery_interval":                        writeLocalParamMISCDiscoveryInterval(params.MISC),
 		"parameters.misc.valid_proposal_operation_expire":           writeLocalParamMISCValidProposalOperationExpire(params.MISC),
 		"parameters.misc.valid_proposal_suffrage_operations_expire": writeLocalParamMISCValidProposalSuffrageOperationsExpire(params.MISC),
 		"parameters.misc.block_item_readers_remove_empty_after":     writeLocalParamMISCBlockItemReadersRemoveEmptyAfter(params.MISC),
 		"parameters.misc.block_item_readers_remove_empty_interval":  writeLocalParamMISCBlockItemReadersRemoveEmptyInterval(params.MISC),
 		"parameters.misc.max_message_size":                          writeLocalParamMISCMaxMessageSize(params.MISC),
+		"parameters.misc.health_max_sync_lag":                       writeLocalParamMISCHealthMaxSyncLag(params.MISC),
+		"parameters.misc.health_max_last_block_elapsed":             writeLocalParamMISCHealthMaxLastBlockElapsed(params.MISC),
+		"parameters.misc.block_archive_heights":                     writeLocalParamMISCBlockArchiveHeights(params.MISC),
+		"parameters.misc.block_archive_interval":                    writeLocalParamMISCBlockArchiveInterval(params.MISC),
 
 		"parameters.memberlist.extra_same_member_limit": writeLocalParamExtraSameMemberLimit(params.Memberlist),
 
@@ -496,6 +518,30 @@ func writeLocalParamMISCSyncSourceCheckerInterval(
 	})
 }
 
+func writeLocalParamMISCDiscoveryInterval(
+	params *MISCParams,
+) writeNodeValueFunc {
+	return writeNodeKey(func(
+		_ context.Context, _, _, value, _ string,
+	) (prev, next interface{}, updated bool, _ error) {
+		d, err := parseNodeValueDuration(value)
+		if err != nil {
+			return nil, nil, false, err
+		}
+
+		prev = params.DiscoveryInterval()
+		if prev == d {
+			return prev, nil, false, nil
+		}
+
+		if err := params.SetDiscoveryInterval(d); err != nil {
+			return nil, nil, false, err
+		}
+
+		return prev, params.DiscoveryInterval(), true, nil
+	})
+}
+
 func writeLocalParamMISCValidProposalOperationExpire(
 	params *MISCParams,
 ) writeNodeValueFunc {
@@ -592,6 +638,54 @@ func writeLocalParamMISCBlockItemReadersRemoveEmptyInterval(
 	})
 }
 
+func writeLocalParamMISCBlockArchiveHeights(
+	params *MISCParams,
+) writeNodeValueFunc {
+	return writeNodeKey(func(
+		_ context.Context, _, _, value, _ string,
+	) (prev, next interface{}, updated bool, _ error) {
+		i, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, nil, false, errors.WithStack(err)
+		}
+
+		prev = params.BlockArchiveHeights()
+		if prev == i {
+			return prev, nil, false, nil
+		}
+
+		if err := params.SetBlockArchiveHeights(i); err != nil {
+			return nil, nil, false, err
+		}
+
+		return prev, params.BlockArchiveHeights(), true, nil
+	})
+}
+
+func writeLocalParamMISCBlockArchiveInterval(
+	params *MISCParams,
+) writeNodeValueFunc {
+	return writeNodeKey(func(
+		_ context.Context, _, _, value, _ string,
+	) (prev, next interface{}, updated bool, _ error) {
+		d, err := parseNodeValueDuration(value)
+		if err != nil {
+			return nil, nil, false, err
+		}
+
+		prev = params.BlockArchiveInterval()
+		if prev == d {
+			return prev, nil, false, nil
+		}
+
+		if err := params.SetBlockArchiveInterval(d); err != nil {
+			return nil, nil, false, err
+		}
+
+		return prev, params.BlockArchiveInterval(), true, nil
+	})
+}
+
 func writeLocalParamMISCMaxMessageSize(
 	params *MISCParams,
 ) writeNodeValueFunc {
@@ -616,6 +710,54 @@ func writeLocalParamMISCMaxMessageSize(
 	})
 }
 
+func writeLocalParamMISCHealthMaxSyncLag(
+	params *MISCParams,
+) writeNodeValueFunc {
+	return writeNodeKey(func(
+		_ context.Context, _, _, value, _ string,
+	) (prev, next interface{}, updated bool, _ error) {
+		i, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, nil, false, errors.WithStack(err)
+		}
+
+		prev = params.HealthMaxSyncLag()
+		if prev == i {
+			return prev, nil, false, nil
+		}
+
+		if err := params.SetHealthMaxSyncLag(i); err != nil {
+			return nil, nil, false, err
+		}
+
+		return prev, params.HealthMaxSyncLag(), true, nil
+	})
+}
+
+func writeLocalParamMISCHealthMaxLastBlockElapsed(
+	params *MISCParams,
+) writeNodeValueFunc {
+	return writeNodeKey(func(
+		_ context.Context, _, _, value, _ string,
+	) (prev, next interface{}, updated bool, _ error) {
+		d, err := parseNodeValueDuration(value)
+		if err != nil {
+			return nil, nil, false, err
+		}
+
+		prev = params.HealthMaxLastBlockElapsed()
+		if prev == d {
+			return prev, nil, false, nil
+		}
+
+		if err := params.SetHealthMaxLastBlockElapsed(d); err != nil {
+			return nil, nil, false, err
+		}
+
+		return prev, params.HealthMaxLastBlockElapsed(), true, nil
+	})
+}
+
 func writeLocalParamExtraSameMemberLimit(
 	params *MemberlistParams,
 ) writeNodeValueFunc {
@@ -1352,6 +1494,11 @@ func readNode(pctx context.Context, lock *sync.RWMutex) (readNodeValueFunc, erro
 		return nil, err
 	}
 
+	fNetworkStats, err := readNetworkStats(pctx)
+	if err != nil {
+		return nil, err
+	}
+
 	return readNodeKey(func(ctx context.Context, key, nextkey, acluser string) (interface{}, error) {
 		lock.RLock()
 		defer lock.RUnlock()
@@ -1367,6 +1514,8 @@ func readNode(pctx context.Context, lock *sync.RWMutex) (readNodeValueFunc, erro
 			return fACL(ctx, nextkey, acluser)
 		case "block_item_files":
 			return fBlockItemFiles(ctx, nextkey, acluser)
+		case "network_stats":
+			return fNetworkStats(ctx, nextkey, acluser)
 		default:
 			return nil, util.ErrNotFound.Errorf("unknown key, %q for params", key)
 		}
@@ -1513,6 +1662,56 @@ func readDiscovery(pctx context.Context) (readNodeValueFunc, error) {
 	}, nil
 }
 
+func readNetworkStats(pctx context.Context) (readNodeValueFunc, error) {
+	var instrument *quicstream.InstrumentStats
+
+	if err := util.LoadFromContextOK(pctx,
+		QuicstreamInstrumentContextKey, &instrument,
+	); err != nil {
+		return nil, err
+	}
+
+	var aclallow ACLAllowFunc
+
+	switch i, err := pACLAllowFunc(pctx); {
+	case err != nil:
+		return nil, err
+	default:
+		aclallow = i
+	}
+
+	return readNodeKey(func(ctx context.Context, key, nextkey, acluser string) (interface{}, error) {
+		fullkey := fullKey("network_stats", key, nextkey)
+
+		if len(nextkey) > 0 {
+			return nil, util.ErrNotFound.Errorf("unknown key, %q", fullkey)
+		}
+
+		extra := zerolog.Dict().Str("key", fullkey)
+
+		if !aclallow(ctx, acluser, NetworkStatsACLScope, ReadAllowACLPerm, extra) {
+			return nil, ErrACLAccessDenied.WithStack()
+		}
+
+		snapshot := instrument.Snapshot()
+
+		switch key {
+		case "":
+			return snapshot, nil
+		case "inbound":
+			return snapshot.Inbound, nil
+		case "outbound":
+			return snapshot.Outbound, nil
+		case "handlers":
+			return snapshot.Handlers, nil
+		case "peers":
+			return snapshot.Peers, nil
+		default:
+			return nil, util.ErrNotFound.Errorf("unknown key, %q", fullkey)
+		}
+	}), nil
+}
+
 func readACL(pctx context.Context) (readNodeValueFunc, error) {
 	var acl *YAMLACL
 
@@ -65,6 +65,8 @@ func UpdateNodeInfoWithNewBlock(
 	db isaac.Database,
 	nodeinfo *isaacnetwork.NodeInfoUpdater,
 ) error {
+	var height base.Height
+
 	switch m, found, err := db.LastBlockMap(); {
 	case err != nil:
 		return err
@@ -72,17 +74,28 @@ func UpdateNodeInfoWithNewBlock(
 		return util.ErrNotFound.Errorf("last BlockMap")
 	case !nodeinfo.SetLastManifest(m.Manifest()):
 		return nil
+	default:
+		height = m.Manifest().Height()
 	}
 
+	// NOTE the consensus nodes are updated with the key rotations of every
+	// block.
 	switch proof, found, err := db.LastSuffrageProof(); {
 	case err != nil:
 		return errors.WithMessage(err, "last SuffrageProof not found")
-	case found && nodeinfo.SetSuffrageHeight(proof.SuffrageHeight()):
+	case found:
+		_ = nodeinfo.SetSuffrageHeight(proof.SuffrageHeight())
+
 		suf, err := proof.Suffrage()
 		if err != nil {
 			return errors.WithMessage(err, "suffrage from proof")
 		}
 
+		suf, err = isaac.KeyRotatedSuffrage(suf, height)
+		if err != nil {
+			return errors.WithMessage(err, "key rotated suffrage")
+		}
+
 		_ = nodeinfo.SetConsensusNodes(suf.Nodes())
 	}
 
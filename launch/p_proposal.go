@@ -93,6 +93,12 @@ func newProposalProcessorFunc(pctx context.Context) (
 		return nil, err
 	}
 
+	var m *Metrics
+
+	if err := util.LoadFromContext(pctx, MetricsContextKey, &m); err != nil {
+		return nil, err
+	}
+
 	return func(proposal base.ProposalSignFact, previous base.Manifest) (
 		isaac.ProposalProcessor, error,
 	) {
@@ -123,6 +129,10 @@ func newProposalProcessorFunc(pctx context.Context) (
 			return db.LastNetworkPolicy().EmptyProposalNoBlock()
 		}
 
+		if m != nil {
+			args.WhenOperationProcessedFunc = m.OperationProcessed
+		}
+
 		return isaac.NewDefaultProposalProcessor(proposal, previous, args)
 	}, nil
 }
@@ -2,6 +2,7 @@ package launch
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net/url"
 	"time"
@@ -57,9 +58,28 @@ func PBallotbox(pctx context.Context) (context.Context, error) {
 		return pctx, err
 	}
 
+	var metrics *Metrics
+	var timeline *Timeline
+
+	if err := util.LoadFromContext(pctx,
+		MetricsContextKey, &metrics,
+		TimelineContextKey, &timeline,
+	); err != nil {
+		return pctx, err
+	}
+
 	ballotbox := isaacstates.NewBallotbox(local.Address(), isaacparams.Threshold, sp.Height)
 	_ = ballotbox.SetCountAfter(isaacparams.WaitPreparingINITBallot())
 	_ = ballotbox.SetLogging(log)
+	_ = ballotbox.SetWhenVotedFunc(func(sf base.BallotSignFact, inVoteproof bool) {
+		if metrics != nil {
+			metrics.BallotVoted(sf, inVoteproof)
+		}
+
+		if timeline != nil {
+			timeline.Ballot(sf)
+		}
+	})
 
 	if err := ballotbox.Start(context.Background()); err != nil {
 		return pctx, err
@@ -101,6 +121,22 @@ func PBallotStuckResolver(pctx context.Context) (context.Context, error) {
 
 	requestMissingBallotsf := isaacstates.RequestMissingBallots(design.Network.PublishConnInfo(), m.CallbackBroadcast)
 
+	var alerts *Alerts
+
+	if err := util.LoadFromContext(pctx, AlertsContextKey, &alerts); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	if alerts != nil {
+		origRequestMissingBallotsf := requestMissingBallotsf
+
+		requestMissingBallotsf = func(ctx context.Context, point base.StagePoint, nodes []base.Address) error {
+			alerts.Signal(AlertRuleBallotStuck, fmt.Sprintf("ballot stuck at %s; missing nodes=%d", point, len(nodes)))
+
+			return origRequestMissingBallotsf(ctx, point, nodes)
+		}
+	}
+
 	voteSuffrageVotingf := isaacstates.VoteSuffrageVotingFunc(
 		local,
 		isaacparams.NetworkID(),
@@ -167,6 +203,10 @@ func PStates(pctx context.Context) (context.Context, error) {
 	args.IntervalBroadcastBallot = isaacparams.IntervalBroadcastBallot
 	args.AllowConsensus = devflags.AllowConsensus
 
+	if err := rotateLocalKeyWithLastBlock(pctx); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
 	if vp := args.LastVoteproofsHandler.Last().Cap(); vp != nil {
 		last := args.Ballotbox.LastPoint()
 
@@ -202,8 +242,26 @@ func PStates(pctx context.Context) (context.Context, error) {
 	_ = bb.SetLogging(log)
 	args.BallotBroadcaster = bb
 
+	var metrics *Metrics
+	var timeline *Timeline
+
+	if err := util.LoadFromContext(pctx,
+		MetricsContextKey, &metrics,
+		TimelineContextKey, &timeline,
+	); err != nil {
+		return pctx, err
+	}
+
 	args.WhenNewVoteproof = func(vp base.Voteproof) {
 		_ = nodeinfo.SetLastVote(vp.Point(), vp.Result())
+
+		if metrics != nil {
+			metrics.NewVoteproof(vp)
+		}
+
+		if timeline != nil {
+			timeline.Voteproof(vp)
+		}
 	}
 
 	states, err := isaacstates.NewStates(isaacparams.NetworkID(), local, args)
@@ -218,9 +276,15 @@ func PStates(pctx context.Context) (context.Context, error) {
 		return pctx, e.Wrap(err)
 	}
 
+	proposalSelectf := isaac.ProposalSelectFunc(proposalSelector.Select)
+
+	if timeline != nil {
+		proposalSelectf = timeline.ProposalSelectFunc(proposalSelectf)
+	}
+
 	nctx := util.ContextWithValues(pctx, map[util.ContextKey]interface{}{
 		StatesContextKey:             states,
-		ProposalSelectFuncContextKey: isaac.ProposalSelectFunc(proposalSelector.Select),
+		ProposalSelectFuncContextKey: proposalSelectf,
 	})
 
 	return patchStatesArgsForHandover(nctx, args)
@@ -290,8 +354,38 @@ func PStatesSetHandlers(pctx context.Context) (context.Context, error) { //reviv
 	getLastManifestf := getLastManifestFunc(db)
 	getManifestf := getManifestFunc(db)
 
+	var metrics *Metrics
+	var timeline *Timeline
+	var health *Health
+	var alerts *Alerts
+
+	if err := util.LoadFromContext(pctx,
+		MetricsContextKey, &metrics,
+		TimelineContextKey, &timeline,
+		HealthContextKey, &health,
+		AlertsContextKey, &alerts,
+	); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
 	states.SetWhenStateSwitched(func(next isaacstates.StateType) {
 		_ = nodeinfo.SetConsensusState(next)
+
+		if metrics != nil {
+			metrics.StateSwitched(next)
+		}
+
+		if timeline != nil {
+			timeline.StateSwitched(next)
+		}
+
+		if health != nil {
+			health.StateSwitched(next)
+		}
+
+		if alerts != nil {
+			alerts.StateSwitched(next)
+		}
 	})
 
 	syncingargs, err := newSyncingHandlerArgs(pctx)
@@ -436,6 +530,7 @@ func newConsensusHandlerArgs(pctx context.Context) (*isaacstates.ConsensusHandle
 	var pps *isaac.ProposalProcessors
 	var nodeinfo *isaacnetwork.NodeInfoUpdater
 	var nodeInConsensusNodesf func(base.Node, base.Height) (base.Suffrage, bool, error)
+	var local base.LocalNode
 
 	if err := util.LoadFromContextOK(pctx,
 		LoggingContextKey, &log,
@@ -446,6 +541,7 @@ func newConsensusHandlerArgs(pctx context.Context) (*isaacstates.ConsensusHandle
 		ProposalProcessorsContextKey, &pps,
 		NodeInfoContextKey, &nodeinfo,
 		NodeInConsensusNodesFuncContextKey, &nodeInConsensusNodesf,
+		LocalContextKey, &local,
 	); err != nil {
 		return nil, err
 	}
@@ -469,6 +565,18 @@ func newConsensusHandlerArgs(pctx context.Context) (*isaacstates.ConsensusHandle
 	}
 
 	defaultWhenNewBlockSavedf := DefaultWhenNewBlockSavedInConsensusStateFunc(log, ballotbox, db, nodeinfo)
+	keyRotateLocalf := keyRotateLocalFunc(log, local, db)
+
+	metricsBlockSavedf, err := metricsWhenNewBlockSavedFunc(pctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var profiler *Profiler
+
+	if err := util.LoadFromContext(pctx, ProfilerContextKey, &profiler); err != nil {
+		return nil, err
+	}
 
 	var whenNewBlockConfirmedf func(base.Height)
 
@@ -490,7 +598,13 @@ func newConsensusHandlerArgs(pctx context.Context) (*isaacstates.ConsensusHandle
 	args.ProposalSelectFunc = proposalSelectf
 	args.ProposalProcessors = pps
 	args.WhenNewBlockSaved = func(bm base.BlockMap) {
+		keyRotateLocalf(bm.Manifest().Height())
 		defaultWhenNewBlockSavedf(bm)
+		metricsBlockSavedf(bm.Manifest().Height())
+
+		if profiler != nil {
+			profiler.NewBlockSaved(bm.Manifest())
+		}
 
 		whenNewBlockSavedf(bm)
 	}
@@ -573,6 +687,7 @@ func newSyncingHandlerArgs(pctx context.Context) (*isaacstates.SyncingHandlerArg
 	var ballotbox *isaacstates.Ballotbox
 	var nodeinfo *isaacnetwork.NodeInfoUpdater
 	var nodeInConsensusNodesf func(base.Node, base.Height) (base.Suffrage, bool, error)
+	var local base.LocalNode
 
 	if err := util.LoadFromContextOK(pctx,
 		LoggingContextKey, &log,
@@ -581,6 +696,7 @@ func newSyncingHandlerArgs(pctx context.Context) (*isaacstates.SyncingHandlerArg
 		BallotboxContextKey, &ballotbox,
 		NodeInfoContextKey, &nodeinfo,
 		NodeInConsensusNodesFuncContextKey, &nodeInConsensusNodesf,
+		LocalContextKey, &local,
 	); err != nil {
 		return nil, err
 	}
@@ -607,6 +723,12 @@ func newSyncingHandlerArgs(pctx context.Context) (*isaacstates.SyncingHandlerArg
 	}
 
 	defaultWhenNewBlockSavedf := DefaultWhenNewBlockSavedInSyncingStateFunc(log, db, nodeinfo)
+	keyRotateLocalf := keyRotateLocalFunc(log, local, db)
+
+	metricsBlockSavedf, err := metricsWhenNewBlockSavedFunc(pctx)
+	if err != nil {
+		return nil, err
+	}
 
 	args := isaacstates.NewSyncingHandlerArgs()
 	args.WaitStuckInterval = func() time.Duration {
@@ -621,7 +743,9 @@ func newSyncingHandlerArgs(pctx context.Context) (*isaacstates.SyncingHandlerArg
 	args.JoinMemberlistFunc = joinMemberlistf
 	args.LeaveMemberlistFunc = leaveMemberlistf
 	args.WhenNewBlockSavedFunc = func(height base.Height) {
+		keyRotateLocalf(height)
 		defaultWhenNewBlockSavedf(height)
+		metricsBlockSavedf(height)
 
 		whenNewBlockSavedf(height)
 	}
@@ -664,6 +788,13 @@ func newSyncerArgsFunc(pctx context.Context) (func(base.Height) (isaacstates.Syn
 
 	isaacparams := params.ISAAC
 
+	var syncSourceChecker *isaacnetwork.SyncSourceChecker
+	if err := util.LoadFromContext(pctx, SyncSourceCheckerContextKey, &syncSourceChecker); err != nil {
+		return nil, err
+	}
+
+	checkpointf := syncerCheckpointFunc(design.Checkpoint, db.LastSuffrageProof, isaacparams.Threshold())
+
 	setLastVoteproofsfFromBlockReaderf, err := setLastVoteproofsfFromBlockReaderFunc(lvps)
 	if err != nil {
 		return nil, err
@@ -686,6 +817,11 @@ func newSyncerArgsFunc(pctx context.Context) (func(base.Height) (isaacstates.Syn
 
 	defaultWhenNewBlockSavedInSyncingStatef := DefaultWhenNewBlockSavedInSyncingStateFunc(log, db, nodeinfo)
 
+	metricsBlockSavedf, err := metricsWhenNewBlockSavedFunc(pctx)
+	if err != nil {
+		return nil, err
+	}
+
 	var whenNewBlockConfirmedf func(base.Height)
 
 	switch err = util.LoadFromContext(
@@ -708,19 +844,43 @@ func newSyncerArgsFunc(pctx context.Context) (func(base.Height) (isaacstates.Syn
 			tempsyncpool = i
 		}
 
+		var mirrors []*isaac.BlockMirror
+
+		if syncSourceChecker != nil {
+			switch i, err := BlockMirrorsFromSyncSources(syncSourceChecker.Sources(), encs.JSON()); {
+			case err != nil:
+				return args, err
+			default:
+				mirrors = i
+			}
+		}
+
 		conninfocache, _ := util.NewShardedMap[base.Height, quicstream.ConnInfo](1<<9, nil) //nolint:gomnd //...
+		mirrorcache, _ := util.NewShardedMap[base.Height, *isaac.BlockMirror](1<<9, nil)    //nolint:gomnd //...
+
+		checkpoint, err := checkpointf()
+		if err != nil {
+			return args, err
+		}
 
 		args = isaacstates.NewSyncerArgs()
-		args.LastBlockMapFunc = syncerLastBlockMapFunc(client, isaacparams, syncSourcePool)
+		args.Checkpoint = checkpoint
+		args.LastBlockMapFunc = syncerLastBlockMapFunc(client, isaacparams, syncSourcePool, mirrors, readers)
 		args.LastBlockMapTimeout = params.Network.TimeoutRequest()
-		args.BlockMapFunc = syncerBlockMapFunc(log, client, params, syncSourcePool, conninfocache, devflags.DelaySyncer)
+		args.BlockMapFunc = syncerBlockMapFunc(
+			log, client, params, syncSourcePool, conninfocache, checkpoint, devflags.DelaySyncer,
+			mirrors, mirrorcache, readers,
+		)
 		args.TempSyncPool = tempsyncpool
 		args.WhenStoppedFunc = func() error {
 			conninfocache.Close()
+			mirrorcache.Close()
 
 			return nil
 		}
 		args.RemovePrevBlockFunc = removePrevBlockf
+		args.SyncSourcePool = syncSourcePool
+		args.FetchBlockItemFunc = syncerFetchBlockItemFunc(client, params.Network.TimeoutRequest, remotesItem)
 
 		stcachef := purgeStateCacheFunc(isaacparams.StateCacheSize())
 
@@ -729,22 +889,30 @@ func newSyncerArgsFunc(pctx context.Context) (func(base.Height) (isaacstates.Syn
 			from, to base.Height,
 			batchlimit int64,
 			blockMapf func(context.Context, base.Height) (base.BlockMap, bool, error),
+			blockItemf isaacblock.ImportBlocksBlockItemFunc,
 		) error {
+			if blockItemf == nil {
+				blockItemf = syncerBlockItemFunc(
+					client, conninfocache, mirrorcache, params.Network.TimeoutRequest, remotesItem)
+			}
+
 			return isaacblock.ImportBlocks(
 				ctx,
 				from, to,
 				batchlimit,
 				readers,
 				blockMapf,
-				syncerBlockItemFunc(client, conninfocache, params.Network.TimeoutRequest, remotesItem),
+				blockItemf,
 				newBlockImpoterFunc(
 					LocalFSDataDirectory(design.Storage.Base), db, isaacparams, encs,
 					to,
 					stcachef,
+					checkpoint,
 				),
 				setLastVoteproofsfFromBlockReaderf,
 				func(context.Context) error {
 					defaultWhenNewBlockSavedInSyncingStatef(to)
+					metricsBlockSavedf(to)
 
 					if c := to.SafePrev(); c >= from {
 						defaultWhenNewBlockConfirmedf(c)
@@ -769,6 +937,7 @@ func newBlockImpoterFunc(
 	encs *encoder.Encoders,
 	to base.Height,
 	stcachef func(func() bool) util.GCache[string, [2]interface{}],
+	checkpoint *isaac.Checkpoint,
 ) func(base.BlockMap) (isaac.BlockImporter, error) {
 	return func(blockmap base.BlockMap) (isaac.BlockImporter, error) {
 		bwdb, err := db.NewBlockWriteDatabase(blockmap.Manifest().Height())
@@ -784,7 +953,7 @@ func newBlockImpoterFunc(
 			}
 		}
 
-		return isaacblock.NewBlockImporter(
+		im, err := isaacblock.NewBlockImporter(
 			root,
 			encs,
 			blockmap,
@@ -794,6 +963,19 @@ func newBlockImpoterFunc(
 			},
 			params.NetworkID(),
 		)
+		if err != nil {
+			return nil, err
+		}
+
+		height := blockmap.Manifest().Height()
+
+		// NOTE the blocks under checkpoint are trusted by the hash chain,
+		// which is checked by syncer; the items are checked with the trees of
+		// manifest by BlockImporter. The signatures are skipped only below
+		// checkpoint and the voteproofs of last block are always verified.
+		return im.
+			SetTrusted(checkpoint != nil && height <= checkpoint.Height()).
+			SetVerifySigns(checkpoint == nil || height >= checkpoint.Height() || height == to), nil
 	}
 }
 
@@ -801,6 +983,8 @@ func syncerLastBlockMapFunc(
 	client *isaacnetwork.BaseClient,
 	params *isaac.Params,
 	syncSourcePool *isaac.SyncSourcePool,
+	mirrors []*isaac.BlockMirror,
+	readers *isaac.BlockItemReaders,
 ) isaacstates.SyncerLastBlockMapFunc {
 	f := func(
 		ctx context.Context, manifest util.Hash, ci quicstream.ConnInfo,
@@ -817,9 +1001,41 @@ func syncerLastBlockMapFunc(
 		}
 	}
 
+	mirrorf := func(
+		ctx context.Context, manifest util.Hash, mirror *isaac.BlockMirror,
+	) (_ base.BlockMap, updated bool, _ error) {
+		switch m, found, err := BlockMirrorLastBlockMap(ctx, mirror, readers); {
+		case err != nil, !found:
+			return nil, false, err
+		case manifest != nil && m.Manifest().Hash().Equal(manifest):
+			return nil, false, nil
+		default:
+			if err := m.IsValid(params.NetworkID()); err != nil {
+				return nil, false, err
+			}
+
+			return m, true, nil
+		}
+	}
+
 	return func(ctx context.Context, manifest util.Hash) (base.BlockMap, bool, error) {
 		ml := util.EmptyLocked[base.BlockMap]()
 
+		setf := func(m base.BlockMap) error {
+			_, err := ml.Set(func(v base.BlockMap, _ bool) (base.BlockMap, error) {
+				switch {
+				case v == nil,
+					m.Manifest().Height() > v.Manifest().Height():
+
+					return m, nil
+				default:
+					return nil, util.ErrLockedSetIgnore
+				}
+			})
+
+			return err
+		}
+
 		numnodes := 3 // NOTE choose top 3 sync nodes
 
 		if err := isaac.ErrCallbackWorkerWithSyncSourcePool(
@@ -837,23 +1053,23 @@ func syncerLastBlockMapFunc(
 					return nil
 				}
 
-				_, err = ml.Set(func(v base.BlockMap, _ bool) (base.BlockMap, error) {
-					switch {
-					case v == nil,
-						m.Manifest().Height() > v.Manifest().Height():
-
-						return m, nil
-					default:
-						return nil, util.ErrLockedSetIgnore
-					}
-				})
-
-				return err
+				return setf(m)
 			},
 		); err != nil {
 			return nil, false, err
 		}
 
+		for i := range mirrors {
+			// NOTE the failed mirror is ignored
+			switch m, updated, err := mirrorf(ctx, manifest, mirrors[i]); {
+			case err != nil, !updated:
+			default:
+				if err := setf(m); err != nil {
+					return nil, false, err
+				}
+			}
+		}
+
 		switch v, _ := ml.Value(); {
 		case v == nil:
 			return nil, false, nil
@@ -869,8 +1085,37 @@ func syncerBlockMapFunc( //revive:disable-line:cognitive-complexity
 	params *LocalParams,
 	syncSourcePool *isaac.SyncSourcePool,
 	conninfocache util.LockedMap[base.Height, quicstream.ConnInfo],
+	checkpoint *isaac.Checkpoint,
 	devdelay time.Duration,
+	mirrors []*isaac.BlockMirror,
+	mirrorcache util.LockedMap[base.Height, *isaac.BlockMirror],
+	readers *isaac.BlockItemReaders,
 ) isaacblock.ImportBlocksBlockMapFunc {
+	validatef := func(height base.Height, m base.BlockMap) (base.BlockMap, bool, error) {
+		switch {
+		case checkpoint != nil && height <= checkpoint.Height():
+			// NOTE the signature of BlockMap under checkpoint is not verified;
+			// the hash chain to checkpoint is checked by syncer and the items
+			// are checked with manifest by BlockImporter.
+			i, ok := m.(isaacblock.BlockMap)
+			if !ok {
+				return m, true, errors.Errorf("expected isaacblock.BlockMap, but %T", m)
+			}
+
+			if err := i.IsValidWithoutSign(); err != nil {
+				return m, true, err
+			}
+
+			return m, true, nil
+		default:
+			if err := m.IsValid(params.ISAAC.NetworkID()); err != nil {
+				return m, true, err
+			}
+
+			return m, true, nil
+		}
+	}
+
 	f := func(ctx context.Context, height base.Height, ci quicstream.ConnInfo) (base.BlockMap, bool, error) {
 		if devdelay > 0 {
 			<-time.After(devdelay) // NOTE for testing
@@ -883,12 +1128,40 @@ func syncerBlockMapFunc( //revive:disable-line:cognitive-complexity
 		case err != nil, !found:
 			return m, found, err
 		default:
-			if err := m.IsValid(params.ISAAC.NetworkID()); err != nil {
-				return m, true, err
+			return validatef(height, m)
+		}
+	}
+
+	// NOTE the block mirrors are used when BlockMap is not found from the
+	// sync sources.
+	mirrorf := func(ctx context.Context, height base.Height) (base.BlockMap, bool) {
+		for i := range mirrors {
+			mirror := mirrors[i]
+
+			cctx, cancel := context.WithTimeout(ctx, params.Network.TimeoutRequest())
+			m, found, err := BlockMirrorBlockMap(cctx, mirror, height, readers)
+
+			cancel()
+
+			if err == nil && found {
+				m, _, err = validatef(height, m)
 			}
 
-			return m, true, nil
+			switch u := mirror.URL(); {
+			case err != nil:
+				log.Log().Error().Err(err).
+					Interface("height", height).
+					Stringer("mirror", &u).
+					Msg("failed to import blockmap from mirror")
+			case !found:
+			default:
+				_ = mirrorcache.SetValue(height, mirror)
+
+				return m, true
+			}
 		}
+
+		return nil, false
 	}
 
 	return func(ctx context.Context, height base.Height) (m base.BlockMap, found bool, _ error) {
@@ -933,6 +1206,10 @@ func syncerBlockMapFunc( //revive:disable-line:cognitive-complexity
 
 				i, isempty := result.Value()
 				if isempty {
+					if m, found = mirrorf(ctx, height); found {
+						return false, nil
+					}
+
 					return true, nil
 				}
 
@@ -951,6 +1228,7 @@ func syncerBlockMapFunc( //revive:disable-line:cognitive-complexity
 func syncerBlockItemFunc(
 	client *isaacnetwork.BaseClient,
 	conninfocache util.LockedMap[base.Height, quicstream.ConnInfo],
+	mirrorcache util.LockedMap[base.Height, *isaac.BlockMirror],
 	requestTimeoutf func() time.Duration,
 	fromRemote isaac.RemotesBlockItemReadFunc,
 ) isaacblock.ImportBlocksBlockItemFunc {
@@ -970,10 +1248,28 @@ func syncerBlockItemFunc(
 		var ci quicstream.ConnInfo
 
 		switch i, cfound := conninfocache.Value(height); {
-		case !cfound:
-			return e.Errorf("conninfo not found")
-		default:
+		case cfound:
 			ci = i
+		default:
+			// NOTE BlockMap is from the block mirror
+			mirror, mfound := mirrorcache.Value(height)
+			if !mfound {
+				return e.Errorf("conninfo not found")
+			}
+
+			cctx, ctxcancel := context.WithTimeout(ctx, nrequestTimeoutf())
+			defer ctxcancel()
+
+			switch found, err := mirror.Item(cctx, height, item, func(r io.Reader, compressFormat string) error {
+				return f(r, true, compressFormat)
+			}); {
+			case err != nil:
+				return e.Wrap(err)
+			case !found:
+				return f(nil, false, "")
+			default:
+				return nil
+			}
 		}
 
 		cctx, ctxcancel := context.WithTimeout(ctx, nrequestTimeoutf())
@@ -1012,6 +1308,67 @@ func syncerBlockItemFunc(
 	}
 }
 
+func syncerFetchBlockItemFunc(
+	client *isaacnetwork.BaseClient,
+	requestTimeoutf func() time.Duration,
+	fromRemote isaac.RemotesBlockItemReadFunc,
+) isaacstates.SyncerFetchBlockItemFunc {
+	nrequestTimeoutf := func() time.Duration {
+		return isaac.DefaultTimeoutRequest
+	}
+
+	if requestTimeoutf != nil {
+		nrequestTimeoutf = requestTimeoutf
+	}
+
+	return func(ctx context.Context, nci isaac.NodeConnInfo, height base.Height, item base.BlockItemType) (
+		b []byte, compressFormat string, found bool, _ error,
+	) {
+		e := util.StringError("fetch block item")
+
+		readf := func(r io.Reader, format string) error {
+			i, err := io.ReadAll(r)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			b = i
+			compressFormat = format
+			found = true
+
+			return nil
+		}
+
+		cctx, ctxcancel := context.WithTimeout(ctx, nrequestTimeoutf())
+		defer ctxcancel()
+
+		switch _, err := client.BlockItem(cctx, nci.ConnInfo(), height, item,
+			func(r io.Reader, uri url.URL, compressFormat string) error {
+				if r != nil {
+					return readf(r, compressFormat)
+				}
+
+				cctx, ctxcancel := context.WithTimeout(ctx, nrequestTimeoutf())
+				defer ctxcancel()
+
+				switch known, _, err := fromRemote(cctx, uri, compressFormat, readf); {
+				case err != nil:
+					return err
+				case !known:
+					return errors.Errorf("unknown remote, %q", &uri)
+				default:
+					return nil
+				}
+			},
+		); {
+		case err != nil:
+			return nil, "", false, e.Wrap(err)
+		default:
+			return b, compressFormat, found, nil
+		}
+	}
+}
+
 func setLastVoteproofsfFromBlockReaderFunc(
 	lvps *isaac.LastVoteproofsHandler,
 ) (func([2]base.Voteproof, bool) error, error) {
@@ -1199,6 +1556,32 @@ func removePrevBlockFunc(pctx context.Context) (func(base.Height) (bool, error),
 		return nil, err
 	}
 
+	var readers *isaac.BlockItemReaders
+
+	if err := util.LoadFromContext(pctx, BlockItemReadersContextKey, &readers); err != nil {
+		return nil, err
+	}
+
+	removeLocalFS := func(height base.Height) (bool, error) {
+		removed, err := isaacblock.RemoveBlocksFromLocalFS(design.Storage.Base, height)
+
+		if readers != nil {
+			readers.PurgeItemFiles()
+		}
+
+		return removed, err
+	}
+
+	var archiver *isaacblock.LocalFSArchiver
+
+	switch err := util.LoadFromContext(pctx, LocalFSArchiverContextKey, &archiver); {
+	case err != nil:
+		return nil, err
+	case archiver != nil:
+		// NOTE blocks are removed after the running archiving finished
+		removeLocalFS = archiver.RemoveBlocks
+	}
+
 	return func(height base.Height) (bool, error) {
 		// NOTE remove from database
 		switch removed, err := db.RemoveBlocks(height); {
@@ -1216,7 +1599,7 @@ func removePrevBlockFunc(pctx context.Context) (func(base.Height) (bool, error),
 		}
 
 		// NOTE remove from local fs
-		switch removed, err := isaacblock.RemoveBlocksFromLocalFS(design.Storage.Base, height); {
+		switch removed, err := removeLocalFS(height); {
 		case err != nil:
 			return false, err
 		case !removed:
@@ -1236,6 +1619,7 @@ func handoverHandlerArgs(pctx context.Context) (*isaacstates.HandoverHandlerArgs
 	var pps *isaac.ProposalProcessors
 	var nodeinfo *isaacnetwork.NodeInfoUpdater
 	var nodeInConsensusNodesf func(base.Node, base.Height) (base.Suffrage, bool, error)
+	var local base.LocalNode
 
 	if err := util.LoadFromContextOK(pctx,
 		LoggingContextKey, &log,
@@ -1246,6 +1630,7 @@ func handoverHandlerArgs(pctx context.Context) (*isaacstates.HandoverHandlerArgs
 		ProposalProcessorsContextKey, &pps,
 		NodeInfoContextKey, &nodeinfo,
 		NodeInConsensusNodesFuncContextKey, &nodeInConsensusNodesf,
+		LocalContextKey, &local,
 	); err != nil {
 		return nil, err
 	}
@@ -1270,6 +1655,12 @@ func handoverHandlerArgs(pctx context.Context) (*isaacstates.HandoverHandlerArgs
 	}
 
 	defaultWhenNewBlockSavedf := DefaultWhenNewBlockSavedInConsensusStateFunc(log, ballotbox, db, nodeinfo)
+	keyRotateLocalf := keyRotateLocalFunc(log, local, db)
+
+	metricsBlockSavedf, err := metricsWhenNewBlockSavedFunc(pctx)
+	if err != nil {
+		return nil, err
+	}
 	defaultWhenNewBlockConfirmedf := DefaultWhenNewBlockConfirmedFunc(log)
 
 	args := isaacstates.NewHandoverHandlerArgs()
@@ -1279,7 +1670,9 @@ func handoverHandlerArgs(pctx context.Context) (*isaacstates.HandoverHandlerArgs
 	args.ProposalSelectFunc = proposalSelectf
 	args.ProposalProcessors = pps
 	args.WhenNewBlockSaved = func(bm base.BlockMap) {
+		keyRotateLocalf(bm.Manifest().Height())
 		defaultWhenNewBlockSavedf(bm)
+		metricsBlockSavedf(bm.Manifest().Height())
 
 		whenNewBlockSavedf(bm)
 	}
@@ -9,7 +9,6 @@ import (
 	isaacdatabase "github.com/ProtoconNet/mitum2/isaac/database"
 	isaacnetwork "github.com/ProtoconNet/mitum2/isaac/network"
 	isaacstates "github.com/ProtoconNet/mitum2/isaac/states"
-	"github.com/ProtoconNet/mitum2/network/quicmemberlist"
 	"github.com/ProtoconNet/mitum2/network/quicstream"
 	quicstreamheader "github.com/ProtoconNet/mitum2/network/quicstream/header"
 	"github.com/ProtoconNet/mitum2/util"
@@ -46,17 +45,20 @@ func AttachHandlerOperation(pctx context.Context) error {
 	var pool *isaacdatabase.TempPool
 	var connectionPool *quicstream.ConnectionPool
 	var states *isaacstates.States
+	var params *LocalParams
 
 	if err := util.LoadFromContext(pctx,
 		EncodersContextKey, &encs,
 		PoolDatabaseContextKey, &pool,
 		ConnectionPoolContextKey, &connectionPool,
 		StatesContextKey, &states,
+		LocalParamsContextKey, &params,
 	); err != nil {
 		return err
 	}
 
-	headerdial := quicstreamheader.NewDialFunc(connectionPool.Dial, encs, encs.Default())
+	headerdial := quicstreamheader.NewDialFunc(
+		connectionPool.Dial, encs, encs.Default(), params.Network.HeadExtension)
 
 	var gerror error
 
@@ -127,7 +129,7 @@ func AttachHandlerSendOperation(pctx context.Context) error {
 	var pool *isaacdatabase.TempPool
 	var states *isaacstates.States
 	var svvotef isaac.SuffrageVoteFunc
-	var memberlist *quicmemberlist.Memberlist
+	var gossip *OperationGossip
 
 	if err := util.LoadFromContext(pctx,
 		LoggingContextKey, &log,
@@ -136,7 +138,7 @@ func AttachHandlerSendOperation(pctx context.Context) error {
 		PoolDatabaseContextKey, &pool,
 		StatesContextKey, &states,
 		SuffrageVotingVoteFuncContextKey, &svvotef,
-		MemberlistContextKey, &memberlist,
+		OperationGossipContextKey, &gossip,
 	); err != nil {
 		return err
 	}
@@ -156,17 +158,7 @@ func AttachHandlerSendOperation(pctx context.Context) error {
 			db.ExistsInStateOperation,
 			sendOperationFilterf,
 			svvotef,
-			func(ctx context.Context, id string, op base.Operation, b []byte) error {
-				if broker := states.HandoverXBroker(); broker != nil {
-					if err := broker.SendData(ctx, isaacstates.HandoverMessageDataTypeOperation, op); err != nil {
-						log.Log().Error().Err(err).
-							Interface("operation", op.Hash()).
-							Msg("failed to send operation data to handover y broker; ignored")
-					}
-				}
-
-				return memberlist.CallbackBroadcast(b, id, nil)
-			},
+			relayNewOperationFunc(log, states, gossip),
 			params.MISC.MaxMessageSize,
 		),
 		nil,
@@ -175,6 +167,28 @@ func AttachHandlerSendOperation(pctx context.Context) error {
 	return gerror
 }
 
+// relayNewOperationFunc relays the newly added operation to the handover y
+// broker and to the other members by gossip.
+func relayNewOperationFunc(
+	log *logging.Logging,
+	states *isaacstates.States,
+	gossip *OperationGossip,
+) func(context.Context, string, base.Operation, []byte) error {
+	return func(ctx context.Context, _ string, op base.Operation, b []byte) error {
+		if broker := states.HandoverXBroker(); broker != nil {
+			if err := broker.SendData(ctx, isaacstates.HandoverMessageDataTypeOperation, op); err != nil {
+				log.Log().Error().Err(err).
+					Interface("operation", op.Hash()).
+					Msg("failed to send operation data to handover y broker; ignored")
+			}
+		}
+
+		_, err := gossip.Relay(op, b)
+
+		return err
+	}
+}
+
 func AttachHandlerStreamOperations(pctx context.Context) error {
 	var local base.LocalNode
 	var params *LocalParams
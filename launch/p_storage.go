@@ -39,6 +39,7 @@ var (
 	PNameLoadDatabase               = ps.Name("load-database")
 	PNameCheckBlocksOfStorage       = ps.Name("check-blocks-of-storage")
 	PNamePatchBlockItemReaders      = ps.Name("patch-block-item-readers")
+	PNameLocalFSArchiver            = ps.Name("localfs-archiver")
 	FSNodeInfoContextKey            = util.ContextKey("fs-node-info")
 	LeveldbStorageContextKey        = util.ContextKey("leveldb-storage")
 	CenterDatabaseContextKey        = util.ContextKey("center-database")
@@ -46,6 +47,7 @@ var (
 	PoolDatabaseContextKey          = util.ContextKey("pool-database")
 	LastVoteproofsHandlerContextKey = util.ContextKey("last-voteproofs-handler")
 	EventLoggingContextKey          = util.ContextKey("event-log")
+	LocalFSArchiverContextKey       = util.ContextKey("localfs-archiver")
 )
 
 var (
@@ -105,6 +107,11 @@ func PStartStorage(pctx context.Context) (context.Context, error) {
 	var readers *isaac.BlockItemReaders
 	_ = load("block item readers", BlockItemReadersContextKey, &readers)
 
+	var archiver *isaacblock.LocalFSArchiver
+	if err := util.LoadFromContextOK(pctx, LocalFSArchiverContextKey, &archiver); err == nil {
+		_ = load("localfs archiver", LocalFSArchiverContextKey, &archiver)
+	}
+
 	for i := range starters {
 		starters[i]()
 	}
@@ -170,6 +177,9 @@ func PCloseStorage(pctx context.Context) (context.Context, error) {
 	var readers *isaac.BlockItemReaders
 	_ = load("block item readers", BlockItemReadersContextKey, &readers)
 
+	var archiver *isaacblock.LocalFSArchiver
+	_ = load("localfs archiver", LocalFSArchiverContextKey, &archiver)
+
 	for i := range stoppers {
 		stoppers[len(stoppers)-i-1]()
 	}
@@ -570,6 +580,46 @@ func PPatchBlockItemReaders(pctx context.Context) (context.Context, error) {
 	}
 }
 
+func PLocalFSArchiver(pctx context.Context) (context.Context, error) {
+	var log *logging.Logging
+	var design NodeDesign
+	var readers *isaac.BlockItemReaders
+	var db isaac.Database
+
+	if err := util.LoadFromContextOK(pctx,
+		LoggingContextKey, &log,
+		DesignContextKey, &design,
+		BlockItemReadersContextKey, &readers,
+		CenterDatabaseContextKey, &db,
+	); err != nil {
+		return pctx, err
+	}
+
+	args := isaacblock.NewLocalFSArchiverArgs()
+	args.Heights = design.LocalParams.MISC.BlockArchiveHeights
+	args.Interval = design.LocalParams.MISC.BlockArchiveInterval
+	args.LastHeightFunc = func() (base.Height, bool, error) {
+		switch m, found, err := db.LastBlockMap(); {
+		case err != nil, !found:
+			return base.NilHeight, found, err
+		default:
+			return m.Manifest().Height(), true, nil
+		}
+	}
+	args.WhenArchived = func(a *isaac.BlockArchive) {
+		log.Log().Debug().
+			Interface("from", a.From()).
+			Interface("to", a.To()).
+			Int("items", len(a.Index().Items)).
+			Msg("block archived")
+	}
+
+	archiver := isaacblock.NewLocalFSArchiver(readers, args)
+	_ = archiver.SetLogging(log)
+
+	return context.WithValue(pctx, LocalFSArchiverContextKey, archiver), nil
+}
+
 func LoadPermanentDatabase(
 	uri, id string,
 	encs *encoder.Encoders,
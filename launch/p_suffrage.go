@@ -163,7 +163,21 @@ func PLastConsensusNodesWatcher(pctx context.Context) (context.Context, error) {
 				return base.NilHeight, nil, false, err
 			}
 
-			return proof.Map().Manifest().Height(), suf, true, nil
+			height := proof.Map().Manifest().Height()
+
+			switch m, found, err := db.LastBlockMap(); {
+			case err != nil:
+				return base.NilHeight, nil, false, err
+			case found && m.Manifest().Height() > height:
+				height = m.Manifest().Height()
+			}
+
+			suf, err = isaac.KeyRotatedSuffrage(suf, height)
+			if err != nil {
+				return base.NilHeight, nil, false, err
+			}
+
+			return height, suf, true, nil
 		},
 	)
 
@@ -360,6 +374,12 @@ func PSuffrageVoting(pctx context.Context) (context.Context, error) {
 		return pctx, err
 	}
 
+	var alerts *Alerts
+
+	if err := util.LoadFromContext(pctx, AlertsContextKey, &alerts); err != nil {
+		return pctx, err
+	}
+
 	svbroadcastf := broadcastSuffrageVotingFunc(log, m)
 
 	sv := isaac.NewSuffrageVoting(
@@ -383,6 +403,10 @@ func PSuffrageVoting(pctx context.Context) (context.Context, error) {
 	)
 
 	ballotbox.SetSuffrageVoteFunc(func(op base.SuffrageExpelOperation) error {
+		if alerts != nil {
+			alerts.SuffrageExpel(op)
+		}
+
 		_, err := sv.Vote(op)
 
 		return err
@@ -419,6 +443,10 @@ func PSuffrageVoting(pctx context.Context) (context.Context, error) {
 			return false, err
 		}
 
+		if alerts != nil {
+			alerts.SuffrageExpel(op)
+		}
+
 		return sv.Vote(op)
 	}
 
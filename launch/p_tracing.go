@@ -0,0 +1,81 @@
+package launch
+
+import (
+	"context"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/ProtoconNet/mitum2/util/ps"
+	"github.com/ProtoconNet/mitum2/util/tracing"
+)
+
+var (
+	PNameTracing          = ps.Name("tracing")
+	TracingFlagContextKey = util.ContextKey("tracing-flag")
+	TracerContextKey      = util.ContextKey("tracer")
+)
+
+// PStartTracing starts the tracer with the exporter from the tracing flag,
+// "file path" or "http collector url". Without flag, tracing is disabled.
+func PStartTracing(pctx context.Context) (context.Context, error) {
+	e := util.StringError("start tracing")
+
+	var log *logging.Logging
+	var local base.LocalNode
+	var flag string
+
+	if err := util.LoadFromContextOK(pctx,
+		LoggingContextKey, &log,
+		LocalContextKey, &local,
+	); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	if err := util.LoadFromContext(pctx, TracingFlagContextKey, &flag); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	if len(flag) < 1 {
+		log.Log().Debug().Msg("tracing disabled")
+
+		return pctx, nil
+	}
+
+	exporter, err := tracing.NewExporterFromString(flag)
+	if err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	tracer := tracing.NewTracer(local.Address().String(), exporter)
+	_ = tracer.SetLogging(log)
+
+	if err := tracer.Start(context.Background()); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	tracing.SetDefaultTracer(tracer)
+
+	log.Log().Debug().Str("exporter", flag).Msg("tracing started")
+
+	return context.WithValue(pctx, TracerContextKey, tracer), nil
+}
+
+func PCloseTracing(pctx context.Context) (context.Context, error) {
+	var tracer *tracing.Tracer
+
+	switch err := util.LoadFromContext(pctx, TracerContextKey, &tracer); {
+	case err != nil:
+		return pctx, err
+	case tracer == nil:
+		return pctx, nil
+	}
+
+	tracing.SetDefaultTracer(nil)
+
+	if err := tracer.Stop(); err != nil {
+		return pctx, util.StringError("stop tracing").Wrap(err)
+	}
+
+	return pctx, nil
+}
@@ -0,0 +1,394 @@
+package launch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	"github.com/ProtoconNet/mitum2/network/quicstream"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/localtime"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/ProtoconNet/mitum2/util/ps"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+var (
+	PNameProfiler      = ps.Name("profiler")
+	ProfilerContextKey = util.ContextKey("profiler")
+)
+
+var LocalFSProfileDirectoryName = "profile"
+
+var (
+	DefaultProfilerInterval        = time.Minute * 10
+	DefaultProfilerCPUDuration     = time.Second * 10
+	DefaultProfilerMaxCaptures     = 10
+	DefaultProfilerRetention       = time.Hour * 72
+	DefaultProfilerSlowBlockFactor = 5
+)
+
+var ErrProfileTooFrequent = util.NewIDError("too frequent profile capture")
+
+var ProfileACLScope = ACLScope("profile")
+
+var profileReasonReplacer = regexp.MustCompile(`[^a-zA-Z0-9_\-.]`)
+
+// ProfilerDesign sets the pprof snapshot captures.
+type ProfilerDesign struct {
+	// Anomaly enables the automatic captures when the block is too slow or the
+	// network handler is timed out.
+	Anomaly bool `json:"anomaly,omitempty" yaml:"anomaly,omitempty"`
+	// Interval is the minimum interval between captures.
+	Interval util.ReadableDuration `json:"interval,omitempty" yaml:"interval,omitempty"`
+	// CPUDuration is the duration of cpu profile.
+	CPUDuration util.ReadableDuration `json:"cpu_duration,omitempty" yaml:"cpu_duration,omitempty"`
+	// MaxCaptures is the maximum number of captures to keep.
+	MaxCaptures int `json:"max_captures,omitempty" yaml:"max_captures,omitempty"`
+	// Retention is the maximum age of captures to keep.
+	Retention util.ReadableDuration `json:"retention,omitempty" yaml:"retention,omitempty"`
+	// SlowBlock is the block interval to be regarded as anomaly; if empty,
+	// DefaultProfilerSlowBlockFactor * MinWaitNextBlockINITBallot is used.
+	SlowBlock util.ReadableDuration `json:"slow_block,omitempty" yaml:"slow_block,omitempty"`
+}
+
+func (d *ProfilerDesign) IsValid([]byte) error {
+	e := util.ErrInvalid.Errorf("invalid ProfilerDesign")
+
+	switch {
+	case d.Interval < 0:
+		return e.Errorf("wrong interval")
+	case d.CPUDuration < 0:
+		return e.Errorf("wrong cpu_duration")
+	case d.MaxCaptures < 0:
+		return e.Errorf("wrong max_captures")
+	case d.Retention < 0:
+		return e.Errorf("wrong retention")
+	case d.SlowBlock < 0:
+		return e.Errorf("wrong slow_block")
+	}
+
+	if d.Interval < 1 {
+		d.Interval = util.ReadableDuration(DefaultProfilerInterval)
+	}
+
+	if d.CPUDuration < 1 {
+		d.CPUDuration = util.ReadableDuration(DefaultProfilerCPUDuration)
+	}
+
+	if d.MaxCaptures < 1 {
+		d.MaxCaptures = DefaultProfilerMaxCaptures
+	}
+
+	if d.Retention < 1 {
+		d.Retention = util.ReadableDuration(DefaultProfilerRetention)
+	}
+
+	if d.Interval < d.CPUDuration {
+		return e.Errorf("interval should be over cpu_duration")
+	}
+
+	return nil
+}
+
+// Profiler captures the cpu, heap and goroutine pprof snapshots under the
+// profile directory. Each capture is stored in it's own directory; captures
+// are rate limited by interval and the old captures are removed by max
+// captures and retention.
+type Profiler struct {
+	*logging.Logging
+	slowBlockf   func() time.Duration
+	lastManifest base.Manifest
+	lastCapture  time.Time
+	root         string
+	design       ProfilerDesign
+	sync.Mutex
+}
+
+func NewProfiler(root string, design ProfilerDesign, slowBlockf func() time.Duration) *Profiler {
+	_ = design.IsValid(nil) // NOTE fill defaults
+
+	return &Profiler{
+		Logging: logging.NewLogging(func(zctx zerolog.Context) zerolog.Context {
+			return zctx.Str("module", "profiler")
+		}),
+		root:       root,
+		design:     design,
+		slowBlockf: slowBlockf,
+	}
+}
+
+// Capture starts new capture in background and returns the directory of
+// capture; if the last capture is within interval, it returns
+// ErrProfileTooFrequent.
+func (p *Profiler) Capture(reason string) (string, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	now := localtime.Now().UTC()
+
+	if !p.lastCapture.IsZero() {
+		if d := now.Sub(p.lastCapture); d < time.Duration(p.design.Interval) {
+			return "", ErrProfileTooFrequent.Errorf("retry after %s", time.Duration(p.design.Interval)-d)
+		}
+	}
+
+	p.lastCapture = now
+
+	name := now.Format("20060102T150405.000000000")
+	if i := profileReasonReplacer.ReplaceAllString(reason, "_"); len(i) > 0 {
+		name += "-" + i
+	}
+
+	dir := filepath.Join(p.root, name)
+
+	go func() {
+		l := p.Log().With().Str("reason", reason).Str("directory", dir).Logger()
+
+		if err := p.capture(dir, reason, time.Duration(p.design.CPUDuration)); err != nil {
+			l.Error().Err(err).Msg("failed to capture profile")
+
+			return
+		}
+
+		l.Debug().Msg("profile captured")
+
+		if err := p.clean(); err != nil {
+			l.Error().Err(err).Msg("failed to clean profiles")
+		}
+	}()
+
+	return dir, nil
+}
+
+// NewBlockSaved captures, when the interval between blocks is over slow block
+// duration.
+func (p *Profiler) NewBlockSaved(m base.Manifest) {
+	if !p.design.Anomaly {
+		return
+	}
+
+	p.Lock()
+	prev := p.lastManifest
+
+	if prev != nil && m.Height() <= prev.Height() {
+		p.Unlock()
+
+		return
+	}
+
+	p.lastManifest = m
+	p.Unlock()
+
+	if prev == nil || m.Height() != prev.Height()+1 {
+		return
+	}
+
+	slow := time.Duration(p.design.SlowBlock)
+	if slow < 1 {
+		slow = p.slowBlockf() * time.Duration(DefaultProfilerSlowBlockFactor)
+	}
+
+	if d := m.ProposedAt().Sub(prev.ProposedAt()); d > slow {
+		p.anomaly(fmt.Sprintf("slow_block_%d", m.Height()))
+	}
+}
+
+// HandlerTimeout captures, when the network handler is timed out.
+func (p *Profiler) HandlerTimeout(name quicstream.HandlerName) {
+	if !p.design.Anomaly {
+		return
+	}
+
+	p.anomaly("handler_timeout_" + name.String())
+}
+
+func (p *Profiler) anomaly(reason string) {
+	switch dir, err := p.Capture(reason); {
+	case errors.Is(err, ErrProfileTooFrequent):
+		p.Log().Debug().Err(err).Str("reason", reason).Msg("anomaly profile skipped")
+	case err != nil:
+		p.Log().Error().Err(err).Str("reason", reason).Msg("failed anomaly profile")
+	default:
+		p.Log().Info().Str("reason", reason).Str("directory", dir).Msg("anomaly detected; profile capturing")
+	}
+}
+
+func (p *Profiler) capture(dir, reason string, cpuDuration time.Duration) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "reason"), []byte(reason), 0o600); err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, name := range []string{"goroutine", "heap"} {
+		if err := writeProfileFile(filepath.Join(dir, name+".pprof"), func(f *os.File) error {
+			return pprof.Lookup(name).WriteTo(f, 0)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return writeProfileFile(filepath.Join(dir, "cpu.pprof"), func(f *os.File) error {
+		if err := pprof.StartCPUProfile(f); err != nil {
+			return errors.WithStack(err)
+		}
+
+		<-time.After(cpuDuration)
+
+		pprof.StopCPUProfile()
+
+		return nil
+	})
+}
+
+func (p *Profiler) clean() error {
+	switch _, err := os.Stat(p.root); {
+	case os.IsNotExist(err):
+		return nil
+	case err != nil:
+		return errors.WithStack(err)
+	}
+
+	entries, err := os.ReadDir(p.root)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	var names []string
+
+	for i := range entries {
+		if entries[i].IsDir() {
+			names = append(names, entries[i].Name())
+		}
+	}
+
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+
+	before := localtime.Now().UTC().Add(time.Duration(p.design.Retention) * -1)
+
+	for i := range names {
+		if i < p.design.MaxCaptures {
+			if t, err := time.Parse("20060102T150405.000000000", strings.SplitN(names[i], "-", 2)[0]); err != nil ||
+				!t.Before(before) {
+				continue
+			}
+		}
+
+		if err := os.RemoveAll(filepath.Join(p.root, names[i])); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return nil
+}
+
+func writeProfileFile(path string, f func(*os.File) error) error {
+	i, err := os.OpenFile(filepath.Clean(path), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	defer func() {
+		_ = i.Close()
+	}()
+
+	return f(i)
+}
+
+func LocalFSProfileDirectory(root string) string {
+	return filepath.Join(root, LocalFSProfileDirectoryName)
+}
+
+func PProfiler(pctx context.Context) (context.Context, error) {
+	var log *logging.Logging
+	var design NodeDesign
+	var isaacparams *isaac.Params
+
+	if err := util.LoadFromContextOK(pctx,
+		LoggingContextKey, &log,
+		DesignContextKey, &design,
+		ISAACParamsContextKey, &isaacparams,
+	); err != nil {
+		return pctx, err
+	}
+
+	p := NewProfiler(
+		LocalFSProfileDirectory(design.Storage.Base),
+		design.Profiler,
+		isaacparams.MinWaitNextBlockINITBallot,
+	)
+	_ = p.SetLogging(log)
+
+	return context.WithValue(pctx, ProfilerContextKey, p), nil
+}
+
+func writeProfile(pctx context.Context) (writeNodeValueFunc, error) {
+	var p *Profiler
+
+	if err := util.LoadFromContextOK(pctx, ProfilerContextKey, &p); err != nil {
+		return nil, err
+	}
+
+	var aclallow ACLAllowFunc
+
+	switch i, err := pACLAllowFunc(pctx); {
+	case err != nil:
+		return nil, err
+	default:
+		aclallow = i
+	}
+
+	return func(ctx context.Context, key, value, acluser string) (prev, next interface{}, updated bool, _ error) {
+		if len(key) > 0 {
+			return nil, nil, false, util.ErrNotFound.Errorf("unknown key, %q for profile", key)
+		}
+
+		extra := zerolog.Dict().Str("reason", value)
+
+		if !aclallow(ctx, acluser, ProfileACLScope, WriteAllowACLPerm, extra) {
+			return nil, nil, false, ErrACLAccessDenied.WithStack()
+		}
+
+		reason := "remote"
+		if len(value) > 0 {
+			reason += "-" + value
+		}
+
+		dir, err := p.Capture(reason)
+		if err != nil {
+			return nil, nil, false, err
+		}
+
+		return nil, dir, true, nil
+	}, nil
+}
+
+func profileTimeoutHandler(
+	handler quicstream.Handler,
+	name quicstream.HandlerName,
+	p *Profiler,
+) quicstream.Handler {
+	return func(ctx context.Context, addr net.Addr, r io.Reader, w io.WriteCloser) (context.Context, error) {
+		i, err := handler(ctx, addr, r, w)
+		if errors.Is(err, context.DeadlineExceeded) {
+			p.HandlerTimeout(name)
+		}
+
+		return i, err
+	}
+}
@@ -0,0 +1,209 @@
+package launch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testProfiler struct {
+	suite.Suite
+}
+
+func (t *testProfiler) design() ProfilerDesign {
+	d := ProfilerDesign{
+		Anomaly:     true,
+		Interval:    util.ReadableDuration(time.Minute),
+		CPUDuration: util.ReadableDuration(time.Millisecond * 100),
+	}
+	t.NoError(d.IsValid(nil))
+
+	return d
+}
+
+func (t *testProfiler) waitCaptured(dir string) {
+	t.Eventually(func() bool {
+		fi, err := os.Stat(filepath.Join(dir, "cpu.pprof"))
+		if err != nil || fi.Size() < 1 {
+			return false
+		}
+
+		for _, name := range []string{"reason", "goroutine.pprof", "heap.pprof"} {
+			if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+				return false
+			}
+		}
+
+		return true
+	}, time.Second*3, time.Millisecond*100)
+}
+
+func (t *testProfiler) manifest(height base.Height, proposedAt time.Time) base.Manifest {
+	return isaac.NewManifest(
+		height,
+		valuehash.RandomSHA256(),
+		valuehash.RandomSHA256(),
+		nil,
+		nil,
+		valuehash.RandomSHA256(),
+		proposedAt,
+	)
+}
+
+func (t *testProfiler) TestDesign() {
+	t.Run("defaults", func() {
+		d := ProfilerDesign{}
+
+		t.NoError(d.IsValid(nil))
+		t.False(d.Anomaly)
+		t.Equal(DefaultProfilerInterval, time.Duration(d.Interval))
+		t.Equal(DefaultProfilerCPUDuration, time.Duration(d.CPUDuration))
+		t.Equal(DefaultProfilerMaxCaptures, d.MaxCaptures)
+		t.Equal(DefaultProfilerRetention, time.Duration(d.Retention))
+	})
+
+	t.Run("interval under cpu duration", func() {
+		d := ProfilerDesign{
+			Interval:    util.ReadableDuration(time.Second),
+			CPUDuration: util.ReadableDuration(time.Second * 2),
+		}
+
+		err := d.IsValid(nil)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "cpu_duration")
+	})
+}
+
+func (t *testProfiler) TestCapture() {
+	root := t.T().TempDir()
+
+	p := NewProfiler(root, t.design(), nil)
+
+	dir, err := p.Capture("show me")
+	t.NoError(err)
+	t.Equal(root, filepath.Dir(dir))
+	t.Contains(filepath.Base(dir), "-show_me")
+
+	t.waitCaptured(dir)
+
+	b, err := os.ReadFile(filepath.Join(dir, "reason"))
+	t.NoError(err)
+	t.Equal("show me", string(b))
+
+	t.Run("too frequent", func() {
+		_, err := p.Capture("again")
+		t.Error(err)
+		t.ErrorIs(err, ErrProfileTooFrequent)
+	})
+}
+
+func (t *testProfiler) TestClean() {
+	root := t.T().TempDir()
+
+	d := t.design()
+	d.MaxCaptures = 2
+	d.Retention = util.ReadableDuration(time.Hour)
+
+	p := NewProfiler(root, d, nil)
+
+	now := time.Now().UTC()
+
+	names := []string{
+		now.Add(time.Hour*-3).Format("20060102T150405.000000000") + "-a",
+		now.Add(time.Minute*-3).Format("20060102T150405.000000000") + "-b",
+		now.Add(time.Minute*-2).Format("20060102T150405.000000000") + "-c",
+		now.Add(time.Minute*-1).Format("20060102T150405.000000000") + "-d",
+	}
+
+	for i := range names {
+		t.NoError(os.MkdirAll(filepath.Join(root, names[i]), 0o700))
+	}
+
+	t.NoError(p.clean())
+
+	entries, err := os.ReadDir(root)
+	t.NoError(err)
+
+	found := make([]string, len(entries))
+	for i := range entries {
+		found[i] = entries[i].Name()
+	}
+
+	t.Equal(names[2:], found)
+
+	t.Run("expired", func() {
+		d.MaxCaptures = 10
+		d.Retention = util.ReadableDuration(time.Second * 90)
+
+		p := NewProfiler(root, d, nil)
+		t.NoError(p.clean())
+
+		entries, err := os.ReadDir(root)
+		t.NoError(err)
+		t.Equal(1, len(entries))
+		t.Equal(names[3], entries[0].Name())
+	})
+}
+
+func (t *testProfiler) TestSlowBlock() {
+	root := t.T().TempDir()
+
+	p := NewProfiler(root, t.design(), func() time.Duration { return time.Second })
+
+	now := time.Now().UTC()
+
+	p.NewBlockSaved(t.manifest(base.Height(33), now))
+	p.NewBlockSaved(t.manifest(base.Height(34), now.Add(time.Second*2)))
+
+	entries, err := os.ReadDir(root)
+	if err == nil {
+		t.Empty(entries)
+	}
+
+	p.NewBlockSaved(t.manifest(base.Height(35), now.Add(time.Second*10)))
+
+	t.Eventually(func() bool {
+		entries, err := os.ReadDir(root)
+
+		return err == nil && len(entries) == 1
+	}, time.Second*3, time.Millisecond*100)
+
+	entries, err = os.ReadDir(root)
+	t.NoError(err)
+	t.Contains(entries[0].Name(), "slow_block_35")
+
+	t.waitCaptured(filepath.Join(root, entries[0].Name()))
+}
+
+func (t *testProfiler) TestAnomalyDisabled() {
+	root := t.T().TempDir()
+
+	d := t.design()
+	d.Anomaly = false
+
+	p := NewProfiler(root, d, func() time.Duration { return time.Second })
+
+	now := time.Now().UTC()
+
+	p.NewBlockSaved(t.manifest(base.Height(33), now))
+	p.NewBlockSaved(t.manifest(base.Height(34), now.Add(time.Hour)))
+	p.HandlerTimeout(HandlerNameNodeRead)
+
+	<-time.After(time.Millisecond * 300)
+
+	entries, err := os.ReadDir(root)
+	t.NoError(err)
+	t.Empty(entries)
+}
+
+func TestProfiler(t *testing.T) {
+	suite.Run(t, new(testProfiler))
+}
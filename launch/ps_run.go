@@ -10,7 +10,9 @@ func DefaultRunPS() *ps.PS {
 		AddOK(PNameDesign, PLoadDesign, nil, PNameEncoder).
 		AddOK(PNameTimeSyncer, PStartTimeSyncer, PCloseTimeSyncer, PNameDesign).
 		AddOK(PNameLocal, PLocal, nil, PNameDesign).
+		AddOK(PNameTracing, PStartTracing, PCloseTracing, PNameLocal).
 		AddOK(PNameStorage, PStorage, nil, PNameLocal).
+		AddOK(PNameStartEventLogging, PStartEventLogging, PCloseEventLogging, PNameStorage).
 		AddOK(PNameProposalMaker, PProposalMaker, nil, PNameStorage).
 		AddOK(PNameNetwork, PNetwork, nil, PNameStorage).
 		AddOK(PNameMemberlist, PMemberlist, nil, PNameNetwork).
@@ -19,12 +21,16 @@ func DefaultRunPS() *ps.PS {
 		AddOK(PNameStartNetwork, PStartNetwork, PCloseNetwork, PNameStates).
 		AddOK(PNameStartMemberlist, PStartMemberlist, PCloseMemberlist, PNameStartNetwork).
 		AddOK(PNameStartSyncSourceChecker, PStartSyncSourceChecker, PCloseSyncSourceChecker, PNameStartNetwork).
+		AddOK(PNameStartDiscoveryUpdater, PStartDiscoveryUpdater, PCloseDiscoveryUpdater,
+			PNameStartMemberlist, PNameStartSyncSourceChecker).
 		AddOK(PNameStartLastConsensusNodesWatcher,
 			PStartLastConsensusNodesWatcher, PCloseLastConsensusNodesWatcher, PNameStartNetwork).
+		AddOK(PNameStartAlerts, PStartAlerts, PCloseAlerts, PNameStates).
 		AddOK(PNameStates, PStates, nil, PNameNetwork).
 		AddOK(PNameStatesReady, nil, PCloseStates,
 			PNameStartStorage,
 			PNameStartSyncSourceChecker,
+			PNameStartDiscoveryUpdater,
 			PNameStartLastConsensusNodesWatcher,
 			PNameStartMemberlist,
 			PNameStartNetwork,
@@ -39,6 +45,8 @@ func DefaultRunPS() *ps.PS {
 		PostAddOK(PNameINITObjectCache, PINITObjectCache)
 
 	_ = pps.POK(PNameLocal).
+		PostAddOK(PNameMetrics, PMetrics).
+		PostAddOK(PNameProfiler, PProfiler).
 		PostAddOK(PNameDiscoveryFlag, PDiscoveryFlag).
 		PostAddOK(PNameLoadACL, PLoadACL)
 
@@ -53,7 +61,10 @@ func DefaultRunPS() *ps.PS {
 		PostAddOK(PNameLoadFromDatabase, PLoadFromDatabase).
 		PostAddOK(PNameCheckBlocksOfStorage, PCheckBlocksOfStorage).
 		PostAddOK(PNamePatchBlockItemReaders, PPatchBlockItemReaders).
-		PostAddOK(PNameNodeInfo, PNodeInfo)
+		PostAddOK(PNameLocalFSArchiver, PLocalFSArchiver).
+		PostAddOK(PNameNodeInfo, PNodeInfo).
+		PostAddOK(PNameTimeline, PTimeline).
+		PostAddOK(PNameAlerts, PAlerts)
 
 	_ = pps.POK(PNameNetwork).
 		PreAddOK(PNameQuicstreamClient, PQuicstreamClient).
@@ -65,6 +76,7 @@ func DefaultRunPS() *ps.PS {
 		PreAddOK(PNameRateLimiterContextKey, PNetworkRateLimiter).
 		PostAddOK(PNameBallotbox, PBallotbox).
 		PostAddOK(PNameLongRunningMemberlistJoin, PLongRunningMemberlistJoin).
+		PostAddOK(PNameDiscoveryUpdater, PDiscoveryUpdater).
 		PostAddOK(PNameSuffrageVoting, PSuffrageVoting).
 		PostAddOK(PNameEventLoggingNetworkHandlers, PEventLoggingNetworkHandlers)
 
@@ -76,6 +88,8 @@ func DefaultRunPS() *ps.PS {
 		PreAddOK(PNameProposalProcessors, PProposalProcessors).
 		PreAddOK(PNameBallotStuckResolver, PBallotStuckResolver).
 		PostAddOK(PNamePatchLastConsensusNodesWatcher, PPatchLastConsensusNodesWatcher).
+		PostAddOK(PNamePatchMetrics, PPatchMetrics).
+		PostAddOK(PNamePatchHealth, PPatchHealth).
 		PostAddOK(PNameStatesSetHandlers, PStatesSetHandlers).
 		PostAddOK(PNameNetworkHandlersReadWriteNode, PNetworkHandlersReadWriteNode).
 		PostAddOK(PNamePatchMemberlist, PPatchMemberlist).
@@ -0,0 +1,872 @@
+package launch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	isaacblock "github.com/ProtoconNet/mitum2/isaac/block"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/ProtoconNet/mitum2/util/hint"
+	"github.com/ProtoconNet/mitum2/util/localtime"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+var (
+	DefaultRemoteSignerTimeout          = time.Second * 3
+	DefaultLocalSignerKeepHeights int64 = 3
+)
+
+var (
+	ErrDoubleSign          = util.NewIDError("double sign")
+	ErrSignerUnauthorized  = util.NewIDError("unauthorized signer request")
+	errSignerNotBallotSign = util.NewIDError("not ballot sign")
+)
+
+// signerSignedAtLayout is the layout of the signed time in sign bytes; see
+// localtime.Time.Bytes().
+var signerSignedAtLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
+
+// SignerDesign sets the remote signer; with signer, the privatekey is not in
+// the design and the signing requests are forwarded to the remote signer.
+type SignerDesign struct {
+	Publickey base.Publickey `json:"publickey" yaml:"publickey"`
+	// Address is the signer address; 'unix:///tmp/signer.sock' or
+	// 'tcp://127.0.0.1:4322'.
+	Address string `json:"address" yaml:"address"`
+	// TokenFile is the file path of token, which is shared with the signer to
+	// authenticate the requests.
+	TokenFile string                `json:"token_file,omitempty" yaml:"token_file,omitempty"`
+	Timeout   util.ReadableDuration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+type SignerDesignYAMLUnmarshaler struct {
+	Publickey string                `json:"publickey" yaml:"publickey"`
+	Address   string                `json:"address" yaml:"address"`
+	TokenFile string                `json:"token_file,omitempty" yaml:"token_file,omitempty"`
+	Timeout   util.ReadableDuration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+func (d *SignerDesign) IsValid([]byte) error {
+	e := util.ErrInvalid.Errorf("invalid SignerDesign")
+
+	if d.Publickey == nil {
+		return e.Errorf("empty publickey")
+	}
+
+	if err := d.Publickey.IsValid(nil); err != nil {
+		return e.Wrap(err)
+	}
+
+	switch network, _, err := signerNetworkAddress(d.Address); {
+	case err != nil:
+		return e.Wrap(err)
+	case network == "tcp" && len(d.TokenFile) < 1:
+		return e.Errorf("empty token file for tcp signer")
+	}
+
+	switch {
+	case d.Timeout < 0:
+		return e.Errorf("wrong timeout")
+	case d.Timeout < 1:
+		d.Timeout = util.ReadableDuration(DefaultRemoteSignerTimeout)
+	}
+
+	return nil
+}
+
+func (d SignerDesignYAMLUnmarshaler) Decode(enc encoder.Encoder) (*SignerDesign, error) {
+	pub, err := base.DecodePublickeyFromString(d.Publickey, enc)
+	if err != nil {
+		return nil, errors.WithMessage(err, "signer publickey")
+	}
+
+	return &SignerDesign{
+		Publickey: pub,
+		Address:   d.Address,
+		TokenFile: d.TokenFile,
+		Timeout:   d.Timeout,
+	}, nil
+}
+
+// LoadSignerToken reads the token from file.
+func LoadSignerToken(f string) (string, error) {
+	e := util.StringError("load signer token")
+
+	b, err := os.ReadFile(filepath.Clean(f))
+	if err != nil {
+		return "", e.Wrap(err)
+	}
+
+	token := strings.TrimSpace(string(b))
+	if len(token) < 1 {
+		return "", e.Errorf("empty token")
+	}
+
+	return token, nil
+}
+
+func signerNetworkAddress(s string) (network, address string, _ error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", "", util.ErrInvalid.WithMessage(err, "signer address")
+	}
+
+	switch u.Scheme {
+	case "unix":
+		if len(u.Path) < 1 {
+			return "", "", util.ErrInvalid.Errorf("empty unix socket path")
+		}
+
+		return "unix", u.Path, nil
+	case "tcp":
+		if len(u.Host) < 1 {
+			return "", "", util.ErrInvalid.Errorf("empty tcp address")
+		}
+
+		return "tcp", u.Host, nil
+	default:
+		return "", "", util.ErrInvalid.Errorf("unknown signer address, %q", s)
+	}
+}
+
+type remoteSignerRequest struct {
+	Type   string          `json:"type"`
+	Token  string          `json:"token,omitempty"`
+	Fact   json.RawMessage `json:"fact,omitempty"`
+	Source json.RawMessage `json:"source,omitempty"`
+	Body   []byte          `json:"body,omitempty"`
+}
+
+type remoteSignerResponse struct {
+	Publickey    string         `json:"publickey,omitempty"`
+	Error        string         `json:"error,omitempty"`
+	Signature    base.Signature `json:"signature,omitempty"`
+	DoubleSign   bool           `json:"double_sign,omitempty"`
+	Unauthorized bool           `json:"unauthorized,omitempty"`
+}
+
+// RemoteSignerPrivatekey is the Privatekey, which forwards the signing
+// requests to the remote signer; the privatekey itself never be in local.
+type RemoteSignerPrivatekey struct {
+	pub     base.Publickey
+	fact    base.BallotFact
+	source  interface{}
+	kind    base.SignKind
+	network string
+	address string
+	token   string
+	timeout time.Duration
+}
+
+func NewRemoteSignerPrivatekey(design SignerDesign) (*RemoteSignerPrivatekey, error) {
+	if err := design.IsValid(nil); err != nil {
+		return nil, err
+	}
+
+	network, address, _ := signerNetworkAddress(design.Address)
+
+	var token string
+
+	if len(design.TokenFile) > 0 {
+		i, err := LoadSignerToken(design.TokenFile)
+		if err != nil {
+			return nil, err
+		}
+
+		token = i
+	}
+
+	return &RemoteSignerPrivatekey{
+		pub:     design.Publickey,
+		network: network,
+		address: address,
+		token:   token,
+		timeout: time.Duration(design.Timeout),
+	}, nil
+}
+
+func (k *RemoteSignerPrivatekey) String() string {
+	return fmt.Sprintf("%s://%s", k.network, k.address)
+}
+
+func (k *RemoteSignerPrivatekey) Bytes() []byte {
+	return []byte(k.String())
+}
+
+func (k *RemoteSignerPrivatekey) IsValid([]byte) error {
+	if k.pub == nil {
+		return util.ErrInvalid.Errorf("empty publickey of remote signer")
+	}
+
+	if len(k.address) < 1 {
+		return util.ErrInvalid.Errorf("empty address of remote signer")
+	}
+
+	return nil
+}
+
+func (k *RemoteSignerPrivatekey) Publickey() base.Publickey {
+	return k.pub
+}
+
+func (k *RemoteSignerPrivatekey) Equal(b base.PKKey) bool {
+	return base.IsEqualPKKey(k, b)
+}
+
+func (k *RemoteSignerPrivatekey) WithBallotFact(fact base.BallotFact) base.Privatekey {
+	n := *k
+	n.fact = fact
+	n.kind = ""
+	n.source = nil
+
+	return &n
+}
+
+func (k *RemoteSignerPrivatekey) WithSignKind(kind base.SignKind, source interface{}) base.Privatekey {
+	n := *k
+	n.fact = nil
+	n.kind = kind
+	n.source = source
+
+	return &n
+}
+
+// Sign sends the bytes to the remote signer; with ballot fact or the source of
+// sign kind, it is also sent and the remote signer rebuilds the bytes from it.
+func (k *RemoteSignerPrivatekey) Sign(b []byte) (base.Signature, error) {
+	e := util.StringError("remote sign")
+
+	req := remoteSignerRequest{Type: "sign", Body: b}
+
+	switch {
+	case k.fact != nil:
+		i, err := util.MarshalJSON(k.fact)
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		req.Type = "sign_ballot"
+		req.Fact = i
+	case len(k.kind) > 0:
+		i, err := util.MarshalJSON(k.source)
+		if err != nil {
+			return nil, e.Wrap(err)
+		}
+
+		req.Type = "sign_" + string(k.kind)
+		req.Source = i
+	}
+
+	res, err := k.request(req)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	// NOTE check the signer has the same key
+	if err := k.pub.Verify(b, res.Signature); err != nil {
+		return nil, e.WithMessage(err, "different key in remote signer")
+	}
+
+	return res.Signature, nil
+}
+
+// Check checks the remote signer has the same publickey.
+func (k *RemoteSignerPrivatekey) Check() error {
+	e := util.StringError("check remote signer")
+
+	res, err := k.request(remoteSignerRequest{Type: "publickey"})
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	if res.Publickey != k.pub.String() {
+		return e.Errorf("different publickey in remote signer, %q", res.Publickey)
+	}
+
+	return nil
+}
+
+func (k *RemoteSignerPrivatekey) request(req remoteSignerRequest) (res remoteSignerResponse, _ error) {
+	conn, err := net.DialTimeout(k.network, k.address, k.timeout)
+	if err != nil {
+		return res, errors.WithStack(err)
+	}
+
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	_ = conn.SetDeadline(time.Now().Add(k.timeout))
+
+	req.Token = k.token
+
+	b, err := util.MarshalJSON(req)
+	if err != nil {
+		return res, err
+	}
+
+	if _, err := conn.Write(append(b, '\n')); err != nil {
+		return res, errors.WithStack(err)
+	}
+
+	switch i, err := bufio.NewReader(conn).ReadBytes('\n'); {
+	case err != nil:
+		return res, errors.WithStack(err)
+	default:
+		if err := util.UnmarshalJSON(i, &res); err != nil {
+			return res, err
+		}
+	}
+
+	switch {
+	case res.DoubleSign:
+		return res, ErrDoubleSign.Errorf(res.Error)
+	case res.Unauthorized:
+		return res, ErrSignerUnauthorized.Errorf(res.Error)
+	case len(res.Error) > 0:
+		return res, errors.Errorf("remote signer: %s", res.Error)
+	default:
+		return res, nil
+	}
+}
+
+type localSignerSigned struct {
+	Fact  string          `json:"fact"`
+	Point base.StagePoint `json:"point"`
+}
+
+type localSignerState struct {
+	Signed map[string]localSignerSigned `json:"signed"`
+	// Watermark is the highest pruned height; the ballots at or below it are
+	// refused.
+	Watermark base.Height `json:"watermark"`
+}
+
+type LocalSignerArgs struct {
+	Privatekey base.Privatekey
+	Node       base.Address
+	Encoder    encoder.Encoder
+	NetworkID  base.NetworkID
+	// Address is the listen address; 'unix:///tmp/signer.sock' or
+	// 'tcp://127.0.0.1:4322'.
+	Address string
+	// StateFile keeps the signed ballots and watermark.
+	StateFile string
+	// Token authenticates the requests; for tcp, token is required.
+	Token string
+	// AllowRaw allows to sign the bytes without kind, which are not checked for
+	// double sign; the ballot sign bytes are still refused.
+	AllowRaw bool
+}
+
+// LocalSigner is the reference signer daemon; it signs the requests from
+// RemoteSignerPrivatekey thru unix socket or tcp. The ballot sign bytes are
+// rebuilt from the decoded ballot fact and the signed ballot facts are kept by
+// hint and stage point to prevent the double sign; if the state file is given,
+// the signed ballots are stored and loaded from file. The sign bytes of
+// blockmap, operation and challenge are also rebuilt from their sources.
+type LocalSigner struct {
+	*logging.Logging
+	*util.ContextDaemon
+	args    LocalSignerArgs
+	state   localSignerState
+	network string
+	address string
+	sync.Mutex
+}
+
+func NewLocalSigner(args LocalSignerArgs) (*LocalSigner, error) {
+	e := util.StringError("create LocalSigner")
+
+	network, addr, err := signerNetworkAddress(args.Address)
+
+	switch {
+	case err != nil:
+		return nil, e.Wrap(err)
+	case args.Privatekey == nil:
+		return nil, e.Errorf("empty privatekey")
+	case args.Node == nil:
+		return nil, e.Errorf("empty node")
+	case args.Encoder == nil:
+		return nil, e.Errorf("empty encoder")
+	case len(args.NetworkID) < 1:
+		return nil, e.Errorf("empty network id")
+	case network == "tcp" && len(args.Token) < 1:
+		return nil, e.Errorf("empty token for tcp signer")
+	}
+
+	s := &LocalSigner{
+		Logging: logging.NewLogging(func(zctx zerolog.Context) zerolog.Context {
+			return zctx.Str("module", "local-signer")
+		}),
+		args:    args,
+		network: network,
+		address: addr,
+		state: localSignerState{
+			Signed:    map[string]localSignerSigned{},
+			Watermark: base.NilHeight,
+		},
+	}
+
+	if err := s.load(); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	s.ContextDaemon = util.NewContextDaemon(s.start)
+
+	return s, nil
+}
+
+func (s *LocalSigner) start(ctx context.Context) error {
+	if s.network == "unix" {
+		_ = os.Remove(s.address)
+	}
+
+	l, err := net.Listen(s.network, s.address)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if s.network == "unix" {
+		if err := os.Chmod(s.address, 0o600); err != nil {
+			_ = l.Close()
+
+			return errors.WithStack(err)
+		}
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		_ = l.Close()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			s.Log().Error().Err(err).Msg("failed to accept")
+
+			continue
+		}
+
+		go s.handle(conn)
+	}
+}
+
+func (s *LocalSigner) handle(conn net.Conn) {
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	_ = conn.SetDeadline(time.Now().Add(DefaultRemoteSignerTimeout))
+
+	var res remoteSignerResponse
+
+	switch b, err := bufio.NewReader(conn).ReadBytes('\n'); {
+	case err != nil:
+		res.Error = err.Error()
+	default:
+		res = s.response(b)
+	}
+
+	b, err := util.MarshalJSON(res)
+	if err != nil {
+		return
+	}
+
+	_, _ = conn.Write(append(b, '\n'))
+}
+
+func (s *LocalSigner) response(b []byte) (res remoteSignerResponse) {
+	var req remoteSignerRequest
+
+	if err := util.UnmarshalJSON(b, &req); err != nil {
+		res.Error = err.Error()
+
+		return res
+	}
+
+	if len(s.args.Token) > 0 && subtle.ConstantTimeCompare([]byte(req.Token), []byte(s.args.Token)) != 1 {
+		s.Log().Warn().Str("type", req.Type).Msg("unauthorized request")
+
+		res.Unauthorized = true
+		res.Error = "wrong token"
+
+		return res
+	}
+
+	var sig base.Signature
+	var err error
+
+	switch req.Type {
+	case "publickey":
+		res.Publickey = s.args.Privatekey.Publickey().String()
+
+		return res
+	case "sign":
+		sig, err = s.signRaw(req.Body)
+	case "sign_ballot":
+		sig, err = s.signBallot(req.Fact, req.Body)
+	case "sign_" + string(base.BlockMapSignKind):
+		sig, err = s.signBlockMap(req.Source, req.Body)
+	case "sign_" + string(base.OperationSignKind):
+		sig, err = s.signOperation(req.Source, req.Body)
+	case "sign_" + string(base.ChallengeSignKind):
+		sig, err = s.signChallenge(req.Source, req.Body)
+	default:
+		res.Error = fmt.Sprintf("unknown request type, %q", req.Type)
+
+		return res
+	}
+
+	switch {
+	case errors.Is(err, ErrDoubleSign):
+		res.DoubleSign = true
+		res.Error = err.Error()
+	case err != nil:
+		res.Error = err.Error()
+	default:
+		res.Signature = sig
+	}
+
+	return res
+}
+
+func (s *LocalSigner) signRaw(body []byte) (base.Signature, error) {
+	switch {
+	case !s.args.AllowRaw:
+		return nil, errSignerNotBallotSign.Errorf("only ballot can be signed")
+	case s.isBallotSignBytes(body):
+		s.Log().Warn().Msg("ballot sign bytes requested as raw")
+
+		return nil, errSignerNotBallotSign.Errorf("ballot sign bytes can not be signed as raw")
+	}
+
+	return s.args.Privatekey.Sign(body)
+}
+
+// isBallotSignBytes checks whether body can be the ballot sign bytes of local
+// node; it has the prefix of network id and node, and ends with signed time. If
+// body decodes as ballot sign fact or ballot fact, it is also true.
+func (s *LocalSigner) isBallotSignBytes(body []byte) bool {
+	if i, err := s.args.Encoder.Decode(body); err == nil {
+		switch i.(type) {
+		case base.BallotSignFact, base.BallotFact:
+			return true
+		}
+	}
+
+	prefix := util.ConcatBytesSlice(s.args.NetworkID, s.args.Node.Bytes())
+
+	if !bytes.HasPrefix(body, prefix) {
+		return false
+	}
+
+	rest := body[len(prefix):]
+
+	for i := range rest {
+		if _, err := time.Parse(signerSignedAtLayout, string(rest[i:])); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// signBlockMap rebuilds the sign bytes from the manifest and item checksums of
+// blockmap; the manifest hash is checked, so the sign bytes can not be the
+// ballot sign bytes.
+func (s *LocalSigner) signBlockMap(b json.RawMessage, body []byte) (base.Signature, error) {
+	e := util.StringError("sign blockmap")
+
+	var u struct {
+		Items map[base.BlockItemType]struct {
+			Checksum string `json:"checksum"`
+		} `json:"items"`
+		Manifest json.RawMessage `json:"manifest"`
+	}
+
+	if err := util.UnmarshalJSON(b, &u); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	m := isaacblock.NewBlockMap()
+
+	var manifest base.Manifest
+
+	if err := encoder.Decode(s.args.Encoder, u.Manifest, &manifest); err != nil {
+		return nil, e.WithMessage(err, "decode manifest")
+	}
+
+	if err := manifest.IsValid(s.args.NetworkID); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	if err := isaac.IsValidManifestHash(manifest); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	m.SetManifest(manifest)
+
+	for k := range u.Items {
+		if err := m.SetItem(isaacblock.NewBlockMapItem(k, u.Items[k].Checksum)); err != nil {
+			return nil, e.Wrap(err)
+		}
+	}
+
+	signbytes, err := s.nodeSignBytes("blockmap", m.SignedBytes(), body)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	return s.args.Privatekey.Sign(signbytes)
+}
+
+// signOperation rebuilds the sign bytes from the operation fact; the ballot
+// fact is refused.
+func (s *LocalSigner) signOperation(b json.RawMessage, body []byte) (base.Signature, error) {
+	e := util.StringError("sign operation")
+
+	var fact base.Fact
+
+	if err := encoder.Decode(s.args.Encoder, b, &fact); err != nil {
+		return nil, e.WithMessage(err, "decode operation fact")
+	}
+
+	if _, ok := fact.(base.BallotFact); ok {
+		return nil, errSignerNotBallotSign.Errorf("ballot fact can not be signed as operation")
+	}
+
+	if err := fact.IsValid(s.args.NetworkID); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	signbytes, err := s.nodeSignBytes("operation fact", fact.Hash().Bytes(), body)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	return s.args.Privatekey.Sign(signbytes)
+}
+
+// signChallenge rebuilds the sign bytes from the challenge input; the sign
+// bytes of node challenge consist of node, network id and input, and the
+// others consist of network id and input.
+func (s *LocalSigner) signChallenge(b json.RawMessage, body []byte) (base.Signature, error) {
+	e := util.StringError("sign challenge")
+
+	var input []byte
+
+	if err := util.UnmarshalJSON(b, &input); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	if len(input) < 1 {
+		return nil, e.Errorf("empty input")
+	}
+
+	switch {
+	case bytes.Equal(body, util.ConcatBytesSlice(s.args.Node.Bytes(), s.args.NetworkID, input)):
+	case bytes.Equal(body, util.ConcatBytesSlice(s.args.NetworkID, input)):
+		if s.isBallotSignBytes(body) {
+			s.Log().Warn().Msg("ballot sign bytes requested as challenge")
+
+			return nil, errSignerNotBallotSign.Errorf("ballot sign bytes can not be signed as challenge")
+		}
+	default:
+		return nil, e.Errorf("sign bytes does not match with challenge input")
+	}
+
+	return s.args.Privatekey.Sign(body)
+}
+
+func (s *LocalSigner) signBallot(b json.RawMessage, body []byte) (base.Signature, error) {
+	fact, err := s.decodeBallotFact(b)
+	if err != nil {
+		return nil, err
+	}
+
+	signbytes, err := s.nodeSignBytes("ballot fact", fact.Hash().Bytes(), body)
+	if err != nil {
+		return nil, errors.WithMessage(err, "ballot sign bytes")
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	point := fact.Point()
+
+	if point.Height() <= s.state.Watermark {
+		return nil, ErrDoubleSign.Errorf("height, %d at or below watermark, %d", point.Height(), s.state.Watermark)
+	}
+
+	key := fact.(hint.Hinter).Hint().Type().String() + "-" + point.String() //nolint:forcetypeassert //...
+
+	if i, found := s.state.Signed[key]; found && i.Fact != fact.Hash().String() {
+		s.Log().Warn().Interface("fact", fact).Str("signed", i.Fact).Msg("double sign requested")
+
+		return nil, ErrDoubleSign.Errorf("already signed different fact for %q", key)
+	}
+
+	sig, err := s.args.Privatekey.Sign(signbytes)
+	if err != nil {
+		return nil, err
+	}
+
+	s.state.Signed[key] = localSignerSigned{Point: point, Fact: fact.Hash().String()}
+
+	s.prune(point.Height())
+
+	// NOTE if failed to save, signature is not returned.
+	if err := s.save(); err != nil {
+		return nil, err
+	}
+
+	return sig, nil
+}
+
+func (s *LocalSigner) decodeBallotFact(b json.RawMessage) (base.BallotFact, error) {
+	e := util.StringError("decode ballot fact")
+
+	var fact base.BallotFact
+
+	if err := encoder.Decode(s.args.Encoder, b, &fact); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	if _, ok := fact.(hint.Hinter); !ok {
+		return nil, e.Errorf("expected hinter, but %T", fact)
+	}
+
+	if err := fact.IsValid(s.args.NetworkID); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	return fact, nil
+}
+
+// nodeSignBytes rebuilds the node sign bytes of ballot fact, blockmap and
+// operation; the sign bytes consist of network id, node, signed bytes of source
+// and signed time.
+func (s *LocalSigner) nodeSignBytes(name string, signed, body []byte) ([]byte, error) {
+	prefix := util.ConcatBytesSlice(s.args.NetworkID, s.args.Node.Bytes(), signed)
+
+	if !bytes.HasPrefix(body, prefix) {
+		return nil, errors.Errorf("sign bytes does not match with %s", name)
+	}
+
+	signedAt, err := time.Parse(signerSignedAtLayout, string(body[len(prefix):]))
+	if err != nil {
+		return nil, errors.WithMessage(err, "signed at")
+	}
+
+	b := util.ConcatBytesSlice(prefix, localtime.New(signedAt).Bytes())
+
+	if !bytes.Equal(b, body) {
+		return nil, errors.Errorf("sign bytes does not match with %s", name)
+	}
+
+	return b, nil
+}
+
+func (s *LocalSigner) prune(height base.Height) {
+	top := height - base.Height(DefaultLocalSignerKeepHeights) - 1
+	if top <= s.state.Watermark {
+		return
+	}
+
+	for k := range s.state.Signed {
+		if s.state.Signed[k].Point.Height() <= top {
+			delete(s.state.Signed, k)
+		}
+	}
+
+	s.state.Watermark = top
+}
+
+func (s *LocalSigner) load() error {
+	if len(s.args.StateFile) < 1 {
+		return nil
+	}
+
+	switch b, err := os.ReadFile(filepath.Clean(s.args.StateFile)); {
+	case os.IsNotExist(err):
+		return nil
+	case err != nil:
+		return errors.WithStack(err)
+	default:
+		var state localSignerState
+
+		if err := util.UnmarshalJSON(b, &state); err != nil {
+			return err
+		}
+
+		if state.Signed == nil {
+			state.Signed = map[string]localSignerSigned{}
+		}
+
+		s.state = state
+
+		return nil
+	}
+}
+
+// save writes the state file thru temp file to prevent the broken state file.
+func (s *LocalSigner) save() error {
+	if len(s.args.StateFile) < 1 {
+		return nil
+	}
+
+	e := util.StringError("save state")
+
+	b, err := util.MarshalJSON(s.state)
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	p := filepath.Clean(s.args.StateFile)
+
+	f, err := os.CreateTemp(filepath.Dir(p), "temp-")
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+
+	if _, err := f.Write(b); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := f.Sync(); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := os.Rename(f.Name(), p); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,578 @@
+package launch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	isaacblock "github.com/ProtoconNet/mitum2/isaac/block"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	jsonenc "github.com/ProtoconNet/mitum2/util/encoder/json"
+	"github.com/ProtoconNet/mitum2/util/localtime"
+	"github.com/ProtoconNet/mitum2/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testSigner struct {
+	suite.Suite
+	root      string
+	priv      base.Privatekey
+	node      base.Address
+	networkID base.NetworkID
+	enc       encoder.Encoder
+}
+
+func (t *testSigner) SetupSuite() {
+	t.enc = jsonenc.NewEncoder()
+
+	t.NoError(t.enc.Add(encoder.DecodeDetail{Hint: base.StringAddressHint, Instance: base.StringAddress{}}))
+	t.NoError(t.enc.Add(encoder.DecodeDetail{Hint: base.MPublickeyHint, Instance: &base.MPublickey{}}))
+	t.NoError(t.enc.Add(encoder.DecodeDetail{Hint: isaac.INITBallotFactHint, Instance: isaac.INITBallotFact{}}))
+	t.NoError(t.enc.Add(encoder.DecodeDetail{Hint: isaac.ACCEPTBallotFactHint, Instance: isaac.ACCEPTBallotFact{}}))
+	t.NoError(t.enc.Add(encoder.DecodeDetail{Hint: isaac.SuffrageExpelFactHint, Instance: isaac.SuffrageExpelFact{}}))
+	t.NoError(t.enc.Add(encoder.DecodeDetail{Hint: isaac.ManifestHint, Instance: isaac.Manifest{}}))
+}
+
+func (t *testSigner) SetupTest() {
+	root, err := os.MkdirTemp("", "signer")
+	t.NoError(err)
+
+	t.root = root
+	t.priv = base.NewMPrivatekey()
+	t.node = base.RandomAddress("")
+	t.networkID = base.RandomNetworkID()
+}
+
+func (t *testSigner) TearDownTest() {
+	_ = os.RemoveAll(t.root)
+}
+
+func (t *testSigner) address() string {
+	return "unix://" + filepath.Join(t.root, "signer.sock")
+}
+
+func (t *testSigner) args(priv base.Privatekey) LocalSignerArgs {
+	return LocalSignerArgs{
+		Privatekey: priv,
+		Node:       t.node,
+		NetworkID:  t.networkID,
+		Encoder:    t.enc,
+		Address:    t.address(),
+	}
+}
+
+func (t *testSigner) newSigner(args LocalSignerArgs) *LocalSigner {
+	s, err := NewLocalSigner(args)
+	t.NoError(err)
+
+	t.NoError(s.Start(context.Background()))
+
+	t.Eventually(func() bool {
+		_, err := os.Stat(filepath.Join(t.root, "signer.sock"))
+
+		return err == nil
+	}, time.Second*2, time.Millisecond*10)
+
+	return s
+}
+
+func (t *testSigner) remote(pub base.Publickey) *RemoteSignerPrivatekey {
+	k, err := NewRemoteSignerPrivatekey(SignerDesign{Publickey: pub, Address: t.address()})
+	t.NoError(err)
+
+	return k
+}
+
+func (t *testSigner) fact(point base.Point) isaac.INITBallotFact {
+	return isaac.NewINITBallotFact(point, valuehash.RandomSHA256(), valuehash.RandomSHA256(), nil)
+}
+
+func (t *testSigner) sign(k base.Privatekey, fact isaac.INITBallotFact) error {
+	sf := isaac.NewINITBallotSignFact(fact)
+	if err := sf.NodeSign(k, t.networkID, t.node); err != nil {
+		return err
+	}
+
+	return sf.IsValid(t.networkID)
+}
+
+func (t *testSigner) TestDesign() {
+	t.Run("ok", func() {
+		d := SignerDesign{Publickey: t.priv.Publickey(), Address: "tcp://127.0.0.1:4322", TokenFile: "/tmp/token"}
+		t.NoError(d.IsValid(nil))
+		t.Equal(DefaultRemoteSignerTimeout, time.Duration(d.Timeout))
+	})
+
+	t.Run("empty publickey", func() {
+		d := SignerDesign{Address: t.address()}
+		err := d.IsValid(nil)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "empty publickey")
+	})
+
+	t.Run("unknown address", func() {
+		d := SignerDesign{Publickey: t.priv.Publickey(), Address: "http://127.0.0.1:4322"}
+		err := d.IsValid(nil)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "unknown signer address")
+	})
+
+	t.Run("empty unix path", func() {
+		d := SignerDesign{Publickey: t.priv.Publickey(), Address: "unix://"}
+		err := d.IsValid(nil)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "empty unix socket path")
+	})
+
+	t.Run("tcp without token file", func() {
+		d := SignerDesign{Publickey: t.priv.Publickey(), Address: "tcp://127.0.0.1:4322"}
+		d.TokenFile = ""
+		err := d.IsValid(nil)
+		t.ErrorIs(err, util.ErrInvalid)
+		t.ErrorContains(err, "empty token file")
+	})
+}
+
+func (t *testSigner) TestSign() {
+	args := t.args(t.priv)
+	args.AllowRaw = true
+
+	s := t.newSigner(args)
+	defer s.Stop()
+
+	k := t.remote(t.priv.Publickey())
+	t.NoError(k.Check())
+
+	input := util.UUID().Bytes()
+
+	sig, err := k.Sign(input)
+	t.NoError(err)
+	t.NoError(t.priv.Publickey().Verify(input, sig))
+}
+
+func (t *testSigner) TestRawNotAllowed() {
+	s := t.newSigner(t.args(t.priv))
+	defer s.Stop()
+
+	k := t.remote(t.priv.Publickey())
+
+	_, err := k.Sign(util.UUID().Bytes())
+	t.Error(err)
+	t.ErrorContains(err, "only ballot can be signed")
+}
+
+func (t *testSigner) TestRawBallotSignBytes() {
+	args := t.args(t.priv)
+	args.AllowRaw = true
+
+	s := t.newSigner(args)
+	defer s.Stop()
+
+	k := t.remote(t.priv.Publickey())
+
+	fact := t.fact(base.NewPoint(base.Height(33), 0))
+
+	t.Run("ballot sign bytes", func() {
+		b := util.ConcatBytesSlice(
+			t.networkID, t.node.Bytes(), fact.Hash().Bytes(), localtime.New(localtime.Now()).Bytes())
+
+		_, err := k.Sign(b)
+		t.Error(err)
+		t.ErrorContains(err, "ballot sign bytes can not be signed as raw")
+	})
+
+	t.Run("ballot fact", func() {
+		b, err := util.MarshalJSON(fact)
+		t.NoError(err)
+
+		_, err = k.Sign(b)
+		t.Error(err)
+		t.ErrorContains(err, "ballot sign bytes can not be signed as raw")
+	})
+
+	t.Run("ballot sign", func() {
+		t.NoError(t.sign(k, fact))
+	})
+}
+
+func (t *testSigner) TestSignOperation() {
+	s := t.newSigner(t.args(t.priv))
+	defer s.Stop()
+
+	k := t.remote(t.priv.Publickey())
+
+	t.Run("ok", func() {
+		op := isaac.NewSuffrageExpelOperation(
+			isaac.NewSuffrageExpelFact(base.RandomAddress(""), base.Height(33), base.Height(34), util.UUID().String()))
+		t.NoError(op.NodeSign(k, t.networkID, t.node))
+		t.NoError(op.IsValid(t.networkID))
+	})
+
+	t.Run("ballot fact", func() {
+		fact := t.fact(base.NewPoint(base.Height(33), 0))
+
+		_, err := base.NewBaseNodeSignFromFact(
+			t.node, k.WithSignKind(base.OperationSignKind, fact), t.networkID, fact)
+		t.Error(err)
+		t.ErrorContains(err, "ballot fact can not be signed as operation")
+	})
+
+	t.Run("different fact", func() {
+		fact := isaac.NewSuffrageExpelFact(base.RandomAddress(""), base.Height(33), base.Height(34), util.UUID().String())
+		other := isaac.NewSuffrageExpelFact(base.RandomAddress(""), base.Height(33), base.Height(34), util.UUID().String())
+
+		_, err := base.NewBaseNodeSignFromFact(
+			t.node, k.WithSignKind(base.OperationSignKind, other), t.networkID, fact)
+		t.Error(err)
+		t.ErrorContains(err, "sign bytes does not match with operation fact")
+	})
+}
+
+func (t *testSigner) TestSignBlockMap() {
+	s := t.newSigner(t.args(t.priv))
+	defer s.Stop()
+
+	k := t.remote(t.priv.Publickey())
+
+	newmap := func() isaacblock.BlockMap {
+		m := isaacblock.NewBlockMap()
+		m.SetManifest(isaac.NewManifest(
+			base.Height(33),
+			valuehash.RandomSHA256(),
+			valuehash.RandomSHA256(),
+			nil,
+			nil,
+			valuehash.RandomSHA256(),
+			localtime.Now().UTC(),
+		))
+
+		for _, i := range []base.BlockItemType{base.BlockItemProposal, base.BlockItemVoteproofs} {
+			t.NoError(m.SetItem(isaacblock.NewBlockMapItem(i, util.UUID().String())))
+		}
+
+		return m
+	}
+
+	t.Run("ok", func() {
+		m := newmap()
+
+		t.NoError(m.Sign(t.node, k, t.networkID))
+		t.NoError(m.IsValid(t.networkID))
+	})
+
+	t.Run("different blockmap", func() {
+		m := newmap()
+
+		_, err := base.NewBaseNodeSignFromBytes(
+			t.node, k.WithSignKind(base.BlockMapSignKind, newmap()), t.networkID, m.SignedBytes())
+		t.Error(err)
+		t.ErrorContains(err, "sign bytes does not match with blockmap")
+	})
+}
+
+func (t *testSigner) TestSignChallenge() {
+	s := t.newSigner(t.args(t.priv))
+	defer s.Stop()
+
+	k := t.remote(t.priv.Publickey())
+
+	t.Run("ok", func() {
+		input := util.UUID().Bytes()
+
+		b := util.ConcatBytesSlice(t.networkID, input)
+
+		sig, err := k.WithSignKind(base.ChallengeSignKind, input).Sign(b)
+		t.NoError(err)
+		t.NoError(t.priv.Publickey().Verify(b, sig))
+	})
+
+	t.Run("node challenge", func() {
+		input := util.UUID().Bytes()
+
+		b := util.ConcatBytesSlice(t.node.Bytes(), t.networkID, input)
+
+		sig, err := k.WithSignKind(base.ChallengeSignKind, input).Sign(b)
+		t.NoError(err)
+		t.NoError(t.priv.Publickey().Verify(b, sig))
+	})
+
+	t.Run("different input", func() {
+		_, err := k.WithSignKind(base.ChallengeSignKind, util.UUID().Bytes()).
+			Sign(util.ConcatBytesSlice(t.networkID, util.UUID().Bytes()))
+		t.Error(err)
+		t.ErrorContains(err, "sign bytes does not match with challenge input")
+	})
+
+	t.Run("ballot sign bytes", func() {
+		fact := t.fact(base.NewPoint(base.Height(33), 0))
+
+		input := util.ConcatBytesSlice(t.node.Bytes(), fact.Hash().Bytes(), localtime.New(localtime.Now()).Bytes())
+
+		_, err := k.WithSignKind(base.ChallengeSignKind, input).Sign(util.ConcatBytesSlice(t.networkID, input))
+		t.Error(err)
+		t.ErrorContains(err, "ballot sign bytes can not be signed as challenge")
+	})
+}
+
+func (t *testSigner) TestDifferentKey() {
+	args := t.args(t.priv)
+	args.AllowRaw = true
+
+	s := t.newSigner(args)
+	defer s.Stop()
+
+	k := t.remote(base.NewMPrivatekey().Publickey())
+
+	err := k.Check()
+	t.Error(err)
+	t.ErrorContains(err, "different publickey")
+
+	_, err = k.Sign(util.UUID().Bytes())
+	t.Error(err)
+	t.ErrorContains(err, "different key")
+}
+
+func (t *testSigner) TestToken() {
+	tokenfile := filepath.Join(t.root, "token")
+	t.NoError(os.WriteFile(tokenfile, []byte(util.UUID().String()+"\n"), 0o600))
+
+	token, err := LoadSignerToken(tokenfile)
+	t.NoError(err)
+
+	args := t.args(t.priv)
+	args.Token = token
+
+	s := t.newSigner(args)
+	defer s.Stop()
+
+	t.Run("ok", func() {
+		k, err := NewRemoteSignerPrivatekey(SignerDesign{
+			Publickey: t.priv.Publickey(),
+			Address:   t.address(),
+			TokenFile: tokenfile,
+		})
+		t.NoError(err)
+
+		t.NoError(k.Check())
+		t.NoError(t.sign(k, t.fact(base.NewPoint(base.Height(33), 0))))
+	})
+
+	t.Run("empty token", func() {
+		k := t.remote(t.priv.Publickey())
+
+		err := k.Check()
+		t.Error(err)
+		t.ErrorIs(err, ErrSignerUnauthorized)
+
+		err = t.sign(k, t.fact(base.NewPoint(base.Height(34), 0)))
+		t.Error(err)
+		t.ErrorIs(err, ErrSignerUnauthorized)
+	})
+
+	t.Run("wrong token", func() {
+		wrongfile := filepath.Join(t.root, "wrong")
+		t.NoError(os.WriteFile(wrongfile, []byte(util.UUID().String()), 0o600))
+
+		k, err := NewRemoteSignerPrivatekey(SignerDesign{
+			Publickey: t.priv.Publickey(),
+			Address:   t.address(),
+			TokenFile: wrongfile,
+		})
+		t.NoError(err)
+
+		err = k.Check()
+		t.Error(err)
+		t.ErrorIs(err, ErrSignerUnauthorized)
+	})
+
+	t.Run("tcp without token", func() {
+		args := t.args(t.priv)
+		args.Address = "tcp://127.0.0.1:4322"
+
+		_, err := NewLocalSigner(args)
+		t.Error(err)
+		t.ErrorContains(err, "empty token for tcp signer")
+	})
+}
+
+func (t *testSigner) TestBallotSign() {
+	s := t.newSigner(t.args(t.priv))
+	defer s.Stop()
+
+	k := t.remote(t.priv.Publickey())
+
+	point := base.NewPoint(base.Height(33), 0)
+	fact := t.fact(point)
+
+	t.NoError(t.sign(k, fact))
+
+	t.Run("same fact", func() {
+		t.NoError(t.sign(k, fact))
+	})
+
+	t.Run("double sign", func() {
+		err := t.sign(k, t.fact(point))
+		t.Error(err)
+		t.ErrorIs(err, ErrDoubleSign)
+	})
+
+	t.Run("next round", func() {
+		t.NoError(t.sign(k, t.fact(point.NextRound())))
+	})
+
+	t.Run("not sign bytes of ballot", func() {
+		_, err := k.WithBallotFact(t.fact(point.NextHeight())).Sign(util.UUID().Bytes())
+		t.Error(err)
+		t.ErrorContains(err, "sign bytes does not match with ballot fact")
+	})
+
+	t.Run("sign bytes of different fact", func() {
+		other := t.fact(point.NextHeight())
+
+		b := util.ConcatBytesSlice(
+			t.networkID, t.node.Bytes(), other.Hash().Bytes(), localtime.New(localtime.Now()).Bytes())
+
+		_, err := k.WithBallotFact(t.fact(point.NextHeight())).Sign(b)
+		t.Error(err)
+		t.ErrorContains(err, "sign bytes does not match with ballot fact")
+	})
+
+	t.Run("different node", func() {
+		sf := isaac.NewINITBallotSignFact(t.fact(point.NextHeight()))
+		err := sf.NodeSign(k, t.networkID, base.RandomAddress(""))
+		t.Error(err)
+		t.ErrorContains(err, "sign bytes does not match with ballot fact")
+	})
+
+	t.Run("different network id", func() {
+		sf := isaac.NewINITBallotSignFact(t.fact(point.NextHeight()))
+		err := sf.NodeSign(k, base.RandomNetworkID(), t.node)
+		t.Error(err)
+		t.ErrorContains(err, "sign bytes does not match with ballot fact")
+	})
+}
+
+func (t *testSigner) TestStateFile() {
+	statefile := filepath.Join(t.root, "state")
+
+	args := t.args(t.priv)
+	args.StateFile = statefile
+
+	point := base.NewPoint(base.Height(33), 0)
+
+	s := t.newSigner(args)
+
+	k := t.remote(t.priv.Publickey())
+
+	t.NoError(t.sign(k, t.fact(point)))
+
+	t.NoError(s.Stop())
+
+	t.Run("restarted", func() {
+		s := t.newSigner(args)
+		defer s.Stop()
+
+		err := t.sign(k, t.fact(point))
+		t.ErrorIs(err, ErrDoubleSign)
+	})
+
+	t.Run("pruned", func() {
+		s := t.newSigner(args)
+		defer s.Stop()
+
+		next := base.NewPoint(point.Height()+base.Height(DefaultLocalSignerKeepHeights)+1, 0)
+
+		t.NoError(t.sign(k, t.fact(next)))
+
+		err := t.sign(k, t.fact(point))
+		t.Error(err)
+		t.ErrorIs(err, ErrDoubleSign)
+		t.ErrorContains(err, "watermark")
+	})
+
+	t.Run("watermark after restarted", func() {
+		s := t.newSigner(args)
+		defer s.Stop()
+
+		err := t.sign(k, t.fact(point))
+		t.Error(err)
+		t.ErrorIs(err, ErrDoubleSign)
+		t.ErrorContains(err, "watermark")
+	})
+
+	t.Run("no temp files", func() {
+		files, err := os.ReadDir(t.root)
+		t.NoError(err)
+
+		for i := range files {
+			t.NotContains(files[i].Name(), "temp-")
+		}
+	})
+}
+
+func (t *testSigner) TestDecodeDesign() {
+	enc := jsonenc.NewEncoder()
+
+	t.NoError(enc.Add(encoder.DecodeDetail{Hint: base.StringAddressHint, Instance: base.StringAddress{}}))
+	t.NoError(enc.Add(encoder.DecodeDetail{Hint: base.MPrivatekeyHint, Instance: &base.MPrivatekey{}}))
+	t.NoError(enc.Add(encoder.DecodeDetail{Hint: base.MPublickeyHint, Instance: &base.MPublickey{}}))
+
+	t.Run("ok", func() {
+		b := []byte(`
+address: no0sas
+signer:
+  publickey: ` + t.priv.Publickey().String() + `
+  address: unix:///tmp/signer.sock
+  timeout: 6s
+network_id: hehe 1 2 3 4
+network:
+  bind: 0.0.0.0:1234
+  publish: 1.2.3.4:4321
+storage:
+  base: /tmp/a/b/c
+`)
+
+		var a NodeDesign
+		t.NoError(a.DecodeYAML(b, enc))
+
+		t.NotNil(a.Signer)
+		t.True(t.priv.Publickey().Equal(a.Signer.Publickey))
+		t.Equal("unix:///tmp/signer.sock", a.Signer.Address)
+		t.Equal(time.Second*6, time.Duration(a.Signer.Timeout))
+
+		k, ok := a.Privatekey.(*RemoteSignerPrivatekey)
+		t.True(ok)
+		t.True(t.priv.Publickey().Equal(k.Publickey()))
+		t.Equal("unix:///tmp/signer.sock", k.String())
+	})
+
+	t.Run("both privatekey and signer", func() {
+		b := []byte(`
+address: no0sas
+privatekey: ` + t.priv.String() + `
+signer:
+  publickey: ` + t.priv.Publickey().String() + `
+  address: unix:///tmp/signer.sock
+network_id: hehe 1 2 3 4
+network:
+  bind: 0.0.0.0:1234
+  publish: 1.2.3.4:4321
+storage:
+  base: /tmp/a/b/c
+`)
+
+		var a NodeDesign
+		err := a.DecodeYAML(b, enc)
+		t.Error(err)
+		t.ErrorContains(err, "privatekey and signer both given")
+	})
+}
+
+func TestSigner(t *testing.T) {
+	suite.Run(t, new(testSigner))
+}
@@ -0,0 +1,321 @@
+package launch
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	isaacstates "github.com/ProtoconNet/mitum2/isaac/states"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/localtime"
+	"github.com/ProtoconNet/mitum2/util/ps"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+var (
+	PNameTimeline      = ps.Name("timeline")
+	TimelineContextKey = util.ContextKey("timeline")
+)
+
+var TimelineEventLogger EventLoggerName = "timeline"
+
+var DefaultTimelineSize = 1 << 13
+
+type TimelineRecordType string
+
+const (
+	TimelineBallot    TimelineRecordType = "ballot"
+	TimelineVoteproof TimelineRecordType = "voteproof"
+	TimelineState     TimelineRecordType = "state"
+	TimelineProposal  TimelineRecordType = "proposal"
+)
+
+// TimelineRecord is the one consensus event of the point.
+type TimelineRecord struct {
+	At      time.Time             `json:"at"`
+	Type    TimelineRecordType    `json:"type"`
+	Local   string                `json:"local"`
+	Node    string                `json:"node,omitempty"`
+	Stage   string                `json:"stage,omitempty"`
+	Result  string                `json:"result,omitempty"`
+	Error   string                `json:"error,omitempty"`
+	Height  base.Height           `json:"height"`
+	Round   base.Round            `json:"round"`
+	Elapsed util.ReadableDuration `json:"elapsed,omitempty"`
+}
+
+// Timeline records the consensus events by point; the records are kept in
+// the in-memory ring buffer and also stored thru the timeline event logger.
+type Timeline struct {
+	el        *zerolog.Logger
+	records   []TimelineRecord
+	local     string
+	lastState isaacstates.StateType
+	lastPoint base.StagePoint
+	index     int
+	isfull    bool
+	sync.RWMutex
+}
+
+func NewTimeline(local base.Address, size int, el *zerolog.Logger) *Timeline {
+	if size < 1 {
+		size = DefaultTimelineSize //revive:disable-line:modifies-parameter
+	}
+
+	return &Timeline{
+		el:        el,
+		records:   make([]TimelineRecord, size),
+		local:     local.String(),
+		lastState: isaacstates.StateEmpty,
+	}
+}
+
+func (t *Timeline) Record(r TimelineRecord) {
+	if r.At.IsZero() {
+		r.At = localtime.Now().UTC()
+	}
+
+	r.Local = t.local
+
+	t.Lock()
+	defer t.Unlock()
+
+	t.records[t.index] = r
+
+	t.index++
+
+	if t.index == len(t.records) {
+		t.index = 0
+		t.isfull = true
+	}
+
+	if t.el != nil {
+		t.el.Debug().Interface("timeline", r).Msg("timeline")
+	}
+}
+
+// Height returns the records of height in recorded order.
+func (t *Timeline) Height(height base.Height) []TimelineRecord {
+	t.RLock()
+	defer t.RUnlock()
+
+	var rs []TimelineRecord
+
+	f := func(i int) {
+		if r := t.records[i]; !r.At.IsZero() && r.Height == height {
+			rs = append(rs, r)
+		}
+	}
+
+	if t.isfull {
+		for i := t.index; i < len(t.records); i++ {
+			f(i)
+		}
+	}
+
+	for i := 0; i < t.index; i++ {
+		f(i)
+	}
+
+	return rs
+}
+
+func (t *Timeline) Ballot(sf base.BallotSignFact) {
+	bf, ok := sf.Fact().(base.BallotFact)
+	if !ok {
+		return
+	}
+
+	point := bf.Point()
+
+	t.Record(TimelineRecord{
+		Type:   TimelineBallot,
+		Node:   sf.Node().String(),
+		Height: point.Height(),
+		Round:  point.Round(),
+		Stage:  point.Stage().String(),
+	})
+}
+
+func (t *Timeline) Voteproof(vp base.Voteproof) {
+	point := vp.Point()
+
+	t.Lock()
+	if point.Compare(t.lastPoint) > 0 {
+		t.lastPoint = point
+	}
+	t.Unlock()
+
+	t.Record(TimelineRecord{
+		Type:   TimelineVoteproof,
+		Height: point.Height(),
+		Round:  point.Round(),
+		Stage:  point.Stage().String(),
+		Result: vp.Result().String(),
+	})
+}
+
+// StateSwitched records the new state with the point of last voteproof.
+func (t *Timeline) StateSwitched(next isaacstates.StateType) {
+	t.Lock()
+	prev, point := t.lastState, t.lastPoint
+	t.lastState = next
+	t.Unlock()
+
+	result := next.String()
+	if prev != isaacstates.StateEmpty {
+		result = fmt.Sprintf("%s -> %s", prev, next)
+	}
+
+	t.Record(TimelineRecord{
+		Type:   TimelineState,
+		Height: point.Height(),
+		Round:  point.Round(),
+		Result: result,
+	})
+}
+
+func (t *Timeline) ProposalSelected(
+	point base.Point, pr base.ProposalSignFact, elapsed time.Duration, err error,
+) {
+	r := TimelineRecord{
+		Type:    TimelineProposal,
+		Height:  point.Height(),
+		Round:   point.Round(),
+		Elapsed: util.ReadableDuration(elapsed),
+	}
+
+	if pr != nil {
+		r.Node = pr.ProposalFact().Proposer().String()
+		r.Result = pr.Fact().Hash().String()
+	}
+
+	if err != nil {
+		r.Error = err.Error()
+	}
+
+	t.Record(r)
+}
+
+// ProposalSelectFunc wraps isaac.ProposalSelectFunc to record the proposal
+// fetch timings.
+func (t *Timeline) ProposalSelectFunc(f isaac.ProposalSelectFunc) isaac.ProposalSelectFunc {
+	return func(
+		ctx context.Context, point base.Point, previousBlock util.Hash, wait time.Duration,
+	) (base.ProposalSignFact, error) {
+		started := time.Now()
+
+		pr, err := f(ctx, point, previousBlock, wait)
+
+		t.ProposalSelected(point, pr, time.Since(started), err)
+
+		return pr, err
+	}
+}
+
+func PTimeline(pctx context.Context) (context.Context, error) {
+	e := util.StringError("timeline")
+
+	var local base.LocalNode
+	var eventLogging *EventLogging
+
+	if err := util.LoadFromContextOK(pctx,
+		LocalContextKey, &local,
+		EventLoggingContextKey, &eventLogging,
+	); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	var el *zerolog.Logger
+
+	if i, found := eventLogging.Logger(TimelineEventLogger); found {
+		el = &i
+	}
+
+	return context.WithValue(pctx, TimelineContextKey, NewTimeline(local.Address(), 0, el)), nil
+}
+
+// LoadTimelineRecords reads the timeline records from the dump of timeline
+// event logger; each line is the json of event log or the output of network
+// client event command.
+func LoadTimelineRecords(r io.Reader) ([]TimelineRecord, error) {
+	var rs []TimelineRecord
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20) //nolint:gomnd //...
+
+	for sc.Scan() {
+		b := sc.Bytes()
+		if len(b) < 1 {
+			continue
+		}
+
+		var u struct {
+			Log *struct {
+				Timeline *TimelineRecord `json:"timeline"`
+			} `json:"log"`
+			Timeline *TimelineRecord `json:"timeline"`
+		}
+
+		if err := util.UnmarshalJSON(b, &u); err != nil {
+			return nil, errors.WithMessage(err, "timeline record")
+		}
+
+		switch {
+		case u.Timeline != nil:
+			rs = append(rs, *u.Timeline)
+		case u.Log != nil && u.Log.Timeline != nil:
+			rs = append(rs, *u.Log.Timeline)
+		}
+	}
+
+	return rs, errors.WithStack(sc.Err())
+}
+
+// RenderTimeline writes the records of height in time order.
+func RenderTimeline(w io.Writer, height base.Height, records []TimelineRecord) error {
+	var rs []TimelineRecord
+
+	for i := range records {
+		if records[i].Height == height {
+			rs = append(rs, records[i])
+		}
+	}
+
+	if len(rs) < 1 {
+		return util.ErrNotFound.Errorf("no records for height, %d", height)
+	}
+
+	sort.SliceStable(rs, func(i, j int) bool {
+		return rs[i].At.Before(rs[j].At)
+	})
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0) //nolint:gomnd //...
+
+	_, _ = fmt.Fprintf(tw, "# height=%d\n", height)
+	_, _ = fmt.Fprintln(tw, "ELAPSED\tLOCAL\tROUND\tSTAGE\tTYPE\tNODE\tRESULT\tDURATION\tERROR")
+
+	first := rs[0].At
+
+	for i := range rs {
+		r := rs[i]
+
+		var d string
+		if r.Elapsed > 0 {
+			d = time.Duration(r.Elapsed).String()
+		}
+
+		_, _ = fmt.Fprintf(tw, "+%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.At.Sub(first), r.Local, r.Round, r.Stage, r.Type, r.Node, r.Result, d, r.Error)
+	}
+
+	return errors.WithStack(tw.Flush())
+}
@@ -0,0 +1,108 @@
+package launch
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/base"
+	isaacstates "github.com/ProtoconNet/mitum2/isaac/states"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+)
+
+type testTimeline struct {
+	suite.Suite
+}
+
+func (t *testTimeline) TestRing() {
+	tl := NewTimeline(base.RandomAddress(""), 3, nil)
+
+	for i := range []int{0, 1, 2, 3, 4} {
+		tl.Record(TimelineRecord{Type: TimelineVoteproof, Height: 33, Round: base.Round(i)})
+	}
+
+	tl.Record(TimelineRecord{Type: TimelineVoteproof, Height: 34})
+
+	rs := tl.Height(33)
+	t.Equal(2, len(rs))
+	t.Equal(base.Round(3), rs[0].Round)
+	t.Equal(base.Round(4), rs[1].Round)
+
+	t.Equal(1, len(tl.Height(34)))
+	t.Empty(tl.Height(35))
+}
+
+func (t *testTimeline) TestStateSwitched() {
+	tl := NewTimeline(base.RandomAddress(""), 0, nil)
+
+	tl.Record(TimelineRecord{Type: TimelineVoteproof, Height: 33})
+	tl.Lock()
+	tl.lastPoint = base.NewStagePoint(base.NewPoint(33, 1), base.StageACCEPT)
+	tl.Unlock()
+
+	tl.StateSwitched(isaacstates.StateConsensus)
+
+	rs := tl.Height(33)
+	t.Equal(2, len(rs))
+	t.Equal(TimelineState, rs[1].Type)
+	t.Equal(base.Round(1), rs[1].Round)
+	t.Equal("CONSENSUS", rs[1].Result)
+
+	tl.StateSwitched(isaacstates.StateSyncing)
+
+	rs = tl.Height(33)
+	t.Equal("CONSENSUS -> SYNCING", rs[2].Result)
+}
+
+func (t *testTimeline) TestLoadAndRender() {
+	buf := bytes.NewBuffer(nil)
+	l := zerolog.New(buf)
+
+	now := time.Now()
+
+	a := NewTimeline(base.RandomAddress("a"), 0, &l)
+	a.Record(TimelineRecord{At: now, Type: TimelineBallot, Height: 33, Stage: base.StageINIT.String(), Node: "n0"})
+	a.Record(TimelineRecord{At: now.Add(time.Millisecond * 2), Type: TimelineVoteproof, Height: 33, Result: "MAJORITY"})
+	a.Record(TimelineRecord{At: now.Add(time.Millisecond), Type: TimelineBallot, Height: 34})
+
+	// NOTE wrapped by network client event command
+	b, err := util.MarshalJSON(map[string]interface{}{
+		"log": map[string]interface{}{
+			"timeline": TimelineRecord{
+				At: now.Add(time.Millisecond), Type: TimelineProposal, Height: 33, Local: "b",
+				Elapsed: util.ReadableDuration(time.Millisecond * 3),
+			},
+		},
+	})
+	t.NoError(err)
+
+	buf.Write(b)
+	buf.WriteString("\n")
+
+	rs, err := LoadTimelineRecords(buf)
+	t.NoError(err)
+	t.Equal(4, len(rs))
+
+	out := bytes.NewBuffer(nil)
+	t.NoError(RenderTimeline(out, 33, rs))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	t.Equal(5, len(lines), out.String())
+	t.Contains(lines[2], string(TimelineBallot))
+	t.Contains(lines[3], string(TimelineProposal))
+	t.Contains(lines[3], "3ms")
+	t.Contains(lines[4], "MAJORITY")
+
+	t.Run("not found", func() {
+		err := RenderTimeline(out, 35, rs)
+		t.Error(err)
+		t.ErrorIs(err, util.ErrNotFound)
+	})
+}
+
+func TestTimeline(t *testing.T) {
+	suite.Run(t, new(testTimeline))
+}
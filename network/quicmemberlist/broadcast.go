@@ -56,8 +56,9 @@ func (b *Broadcast) MarshalZerologObject(e *zerolog.Event) {
 var ConnInfoBroadcastMessageHint = hint.MustNewHint("conninfo-broadcast-message-v0.0.1")
 
 type ConnInfoBroadcastMessage struct {
-	id string
-	ci quicstream.ConnInfo
+	id    string
+	ci    quicstream.ConnInfo
+	relay quicstream.ConnInfo
 	hint.BaseHinter
 }
 
@@ -84,6 +85,12 @@ func (m ConnInfoBroadcastMessage) IsValid([]byte) error {
 		return e.Wrap(err)
 	}
 
+	if m.relay.UDPAddr() != nil {
+		if err := m.relay.IsValid(nil); err != nil {
+			return e.WithMessage(err, "relay")
+		}
+	}
+
 	return nil
 }
 
@@ -95,17 +102,36 @@ func (m ConnInfoBroadcastMessage) ConnInfo() quicstream.ConnInfo {
 	return m.ci
 }
 
+// Relay returns the relay of ConnInfo; if ConnInfo can not be reached directly,
+// the stream to ConnInfo should be forwarded by relay.
+func (m ConnInfoBroadcastMessage) Relay() (quicstream.ConnInfo, bool) {
+	return m.relay, m.relay.UDPAddr() != nil
+}
+
+func (m ConnInfoBroadcastMessage) WithRelay(relay quicstream.ConnInfo) ConnInfoBroadcastMessage {
+	m.relay = relay
+
+	return m
+}
+
 type connInfoBroadcastMessageJSONMarshaler struct {
-	ID string              `json:"id"`
-	CI quicstream.ConnInfo `json:"conn_info"` //nolint:tagliatelle //...
+	ID    string               `json:"id"`
+	CI    quicstream.ConnInfo  `json:"conn_info"` //nolint:tagliatelle //...
+	Relay *quicstream.ConnInfo `json:"relay,omitempty"`
 	hint.BaseHinter
 }
 
 func (m ConnInfoBroadcastMessage) MarshalJSON() ([]byte, error) {
+	var relay *quicstream.ConnInfo
+	if m.relay.UDPAddr() != nil {
+		relay = &m.relay
+	}
+
 	return util.MarshalJSON(connInfoBroadcastMessageJSONMarshaler{
 		BaseHinter: m.BaseHinter,
 		ID:         m.id,
 		CI:         m.ci,
+		Relay:      relay,
 	})
 }
 
@@ -119,6 +145,10 @@ func (m *ConnInfoBroadcastMessage) UnmarshalJSON(b []byte) error {
 	m.id = u.ID
 	m.ci = u.CI
 
+	if u.Relay != nil {
+		m.relay = *u.Relay
+	}
+
 	return nil
 }
 
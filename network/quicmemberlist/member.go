@@ -26,6 +26,7 @@ type Member interface {
 	Address() base.Address
 	Publickey() base.Publickey
 	Publish() NamedConnInfo
+	Relay() (quicstream.ConnInfo, bool)
 	JoinedAt() time.Time
 	MetaBytes() []byte
 	HashBytes() []byte
@@ -96,6 +97,12 @@ func newMemberWithMeta(name string, addr *net.UDPAddr, meta memberMeta) (b BaseM
 		publish = NewNamedConnInfoFromConnInfo(i)
 	}
 
+	if len(meta.relay) > 0 {
+		if _, err := quicstream.NewConnInfoFromFullString(meta.relay); err != nil {
+			return b, errors.WithMessage(err, "new Member; relay")
+		}
+	}
+
 	n := BaseMember{
 		BaseHinter: hint.NewBaseHinter(MemberHint),
 		name:       name,
@@ -109,6 +116,23 @@ func newMemberWithMeta(name string, addr *net.UDPAddr, meta memberMeta) (b BaseM
 	return n, nil
 }
 
+// WithRelay returns new BaseMember with relay. Relay is the ConnInfo of
+// the other node, which forwards the streams to this member; it is used when
+// member can not be reached directly, like behind NAT.
+func (n BaseMember) WithRelay(relay quicstream.ConnInfo) (BaseMember, error) {
+	meta := n.meta
+	meta.relay = relay.String()
+
+	i, err := newMemberWithMeta(n.name, n.addr, meta)
+	if err != nil {
+		return n, err
+	}
+
+	i.joinedAt = n.joinedAt
+
+	return i, nil
+}
+
 func (n BaseMember) IsValid([]byte) error {
 	e := util.ErrInvalid.Errorf("invalid BaseNode")
 
@@ -189,6 +213,16 @@ func (n BaseMember) Publish() NamedConnInfo {
 	return n.publish
 }
 
+func (n BaseMember) Relay() (quicstream.ConnInfo, bool) {
+	if len(n.meta.relay) < 1 {
+		return quicstream.ConnInfo{}, false
+	}
+
+	ci, err := quicstream.NewConnInfoFromFullString(n.meta.relay)
+
+	return ci, err == nil
+}
+
 func (n BaseMember) MetaBytes() []byte {
 	return n.metab
 }
@@ -243,6 +277,7 @@ type memberMeta struct {
 	address     base.Address
 	publickey   base.Publickey
 	publish     string
+	relay       string
 	tlsinsecure bool
 }
 
@@ -264,6 +299,15 @@ func (n memberMeta) IsValid([]byte) error {
 		util.DummyIsValider(func([]byte) error {
 			return network.IsValidAddr(n.publish)
 		}),
+		util.DummyIsValider(func([]byte) error {
+			if len(n.relay) < 1 {
+				return nil
+			}
+
+			addr, _ := network.ParseTLSInsecure(n.relay)
+
+			return network.IsValidAddr(addr)
+		}),
 	); err != nil {
 		return e.Wrap(err)
 	}
@@ -275,6 +319,7 @@ type memberMetaJSONMmarshaler struct {
 	Address     base.Address   `json:"address"`
 	Publickey   base.Publickey `json:"publickey"`
 	Publish     string         `json:"publish"`
+	Relay       string         `json:"relay,omitempty"`
 	TLSInsecure bool           `json:"tls_insecure"`
 }
 
@@ -283,6 +328,7 @@ func (n memberMeta) MarshalJSON() ([]byte, error) {
 		Address:     n.address,
 		Publickey:   n.publickey,
 		Publish:     n.publish,
+		Relay:       n.relay,
 		TLSInsecure: n.tlsinsecure,
 	})
 }
@@ -291,6 +337,7 @@ type memberMetaJSONUnmarshaler struct {
 	Address     string `json:"address"`
 	Publickey   string `json:"publickey"`
 	Publish     string `json:"publish"`
+	Relay       string `json:"relay,omitempty"`
 	TLSInsecure bool   `json:"tls_insecure"`
 }
 
@@ -317,6 +364,7 @@ func (n *memberMeta) DecodeJSON(b []byte, jsonencoder encoder.Encoder) error {
 	}
 
 	n.publish = u.Publish
+	n.relay = u.Relay
 	n.tlsinsecure = u.TLSInsecure
 
 	return nil
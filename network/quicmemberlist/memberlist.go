@@ -41,6 +41,9 @@ type MemberlistArgs struct {
 	FetchCallbackBroadcastMessageFunc func(context.Context, ConnInfoBroadcastMessage) ([]byte, encoder.Encoder, error)
 	PongEnsureBroadcastMessageFunc    func(context.Context, ConnInfoBroadcastMessage) error
 	WhenLeftFunc                      func(Member)
+	// WhenRelayedFunc is called when the relay of remote ConnInfo is found;
+	// the stream to ConnInfo should be forwarded by relay.
+	WhenRelayedFunc func(ci, relay quicstream.ConnInfo)
 	// Members, which has same node address will be allowed to join up to
 	// ExtraSameMemberLimit + 1. If ExtraSameMemberLimit is 0, only 1 member is
 	// allowed to join.
@@ -62,6 +65,7 @@ func NewMemberlistArgs(jsonencoder encoder.Encoder, config *memberlist.Config) *
 		Config:                               config,
 		ExtraSameMemberLimit:                 func() uint64 { return 1 },
 		WhenLeftFunc:                         func(Member) {},
+		WhenRelayedFunc:                      func(quicstream.ConnInfo, quicstream.ConnInfo) {},
 		NotAllowedMemberExpire:               time.Second * 6, //nolint:gomnd //...
 		ChallengeExpire:                      defaultNodeChallengeExpire,
 		CallbackBroadcastMessageExpire:       time.Second * 30, //nolint:gomnd //...
@@ -325,8 +329,7 @@ func (srv *Memberlist) CallbackBroadcast(b []byte, id string, notifych chan stru
 	buf := bytes.NewBuffer(callbackBroadcastMessageHeaderPrefix)
 	defer buf.Reset()
 
-	switch err := srv.args.Encoder.StreamEncoder(buf).Encode(
-		NewConnInfoBroadcastMessage(id, srv.local.ConnInfo())); {
+	switch err := srv.args.Encoder.StreamEncoder(buf).Encode(srv.connInfoBroadcastMessage(id)); {
 	case err != nil:
 		return err
 	default:
@@ -402,7 +405,7 @@ func (srv *Memberlist) EnsureBroadcast(
 
 	buf := bytes.NewBuffer(ensureBroadcastMessageHeaderPrefix)
 
-	switch i, err := srv.args.Encoder.Marshal(NewConnInfoBroadcastMessage(id, srv.local.ConnInfo())); {
+	switch i, err := srv.args.Encoder.Marshal(srv.connInfoBroadcastMessage(id)); {
 	case err != nil:
 		return err
 	default:
@@ -742,6 +745,38 @@ func (srv *Memberlist) SetNotifyMsg(f func([]byte, encoder.Encoder)) *Memberlist
 	return srv
 }
 
+func (srv *Memberlist) connInfoBroadcastMessage(id string) ConnInfoBroadcastMessage {
+	m := NewConnInfoBroadcastMessage(id, srv.local.ConnInfo())
+
+	if relay, found := srv.local.Relay(); found {
+		m = m.WithRelay(relay)
+	}
+
+	return m
+}
+
+// whenRelayed accepts the relay of broadcast message only when it is same with
+// the relay of joined member; the relay of member is checked by the node
+// challenge when joined.
+func (srv *Memberlist) whenRelayed(m ConnInfoBroadcastMessage) {
+	relay, found := m.Relay()
+	if !found {
+		return
+	}
+
+	member, _ := srv.members.Get(m.ConnInfo().UDPAddr())
+	if member == nil {
+		return
+	}
+
+	switch i, found := member.Relay(); {
+	case !found, !network.DeepEqualConnInfo(i, relay):
+		return
+	default:
+		srv.args.WhenRelayedFunc(m.ConnInfo(), relay)
+	}
+}
+
 func (srv *Memberlist) notifyMsgFunc(b []byte) {
 	srv.usermsgsLock.Lock()
 	defer srv.usermsgsLock.Unlock()
@@ -778,6 +813,13 @@ func (srv *Memberlist) notifyMsgCallbackBroadcastMessage(b []byte) ([]byte, enco
 		m = j
 	}
 
+	srv.whenRelayed(m)
+
+	// NOTE the message in local cache was already broadcasted by local.
+	if srv.cbcache.Exists(m.ID()) {
+		return nil, nil, errors.Errorf("already known message")
+	}
+
 	// NOTE fetch callback message
 	ctx, cancel := context.WithTimeout(context.Background(), srv.args.FetchCallbackBroadcastMessageTimeout)
 	defer cancel()
@@ -819,6 +861,8 @@ func (srv *Memberlist) notifyMsgEnsureBroadcastMessage(b []byte) ([]byte, error)
 		left = j
 	}
 
+	srv.whenRelayed(m)
+
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), srv.args.PongEnsureBroadcastMessageTimeout)
 		defer cancel()
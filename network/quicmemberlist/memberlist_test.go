@@ -1146,7 +1146,7 @@ func (t *testMemberlist) TestCallbackBroadcast() {
 	))
 	t.NoError(err)
 
-	dialf := quicstreamheader.NewDialFunc(cp.Dial, t.encs, t.enc)
+	dialf := quicstreamheader.NewDialFunc(cp.Dial, t.encs, t.enc, nil)
 
 	notfoundid := util.UUID().String()
 
@@ -1257,7 +1257,7 @@ func (t *testMemberlist) TestEnsureBroadcast() {
 	))
 	t.NoError(err)
 
-	dialf := quicstreamheader.NewDialFunc(cp.Dial, t.encs, t.enc)
+	dialf := quicstreamheader.NewDialFunc(cp.Dial, t.encs, t.enc, nil)
 
 	var skipnodes []base.Address
 	var skipnodeslock sync.RWMutex
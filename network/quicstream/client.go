@@ -7,6 +7,7 @@ import (
 	"net"
 	"time"
 
+	"github.com/ProtoconNet/mitum2/network"
 	"github.com/ProtoconNet/mitum2/util"
 	"github.com/pkg/errors"
 	"github.com/quic-go/quic-go"
@@ -49,9 +50,12 @@ func NewConnInfoDialFunc(
 }
 
 type ConnectionPool struct {
-	conns util.LockedMap[string, Streamer]
-	dialf ConnInfoDialFunc
-	Stop  func()
+	conns      util.LockedMap[string, Streamer]
+	relays     util.LockedMap[string, ConnInfo]
+	local      *util.Locked[*Relay]
+	dialf      ConnInfoDialFunc
+	instrument Instrument
+	Stop       func()
 }
 
 func NewConnectionPool(
@@ -63,12 +67,20 @@ func NewConnectionPool(
 		return nil, err
 	}
 
+	relays, err := util.NewLockedMap[string, ConnInfo](size, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	cctx, ccancel := context.WithCancel(context.Background())
 
 	c := &ConnectionPool{
-		conns: conns,
-		dialf: dialf,
-		Stop:  ccancel,
+		conns:      conns,
+		relays:     relays,
+		local:      util.EmptyLocked[*Relay](),
+		dialf:      dialf,
+		instrument: nilInstrument{},
+		Stop:       ccancel,
 	}
 
 	go func() {
@@ -88,7 +100,83 @@ func NewConnectionPool(
 	return c, nil
 }
 
+// Dial returns the pooled Streamer. If ci is registered to the local Relay,
+// Streamer opens stream thru the registered connection. If ci has the remote
+// relay, stream is forwarded by the remote relay.
 func (c *ConnectionPool) Dial(ctx context.Context, ci ConnInfo) (Streamer, error) {
+	if relay, _ := c.local.Value(); relay != nil {
+		if i, found := relay.Streamer(ci); found {
+			return i, nil
+		}
+	}
+
+	if ci.UDPAddr() != nil {
+		if relayci, found := c.relays.Value(ci.Addr().String()); found {
+			switch i, err := c.dial(ctx, relayci); {
+			case err != nil:
+				return nil, err
+			default:
+				return newRelayForwardStreamer(i, ci), nil
+			}
+		}
+	}
+
+	return c.dial(ctx, ci)
+}
+
+// SetLocalRelay sets the local Relay; the nodes registered to the local Relay
+// can be dialed thru the registered connection.
+func (c *ConnectionPool) SetLocalRelay(relay *Relay) {
+	_ = c.local.SetValue(relay)
+}
+
+// SetRelay sets the remote relay of ci; the streams to ci will be forwarded by
+// the relay.
+func (c *ConnectionPool) SetRelay(ci, relay ConnInfo) bool {
+	if network.EqualConnInfo(ci, relay) {
+		return false
+	}
+
+	var updated bool
+
+	_, _, _ = c.relays.Set(ci.Addr().String(), func(old ConnInfo, found bool) (ConnInfo, error) {
+		if found && network.DeepEqualConnInfo(old, relay) {
+			return old, util.ErrLockedSetIgnore
+		}
+
+		updated = true
+
+		return relay, nil
+	})
+
+	return updated
+}
+
+// DialRelay returns the Streamer, which is forwarded by the relay; unlike
+// SetRelay, the relay is not kept.
+func (c *ConnectionPool) DialRelay(ctx context.Context, ci, relay ConnInfo) (Streamer, error) {
+	i, err := c.dial(ctx, relay)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRelayForwardStreamer(i, ci), nil
+}
+
+func (c *ConnectionPool) RemoveRelay(ci ConnInfo) bool {
+	return c.relays.RemoveValue(ci.Addr().String())
+}
+
+func (c *ConnectionPool) Relay(ci ConnInfo) (ConnInfo, bool) {
+	return c.relays.Value(ci.Addr().String())
+}
+
+// SetInstrument sets Instrument; it should be called before dialing.
+func (c *ConnectionPool) SetInstrument(i Instrument) {
+	c.instrument = i
+}
+
+func (c *ConnectionPool) dial(ctx context.Context, ci ConnInfo) (Streamer, error) {
 	var conn Streamer
 
 	if _, _, err := c.conns.Set(ci.Addr().String(), func(old Streamer, _ bool) (Streamer, error) {
@@ -98,12 +186,24 @@ func (c *ConnectionPool) Dial(ctx context.Context, ci ConnInfo) (Streamer, error
 			return nil, util.ErrLockedSetIgnore
 		}
 
+		remote := ci.Addr().String()
+
 		switch i, err := c.dialf(ctx, ci); {
 		case err == nil:
 			conn = i
 
+			c.instrument.ConnectionOpened(remote, false)
+
+			go func() {
+				<-i.Context().Done()
+
+				c.instrument.ConnectionClosed(remote, false)
+			}()
+
 			return i, nil
 		default:
+			c.instrument.ConnectionFailed(remote, false, err)
+
 			return i, err
 		}
 	}); err != nil {
@@ -115,6 +215,8 @@ func (c *ConnectionPool) Dial(ctx context.Context, ci ConnInfo) (Streamer, error
 		onerror: func() {
 			c.onerror(ci)
 		},
+		instrument: c.instrument,
+		remote:     ci.Addr().String(),
 	}, nil
 }
 
@@ -196,11 +298,21 @@ func (c *ConnectionPool) clean() {
 
 type connectionPoolStreamer struct {
 	Streamer
-	onerror func()
+	instrument Instrument
+	onerror    func()
+	remote     string
 }
 
 func (s connectionPoolStreamer) Stream(ctx context.Context, f StreamFunc) error {
-	err := s.Streamer.Stream(ctx, f)
+	err := s.Streamer.Stream(ctx, func(ctx context.Context, r io.Reader, w io.WriteCloser) error {
+		ictx, ir, iw, ended := instrumentStream(ctx, s.instrument, s.remote, false, r, w)
+
+		err := f(ictx, ir, iw)
+
+		ended(err)
+
+		return err
+	})
 	if IsSeriousError(err) {
 		s.onerror()
 	}
@@ -208,6 +320,23 @@ func (s connectionPoolStreamer) Stream(ctx context.Context, f StreamFunc) error
 	return err
 }
 
+func (s connectionPoolStreamer) OpenStream(ctx context.Context) (io.Reader, io.WriteCloser, func() error, error) {
+	r, w, closef, err := s.Streamer.OpenStream(ctx)
+	if err != nil {
+		return r, w, closef, err
+	}
+
+	_, ir, iw, ended := instrumentStream(ctx, s.instrument, s.remote, false, r, w)
+
+	return ir, iw, func() error {
+		err := closef()
+
+		ended(err)
+
+		return err
+	}, nil
+}
+
 func IsSeriousError(err error) bool {
 	switch {
 	case err == nil:
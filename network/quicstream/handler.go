@@ -81,6 +81,8 @@ func (h *PrefixHandler) Handler(
 		return h.errorHandler(ctx, addr, r, w, err)
 	}
 
+	setStreamHandlerName(ctx, name)
+
 	l := ConnectionLoggerFromContext(ctx, h.Log()).With().
 		Stringer("handler", name).
 		Stringer("prefix", prefix).
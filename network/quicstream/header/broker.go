@@ -3,25 +3,36 @@ package quicstreamheader
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"io"
 	"sync"
 
 	"github.com/ProtoconNet/mitum2/network/quicstream"
 	"github.com/ProtoconNet/mitum2/util"
 	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/ProtoconNet/mitum2/util/tracing"
 	"github.com/pkg/errors"
 )
 
+// spanContextHeaderKey is the key of span context in the marshaled header.
+const spanContextHeaderKey = "_span_context"
+
 type (
 	BrokerFunc func(context.Context, *ClientBroker) error
 	StreamFunc func(context.Context, BrokerFunc) error
 	DialFunc   func(context.Context, quicstream.ConnInfo) (_ StreamFunc, closeConnection func() error, _ error)
 )
 
+// NewDialFunc returns the DialFunc; headExtensionf decides whether the client
+// broker uses the extended head, which is checked whenever new stream is
+// opened. The body is compressed only after the extended head and the old nodes
+// can not read the extended head, so it should be enabled after all the nodes
+// are upgraded. With nil headExtensionf, the extended head is not used.
 func NewDialFunc(
 	dialf quicstream.ConnInfoDialFunc,
 	encs *encoder.Encoders,
 	enc encoder.Encoder,
+	headExtensionf func() bool,
 ) DialFunc {
 	return func(ctx context.Context, ci quicstream.ConnInfo) (StreamFunc, func() error, error) {
 		streamer, err := dialf(ctx, ci)
@@ -32,6 +43,10 @@ func NewDialFunc(
 		return func(ctx context.Context, f BrokerFunc) error {
 			return streamer.Stream(ctx, func(ctx context.Context, r io.Reader, w io.WriteCloser) error {
 				broker := NewClientBroker(encs, enc, r, w)
+				if headExtensionf != nil {
+					broker.SetHeadExtension(headExtensionf())
+				}
+
 				defer func() {
 					_ = broker.Close()
 				}()
@@ -75,6 +90,16 @@ func NewClientBroker(
 	return &ClientBroker{baseBroker: newBaseBroker(encs, enc, r, w)}
 }
 
+// SetHeadExtension enables or disables the extended head; it should be called
+// before writing request head. The handler follows the head of request, but the
+// old handler, which does not know the extended head, can not read it.
+func (broker *ClientBroker) SetHeadExtension(enabled bool) {
+	broker.Lock()
+	defer broker.Unlock()
+
+	broker.extended = enabled
+}
+
 func (broker *ClientBroker) WriteRequestHead(ctx context.Context, header RequestHeader) error {
 	if header.Handler() == quicstream.ZeroPrefix {
 		return errors.Errorf("write request; empty prefix")
@@ -98,7 +123,7 @@ func (broker *ClientBroker) ReadResponseHead(ctx context.Context) (enc encoder.E
 		switch dataType, err = broker.readDataType(ctx); {
 		case err != nil:
 			return err
-		case dataType != ResponseHeaderDataType:
+		case !dataType.isResponseHead():
 			return errors.Errorf("expected response header data type, but %v", dataType)
 		}
 
@@ -157,8 +182,8 @@ func (broker *HandlerBroker) ReadRequestHead(ctx context.Context) (header Reques
 		switch t, err := broker.readDataType(ctx); {
 		case err != nil:
 			return err
-		case t != RequestHeaderDataType:
-			return errors.Errorf("expected request header data type, but %v", dataType)
+		case !t.isRequestHead():
+			return errors.Errorf("expected request header data type, but %v", t)
 		default:
 			dataType = t
 		}
@@ -171,6 +196,8 @@ func (broker *HandlerBroker) ReadRequestHead(ctx context.Context) (header Reques
 			defer broker.Unlock()
 
 			broker.Encoder = i
+			// NOTE the response follows the head of request
+			broker.extended = dataType.isExtended()
 			header = h.(RequestHeader) //nolint:forcetypeassert //...
 		}
 
@@ -183,11 +210,16 @@ func (broker *HandlerBroker) ReadRequestHead(ctx context.Context) (header Reques
 }
 
 type baseBroker struct {
-	Encoders *encoder.Encoders
-	Encoder  encoder.Encoder
-	Reader   io.Reader
-	Writer   io.Writer
-	closef   func() error
+	Encoders          *encoder.Encoders
+	Encoder           encoder.Encoder
+	Reader            io.Reader
+	Writer            io.Writer
+	closef            func() error
+	peerCompressions  *util.Locked[[]Compression]
+	remoteSpanContext *util.Locked[tracing.SpanContext]
+	compressions      []Compression
+	threshold         uint64
+	extended          bool
 	sync.Mutex
 }
 
@@ -219,11 +251,15 @@ func newBaseBroker(
 	}
 
 	return &baseBroker{
-		Encoders: encs,
-		Encoder:  enc,
-		Reader:   r,
-		Writer:   w,
-		closef:   closef,
+		Encoders:          encs,
+		Encoder:           enc,
+		Reader:            r,
+		Writer:            w,
+		closef:            closef,
+		peerCompressions:  util.EmptyLocked[[]Compression](),
+		remoteSpanContext: util.EmptyLocked[tracing.SpanContext](),
+		compressions:      DefaultCompressions,
+		threshold:         DefaultCompressionThreshold,
 	}
 }
 
@@ -231,6 +267,36 @@ func (broker *baseBroker) Close() error {
 	return broker.closef()
 }
 
+// RemoteSpanContext returns the tracing.SpanContext, which the other side sent
+// with the head.
+func (broker *baseBroker) RemoteSpanContext() tracing.SpanContext {
+	sc, _ := broker.remoteSpanContext.Value()
+
+	return sc
+}
+
+// SetCompressions sets the preferred compressions; the body, which is longer
+// than threshold, will be compressed by the first compression, which the other
+// side accepts. The accepted compressions are exchanged with the head, so
+// SetCompressions should be called before writing head. Without compressions,
+// body will not be compressed and the other side will not compress body.
+func (broker *baseBroker) SetCompressions(threshold uint64, compressions ...Compression) error {
+	for i := range compressions {
+		if compressions[i] == NoCompression {
+			return errors.Errorf("no compression in compressions")
+		}
+
+		if err := compressions[i].IsValid(nil); err != nil {
+			return err
+		}
+	}
+
+	broker.threshold = threshold
+	broker.compressions = compressions
+
+	return nil
+}
+
 func (broker *baseBroker) WriteBody(
 	ctx context.Context,
 	bodyType BodyType,
@@ -264,7 +330,7 @@ func (broker *baseBroker) ReadBody(ctx context.Context) (
 		case err != nil:
 			return err
 		case dataType == BodyDataType:
-		case dataType == ResponseHeaderDataType:
+		case dataType.isResponseHead():
 			switch i, header, err := broker.readHead(ctx, dataType); {
 			case err != nil:
 				return err
@@ -326,10 +392,15 @@ func (broker *baseBroker) writeHead(ctx context.Context, dataType DataType, head
 	case err != nil:
 		return err
 	default:
-		headerb = b
+		headerb = headerWithSpanContext(b, tracing.SpanContextFromContext(ctx))
+	}
+
+	dt := dataType
+	if broker.extended {
+		dt = dataType.extended()
 	}
 
-	if _, err := broker.write(ctx, dataType[:]); err != nil {
+	if _, err := broker.write(ctx, dt[:]); err != nil {
 		return errors.WithMessage(err, "data type")
 	}
 
@@ -341,6 +412,12 @@ func (broker *baseBroker) writeHead(ctx context.Context, dataType DataType, head
 		return errors.WithMessage(err, "header bytes")
 	}
 
+	if dt.isExtended() {
+		if err := broker.writeHeadExtension(ctx); err != nil {
+			return errors.WithMessage(err, "head extension")
+		}
+	}
+
 	return nil
 }
 
@@ -373,19 +450,36 @@ func (broker *baseBroker) writeBody(
 		return errors.WithMessage(err, "body type")
 	}
 
-	switch bodyType {
-	case EmptyBodyType:
-	case FixedLengthBodyType:
+	switch {
+	case bodyType == EmptyBodyType:
+		return nil
+	case !broker.isExtended():
+		return broker.writeRawBody(ctx, bodyType, bodyLength, bd)
+	case bodyType == FixedLengthBodyType:
+		return broker.writeFixedLengthBody(ctx, bodyLength, bd)
+	case bodyType == StreamBodyType:
+		return broker.writeStreamBody(ctx, bd)
+	default:
+		return errors.Errorf("unknown body type, %d", bodyType)
+	}
+}
+
+// writeRawBody writes body without compression; the body after the head
+// without extension is not compressed.
+func (broker *baseBroker) writeRawBody(
+	ctx context.Context,
+	bodyType BodyType,
+	bodyLength uint64,
+	body io.Reader,
+) error {
+	if bodyType == FixedLengthBodyType {
 		if err := broker.writeLength(ctx, bodyLength); err != nil {
 			return errors.WithMessage(err, "body length")
 		}
-	case StreamBodyType:
-	default:
-		return errors.Errorf("unknown body type, %d", bodyType)
 	}
 
-	if bd != nil {
-		if _, err := broker.writeReader(ctx, bd); err != nil {
+	if body != nil {
+		if _, err := broker.writeReader(ctx, body); err != nil {
 			return errors.WithMessage(err, "write body reader")
 		}
 	}
@@ -393,6 +487,157 @@ func (broker *baseBroker) writeBody(
 	return nil
 }
 
+// writeFixedLengthBody writes the compression, original body length and body;
+// the compressed body is written in frames without buffering whole body.
+func (broker *baseBroker) writeFixedLengthBody(ctx context.Context, bodyLength uint64, body io.Reader) error {
+	c := NoCompression
+
+	bd := body
+
+	if body != nil && bodyLength > 0 && bodyLength >= broker.threshold {
+		c = broker.compression()
+	}
+
+	if c != NoCompression {
+		// NOTE already compressed body is not compressed
+		peeked := make([]byte, len(zstdMagic))
+
+		switch n, err := io.ReadFull(body, peeked); {
+		case err == nil:
+			if isCompressed(peeked) {
+				c = NoCompression
+			}
+		case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+			peeked = peeked[:n]
+			c = NoCompression
+		default:
+			return errors.WithMessage(err, "read body")
+		}
+
+		bd = io.MultiReader(bytes.NewReader(peeked), body)
+	}
+
+	if _, err := broker.write(ctx, c[:]); err != nil {
+		return errors.WithMessage(err, "compression")
+	}
+
+	if err := broker.writeLength(ctx, bodyLength); err != nil {
+		return errors.WithMessage(err, "body length")
+	}
+
+	switch {
+	case bd == nil:
+		return nil
+	case c == NoCompression:
+		if _, err := broker.writeReader(ctx, bd); err != nil {
+			return errors.WithMessage(err, "write body reader")
+		}
+
+		return nil
+	}
+
+	fw := newFrameWriter(broker.Writer)
+
+	cw, err := c.writer(fw)
+	if err != nil {
+		return errors.WithMessage(err, "compress body")
+	}
+
+	if _, err := io.Copy(cw, io.LimitReader(bd, int64(bodyLength))); err != nil { //nolint:gosec //...
+		_ = cw.Close()
+
+		return errors.Wrap(err, "write compressed body")
+	}
+
+	if err := cw.Close(); err != nil {
+		return errors.Wrap(err, "close compressed body")
+	}
+
+	return errors.WithMessage(fw.Close(), "close compressed body")
+}
+
+func (broker *baseBroker) writeStreamBody(ctx context.Context, body io.Reader) error {
+	c := broker.compression()
+
+	// NOTE peek the head of body to check the threshold; the short or already
+	// compressed body is not compressed.
+	var peeked []byte
+
+	if c != NoCompression {
+		size := broker.threshold
+		if size < uint64(len(zstdMagic)) {
+			size = uint64(len(zstdMagic))
+		}
+
+		peeked = make([]byte, size)
+
+		switch n, err := io.ReadFull(body, peeked); {
+		case err == nil:
+			if isCompressed(peeked) {
+				c = NoCompression
+			}
+		case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+			peeked = peeked[:n]
+			c = NoCompression
+		default:
+			return errors.WithMessage(err, "read body")
+		}
+	}
+
+	if _, err := broker.write(ctx, c[:]); err != nil {
+		return errors.WithMessage(err, "compression")
+	}
+
+	if c == NoCompression {
+		if _, err := broker.write(ctx, peeked); err != nil {
+			return errors.WithMessage(err, "write body")
+		}
+
+		if _, err := broker.writeReader(ctx, body); err != nil {
+			return errors.WithMessage(err, "write body reader")
+		}
+
+		return nil
+	}
+
+	cw, err := c.writer(broker.Writer)
+	if err != nil {
+		return errors.WithMessage(err, "compress body")
+	}
+
+	if _, err := io.Copy(cw, io.MultiReader(bytes.NewReader(peeked), body)); err != nil {
+		_ = cw.Close()
+
+		return errors.Wrap(err, "write compressed body")
+	}
+
+	return errors.Wrap(cw.Close(), "close compressed body")
+}
+
+// compression returns the first preferred compression, which the other side
+// accepts. Before reading head of the other side, the first preferred
+// compression is used; every broker can decompress all the known compressions.
+func (broker *baseBroker) compression() Compression {
+	if len(broker.compressions) < 1 {
+		return NoCompression
+	}
+
+	peers, isempty := broker.peerCompressions.Value()
+	if isempty {
+		return broker.compressions[0]
+	}
+
+	for i := range broker.compressions {
+		for j := range peers {
+			if broker.compressions[i] == peers[j] {
+				return broker.compressions[i]
+			}
+		}
+	}
+
+	return NoCompression
+}
+
 func (broker *baseBroker) readDataType(ctx context.Context) (dataType DataType, _ error) {
 	if err := func() error {
 		if _, err := broker.read(ctx, dataType[:]); err != nil {
@@ -423,6 +668,24 @@ func (broker *baseBroker) readBodyType(ctx context.Context) (bodyType BodyType,
 	return bodyType, nil
 }
 
+func (broker *baseBroker) readCompression(ctx context.Context) (c Compression, _ error) {
+	switch n, err := broker.read(ctx, c[:]); {
+	case err == nil:
+	case errors.Is(err, io.EOF):
+		if n < 1 {
+			return NoCompression, errors.WithMessage(err, "read compression")
+		}
+	default:
+		return NoCompression, errors.WithMessage(err, "read compression")
+	}
+
+	if err := c.IsValid(nil); err != nil {
+		return NoCompression, errors.WithMessage(err, "read compression")
+	}
+
+	return c, nil
+}
+
 func (broker *baseBroker) readEncoder(ctx context.Context) (encoder.Encoder, error) {
 	var ht string
 
@@ -447,10 +710,7 @@ func (broker *baseBroker) readHead(
 	ctx context.Context,
 	dataType DataType,
 ) (enc encoder.Encoder, header Header, _ error) {
-	switch dataType {
-	case RequestHeaderDataType:
-	case ResponseHeaderDataType:
-	default:
+	if !dataType.isRequestHead() && !dataType.isResponseHead() {
 		return nil, nil, errors.Errorf("expected header data type, but %v", dataType)
 	}
 
@@ -468,14 +728,27 @@ func (broker *baseBroker) readHead(
 		if err := encoder.Decode(enc, b, &header); err != nil {
 			return nil, nil, errors.WithMessage(err, "header")
 		}
+
+		switch sc, err := spanContextFromHeader(b); {
+		case err != nil:
+			return nil, nil, errors.WithMessage(err, "span context")
+		case !sc.IsZero():
+			_ = broker.remoteSpanContext.SetValue(sc)
+		}
+	}
+
+	if dataType.isExtended() {
+		if err := broker.readHeadExtension(ctx); err != nil {
+			return nil, nil, errors.WithMessage(err, "head extension")
+		}
 	}
 
-	switch dataType {
-	case RequestHeaderDataType:
+	switch {
+	case dataType.isRequestHead():
 		if _, err := util.AssertInterfaceValue[RequestHeader](header); err != nil {
 			return nil, nil, err
 		}
-	case ResponseHeaderDataType:
+	case dataType.isResponseHead():
 		if _, err := util.AssertInterfaceValue[ResponseHeader](header); err != nil {
 			return nil, nil, err
 		}
@@ -486,6 +759,76 @@ func (broker *baseBroker) readHead(
 	return enc, header, nil
 }
 
+// writeHeadExtension writes the head extension; the head extension is the
+// lengthed slice and the unknown items are ignored by the other side.
+func (broker *baseBroker) writeHeadExtension(context.Context) error {
+	return util.WriteLengthedSlice(broker.Writer, [][]byte{
+		compressionsToBytes(broker.compressions),
+	})
+}
+
+func (broker *baseBroker) readHeadExtension(context.Context) error {
+	var m [][]byte
+
+	switch _, i, err := util.ReadLengthedSlice(broker.Reader); {
+	case err != nil && !errors.Is(err, io.EOF):
+		return err
+	default:
+		m = i
+	}
+
+	if len(m) > 0 {
+		_ = broker.peerCompressions.SetValue(compressionsFromBytes(m[0]))
+	}
+
+	return nil
+}
+
+// headerWithSpanContext puts the span context into the marshaled header; the
+// span context is independent of the head extension and the other side, which
+// does not know the span context, ignores the unknown key of header.
+func headerWithSpanContext(b []byte, sc tracing.SpanContext) []byte {
+	if sc.IsZero() || len(b) < 2 || b[0] != '{' || b[len(b)-1] != '}' { //nolint:gomnd //...
+		return b
+	}
+
+	item := `"` + spanContextHeaderKey + `":"` + hex.EncodeToString(sc.Bytes()) + `"`
+
+	if len(bytes.TrimSpace(b[1:len(b)-1])) > 0 {
+		item += ","
+	}
+
+	return util.ConcatBytesSlice([]byte("{"), []byte(item), b[1:])
+}
+
+func spanContextFromHeader(b []byte) (sc tracing.SpanContext, _ error) {
+	if !bytes.Contains(b, []byte(`"`+spanContextHeaderKey+`"`)) {
+		return sc, nil
+	}
+
+	var u struct {
+		SpanContext string `json:"_span_context"` //nolint:tagliatelle //...
+	}
+
+	if err := util.UnmarshalJSON(b, &u); err != nil {
+		return sc, err
+	}
+
+	i, err := hex.DecodeString(u.SpanContext)
+	if err != nil {
+		return sc, errors.WithStack(err)
+	}
+
+	return tracing.SpanContextFromBytes(i)
+}
+
+func (broker *baseBroker) isExtended() bool {
+	broker.Lock()
+	defer broker.Unlock()
+
+	return broker.extended
+}
+
 func (broker *baseBroker) readBody(ctx context.Context) (
 	bodyType BodyType,
 	bodyLength uint64,
@@ -511,6 +854,17 @@ func (broker *baseBroker) readBody(ctx context.Context) (
 				return errors.Errorf("read fixed body length; eof")
 			}
 
+			c := NoCompression
+
+			if broker.isExtended() {
+				switch i, err := broker.readCompression(ctx); {
+				case err != nil:
+					return err
+				default:
+					c = i
+				}
+			}
+
 			switch i, err := broker.readLength(ctx); {
 			case err != nil && !errors.Is(err, io.EOF):
 				return errors.WithMessage(err, "read fixed body length")
@@ -520,15 +874,54 @@ func (broker *baseBroker) readBody(ctx context.Context) (
 
 				body = &bytes.Buffer{}
 
-				if bodyLength > 0 && !isEOF {
-					body = io.NewSectionReader(readerAt{broker.Reader}, 0, int64(bodyLength))
+				if bodyLength < 1 || isEOF {
+					return nil
 				}
 			}
+
+			if c == NoCompression {
+				body = io.NewSectionReader(readerAt{broker.Reader}, 0, int64(bodyLength))
+
+				return nil
+			}
+
+			// NOTE the decompressed body is limited by body length
+			switch i, err := c.reader(newFrameReader(broker.Reader)); {
+			case err != nil:
+				return errors.WithMessage(err, "decompress body")
+			default:
+				body = newLimitedReader(i, bodyLength)
+			}
 		case StreamBodyType:
-			body = broker.Reader
+			body = &bytes.Buffer{}
 
 			if isEOF {
-				body = &bytes.Buffer{}
+				return nil
+			}
+
+			if !broker.isExtended() {
+				body = broker.Reader
+
+				return nil
+			}
+
+			var c Compression
+
+			switch i, err := broker.readCompression(ctx); {
+			case errors.Is(err, io.EOF):
+				return nil
+			case err != nil:
+				return err
+			default:
+				c = i
+			}
+
+			switch i, err := c.reader(broker.Reader); {
+			case errors.Is(err, io.EOF):
+			case err != nil:
+				return errors.WithMessage(err, "decompress body")
+			default:
+				body = i
 			}
 		default:
 			return errors.Errorf("unknown body type, %d", bodyType)
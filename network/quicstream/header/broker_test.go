@@ -14,6 +14,7 @@ import (
 	"github.com/ProtoconNet/mitum2/util/encoder"
 	jsonenc "github.com/ProtoconNet/mitum2/util/encoder/json"
 	"github.com/ProtoconNet/mitum2/util/hint"
+	"github.com/ProtoconNet/mitum2/util/tracing"
 	"github.com/pkg/errors"
 	"github.com/quic-go/quic-go"
 	"github.com/stretchr/testify/suite"
@@ -53,7 +54,9 @@ func (t *testBrokers) clientBroker(ctx context.Context, ci quicstream.ConnInfo,
 	}
 }
 
-func (t *testBrokers) dialBroker(ctx context.Context, ci quicstream.ConnInfo, tlsConfig *tls.Config) (StreamFunc, func() error, error) {
+func (t *testBrokers) dialBroker(
+	ctx context.Context, ci quicstream.ConnInfo, tlsConfig *tls.Config, headExtensionf func() bool,
+) (StreamFunc, func() error, error) {
 	return NewDialFunc(
 		quicstream.NewConnInfoDialFunc(
 			func() *quic.Config { return nil },
@@ -61,6 +64,7 @@ func (t *testBrokers) dialBroker(ctx context.Context, ci quicstream.ConnInfo, tl
 		),
 		t.encs,
 		t.enc,
+		headExtensionf,
 	)(ctx, ci)
 }
 
@@ -124,7 +128,7 @@ func (t *testBrokers) TestRequestHeader() {
 		))
 
 		ctx := context.Background()
-		f, closef, err := t.dialBroker(ctx, quicstream.UnsafeConnInfo(srv.Bind, true), srv.TLSConfig)
+		f, closef, err := t.dialBroker(ctx, quicstream.UnsafeConnInfo(srv.Bind, true), srv.TLSConfig, nil)
 		t.NoError(err)
 		defer closef()
 
@@ -173,7 +177,7 @@ func (t *testBrokers) TestRequestHeader() {
 		))
 
 		ctx := context.Background()
-		f, closef, err := t.dialBroker(ctx, quicstream.UnsafeConnInfo(srv.Bind, true), srv.TLSConfig)
+		f, closef, err := t.dialBroker(ctx, quicstream.UnsafeConnInfo(srv.Bind, true), srv.TLSConfig, nil)
 		t.NoError(err)
 		defer closef()
 
@@ -219,7 +223,7 @@ func (t *testBrokers) TestRequestHeader() {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*1)
 		defer cancel()
 
-		f, closef, err := t.dialBroker(ctx, quicstream.UnsafeConnInfo(srv.Bind, true), srv.TLSConfig)
+		f, closef, err := t.dialBroker(ctx, quicstream.UnsafeConnInfo(srv.Bind, true), srv.TLSConfig, nil)
 		t.NoError(err)
 		defer closef()
 
@@ -264,7 +268,7 @@ func (t *testBrokers) TestRequestHeader() {
 		defer cancel()
 
 		t.T().Log("close writer")
-		f, closef, err := t.dialBroker(ctx, quicstream.UnsafeConnInfo(srv.Bind, true), srv.TLSConfig)
+		f, closef, err := t.dialBroker(ctx, quicstream.UnsafeConnInfo(srv.Bind, true), srv.TLSConfig, nil)
 		t.NoError(err)
 		defer closef()
 
@@ -574,6 +578,362 @@ func (t *testBrokers) TestReadBody() {
 	})
 }
 
+func (t *testBrokers) TestCompression() {
+	name := quicstream.HandlerName(util.UUID().String())
+	srv, ph, _, brokerf := t.server(name)
+	defer srv.StopWait()
+
+	compressionch := make(chan Compression, 1)
+
+	ph.Add(name, NewHandler(t.encs, func(ctx context.Context, _ net.Addr, broker *HandlerBroker, _ RequestHeader) (context.Context, error) {
+		switch {
+		case broker.isExtended():
+			compressionch <- broker.compression()
+		default:
+			compressionch <- NoCompression
+		}
+
+		bodyType, bodyLength, body, _, _, err := broker.ReadBody(ctx)
+		if err != nil {
+			return ctx, err
+		}
+
+		b, err := io.ReadAll(body)
+		if err != nil {
+			return ctx, err
+		}
+
+		if err := broker.WriteResponseHeadOK(ctx, true, nil); err != nil {
+			return ctx, err
+		}
+
+		return ctx, broker.WriteBody(ctx, bodyType, bodyLength, bytes.NewBuffer(b))
+	}, nil))
+
+	bodybytes := bytes.Repeat(util.UUID().Bytes(), 1<<10)
+
+	extended := true
+
+	request := func(bodyType BodyType, body []byte, cs ...Compression) (Compression, []byte) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		broker, closef := brokerf(ctx)
+		defer closef()
+
+		broker.SetHeadExtension(extended)
+
+		if cs != nil {
+			t.NoError(broker.SetCompressions(DefaultCompressionThreshold, cs...))
+		}
+
+		t.NoError(broker.WriteRequestHead(ctx, newDummyRequestHeader(name, util.UUID().String())))
+		t.NoError(broker.WriteBody(ctx, bodyType, uint64(len(body)), bytes.NewBuffer(body)))
+
+		var c Compression
+
+		select {
+		case <-time.After(time.Second * 2):
+			t.Fail("failed to wait handler")
+		case c = <-compressionch:
+		}
+
+		_, rres, err := broker.ReadResponseHead(ctx)
+		t.NoError(err)
+		t.True(rres.OK())
+
+		rbodyType, rbodyLength, rbody, _, _, err := broker.ReadBody(ctx)
+		t.NoError(err)
+		t.Equal(bodyType, rbodyType)
+
+		if bodyType == FixedLengthBodyType {
+			t.Equal(uint64(len(body)), rbodyLength)
+		}
+
+		rb, err := io.ReadAll(rbody)
+		t.NoError(err)
+
+		return c, rb
+	}
+
+	t.Run("fixed length body", func() {
+		c, rb := request(FixedLengthBodyType, bodybytes)
+		t.Equal(ZstdCompression, c)
+		t.Equal(bodybytes, rb)
+	})
+
+	t.Run("stream body", func() {
+		c, rb := request(StreamBodyType, bodybytes)
+		t.Equal(ZstdCompression, c)
+		t.Equal(bodybytes, rb)
+	})
+
+	t.Run("short body", func() {
+		body := util.UUID().Bytes()
+
+		_, rb := request(FixedLengthBodyType, body)
+		t.Equal(body, rb)
+
+		_, rb = request(StreamBodyType, body)
+		t.Equal(body, rb)
+	})
+
+	t.Run("gzip only", func() {
+		c, rb := request(FixedLengthBodyType, bodybytes, GzipCompression)
+		t.Equal(GzipCompression, c)
+		t.Equal(bodybytes, rb)
+
+		c, rb = request(StreamBodyType, bodybytes, GzipCompression)
+		t.Equal(GzipCompression, c)
+		t.Equal(bodybytes, rb)
+	})
+
+	t.Run("without compression", func() {
+		c, rb := request(FixedLengthBodyType, bodybytes, []Compression{}...)
+		t.Equal(NoCompression, c)
+		t.Equal(bodybytes, rb)
+	})
+
+	t.Run("without head extension", func() {
+		extended = false
+		defer func() {
+			extended = true
+		}()
+
+		c, rb := request(FixedLengthBodyType, bodybytes)
+		t.Equal(NoCompression, c)
+		t.Equal(bodybytes, rb)
+
+		c, rb = request(StreamBodyType, bodybytes)
+		t.Equal(NoCompression, c)
+		t.Equal(bodybytes, rb)
+	})
+
+	t.Run("already compressed body", func() {
+		body := append(bytes.Clone(zstdMagic), bodybytes...)
+
+		_, rb := request(FixedLengthBodyType, body)
+		t.Equal(body, rb)
+
+		_, rb = request(StreamBodyType, body)
+		t.Equal(body, rb)
+	})
+
+	t.Run("unknown compression", func() {
+		ctx := context.Background()
+
+		broker, closef := brokerf(ctx)
+		defer closef()
+
+		err := broker.SetCompressions(0, Compression{0xff})
+		t.Error(err)
+		t.ErrorIs(err, util.ErrInvalid)
+	})
+}
+
+func (t *testBrokers) TestSpanContext() {
+	name := quicstream.HandlerName(util.UUID().String())
+	srv, ph, _, brokerf := t.server(name)
+	defer srv.StopWait()
+
+	scch := make(chan tracing.SpanContext, 1)
+
+	ph.Add(name, NewHandler(t.encs, func(ctx context.Context, _ net.Addr, broker *HandlerBroker, _ RequestHeader) (context.Context, error) {
+		scch <- tracing.SpanContextFromContext(ctx)
+
+		return ctx, broker.WriteResponseHeadOK(ctx, true, nil)
+	}, nil))
+
+	request := func(ctx context.Context, extended bool) tracing.SpanContext {
+		broker, closef := brokerf(ctx)
+		defer closef()
+
+		broker.SetHeadExtension(extended)
+
+		t.NoError(broker.WriteRequestHead(ctx, newDummyRequestHeader(name, util.UUID().String())))
+
+		var sc tracing.SpanContext
+
+		select {
+		case <-time.After(time.Second * 2):
+			t.Fail("failed to wait handler")
+		case sc = <-scch:
+		}
+
+		_, rres, err := broker.ReadResponseHead(ctx)
+		t.NoError(err)
+		t.True(rres.OK())
+
+		return sc
+	}
+
+	t.Run("with span context", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		sc := tracing.SpanContext{TraceID: tracing.NewTraceID(), SpanID: tracing.NewSpanID()}
+
+		t.Equal(sc, request(tracing.ContextWithRemoteSpanContext(ctx, sc), true))
+	})
+
+	t.Run("without span context", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		t.True(request(ctx, true).IsZero())
+	})
+
+	t.Run("without head extension", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		sc := tracing.SpanContext{TraceID: tracing.NewTraceID(), SpanID: tracing.NewSpanID()}
+
+		t.Equal(sc, request(tracing.ContextWithRemoteSpanContext(ctx, sc), false))
+	})
+}
+
+func (t *testBrokers) TestDialFuncHeadExtension() {
+	name := quicstream.HandlerName(util.UUID().String())
+	srv, ph, _, _ := t.server(name)
+	defer srv.StopWait()
+
+	extendedch := make(chan bool, 1)
+
+	ph.Add(name, NewHandler(t.encs, func(ctx context.Context, _ net.Addr, broker *HandlerBroker, _ RequestHeader) (context.Context, error) {
+		extendedch <- broker.isExtended()
+
+		return ctx, broker.WriteResponseHeadOK(ctx, true, nil)
+	}, nil))
+
+	request := func(headExtensionf func() bool) bool {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		f, closef, err := t.dialBroker(ctx, quicstream.UnsafeConnInfo(srv.Bind, true), srv.TLSConfig, headExtensionf)
+		t.NoError(err)
+		defer closef()
+
+		var extended bool
+
+		t.NoError(f(ctx, func(ctx context.Context, broker *ClientBroker) error {
+			if err := broker.WriteRequestHead(ctx, newDummyRequestHeader(name, util.UUID().String())); err != nil {
+				return err
+			}
+
+			select {
+			case <-time.After(time.Second * 2):
+				return errors.Errorf("failed to wait handler")
+			case extended = <-extendedch:
+			}
+
+			_, _, err := broker.ReadResponseHead(ctx)
+
+			return err
+		}))
+
+		return extended
+	}
+
+	t.Run("nil", func() {
+		t.False(request(nil))
+	})
+
+	t.Run("changed", func() {
+		var enabled bool
+
+		f := func() bool { return enabled }
+
+		t.False(request(f))
+
+		enabled = true
+
+		t.True(request(f))
+	})
+}
+
+func (t *testBrokers) TestSpanContextDefault() {
+	name := quicstream.HandlerName(util.UUID().String())
+	srv, ph, _, brokerf := t.server(name)
+	defer srv.StopWait()
+
+	tracer := tracing.NewTracer("n0", nil)
+
+	spanch := make(chan tracing.SpanContext, 1)
+
+	ph.Add(name, NewHandler(t.encs, func(ctx context.Context, _ net.Addr, broker *HandlerBroker, _ RequestHeader) (context.Context, error) {
+		_, span := tracer.StartSpan(ctx, "handler")
+		spanch <- span.SpanContext()
+
+		return ctx, broker.WriteResponseHeadOK(ctx, true, nil)
+	}, nil))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+
+	ctx, span := tracer.StartSpan(ctx, "client")
+
+	broker, closef := brokerf(ctx)
+	defer closef()
+
+	t.False(broker.isExtended(), "default params")
+
+	t.NoError(broker.WriteRequestHead(ctx, newDummyRequestHeader(name, util.UUID().String())))
+
+	var sc tracing.SpanContext
+
+	select {
+	case <-time.After(time.Second * 2):
+		t.Fail("failed to wait handler")
+	case sc = <-spanch:
+	}
+
+	_, rres, err := broker.ReadResponseHead(ctx)
+	t.NoError(err)
+	t.True(rres.OK())
+
+	t.Equal(span.SpanContext().TraceID, sc.TraceID, "same trace id")
+	t.NotEqual(span.SpanContext().SpanID, sc.SpanID)
+}
+
+func (t *testBrokers) TestHeaderWithSpanContext() {
+	sc := tracing.SpanContext{TraceID: tracing.NewTraceID(), SpanID: tracing.NewSpanID()}
+
+	t.Run("header", func() {
+		header := newDummyRequestHeader(quicstream.HandlerName(util.UUID().String()), util.UUID().String())
+
+		b, err := t.enc.Marshal(header)
+		t.NoError(err)
+
+		hb := headerWithSpanContext(b, sc)
+
+		var rheader dummyRequestHeader
+		t.NoError(encoder.Decode(t.enc, hb, &rheader), "old side can decode")
+		t.Equal(header.ID, rheader.ID)
+
+		rsc, err := spanContextFromHeader(hb)
+		t.NoError(err)
+		t.Equal(sc, rsc)
+	})
+
+	t.Run("empty object", func() {
+		hb := headerWithSpanContext([]byte("{}"), sc)
+
+		rsc, err := spanContextFromHeader(hb)
+		t.NoError(err)
+		t.Equal(sc, rsc)
+	})
+
+	t.Run("empty span context", func() {
+		b := []byte(`{"a":1}`)
+		t.Equal(b, headerWithSpanContext(b, tracing.SpanContext{}))
+
+		rsc, err := spanContextFromHeader(b)
+		t.NoError(err)
+		t.True(rsc.IsZero())
+	})
+}
+
 func TestBrokers(t *testing.T) {
 	defer goleak.VerifyNone(t,
 		goleak.IgnoreTopFunction("github.com/ProtoconNet/mitum2/util.EnsureRead.func1"),
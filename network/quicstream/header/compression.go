@@ -0,0 +1,321 @@
+package quicstreamheader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+)
+
+type Compression [1]byte
+
+var (
+	NoCompression   Compression = [1]byte{0x00}
+	GzipCompression Compression = [1]byte{0x01}
+	ZstdCompression Compression = [1]byte{0x02}
+)
+
+var (
+	// DefaultCompressions is the default preferred compressions of broker.
+	DefaultCompressions = []Compression{ZstdCompression, GzipCompression}
+	// DefaultCompressionThreshold is the default minimum body length to be
+	// compressed.
+	DefaultCompressionThreshold uint64 = 1 << 12 //nolint:gomnd //...
+)
+
+var compressionFrameSize uint64 = 1 << 16 //nolint:gomnd //...
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+func (c Compression) IsValid([]byte) error {
+	switch c {
+	case NoCompression,
+		GzipCompression,
+		ZstdCompression:
+		return nil
+	default:
+		return util.ErrInvalid.Errorf("unknown compression, %v", c)
+	}
+}
+
+func (c Compression) String() string {
+	switch c {
+	case NoCompression:
+		return "none"
+	case GzipCompression:
+		return "gzip"
+	case ZstdCompression:
+		return "zstd"
+	default:
+		return "<unknown>"
+	}
+}
+
+func (c Compression) writer(w io.Writer) (io.WriteCloser, error) {
+	switch c {
+	case GzipCompression:
+		i, err := gzip.NewWriterLevel(w, gzip.BestSpeed)
+
+		return i, errors.WithStack(err)
+	case ZstdCompression:
+		i, err := zstd.NewWriter(w,
+			zstd.WithEncoderLevel(zstd.SpeedFastest),
+			zstd.WithEncoderConcurrency(1),
+		)
+
+		return i, errors.WithStack(err)
+	default:
+		return nil, errors.Errorf("unknown compression, %v", c)
+	}
+}
+
+func (c Compression) reader(r io.Reader) (io.Reader, error) {
+	switch c {
+	case NoCompression:
+		return r, nil
+	case GzipCompression:
+		i, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		return &decompressReader{Reader: i, closef: i.Close}, nil
+	case ZstdCompression:
+		i, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		return &decompressReader{Reader: i, closef: func() error {
+			i.Close()
+
+			return nil
+		}}, nil
+	default:
+		return nil, errors.Errorf("unknown compression, %v", c)
+	}
+}
+
+func compressionsToBytes(cs []Compression) []byte {
+	b := make([]byte, len(cs))
+
+	for i := range cs {
+		b[i] = cs[i][0]
+	}
+
+	return b
+}
+
+func compressionsFromBytes(b []byte) []Compression {
+	cs := make([]Compression, 0, len(b))
+
+	for i := range b {
+		c := Compression{b[i]}
+
+		// NOTE unknown compression is ignored
+		if c == NoCompression || c.IsValid(nil) != nil {
+			continue
+		}
+
+		cs = append(cs, c)
+	}
+
+	return cs
+}
+
+// isCompressed checks whether the body is already compressed.
+func isCompressed(b []byte) bool {
+	return bytes.HasPrefix(b, gzipMagic) || bytes.HasPrefix(b, zstdMagic)
+}
+
+// decompressReader releases the decompressor when the body is read to the end.
+type decompressReader struct {
+	io.Reader
+	closef func() error
+	closed bool
+}
+
+func (r *decompressReader) Read(p []byte) (int, error) {
+	if r.closed {
+		return 0, io.EOF
+	}
+
+	n, err := r.Reader.Read(p)
+	if err != nil {
+		_ = r.Close()
+	}
+
+	return n, err //nolint:wrapcheck //...
+}
+
+func (r *decompressReader) Close() error {
+	if r.closed {
+		return nil
+	}
+
+	r.closed = true
+
+	return r.closef()
+}
+
+// frameWriter writes the compressed body in lengthed frames; the empty frame
+// closes the body, so the other side can read the compressed body without the
+// whole length.
+type frameWriter struct {
+	w   io.Writer
+	buf []byte
+}
+
+func newFrameWriter(w io.Writer) *frameWriter {
+	return &frameWriter{w: w, buf: make([]byte, 0, compressionFrameSize)}
+}
+
+func (w *frameWriter) Write(p []byte) (int, error) {
+	var n int
+
+	for len(p) > 0 {
+		i := copy(w.buf[len(w.buf):cap(w.buf)], p)
+		w.buf = w.buf[:len(w.buf)+i]
+		p = p[i:]
+		n += i
+
+		if len(w.buf) == cap(w.buf) {
+			if err := w.flush(); err != nil {
+				return n, err
+			}
+		}
+	}
+
+	return n, nil
+}
+
+func (w *frameWriter) Close() error {
+	if err := w.flush(); err != nil {
+		return err
+	}
+
+	return util.WriteLength(w.w, 0)
+}
+
+func (w *frameWriter) flush() error {
+	if len(w.buf) < 1 {
+		return nil
+	}
+
+	if err := util.WriteLengthed(w.w, w.buf); err != nil {
+		return err
+	}
+
+	w.buf = w.buf[:0]
+
+	return nil
+}
+
+type frameReader struct {
+	r     io.Reader
+	frame []byte
+	eof   bool
+}
+
+func newFrameReader(r io.Reader) *frameReader {
+	return &frameReader{r: r}
+}
+
+func (r *frameReader) Read(p []byte) (int, error) {
+	if len(r.frame) < 1 {
+		if r.eof {
+			return 0, io.EOF
+		}
+
+		switch _, i, err := util.ReadLength(r.r); {
+		case err != nil:
+			return 0, err
+		case i < 1:
+			r.eof = true
+
+			return 0, io.EOF
+		case i > compressionFrameSize:
+			return 0, errors.Errorf("too big frame, %d", i)
+		default:
+			r.frame = make([]byte, i)
+
+			if _, err := util.EnsureRead(context.Background(), r.r, r.frame); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	n := copy(p, r.frame)
+	r.frame = r.frame[n:]
+
+	return n, nil
+}
+
+// limitedReader reads n bytes; if the underlying reader returns EOF before n
+// bytes, it returns io.ErrUnexpectedEOF and if the underlying reader has more
+// than n bytes, it returns error. The underlying reader is closed when n bytes
+// are read.
+type limitedReader struct {
+	r    io.Reader
+	n    uint64
+	done bool
+}
+
+func newLimitedReader(r io.Reader, n uint64) *limitedReader {
+	return &limitedReader{r: r, n: n}
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n < 1 {
+		if l.done {
+			return 0, io.EOF
+		}
+
+		l.done = true
+
+		return 0, l.end()
+	}
+
+	if uint64(len(p)) > l.n {
+		p = p[:l.n] //revive:disable-line:modifies-parameter
+	}
+
+	n, err := l.r.Read(p)
+	l.n -= uint64(n) //nolint:gosec //...
+
+	if errors.Is(err, io.EOF) && l.n > 0 {
+		return n, io.ErrUnexpectedEOF
+	}
+
+	return n, err //nolint:wrapcheck //...
+}
+
+// end checks the underlying reader reaches EOF; the end of compressed body
+// should be read.
+func (l *limitedReader) end() error {
+	defer func() {
+		if i, ok := l.r.(io.Closer); ok {
+			_ = i.Close()
+		}
+	}()
+
+	var b [1]byte
+
+	for {
+		switch n, err := l.r.Read(b[:]); {
+		case n > 0:
+			return errors.Errorf("body longer than body length")
+		case errors.Is(err, io.EOF):
+			return io.EOF
+		case err != nil:
+			return err
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package quicstreamheader
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/stretchr/testify/suite"
+)
+
+type testCompression struct {
+	suite.Suite
+}
+
+func (t *testCompression) compress(c Compression, b []byte) []byte {
+	buf := bytes.NewBuffer(nil)
+
+	fw := newFrameWriter(buf)
+
+	cw, err := c.writer(fw)
+	t.NoError(err)
+
+	_, err = cw.Write(b)
+	t.NoError(err)
+	t.NoError(cw.Close())
+	t.NoError(fw.Close())
+
+	return buf.Bytes()
+}
+
+func (t *testCompression) TestFrames() {
+	body := bytes.Repeat(util.UUID().Bytes(), int(compressionFrameSize)) // NOTE multiple frames
+
+	for _, c := range []Compression{GzipCompression, ZstdCompression} {
+		t.Run(c.String(), func() {
+			next := util.UUID().Bytes()
+
+			r := bytes.NewReader(append(t.compress(c, body), next...))
+
+			dr, err := c.reader(newFrameReader(r))
+			t.NoError(err)
+
+			b, err := io.ReadAll(newLimitedReader(dr, uint64(len(body))))
+			t.NoError(err)
+			t.Equal(body, b)
+
+			left, err := io.ReadAll(r)
+			t.NoError(err)
+			t.Equal(next, left, "next bytes should not be read")
+		})
+	}
+}
+
+func (t *testCompression) TestLimit() {
+	body := bytes.Repeat(util.UUID().Bytes(), 1<<10)
+
+	t.Run("bigger than body length", func() {
+		dr, err := ZstdCompression.reader(newFrameReader(bytes.NewReader(t.compress(ZstdCompression, body))))
+		t.NoError(err)
+
+		b, err := io.ReadAll(newLimitedReader(dr, 33))
+		t.Error(err)
+		t.ErrorContains(err, "body longer than body length")
+		t.Equal(body[:33], b)
+	})
+
+	t.Run("smaller than body length", func() {
+		dr, err := ZstdCompression.reader(newFrameReader(bytes.NewReader(t.compress(ZstdCompression, body))))
+		t.NoError(err)
+
+		_, err = io.ReadAll(newLimitedReader(dr, uint64(len(body)+1)))
+		t.Error(err)
+		t.ErrorIs(err, io.ErrUnexpectedEOF)
+	})
+
+	t.Run("too big frame", func() {
+		buf := bytes.NewBuffer(nil)
+		t.NoError(util.WriteLength(buf, compressionFrameSize+1))
+
+		_, err := io.ReadAll(newFrameReader(buf))
+		t.Error(err)
+		t.ErrorContains(err, "too big frame")
+	})
+}
+
+func TestCompression(t *testing.T) {
+	suite.Run(t, new(testCompression))
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/ProtoconNet/mitum2/network/quicstream"
 	"github.com/ProtoconNet/mitum2/util"
 	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/ProtoconNet/mitum2/util/tracing"
 	"github.com/pkg/errors"
 )
 
@@ -88,7 +89,7 @@ func baseHandler[T RequestHeader](
 			}
 		}
 
-		return handler(ctx, addr, broker, header)
+		return handler(tracing.ContextWithRemoteSpanContext(ctx, broker.RemoteSpanContext()), addr, broker, header)
 	})
 
 	if i == nil {
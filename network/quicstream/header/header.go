@@ -19,6 +19,11 @@ var (
 	RequestHeaderDataType  DataType = [1]byte{0x01}
 	BodyDataType           DataType = [1]byte{0x02}
 	ResponseHeaderDataType DataType = [1]byte{0x03}
+	// RequestHeaderV1DataType and ResponseHeaderV1DataType are the extended
+	// heads; the extended head has the head extension and the body after the
+	// extended head has the compression.
+	RequestHeaderV1DataType  DataType = [1]byte{0x04}
+	ResponseHeaderV1DataType DataType = [1]byte{0x05}
 )
 
 var (
@@ -32,13 +37,38 @@ func (t DataType) IsValid([]byte) error {
 	switch t {
 	case RequestHeaderDataType,
 		BodyDataType,
-		ResponseHeaderDataType:
+		ResponseHeaderDataType,
+		RequestHeaderV1DataType,
+		ResponseHeaderV1DataType:
 		return nil
 	default:
 		return util.ErrInvalid.Errorf("unknown data type, %v", t)
 	}
 }
 
+func (t DataType) isRequestHead() bool {
+	return t == RequestHeaderDataType || t == RequestHeaderV1DataType
+}
+
+func (t DataType) isResponseHead() bool {
+	return t == ResponseHeaderDataType || t == ResponseHeaderV1DataType
+}
+
+func (t DataType) isExtended() bool {
+	return t == RequestHeaderV1DataType || t == ResponseHeaderV1DataType
+}
+
+func (t DataType) extended() DataType {
+	switch t {
+	case RequestHeaderDataType:
+		return RequestHeaderV1DataType
+	case ResponseHeaderDataType:
+		return ResponseHeaderV1DataType
+	default:
+		return t
+	}
+}
+
 func (t BodyType) IsValid([]byte) error {
 	switch t {
 	case EmptyBodyType,
@@ -0,0 +1,341 @@
+package quicstream
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/util"
+)
+
+// Instrument collects the connection and stream events of Server and
+// ConnectionPool. inbound is true for the connections and streams accepted by
+// Server.
+type Instrument interface {
+	ConnectionOpened(remote string, inbound bool)
+	ConnectionClosed(remote string, inbound bool)
+	ConnectionFailed(remote string, inbound bool, _ error)
+	StreamStarted(remote string, inbound bool)
+	StreamEnded(remote string, inbound bool, _ StreamEnd)
+}
+
+// StreamEnd is the result of stream. Handler is empty for the outbound streams
+// or when handler is not found.
+type StreamEnd struct {
+	Err      error
+	Handler  HandlerName
+	Elapsed  time.Duration
+	BytesIn  uint64
+	BytesOut uint64
+}
+
+type nilInstrument struct{}
+
+func (nilInstrument) ConnectionOpened(string, bool)        {}
+func (nilInstrument) ConnectionClosed(string, bool)        {}
+func (nilInstrument) ConnectionFailed(string, bool, error) {}
+func (nilInstrument) StreamStarted(string, bool)           {}
+func (nilInstrument) StreamEnded(string, bool, StreamEnd)  {}
+
+// Instruments delivers the events to the multiple Instruments.
+type Instruments []Instrument
+
+func (is Instruments) ConnectionOpened(remote string, inbound bool) {
+	for i := range is {
+		is[i].ConnectionOpened(remote, inbound)
+	}
+}
+
+func (is Instruments) ConnectionClosed(remote string, inbound bool) {
+	for i := range is {
+		is[i].ConnectionClosed(remote, inbound)
+	}
+}
+
+func (is Instruments) ConnectionFailed(remote string, inbound bool, err error) {
+	for i := range is {
+		is[i].ConnectionFailed(remote, inbound, err)
+	}
+}
+
+func (is Instruments) StreamStarted(remote string, inbound bool) {
+	for i := range is {
+		is[i].StreamStarted(remote, inbound)
+	}
+}
+
+func (is Instruments) StreamEnded(remote string, inbound bool, e StreamEnd) {
+	for i := range is {
+		is[i].StreamEnded(remote, inbound, e)
+	}
+}
+
+var streamHandlerNameContextKey = util.ContextKey("stream_handler_name")
+
+// setStreamHandlerName records the handler name to the instrumented stream.
+func setStreamHandlerName(ctx context.Context, name HandlerName) {
+	if i, ok := ctx.Value(streamHandlerNameContextKey).(*util.Locked[HandlerName]); ok {
+		_ = i.SetValue(name)
+	}
+}
+
+// instrumentStream wraps the stream reader and writer to count the bytes; the
+// returned function reports the end of stream.
+func instrumentStream(
+	ctx context.Context,
+	instrument Instrument,
+	remote string,
+	inbound bool,
+	r io.Reader,
+	w io.WriteCloser,
+) (context.Context, io.Reader, io.WriteCloser, func(error)) {
+	instrument.StreamStarted(remote, inbound)
+
+	started := time.Now()
+	name := util.EmptyLocked[HandlerName]()
+
+	cr := &countReader{Reader: r}
+	cw := &countWriteCloser{WriteCloser: w}
+
+	return context.WithValue(ctx, streamHandlerNameContextKey, name), cr, cw, func(err error) {
+		handler, _ := name.Value()
+
+		instrument.StreamEnded(remote, inbound, StreamEnd{
+			Handler:  handler,
+			Err:      err,
+			Elapsed:  time.Since(started),
+			BytesIn:  cr.n.Load(),
+			BytesOut: cw.n.Load(),
+		})
+	}
+}
+
+type countReader struct {
+	io.Reader
+	n atomic.Uint64
+}
+
+func (r *countReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.n.Add(uint64(n))
+
+	return n, err //nolint:wrapcheck //...
+}
+
+type countWriteCloser struct {
+	io.WriteCloser
+	n atomic.Uint64
+}
+
+func (w *countWriteCloser) Write(p []byte) (int, error) {
+	n, err := w.WriteCloser.Write(p)
+	w.n.Add(uint64(n))
+
+	return n, err //nolint:wrapcheck //...
+}
+
+type ConnectionStats struct {
+	Open   int64  `json:"open"`
+	Total  uint64 `json:"total"`
+	Failed uint64 `json:"failed"`
+}
+
+type StreamStats struct {
+	Open       int64                 `json:"open"`
+	Total      uint64                `json:"total"`
+	Errors     uint64                `json:"errors"`
+	BytesIn    uint64                `json:"bytes_in"`
+	BytesOut   uint64                `json:"bytes_out"`
+	Elapsed    util.ReadableDuration `json:"elapsed"`
+	MaxElapsed util.ReadableDuration `json:"max_elapsed"`
+}
+
+func (s *StreamStats) ended(e StreamEnd) {
+	s.Open--
+	s.Total++
+	s.BytesIn += e.BytesIn
+	s.BytesOut += e.BytesOut
+	s.Elapsed += util.ReadableDuration(e.Elapsed)
+
+	if util.ReadableDuration(e.Elapsed) > s.MaxElapsed {
+		s.MaxElapsed = util.ReadableDuration(e.Elapsed)
+	}
+
+	if e.Err != nil {
+		s.Errors++
+	}
+}
+
+type PeerStats struct {
+	Inbound     StreamStats     `json:"inbound"`
+	Outbound    StreamStats     `json:"outbound"`
+	Connections ConnectionStats `json:"connections"`
+}
+
+func (s *PeerStats) streams(inbound bool) *StreamStats {
+	if inbound {
+		return &s.Inbound
+	}
+
+	return &s.Outbound
+}
+
+type InstrumentSnapshot struct {
+	Handlers map[string]StreamStats `json:"handlers"`
+	Peers    map[string]PeerStats   `json:"peers"`
+	Inbound  struct {
+		Connections ConnectionStats `json:"connections"`
+		Streams     StreamStats     `json:"streams"`
+	} `json:"inbound"`
+	Outbound struct {
+		Connections ConnectionStats `json:"connections"`
+		Streams     StreamStats     `json:"streams"`
+	} `json:"outbound"`
+}
+
+// OthersInstrumentPeer collects the peers over the peer limit of
+// InstrumentStats.
+const OthersInstrumentPeer = "_others"
+
+// InstrumentStats is the Instrument, which aggregates the events by peer and
+// handler. The inbound peers are keyed by host without port, because the port
+// of inbound connection is ephemeral. The number of peers is limited; the peers
+// over the limit are aggregated into OthersInstrumentPeer.
+type InstrumentStats struct {
+	snapshot InstrumentSnapshot
+	maxPeers int
+	sync.RWMutex
+}
+
+func NewInstrumentStats(maxPeers int) *InstrumentStats {
+	s := &InstrumentStats{maxPeers: maxPeers}
+
+	s.snapshot.Handlers = map[string]StreamStats{}
+	s.snapshot.Peers = map[string]PeerStats{}
+
+	return s
+}
+
+func (s *InstrumentStats) ConnectionOpened(remote string, inbound bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.updatePeer(remote, inbound, func(p *PeerStats) {
+		p.Connections.Open++
+		p.Connections.Total++
+	})
+
+	c := s.connections(inbound)
+	c.Open++
+	c.Total++
+}
+
+func (s *InstrumentStats) ConnectionClosed(remote string, inbound bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.updatePeer(remote, inbound, func(p *PeerStats) {
+		p.Connections.Open--
+	})
+
+	s.connections(inbound).Open--
+}
+
+func (s *InstrumentStats) ConnectionFailed(remote string, inbound bool, _ error) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.updatePeer(remote, inbound, func(p *PeerStats) {
+		p.Connections.Failed++
+	})
+
+	s.connections(inbound).Failed++
+}
+
+func (s *InstrumentStats) StreamStarted(remote string, inbound bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.updatePeer(remote, inbound, func(p *PeerStats) {
+		p.streams(inbound).Open++
+	})
+
+	s.streams(inbound).Open++
+}
+
+func (s *InstrumentStats) StreamEnded(remote string, inbound bool, e StreamEnd) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.updatePeer(remote, inbound, func(p *PeerStats) {
+		p.streams(inbound).ended(e)
+	})
+
+	s.streams(inbound).ended(e)
+
+	if inbound && len(e.Handler) > 0 {
+		h := s.snapshot.Handlers[e.Handler.String()]
+		h.Open++ // NOTE handler is known after stream ended
+		h.ended(e)
+
+		s.snapshot.Handlers[e.Handler.String()] = h
+	}
+}
+
+// Snapshot returns the copy of current stats.
+func (s *InstrumentStats) Snapshot() InstrumentSnapshot {
+	s.RLock()
+	defer s.RUnlock()
+
+	n := s.snapshot
+
+	n.Handlers = make(map[string]StreamStats, len(s.snapshot.Handlers))
+	for i := range s.snapshot.Handlers {
+		n.Handlers[i] = s.snapshot.Handlers[i]
+	}
+
+	n.Peers = make(map[string]PeerStats, len(s.snapshot.Peers))
+	for i := range s.snapshot.Peers {
+		n.Peers[i] = s.snapshot.Peers[i]
+	}
+
+	return n
+}
+
+func (s *InstrumentStats) updatePeer(remote string, inbound bool, f func(*PeerStats)) {
+	key := remote
+
+	if inbound {
+		if host, _, err := net.SplitHostPort(remote); err == nil {
+			key = host
+		}
+	}
+
+	if _, found := s.snapshot.Peers[key]; !found && s.maxPeers > 0 && len(s.snapshot.Peers) >= s.maxPeers {
+		key = OthersInstrumentPeer
+	}
+
+	p := s.snapshot.Peers[key]
+	f(&p)
+
+	s.snapshot.Peers[key] = p
+}
+
+func (s *InstrumentStats) connections(inbound bool) *ConnectionStats {
+	if inbound {
+		return &s.snapshot.Inbound.Connections
+	}
+
+	return &s.snapshot.Outbound.Connections
+}
+
+func (s *InstrumentStats) streams(inbound bool) *StreamStats {
+	if inbound {
+		return &s.snapshot.Inbound.Streams
+	}
+
+	return &s.snapshot.Outbound.Streams
+}
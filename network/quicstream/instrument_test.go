@@ -0,0 +1,162 @@
+package quicstream
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/pkg/errors"
+	"github.com/quic-go/quic-go"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/goleak"
+)
+
+type testInstrument struct {
+	BaseTest
+}
+
+func (t *testInstrument) TestStats() {
+	serverStats := NewInstrumentStats(0)
+
+	handlers := NewPrefixHandler(nil)
+	handlers.Add("echo", t.EchoHandler())
+
+	srv := t.NewDefaultServer(nil, handlers.Handler)
+	srv.SetInstrument(serverStats)
+	t.NoError(srv.EnsureStart(context.Background()))
+	defer srv.StopWait()
+
+	clientStats := NewInstrumentStats(0)
+
+	p, err := NewConnectionPool(3, NewConnInfoDialFunc(
+		func() *quic.Config { return nil },
+		t.TLSConfig,
+	))
+	t.NoError(err)
+	defer p.Stop()
+	defer p.CloseAll()
+
+	p.SetInstrument(clientStats)
+
+	ci := UnsafeConnInfo(srv.Bind, true)
+
+	streamer, err := p.Dial(context.Background(), ci)
+	t.NoError(err)
+
+	body := util.UUID().Bytes()
+
+	t.NoError(streamer.Stream(context.Background(), func(ctx context.Context, r io.Reader, w io.WriteCloser) error {
+		if err := WritePrefix(ctx, w, HandlerName("echo").Prefix()); err != nil {
+			return err
+		}
+
+		if _, err := w.Write(body); err != nil {
+			return errors.WithStack(err)
+		}
+
+		_ = w.Close()
+
+		_, err := io.ReadAll(r)
+
+		return errors.WithStack(err)
+	}))
+
+	t.Run("client", func() {
+		s := clientStats.Snapshot()
+
+		t.Equal(int64(1), s.Outbound.Connections.Open)
+		t.Equal(uint64(1), s.Outbound.Streams.Total)
+		t.Equal(int64(0), s.Outbound.Streams.Open)
+		t.Equal(uint64(len(body)+32), s.Outbound.Streams.BytesOut)
+		t.Equal(uint64(len(body)), s.Outbound.Streams.BytesIn)
+
+		peer, found := s.Peers[ci.Addr().String()]
+		t.True(found)
+		t.Equal(uint64(1), peer.Outbound.Total)
+		t.Empty(s.Handlers)
+	})
+
+	t.Run("server", func() {
+		var s InstrumentSnapshot
+
+		// NOTE stream end is reported after client finished
+		t.Eventually(func() bool {
+			s = serverStats.Snapshot()
+
+			return s.Inbound.Streams.Total > 0
+		}, time.Second*2, time.Millisecond*33)
+
+		t.Equal(int64(1), s.Inbound.Connections.Open)
+		t.Equal(uint64(1), s.Inbound.Streams.Total)
+		t.Equal(uint64(len(body)+32), s.Inbound.Streams.BytesIn)
+
+		host, _, err := net.SplitHostPort(ci.Addr().String())
+		t.NoError(err)
+
+		t.Equal(1, len(s.Peers))
+		_, found := s.Peers[host]
+		t.True(found)
+
+		h, found := s.Handlers["echo"]
+		t.True(found)
+		t.Equal(uint64(1), h.Total)
+		t.Equal(int64(0), h.Open)
+		t.Equal(uint64(0), h.Errors)
+	})
+
+	t.Run("dial failed", func() {
+		unknown := UnsafeConnInfo(t.NewAddr(), true)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*333)
+		defer cancel()
+
+		_, err := p.Dial(ctx, unknown)
+		t.Error(err)
+
+		s := clientStats.Snapshot()
+		t.Equal(uint64(1), s.Outbound.Connections.Failed)
+		t.Equal(uint64(1), s.Peers[unknown.Addr().String()].Connections.Failed)
+	})
+}
+
+func (t *testInstrument) TestMaxPeers() {
+	s := NewInstrumentStats(2)
+
+	s.ConnectionOpened("a", true)
+	s.ConnectionOpened("b", true)
+	s.ConnectionOpened("c", true)
+	s.ConnectionOpened("a", true)
+
+	snapshot := s.Snapshot()
+
+	t.Equal(3, len(snapshot.Peers))
+	t.Equal(uint64(2), snapshot.Peers["a"].Connections.Total)
+	t.Equal(uint64(1), snapshot.Peers[OthersInstrumentPeer].Connections.Total)
+	t.Equal(uint64(4), snapshot.Inbound.Connections.Total)
+}
+
+func (t *testInstrument) TestInboundPeerWithoutPort() {
+	s := NewInstrumentStats(2)
+
+	for i := range make([]int, 3) {
+		s.ConnectionOpened(fmt.Sprintf("1.2.3.4:%d", 4000+i), true)
+	}
+
+	s.ConnectionOpened("5.6.7.8:4000", false)
+
+	snapshot := s.Snapshot()
+
+	t.Equal(2, len(snapshot.Peers))
+	t.Equal(uint64(3), snapshot.Peers["1.2.3.4"].Connections.Total)
+	t.Equal(uint64(1), snapshot.Peers["5.6.7.8:4000"].Connections.Total)
+}
+
+func TestInstrument(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	suite.Run(t, new(testInstrument))
+}
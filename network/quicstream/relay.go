@@ -0,0 +1,487 @@
+package quicstream
+
+import (
+	"context"
+	"crypto/rand"
+	"io"
+	"net"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/network"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/pkg/errors"
+	"github.com/quic-go/quic-go"
+	"github.com/rs/zerolog"
+)
+
+var (
+	HandlerNameRelayRegister HandlerName = "relay_register"
+	HandlerNameRelayForward  HandlerName = "relay_forward"
+)
+
+var (
+	ErrRelayNotAllowed     = util.NewIDError("relay not allowed")
+	ErrRelayTargetNotFound = util.NewIDError("relay target not found")
+)
+
+var (
+	relayResponseOK    = []byte{0x01}
+	relayResponseNotOK = []byte{0x00}
+)
+
+const relayNonceSize = 32
+
+// RelayAllowFunc checks the registration of target; proof is made by
+// RelayProofFunc of the node behind NAT with the nonce from Relay.
+type RelayAllowFunc func(target string, nonce, proof []byte) bool
+
+// RelayProofFunc makes the proof of registration with the nonce from Relay.
+type RelayProofFunc func(target string, nonce []byte) ([]byte, error)
+
+// Relay forwards the streams to the nodes, which can not be reached directly,
+// like the nodes behind NAT. The node behind NAT dials to the relay and
+// registers it's publish address with the proof for the nonce from relay. The
+// relay keeps the connection from the node and opens new stream to the node
+// thru that connection, when the stream for the node is requested by the other
+// nodes.
+type Relay struct {
+	*logging.Logging
+	local  net.Addr
+	conns  util.LockedMap[string, quic.EarlyConnection]
+	allowf RelayAllowFunc
+}
+
+// NewRelay creates new Relay; if allowf is nil, all the registrations are
+// refused.
+func NewRelay(local net.Addr, size uint64, allowf RelayAllowFunc) (*Relay, error) {
+	conns, err := util.NewLockedMap[string, quic.EarlyConnection](size, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	nallowf := allowf
+	if nallowf == nil {
+		nallowf = func(string, []byte, []byte) bool { return false }
+	}
+
+	return &Relay{
+		Logging: logging.NewLogging(func(zctx zerolog.Context) zerolog.Context {
+			return zctx.Str("module", "quicstream-relay")
+		}),
+		local:  local,
+		conns:  conns,
+		allowf: nallowf,
+	}, nil
+}
+
+// Exists checks whether the address is registered.
+func (r *Relay) Exists(addr string) bool {
+	return r.conns.Exists(addr)
+}
+
+// Streamer returns Streamer for the registered node. The returned Streamer
+// opens stream thru the registered connection.
+func (r *Relay) Streamer(ci ConnInfo) (Streamer, bool) {
+	if ci.UDPAddr() == nil {
+		return nil, false
+	}
+
+	conn, found := r.conns.Value(ci.Addr().String())
+	if !found || conn.Context().Err() != nil {
+		return nil, false
+	}
+
+	origin := []byte(r.local.String())
+
+	return relayStreamer{
+		Streamer: &Connection{
+			conn: conn,
+			id:   conn.RemoteAddr().String() + "-relayed",
+		},
+		beforef: func(_ context.Context, _ io.Reader, w io.Writer) error {
+			return util.WriteLengthed(w, origin)
+		},
+		closef: func() error { return nil },
+	}, true
+}
+
+// RegisterHandler handles the register request from the node behind NAT.
+func (r *Relay) RegisterHandler(
+	ctx context.Context, _ net.Addr, rd io.Reader, w io.WriteCloser,
+) (context.Context, error) {
+	e := util.StringError("register relay")
+
+	conn, ok := ctx.Value(connectionContextKey).(quic.EarlyConnection)
+	if !ok {
+		return ctx, e.Errorf("quic connection not found")
+	}
+
+	var target string
+
+	switch _, b, err := util.ReadLengthed(rd); {
+	case err != nil && !errors.Is(err, io.EOF):
+		return ctx, e.WithMessage(err, "read publish address")
+	default:
+		target = string(b)
+	}
+
+	if err := network.IsValidAddr(target); err != nil {
+		_, _ = w.Write(relayResponseNotOK)
+
+		return ctx, e.Wrap(err)
+	}
+
+	nonce := make([]byte, relayNonceSize)
+
+	if _, err := rand.Read(nonce); err != nil {
+		return ctx, e.WithMessage(err, "nonce")
+	}
+
+	if err := util.WriteLengthed(w, nonce); err != nil {
+		return ctx, e.WithMessage(err, "write nonce")
+	}
+
+	var proof []byte
+
+	switch _, b, err := util.ReadLengthed(rd); {
+	case err != nil && !errors.Is(err, io.EOF):
+		return ctx, e.WithMessage(err, "read proof")
+	default:
+		proof = b
+	}
+
+	if !r.allowf(target, nonce, proof) {
+		_, _ = w.Write(relayResponseNotOK)
+
+		return ctx, e.Wrap(ErrRelayNotAllowed.Errorf("%q", target))
+	}
+
+	_, _, _ = r.conns.Set(target, func(old quic.EarlyConnection, found bool) (quic.EarlyConnection, error) {
+		if found && old != conn {
+			_ = old.CloseWithError(0, "relay replaced")
+		}
+
+		return conn, nil
+	})
+
+	go func() {
+		<-conn.Context().Done()
+
+		_, _ = r.conns.Remove(target, func(i quic.EarlyConnection, found bool) error {
+			if !found || i != conn {
+				return util.ErrLockedSetIgnore
+			}
+
+			return nil
+		})
+
+		r.Log().Debug().Str("target", target).Msg("relay unregistered")
+	}()
+
+	r.Log().Debug().
+		Str("target", target).
+		Stringer("remote", conn.RemoteAddr()).
+		Msg("relay registered")
+
+	if _, err := util.EnsureWrite(w, relayResponseOK); err != nil {
+		return ctx, e.Wrap(err)
+	}
+
+	return ctx, nil
+}
+
+// ForwardHandler forwards the requested stream to the registered node.
+func (r *Relay) ForwardHandler(
+	ctx context.Context, addr net.Addr, rd io.Reader, w io.WriteCloser,
+) (context.Context, error) {
+	e := util.StringError("forward relay")
+
+	var target string
+
+	switch _, b, err := util.ReadLengthed(rd); {
+	case err != nil && !errors.Is(err, io.EOF):
+		return ctx, e.WithMessage(err, "read target address")
+	default:
+		target = string(b)
+	}
+
+	conn, found := r.conns.Value(target)
+	if !found {
+		_, _ = w.Write(relayResponseNotOK)
+
+		return ctx, e.Wrap(ErrRelayTargetNotFound.Errorf("%q", target))
+	}
+
+	stream, err := conn.OpenStreamSync(ctx)
+	if err != nil {
+		_, _ = w.Write(relayResponseNotOK)
+
+		return ctx, e.WithMessage(err, "open stream to target")
+	}
+
+	defer func() {
+		stream.CancelRead(0)
+		_ = stream.Close()
+	}()
+
+	// NOTE the target node will get the origin address
+	if err := util.WriteLengthed(stream, []byte(addr.String())); err != nil {
+		return ctx, e.WithMessage(err, "write origin address")
+	}
+
+	if _, err := util.EnsureWrite(w, relayResponseOK); err != nil {
+		return ctx, e.Wrap(err)
+	}
+
+	go func() {
+		_, _ = io.Copy(stream, rd)
+		_ = stream.Close()
+	}()
+
+	if _, err := io.Copy(w, stream); err != nil {
+		return ctx, e.WithMessage(err, "copy from target")
+	}
+
+	return ctx, nil
+}
+
+// RelayClient registers the local node to the relay and serves the streams,
+// which are forwarded by the relay, thru the local Handler.
+type RelayClient struct {
+	*logging.Logging
+	*util.ContextDaemon
+	dialf         ConnInfoDialFunc
+	handler       Handler
+	prooff        RelayProofFunc
+	streamTimeout func() time.Duration
+	publish       string
+	relay         ConnInfo
+	interval      time.Duration
+}
+
+func NewRelayClient(
+	relay ConnInfo,
+	publish string,
+	dialf ConnInfoDialFunc,
+	handler Handler,
+	prooff RelayProofFunc,
+	maxStreamTimeout func() time.Duration,
+) *RelayClient {
+	c := &RelayClient{
+		Logging: logging.NewLogging(func(zctx zerolog.Context) zerolog.Context {
+			return zctx.Str("module", "quicstream-relay-client")
+		}),
+		relay:         relay,
+		publish:       publish,
+		dialf:         dialf,
+		handler:       handler,
+		prooff:        prooff,
+		streamTimeout: maxStreamTimeout,
+		interval:      time.Second * 3, //nolint:gomnd //...
+	}
+
+	c.ContextDaemon = util.NewContextDaemon(c.start)
+
+	return c
+}
+
+func (c *RelayClient) Relay() ConnInfo {
+	return c.relay
+}
+
+func (c *RelayClient) start(ctx context.Context) error {
+	for {
+		if err := c.serve(ctx); err != nil && ctx.Err() == nil {
+			c.Log().Error().Err(err).Interface("relay", c.relay).Msg("relay connection closed; retry")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(c.interval):
+		}
+	}
+}
+
+func (c *RelayClient) serve(ctx context.Context) error {
+	var conn *Connection
+
+	switch i, err := c.dialf(ctx, c.relay); {
+	case err != nil:
+		return err
+	default:
+		j, ok := i.(*Connection)
+		if !ok {
+			_ = i.Close()
+
+			return errors.Errorf("expected *Connection, but %T", i)
+		}
+
+		conn = j
+	}
+
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	if err := c.register(ctx, conn); err != nil {
+		return err
+	}
+
+	c.Log().Debug().Interface("relay", c.relay).Msg("registered to relay")
+
+	for {
+		stream, err := conn.conn.AcceptStream(ctx)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		go c.handleStream(ctx, stream)
+	}
+}
+
+func (c *RelayClient) register(ctx context.Context, conn *Connection) error {
+	return conn.Stream(ctx, func(ctx context.Context, r io.Reader, w io.WriteCloser) error {
+		if err := WritePrefix(ctx, w, HandlerNameRelayRegister.Prefix()); err != nil {
+			return err
+		}
+
+		if err := util.WriteLengthed(w, []byte(c.publish)); err != nil {
+			return err
+		}
+
+		var nonce []byte
+
+		switch _, b, err := util.ReadLengthed(r); {
+		case err != nil:
+			return errors.WithMessage(err, "read nonce")
+		case len(b) != relayNonceSize:
+			return errors.Errorf("wrong nonce")
+		default:
+			nonce = b
+		}
+
+		proof, err := c.prooff(c.publish, nonce)
+		if err != nil {
+			return err
+		}
+
+		if err := util.WriteLengthed(w, proof); err != nil {
+			return err
+		}
+
+		return readRelayResponse(ctx, r, ErrRelayNotAllowed)
+	})
+}
+
+func (c *RelayClient) handleStream(ctx context.Context, stream quic.Stream) {
+	sctx, cancel := context.WithTimeout(ctx, c.streamTimeout())
+	defer cancel()
+
+	var errcode quic.StreamErrorCode
+
+	var origin net.Addr = c.relay.UDPAddr()
+
+	switch _, b, err := util.ReadLengthed(stream); {
+	case err != nil && !errors.Is(err, io.EOF):
+		c.Log().Trace().Err(err).Msg("failed to read origin address")
+
+		stream.CancelRead(errcode)
+		_ = stream.Close()
+
+		return
+	default:
+		if addr, err := net.ResolveUDPAddr("udp", string(b)); err == nil {
+			origin = addr
+		}
+	}
+
+	if err := util.AwareContext(sctx, func(context.Context) error {
+		_, err := c.handler(sctx, origin, stream, stream)
+
+		return err
+	}); err != nil {
+		if errors.Is(err, context.Canceled) {
+			errcode = 0x401
+		}
+
+		stream.CancelWrite(errcode)
+
+		c.Log().Trace().Err(err).Stringer("origin", origin).Msg("failed to handle relayed stream")
+	}
+
+	stream.CancelRead(errcode)
+	_ = stream.Close()
+}
+
+type relayStreamer struct {
+	Streamer
+	beforef func(context.Context, io.Reader, io.Writer) error
+	closef  func() error
+}
+
+func newRelayForwardStreamer(streamer Streamer, target ConnInfo) relayStreamer {
+	b := []byte(target.Addr().String())
+
+	return relayStreamer{
+		Streamer: streamer,
+		beforef: func(ctx context.Context, r io.Reader, w io.Writer) error {
+			if err := WritePrefix(ctx, w, HandlerNameRelayForward.Prefix()); err != nil {
+				return err
+			}
+
+			if err := util.WriteLengthed(w, b); err != nil {
+				return errors.WithStack(err)
+			}
+
+			return readRelayResponse(ctx, r, ErrRelayTargetNotFound)
+		},
+	}
+}
+
+func (s relayStreamer) Stream(ctx context.Context, f StreamFunc) error {
+	return s.Streamer.Stream(ctx, func(ctx context.Context, r io.Reader, w io.WriteCloser) error {
+		if err := s.beforef(ctx, r, w); err != nil {
+			return err
+		}
+
+		return f(ctx, r, w)
+	})
+}
+
+func (s relayStreamer) OpenStream(ctx context.Context) (io.Reader, io.WriteCloser, func() error, error) {
+	r, w, closef, err := s.Streamer.OpenStream(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err := s.beforef(ctx, r, w); err != nil {
+		_ = closef()
+
+		return nil, nil, nil, err
+	}
+
+	return r, w, closef, nil
+}
+
+func (s relayStreamer) Close() error {
+	if s.closef != nil {
+		return s.closef()
+	}
+
+	return s.Streamer.Close()
+}
+
+func readRelayResponse(ctx context.Context, r io.Reader, notok *util.IDError) error {
+	p := make([]byte, 1)
+
+	switch _, err := util.EnsureRead(ctx, r, p); {
+	case err != nil && !errors.Is(err, io.EOF):
+		return errors.WithMessage(err, "read relay response")
+	case p[0] != relayResponseOK[0]:
+		return notok.Errorf("relay refused")
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,274 @@
+package quicstream
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/pkg/errors"
+	"github.com/quic-go/quic-go"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/goleak"
+)
+
+type testRelay struct {
+	BaseTest
+}
+
+func (t *testRelay) dialf() ConnInfoDialFunc {
+	return NewConnInfoDialFunc(
+		func() *quic.Config { return nil },
+		t.TLSConfig,
+	)
+}
+
+func (t *testRelay) proof(target string, nonce []byte) ([]byte, error) {
+	return util.ConcatBytesSlice(nonce, []byte(target)), nil
+}
+
+func (t *testRelay) allow(target string, nonce, proof []byte) bool {
+	return bytes.Equal(proof, util.ConcatBytesSlice(nonce, []byte(target)))
+}
+
+func (t *testRelay) prepareRelay(allowf RelayAllowFunc) (*Relay, ConnInfo, func()) {
+	relay, err := NewRelay(t.Bind, 3, allowf)
+	t.NoError(err)
+	_ = relay.SetLogging(logging.TestNilLogging)
+
+	handlers := NewPrefixHandler(nil)
+	handlers.Add(HandlerNameRelayRegister, relay.RegisterHandler)
+	handlers.Add(HandlerNameRelayForward, relay.ForwardHandler)
+
+	srv := t.NewDefaultServer(nil, handlers.Handler)
+	t.NoError(srv.EnsureStart(context.Background()))
+
+	return relay, UnsafeConnInfo(srv.Bind, true), func() {
+		_ = srv.StopWait()
+	}
+}
+
+func (t *testRelay) prepareTarget(relayci ConnInfo, prooff RelayProofFunc) (
+	*RelayClient, ConnInfo, chan net.Addr, func(),
+) {
+	publish := UnsafeConnInfo(t.NewAddr(), true)
+
+	originch := make(chan net.Addr, 1)
+
+	handlers := NewPrefixHandler(nil)
+	handlers.Add("echo", func(ctx context.Context, addr net.Addr, r io.Reader, w io.WriteCloser) (context.Context, error) {
+		originch <- addr
+
+		return t.EchoHandler()(ctx, addr, r, w)
+	})
+
+	client := NewRelayClient(
+		relayci,
+		publish.Addr().String(),
+		t.dialf(),
+		handlers.Handler,
+		prooff,
+		func() time.Duration { return time.Second * 3 },
+	)
+	client.interval = time.Millisecond * 33
+	_ = client.SetLogging(logging.TestNilLogging)
+
+	t.NoError(client.Start(context.Background()))
+
+	return client, publish, originch, func() {
+		_ = client.Stop()
+	}
+}
+
+func (t *testRelay) waitRegistered(relay *Relay, ci ConnInfo) {
+	ticker := time.NewTicker(time.Millisecond * 33)
+	defer ticker.Stop()
+
+	timeout := time.After(time.Second * 3)
+
+	for {
+		select {
+		case <-timeout:
+			t.Fail("failed to wait registered")
+
+			return
+		case <-ticker.C:
+			if relay.Exists(ci.Addr().String()) {
+				return
+			}
+		}
+	}
+}
+
+func (t *testRelay) echo(streamer Streamer, body []byte) ([]byte, error) {
+	var rb []byte
+
+	err := streamer.Stream(context.Background(), func(ctx context.Context, r io.Reader, w io.WriteCloser) error {
+		if err := WritePrefix(ctx, w, HandlerName("echo").Prefix()); err != nil {
+			return err
+		}
+
+		if _, err := w.Write(body); err != nil {
+			return errors.WithStack(err)
+		}
+
+		_ = w.Close()
+
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		rb = b
+
+		return nil
+	})
+
+	return rb, err
+}
+
+func (t *testRelay) TestForward() {
+	relay, relayci, deferredrelay := t.prepareRelay(t.allow)
+	defer deferredrelay()
+
+	_, targetci, originch, deferredtarget := t.prepareTarget(relayci, t.proof)
+	defer deferredtarget()
+
+	t.waitRegistered(relay, targetci)
+
+	p, err := NewConnectionPool(3, t.dialf())
+	t.NoError(err)
+	defer p.Stop()
+	defer p.CloseAll()
+
+	t.True(p.SetRelay(targetci, relayci))
+	t.False(p.SetRelay(targetci, relayci))
+
+	rci, found := p.Relay(targetci)
+	t.True(found)
+	t.Equal(relayci.String(), rci.String())
+
+	streamer, err := p.Dial(context.Background(), targetci)
+	t.NoError(err)
+
+	body := util.UUID().Bytes()
+
+	rb, err := t.echo(streamer, body)
+	t.NoError(err)
+	t.Equal(body, rb)
+
+	select {
+	case <-time.After(time.Second * 2):
+		t.Fail("failed to wait origin")
+	case origin := <-originch:
+		t.NotNil(origin)
+	}
+
+	t.Run("remove relay", func() {
+		t.True(p.RemoveRelay(targetci))
+
+		_, found := p.Relay(targetci)
+		t.False(found)
+	})
+}
+
+func (t *testRelay) TestLocalRelay() {
+	relay, relayci, deferredrelay := t.prepareRelay(t.allow)
+	defer deferredrelay()
+
+	_, targetci, originch, deferredtarget := t.prepareTarget(relayci, t.proof)
+	defer deferredtarget()
+
+	t.waitRegistered(relay, targetci)
+
+	p, err := NewConnectionPool(3, t.dialf())
+	t.NoError(err)
+	defer p.Stop()
+	defer p.CloseAll()
+
+	p.SetLocalRelay(relay)
+
+	streamer, err := p.Dial(context.Background(), targetci)
+	t.NoError(err)
+
+	body := util.UUID().Bytes()
+
+	rb, err := t.echo(streamer, body)
+	t.NoError(err)
+	t.Equal(body, rb)
+
+	select {
+	case <-time.After(time.Second * 2):
+		t.Fail("failed to wait origin")
+	case origin := <-originch:
+		t.Equal(relayci.Addr().String(), origin.String())
+	}
+}
+
+func (t *testRelay) TestUnknownTarget() {
+	_, relayci, deferredrelay := t.prepareRelay(t.allow)
+	defer deferredrelay()
+
+	p, err := NewConnectionPool(3, t.dialf())
+	t.NoError(err)
+	defer p.Stop()
+	defer p.CloseAll()
+
+	targetci := UnsafeConnInfo(t.NewAddr(), true)
+	t.True(p.SetRelay(targetci, relayci))
+
+	streamer, err := p.Dial(context.Background(), targetci)
+	t.NoError(err)
+
+	_, err = t.echo(streamer, util.UUID().Bytes())
+	t.Error(err)
+	t.ErrorIs(err, ErrRelayTargetNotFound)
+}
+
+func (t *testRelay) TestNotAllowed() {
+	relay, relayci, deferredrelay := t.prepareRelay(func(string, []byte, []byte) bool { return false })
+	defer deferredrelay()
+
+	_, targetci, _, deferredtarget := t.prepareTarget(relayci, t.proof)
+	defer deferredtarget()
+
+	<-time.After(time.Millisecond * 333)
+
+	t.False(relay.Exists(targetci.Addr().String()))
+}
+
+func (t *testRelay) TestNilAllowFunc() {
+	relay, relayci, deferredrelay := t.prepareRelay(nil)
+	defer deferredrelay()
+
+	_, targetci, _, deferredtarget := t.prepareTarget(relayci, t.proof)
+	defer deferredtarget()
+
+	<-time.After(time.Millisecond * 333)
+
+	t.False(relay.Exists(targetci.Addr().String()))
+}
+
+func (t *testRelay) TestWrongProof() {
+	relay, relayci, deferredrelay := t.prepareRelay(t.allow)
+	defer deferredrelay()
+
+	_, targetci, _, deferredtarget := t.prepareTarget(relayci, func(target string, _ []byte) ([]byte, error) {
+		return t.proof(target, util.UUID().Bytes())
+	})
+	defer deferredtarget()
+
+	<-time.After(time.Millisecond * 333)
+
+	t.False(relay.Exists(targetci.Addr().String()))
+}
+
+func TestRelay(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	suite.Run(t, new(testRelay))
+}
@@ -13,10 +13,13 @@ import (
 	"github.com/rs/zerolog"
 )
 
+var connectionContextKey = util.ContextKey("quic_connection")
+
 type Server struct {
 	*logging.Logging
 	*util.ContextDaemon
 	handler              Handler
+	instrument           Instrument
 	streamTimeoutContext func(context.Context) (context.Context, func())
 }
 
@@ -40,7 +43,8 @@ func NewServer(
 		Logging: logging.NewLogging(func(zctx zerolog.Context) zerolog.Context {
 			return zctx.Str("module", "quicstream-server")
 		}),
-		handler: handler,
+		handler:    handler,
+		instrument: nilInstrument{},
 	}
 
 	srv.streamTimeoutContext = func(ctx context.Context) (context.Context, func()) {
@@ -54,6 +58,11 @@ func NewServer(
 	return srv, nil
 }
 
+// SetInstrument sets Instrument; it should be called before Start.
+func (srv *Server) SetInstrument(i Instrument) {
+	srv.instrument = i
+}
+
 func (srv *Server) start(ctx context.Context, listener *quic.EarlyListener) error {
 	go srv.accept(ctx, listener)
 
@@ -81,11 +90,15 @@ func (srv *Server) accept(ctx context.Context, listener *quic.EarlyListener) {
 		}
 
 		nctx := context.WithValue(ctx, ConnectionIDContextKey, util.UUID().String())
+		nctx = context.WithValue(nctx, connectionContextKey, conn)
 		l := ConnectionLoggerFromContext(nctx, srv.Log())
 		l.Trace().
 			Stringer("remote", conn.RemoteAddr()).
 			Msg("new connection")
 
+		remote := conn.RemoteAddr().String()
+		srv.instrument.ConnectionOpened(remote, true)
+
 		go func() {
 			elapsed := logging.TimeElapsed()
 
@@ -95,6 +108,14 @@ func (srv *Server) accept(ctx context.Context, listener *quic.EarlyListener) {
 			case <-conn.Context().Done():
 				elapsed(l.Trace().Err(conn.Context().Err()), "connection done")
 			}
+
+			select {
+			case <-conn.HandshakeComplete():
+			default:
+				srv.instrument.ConnectionFailed(remote, true, context.Cause(conn.Context()))
+			}
+
+			srv.instrument.ConnectionClosed(remote, true)
 		}()
 
 		go srv.handleConnection(nctx, conn)
@@ -161,11 +182,17 @@ func (srv *Server) handleStream(ctx context.Context, remoteAddr net.Addr, stream
 
 	var errcode quic.StreamErrorCode
 
-	if err := util.AwareContext(sctx, func(context.Context) error {
-		_, err := srv.handler(sctx, remoteAddr, stream, stream)
+	ictx, r, w, ended := instrumentStream(sctx, srv.instrument, remoteAddr.String(), true, stream, stream)
+
+	err := util.AwareContext(ictx, func(context.Context) error {
+		_, err := srv.handler(ictx, remoteAddr, r, w)
 
 		return err
-	}); err != nil {
+	})
+
+	ended(err)
+
+	if err != nil {
 		if errors.Is(err, context.Canceled) {
 			errcode = 0x401
 		}
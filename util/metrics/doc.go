@@ -0,0 +1,5 @@
+/*
+Package metrics provides the simple metrics, which can be exported in
+prometheus text format.
+*/
+package metrics
@@ -0,0 +1,392 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const TextContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10} //nolint:gomnd //...
+
+type Metric interface {
+	Name() string
+	Help() string
+	Type() string
+	writeSamples(io.Writer) error
+}
+
+// Registry collects the metrics and exports them in prometheus text format.
+type Registry struct {
+	ms map[string]Metric
+	sync.RWMutex
+}
+
+func NewRegistry() *Registry {
+	return &Registry{ms: map[string]Metric{}}
+}
+
+func (r *Registry) Register(ms ...Metric) error {
+	r.Lock()
+	defer r.Unlock()
+
+	for i := range ms {
+		if _, found := r.ms[ms[i].Name()]; found {
+			return errors.Errorf("metric already registered, %q", ms[i].Name())
+		}
+	}
+
+	for i := range ms {
+		r.ms[ms[i].Name()] = ms[i]
+	}
+
+	return nil
+}
+
+func (r *Registry) Unregister(name string) bool {
+	r.Lock()
+	defer r.Unlock()
+
+	if _, found := r.ms[name]; !found {
+		return false
+	}
+
+	delete(r.ms, name)
+
+	return true
+}
+
+// WriteText writes metrics in prometheus text exposition format.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.RLock()
+	defer r.RUnlock()
+
+	names := make([]string, 0, len(r.ms))
+
+	for i := range r.ms {
+		names = append(names, i)
+	}
+
+	sort.Strings(names)
+
+	for i := range names {
+		m := r.ms[names[i]]
+
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n",
+			m.Name(), escapeHelp(m.Help()), m.Name(), m.Type()); err != nil {
+			return errors.WithStack(err)
+		}
+
+		if err := m.writeSamples(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", TextContentType)
+
+	if err := r.WriteText(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+type baseMetric struct {
+	name   string
+	help   string
+	labels []string
+}
+
+func (m baseMetric) Name() string {
+	return m.name
+}
+
+func (m baseMetric) Help() string {
+	return m.help
+}
+
+type series struct {
+	labels []string
+	values []float64
+}
+
+// vec keeps the values by label values.
+type vec struct {
+	m    map[string]*series
+	size int
+	sync.RWMutex
+}
+
+func newVec(size int) *vec {
+	return &vec{m: map[string]*series{}, size: size}
+}
+
+func (v *vec) update(labels, expected []string, f func([]float64)) {
+	if len(labels) != len(expected) {
+		return
+	}
+
+	key := strings.Join(labels, "\xff")
+
+	v.Lock()
+	defer v.Unlock()
+
+	s, found := v.m[key]
+	if !found {
+		ls := make([]string, len(labels))
+		copy(ls, labels)
+
+		s = &series{labels: ls, values: make([]float64, v.size)}
+		v.m[key] = s
+	}
+
+	f(s.values)
+}
+
+func (v *vec) traverse(f func(labels []string, values []float64) error) error {
+	v.RLock()
+	defer v.RUnlock()
+
+	keys := make([]string, 0, len(v.m))
+
+	for i := range v.m {
+		keys = append(keys, i)
+	}
+
+	sort.Strings(keys)
+
+	for i := range keys {
+		s := v.m[keys[i]]
+
+		if err := f(s.labels, s.values); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Counter is the monotonically increasing value.
+type Counter struct {
+	v *vec
+	baseMetric
+}
+
+func NewCounter(name, help string, labels ...string) *Counter {
+	return &Counter{baseMetric: baseMetric{name: name, help: help, labels: labels}, v: newVec(1)}
+}
+
+func (*Counter) Type() string {
+	return "counter"
+}
+
+func (c *Counter) Inc(labels ...string) {
+	c.Add(1, labels...)
+}
+
+// Add increases counter; negative v is ignored.
+func (c *Counter) Add(v float64, labels ...string) {
+	if v < 0 {
+		return
+	}
+
+	c.v.update(labels, c.labels, func(values []float64) {
+		values[0] += v
+	})
+}
+
+func (c *Counter) writeSamples(w io.Writer) error {
+	return c.v.traverse(func(labels []string, values []float64) error {
+		return writeSample(w, c.name, c.labels, labels, nil, values[0])
+	})
+}
+
+// Gauge is the value, which can go up and down.
+type Gauge struct {
+	v *vec
+	baseMetric
+}
+
+func NewGauge(name, help string, labels ...string) *Gauge {
+	return &Gauge{baseMetric: baseMetric{name: name, help: help, labels: labels}, v: newVec(1)}
+}
+
+func (*Gauge) Type() string {
+	return "gauge"
+}
+
+func (g *Gauge) Set(v float64, labels ...string) {
+	g.v.update(labels, g.labels, func(values []float64) {
+		values[0] = v
+	})
+}
+
+func (g *Gauge) Add(v float64, labels ...string) {
+	g.v.update(labels, g.labels, func(values []float64) {
+		values[0] += v
+	})
+}
+
+func (g *Gauge) writeSamples(w io.Writer) error {
+	return g.v.traverse(func(labels []string, values []float64) error {
+		return writeSample(w, g.name, g.labels, labels, nil, values[0])
+	})
+}
+
+// GaugeFunc is the Gauge, which value is collected by function when exported.
+// If function returns false, the value is not exported.
+type GaugeFunc struct {
+	f func() (float64, bool)
+	baseMetric
+}
+
+func NewGaugeFunc(name, help string, f func() (float64, bool)) *GaugeFunc {
+	return &GaugeFunc{baseMetric: baseMetric{name: name, help: help}, f: f}
+}
+
+func (*GaugeFunc) Type() string {
+	return "gauge"
+}
+
+func (g *GaugeFunc) writeSamples(w io.Writer) error {
+	switch v, ok := g.f(); {
+	case !ok:
+		return nil
+	default:
+		return writeSample(w, g.name, nil, nil, nil, v)
+	}
+}
+
+// Histogram counts the observed values in buckets.
+type Histogram struct {
+	v       *vec
+	buckets []float64
+	baseMetric
+}
+
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if len(buckets) < 1 {
+		buckets = DefaultBuckets //revive:disable-line:modifies-parameter
+	}
+
+	bs := make([]float64, len(buckets))
+	copy(bs, buckets)
+	sort.Float64s(bs)
+
+	return &Histogram{
+		baseMetric: baseMetric{name: name, help: help, labels: labels},
+		buckets:    bs,
+		v:          newVec(len(bs) + 2), //nolint:gomnd // buckets + sum + count
+	}
+}
+
+func (*Histogram) Type() string {
+	return "histogram"
+}
+
+func (h *Histogram) Observe(v float64, labels ...string) {
+	h.v.update(labels, h.labels, func(values []float64) {
+		for i := range h.buckets {
+			if v <= h.buckets[i] {
+				values[i]++
+			}
+		}
+
+		values[len(h.buckets)] += v
+		values[len(h.buckets)+1]++
+	})
+}
+
+func (h *Histogram) writeSamples(w io.Writer) error {
+	lenbuckets := len(h.buckets)
+
+	return h.v.traverse(func(labels []string, values []float64) error {
+		for i := range h.buckets {
+			if err := writeSample(w, h.name+"_bucket", h.labels, labels,
+				[]string{"le", formatFloat(h.buckets[i])}, values[i]); err != nil {
+				return err
+			}
+		}
+
+		if err := writeSample(w, h.name+"_bucket", h.labels, labels,
+			[]string{"le", "+Inf"}, values[lenbuckets+1]); err != nil {
+			return err
+		}
+
+		if err := writeSample(w, h.name+"_sum", h.labels, labels, nil, values[lenbuckets]); err != nil {
+			return err
+		}
+
+		return writeSample(w, h.name+"_count", h.labels, labels, nil, values[lenbuckets+1])
+	})
+}
+
+func writeSample(w io.Writer, name string, keys, values, extra []string, v float64) error {
+	var sb strings.Builder
+
+	_, _ = sb.WriteString(name)
+
+	if len(keys) > 0 || len(extra) > 0 {
+		_, _ = sb.WriteString("{")
+
+		for i := range keys {
+			if i > 0 {
+				_, _ = sb.WriteString(",")
+			}
+
+			_, _ = sb.WriteString(keys[i] + `="` + escapeLabelValue(values[i]) + `"`)
+		}
+
+		if len(extra) > 0 {
+			if len(keys) > 0 {
+				_, _ = sb.WriteString(",")
+			}
+
+			_, _ = sb.WriteString(extra[0] + `="` + escapeLabelValue(extra[1]) + `"`)
+		}
+
+		_, _ = sb.WriteString("}")
+	}
+
+	_, _ = sb.WriteString(" " + formatFloat(v) + "\n")
+
+	_, err := io.WriteString(w, sb.String())
+
+	return errors.WithStack(err)
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+}
+
+var (
+	helpReplacer  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpReplacer.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelReplacer.Replace(s)
+}
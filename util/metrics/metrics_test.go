@@ -0,0 +1,124 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type testRegistry struct {
+	suite.Suite
+}
+
+func (t *testRegistry) text(r *Registry) string {
+	buf := bytes.NewBuffer(nil)
+	t.NoError(r.WriteText(buf))
+
+	return buf.String()
+}
+
+func (t *testRegistry) TestCounter() {
+	r := NewRegistry()
+
+	c := NewCounter("a_total", "help of a", "stage", "result")
+	t.NoError(r.Register(c))
+
+	c.Inc("INIT", "ok")
+	c.Inc("INIT", "ok")
+	c.Add(3, "ACCEPT", "failed")
+	c.Add(-1, "ACCEPT", "failed") // NOTE ignored
+	c.Inc("INIT")                 // NOTE wrong labels; ignored
+
+	t.Equal(`# HELP a_total help of a
+# TYPE a_total counter
+a_total{stage="ACCEPT",result="failed"} 3
+a_total{stage="INIT",result="ok"} 2
+`, t.text(r))
+}
+
+func (t *testRegistry) TestGauge() {
+	r := NewRegistry()
+
+	g := NewGauge("b", "help\nof b")
+	t.NoError(r.Register(g))
+
+	g.Set(3)
+	g.Add(-1.5)
+
+	var ok bool
+	gf := NewGaugeFunc("c", "help of c", func() (float64, bool) { return 33, ok })
+	t.NoError(r.Register(gf))
+
+	t.Equal(`# HELP b help\nof b
+# TYPE b gauge
+b 1.5
+# HELP c help of c
+# TYPE c gauge
+`, t.text(r))
+
+	ok = true
+
+	t.Equal(`# HELP b help\nof b
+# TYPE b gauge
+b 1.5
+# HELP c help of c
+# TYPE c gauge
+c 33
+`, t.text(r))
+}
+
+func (t *testRegistry) TestHistogram() {
+	r := NewRegistry()
+
+	h := NewHistogram("d_seconds", "help of d", []float64{1, 0.1}, "handler")
+	t.NoError(r.Register(h))
+
+	h.Observe(0.05, `a"b`)
+	h.Observe(0.5, `a"b`)
+	h.Observe(3, `a"b`)
+
+	t.Equal(`# HELP d_seconds help of d
+# TYPE d_seconds histogram
+d_seconds_bucket{handler="a\"b",le="0.1"} 1
+d_seconds_bucket{handler="a\"b",le="1"} 2
+d_seconds_bucket{handler="a\"b",le="+Inf"} 3
+d_seconds_sum{handler="a\"b"} 3.55
+d_seconds_count{handler="a\"b"} 3
+`, t.text(r))
+}
+
+func (t *testRegistry) TestRegister() {
+	r := NewRegistry()
+
+	t.NoError(r.Register(NewGauge("a", "")))
+
+	err := r.Register(NewCounter("a", ""))
+	t.Error(err)
+	t.ErrorContains(err, "already registered")
+
+	t.True(r.Unregister("a"))
+	t.False(r.Unregister("a"))
+	t.NoError(r.Register(NewCounter("a", "")))
+}
+
+func (t *testRegistry) TestServeHTTP() {
+	r := NewRegistry()
+
+	g := NewGauge("a", "")
+	t.NoError(r.Register(g))
+	g.Set(1)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	t.Equal(http.StatusOK, w.Code)
+	t.Equal(TextContentType, w.Header().Get("Content-Type"))
+	t.Equal("# HELP a \n# TYPE a gauge\na 1\n", w.Body.String())
+}
+
+func TestRegistry(t *testing.T) {
+	suite.Run(t, new(testRegistry))
+}
@@ -0,0 +1,5 @@
+/*
+Package tracing provides the simple span based tracing. Spans are threaded
+through context.Context and exported to the file or the collector.
+*/
+package tracing
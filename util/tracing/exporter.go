@@ -0,0 +1,152 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/pkg/errors"
+)
+
+type Exporter interface {
+	Export(context.Context, []SpanData) error
+	Close() error
+}
+
+// NewExporterFromString returns the Exporter from the exporter string;
+//   - "file:///a/b/trace.json" or "/a/b/trace.json": FileExporter
+//   - "http://localhost:4318/spans": HTTPExporter
+func NewExporterFromString(s string) (Exporter, error) {
+	e := util.StringError("exporter")
+
+	switch {
+	case len(strings.TrimSpace(s)) < 1:
+		return nil, e.Errorf("empty")
+	case !strings.Contains(s, "://"):
+		return NewFileExporter(s)
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	switch u.Scheme {
+	case "file":
+		return NewFileExporter(u.Path)
+	case "http", "https":
+		if len(u.Host) < 1 {
+			return nil, e.Errorf("empty host")
+		}
+
+		return NewHTTPExporter(u, nil), nil
+	default:
+		return nil, e.Errorf("unsupported scheme, %q", u.Scheme)
+	}
+}
+
+// FileExporter appends the spans to file in json lines.
+type FileExporter struct {
+	f *os.File
+	sync.Mutex
+}
+
+func NewFileExporter(p string) (*FileExporter, error) {
+	e := util.StringError("file exporter")
+
+	if len(p) < 1 {
+		return nil, e.Errorf("empty path")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	f, err := os.OpenFile(filepath.Clean(p), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	return &FileExporter{f: f}, nil
+}
+
+func (ex *FileExporter) Export(_ context.Context, spans []SpanData) error {
+	buf := bytes.NewBuffer(nil)
+
+	for i := range spans {
+		b, err := util.MarshalJSON(spans[i])
+		if err != nil {
+			return err
+		}
+
+		_, _ = buf.Write(b)
+		_ = buf.WriteByte('\n')
+	}
+
+	ex.Lock()
+	defer ex.Unlock()
+
+	_, err := ex.f.Write(buf.Bytes())
+
+	return errors.WithStack(err)
+}
+
+func (ex *FileExporter) Close() error {
+	ex.Lock()
+	defer ex.Unlock()
+
+	return errors.WithStack(ex.f.Close())
+}
+
+// HTTPExporter posts the spans to the collector in json list.
+type HTTPExporter struct {
+	client *http.Client
+	u      *url.URL
+}
+
+func NewHTTPExporter(u *url.URL, client *http.Client) *HTTPExporter {
+	if client == nil {
+		client = http.DefaultClient //revive:disable-line:modifies-parameter
+	}
+
+	return &HTTPExporter{client: client, u: u}
+}
+
+func (ex *HTTPExporter) Export(ctx context.Context, spans []SpanData) error {
+	b, err := util.MarshalJSON(spans)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ex.u.String(), bytes.NewReader(b))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := ex.client.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return errors.Errorf("unexpected status code, %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+func (*HTTPExporter) Close() error {
+	return nil
+}
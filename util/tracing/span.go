@@ -0,0 +1,248 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/localtime"
+)
+
+type (
+	TraceID [16]byte
+	SpanID  [8]byte
+)
+
+var (
+	spanContextKey       = util.ContextKey("tracing-span")
+	remoteSpanContextKey = util.ContextKey("tracing-remote-span")
+)
+
+func NewTraceID() TraceID {
+	var id TraceID
+	_, _ = rand.Read(id[:])
+
+	return id
+}
+
+// NewTraceIDFromBytes returns the deterministic TraceID from the given seed;
+// the nodes can share the same TraceID for the same seed, like height and
+// round, without propagation.
+func NewTraceIDFromBytes(b []byte) TraceID {
+	var id TraceID
+
+	h := sha256.Sum256(b)
+	copy(id[:], h[:len(id)])
+
+	return id
+}
+
+func (id TraceID) IsZero() bool {
+	return id == TraceID{}
+}
+
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+func NewSpanID() SpanID {
+	var id SpanID
+	_, _ = rand.Read(id[:])
+
+	return id
+}
+
+func (id SpanID) IsZero() bool {
+	return id == SpanID{}
+}
+
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanContext identifies the span over nodes.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+}
+
+func SpanContextFromBytes(b []byte) (sc SpanContext, _ error) {
+	switch {
+	case len(b) < 1:
+		return sc, nil
+	case len(b) != len(sc.TraceID)+len(sc.SpanID):
+		return sc, util.ErrInvalid.Errorf("wrong span context length, %d", len(b))
+	}
+
+	copy(sc.TraceID[:], b[:len(sc.TraceID)])
+	copy(sc.SpanID[:], b[len(sc.TraceID):])
+
+	return sc, nil
+}
+
+func (sc SpanContext) IsZero() bool {
+	return sc.TraceID.IsZero()
+}
+
+// Bytes returns bytes of SpanContext; empty SpanContext returns nil.
+func (sc SpanContext) Bytes() []byte {
+	if sc.IsZero() {
+		return nil
+	}
+
+	b := make([]byte, len(sc.TraceID)+len(sc.SpanID))
+	copy(b, sc.TraceID[:])
+	copy(b[len(sc.TraceID):], sc.SpanID[:])
+
+	return b
+}
+
+// SpanContextFromContext returns the SpanContext of current span; if not
+// found, returns the remote SpanContext.
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if sp, ok := ctx.Value(spanContextKey).(*Span); ok && sp != nil {
+		return sp.sc
+	}
+
+	if sc, ok := ctx.Value(remoteSpanContextKey).(SpanContext); ok {
+		return sc
+	}
+
+	return SpanContext{}
+}
+
+// ContextWithSpan sets span to the context; the new span in this context will
+// be the child of span.
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	if span == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, spanContextKey, span)
+}
+
+// ContextWithRemoteSpanContext sets the SpanContext from the other node; the
+// new span in this context will be the child of the remote span.
+func ContextWithRemoteSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	if sc.IsZero() {
+		return ctx
+	}
+
+	return context.WithValue(ctx, remoteSpanContextKey, sc)
+}
+
+type StartOption func(*startOptions)
+
+type startOptions struct {
+	attrs   map[string]interface{}
+	traceID TraceID
+}
+
+// WithTraceID sets the TraceID of new span, which has no parent.
+func WithTraceID(id TraceID) StartOption {
+	return func(o *startOptions) {
+		o.traceID = id
+	}
+}
+
+func WithAttribute(key string, value interface{}) StartOption {
+	return func(o *startOptions) {
+		o.attrs[key] = value
+	}
+}
+
+// Start starts new span with the default Tracer. Without the default Tracer,
+// the returned span is nil, but nil span can be safely used.
+func Start(ctx context.Context, name string, options ...StartOption) (context.Context, *Span) {
+	t := DefaultTracer()
+	if t == nil {
+		return ctx, nil
+	}
+
+	return t.StartSpan(ctx, name, options...)
+}
+
+// Span records the elapsed time of one job.
+type Span struct {
+	startedAt time.Time
+	tracer    *Tracer
+	attrs     map[string]interface{}
+	name      string
+	sc        SpanContext
+	parent    SpanID
+	sync.Mutex
+	ended bool
+}
+
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+
+	return s.sc
+}
+
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.attrs[key] = value
+}
+
+// End finishes span and sends it to the Tracer; End can be called multiple
+// times, but only the first one is exported.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if s.ended {
+		return
+	}
+
+	s.ended = true
+
+	d := SpanData{
+		TraceID:    s.sc.TraceID.String(),
+		SpanID:     s.sc.SpanID.String(),
+		Name:       s.name,
+		Node:       s.tracer.node,
+		StartedAt:  s.startedAt,
+		Elapsed:    util.ReadableDuration(localtime.Now().Sub(s.startedAt)),
+		Attributes: s.attrs,
+	}
+
+	if !s.parent.IsZero() {
+		d.ParentID = s.parent.String()
+	}
+
+	if err != nil {
+		d.Error = err.Error()
+	}
+
+	s.tracer.export(d)
+}
+
+// SpanData is the exported span.
+type SpanData struct {
+	StartedAt  time.Time              `json:"started_at"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	TraceID    string                 `json:"trace_id"`
+	SpanID     string                 `json:"span_id"`
+	ParentID   string                 `json:"parent_id,omitempty"`
+	Name       string                 `json:"name"`
+	Node       string                 `json:"node,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+	Elapsed    util.ReadableDuration  `json:"elapsed"`
+}
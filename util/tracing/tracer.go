@@ -0,0 +1,158 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/localtime"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/rs/zerolog"
+)
+
+var (
+	defaultTracer             = util.EmptyLocked[*Tracer]()
+	DefaultTracerBatchSize    = 1 << 8
+	DefaultTracerInterval     = time.Second
+	defaultTracerQueueSize    = 1 << 12
+	defaultTracerExportTimout = time.Second * 3
+)
+
+// DefaultTracer returns the default Tracer; if not set, returns nil.
+func DefaultTracer() *Tracer {
+	t, _ := defaultTracer.Value()
+
+	return t
+}
+
+// SetDefaultTracer sets the default Tracer, which is used by Start; nil
+// disables tracing.
+func SetDefaultTracer(t *Tracer) {
+	if t == nil {
+		defaultTracer.EmptyValue()
+
+		return
+	}
+
+	_ = defaultTracer.SetValue(t)
+}
+
+// Tracer collects the ended spans and exports them in batch.
+type Tracer struct {
+	*logging.Logging
+	*util.ContextDaemon
+	exporter  Exporter
+	spans     chan SpanData
+	node      string
+	batchSize int
+	interval  time.Duration
+}
+
+func NewTracer(node string, exporter Exporter) *Tracer {
+	t := &Tracer{
+		Logging: logging.NewLogging(func(zctx zerolog.Context) zerolog.Context {
+			return zctx.Str("module", "tracer")
+		}),
+		exporter:  exporter,
+		spans:     make(chan SpanData, defaultTracerQueueSize),
+		node:      node,
+		batchSize: DefaultTracerBatchSize,
+		interval:  DefaultTracerInterval,
+	}
+
+	t.ContextDaemon = util.NewContextDaemon(t.start)
+
+	return t
+}
+
+func (t *Tracer) StartSpan(ctx context.Context, name string, options ...StartOption) (context.Context, *Span) {
+	o := startOptions{attrs: map[string]interface{}{}}
+
+	for i := range options {
+		options[i](&o)
+	}
+
+	s := &Span{
+		startedAt: localtime.Now(),
+		tracer:    t,
+		attrs:     o.attrs,
+		name:      name,
+	}
+
+	switch parent := SpanContextFromContext(ctx); {
+	case !parent.IsZero():
+		s.sc.TraceID = parent.TraceID
+		s.parent = parent.SpanID
+	case !o.traceID.IsZero():
+		s.sc.TraceID = o.traceID
+	default:
+		s.sc.TraceID = NewTraceID()
+	}
+
+	s.sc.SpanID = NewSpanID()
+
+	return context.WithValue(ctx, spanContextKey, s), s
+}
+
+func (t *Tracer) export(d SpanData) {
+	select {
+	case t.spans <- d:
+	default:
+		t.Log().Trace().Str("span", d.Name).Msg("queue full; span dropped")
+	}
+}
+
+func (t *Tracer) start(ctx context.Context) error {
+	defer func() {
+		if err := t.exporter.Close(); err != nil {
+			t.Log().Error().Err(err).Msg("failed to close exporter")
+		}
+	}()
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	batch := make([]SpanData, 0, t.batchSize)
+
+	flush := func() {
+		if len(batch) < 1 {
+			return
+		}
+
+		ectx, cancel := context.WithTimeout(context.Background(), defaultTracerExportTimout)
+		defer cancel()
+
+		if err := t.exporter.Export(ectx, batch); err != nil {
+			t.Log().Error().Err(err).Int("spans", len(batch)).Msg("failed to export spans")
+		}
+
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+		end:
+			for {
+				select {
+				case d := <-t.spans:
+					batch = append(batch, d)
+				default:
+					break end
+				}
+			}
+
+			flush()
+
+			return ctx.Err()
+		case <-ticker.C:
+			flush()
+		case d := <-t.spans:
+			batch = append(batch, d)
+
+			if len(batch) >= t.batchSize {
+				flush()
+			}
+		}
+	}
+}
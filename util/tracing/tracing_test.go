@@ -0,0 +1,209 @@
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/goleak"
+)
+
+type dummyExporter struct {
+	spans []SpanData
+	sync.Mutex
+	closed bool
+}
+
+func (ex *dummyExporter) Export(_ context.Context, spans []SpanData) error {
+	ex.Lock()
+	defer ex.Unlock()
+
+	ex.spans = append(ex.spans, spans...)
+
+	return nil
+}
+
+func (ex *dummyExporter) Close() error {
+	ex.Lock()
+	defer ex.Unlock()
+
+	ex.closed = true
+
+	return nil
+}
+
+func (ex *dummyExporter) all() []SpanData {
+	ex.Lock()
+	defer ex.Unlock()
+
+	return ex.spans
+}
+
+type testTracing struct {
+	suite.Suite
+}
+
+func (t *testTracing) TestSpanContextBytes() {
+	sc := SpanContext{TraceID: NewTraceID(), SpanID: NewSpanID()}
+
+	usc, err := SpanContextFromBytes(sc.Bytes())
+	t.NoError(err)
+	t.Equal(sc, usc)
+
+	t.Run("empty", func() {
+		t.Nil(SpanContext{}.Bytes())
+
+		usc, err := SpanContextFromBytes(nil)
+		t.NoError(err)
+		t.True(usc.IsZero())
+	})
+
+	t.Run("wrong length", func() {
+		_, err := SpanContextFromBytes([]byte("showme"))
+		t.Error(err)
+		t.ErrorContains(err, "wrong span context length")
+	})
+}
+
+func (t *testTracing) TestTraceIDFromBytes() {
+	t.Equal(NewTraceIDFromBytes([]byte("33/0")), NewTraceIDFromBytes([]byte("33/0")))
+	t.NotEqual(NewTraceIDFromBytes([]byte("33/0")), NewTraceIDFromBytes([]byte("33/1")))
+}
+
+func (t *testTracing) TestNilSpan() {
+	ctx, span := Start(context.Background(), "showme")
+	t.Nil(span)
+	t.True(SpanContextFromContext(ctx).IsZero())
+
+	span.SetAttribute("a", 1)
+	span.End(nil)
+}
+
+func (t *testTracing) TestSpans() {
+	ex := &dummyExporter{}
+
+	tracer := NewTracer("no0sas", ex)
+	tracer.interval = time.Millisecond * 33
+	t.NoError(tracer.Start(context.Background()))
+
+	traceID := NewTraceIDFromBytes([]byte("33/0"))
+
+	ctx, parent := tracer.StartSpan(context.Background(), "parent", WithTraceID(traceID), WithAttribute("a", 1))
+	_, child := tracer.StartSpan(ctx, "child")
+
+	t.Equal(traceID, parent.SpanContext().TraceID)
+	t.Equal(traceID, child.SpanContext().TraceID)
+
+	child.End(errors.Errorf("hehehe"))
+	child.End(nil) // NOTE ignored
+	parent.End(nil)
+
+	t.Run("remote", func() {
+		rctx := ContextWithRemoteSpanContext(context.Background(), parent.SpanContext())
+
+		_, remote := tracer.StartSpan(rctx, "remote", WithTraceID(NewTraceID()))
+		t.Equal(traceID, remote.SpanContext().TraceID)
+
+		remote.End(nil)
+	})
+
+	t.Eventually(func() bool {
+		return len(ex.all()) == 3
+	}, time.Second*2, time.Millisecond*33)
+
+	t.NoError(tracer.Stop())
+
+	spans := ex.all()
+
+	t.Equal("child", spans[0].Name)
+	t.Equal(parent.SpanContext().SpanID.String(), spans[0].ParentID)
+	t.Equal("hehehe", spans[0].Error)
+	t.Equal("no0sas", spans[0].Node)
+
+	t.Equal("parent", spans[1].Name)
+	t.Empty(spans[1].ParentID)
+	t.Equal(1, spans[1].Attributes["a"])
+
+	t.Equal("remote", spans[2].Name)
+	t.Equal(parent.SpanContext().SpanID.String(), spans[2].ParentID)
+
+	ex.Lock()
+	t.True(ex.closed)
+	ex.Unlock()
+}
+
+func (t *testTracing) TestFileExporter() {
+	p := filepath.Join(t.T().TempDir(), "a", "trace.json")
+
+	ex, err := NewExporterFromString("file://" + p)
+	t.NoError(err)
+
+	t.NoError(ex.Export(context.Background(), []SpanData{{Name: "a"}, {Name: "b"}}))
+	t.NoError(ex.Close())
+
+	f, err := os.Open(p)
+	t.NoError(err)
+	defer f.Close()
+
+	var names []string
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var d SpanData
+		t.NoError(json.Unmarshal(sc.Bytes(), &d))
+
+		names = append(names, d.Name)
+	}
+
+	t.Equal([]string{"a", "b"}, names)
+}
+
+func (t *testTracing) TestHTTPExporter() {
+	received := make(chan []SpanData, 1)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var spans []SpanData
+		if err := json.NewDecoder(r.Body).Decode(&spans); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		received <- spans
+	}))
+	defer ts.Close()
+
+	ex, err := NewExporterFromString(ts.URL + "/spans")
+	t.NoError(err)
+
+	t.NoError(ex.Export(context.Background(), []SpanData{{Name: "a"}}))
+
+	select {
+	case <-time.After(time.Second):
+		t.Fail("failed to wait spans")
+	case spans := <-received:
+		t.Equal(1, len(spans))
+		t.Equal("a", spans[0].Name)
+	}
+
+	t.Run("unknown scheme", func() {
+		_, err := NewExporterFromString("ftp://localhost/a")
+		t.Error(err)
+		t.ErrorContains(err, "unsupported scheme")
+	})
+}
+
+func TestTracing(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	suite.Run(t, new(testTracing))
+}